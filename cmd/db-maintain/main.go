@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thesavant42/gitsome-ng/internal/db"
+)
+
+func main() {
+	dbPath := flag.String("db", "generic.db", "Path to SQLite project database")
+	flag.Parse()
+
+	// db.New creates missing files, so check first rather than "optimizing" a fresh empty database
+	before, err := os.Stat(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+
+	database, err := db.New(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Optimizing %s (close any running gitsome-ng sessions using it first)...\n", *dbPath)
+	if err := database.Optimize(); err != nil {
+		database.Close()
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := database.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close database: %v\n", err)
+		os.Exit(1)
+	}
+
+	after, err := os.Stat(*dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read database size: %v\n", err)
+		os.Exit(1)
+	}
+
+	saved := before.Size() - after.Size()
+	fmt.Printf("Size before: %s\n", formatBytes(before.Size()))
+	fmt.Printf("Size after:  %s\n", formatBytes(after.Size()))
+	if saved > 0 {
+		fmt.Printf("Reclaimed:   %s (%.1f%%)\n", formatBytes(saved), float64(saved)/float64(before.Size())*100)
+	} else {
+		fmt.Println("Nothing to reclaim")
+	}
+}
+
+// formatBytes renders a byte count in human-readable units
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -10,9 +11,19 @@ import (
 )
 
 func main() {
+	statusFlag := flag.String("status", "", "Status code filter, e.g. 200,3xx or !404,!301")
+	mimeFlag := flag.String("mime", "", "MIME type filter, e.g. text/html,application/json")
+	flag.Parse()
+
 	domain := "raspberrypi.com"
-	if len(os.Args) > 1 {
-		domain = os.Args[1]
+	if flag.NArg() > 0 {
+		domain = flag.Arg(0)
+	}
+
+	filter, err := api.ParseCDXFilter(*statusFlag, *mimeFlag)
+	if err != nil {
+		fmt.Printf("ERROR: %v\n", err)
+		os.Exit(1)
 	}
 
 	logger := log.NewWithOptions(os.Stderr, log.Options{
@@ -21,13 +32,13 @@ func main() {
 	})
 
 	fmt.Printf("Testing CDX fetch for domain: %s\n", domain)
-	fmt.Printf("Query: %s\n", api.BuildCDXQuery(domain, ""))
+	fmt.Printf("Query: %s\n", api.BuildCDXQuery(domain, "", filter))
 
 	client := api.NewWaybackClient(logger)
 
 	// Single page fetch
 	fmt.Println("\n--- Fetching single page ---")
-	resp, err := client.FetchCDX(domain, "")
+	resp, err := client.FetchCDX(domain, "", filter)
 	if err != nil {
 		fmt.Printf("ERROR: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/thesavant42/gitsome-ng/internal/api"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// allSources lists every enumeration source, in the order they run
+var allSources = []string{"crtsh", "virustotal", "securitytrails", "censys"}
+
+// sourceAliases maps accepted -sources names to the stored source names
+var sourceAliases = map[string]string{
+	"crtsh":          "crtsh",
+	"crt.sh":         "crtsh",
+	"vt":             "virustotal",
+	"virustotal":     "virustotal",
+	"st":             "securitytrails",
+	"securitytrails": "securitytrails",
+	"censys":         "censys",
+}
+
+// sourceResult is the outcome of enumerating one domain with one source
+type sourceResult struct {
+	Source string `json:"source"`
+	Found  int    `json:"found"`
+	New    int    `json:"new"`
+	Error  string `json:"error,omitempty"`
+}
+
+// domainResult collects the per-source results and the stored total for a domain
+type domainResult struct {
+	Domain   string         `json:"domain"`
+	Total    int            `json:"total"`
+	Summary  string         `json:"summary"`
+	Sources  []sourceResult `json:"sources"`
+	Diff     *snapshotDiff  `json:"diff,omitempty"`     // Changes since the -diff snapshot
+	Snapshot string         `json:"snapshot,omitempty"` // Label the -snapshot was saved under
+}
+
+// snapshotDiff lists the subdomains added and removed since a saved snapshot
+type snapshotDiff struct {
+	Snapshot string   `json:"snapshot"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+}
+
+func main() {
+	dbPath := flag.String("db", "generic.db", "Path to SQLite database")
+	domainFlag := flag.String("domain", "", "Domain(s) to enumerate, comma-separated (required)")
+	sourcesFlag := flag.String("sources", "crtsh", "Sources to query, comma-separated (crtsh|vt|securitytrails|censys|all)")
+	format := flag.String("format", "text", "Output format (text|json|csv)")
+	restart := flag.Bool("restart", false, "Restart VirusTotal enumeration instead of resuming from the stored cursor")
+	concurrency := flag.Int("concurrency", 4, "Number of parallel crt.sh queries across domains")
+	vtDelay := flag.Duration("vt-delay", 0, "Pause between VirusTotal pages when VT sends no rate limit headers (default 500ms)")
+	diffLabel := flag.String("diff", "", "After enumerating, compare each domain's subdomains with this snapshot (latest = the newest one)")
+	snapshotLabel := flag.String("snapshot", "", "After enumerating (and diffing), save each domain's subdomains as a snapshot with this label (auto = timestamp)")
+	proxy := flag.String("proxy", "", "Proxy URL for all requests (http, https, socks5 or socks5h; default: proxy environment variables)")
+	logOpts := logging.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	logFile, err := logging.Setup(*logOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer logFile.Close()
+
+	switch *format {
+	case "text", "json", "csv":
+	default:
+		log.Fatalf("Invalid -format %q (want text|json|csv)", *format)
+	}
+
+	domains := splitList(*domainFlag)
+	if len(domains) == 0 {
+		log.Fatal("-domain is required")
+	}
+	sources, explicit, err := parseSources(*sourcesFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := api.SetProxy(*proxy); err != nil {
+		log.Fatalf("Invalid -proxy: %v", err)
+	}
+
+	// API keys come from the project's settings (decrypted with GITSOME_SECRET when set)
+	database, err := db.New(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	client := api.NewSubdomainClient("", nil)
+	client.SetVirusTotalPageDelay(*vtDelay)
+	if key, err := database.GetVirusTotalAPIKey(); err == nil {
+		client.SetVirusTotalAPIKey(key)
+	}
+	if key, err := database.GetSecurityTrailsAPIKey(); err == nil {
+		client.SetSecurityTrailsAPIKey(key)
+	}
+	if id, secret, err := database.GetCensysCredentials(); err == nil {
+		client.SetCensysCredentials(id, secret)
+	}
+
+	// Sources named explicitly must be usable; "all" skips the ones without credentials
+	sources, err = usableSources(client, sources, explicit)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Ctrl-C stops VirusTotal and crt.sh cleanly, keeping what was fetched so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	for _, domain := range domains {
+		if err := database.InsertTargetDomain(domain); err != nil {
+			log.Fatalf("Failed to add %s: %v", domain, err)
+		}
+	}
+
+	// crt.sh is slow, so every domain is queried up front in parallel
+	var crtsh *crtshBatch
+	for _, source := range sources {
+		if source == "crtsh" {
+			crtsh = fetchCrtshBatch(ctx, client, domains, *concurrency)
+		}
+	}
+
+	failed := false
+	results := make([]domainResult, 0, len(domains))
+	for _, domain := range domains {
+		result := domainResult{Domain: domain}
+		for _, source := range sources {
+			res := enumerate(ctx, database, client, crtsh, domain, source, *restart)
+			if res.Error != "" {
+				failed = true
+			}
+			if *format == "text" {
+				printSourceResult(domain, res)
+			}
+			result.Sources = append(result.Sources, res)
+		}
+
+		stats, err := database.GetSubdomainStats(domain)
+		if err != nil {
+			log.Fatalf("Failed to count subdomains for %s: %v", domain, err)
+		}
+		result.Total = stats.Total
+		result.Summary = stats.Summary()
+		if *format == "text" {
+			fmt.Printf("%s now has %s subdomains\n", domain, result.Summary)
+		}
+
+		if *diffLabel != "" {
+			diff, err := diffSnapshot(database, domain, *diffLabel)
+			if err != nil {
+				log.Fatalf("Failed to compare %s with snapshot %s: %v", domain, *diffLabel, err)
+			}
+			result.Diff = diff
+			if *format == "text" {
+				printDiff(domain, diff)
+			}
+		}
+		if *snapshotLabel != "" {
+			label := *snapshotLabel
+			if label == "auto" {
+				label = ""
+			}
+			snapshot, err := database.SaveSubdomainSnapshot(domain, label)
+			if err != nil {
+				log.Fatalf("Failed to save snapshot of %s: %v", domain, err)
+			}
+			result.Snapshot = snapshot.Label
+			if *format == "text" {
+				fmt.Printf("Saved snapshot %s of %s (%d subdomains)\n", snapshot.Label, domain, snapshot.Count)
+			}
+		}
+		results = append(results, result)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(results)
+	case "csv":
+		err = writeCSV(results)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write results: %v", err)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// crtshBatch holds the crt.sh results fetched for every domain before enumeration starts
+type crtshBatch struct {
+	subdomains map[string][]models.Subdomain
+	errs       map[string]error
+}
+
+// fetchCrtshBatch queries crt.sh for all domains in parallel, grouping the merged results
+// back by domain
+func fetchCrtshBatch(ctx context.Context, client *api.SubdomainClient, domains []string, concurrency int) *crtshBatch {
+	subdomains, errs := client.FetchCrtshSubdomainsMulti(ctx, domains, concurrency)
+	batch := &crtshBatch{subdomains: make(map[string][]models.Subdomain), errs: errs}
+	for _, sub := range subdomains {
+		batch.subdomains[sub.Domain] = append(batch.subdomains[sub.Domain], sub)
+	}
+	return batch
+}
+
+// enumerate fetches one domain from one source and stores what was found, marking the domain
+// enumerated on success. Partial results are kept on failure, along with the VT resume cursor.
+// crt.sh results come from crtsh when it was fetched up front.
+func enumerate(ctx context.Context, database *db.DB, client *api.SubdomainClient, crtsh *crtshBatch, domain, source string, restart bool) sourceResult {
+	res := sourceResult{Source: source}
+
+	var subdomains []models.Subdomain
+	var cursor string
+	var err error
+	switch source {
+	case "virustotal":
+		startCursor := ""
+		if !restart {
+			startCursor, _ = database.GetVTCursor(domain)
+		}
+		subdomains, cursor, err = client.FetchAllVirusTotalSubdomainsWithResume(ctx, domain, startCursor, nil)
+	case "crtsh":
+		if crtsh != nil {
+			subdomains, err = crtsh.subdomains[domain], crtsh.errs[domain]
+		} else {
+			subdomains, err = client.FetchCrtshSubdomains(ctx, domain)
+		}
+	case "securitytrails":
+		subdomains, err = client.FetchSecurityTrailsSubdomains(domain)
+	case "censys":
+		subdomains, err = client.FetchCensysSubdomains(domain)
+	}
+
+	res.Found = len(subdomains)
+	if len(subdomains) > 0 {
+		inserted, insertErr := database.InsertSubdomains(subdomains)
+		if insertErr != nil {
+			res.Error = insertErr.Error()
+			return res
+		}
+		res.New = inserted
+	}
+
+	if err != nil {
+		res.Error = err.Error()
+		if source == "virustotal" && cursor != "" {
+			database.SetVTCursor(domain, cursor)
+		}
+		return res
+	}
+
+	// A completed run with no results still counts as enumerated
+	switch source {
+	case "virustotal":
+		database.MarkVTEnumerated(domain)
+		database.SetVTCursor(domain, "")
+	case "crtsh":
+		database.MarkCrtshEnumerated(domain)
+	}
+	return res
+}
+
+// diffSnapshot compares a domain's subdomains with the snapshot saved under label, or the
+// newest one for "latest". A domain without that snapshot yet gets a nil diff.
+func diffSnapshot(database *db.DB, domain, label string) (*snapshotDiff, error) {
+	if label == "latest" {
+		snapshots, err := database.GetSubdomainSnapshots(domain)
+		if err != nil || len(snapshots) == 0 {
+			return nil, err
+		}
+		label = snapshots[0].Label
+	}
+	diff, err := database.DiffSubdomainSnapshot(domain, label)
+	if err != nil || diff == nil {
+		return nil, err
+	}
+	return &snapshotDiff{Snapshot: label, Added: diff.Added, Removed: diff.Removed}, nil
+}
+
+// printDiff prints the subdomains added and removed since the snapshot
+func printDiff(domain string, diff *snapshotDiff) {
+	if diff == nil {
+		fmt.Printf("No snapshot of %s to compare with\n", domain)
+		return
+	}
+	fmt.Printf("%s since snapshot %s: %d added, %d removed\n", domain, diff.Snapshot, len(diff.Added), len(diff.Removed))
+	for _, name := range diff.Added {
+		fmt.Printf("  + %s\n", name)
+	}
+	for _, name := range diff.Removed {
+		fmt.Printf("  - %s\n", name)
+	}
+}
+
+// parseSources resolves -sources into stored source names; explicit is false for "all"
+func parseSources(raw string) ([]string, bool, error) {
+	names := splitList(raw)
+	if len(names) == 0 {
+		return nil, false, fmt.Errorf("-sources is empty")
+	}
+
+	var sources []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if name == "all" {
+			return allSources, false, nil
+		}
+		source, ok := sourceAliases[name]
+		if !ok {
+			return nil, false, fmt.Errorf("invalid source %q (want crtsh|vt|securitytrails|censys|all)", name)
+		}
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	return sources, true, nil
+}
+
+// usableSources drops sources whose API credentials are not configured. Requesting such a
+// source by name is an error; with "all" it is skipped with a warning.
+func usableSources(client *api.SubdomainClient, sources []string, explicit bool) ([]string, error) {
+	var usable []string
+	for _, source := range sources {
+		var missing string
+		switch source {
+		case "virustotal":
+			if !client.HasVirusTotalAPIKey() {
+				missing = "VirusTotal API key"
+			}
+		case "securitytrails":
+			if !client.HasSecurityTrailsAPIKey() {
+				missing = "SecurityTrails API key"
+			}
+		case "censys":
+			if !client.HasCensysCredentials() {
+				missing = "Censys API credentials"
+			}
+		}
+		if missing == "" {
+			usable = append(usable, source)
+			continue
+		}
+		if explicit {
+			return nil, fmt.Errorf("no %s configured for this project (add it in the TUI settings)", missing)
+		}
+		log.Printf("Skipping %s: no %s configured", source, missing)
+	}
+	return usable, nil
+}
+
+// splitList splits a comma-separated flag value, lowercasing and dropping empty entries
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// printSourceResult prints one source's outcome for a domain
+func printSourceResult(domain string, res sourceResult) {
+	if res.Error != "" {
+		fmt.Printf("[FAIL] %s via %s: %s (kept %d subdomains, %d new)\n", domain, res.Source, res.Error, res.Found, res.New)
+		return
+	}
+	fmt.Printf("[OK] %s via %s: %d subdomains (%d new)\n", domain, res.Source, res.Found, res.New)
+}
+
+// writeCSV writes one row per domain and source
+func writeCSV(results []domainResult) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write([]string{"domain", "source", "found", "new", "domain_total", "error", "snapshot_added", "snapshot_removed"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		// Diff counts are per domain, repeated on each of its rows (empty without a snapshot)
+		var added, removed string
+		if r.Diff != nil {
+			added, removed = strconv.Itoa(len(r.Diff.Added)), strconv.Itoa(len(r.Diff.Removed))
+		}
+		for _, res := range r.Sources {
+			if err := w.Write([]string{
+				r.Domain,
+				res.Source,
+				strconv.Itoa(res.Found),
+				strconv.Itoa(res.New),
+				strconv.Itoa(r.Total),
+				res.Error,
+				added,
+				removed,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -6,15 +6,45 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 
 	_ "modernc.org/sqlite"
 )
 
+// identity is one exported row: an email (or a login's emails) with its name, login and commits
+type identity struct {
+	email string
+	login string
+	names map[string]int // display name -> commits using it
+	shas  map[string]bool
+}
+
+// name returns the most used display name (ties broken alphabetically)
+func (id *identity) name() string {
+	best, bestCount := "", 0
+	for name, count := range id.names {
+		if count > bestCount || (count == bestCount && name < best) {
+			best, bestCount = name, count
+		}
+	}
+	return best
+}
+
 func main() {
 	dbPath := flag.String("db", "raspberrypi.db", "Path to SQLite database")
 	outputPath := flag.String("output", "emails.csv", "Output CSV file")
+	withName := flag.Bool("name", false, "Include the display name used with each email")
+	withCommits := flag.Bool("commits", false, "Include the number of commits authored or committed")
+	withLogin := flag.Bool("login", false, "Include the GitHub login")
+	dedupeBy := flag.String("dedupe-by", "email", "One row per email or per GitHub login (email|login)")
 	flag.Parse()
 
+	if *dedupeBy != "email" && *dedupeBy != "login" {
+		fmt.Fprintf(os.Stderr, "Invalid -dedupe-by %q: use email or login\n", *dedupeBy)
+		os.Exit(1)
+	}
+
 	db, err := sql.Open("sqlite", *dbPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
@@ -22,6 +52,33 @@ func main() {
 	}
 	defer db.Close()
 
+	f, err := os.Create(*outputPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	// Without extra columns or login dedupe, keep the original single-column dump
+	var count int
+	if !*withName && !*withCommits && !*withLogin && *dedupeBy == "email" {
+		count, err = exportEmails(db, w)
+	} else {
+		count, err = exportIdentities(db, w, *withName, *withCommits, *withLogin, *dedupeBy == "login")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Exported %d emails to %s\n", count, *outputPath)
+}
+
+// exportEmails writes the distinct author and committer emails as a single "email" column
+func exportEmails(db *sql.DB, w *csv.Writer) (int, error) {
 	rows, err := db.Query(`
 		SELECT DISTINCT author_email as email
 		FROM commits
@@ -33,24 +90,12 @@ func main() {
 		ORDER BY 1
 	`)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to query database: %v\n", err)
-		os.Exit(1)
+		return 0, fmt.Errorf("failed to query database: %w", err)
 	}
 	defer rows.Close()
 
-	f, err := os.Create(*outputPath)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to create output file: %v\n", err)
-		os.Exit(1)
-	}
-	defer f.Close()
-
-	w := csv.NewWriter(f)
-	defer w.Flush()
-
 	if err := w.Write([]string{"email"}); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to write header: %v\n", err)
-		os.Exit(1)
+		return 0, fmt.Errorf("failed to write header: %w", err)
 	}
 
 	count := 0
@@ -66,6 +111,126 @@ func main() {
 		}
 		count++
 	}
+	return count, nil
+}
 
-	fmt.Printf("Exported %d emails to %s\n", count, *outputPath)
+// exportIdentities writes one row per email (or per login when byLogin is set) with the
+// requested extra columns. Commits are counted once even when an email both authored and
+// committed them. Emails with no known login stay on their own row when deduping by login.
+func exportIdentities(db *sql.DB, w *csv.Writer, withName, withCommits, withLogin, byLogin bool) (int, error) {
+	rows, err := db.Query(`
+		SELECT sha, author_email, author_name, COALESCE(github_author_login, '')
+		FROM commits
+		WHERE author_email IS NOT NULL AND author_email != ''
+		UNION ALL
+		SELECT sha, committer_email, committer_name, COALESCE(github_committer_login, '')
+		FROM commits
+		WHERE committer_email IS NOT NULL AND committer_email != ''
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query database: %w", err)
+	}
+	defer rows.Close()
+
+	byEmail := make(map[string]*identity)
+	for rows.Next() {
+		var sha, email, name, login string
+		if err := rows.Scan(&sha, &email, &name, &login); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to scan row: %v\n", err)
+			continue
+		}
+		id := byEmail[email]
+		if id == nil {
+			id = &identity{email: email, names: make(map[string]int), shas: make(map[string]bool)}
+			byEmail[email] = id
+		}
+		if login != "" && id.login == "" {
+			id.login = login
+		}
+		if name != "" && !id.shas[sha] {
+			id.names[name]++
+		}
+		id.shas[sha] = true
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	identities := make([]*identity, 0, len(byEmail))
+	if byLogin {
+		identities = mergeByLogin(byEmail)
+	} else {
+		for _, id := range byEmail {
+			identities = append(identities, id)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].email < identities[j].email })
+
+	header := []string{"email"}
+	if withName {
+		header = append(header, "name")
+	}
+	if withCommits {
+		header = append(header, "commits")
+	}
+	if withLogin {
+		header = append(header, "login")
+	}
+	if err := w.Write(header); err != nil {
+		return 0, fmt.Errorf("failed to write header: %w", err)
+	}
+
+	count := 0
+	for _, id := range identities {
+		record := []string{id.email}
+		if withName {
+			record = append(record, id.name())
+		}
+		if withCommits {
+			record = append(record, strconv.Itoa(len(id.shas)))
+		}
+		if withLogin {
+			record = append(record, id.login)
+		}
+		if err := w.Write(record); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write row: %v\n", err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// mergeByLogin folds emails sharing a GitHub login into one identity, listed under the
+// email with the most commits
+func mergeByLogin(byEmail map[string]*identity) []*identity {
+	var merged []*identity
+	byLogin := make(map[string][]*identity)
+	for _, id := range byEmail {
+		if id.login == "" {
+			merged = append(merged, id)
+			continue
+		}
+		byLogin[id.login] = append(byLogin[id.login], id)
+	}
+
+	for login, ids := range byLogin {
+		sort.Slice(ids, func(i, j int) bool {
+			if len(ids[i].shas) != len(ids[j].shas) {
+				return len(ids[i].shas) > len(ids[j].shas)
+			}
+			return ids[i].email < ids[j].email
+		})
+		combined := &identity{email: ids[0].email, login: login, names: make(map[string]int), shas: make(map[string]bool)}
+		for _, id := range ids {
+			for name, n := range id.names {
+				combined.names[name] += n
+			}
+			for sha := range id.shas {
+				combined.shas[sha] = true
+			}
+		}
+		merged = append(merged, combined)
+	}
+	return merged
 }
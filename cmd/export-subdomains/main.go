@@ -1,20 +1,70 @@
 package main
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/thesavant42/gitsome-ng/internal/api"
 	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
+	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 func main() {
 	// Parse command line flags
 	dbPath := flag.String("db", "generic.db", "Path to SQLite database")
+	source := flag.String("source", "all", "Only export subdomains from this source (virustotal|crtsh|import|securitytrails|censys|rdns|all)")
+	domainFlag := flag.String("domain", "", "Only export this target domain")
+	format := flag.String("format", "md", "Output format (md|json|csv)")
+	refresh := flag.Bool("refresh-crtsh", false, "Re-fetch crt.sh subdomains for the exported domains before writing")
+	concurrency := flag.Int("concurrency", 4, "Number of parallel crt.sh queries when refreshing")
+	crtshTimeout := flag.Duration("crtsh-timeout", 0, "Give up on the crt.sh refresh after this long (e.g. 5m; 0 = no limit)")
+	resolve := flag.Bool("resolve", false, "Resolve the exported subdomains' IPs before writing")
+	ptr := flag.Bool("ptr", false, "Also look up reverse DNS (PTR) names for resolved IPs (implies -resolve)")
+	expandCIDR := flag.String("expand-cidr", "", "Reverse-resolve an IPv4 CIDR and store hostnames under matching tracked domains")
+	expandASN := flag.String("expand-asn", "", "Reverse-resolve the IPv4 prefixes an ASN announces (e.g. AS13335) and store matching hostnames")
+	probe := flag.Bool("probe", false, "Probe the exported subdomains over http:// and https://, recording status and the served TLS certificate")
+	since := flag.Duration("since", 0, "Only export subdomains discovered within this long (e.g. 24h), newest first")
+	lastRun := flag.Bool("last-run", false, "Only export subdomains found by each domain's latest enumeration run, newest first")
+	proxy := flag.String("proxy", "", "Proxy URL for crt.sh and RIPEstat requests (http, https, socks5 or socks5h; default: proxy environment variables)")
+	logOpts := logging.AddFlags(flag.CommandLine)
 	flag.Parse()
 
+	logFile, err := logging.Setup(*logOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer logFile.Close()
+
+	if err := api.SetProxy(*proxy); err != nil {
+		log.Fatalf("Invalid -proxy: %v", err)
+	}
+
+	switch *format {
+	case "md", "json", "csv":
+	default:
+		log.Fatalf("Invalid -format %q (want md|json|csv)", *format)
+	}
+
+	switch *source {
+	case "all", "virustotal", "crtsh", "import", "securitytrails", "censys", api.SourceReverseDNS:
+	default:
+		log.Fatalf("Invalid -source %q (want virustotal|crtsh|import|securitytrails|censys|rdns|all)", *source)
+	}
+
+	if *since < 0 {
+		log.Fatalf("Invalid -since %s (want a positive duration)", *since)
+	}
+	filter := exportFilter{source: *source, since: *since, lastRun: *lastRun}
+
 	// Open database
 	database, err := db.New(*dbPath)
 	if err != nil {
@@ -22,21 +72,294 @@ func main() {
 	}
 	defer database.Close()
 
-	// Get all target domains
-	domains, err := database.GetTargetDomainsWithCounts()
-	if err != nil {
-		log.Fatalf("Failed to get target domains: %v", err)
+	// Get target domains - either the single requested domain or all of them
+	var domains []models.TargetDomain
+	if *domainFlag != "" {
+		target, err := database.GetTargetDomain(strings.ToLower(strings.TrimSpace(*domainFlag)))
+		if err != nil {
+			log.Fatalf("Failed to get target domain: %v", err)
+		}
+		if target == nil {
+			log.Fatalf("Domain %s is not tracked", *domainFlag)
+		}
+		stats, err := database.GetSubdomainStats(target.Domain)
+		if err != nil {
+			log.Fatalf("Failed to count subdomains: %v", err)
+		}
+		target.SubdomainCount = stats.Total
+		target.VTCount = stats.VTCount
+		target.CrtshCount = stats.CrtshCount
+		target.SecurityTrailsCount = stats.SecurityTrailsCount
+		target.CensysCount = stats.CensysCount
+		target.ImportCount = stats.ImportCount
+		domains = []models.TargetDomain{*target}
+	} else {
+		domains, err = database.GetTargetDomainsWithCounts()
+		if err != nil {
+			log.Fatalf("Failed to get target domains: %v", err)
+		}
 	}
 
-	// Create markdown file
+	if *expandCIDR != "" || *expandASN != "" {
+		expandNetblock(database, domains, *expandCIDR, *expandASN)
+	}
+	if *refresh {
+		ctx := context.Background()
+		if *crtshTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *crtshTimeout)
+			defer cancel()
+		}
+		refreshCrtsh(ctx, database, domains, *concurrency)
+	}
+	if *resolve || *ptr {
+		resolveDomains(database, domains, *ptr)
+	}
+	if *probe {
+		probeDomains(database, domains, *concurrency)
+	}
+
+	// Create output file - extension follows the format
 	timestamp := time.Now().Format("20060102-150405")
-	filename := fmt.Sprintf("subdomains-export-%s.md", timestamp)
+	filename := fmt.Sprintf("subdomains-export-%s.%s", timestamp, *format)
 	f, err := os.Create(filename)
 	if err != nil {
 		log.Fatalf("Failed to create file: %v", err)
 	}
 	defer f.Close()
 
+	exportedAt := time.Now()
+	switch *format {
+	case "json":
+		err = writeJSON(f, database, domains, filter)
+	case "csv":
+		err = writeCSV(f, database, domains, filter)
+	default:
+		writeMarkdown(f, database, domains, filter)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write export: %v", err)
+	}
+
+	// The next export marks what was discovered after this one as new. A filtered export
+	// left rows out, so it doesn't move the marker past them.
+	if !filter.filtered() {
+		for _, domain := range domains {
+			if err := database.SetLastSubdomainExport(domain.Domain, exportedAt); err != nil {
+				log.Printf("Failed to record export time for %s: %v", domain.Domain, err)
+			}
+		}
+	}
+
+	fmt.Printf("[OK] Exported to %s\n", filename)
+}
+
+// exportFilter selects which subdomains of a domain are exported
+type exportFilter struct {
+	source  string        // Only subdomains reported by this source ("all" keeps everything)
+	since   time.Duration // Only subdomains discovered within this long (0 = any time)
+	lastRun bool          // Only subdomains found by the domain's latest enumeration run
+}
+
+// recent reports whether the export is limited by discovery time, which sorts it newest first
+func (e exportFilter) recent() bool {
+	return e.since > 0 || e.lastRun
+}
+
+// filtered reports whether the export leaves out any of a domain's subdomains
+func (e exportFilter) filtered() bool {
+	return e.source != "all" || e.recent()
+}
+
+// domainSubdomains returns the domain's subdomains that pass the filter, along with when the
+// domain was last exported (zero if never)
+func domainSubdomains(database *db.DB, domain string, filter exportFilter) ([]models.Subdomain, time.Time, error) {
+	lastExport, err := database.GetLastSubdomainExport(domain)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if !filter.recent() {
+		subdomains, err := database.GetSubdomains(domain)
+		return filterBySource(subdomains, filter.source), lastExport, err
+	}
+
+	// With both limits set the later start wins
+	var from time.Time
+	if filter.since > 0 {
+		from = time.Now().Add(-filter.since)
+	}
+	if filter.lastRun {
+		run, err := database.GetLastSubdomainRun(domain)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if run.IsZero() {
+			return nil, lastExport, nil // never enumerated, so nothing is recent
+		}
+		if run.After(from) {
+			from = run
+		}
+	}
+	subdomains, err := database.GetRecentSubdomains(domain, from)
+	return filterBySource(subdomains, filter.source), lastExport, err
+}
+
+// newSinceExport reports whether sub was discovered after the last export (never exported
+// marks nothing, as everything would be new)
+func newSinceExport(sub models.Subdomain, lastExport time.Time) bool {
+	return !lastExport.IsZero() && sub.DiscoveredAt.After(lastExport)
+}
+
+// refreshCrtsh fetches crt.sh for all domains in parallel and stores the results
+// Subdomain counts on domains are updated in place so the export reflects new records.
+func refreshCrtsh(ctx context.Context, database *db.DB, domains []models.TargetDomain, concurrency int) {
+	names := make([]string, len(domains))
+	for i, d := range domains {
+		names[i] = d.Domain
+	}
+
+	client := api.NewSubdomainClient("", nil)
+	subdomains, errs := client.FetchCrtshSubdomainsMulti(ctx, names, concurrency)
+	for domain, err := range errs {
+		log.Printf("crt.sh fetch failed for %s: %v", domain, err)
+	}
+
+	inserted, err := database.InsertSubdomains(subdomains)
+	if err != nil {
+		log.Printf("Failed to store crt.sh subdomains: %v", err)
+		return
+	}
+	fmt.Printf("[OK] crt.sh refresh: %d subdomains (%d new) across %d domains\n", len(subdomains), inserted, len(names)-len(errs))
+
+	for i := range domains {
+		if _, failed := errs[domains[i].Domain]; failed {
+			continue
+		}
+		database.MarkCrtshEnumerated(domains[i].Domain)
+		domains[i].CrtshEnumerated = true
+		if count, err := database.GetSubdomainCount(domains[i].Domain); err == nil {
+			domains[i].SubdomainCount = count
+		}
+	}
+}
+
+// expandNetblock reverse-resolves a CIDR and/or an ASN's prefixes and stores the hostnames
+// that fall under the domains being exported
+func expandNetblock(database *db.DB, domains []models.TargetDomain, cidr, asn string) {
+	targets := make([]string, len(domains))
+	for i, d := range domains {
+		targets[i] = d.Domain
+	}
+
+	client := api.NewSubdomainClient("", nil)
+	ctx := context.Background()
+	var hostnames []string
+	if cidr != "" {
+		names, err := client.ReverseResolveCIDR(ctx, cidr, 0)
+		if err != nil {
+			log.Printf("Reverse DNS of %s failed: %v", cidr, err)
+		}
+		hostnames = append(hostnames, names...)
+	}
+	if asn != "" {
+		names, err := client.ReverseResolveASN(ctx, asn, 0)
+		if err != nil {
+			log.Printf("Reverse DNS of %s failed: %v", asn, err)
+		}
+		hostnames = append(hostnames, names...)
+	}
+
+	subdomains := api.SubdomainsForTargets(hostnames, targets)
+	inserted, err := database.InsertSubdomains(subdomains)
+	if err != nil {
+		log.Printf("Failed to store reverse DNS subdomains: %v", err)
+		return
+	}
+	fmt.Printf("[OK] Reverse DNS: %d hostnames, %d under tracked domains (%d new)\n", len(hostnames), len(subdomains), inserted)
+
+	for i := range domains {
+		if count, err := database.GetSubdomainCount(domains[i].Domain); err == nil {
+			domains[i].SubdomainCount = count
+		}
+	}
+}
+
+// resolveDomains looks up the IPs (and PTR names when ptr is set) of every subdomain of the
+// domains and stores them
+func resolveDomains(database *db.DB, domains []models.TargetDomain, ptr bool) {
+	client := api.NewSubdomainClient("", nil)
+	client.SetLookupPTR(ptr)
+
+	for _, domain := range domains {
+		subdomains, err := database.GetAllSubdomainsForDomain(domain.Domain)
+		if err != nil {
+			log.Printf("Failed to get subdomains for %s: %v", domain.Domain, err)
+			continue
+		}
+		resolved, err := client.ResolveSubdomains(subdomains, 0, nil)
+		if err != nil {
+			log.Printf("Resolution failed for %s: %v", domain.Domain, err)
+		}
+
+		live := 0
+		for _, sub := range resolved {
+			var ips []string
+			if sub.ResolvedIPs != "" {
+				ips = strings.Split(sub.ResolvedIPs, ",")
+				live++
+			}
+			if err := database.UpdateSubdomainResolution(sub.Subdomain, ips); err != nil {
+				log.Printf("Failed to store resolution for %s: %v", sub.Subdomain, err)
+				continue
+			}
+			if ptr {
+				var names []string
+				if sub.PTRNames != "" {
+					names = strings.Split(sub.PTRNames, ",")
+				}
+				if err := database.UpdateSubdomainPTRNames(sub.Subdomain, names); err != nil {
+					log.Printf("Failed to store PTR names for %s: %v", sub.Subdomain, err)
+				}
+			}
+		}
+		fmt.Printf("[OK] Resolved %s: %d of %d subdomains resolve\n", domain.Domain, live, len(resolved))
+	}
+}
+
+// probeDomains probes every subdomain of the domains over HTTP(S) and stores the status,
+// final URL and served certificate
+func probeDomains(database *db.DB, domains []models.TargetDomain, concurrency int) {
+	client := api.NewSubdomainClient("", nil)
+
+	for _, domain := range domains {
+		subdomains, err := database.GetAllSubdomainsForDomain(domain.Domain)
+		if err != nil {
+			log.Printf("Failed to get subdomains for %s: %v", domain.Domain, err)
+			continue
+		}
+		probed, err := client.ProbeSubdomains(subdomains, 0, concurrency, nil, nil)
+		if err != nil {
+			log.Printf("Probing failed for %s: %v", domain.Domain, err)
+		}
+
+		live, mismatched := 0, 0
+		for _, sub := range probed {
+			if sub.HTTPStatus != 0 || sub.HTTPSStatus != 0 {
+				live++
+			}
+			if sub.TLSHostMismatch {
+				mismatched++
+			}
+			if err := database.UpdateSubdomainProbe(sub); err != nil {
+				log.Printf("Failed to store probe result for %s: %v", sub.Subdomain, err)
+			}
+		}
+		fmt.Printf("[OK] Probed %s: %d of %d subdomains answered, %d certificates do not match the host\n", domain.Domain, live, len(probed), mismatched)
+	}
+}
+
+// writeMarkdown writes the human-readable report, one section per domain
+func writeMarkdown(f *os.File, database *db.DB, domains []models.TargetDomain, filter exportFilter) {
 	// Write header
 	fmt.Fprintf(f, "# Subdomain Export\n\n")
 	fmt.Fprintf(f, "Generated: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
@@ -45,22 +368,39 @@ func main() {
 	// Process each domain
 	for _, domain := range domains {
 		fmt.Fprintf(f, "## %s\n\n", domain.Domain)
-		fmt.Fprintf(f, "- **Subdomain Count**: %d\n", domain.SubdomainCount)
+		fmt.Fprintf(f, "- **Subdomain Count**: %s\n", domain.CountSummary())
 		fmt.Fprintf(f, "- **VirusTotal Enumerated**: %v\n", domain.VTEnumerated)
 		fmt.Fprintf(f, "- **crt.sh Enumerated**: %v\n", domain.CrtshEnumerated)
 		fmt.Fprintf(f, "- **Added**: %s\n\n", domain.AddedAt.Format("2006-01-02 15:04:05"))
 
 		// Get subdomains for this domain
-		subdomains, err := database.GetSubdomains(domain.Domain)
+		subdomains, lastExport, err := domainSubdomains(database, domain.Domain, filter)
 		if err != nil {
 			log.Printf("Failed to get subdomains for %s: %v", domain.Domain, err)
 			continue
 		}
 
 		if len(subdomains) > 0 {
+			// Only show the Resolves and Live Cert columns once resolution/probing has been
+			// run for this domain
+			showResolves := hasResolutionData(subdomains)
+			showTLS := hasTLSData(subdomains)
+
+			headers := []string{"Subdomain", "Sources", "CNAMEs", "Cert Expired", "CDX Indexed"}
+			if showResolves {
+				headers = append(headers, "Resolves", "PTR", "Networks")
+			}
+			if showTLS {
+				headers = append(headers, "Live Cert")
+			}
+			headers = append(headers, "Discovered")
+
 			fmt.Fprintf(f, "### Subdomains\n\n")
-			fmt.Fprintf(f, "| Subdomain | Source | CNAMEs | Cert Expired | CDX Indexed | Discovered |\n")
-			fmt.Fprintf(f, "|-----------|--------|--------|--------------|-------------|------------|\n")
+			if !lastExport.IsZero() {
+				fmt.Fprintf(f, "Subdomains marked **new** were discovered after the last export (%s).\n\n", lastExport.Local().Format("2006-01-02 15:04:05"))
+			}
+			fmt.Fprintf(f, "| %s |\n", strings.Join(headers, " | "))
+			fmt.Fprintf(f, "|%s\n", strings.Repeat("---|", len(headers)))
 
 			for _, sub := range subdomains {
 				cnames := sub.CNAMEs
@@ -77,8 +417,32 @@ func main() {
 				}
 				discovered := sub.DiscoveredAt.Format("2006-01-02")
 
-				fmt.Fprintf(f, "| %s | %s | %s | %s | %s | %s |\n",
-					sub.Subdomain, sub.Source, cnames, certExpired, cdxIndexed, discovered)
+				name := sub.Subdomain
+				if newSinceExport(sub, lastExport) {
+					name += " **new**"
+				}
+
+				cells := []string{name, sub.Sources, cnames, certExpired, cdxIndexed}
+				if showResolves {
+					resolves := "No"
+					if sub.Resolves {
+						resolves = "Yes"
+					}
+					ptrNames := sub.PTRNames
+					if ptrNames == "" {
+						ptrNames = "-"
+					}
+					networks := strings.Join(sub.Networks(), ",")
+					if networks == "" {
+						networks = "-"
+					}
+					cells = append(cells, resolves, ptrNames, networks)
+				}
+				if showTLS {
+					cells = append(cells, liveCertSummary(sub))
+				}
+				cells = append(cells, discovered)
+				fmt.Fprintf(f, "| %s |\n", strings.Join(cells, " | "))
 			}
 			fmt.Fprintf(f, "\n")
 		} else {
@@ -87,6 +451,163 @@ func main() {
 
 		fmt.Fprintf(f, "---\n\n")
 	}
+}
 
-	fmt.Printf("[OK] Exported to %s\n", filename)
+// exportRecord is the machine-readable form of a subdomain for JSON/CSV output
+type exportRecord struct {
+	Domain       string    `json:"domain"`
+	Subdomain    string    `json:"subdomain"`
+	Source       string    `json:"source"`
+	Sources      string    `json:"sources"`
+	CNAMEs       string    `json:"cnames"`
+	ResolvedIPs  string    `json:"resolved_ips"`
+	PTRNames     string    `json:"ptr_names"`
+	Networks     string    `json:"networks"`
+	CertExpired  bool      `json:"cert_expired"`
+	CDXIndexed   bool      `json:"cdx_indexed"`
+	DiscoveredAt time.Time `json:"discovered_at"`
+	New          bool      `json:"new_since_export"` // Discovered after the previous export
+
+	// Certificate served live over https:// (empty until the subdomain is probed)
+	TLSSubject      string     `json:"tls_subject"`
+	TLSSANs         string     `json:"tls_sans"`
+	TLSIssuer       string     `json:"tls_issuer"`
+	TLSNotBefore    *time.Time `json:"tls_not_before,omitempty"`
+	TLSNotAfter     *time.Time `json:"tls_not_after,omitempty"`
+	TLSHostMismatch bool       `json:"tls_host_mismatch"`
+}
+
+// collectRecords flattens the subdomains of all domains into export records
+func collectRecords(database *db.DB, domains []models.TargetDomain, filter exportFilter) []exportRecord {
+	records := []exportRecord{}
+	for _, domain := range domains {
+		subdomains, lastExport, err := domainSubdomains(database, domain.Domain, filter)
+		if err != nil {
+			log.Printf("Failed to get subdomains for %s: %v", domain.Domain, err)
+			continue
+		}
+		for _, sub := range subdomains {
+			record := exportRecord{
+				Domain:       sub.Domain,
+				Subdomain:    sub.Subdomain,
+				Source:       sub.Source,
+				Sources:      sub.Sources,
+				CNAMEs:       sub.CNAMEs,
+				ResolvedIPs:  sub.ResolvedIPs,
+				PTRNames:     sub.PTRNames,
+				Networks:     strings.Join(sub.Networks(), ","),
+				CertExpired:  sub.CertExpired,
+				CDXIndexed:   sub.CDXIndexed,
+				DiscoveredAt: sub.DiscoveredAt,
+				New:          newSinceExport(sub, lastExport),
+
+				TLSSubject:      sub.TLSSubject,
+				TLSSANs:         sub.TLSSANs,
+				TLSIssuer:       sub.TLSIssuer,
+				TLSHostMismatch: sub.TLSHostMismatch,
+			}
+			if sub.HasTLSCert() {
+				notBefore, notAfter := sub.TLSNotBefore, sub.TLSNotAfter
+				record.TLSNotBefore, record.TLSNotAfter = &notBefore, &notAfter
+			}
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// writeJSON writes all subdomains as a single JSON array
+func writeJSON(f *os.File, database *db.DB, domains []models.TargetDomain, filter exportFilter) error {
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(collectRecords(database, domains, filter))
+}
+
+// writeCSV writes a header row followed by one line per subdomain
+func writeCSV(f *os.File, database *db.DB, domains []models.TargetDomain, filter exportFilter) error {
+	w := csv.NewWriter(f)
+	if err := w.Write([]string{"domain", "subdomain", "source", "sources", "cnames", "resolved_ips", "ptr_names", "networks", "cert_expired", "cdx_indexed", "discovered_at", "new_since_export", "tls_subject", "tls_sans", "tls_issuer", "tls_not_before", "tls_not_after", "tls_host_mismatch"}); err != nil {
+		return err
+	}
+	for _, r := range collectRecords(database, domains, filter) {
+		if err := w.Write([]string{
+			r.Domain,
+			r.Subdomain,
+			r.Source,
+			r.Sources,
+			r.CNAMEs,
+			r.ResolvedIPs,
+			r.PTRNames,
+			r.Networks,
+			strconv.FormatBool(r.CertExpired),
+			strconv.FormatBool(r.CDXIndexed),
+			r.DiscoveredAt.Format(time.RFC3339),
+			strconv.FormatBool(r.New),
+			r.TLSSubject,
+			r.TLSSANs,
+			r.TLSIssuer,
+			formatOptionalTime(r.TLSNotBefore),
+			formatOptionalTime(r.TLSNotAfter),
+			strconv.FormatBool(r.TLSHostMismatch),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// filterBySource returns only the subdomains reported by source ("all" keeps everything)
+func filterBySource(subdomains []models.Subdomain, source string) []models.Subdomain {
+	if source == "all" {
+		return subdomains
+	}
+	filtered := make([]models.Subdomain, 0, len(subdomains))
+	for _, sub := range subdomains {
+		if sub.HasSource(source) {
+			filtered = append(filtered, sub)
+		}
+	}
+	return filtered
+}
+
+// hasResolutionData reports whether any subdomain has been resolved
+func hasResolutionData(subdomains []models.Subdomain) bool {
+	for _, sub := range subdomains {
+		if sub.Resolves || sub.ResolvedIPs != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTLSData reports whether probing captured a certificate for any subdomain
+func hasTLSData(subdomains []models.Subdomain) bool {
+	for _, sub := range subdomains {
+		if sub.HasTLSCert() {
+			return true
+		}
+	}
+	return false
+}
+
+// liveCertSummary describes the probed certificate, e.g. "CN=api.example.com, R3, until 2026-01-02"
+// with a host mismatch called out; "-" when none was captured
+func liveCertSummary(sub models.Subdomain) string {
+	if !sub.HasTLSCert() {
+		return "-"
+	}
+	summary := fmt.Sprintf("CN=%s, %s, until %s", sub.TLSSubject, sub.TLSIssuer, sub.TLSNotAfter.Format("2006-01-02"))
+	if sub.TLSHostMismatch {
+		summary += " (**host mismatch**)"
+	}
+	return strings.ReplaceAll(summary, "|", "\\|")
+}
+
+// formatOptionalTime formats t as RFC 3339, or "" when nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Rule names reported by the linter
+const (
+	RuleHardcodedWidth  = "hardcoded-width"
+	RuleHardcodedHeight = "hardcoded-height"
+	RuleFixedRepeat     = "fixed-repeat"
+	RuleSprintfWidth    = "hardcoded-sprintf-width"
+	RuleFixedInputWidth = "fixed-input-width"
+	RuleLipglossTable   = "lipgloss-table"
+)
+
+// SeverityOff disables a rule
+const SeverityOff = "OFF"
+
+// Config is the on-disk linter configuration (JSON), merged over the built-in defaults
+//
+// Example:
+//
+//	{
+//	  "rules": {"hardcoded-sprintf-width": "off", "fixed-repeat": "warning"},
+//	  "allowedConstPatterns": ["Gutter\\w*\\s*=\\s*\\d+"],
+//	  "exemptLiterals": {"hardcoded-height": ["3"]}
+//	}
+type Config struct {
+	// Rules overrides rule severities: "error", "warning" or "off"
+	Rules map[string]string `json:"rules"`
+	// AllowedConstPatterns are extra regexes for lines (typically constants) that may hold numeric literals
+	AllowedConstPatterns []string `json:"allowedConstPatterns"`
+	// ExemptLiterals lists extra numeric literals each rule ignores
+	ExemptLiterals map[string][]string `json:"exemptLiterals"`
+}
+
+// RuleSet is the effective configuration used while linting
+type RuleSet struct {
+	Severity       map[string]string          // rule -> SeverityError, SeverityWarning or SeverityOff
+	AllowedPattern []*regexp.Regexp           // lines matching any pattern are exempt
+	ExemptLiterals map[string]map[string]bool // rule -> numeric literals it ignores
+}
+
+// DefaultRuleSet returns the built-in rules
+func DefaultRuleSet() *RuleSet {
+	return &RuleSet{
+		Severity: map[string]string{
+			RuleHardcodedWidth:  SeverityError,
+			RuleHardcodedHeight: SeverityError,
+			RuleFixedRepeat:     SeverityError,
+			RuleSprintfWidth:    SeverityWarning,
+			RuleFixedInputWidth: SeverityError,
+			RuleLipglossTable:   SeverityWarning,
+		},
+		AllowedPattern: []*regexp.Regexp{
+			constPattern,
+			colWidthConstPattern,
+			minMaxConstPattern,
+			tableHeightConstPattern,
+			paddingConstPattern,
+			colSepPattern,
+		},
+		ExemptLiterals: map[string]map[string]bool{
+			// Small heights are typically minimum safeguards
+			RuleHardcodedHeight: {"10": true, "5": true},
+		},
+	}
+}
+
+// LoadConfig reads a JSON config file and merges it over the rule set
+func (rs *RuleSet) LoadConfig(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config: %w", err)
+	}
+	defer f.Close()
+
+	var cfg Config
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return rs.Merge(cfg)
+}
+
+// Merge applies a config over the rule set; unknown rules and severities are errors
+func (rs *RuleSet) Merge(cfg Config) error {
+	for rule, severity := range cfg.Rules {
+		if _, ok := rs.Severity[rule]; !ok {
+			return fmt.Errorf("unknown rule %q in config", rule)
+		}
+		switch strings.ToLower(severity) {
+		case "error":
+			rs.Severity[rule] = SeverityError
+		case "warning":
+			rs.Severity[rule] = SeverityWarning
+		case "off":
+			rs.Severity[rule] = SeverityOff
+		default:
+			return fmt.Errorf("invalid severity %q for rule %q (want error, warning or off)", severity, rule)
+		}
+	}
+
+	for _, pattern := range cfg.AllowedConstPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid allowed pattern %q: %w", pattern, err)
+		}
+		rs.AllowedPattern = append(rs.AllowedPattern, re)
+	}
+
+	for rule, literals := range cfg.ExemptLiterals {
+		if _, ok := rs.Severity[rule]; !ok {
+			return fmt.Errorf("unknown rule %q in exemptLiterals", rule)
+		}
+		if rs.ExemptLiterals[rule] == nil {
+			rs.ExemptLiterals[rule] = make(map[string]bool)
+		}
+		for _, lit := range literals {
+			rs.ExemptLiterals[rule][lit] = true
+		}
+	}
+	return nil
+}
+
+// severityFor returns the severity to report for a rule match, or "" when it is disabled or exempt
+func (rs *RuleSet) severityFor(rule, literal string) string {
+	severity := rs.Severity[rule]
+	if severity == SeverityOff || rs.ExemptLiterals[rule][literal] {
+		return ""
+	}
+	return severity
+}
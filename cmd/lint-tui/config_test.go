@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfig verifies a config file is merged over the defaults and that unknown rules,
+// bad severities, bad patterns and unknown fields are rejected
+func TestLoadConfig(t *testing.T) {
+	writeConfig := func(t *testing.T, body string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "lint-tui.json")
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("merges over defaults", func(t *testing.T) {
+		rs := DefaultRuleSet()
+		path := writeConfig(t, `{
+			"rules": {"hardcoded-sprintf-width": "off", "fixed-repeat": "Warning"},
+			"allowedConstPatterns": ["Gutter\\w*\\s*=\\s*\\d+"],
+			"exemptLiterals": {"hardcoded-height": ["3"], "hardcoded-width": ["1"]}
+		}`)
+		if err := rs.LoadConfig(path); err != nil {
+			t.Fatalf("LoadConfig() error = %v", err)
+		}
+
+		wantSeverity := map[string]string{
+			RuleHardcodedWidth:  SeverityError, // untouched default
+			RuleFixedRepeat:     SeverityWarning,
+			RuleSprintfWidth:    SeverityOff,
+			RuleLipglossTable:   SeverityWarning,
+			RuleHardcodedHeight: SeverityError,
+		}
+		for rule, want := range wantSeverity {
+			if got := rs.Severity[rule]; got != want {
+				t.Errorf("Severity[%s] = %q, want %q", rule, got, want)
+			}
+		}
+
+		// Built-in exemptions survive alongside the configured ones
+		for _, lit := range []string{"10", "5", "3"} {
+			if rs.severityFor(RuleHardcodedHeight, lit) != "" {
+				t.Errorf("height literal %s not exempt", lit)
+			}
+		}
+		if rs.severityFor(RuleHardcodedWidth, "1") != "" {
+			t.Error("width literal 1 not exempt")
+		}
+		if rs.severityFor(RuleHardcodedWidth, "50") != SeverityError {
+			t.Error("width literal 50 should still be an error")
+		}
+		if rs.severityFor(RuleSprintfWidth, "20") != "" {
+			t.Error("disabled rule still reports")
+		}
+
+		if !rs.isAllowedException("\tGutterWidth = 4", nil, 0) {
+			t.Error("configured allowed pattern not applied")
+		}
+		if !rs.isAllowedException("const maxRows = 4", nil, 0) {
+			t.Error("built-in const pattern dropped")
+		}
+	})
+
+	errorCases := []struct {
+		name, body, wantErr string
+	}{
+		{"unknown rule", `{"rules": {"no-such-rule": "error"}}`, `unknown rule "no-such-rule"`},
+		{"unknown rule in exemptLiterals", `{"exemptLiterals": {"no-such-rule": ["1"]}}`, `unknown rule "no-such-rule" in exemptLiterals`},
+		{"invalid severity", `{"rules": {"fixed-repeat": "fatal"}}`, `invalid severity "fatal"`},
+		{"invalid pattern", `{"allowedConstPatterns": ["("]}`, `invalid allowed pattern`},
+		{"unknown field", `{"rulez": {}}`, `unknown field "rulez"`},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DefaultRuleSet().LoadConfig(writeConfig(t, tc.body))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("LoadConfig() error = %v, want it to mention %s", err, tc.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		if err := DefaultRuleSet().LoadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("LoadConfig() of a missing file succeeded")
+		}
+	})
+}
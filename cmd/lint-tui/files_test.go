@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// TestExpandArgs verifies directory arguments are walked recursively for .go files, hidden
+// directories are skipped and file arguments are passed through
+func TestExpandArgs(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"view.go",
+		"view_test.go",
+		"notes.md",
+		"sub/deep/table.go",
+		".git/hooks/pre-commit.go",
+		"sub/.cache/gen.go",
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("package ui\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	single := filepath.Join(root, "notes.md")
+
+	files, err := expandArgs([]string{root, single})
+	if err != nil {
+		t.Fatalf("expandArgs() error = %v", err)
+	}
+	// Test files are filtered later, alongside -exclude
+	want := []string{
+		filepath.Join(root, "sub/deep/table.go"),
+		filepath.Join(root, "view.go"),
+		filepath.Join(root, "view_test.go"),
+		single,
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("expandArgs() = %v, want %v", files, want)
+	}
+
+	// A hidden directory named explicitly is still walked
+	hidden := filepath.Join(root, ".git")
+	files, err = expandArgs([]string{hidden})
+	if err != nil {
+		t.Fatalf("expandArgs(%s) error = %v", hidden, err)
+	}
+	if want := []string{filepath.Join(hidden, "hooks/pre-commit.go")}; !slices.Equal(files, want) {
+		t.Errorf("expandArgs(%s) = %v, want %v", hidden, files, want)
+	}
+
+	if _, err := expandArgs([]string{filepath.Join(root, "missing")}); err == nil {
+		t.Error("expandArgs() of a missing path succeeded")
+	}
+}
+
+// TestIsExcluded verifies -exclude globs match the full path or the base name
+func TestIsExcluded(t *testing.T) {
+	globs := []string{"internal/ui/generated_*.go", "*_gen.go"}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"internal/ui/generated_styles.go", true},
+		{"internal/ui/deep/table_gen.go", true},
+		{"internal/ui/deep/generated_styles.go", false},
+		{"internal/ui/view.go", false},
+	}
+	for _, tt := range tests {
+		if got := isExcluded(tt.path, globs); got != tt.want {
+			t.Errorf("isExcluded(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestHasIgnoreBuildTag verifies only ignore constraints before the package clause count
+func TestHasIgnoreBuildTag(t *testing.T) {
+	tests := []struct {
+		name, src string
+		want      bool
+	}{
+		{"go:build ignore", "//go:build ignore\n\npackage main\n", true},
+		{"legacy +build ignore", "// +build ignore\n\npackage main\n", true},
+		{"other constraint", "//go:build linux\n\npackage ui\n", false},
+		{"after package clause", "package ui\n\n//go:build ignore\n", false},
+		{"no constraint", "package ui\n", false},
+	}
+	dir := t.TempDir()
+	for i, tt := range tests {
+		path := filepath.Join(dir, string(rune('a'+i))+".go")
+		if err := os.WriteFile(path, []byte(tt.src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := hasIgnoreBuildTag(path); got != tt.want {
+			t.Errorf("%s: hasIgnoreBuildTag() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if hasIgnoreBuildTag(filepath.Join(dir, "missing.go")) {
+		t.Error("hasIgnoreBuildTag() of a missing file = true")
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// fixedRepeatReplacement is the width expression substituted for fixed-repeat literals
+const fixedRepeatReplacement = "m.layout.InnerWidth"
+
+// methodOnMPattern matches a method declaration whose receiver is named m
+var methodOnMPattern = regexp.MustCompile(`^func\s+\(m\s+\*?\w+\)`)
+
+// Fix is a single line rewritten by -fix
+type Fix struct {
+	File   string
+	Line   int
+	Before string
+	After  string
+}
+
+// fixFile rewrites fixed-repeat dividers in place and returns the changed lines
+// Only unambiguous matches are fixed: one strings.Repeat per line, outside comments
+// and allowed exceptions, inside a method with an m receiver in a file that already uses m.layout.
+func fixFile(path string, rs *RuleSet) ([]Fix, error) {
+	if isTestFile(path) || rs.Severity[RuleFixedRepeat] == SeverityOff {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	if !strings.Contains(content, "m.layout.") {
+		return nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var fixes []Fix
+	inMethodOnM := false
+	for lineNum, line := range lines {
+		if strings.HasPrefix(line, "func ") {
+			inMethodOnM = methodOnMPattern.MatchString(line)
+		}
+		if !inMethodOnM || isComment(line) {
+			continue
+		}
+
+		matches := fixedRepeatPattern.FindAllStringSubmatchIndex(line, -1)
+		if len(matches) != 1 {
+			continue
+		}
+		match := matches[0]
+		if isInComment(line, match[0]) || rs.isAllowedException(line, lines, lineNum) {
+			continue
+		}
+		if rs.severityFor(RuleFixedRepeat, line[match[2]:match[3]]) == "" {
+			continue
+		}
+
+		fixed := line[:match[2]] + fixedRepeatReplacement + line[match[3]:]
+		fixes = append(fixes, Fix{File: path, Line: lineNum + 1, Before: line, After: fixed})
+		lines[lineNum] = fixed
+	}
+
+	if len(fixes) == 0 {
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return fixes, nil
+}
+
+// printFixes writes a diff-style summary of applied fixes
+func printFixes(w io.Writer, fixes []Fix) {
+	files := make(map[string]bool)
+	for _, f := range fixes {
+		files[f.File] = true
+		fmt.Fprintf(w, "%s:%d\n", f.File, f.Line)
+		fmt.Fprintf(w, "- %s\n", strings.TrimSpace(f.Before))
+		fmt.Fprintf(w, "+ %s\n", strings.TrimSpace(f.After))
+	}
+	fmt.Fprintf(w, "Fixed %d fixed-repeat violation(s) in %d file(s)\n", len(fixes), len(files))
+}
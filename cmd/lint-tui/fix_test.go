@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFixFile verifies -fix rewrites only unambiguous fixed-repeat dividers and leaves files
+// with nothing to fix byte-for-byte untouched
+func TestFixFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string // fixture file name
+		src       string
+		configure func(rs *RuleSet)
+		want      string // expected content after the fix ("" = unchanged)
+		wantLines []int
+	}{
+		{
+			name: "divider in method on m",
+			file: "view.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	w := m.layout.InnerWidth
+	return strings.Repeat("─", 80) + fmt.Sprint(w)
+}
+`,
+			want: `package ui
+
+func (m *Model) View() string {
+	w := m.layout.InnerWidth
+	return strings.Repeat("─", m.layout.InnerWidth) + fmt.Sprint(w)
+}
+`,
+			wantLines: []int{5},
+		},
+		{
+			name: "file without m.layout",
+			file: "nolayout.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	return strings.Repeat("─", 80)
+}
+`,
+		},
+		{
+			name: "function without m receiver",
+			file: "helper.go",
+			src: `package ui
+
+var _ = m.layout.InnerWidth
+
+func divider() string {
+	return strings.Repeat("─", 80)
+}
+`,
+		},
+		{
+			name: "comments and two repeats on a line",
+			file: "ambiguous.go",
+			src: `package ui
+
+func (m Model) View() string {
+	_ = m.layout.InnerWidth
+	// strings.Repeat("─", 80)
+	return strings.Repeat("─", 40) + strings.Repeat("=", 40)
+}
+`,
+		},
+		{
+			name: "allowed safeguard",
+			file: "safeguard.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	_ = m.layout.InnerWidth
+	if w < 40 { return strings.Repeat("─", 40) }
+	return ""
+}
+`,
+		},
+		{
+			name: "rule off",
+			file: "off.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	_ = m.layout.InnerWidth
+	return strings.Repeat("─", 80)
+}
+`,
+			configure: func(rs *RuleSet) { rs.Severity[RuleFixedRepeat] = SeverityOff },
+		},
+		{
+			name: "exempt literal",
+			file: "exempt.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	_ = m.layout.InnerWidth
+	return strings.Repeat("─", 3)
+}
+`,
+			configure: func(rs *RuleSet) { rs.ExemptLiterals[RuleFixedRepeat] = map[string]bool{"3": true} },
+		},
+		{
+			name: "test file",
+			file: "view_test.go",
+			src: `package ui
+
+func (m *Model) View() string {
+	_ = m.layout.InnerWidth
+	return strings.Repeat("─", 80)
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.src), 0600); err != nil {
+				t.Fatal(err)
+			}
+			rs := DefaultRuleSet()
+			if tt.configure != nil {
+				tt.configure(rs)
+			}
+
+			fixes, err := fixFile(path, rs)
+			if err != nil {
+				t.Fatalf("fixFile() error = %v", err)
+			}
+
+			want := tt.want
+			if want == "" {
+				want = tt.src
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want {
+				t.Errorf("rewritten file =\n%s\nwant\n%s", got, want)
+			}
+
+			if len(fixes) != len(tt.wantLines) {
+				t.Fatalf("got %d fixes, want %d: %+v", len(fixes), len(tt.wantLines), fixes)
+			}
+			for i, fix := range fixes {
+				if fix.File != path || fix.Line != tt.wantLines[i] {
+					t.Errorf("fix %d at %s:%d, want %s:%d", i, fix.File, fix.Line, path, tt.wantLines[i])
+				}
+				if !strings.Contains(fix.After, fixedRepeatReplacement) {
+					t.Errorf("fix %d After = %q, want it to use %s", i, fix.After, fixedRepeatReplacement)
+				}
+			}
+
+			// Rewrites keep the file's permissions
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0600 {
+				t.Errorf("file mode after fix = %v, want 0600", perm)
+			}
+		})
+	}
+}
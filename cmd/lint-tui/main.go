@@ -5,8 +5,10 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -21,13 +23,13 @@ const (
 
 // Violation represents a single style violation
 type Violation struct {
-	File     string
-	Line     int
-	Column   int
-	Severity string
-	Rule     string
-	Message  string
-	Content  string
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Content  string `json:"-"`
 }
 
 // LintResult holds the results of linting
@@ -38,6 +40,16 @@ type LintResult struct {
 	WarnCount  int
 }
 
+// jsonReport is the -format json output
+type jsonReport struct {
+	Violations []Violation `json:"violations"`
+	Summary    struct {
+		Files    int `json:"files"`
+		Errors   int `json:"errors"`
+		Warnings int `json:"warnings"`
+	} `json:"summary"`
+}
+
 // Pattern definitions for violations
 var (
 	// Rule 1: Hardcoded width/height numbers
@@ -51,7 +63,7 @@ var (
 
 	// Rule 3: Hardcoded fmt.Sprintf width specifiers
 	// Matches %-50s, %50s, %-30d, %10d, etc.
-	hardcodedSprintfPattern = regexp.MustCompile(`%-?\d+[sdvfgx]`)
+	hardcodedSprintfPattern = regexp.MustCompile(`%-?(\d+)[sdvfgx]`)
 
 	// Rule 4: Fixed text input widths
 	// Matches ti.Width = 40, textInput.Width = 50, etc.
@@ -72,6 +84,13 @@ var (
 	paddingConstPattern = regexp.MustCompile(`Padding\s*=\s*\d+`)
 	// Column separators constant
 	colSepPattern = regexp.MustCompile(`ColSeparators\s*=\s*\d+`)
+
+	// Rule 5: Tables fabricated with lipgloss instead of bubbles/table
+	// Matches lipgloss.JoinVertical( / lipgloss.JoinHorizontal( and lipgloss.NewStyle()...Border(
+	lipglossJoinPattern   = regexp.MustCompile(`lipgloss\.Join(Vertical|Horizontal)\(`)
+	lipglossBorderPattern = regexp.MustCompile(`lipgloss\.NewStyle\(\).*\.Border\(`)
+	// Manual row strings - identifiers like row, rows, cells, header
+	manualRowPattern = regexp.MustCompile(`(?i)\b(rows?|cells?|headers?)\b`)
 )
 
 // isComment checks if a line is a comment
@@ -96,35 +115,12 @@ func isInComment(line string, matchStart int) bool {
 }
 
 // isAllowedException checks if a match is an allowed exception
-func isAllowedException(line string, fullContent []string, lineNum int) bool {
-	// Check if it's a constant definition
-	if constPattern.MatchString(line) {
-		return true
-	}
-
-	// Check for column width constants
-	if colWidthConstPattern.MatchString(line) {
-		return true
-	}
-
-	// Check for min/max/default constants
-	if minMaxConstPattern.MatchString(line) {
-		return true
-	}
-
-	// Check for table height constants
-	if tableHeightConstPattern.MatchString(line) {
-		return true
-	}
-
-	// Check for padding constants
-	if paddingConstPattern.MatchString(line) {
-		return true
-	}
-
-	// Check for column separators constants
-	if colSepPattern.MatchString(line) {
-		return true
+func (rs *RuleSet) isAllowedException(line string, fullContent []string, lineNum int) bool {
+	// Check for constant definitions (const/var, column widths, min/max/default, table height, padding, ...)
+	for _, re := range rs.AllowedPattern {
+		if re.MatchString(line) {
+			return true
+		}
 	}
 
 	// Check for minimum width safeguards (look at surrounding context)
@@ -160,7 +156,7 @@ func isAllowedException(line string, fullContent []string, lineNum int) bool {
 }
 
 // lintFile lints a single Go file and returns violations
-func lintFile(filepath string) ([]Violation, error) {
+func lintFile(filepath string, rs *RuleSet) ([]Violation, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
 		return nil, err
@@ -189,15 +185,19 @@ func lintFile(filepath string) ([]Violation, error) {
 		// Check for hardcoded Width() calls
 		if matches := hardcodedWidthPattern.FindAllStringSubmatchIndex(line, -1); matches != nil {
 			for _, match := range matches {
-				if !isInComment(line, match[0]) && !isAllowedException(line, lines, lineNum) {
+				if !isInComment(line, match[0]) && !rs.isAllowedException(line, lines, lineNum) {
 					// Extract the number
 					numStr := line[match[2]:match[3]]
+					severity := rs.severityFor(RuleHardcodedWidth, numStr)
+					if severity == "" {
+						continue
+					}
 					violations = append(violations, Violation{
 						File:     filepath,
 						Line:     lineNum + 1,
 						Column:   match[0] + 1,
-						Severity: SeverityError,
-						Rule:     "hardcoded-width",
+						Severity: severity,
+						Rule:     RuleHardcodedWidth,
 						Message:  fmt.Sprintf("Hardcoded width value '%s'. Use m.layout.InnerWidth or m.layout.TableWidth instead.", numStr),
 						Content:  strings.TrimSpace(line),
 					})
@@ -208,17 +208,16 @@ func lintFile(filepath string) ([]Violation, error) {
 		// Check for hardcoded Height() calls (with more lenient checking for minimum safeguards)
 		if matches := hardcodedHeightPattern.FindAllStringSubmatchIndex(line, -1); matches != nil {
 			for _, match := range matches {
-				if !isInComment(line, match[0]) && !isAllowedException(line, lines, lineNum) {
-					// Check if this looks like a minimum safeguard
+				if !isInComment(line, match[0]) && !rs.isAllowedException(line, lines, lineNum) {
+					// Small numbers (like 10) that are typically minimum safeguards are exempt by default
 					numStr := line[match[2]:match[3]]
-					// Allow small numbers (like 10) that are typically minimum safeguards
-					if numStr != "10" && numStr != "5" {
+					if severity := rs.severityFor(RuleHardcodedHeight, numStr); severity != "" {
 						violations = append(violations, Violation{
 							File:     filepath,
 							Line:     lineNum + 1,
 							Column:   match[0] + 1,
-							Severity: SeverityError,
-							Rule:     "hardcoded-height",
+							Severity: severity,
+							Rule:     RuleHardcodedHeight,
 							Message:  fmt.Sprintf("Hardcoded height value '%s'. Use m.layout.TableHeight or calculated availableHeight instead.", numStr),
 							Content:  strings.TrimSpace(line),
 						})
@@ -230,14 +229,18 @@ func lintFile(filepath string) ([]Violation, error) {
 		// Check for fixed-length strings.Repeat
 		if matches := fixedRepeatPattern.FindAllStringSubmatchIndex(line, -1); matches != nil {
 			for _, match := range matches {
-				if !isInComment(line, match[0]) && !isAllowedException(line, lines, lineNum) {
+				if !isInComment(line, match[0]) && !rs.isAllowedException(line, lines, lineNum) {
 					numStr := line[match[2]:match[3]]
+					severity := rs.severityFor(RuleFixedRepeat, numStr)
+					if severity == "" {
+						continue
+					}
 					violations = append(violations, Violation{
 						File:     filepath,
 						Line:     lineNum + 1,
 						Column:   match[0] + 1,
-						Severity: SeverityError,
-						Rule:     "fixed-repeat",
+						Severity: severity,
+						Rule:     RuleFixedRepeat,
 						Message:  fmt.Sprintf("Fixed-length strings.Repeat with '%s'. Use m.layout.InnerWidth for divider width.", numStr),
 						Content:  strings.TrimSpace(line),
 					})
@@ -245,19 +248,23 @@ func lintFile(filepath string) ([]Violation, error) {
 			}
 		}
 
-		// Check for hardcoded fmt.Sprintf width specifiers (warning only - some are legitimate)
-		if matches := hardcodedSprintfPattern.FindAllStringIndex(line, -1); matches != nil {
+		// Check for hardcoded fmt.Sprintf width specifiers (warning by default - some are legitimate)
+		if matches := hardcodedSprintfPattern.FindAllStringSubmatchIndex(line, -1); matches != nil {
 			for _, match := range matches {
 				if !isInComment(line, match[0]) {
 					// Only warn if it looks like it's for display formatting, not parsing
 					if strings.Contains(line, "fmt.Sprintf") || strings.Contains(line, "Printf") {
 						matchStr := line[match[0]:match[1]]
+						severity := rs.severityFor(RuleSprintfWidth, line[match[2]:match[3]])
+						if severity == "" {
+							continue
+						}
 						violations = append(violations, Violation{
 							File:     filepath,
 							Line:     lineNum + 1,
 							Column:   match[0] + 1,
-							Severity: SeverityWarning,
-							Rule:     "hardcoded-sprintf-width",
+							Severity: severity,
+							Rule:     RuleSprintfWidth,
 							Message:  fmt.Sprintf("Hardcoded width specifier '%s' in format string. Consider using dynamic width if for display.", matchStr),
 							Content:  strings.TrimSpace(line),
 						})
@@ -269,16 +276,20 @@ func lintFile(filepath string) ([]Violation, error) {
 		// Check for fixed text input widths (like ti.Width = 40)
 		if matches := fixedTextInputPattern.FindAllStringSubmatchIndex(line, -1); matches != nil {
 			for _, match := range matches {
-				if !isInComment(line, match[0]) && !isAllowedException(line, lines, lineNum) {
+				if !isInComment(line, match[0]) && !rs.isAllowedException(line, lines, lineNum) {
 					// Make sure it's not a table column width (which is calculated)
 					if !strings.Contains(line, "columns") && !strings.Contains(line, "Column") {
 						numStr := line[match[2]:match[3]]
+						severity := rs.severityFor(RuleFixedInputWidth, numStr)
+						if severity == "" {
+							continue
+						}
 						violations = append(violations, Violation{
 							File:     filepath,
 							Line:     lineNum + 1,
 							Column:   match[0] + 1,
-							Severity: SeverityError,
-							Rule:     "fixed-input-width",
+							Severity: severity,
+							Rule:     RuleFixedInputWidth,
 							Message:  fmt.Sprintf("Fixed text input width '%s'. Text inputs must resize dynamically on tea.WindowSizeMsg.", numStr),
 							Content:  strings.TrimSpace(line),
 						})
@@ -288,15 +299,81 @@ func lintFile(filepath string) ([]Violation, error) {
 		}
 	}
 
+	violations = append(violations, lintLipglossTables(filepath, lines, rs)...)
+
 	return violations, nil
 }
 
+// lintLipglossTables flags table structure fabricated with lipgloss instead of bubbles/table.
+// Join and Border calls only count inside functions that also build manual row strings;
+// styling existing bubbles output (lines that call .View()) is allowed.
+func lintLipglossTables(filepath string, lines []string, rs *RuleSet) []Violation {
+	severity := rs.severityFor(RuleLipglossTable, "")
+	if severity == "" {
+		return nil
+	}
+
+	var violations []Violation
+	funcHasRows := false
+	for lineNum, line := range lines {
+		if strings.HasPrefix(line, "func ") {
+			funcHasRows = false
+			for i := lineNum + 1; i < len(lines) && !strings.HasPrefix(lines[i], "func "); i++ {
+				if !isComment(lines[i]) && manualRowPattern.MatchString(lines[i]) {
+					funcHasRows = true
+					break
+				}
+			}
+		}
+		if isComment(line) || strings.Contains(line, ".View()") {
+			continue
+		}
+
+		var match []int
+		var construct string
+		if m := lipglossJoinPattern.FindStringSubmatchIndex(line); m != nil && funcHasRows {
+			match, construct = m, "lipgloss.Join"+line[m[2]:m[3]]
+		} else if m := lipglossBorderPattern.FindStringIndex(line); m != nil && funcHasRows {
+			match, construct = m, "lipgloss.NewStyle().Border()"
+		}
+		if match == nil || isInComment(line, match[0]) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			File:     filepath,
+			Line:     lineNum + 1,
+			Column:   match[0] + 1,
+			Severity: severity,
+			Rule:     RuleLipglossTable,
+			Message:  fmt.Sprintf("Table structure built with %s and manual rows. Use bubbles/table and only colorize its output (see docs/LIPGLOSS_FORBIDDEN_PATTERNS.md).", construct),
+			Content:  strings.TrimSpace(line),
+		})
+	}
+	return violations
+}
+
 // formatViolation formats a violation for output
 func formatViolation(v Violation) string {
 	return fmt.Sprintf("%s:%d:%d: %s [%s] %s\n    %s",
 		v.File, v.Line, v.Column, v.Severity, v.Rule, v.Message, v.Content)
 }
 
+// printJSON writes the reported violations and a summary as JSON
+func printJSON(w io.Writer, result LintResult) error {
+	report := jsonReport{Violations: result.Violations}
+	if report.Violations == nil {
+		report.Violations = []Violation{}
+	}
+	report.Summary.Files = result.FileCount
+	report.Summary.Errors = result.ErrorCount
+	report.Summary.Warnings = result.WarnCount
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
 // printSummary prints a summary of the lint results
 func printSummary(result LintResult) {
 	fmt.Printf("\n%s\n", strings.Repeat("─", 60))
@@ -322,31 +399,57 @@ func main() {
 	verbose := flag.Bool("v", false, "Verbose output")
 	showWarnings := flag.Bool("w", true, "Show warnings (not just errors)")
 	help := flag.Bool("h", false, "Show help")
+	format := flag.String("format", "text", "Output format (text|json)")
+	var excludes globList
+	flag.Var(&excludes, "exclude", "Skip files matching this glob (path or base name; repeatable)")
+	fix := flag.Bool("fix", false, "Rewrite fixed-repeat dividers in place to use m.layout.InnerWidth")
+	configPath := flag.String("config", "", "JSON config with rule severities, allowed constant patterns and exempt literals (merged over defaults)")
 	flag.Parse()
 
 	if *help {
 		fmt.Println("TUI Style Linter for gitsome-ng")
 		fmt.Println()
-		fmt.Println("Usage: lint-tui [options] [files...]")
+		fmt.Println("Usage: lint-tui [options] [files or directories...]")
 		fmt.Println()
-		fmt.Println("If no files are specified, lints internal/ui/*.go")
+		fmt.Println("If no files are specified, lints internal/ui/*.go. Directories are walked recursively.")
 		fmt.Println()
 		fmt.Println("Options:")
 		flag.PrintDefaults()
 		fmt.Println()
 		fmt.Println("Rules checked:")
-		fmt.Println("  hardcoded-width     Hardcoded Width() values")
-		fmt.Println("  hardcoded-height    Hardcoded Height() values")
-		fmt.Println("  fixed-repeat        Fixed-length strings.Repeat dividers")
-		fmt.Println("  hardcoded-sprintf   Hardcoded width in format specifiers")
-		fmt.Println("  fixed-input-width   Fixed text input widths")
+		fmt.Println("  hardcoded-width          Hardcoded Width() values")
+		fmt.Println("  hardcoded-height         Hardcoded Height() values")
+		fmt.Println("  fixed-repeat             Fixed-length strings.Repeat dividers")
+		fmt.Println("  hardcoded-sprintf-width  Hardcoded width in format specifiers")
+		fmt.Println("  fixed-input-width        Fixed text input widths")
+		fmt.Println("  lipgloss-table           Tables built with lipgloss instead of bubbles/table")
 		os.Exit(0)
 	}
 
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format %q (want text or json)\n", *format)
+		os.Exit(1)
+	}
+	jsonOutput := *format == "json"
+
+	// Built-in rules, optionally tuned by a config file
+	rules := DefaultRuleSet()
+	if *configPath != "" {
+		if err := rules.LoadConfig(*configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Determine files to lint
 	var files []string
 	if flag.NArg() > 0 {
-		files = flag.Args()
+		var err error
+		files, err = expandArgs(flag.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error finding files: %v\n", err)
+			os.Exit(1)
+		}
 	} else {
 		// Default: lint internal/ui/*.go
 		pattern := filepath.Join("internal", "ui", "*.go")
@@ -363,10 +466,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Filter out test files
+	// Filter out test files, excluded paths and files built with "ignore"
 	var filesToLint []string
 	for _, f := range files {
-		if !isTestFile(f) {
+		if !isTestFile(f) && !isExcluded(f, excludes) && !hasIgnoreBuildTag(f) {
 			filesToLint = append(filesToLint, f)
 		}
 	}
@@ -375,13 +478,37 @@ func main() {
 		FileCount: len(filesToLint),
 	}
 
+	// Apply autofixes before linting so the report reflects the rewritten files
+	if *fix {
+		var fixes []Fix
+		for _, f := range filesToLint {
+			fileFixes, err := fixFile(f, rules)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fixing %s: %v\n", f, err)
+				continue
+			}
+			fixes = append(fixes, fileFixes...)
+		}
+		// Keep stdout valid JSON
+		if jsonOutput {
+			printFixes(os.Stderr, fixes)
+		} else {
+			printFixes(os.Stdout, fixes)
+		}
+	}
+
 	// Lint each file
 	for _, f := range filesToLint {
 		if *verbose {
-			fmt.Printf("Checking %s...\n", f)
+			if jsonOutput {
+				// Keep stdout valid JSON
+				fmt.Fprintf(os.Stderr, "Checking %s...\n", f)
+			} else {
+				fmt.Printf("Checking %s...\n", f)
+			}
 		}
 
-		violations, err := lintFile(f)
+		violations, err := lintFile(f, rules)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error linting %s: %v\n", f, err)
 			continue
@@ -390,17 +517,27 @@ func main() {
 		for _, v := range violations {
 			if v.Severity == SeverityError {
 				result.ErrorCount++
-				fmt.Println(formatViolation(v))
 			} else if *showWarnings && v.Severity == SeverityWarning {
 				result.WarnCount++
+			} else {
+				continue
+			}
+			result.Violations = append(result.Violations, v)
+			if !jsonOutput {
 				fmt.Println(formatViolation(v))
 			}
 		}
-		result.Violations = append(result.Violations, violations...)
 	}
 
 	// Print summary
-	printSummary(result)
+	if jsonOutput {
+		if err := printJSON(os.Stdout, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		printSummary(result)
+	}
 
 	// Exit with error code if violations found
 	if result.ErrorCount > 0 {
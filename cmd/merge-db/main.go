@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thesavant42/gitsome-ng/internal/db"
+)
+
+func main() {
+	intoPath := flag.String("into", "", "Project database to merge into (required)")
+	fromPath := flag.String("from", "", "Project database to merge from (required, left unchanged apart from schema upgrades)")
+	overwrite := flag.Bool("overwrite", false, "Replace conflicting rows with the -from copy instead of keeping the -into copy")
+	flag.Parse()
+
+	if *intoPath == "" || *fromPath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: merge-db -into <project.db> -from <other.db> [-overwrite]")
+		os.Exit(2)
+	}
+
+	// db.New creates missing files; merging into a typo should fail instead
+	if _, err := os.Stat(*intoPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+
+	database, err := db.New(*intoPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	report, err := database.MergeFrom(*fromPath, *overwrite)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Merge failed, %s is unchanged: %v\n", *intoPath, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Merged %s into %s\n\n", *fromPath, *intoPath)
+	fmt.Printf("%-20s %10s %10s\n", "Table", "Merged", "Skipped")
+	for _, t := range report.Tables {
+		fmt.Printf("%-20s %10d %10d\n", t.Table, t.Merged, t.Skipped)
+	}
+	if !*overwrite {
+		fmt.Println("\nSkipped rows already existed in the destination; use -overwrite to replace them.")
+	}
+}
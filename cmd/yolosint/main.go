@@ -1,15 +1,17 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/charmbracelet/log"
 	"github.com/joho/godotenv"
 	"github.com/thesavant42/gitsome-ng/internal/api"
 	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 	"github.com/thesavant42/gitsome-ng/internal/ui"
 )
@@ -19,9 +21,6 @@ const (
 )
 
 func main() {
-	// Show splash screen on startup
-	ui.ShowSplash()
-
 	// Load .env file if it exists (silently ignore if not found)
 	_ = godotenv.Load()
 
@@ -32,8 +31,48 @@ func main() {
 	tokenFlag := flag.String("token", "", "GitHub personal access token (optional)")
 	addRepoFlag := flag.String("add-repo", "", "Add a repository to tracking (owner/repo format)")
 	listReposFlag := flag.Bool("list-repos", false, "List all tracked repositories")
+	filesFlag := flag.Bool("files", false, "Also fetch each commit's changed file paths (one extra API request per commit)")
+	maxPagesFlag := flag.Int("max-pages", 0, "Max pages of repositories and gists fetched per user in user queries (0 = default of 10)")
+	noMouseFlag := flag.Bool("no-mouse", false, "Disable mouse support (for terminals that mishandle mouse reporting)")
+	themeFlag := flag.String("theme", "", "Color theme: "+strings.Join(ui.ThemeNames(), ", ")+" (default: GITSOME_THEME, else default)")
+	backgroundFlag := flag.String("background", ui.BackgroundAuto, "Terminal background for text colors: auto (ask the terminal, dark if unknown), dark or light")
+	messageSearchFlag := flag.String("message-search", "", "Turn full-text indexing of commit messages on or off for the project (on|off; indexing grows the database)")
+	saveDockerAuthFlag := flag.Bool("save-docker-auth", false, "Save DOCKER_USERNAME/DOCKER_PASSWORD to the project database for pulling private images")
+	proxyFlag := flag.String("proxy", "", "Proxy URL for all API requests, e.g. http://127.0.0.1:8080 or socks5://127.0.0.1:9050 (default: HTTP_PROXY/HTTPS_PROXY/ALL_PROXY)")
+	logOpts := logging.AddFlags(flag.CommandLine)
 	flag.Parse()
 
+	// The TUI owns the terminal, so logs only go to a file (-log-file or gitsome.log)
+	logOpts.Interactive = true
+	logFile, err := logging.Setup(*logOpts)
+	if err != nil {
+		ui.PrintError(err.Error())
+		os.Exit(1)
+	}
+	defer logFile.Close()
+
+	// Pick the palette before anything is drawn
+	theme := *themeFlag
+	if theme == "" {
+		theme = os.Getenv("GITSOME_THEME")
+	}
+	if err := ui.SetTheme(theme); err != nil {
+		ui.PrintError(fmt.Sprintf("Invalid theme: %v", err))
+		os.Exit(1)
+	}
+	if err := ui.SetBackground(*backgroundFlag); err != nil {
+		ui.PrintError(fmt.Sprintf("Invalid -background: %v", err))
+		os.Exit(1)
+	}
+
+	// Show splash screen on startup
+	ui.ShowSplash()
+
+	if err := api.SetProxy(*proxyFlag); err != nil {
+		ui.PrintError(fmt.Sprintf("Invalid -proxy: %v", err))
+		os.Exit(1)
+	}
+
 	// Also accept repo as positional argument
 	if *repoFlag == "" && flag.NArg() > 0 {
 		*repoFlag = flag.Arg(0)
@@ -45,6 +84,9 @@ func main() {
 		token = os.Getenv("GITHUB_TOKEN")
 	}
 
+	// GitHub Enterprise Server root, e.g. https://github.example.com (empty = github.com)
+	apiBaseURL := os.Getenv("GITHUB_API_URL")
+
 	// Determine database path
 	var selectedDBPath string
 
@@ -81,6 +123,7 @@ func main() {
 		os.Exit(1)
 	}
 	defer database.Close()
+	unlockSettings(database)
 
 	// Handle --list-repos flag
 	if *listReposFlag {
@@ -101,6 +144,35 @@ func main() {
 		return
 	}
 
+	// Handle --message-search flag
+	if *messageSearchFlag != "" {
+		if *messageSearchFlag != "on" && *messageSearchFlag != "off" {
+			ui.PrintError(fmt.Sprintf("Invalid -message-search %q (want on|off)", *messageSearchFlag))
+			os.Exit(1)
+		}
+		if err := database.SetCommitMessageSearch(*messageSearchFlag == "on"); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to set commit message search: %v", err))
+			os.Exit(1)
+		}
+		ui.PrintSuccess(fmt.Sprintf("Commit message search turned %s", *messageSearchFlag))
+		return
+	}
+
+	// Handle --save-docker-auth flag
+	if *saveDockerAuthFlag {
+		username, password := os.Getenv("DOCKER_USERNAME"), os.Getenv("DOCKER_PASSWORD")
+		if username == "" || password == "" {
+			ui.PrintError("DOCKER_USERNAME and DOCKER_PASSWORD must both be set")
+			os.Exit(1)
+		}
+		if err := database.SetDockerCredentials(username, password); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to save registry credentials: %v", err))
+			os.Exit(1)
+		}
+		ui.PrintSuccess(fmt.Sprintf("Saved registry credentials for %s", username))
+		return
+	}
+
 	// Handle --add-repo flag
 	if *addRepoFlag != "" {
 		owner, repo, err := api.ParseRepoString(*addRepoFlag)
@@ -117,7 +189,7 @@ func main() {
 		// Optionally fetch commits for the new repo
 		fmt.Println()
 		fmt.Println("Fetching commits for the new repository...")
-		fetchAndStoreCommits(tokenFlag, owner, repo, database)
+		fetchAndStoreCommits(tokenFlag, owner, repo, database, *filesFlag)
 		return
 	}
 
@@ -140,7 +212,7 @@ func main() {
 				fmt.Print("\033[H\033[2J")
 
 				// Launch multi-repo TUI
-				result, err := ui.RunMultiRepoTUI(trackedRepos, database, "Committers", token, selectedDBPath)
+				result, err := ui.RunMultiRepoTUI(trackedRepos, database, "Committers", token, apiBaseURL, selectedDBPath, !*noMouseFlag, *filesFlag, *maxPagesFlag)
 				if err != nil {
 					ui.PrintError(fmt.Sprintf("Interactive mode failed: %v", err))
 					os.Exit(1)
@@ -148,7 +220,7 @@ func main() {
 
 				// If user wants to launch Docker Hub search
 				if result.LaunchDockerSearch {
-					if err := ui.RunDockerHubSearch(log.Default(), database, result.DockerSearchQuery); err != nil {
+					if err := ui.RunDockerHubSearch(logging.Logger(), database, result.DockerSearchQuery); err != nil {
 						ui.PrintError(fmt.Sprintf("Docker Hub search failed: %v", err))
 					}
 					continue // Return to main TUI after search
@@ -156,7 +228,7 @@ func main() {
 
 				// If user wants to browse a specific Docker Hub repository
 				if result.LaunchBrowseDockerRepo {
-					if err := ui.RunBrowseDockerHubRepo(log.Default(), database); err != nil {
+					if err := ui.RunBrowseDockerHubRepo(logging.Logger(), database); err != nil {
 						ui.PrintError(fmt.Sprintf("Browse Docker Hub repo failed: %v", err))
 					}
 					continue // Return to main TUI after browsing
@@ -178,9 +250,17 @@ func main() {
 					continue // Return to main TUI after search
 				}
 
+				// If user wants to search cached build steps
+				if result.LaunchSearchBuildSteps {
+					if err := ui.RunSearchBuildSteps(database); err != nil {
+						ui.PrintError(fmt.Sprintf("Search build steps failed: %v", err))
+					}
+					continue // Return to main TUI after search
+				}
+
 				// If user wants to launch Wayback Machine browser
 				if result.LaunchWayback {
-					if err := ui.RunWaybackBrowser(log.Default(), database); err != nil {
+					if err := ui.RunWaybackBrowser(logging.Logger(), database); err != nil {
 						ui.PrintError(fmt.Sprintf("Wayback browser failed: %v", err))
 					}
 					continue // Return to main TUI after browsing
@@ -188,7 +268,7 @@ func main() {
 
 				// If user wants to browse cached Wayback CDX records
 				if result.LaunchWaybackCache {
-					if err := ui.RunWaybackCacheBrowser(log.Default(), database); err != nil {
+					if err := ui.RunWaybackCacheBrowser(logging.Logger(), database); err != nil {
 						ui.PrintError(fmt.Sprintf("Wayback cache browser failed: %v", err))
 					}
 					continue // Return to main TUI after browsing
@@ -196,7 +276,7 @@ func main() {
 
 				// If user wants to launch SubDomonster
 				if result.LaunchSubdomonster {
-					if err := ui.RunSubdomonster(log.Default(), database); err != nil {
+					if err := ui.RunSubdomonster(logging.Logger(), database); err != nil {
 						ui.PrintError(fmt.Sprintf("SubDomonster failed: %v", err))
 					}
 					continue // Return to main TUI after browsing
@@ -204,12 +284,36 @@ func main() {
 
 				// If user wants to browse cached subdomains
 				if result.LaunchSubdomonsterCache {
-					if err := ui.RunSubdomonsterCache(log.Default(), database); err != nil {
+					if err := ui.RunSubdomainBrowser(database); err != nil {
 						ui.PrintError(fmt.Sprintf("SubDomonster cache browser failed: %v", err))
 					}
 					continue // Return to main TUI after browsing
 				}
 
+				// If user wants to browse the most changed files of a repo
+				if result.LaunchFileHotspots {
+					if err := ui.RunFileHotspots(database, result.RepoOwner, result.RepoName); err != nil {
+						ui.PrintError(fmt.Sprintf("File hotspots failed: %v", err))
+					}
+					continue // Return to main TUI after browsing
+				}
+
+				// If user wants to search every subsystem at once
+				if result.LaunchGlobalSearch {
+					if err := ui.RunGlobalSearch(logging.Logger(), database); err != nil {
+						ui.PrintError(fmt.Sprintf("Global search failed: %v", err))
+					}
+					continue // Return to main TUI after search
+				}
+
+				// If user wants to search a repo's commit messages
+				if result.LaunchCommitSearch {
+					if err := ui.RunCommitMessageSearch(database, result.RepoOwner, result.RepoName); err != nil {
+						ui.PrintError(fmt.Sprintf("Commit message search failed: %v", err))
+					}
+					continue // Return to main TUI after search
+				}
+
 				// If user wants to switch projects, close current db and show selector
 				if result.SwitchProject {
 					database.Close()
@@ -239,6 +343,7 @@ func main() {
 						ui.PrintError(fmt.Sprintf("Failed to initialize database: %v", err))
 						os.Exit(1)
 					}
+					unlockSettings(database)
 					continue // Loop back to show the new project
 				}
 				return
@@ -293,11 +398,11 @@ func main() {
 				if !shouldUpdate {
 					usedCache = true
 				} else {
-					fetchAndStoreCommits(tokenFlag, owner, repo, database)
+					fetchAndStoreCommits(tokenFlag, owner, repo, database, *filesFlag)
 				}
 			} else {
 				// No cache - must fetch from API
-				fetchAndStoreCommits(tokenFlag, owner, repo, database)
+				fetchAndStoreCommits(tokenFlag, owner, repo, database, *filesFlag)
 			}
 		}
 
@@ -311,7 +416,7 @@ func main() {
 		}
 
 		// Launch interactive TUI
-		if err := ui.RunInteractiveTable(committerStats, owner, repo, database, "Committers", totalCommits, usedCache, token); err != nil {
+		if err := ui.RunInteractiveTable(committerStats, owner, repo, database, "Committers", totalCommits, usedCache, token, apiBaseURL, !*noMouseFlag, *filesFlag, *maxPagesFlag); err != nil {
 			ui.PrintError(fmt.Sprintf("Interactive mode failed: %v", err))
 			os.Exit(1)
 		}
@@ -321,8 +426,53 @@ func main() {
 	}
 }
 
+// sessionPassphrase remembers the settings passphrase entered this run so switching projects
+// doesn't prompt again
+var sessionPassphrase string
+
+// plaintextWarned is set once the plaintext API key warning has been shown
+var plaintextWarned bool
+
+// unlockSettings prompts for the settings passphrase when the project has encrypted API keys and
+// GITSOME_SECRET isn't set, and warns once per run when API keys are stored in plaintext
+func unlockSettings(database *db.DB) {
+	if database.HasPassphrase() {
+		return
+	}
+
+	encrypted, err := database.HasEncryptedSettings()
+	if err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+	if !encrypted {
+		if plain, err := database.HasPlaintextSecrets(); err == nil && plain && !plaintextWarned {
+			ui.PrintWarning(fmt.Sprintf("API keys in this project are stored in plaintext. Set %s to encrypt them.", db.SecretEnvVar))
+			plaintextWarned = true
+		}
+		return
+	}
+
+	if sessionPassphrase != "" && database.SetPassphrase(sessionPassphrase) == nil {
+		return
+	}
+	for {
+		passphrase, _ := ui.PromptForPassphrase()
+		if passphrase == "" {
+			ui.PrintWarning("Continuing without the passphrase: stored API keys are unavailable")
+			return
+		}
+		if err := database.SetPassphrase(passphrase); err != nil {
+			ui.PrintError(err.Error())
+			continue
+		}
+		sessionPassphrase = passphrase
+		return
+	}
+}
+
 // fetchAndStoreCommits fetches commits from GitHub API and stores them
-func fetchAndStoreCommits(tokenFlag *string, owner, repo string, database *db.DB) {
+func fetchAndStoreCommits(tokenFlag *string, owner, repo string, database *db.DB, fetchFiles bool) {
 	token := *tokenFlag
 	if token == "" {
 		token = os.Getenv("GITHUB_TOKEN")
@@ -337,11 +487,18 @@ func fetchAndStoreCommits(tokenFlag *string, owner, repo string, database *db.DB
 	// Check for existing commits to enable incremental fetch
 	latestSHA, _ := database.GetLatestCommitSHA(owner, repo)
 
-	client := api.NewClient(token)
+	client := api.NewClientWithBaseURL(token, os.Getenv("GITHUB_API_URL"))
+	client.SetWaitOnRateLimit(true) // Batch fetch - block through rate limits rather than abort
+	client.SetETagStore(database)
+	client.SetFetchFiles(fetchFiles)
 	fmt.Println()
 	commits, err := client.FetchCommits(owner, repo, latestSHA, ui.PrintProgress)
 	if err != nil {
 		fmt.Println()
+		if errors.Is(err, api.ErrUnauthorized) {
+			ui.PrintError("GitHub token invalid or expired - update GITHUB_TOKEN in .env or environment.")
+			os.Exit(1)
+		}
 		ui.PrintError(fmt.Sprintf("Failed to fetch commits: %v", err))
 		os.Exit(1)
 	}
@@ -352,6 +509,14 @@ func fetchAndStoreCommits(tokenFlag *string, owner, repo string, database *db.DB
 	} else {
 		ui.PrintSuccess(fmt.Sprintf("Fetched %d commits from %s/%s", len(commits), owner, repo))
 
+		// Name the accounts behind ID-only noreply emails GitHub didn't link
+		client.SetLoginCache(database)
+		if resolved, err := client.ResolveNoreplyUserIDs(commits); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Stopped resolving noreply emails: %v", err))
+		} else if resolved > 0 {
+			ui.PrintSuccess(fmt.Sprintf("Resolved %d logins from noreply emails", resolved))
+		}
+
 		// Ensure repo is tracked before storing commits
 		if err := database.AddTrackedRepo(owner, repo); err != nil {
 			ui.PrintError(fmt.Sprintf("Failed to add tracked repo: %v", err))
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 )
 
 const (
@@ -79,13 +80,29 @@ type DockerHubSearchResponse struct {
 	Query   string                  `json:"query"`
 }
 
-// NewDockerHubClient creates a new Docker Hub API client
+// TotalPages returns how many result pages the search has, at least 1
+func (r *DockerHubSearchResponse) TotalPages() int {
+	if r.Total <= dockerHubPageSize {
+		return 1
+	}
+	return (r.Total + dockerHubPageSize - 1) / dockerHubPageSize
+}
+
+// HasNextPage reports whether another page of results follows this one
+// A short page ends the results even if Total claims more, as Docker Hub stops serving
+// results past a fixed depth.
+func (r *DockerHubSearchResponse) HasNextPage() bool {
+	return len(r.Results) == dockerHubPageSize && r.Page < r.TotalPages()
+}
+
+// NewDockerHubClient creates a new Docker Hub API client; a nil logger uses the shared one
 func NewDockerHubClient(logger *log.Logger) *DockerHubClient {
+	if logger == nil {
+		logger = logging.WithPrefix("DockerHub")
+	}
 	return &DockerHubClient{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: logger,
+		httpClient: newHTTPClient(30 * time.Second),
+		logger:     logger,
 	}
 }
 
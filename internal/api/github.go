@@ -3,23 +3,24 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 const (
 	baseURL            = "https://api.github.com"
+	webURL             = "https://github.com"
 	perPage            = 100 // Max allowed by GitHub API
-	userAgent          = "charming-commits/1.0"
 	dockerHubUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"
 	dockerHubReferer   = "https://github.com/"
 )
@@ -29,57 +30,173 @@ type Client struct {
 	httpClient *http.Client
 	token      string // Optional: for authenticated requests (higher rate limits)
 	logger     *log.Logger
+	userAgent  string
+
+	// Endpoints - public github.com unless configured via SetBaseURL
+	restURL    string
+	graphQLURL string
+
+	// waitOnRateLimit makes FetchCommits sleep through rate limits instead of returning RateLimitError
+	waitOnRateLimit bool
+	// rateLimitReset is set when the last response reported zero remaining requests
+	rateLimitReset time.Time
+
+	// maxUserPages caps repo/gist pagination in FetchUserReposAndGists (0 = default)
+	maxUserPages int
+	// maxUserEvents caps how many public events FetchUserEvents returns (0 = default)
+	maxUserEvents int
+
+	// fetchFiles makes FetchCommits look up the files each commit changed (one request per commit)
+	fetchFiles bool
+
+	// etags enables conditional requests for incremental commit fetches (optional)
+	etags ETagStore
+	// logins caches account ID lookups for numeric noreply emails (optional)
+	logins LoginCache
+}
+
+// ETagStore persists ETags per API endpoint for conditional requests
+type ETagStore interface {
+	GetETag(endpoint string) (string, error)
+	SaveETag(endpoint, etag string) error
+}
+
+// errNotModified is returned by fetchCommitPageETag on a 304 Not Modified response
+var errNotModified = errors.New("not modified")
+
+// ErrUnauthorized is returned when GitHub rejects the token (HTTP 401)
+var ErrUnauthorized = errors.New("GitHub token invalid or expired")
+
+// RateLimitError is returned when GitHub rejects a request due to rate limiting
+// Reset is when requests are expected to be accepted again.
+type RateLimitError struct {
+	Reset     time.Time
+	Secondary bool // Secondary (abuse) limit signalled via Retry-After
+}
+
+func (e *RateLimitError) Error() string {
+	kind := "rate limit"
+	if e.Secondary {
+		kind = "secondary rate limit"
+	}
+	return fmt.Sprintf("GitHub %s exceeded, resets at %s (in %s)",
+		kind, e.Reset.Format("15:04:05"), time.Until(e.Reset).Round(time.Second))
+}
+
+// SetWaitOnRateLimit controls how FetchCommits handles rate limits
+// When enabled it blocks until the limit resets and retries; otherwise it returns a *RateLimitError.
+func (c *Client) SetWaitOnRateLimit(wait bool) {
+	c.waitOnRateLimit = wait
+}
+
+// SetFetchFiles makes FetchCommits also fetch each commit's changed files
+// This costs one extra request per commit, so it is off by default.
+func (c *Client) SetFetchFiles(fetch bool) {
+	c.fetchFiles = fetch
+}
+
+// SetETagStore enables conditional requests for incremental fetches
+// A 304 Not Modified does not count against the rate limit.
+func (c *Client) SetETagStore(store ETagStore) {
+	c.etags = store
 }
 
-// NewClient creates a new GitHub API client with a 30 second timeout
+// NewClient creates a new GitHub API client with a 30 second timeout, logging through the
+// shared logger
 func NewClient(token string) *Client {
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		token: token,
+		httpClient: newHTTPClient(30 * time.Second),
+		token:      token,
+		logger:     logging.WithPrefix("API"),
+		userAgent:  defaultUserAgent(),
+		restURL:    baseURL,
+		graphQLURL: graphQLURL,
 	}
 }
 
-// NewClientWithLogging creates a new GitHub API client with logging enabled
-func NewClientWithLogging(token string, dbPath string) *Client {
-	// Create logger that writes to file in same directory as database
-	logDir := filepath.Dir(dbPath)
-	logFile := filepath.Join(logDir, "api.log")
+// SetUserAgent sets the User-Agent sent with every request; empty restores the default
+func (c *Client) SetUserAgent(ua string) {
+	c.userAgent = resolveUserAgent(ua)
+}
 
-	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		// Fall back to client without file logging if we can't open the log file
-		return NewClient(token)
+// NewClientWithBaseURL creates a GitHub API client for a GitHub Enterprise Server instance
+// baseURL is the server root (e.g., https://github.example.com); an empty baseURL targets github.com
+func NewClientWithBaseURL(token, baseURL string) *Client {
+	c := NewClient(token)
+	c.SetBaseURL(baseURL)
+	return c
+}
+
+// SetBaseURL points the client at a GitHub Enterprise Server instance
+// REST requests go to {base}/api/v3 and GraphQL to {base}/api/graphql.
+// An empty value (or api.github.com itself) keeps the public github.com endpoints.
+func (c *Client) SetBaseURL(base string) {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	base = strings.TrimSuffix(base, "/api/v3")
+
+	if base == "" || base == baseURL {
+		c.restURL = baseURL
+		c.graphQLURL = graphQLURL
+		return
 	}
 
-	logger := log.NewWithOptions(f, log.Options{
-		ReportTimestamp: true,
-		TimeFormat:      time.RFC3339,
-		Prefix:          "API",
-	})
+	c.restURL = base + "/api/v3"
+	c.graphQLURL = base + "/api/graphql"
+}
 
-	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		token:  token,
-		logger: logger,
+// webBaseURL returns the web root of the server the client talks to
+func (c *Client) webBaseURL() string {
+	return WebBaseURL(c.restURL)
+}
+
+// WebBaseURL returns the web root that repo and profile links use for a base configured as
+// in SetBaseURL: https://github.com for an empty value (or api.github.com), otherwise the
+// GitHub Enterprise Server root
+func WebBaseURL(base string) string {
+	base = strings.TrimRight(strings.TrimSpace(base), "/")
+	base = strings.TrimSuffix(base, "/api/v3")
+	if base == "" || base == baseURL {
+		return webURL
 	}
+	return base
 }
 
 // FetchCommits fetches commits from a repository with pagination
 // If sinceSHA is provided, only fetches commits newer than that SHA (incremental fetch)
+// With an ETag store, an incremental fetch of an unchanged repo returns no commits after a single 304.
 func (c *Client) FetchCommits(owner, repo string, sinceSHA string, onProgress func(fetched, page int)) ([]models.Commit, error) {
 	var allCommits []models.Commit
-	url := fmt.Sprintf("%s/repos/%s/%s/commits?per_page=%d", baseURL, owner, repo, perPage)
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits?per_page=%d", c.restURL, owner, repo, perPage)
+	url := endpoint
 	page := 1
 
+	// Only send If-None-Match when commits are already cached - a full fetch needs the data
+	var etag, firstETag string
+	if sinceSHA != "" && c.etags != nil {
+		etag, _ = c.etags.GetETag(endpoint)
+	}
+
 	for url != "" {
-		commits, nextURL, err := c.fetchCommitPage(url)
+		requestETag := ""
+		if page == 1 {
+			requestETag = etag
+		}
+		commits, nextURL, respETag, err := c.fetchCommitPageETag(url, requestETag)
+		if errors.Is(err, errNotModified) {
+			return nil, nil
+		}
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) && c.waitOnRateLimit {
+			// Block until the limit resets, then retry the same page
+			c.sleepUntil(rateErr.Reset)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
+		if page == 1 {
+			firstETag = respETag
+		}
 
 		// If doing incremental fetch, stop when we hit the known commit
 		if sinceSHA != "" {
@@ -87,6 +204,8 @@ func (c *Client) FetchCommits(owner, repo string, sinceSHA string, onProgress fu
 				if commit.SHA == sinceSHA {
 					// Found the last known commit, return only the new ones
 					allCommits = append(allCommits, commits[:i]...)
+					c.saveETag(endpoint, firstETag)
+					c.attachCommitFiles(owner, repo, allCommits)
 					return allCommits, nil
 				}
 			}
@@ -100,24 +219,173 @@ func (c *Client) FetchCommits(owner, repo string, sinceSHA string, onProgress fu
 
 		url = nextURL
 		page++
+
+		// Budget exhausted - wait it out before requesting the next page
+		if url != "" && c.waitOnRateLimit && !c.rateLimitReset.IsZero() {
+			c.sleepUntil(c.rateLimitReset)
+		}
 	}
 
+	c.saveETag(endpoint, firstETag)
+	c.attachCommitFiles(owner, repo, allCommits)
 	return allCommits, nil
 }
 
+// attachCommitFiles fills in the changed files of each commit when SetFetchFiles is enabled
+// Lookups stop at the first error other than a waited-out rate limit; the remaining commits
+// keep nil Files so previously stored paths are left alone.
+func (c *Client) attachCommitFiles(owner, repo string, commits []models.Commit) {
+	if !c.fetchFiles {
+		return
+	}
+	for i := 0; i < len(commits); i++ {
+		files, err := c.fetchCommitFiles(owner, repo, commits[i].SHA)
+		var rateErr *RateLimitError
+		if errors.As(err, &rateErr) && c.waitOnRateLimit {
+			c.sleepUntil(rateErr.Reset)
+			i--
+			continue
+		}
+		if err != nil {
+			if c.logger != nil {
+				c.logger.Warn("Stopped fetching commit files", "sha", commits[i].SHA, "error", err)
+			}
+			return
+		}
+		commits[i].Files = files
+
+		if c.waitOnRateLimit && !c.rateLimitReset.IsZero() {
+			c.sleepUntil(c.rateLimitReset)
+		}
+	}
+}
+
+// fetchCommitFiles fetches a single commit and returns the files it changed
+func (c *Client) fetchCommitFiles(owner, repo, sha string) ([]models.CommitFile, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/%s", c.restURL, owner, repo, sha)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("GET", "endpoint", url)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := c.checkRateLimit(resp); err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var commit models.Commit
+	if err := json.NewDecoder(resp.Body).Decode(&commit); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if commit.Files == nil {
+		commit.Files = []models.CommitFile{}
+	}
+	return commit.Files, nil
+}
+
+// saveETag records the first-page ETag once a fetch has completed successfully
+func (c *Client) saveETag(endpoint, etag string) {
+	if c.etags == nil || etag == "" {
+		return
+	}
+	if err := c.etags.SaveETag(endpoint, etag); err != nil && c.logger != nil {
+		c.logger.Warn("Failed to save ETag", "endpoint", endpoint, "error", err)
+	}
+}
+
+// sleepUntil blocks until t (plus a small margin for clock skew)
+func (c *Client) sleepUntil(t time.Time) {
+	wait := time.Until(t) + time.Second
+	if wait <= 0 {
+		return
+	}
+	if c.logger != nil {
+		c.logger.Warn("Rate limited, waiting", "until", t.Format(time.RFC3339), "wait", wait.Round(time.Second))
+	}
+	time.Sleep(wait)
+}
+
+// checkRateLimit inspects rate limit headers on a response
+// Records an exhausted budget on the client and returns a *RateLimitError when the
+// response itself was rejected for rate limiting (primary or secondary).
+func (c *Client) checkRateLimit(resp *http.Response) error {
+	c.rateLimitReset = time.Time{}
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	var reset time.Time
+	if secs, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(secs, 0)
+	}
+	if remaining == "0" && !reset.IsZero() {
+		c.rateLimitReset = reset
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	// Secondary rate limit: GitHub says how long to back off
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			return &RateLimitError{Reset: time.Now().Add(time.Duration(secs) * time.Second), Secondary: true}
+		}
+	}
+
+	// Primary rate limit: budget exhausted until the reset time
+	if remaining == "0" && !reset.IsZero() {
+		return &RateLimitError{Reset: reset}
+	}
+
+	return nil
+}
+
 // fetchCommitPage fetches a single page of commits and returns the next page URL
 func (c *Client) fetchCommitPage(url string) ([]models.Commit, string, error) {
+	commits, nextURL, _, err := c.fetchCommitPageETag(url, "")
+	return commits, nextURL, err
+}
+
+// fetchCommitPageETag fetches a page of commits, sending If-None-Match when etag is set
+// Returns the response ETag, or errNotModified when the server replies 304.
+func (c *Client) fetchCommitPageETag(url, etag string) ([]models.Commit, string, string, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		if c.logger != nil {
 			c.logger.Error("Failed to create request", "url", url, "error", err)
 		}
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	if c.token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.token)
@@ -132,7 +400,7 @@ func (c *Client) fetchCommitPage(url string) ([]models.Commit, string, error) {
 		if c.logger != nil {
 			c.logger.Error("Request failed", "url", url, "error", err)
 		}
-		return nil, "", fmt.Errorf("failed to fetch commits: %w", err)
+		return nil, "", "", fmt.Errorf("failed to fetch commits: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -143,23 +411,40 @@ func (c *Client) fetchCommitPage(url string) ([]models.Commit, string, error) {
 		c.logger.Debug("Rate limit", "remaining", remaining, "reset", reset, "status", resp.StatusCode)
 	}
 
+	if err := c.checkRateLimit(resp); err != nil {
+		if c.logger != nil {
+			c.logger.Error("Rate limited", "status", resp.StatusCode, "error", err)
+		}
+		return nil, "", "", err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		if c.logger != nil {
+			c.logger.Info("Not modified", "endpoint", url)
+		}
+		return nil, "", "", errNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		if c.logger != nil {
 			c.logger.Error("API error", "status", resp.StatusCode, "response", string(body))
 		}
-		return nil, "", fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, "", "", ErrUnauthorized
+		}
+		return nil, "", "", fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
 	}
 
 	var commits []models.Commit
 	if err := json.NewDecoder(resp.Body).Decode(&commits); err != nil {
-		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+		return nil, "", "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Parse Link header for pagination
 	nextURL := parseNextLink(resp.Header.Get("Link"))
 
-	return commits, nextURL, nil
+	return commits, nextURL, resp.Header.Get("ETag"), nil
 }
 
 // parseNextLink extracts the "next" URL from GitHub's Link header
@@ -212,7 +497,7 @@ func ParseCommitsFromJSON(data []byte) ([]models.Commit, error) {
 	return commits, nil
 }
 
-// GraphQL endpoint
+// GraphQL endpoint (public github.com)
 const graphQLURL = "https://api.github.com/graphql"
 
 // graphQLRequest represents a GraphQL request
@@ -221,64 +506,16 @@ type graphQLRequest struct {
 }
 
 // FetchUserReposAndGists fetches all repositories and gists for a GitHub user
+// Repositories and gists are paginated up to the client's max pages (see SetMaxUserPages);
+// if the cap is hit, partial results are returned with UserData.Truncated set.
 func (c *Client) FetchUserReposAndGists(login string) (*models.UserData, error) {
 	if c.token == "" {
 		return nil, fmt.Errorf("GitHub token required for GraphQL queries")
 	}
 
-	query := buildUserDataQuery(login)
-
-	reqBody := graphQLRequest{Query: query}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequest("POST", graphQLURL, strings.NewReader(string(bodyBytes)))
-	if err != nil {
-		if c.logger != nil {
-			c.logger.Error("Failed to create GraphQL request", "login", login, "error", err)
-		}
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Authorization", "Bearer "+c.token)
-	req.Header.Set("Content-Type", "application/json")
-
-	if c.logger != nil {
-		c.logger.Info("POST GraphQL", "endpoint", graphQLURL, "login", login)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		if c.logger != nil {
-			c.logger.Error("GraphQL request failed", "login", login, "error", err)
-		}
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Log rate limit info
-	if c.logger != nil {
-		remaining := resp.Header.Get("X-RateLimit-Remaining")
-		reset := resp.Header.Get("X-RateLimit-Reset")
-		c.logger.Debug("Rate limit", "remaining", remaining, "reset", reset, "status", resp.StatusCode, "login", login)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.postGraphQL(buildUserDataQuery(login), login)
 	if err != nil {
-		if c.logger != nil {
-			c.logger.Error("Failed to read response", "login", login, "error", err)
-		}
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		if c.logger != nil {
-			c.logger.Error("GraphQL API error", "status", resp.StatusCode, "login", login, "response", string(body))
-		}
-		return nil, fmt.Errorf("GraphQL error (status %d): %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	// Debug: Log raw response to inspect organizations data
@@ -308,6 +545,30 @@ func (c *Client) FetchUserReposAndGists(login string) (*models.UserData, error)
 		return nil, err
 	}
 
+	if err := c.fetchRemainingUserPages(login, body, userData); err != nil {
+		return nil, err
+	}
+
+	// GPG keys are only exposed via REST; a failure here shouldn't lose the rest of the data
+	gpgKeys, err := c.FetchUserGPGKeys(login)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("GPG key fetch failed", "login", login, "error", err)
+		}
+	} else {
+		userData.PublicKeys = append(userData.PublicKeys, gpgKeys...)
+	}
+
+	// Events are REST-only as well and equally optional
+	events, err := c.FetchUserEvents(login)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("Event fetch failed", "login", login, "error", err)
+		}
+	} else {
+		userData.Events = events
+	}
+
 	// Check for Docker Hub profile - always add entry, even if not found
 	if c.logger != nil {
 		c.logger.Info("Checking Docker Hub profile", "login", login)
@@ -350,40 +611,308 @@ func (c *Client) FetchUserReposAndGists(login string) (*models.UserData, error)
 	return userData, nil
 }
 
-// buildUserDataQuery constructs the GraphQL query for user data
-func buildUserDataQuery(login string) string {
-	return fmt.Sprintf(`
-query {
-  user(login: "%s") {
-    login
-    name
-    bio
-    company
-    location
-    email
-    websiteUrl
-    twitterUsername
-    pronouns
-    avatarUrl
-    followers { totalCount }
-    following { totalCount }
-    createdAt
-    socialAccounts(first: 10) {
-      nodes {
-        provider
-        displayName
-        url
-      }
-    }
-    organizations(first: 100) {
-      nodes {
-        login
-        name
-      }
-    }
-    repositories(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
-      totalCount
-      nodes {
+// FetchUserGPGKeys fetches a user's public GPG keys via the REST API
+func (c *Client) FetchUserGPGKeys(login string) ([]models.UserPublicKey, error) {
+	url := fmt.Sprintf("%s/users/%s/gpg_keys", c.restURL, login)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("GET", "endpoint", url)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch GPG keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var raw []struct {
+		KeyID     string `json:"key_id"`
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	keys := make([]models.UserPublicKey, 0, len(raw))
+	for _, k := range raw {
+		keys = append(keys, models.UserPublicKey{
+			GitHubLogin: login,
+			KeyType:     "gpg",
+			KeyID:       k.KeyID,
+			Fingerprint: k.KeyID,
+			CreatedAt:   k.CreatedAt,
+		})
+	}
+	return keys, nil
+}
+
+// SetMaxUserEvents caps how many public events are fetched per user
+// Values below 1 restore the default.
+func (c *Client) SetMaxUserEvents(n int) {
+	c.maxUserEvents = n
+}
+
+// FetchUserEvents fetches a user's recent public events via the REST API
+// Follows Link pagination until the event cap is reached.
+func (c *Client) FetchUserEvents(login string) ([]models.UserEvent, error) {
+	maxEvents := c.maxUserEvents
+	if maxEvents < 1 {
+		maxEvents = defaultMaxUserEvents
+	}
+	pageSize := perPage
+	if maxEvents < pageSize {
+		pageSize = maxEvents
+	}
+
+	var events []models.UserEvent
+	url := fmt.Sprintf("%s/users/%s/events/public?per_page=%d", c.restURL, login, pageSize)
+	for url != "" && len(events) < maxEvents {
+		page, next, err := c.fetchEventPage(url, login)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		url = next
+	}
+
+	if len(events) > maxEvents {
+		events = events[:maxEvents]
+	}
+	return events, nil
+}
+
+// fetchEventPage fetches a single page of user events
+func (c *Client) fetchEventPage(url, login string) ([]models.UserEvent, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("GET", "endpoint", url)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, "", ErrUnauthorized
+		}
+		return nil, "", fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var raw []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Repo struct {
+			Name string `json:"name"`
+		} `json:"repo"`
+		Payload struct {
+			PullRequest *struct {
+				HTMLURL string `json:"html_url"`
+			} `json:"pull_request"`
+			Issue *struct {
+				HTMLURL string `json:"html_url"`
+			} `json:"issue"`
+		} `json:"payload"`
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	events := make([]models.UserEvent, 0, len(raw))
+	for _, e := range raw {
+		eventURL := c.webBaseURL() + "/" + e.Repo.Name
+		if e.Payload.PullRequest != nil && e.Payload.PullRequest.HTMLURL != "" {
+			eventURL = e.Payload.PullRequest.HTMLURL
+		} else if e.Payload.Issue != nil && e.Payload.Issue.HTMLURL != "" {
+			eventURL = e.Payload.Issue.HTMLURL
+		}
+		events = append(events, models.UserEvent{
+			GitHubLogin: login,
+			EventID:     e.ID,
+			Type:        e.Type,
+			RepoName:    e.Repo.Name,
+			URL:         eventURL,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+
+	return events, parseNextLink(resp.Header.Get("Link")), nil
+}
+
+// SetMaxUserPages caps how many pages of repositories and gists are fetched per user
+// Values below 1 restore the default.
+func (c *Client) SetMaxUserPages(n int) {
+	c.maxUserPages = n
+}
+
+// fetchRemainingUserPages follows repository and gist cursors after the first page
+func (c *Client) fetchRemainingUserPages(login string, firstPage []byte, userData *models.UserData) error {
+	maxPages := c.maxUserPages
+	if maxPages < 1 {
+		maxPages = defaultMaxUserPages
+	}
+
+	var first graphQLResponse
+	if err := json.Unmarshal(firstPage, &first); err != nil || first.Data.User == nil {
+		return nil // Already validated by parseUserDataResponse
+	}
+
+	// Repositories
+	pageInfo := first.Data.User.Repositories.PageInfo
+	for page := 1; pageInfo.HasNextPage; page++ {
+		if page >= maxPages {
+			userData.Truncated = true
+			if c.logger != nil {
+				c.logger.Warn("Repository page cap reached", "login", login, "pages", maxPages, "fetched", len(userData.Repositories))
+			}
+			break
+		}
+		body, err := c.postGraphQL(buildUserReposPageQuery(login, pageInfo.EndCursor), login)
+		if err != nil {
+			return err
+		}
+		user, err := parseGraphQLUser(body, login)
+		if err != nil {
+			return err
+		}
+		userData.Repositories = append(userData.Repositories, convertRepos(user.Repositories.Nodes, login)...)
+		pageInfo = user.Repositories.PageInfo
+	}
+
+	// Gists
+	pageInfo = first.Data.User.Gists.PageInfo
+	for page := 1; pageInfo.HasNextPage; page++ {
+		if page >= maxPages {
+			userData.Truncated = true
+			if c.logger != nil {
+				c.logger.Warn("Gist page cap reached", "login", login, "pages", maxPages, "fetched", len(userData.Gists))
+			}
+			break
+		}
+		body, err := c.postGraphQL(buildUserGistsPageQuery(login, pageInfo.EndCursor), login)
+		if err != nil {
+			return err
+		}
+		user, err := parseGraphQLUser(body, login)
+		if err != nil {
+			return err
+		}
+		userData.Gists = append(userData.Gists, convertGists(user.Gists.Nodes, login)...)
+		pageInfo = user.Gists.PageInfo
+	}
+
+	return nil
+}
+
+// postGraphQL sends a GraphQL query and returns the raw response body
+func (c *Client) postGraphQL(query, login string) ([]byte, error) {
+	reqBody := graphQLRequest{Query: query}
+	bodyBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.graphQLURL, strings.NewReader(string(bodyBytes)))
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Error("Failed to create GraphQL request", "login", login, "error", err)
+		}
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.logger != nil {
+		c.logger.Info("POST GraphQL", "endpoint", c.graphQLURL, "login", login)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Error("GraphQL request failed", "login", login, "error", err)
+		}
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Log rate limit info
+	if c.logger != nil {
+		remaining := resp.Header.Get("X-RateLimit-Remaining")
+		reset := resp.Header.Get("X-RateLimit-Reset")
+		c.logger.Debug("Rate limit", "remaining", remaining, "reset", reset, "status", resp.StatusCode, "login", login)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Error("Failed to read response", "login", login, "error", err)
+		}
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if c.logger != nil {
+			c.logger.Error("GraphQL API error", "status", resp.StatusCode, "login", login, "response", string(body))
+		}
+		if resp.StatusCode == http.StatusUnauthorized {
+			return nil, ErrUnauthorized
+		}
+		return nil, fmt.Errorf("GraphQL error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// userReposPageSize and userGistsPageSize are the GraphQL page sizes (100 is the API maximum)
+const (
+	userReposPageSize = 100
+	userGistsPageSize = 100
+)
+
+// defaultMaxUserPages caps repo/gist pagination per user (per connection)
+const defaultMaxUserPages = 10
+
+// defaultMaxUserEvents caps public events per user (GitHub serves at most 300)
+const defaultMaxUserEvents = 100
+
+// userRepoFields are the repository fields requested for each node
+const userRepoFields = `
         name
         owner { login }
         description
@@ -403,11 +932,10 @@ query {
         createdAt
         updatedAt
         pushedAt
-      }
-    }
-    gists(first: 100, orderBy: {field: CREATED_AT, direction: DESC}) {
-      totalCount
-      nodes {
+`
+
+// userGistFields are the gist fields requested for each node
+const userGistFields = `
         id
         name
         description
@@ -440,11 +968,88 @@ query {
             updatedAt
           }
         }
+`
+
+// buildUserDataQuery constructs the GraphQL query for user data
+// Returns the profile plus the first page of repositories and gists.
+func buildUserDataQuery(login string) string {
+	return fmt.Sprintf(`
+query {
+  user(login: "%s") {
+    login
+    name
+    bio
+    company
+    location
+    email
+    websiteUrl
+    twitterUsername
+    pronouns
+    avatarUrl
+    followers { totalCount }
+    following { totalCount }
+    createdAt
+    socialAccounts(first: 10) {
+      nodes {
+        provider
+        displayName
+        url
+      }
+    }
+    organizations(first: 100) {
+      nodes {
+        login
+        name
       }
     }
+    publicKeys(first: 100) {
+      nodes {
+        id
+        key
+        fingerprint
+        createdAt
+      }
+    }
+    repositories(first: %d, orderBy: {field: CREATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {%s}
+    }
+    gists(first: %d, orderBy: {field: CREATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {%s}
+    }
   }
+}`, login, userReposPageSize, userRepoFields, userGistsPageSize, userGistFields)
 }
-`, login)
+
+// buildUserReposPageQuery constructs the GraphQL query for a follow-up page of repositories
+func buildUserReposPageQuery(login, after string) string {
+	return fmt.Sprintf(`
+query {
+  user(login: "%s") {
+    repositories(first: %d, after: "%s", orderBy: {field: CREATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {%s}
+    }
+  }
+}`, login, userReposPageSize, after, userRepoFields)
+}
+
+// buildUserGistsPageQuery constructs the GraphQL query for a follow-up page of gists
+func buildUserGistsPageQuery(login, after string) string {
+	return fmt.Sprintf(`
+query {
+  user(login: "%s") {
+    gists(first: %d, after: "%s", orderBy: {field: CREATED_AT, direction: DESC}) {
+      totalCount
+      pageInfo { hasNextPage endCursor }
+      nodes {%s}
+    }
+  }
+}`, login, userGistsPageSize, after, userGistFields)
 }
 
 // graphQLResponse represents the structure of the GraphQL response
@@ -484,16 +1089,34 @@ type graphQLUser struct {
 			Name  string `json:"name"`
 		} `json:"nodes"`
 	} `json:"organizations"`
+	PublicKeys struct {
+		Nodes []graphQLPublicKey `json:"nodes"`
+	} `json:"publicKeys"`
 	Repositories struct {
-		TotalCount int           `json:"totalCount"`
-		Nodes      []graphQLRepo `json:"nodes"`
+		TotalCount int             `json:"totalCount"`
+		PageInfo   graphQLPageInfo `json:"pageInfo"`
+		Nodes      []graphQLRepo   `json:"nodes"`
 	} `json:"repositories"`
 	Gists struct {
-		TotalCount int           `json:"totalCount"`
-		Nodes      []graphQLGist `json:"nodes"`
+		TotalCount int             `json:"totalCount"`
+		PageInfo   graphQLPageInfo `json:"pageInfo"`
+		Nodes      []graphQLGist   `json:"nodes"`
 	} `json:"gists"`
 }
 
+// graphQLPageInfo is the cursor pagination state of a GraphQL connection
+type graphQLPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphQLPublicKey struct {
+	ID          string `json:"id"`
+	Key         string `json:"key"`
+	Fingerprint string `json:"fingerprint"`
+	CreatedAt   string `json:"createdAt"`
+}
+
 type graphQLSocialAccount struct {
 	Provider    string `json:"provider"`
 	DisplayName string `json:"displayName"`
@@ -585,21 +1208,11 @@ type graphQLGistComment struct {
 
 // parseUserDataResponse parses the GraphQL response into UserData
 func parseUserDataResponse(body []byte, login string) (*models.UserData, error) {
-	var response graphQLResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if len(response.Errors) > 0 {
-		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
-	}
-
-	if response.Data.User == nil {
-		return nil, fmt.Errorf("user not found: %s", login)
+	user, err := parseGraphQLUser(body, login)
+	if err != nil {
+		return nil, err
 	}
 
-	user := response.Data.User
-
 	// Build profile
 	profile := models.UserProfile{
 		Login:           user.Login,
@@ -637,8 +1250,49 @@ func parseUserDataResponse(body []byte, login string) (*models.UserData, error)
 		Profile: profile,
 	}
 
-	// Convert repositories
-	for _, repo := range user.Repositories.Nodes {
+	userData.Repositories = convertRepos(user.Repositories.Nodes, login)
+	userData.Gists = convertGists(user.Gists.Nodes, login)
+
+	// SSH keys - the algorithm is the first field of the public key
+	for _, k := range user.PublicKeys.Nodes {
+		keyType := "ssh"
+		if fields := strings.Fields(k.Key); len(fields) > 0 {
+			keyType = fields[0]
+		}
+		userData.PublicKeys = append(userData.PublicKeys, models.UserPublicKey{
+			GitHubLogin: login,
+			KeyType:     keyType,
+			KeyID:       k.ID,
+			Fingerprint: k.Fingerprint,
+			CreatedAt:   k.CreatedAt,
+		})
+	}
+
+	return userData, nil
+}
+
+// parseGraphQLUser decodes a GraphQL response and returns its user node
+func parseGraphQLUser(body []byte, login string) (*graphQLUser, error) {
+	var response graphQLResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	if response.Data.User == nil {
+		return nil, fmt.Errorf("user not found: %s", login)
+	}
+
+	return response.Data.User, nil
+}
+
+// convertRepos converts GraphQL repository nodes to models
+func convertRepos(nodes []graphQLRepo, login string) []models.UserRepository {
+	var repos []models.UserRepository
+	for _, repo := range nodes {
 		commitCount := 0
 		if repo.DefaultBranchRef != nil && repo.DefaultBranchRef.Target.History != nil {
 			commitCount = repo.DefaultBranchRef.Target.History.TotalCount
@@ -651,7 +1305,7 @@ func parseUserDataResponse(body []byte, login string) (*models.UserData, error)
 		if repo.LicenseInfo != nil {
 			licenseName = repo.LicenseInfo.Name
 		}
-		userData.Repositories = append(userData.Repositories, models.UserRepository{
+		repos = append(repos, models.UserRepository{
 			GitHubLogin:      login,
 			Name:             repo.Name,
 			OwnerLogin:       repo.Owner.Login,
@@ -675,9 +1329,13 @@ func parseUserDataResponse(body []byte, login string) (*models.UserData, error)
 			PushedAt:         repo.PushedAt,
 		})
 	}
+	return repos
+}
 
-	// Convert gists
-	for _, gist := range user.Gists.Nodes {
+// convertGists converts GraphQL gist nodes (with files and comments) to models
+func convertGists(nodes []graphQLGist, login string) []models.UserGist {
+	var gists []models.UserGist
+	for _, gist := range nodes {
 		userGist := models.UserGist{
 			ID:             gist.ID,
 			GitHubLogin:    login,
@@ -731,10 +1389,9 @@ func parseUserDataResponse(body []byte, login string) (*models.UserData, error)
 			})
 		}
 
-		userData.Gists = append(userData.Gists, userGist)
+		gists = append(gists, userGist)
 	}
-
-	return userData, nil
+	return gists
 }
 
 // CheckDockerHubProfile checks if a Docker Hub profile exists for the given username
@@ -756,7 +1413,8 @@ func CheckDockerHubProfile(username string) (bool, error) {
 	req.Header.Set("Referer", dockerHubReferer)
 
 	client := &http.Client{
-		Timeout: 10 * time.Second,
+		Timeout:   10 * time.Second,
+		Transport: newTransport(),
 		// Don't follow redirects - we only care about the initial response
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// TestFetchCommitPageUnauthorized verifies a 401 maps to ErrUnauthorized
+func TestFetchCommitPageUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("expired-token")
+	_, _, err := client.fetchCommitPage(server.URL)
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("fetchCommitPage() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+// TestFetchCommitPageOtherErrors verifies non-401 failures stay distinct from ErrUnauthorized
+func TestFetchCommitPageOtherErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	_, _, err := client.fetchCommitPage(server.URL)
+
+	if err == nil {
+		t.Fatal("fetchCommitPage() error = nil, want error for 404")
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("fetchCommitPage() error = %v, should not be ErrUnauthorized", err)
+	}
+}
+
+// memETagStore is an in-memory ETagStore for tests
+type memETagStore map[string]string
+
+func (s memETagStore) GetETag(endpoint string) (string, error) { return s[endpoint], nil }
+
+func (s memETagStore) SaveETag(endpoint, etag string) error {
+	s[endpoint] = etag
+	return nil
+}
+
+// TestFetchCommitsNotModified verifies a 304 short-circuits an incremental fetch
+func TestFetchCommitsNotModified(t *testing.T) {
+	const etag = `"abc123"`
+	var gotIfNoneMatch string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIfNoneMatch = r.Header.Get("If-None-Match")
+		if gotIfNoneMatch == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.restURL = server.URL
+	store := memETagStore{}
+	client.SetETagStore(store)
+
+	endpoint := server.URL + "/repos/owner/repo/commits?per_page=100"
+	store[endpoint] = etag
+
+	commits, err := client.FetchCommits("owner", "repo", "deadbeef", nil)
+	if err != nil {
+		t.Fatalf("FetchCommits() error = %v, want nil", err)
+	}
+	if len(commits) != 0 {
+		t.Errorf("FetchCommits() returned %d commits, want 0", len(commits))
+	}
+	if gotIfNoneMatch != etag {
+		t.Errorf("If-None-Match = %q, want %q", gotIfNoneMatch, etag)
+	}
+}
+
+func TestFetchCommitsWithFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/commits":
+			w.Write([]byte(`[{"sha":"aaa"},{"sha":"bbb"},{"sha":"ccc"}]`))
+		case "/repos/owner/repo/commits/aaa":
+			w.Write([]byte(`{"sha":"aaa","files":[{"filename":"main.go","status":"modified","additions":3,"deletions":1}]}`))
+		case "/repos/owner/repo/commits/bbb":
+			w.Write([]byte(`{"sha":"bbb","files":[]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	client.restURL = server.URL
+
+	commits, err := client.FetchCommits("owner", "repo", "", nil)
+	if err != nil {
+		t.Fatalf("FetchCommits() error = %v", err)
+	}
+	if len(commits) != 3 || commits[0].Files != nil {
+		t.Fatalf("without SetFetchFiles got %d commits, files %v; want 3 commits, no files", len(commits), commits[0].Files)
+	}
+
+	client.SetFetchFiles(true)
+	commits, err = client.FetchCommits("owner", "repo", "", nil)
+	if err != nil {
+		t.Fatalf("FetchCommits() error = %v, want commits kept when a file lookup fails", err)
+	}
+	if len(commits[0].Files) != 1 || commits[0].Files[0].Filename != "main.go" || commits[0].Files[0].Additions != 3 {
+		t.Errorf("commit aaa files = %+v, want main.go with 3 additions", commits[0].Files)
+	}
+	if commits[1].Files == nil || len(commits[1].Files) != 0 {
+		t.Errorf("commit bbb files = %#v, want empty non-nil slice", commits[1].Files)
+	}
+	if commits[2].Files != nil {
+		t.Errorf("commit ccc files = %+v, want nil after the lookup failed", commits[2].Files)
+	}
+}
+
+// graphQLPageServer serves repository pages; each page links to the next until lastPage
+func graphQLPageServer(t *testing.T, lastPage int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		page := 1
+		if i := strings.Index(req.Query, `after: "page`); i >= 0 {
+			fmt.Sscanf(req.Query[i+len(`after: "page`):], "%d", &page)
+			page++
+		}
+		fmt.Fprintf(w, `{"data":{"user":{"repositories":{"totalCount":%d,"pageInfo":{"hasNextPage":%t,"endCursor":"page%d"},"nodes":[{"name":"repo%d","owner":{"login":"octo"}}]}}}}`,
+			lastPage, page < lastPage, page, page)
+	}))
+}
+
+// TestFetchRemainingUserPages verifies repositories are followed across cursors
+func TestFetchRemainingUserPages(t *testing.T) {
+	server := graphQLPageServer(t, 3)
+	defer server.Close()
+
+	client := NewClient("token")
+	client.graphQLURL = server.URL
+
+	first := []byte(`{"data":{"user":{"repositories":{"pageInfo":{"hasNextPage":true,"endCursor":"page1"},"nodes":[{"name":"repo1"}]},"gists":{"pageInfo":{"hasNextPage":false}}}}}`)
+	userData := &models.UserData{Repositories: []models.UserRepository{{Name: "repo1"}}}
+
+	if err := client.fetchRemainingUserPages("octo", first, userData); err != nil {
+		t.Fatalf("fetchRemainingUserPages() error = %v", err)
+	}
+	if len(userData.Repositories) != 3 {
+		t.Errorf("got %d repositories, want 3", len(userData.Repositories))
+	}
+	if userData.Truncated {
+		t.Error("Truncated = true, want false")
+	}
+}
+
+// TestFetchRemainingUserPagesCap verifies the page cap stops pagination and flags partial results
+func TestFetchRemainingUserPagesCap(t *testing.T) {
+	server := graphQLPageServer(t, 5)
+	defer server.Close()
+
+	client := NewClient("token")
+	client.graphQLURL = server.URL
+	client.SetMaxUserPages(2)
+
+	first := []byte(`{"data":{"user":{"repositories":{"pageInfo":{"hasNextPage":true,"endCursor":"page1"},"nodes":[{"name":"repo1"}]},"gists":{"pageInfo":{"hasNextPage":false}}}}}`)
+	userData := &models.UserData{Repositories: []models.UserRepository{{Name: "repo1"}}}
+
+	if err := client.fetchRemainingUserPages("octo", first, userData); err != nil {
+		t.Fatalf("fetchRemainingUserPages() error = %v", err)
+	}
+	if len(userData.Repositories) != 2 {
+		t.Errorf("got %d repositories, want 2 (capped)", len(userData.Repositories))
+	}
+	if !userData.Truncated {
+		t.Error("Truncated = false, want true when cap is hit")
+	}
+}
+
+// TestFetchUserEvents verifies event pagination stops at the cap, PR URLs are preferred and
+// other events link to the repo on the client's server
+func TestFetchUserEvents(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=2&page=%d>; rel="next"`, server.URL, r.URL.Path, page+1))
+		fmt.Fprintf(w, `[{"id":"%d1","type":"PullRequestEvent","repo":{"name":"octo/a"},"payload":{"pull_request":{"html_url":"https://github.com/octo/a/pull/%d"}},"created_at":"2024-01-0%dT00:00:00Z"},`+
+			`{"id":"%d2","type":"PushEvent","repo":{"name":"octo/b"},"payload":{},"created_at":"2024-01-0%dT00:00:00Z"}]`,
+			page, page, page, page, page)
+	}))
+	defer server.Close()
+
+	client := NewClient("")
+	if got := client.webBaseURL(); got != "https://github.com" {
+		t.Errorf("webBaseURL() = %q, want https://github.com", got)
+	}
+	client.restURL = server.URL + "/api/v3"
+	client.SetMaxUserEvents(3)
+
+	events, err := client.FetchUserEvents("octo")
+	if err != nil {
+		t.Fatalf("FetchUserEvents() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3 (capped)", len(events))
+	}
+	if events[0].URL != "https://github.com/octo/a/pull/1" {
+		t.Errorf("events[0].URL = %q, want pull request URL", events[0].URL)
+	}
+	if events[1].URL != server.URL+"/octo/b" {
+		t.Errorf("events[1].URL = %q, want repo URL on the client's server", events[1].URL)
+	}
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+const (
+	ripeStatBaseURL = "https://stat.ripe.net"
+
+	// MaxExpandAddresses caps how many IPs one CIDR or ASN expansion reverse-resolves (an IPv4 /16)
+	MaxExpandAddresses = 1 << 16
+
+	// SourceReverseDNS marks subdomains seeded from PTR records of a netblock
+	SourceReverseDNS = "rdns"
+)
+
+// PrefixSource looks up the IP prefixes an autonomous system announces
+type PrefixSource interface {
+	AnnouncedPrefixes(ctx context.Context, asn string) ([]string, error)
+}
+
+// RIPEStatPrefixSource looks up announced prefixes via the RIPEstat data API
+type RIPEStatPrefixSource struct {
+	httpClient *http.Client
+	baseURL    string
+	userAgent  string
+}
+
+// NewRIPEStatPrefixSource creates a prefix source backed by stat.ripe.net
+func NewRIPEStatPrefixSource() *RIPEStatPrefixSource {
+	return &RIPEStatPrefixSource{
+		httpClient: newHTTPClient(subdomainTimeout),
+		baseURL:    ripeStatBaseURL,
+		userAgent:  defaultUserAgent(),
+	}
+}
+
+// ripeStatPrefixesResponse is the part of the announced-prefixes response we use
+type ripeStatPrefixesResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Prefixes []struct {
+			Prefix string `json:"prefix"`
+		} `json:"prefixes"`
+	} `json:"data"`
+}
+
+// AnnouncedPrefixes returns the prefixes asn currently announces
+func (s *RIPEStatPrefixSource) AnnouncedPrefixes(ctx context.Context, asn string) ([]string, error) {
+	reqURL := fmt.Sprintf("%s/data/announced-prefixes/data.json?resource=%s", s.baseURL, url.QueryEscape(asn))
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.Canceled {
+			return nil, fmt.Errorf("cancelled")
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RIPEstat returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var parsed ripeStatPrefixesResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if parsed.Status != "ok" {
+		return nil, fmt.Errorf("RIPEstat returned status %q", parsed.Status)
+	}
+
+	prefixes := make([]string, 0, len(parsed.Data.Prefixes))
+	for _, p := range parsed.Data.Prefixes {
+		prefixes = append(prefixes, p.Prefix)
+	}
+	return prefixes, nil
+}
+
+// SetPrefixSource replaces the ASN prefix lookup used by ReverseResolveASN
+func (c *SubdomainClient) SetPrefixSource(source PrefixSource) {
+	c.prefixSource = source
+}
+
+// NormalizeASN canonicalizes "13335", "as13335" or "AS13335" to "AS13335"
+func NormalizeASN(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	digits := strings.TrimPrefix(strings.TrimPrefix(trimmed, "AS"), "as")
+	n, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil || n == 0 {
+		return "", fmt.Errorf("invalid ASN %q", raw)
+	}
+	return fmt.Sprintf("AS%d", n), nil
+}
+
+// ExpandCIDR returns the host addresses of an IPv4 CIDR, leaving out the network and
+// broadcast addresses of ranges larger than a /31. IPv6 ranges can't be walked and are
+// refused, as are ranges over MaxExpandAddresses.
+func ExpandCIDR(cidr string) ([]string, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+	}
+	base := ipNet.IP.To4()
+	if base == nil {
+		return nil, fmt.Errorf("IPv6 range %s is too large to reverse-resolve", cidr)
+	}
+	ones, bits := ipNet.Mask.Size()
+	size := 1 << (bits - ones)
+	if size > MaxExpandAddresses {
+		return nil, fmt.Errorf("%s has %d addresses, more than the %d limit", cidr, size, MaxExpandAddresses)
+	}
+
+	first, last := 0, size
+	if size > 2 {
+		first, last = 1, size-1
+	}
+	start := binary.BigEndian.Uint32(base)
+	ips := make([]string, 0, last-first)
+	for i := first; i < last; i++ {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, start+uint32(i))
+		ips = append(ips, ip.String())
+	}
+	return ips, nil
+}
+
+// ReverseResolveCIDR looks up the PTR names of every host in an IPv4 CIDR using a bounded
+// worker pool and returns the distinct hostnames, sorted. On cancellation the names found
+// so far are returned along with a "cancelled" error.
+func (c *SubdomainClient) ReverseResolveCIDR(ctx context.Context, cidr string, concurrency int) ([]string, error) {
+	ips, err := ExpandCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return c.reverseResolveIPs(ctx, ips, concurrency)
+}
+
+// ReverseResolveASN looks up the IPv4 prefixes an ASN announces through the prefix source
+// (RIPEstat unless SetPrefixSource changed it) and reverse-resolves all of them. IPv6
+// prefixes are skipped; an ASN announcing more than MaxExpandAddresses IPv4 addresses is
+// refused so the caller can pick individual prefixes instead.
+func (c *SubdomainClient) ReverseResolveASN(ctx context.Context, asn string, concurrency int) ([]string, error) {
+	asn, err := NormalizeASN(asn)
+	if err != nil {
+		return nil, err
+	}
+	if c.prefixSource == nil {
+		source := NewRIPEStatPrefixSource()
+		source.userAgent = c.userAgent
+		c.prefixSource = source
+	}
+	prefixes, err := c.prefixSource.AnnouncedPrefixes(ctx, asn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up prefixes for %s: %w", asn, err)
+	}
+
+	var ips []string
+	total := 0
+	for _, prefix := range prefixes {
+		_, ipNet, err := net.ParseCIDR(prefix)
+		if err != nil || ipNet.IP.To4() == nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		total += 1 << (bits - ones)
+		if total > MaxExpandAddresses {
+			return nil, fmt.Errorf("%s announces more than %d IPv4 addresses - expand individual prefixes instead", asn, MaxExpandAddresses)
+		}
+		expanded, err := ExpandCIDR(prefix)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, expanded...)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("ASN prefixes expanded", "asn", asn, "prefixes", len(prefixes), "addresses", len(ips))
+	}
+	return c.reverseResolveIPs(ctx, ips, concurrency)
+}
+
+// reverseResolveIPs runs PTR lookups for ips on the subdomain worker pool
+func (c *SubdomainClient) reverseResolveIPs(ctx context.Context, ips []string, concurrency int) ([]string, error) {
+	if concurrency <= 0 {
+		concurrency = defaultResolveConcurrency
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	_, cancelled := runSubdomainWorkers(len(ips), concurrency, ctx.Done(), func(i int) {
+		names := c.reverseLookup(ips[i : i+1])
+		mu.Lock()
+		for _, name := range names {
+			seen[name] = true
+		}
+		mu.Unlock()
+	})
+
+	hostnames := make([]string, 0, len(seen))
+	for name := range seen {
+		hostnames = append(hostnames, name)
+	}
+	sort.Strings(hostnames)
+
+	if c.logger != nil {
+		c.logger.Info("Reverse DNS expansion finished", "addresses", len(ips), "hostnames", len(hostnames), "cancelled", cancelled)
+	}
+	if cancelled {
+		return hostnames, fmt.Errorf("cancelled")
+	}
+	return hostnames, nil
+}
+
+// SubdomainsForTargets turns reverse-resolved hostnames into subdomain records under the
+// tracked target domains they belong to (the most specific target wins). Hostnames outside
+// every target are dropped.
+func SubdomainsForTargets(hostnames, targets []string) []models.Subdomain {
+	var subdomains []models.Subdomain
+	for _, host := range hostnames {
+		best := ""
+		for _, target := range targets {
+			if _, ok := NormalizeSubdomain(host, target); ok && len(target) > len(best) {
+				best = target
+			}
+		}
+		if best == "" {
+			continue
+		}
+		name, _ := NormalizeSubdomain(host, best)
+		subdomains = append(subdomains, models.Subdomain{
+			Domain:    best,
+			Subdomain: name,
+			Source:    SourceReverseDNS,
+		})
+	}
+	return subdomains
+}
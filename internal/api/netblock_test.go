@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakePrefixSource returns fixed prefixes for any ASN
+type fakePrefixSource struct {
+	prefixes []string
+	asn      string
+}
+
+func (f *fakePrefixSource) AnnouncedPrefixes(ctx context.Context, asn string) ([]string, error) {
+	f.asn = asn
+	return f.prefixes, nil
+}
+
+// TestExpandCIDR verifies host enumeration and the range limits
+func TestExpandCIDR(t *testing.T) {
+	tests := []struct {
+		cidr string
+		want string
+	}{
+		{"192.0.2.0/30", "192.0.2.1 192.0.2.2"},
+		{"192.0.2.8/31", "192.0.2.8 192.0.2.9"},
+		{"192.0.2.7/32", "192.0.2.7"},
+		{"192.0.2.5/30", "192.0.2.5 192.0.2.6"}, // host bits are masked off
+	}
+	for _, tt := range tests {
+		ips, err := ExpandCIDR(tt.cidr)
+		if err != nil || strings.Join(ips, " ") != tt.want {
+			t.Errorf("ExpandCIDR(%s) = %v, %v; want %s", tt.cidr, ips, err, tt.want)
+		}
+	}
+	if ips, err := ExpandCIDR("10.0.0.0/16"); err != nil || len(ips) != MaxExpandAddresses-2 {
+		t.Errorf("ExpandCIDR(/16) = %d addresses, %v", len(ips), err)
+	}
+	for _, bad := range []string{"10.0.0.0/15", "2001:db8::/64", "not-a-cidr"} {
+		if _, err := ExpandCIDR(bad); err == nil {
+			t.Errorf("ExpandCIDR(%s) accepted", bad)
+		}
+	}
+}
+
+// TestReverseResolveASN verifies prefixes come from the pluggable source, IPv6 prefixes are
+// skipped, and hostnames land under the most specific tracked target
+func TestReverseResolveASN(t *testing.T) {
+	source := &fakePrefixSource{prefixes: []string{"198.51.100.0/30", "2001:db8::/32"}}
+	client := NewSubdomainClient("", nil)
+	client.SetPrefixSource(source)
+	client.lookupAddr = func(addr string) ([]string, error) {
+		switch addr {
+		case "198.51.100.1":
+			return []string{"mail.corp.example.com."}, nil
+		case "198.51.100.2":
+			return []string{"Host-2.ISP.net.", "web.example.com."}, nil
+		}
+		return nil, fmt.Errorf("no PTR")
+	}
+
+	hostnames, err := client.ReverseResolveASN(context.Background(), "as64500", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.asn != "AS64500" {
+		t.Errorf("prefix source asked for %q", source.asn)
+	}
+	if got := strings.Join(hostnames, " "); got != "host-2.isp.net mail.corp.example.com web.example.com" {
+		t.Errorf("hostnames = %q", got)
+	}
+
+	subs := SubdomainsForTargets(hostnames, []string{"example.com", "corp.example.com"})
+	if len(subs) != 2 || subs[0].Domain != "corp.example.com" || subs[1].Domain != "example.com" || subs[0].Source != SourceReverseDNS {
+		t.Errorf("SubdomainsForTargets = %+v", subs)
+	}
+
+	source.prefixes = []string{"10.0.0.0/16", "10.1.0.0/24"}
+	if _, err := client.ReverseResolveASN(context.Background(), "64500", 4); err == nil {
+		t.Error("oversized ASN accepted")
+	}
+	if _, err := client.ReverseResolveASN(context.Background(), "ASX", 4); err == nil {
+		t.Error("invalid ASN accepted")
+	}
+}
+
+// TestRIPEStatPrefixSource verifies the announced-prefixes response is parsed
+func TestRIPEStatPrefixSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/announced-prefixes/data.json" || r.URL.Query().Get("resource") != "AS64500" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"status":"ok","data":{"prefixes":[{"prefix":"198.51.100.0/24"},{"prefix":"2001:db8::/32"}]}}`)
+	}))
+	defer server.Close()
+
+	source := NewRIPEStatPrefixSource()
+	source.baseURL = server.URL
+	prefixes, err := source.AnnouncedPrefixes(context.Background(), "AS64500")
+	if err != nil || strings.Join(prefixes, " ") != "198.51.100.0/24 2001:db8::/32" {
+		t.Errorf("AnnouncedPrefixes = %v, %v", prefixes, err)
+	}
+}
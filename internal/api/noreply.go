@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// LoginCache persists GitHub account ID to login lookups
+// An empty login records an ID that no longer resolves, so it isn't looked up again.
+type LoginCache interface {
+	GetLoginByID(id int64) (login string, found bool, err error)
+	SaveLoginByID(id int64, login string) error
+}
+
+// SetLoginCache enables caching for ResolveNoreplyUserIDs lookups
+func (c *Client) SetLoginCache(cache LoginCache) {
+	c.logins = cache
+}
+
+// FetchLoginByID looks up the login of a GitHub account by its numeric ID
+// Returns an empty login without error when the account doesn't exist (e.g. deleted).
+func (c *Client) FetchLoginByID(id int64) (string, error) {
+	url := fmt.Sprintf("%s/user/%d", c.restURL, id)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	if c.logger != nil {
+		c.logger.Info("GET", "endpoint", url)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch user %d: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	if err := c.checkRateLimit(resp); err != nil {
+		return "", err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	case http.StatusUnauthorized:
+		return "", ErrUnauthorized
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var user models.GitHubUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return user.Login, nil
+}
+
+// ResolveNoreplyUserIDs fills in the GitHub account of commits whose author or committer has
+// none but uses an ID-only noreply email ("12345678@users.noreply.github.com"), looking each
+// ID up once via the login cache or the API. Emails naming the login are handled offline by
+// ToRecord. Returns how many commit identities were filled in; on error, those resolved so far
+// are kept.
+func (c *Client) ResolveNoreplyUserIDs(commits []models.Commit) (int, error) {
+	resolved := make(map[int64]string)
+	lookup := func(id int64) (string, error) {
+		if login, ok := resolved[id]; ok {
+			return login, nil
+		}
+		if c.logins != nil {
+			login, found, err := c.logins.GetLoginByID(id)
+			if err != nil {
+				return "", err
+			}
+			if found {
+				resolved[id] = login
+				return login, nil
+			}
+		}
+		login, err := c.FetchLoginByID(id)
+		if err != nil {
+			return "", err
+		}
+		resolved[id] = login
+		if c.logins != nil {
+			if err := c.logins.SaveLoginByID(id, login); err != nil {
+				return "", err
+			}
+		}
+		return login, nil
+	}
+
+	filled := 0
+	fill := func(account **models.GitHubUser, email string) error {
+		if *account != nil && (*account).Login != "" {
+			return nil
+		}
+		id, ok := models.NoreplyUserID(email)
+		if !ok {
+			return nil
+		}
+		login, err := lookup(id)
+		if err != nil || login == "" {
+			return err
+		}
+		*account = &models.GitHubUser{Login: login, ID: id}
+		filled++
+		return nil
+	}
+
+	for i := range commits {
+		if err := fill(&commits[i].Author, commits[i].Commit.Author.Email); err != nil {
+			return filled, err
+		}
+		if err := fill(&commits[i].Committer, commits[i].Commit.Committer.Email); err != nil {
+			return filled, err
+		}
+	}
+	return filled, nil
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// memLoginCache is an in-memory LoginCache for tests
+type memLoginCache map[int64]string
+
+func (c memLoginCache) GetLoginByID(id int64) (string, bool, error) {
+	login, ok := c[id]
+	return login, ok, nil
+}
+
+func (c memLoginCache) SaveLoginByID(id int64, login string) error {
+	c[id] = login
+	return nil
+}
+
+// TestResolveNoreplyUserIDs verifies ID-only noreply emails are looked up once per ID, cached
+// (including IDs that don't resolve), and only fill in accounts GitHub left empty
+func TestResolveNoreplyUserIDs(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path == "/user/583231" {
+			w.Write([]byte(`{"login":"octocat","id":583231}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient("token")
+	client.restURL = server.URL
+	cache := memLoginCache{}
+	client.SetLoginCache(cache)
+
+	commit := func(author, committer string) models.Commit {
+		return models.Commit{Commit: models.CommitDetails{
+			Author:    models.GitUser{Email: author},
+			Committer: models.GitUser{Email: committer},
+		}}
+	}
+	commits := []models.Commit{
+		commit("583231@users.noreply.github.com", "583231@users.noreply.github.com"),
+		commit("999@users.noreply.github.com", "dev@example.com"),
+		commit("583231@users.noreply.github.com", "octocat@users.noreply.github.com"),
+	}
+	commits[2].Author = &models.GitHubUser{Login: "already-linked"}
+
+	resolved, err := client.ResolveNoreplyUserIDs(commits)
+	if err != nil {
+		t.Fatalf("ResolveNoreplyUserIDs() error = %v", err)
+	}
+	var got []string
+	for _, c := range commits {
+		for _, u := range []*models.GitHubUser{c.Author, c.Committer} {
+			if u == nil {
+				got = append(got, "-")
+			} else {
+				got = append(got, u.Login)
+			}
+		}
+	}
+	if want := "octocat octocat - - already-linked -"; resolved != 2 || strings.Join(got, " ") != want {
+		t.Errorf("resolved %d: %s, want 2: %s", resolved, strings.Join(got, " "), want)
+	}
+	if fmt.Sprint(requests) != "map[/user/583231:1 /user/999:1]" {
+		t.Errorf("requests = %v, want one per ID", requests)
+	}
+	if cache[583231] != "octocat" || cache[999] != "" || len(cache) != 2 {
+		t.Errorf("cache = %v", cache)
+	}
+
+	// A second run is answered from the cache
+	if _, err := client.ResolveNoreplyUserIDs([]models.Commit{commit("999@users.noreply.github.com", "583231@users.noreply.github.com")}); err != nil {
+		t.Fatal(err)
+	}
+	if requests["/user/583231"] != 1 || requests["/user/999"] != 1 {
+		t.Errorf("cached IDs fetched again: %v", requests)
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyEnvVar names the catch-all proxy variable consulted when HTTP_PROXY/HTTPS_PROXY are unset
+const ProxyEnvVar = "ALL_PROXY"
+
+var (
+	proxyMu       sync.RWMutex
+	explicitProxy *url.URL
+)
+
+// SetProxy routes every API client through the given proxy URL (http, https, socks5 or socks5h),
+// overriding the proxy environment variables. An empty string restores the environment behavior.
+func SetProxy(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		proxyMu.Lock()
+		explicitProxy = nil
+		proxyMu.Unlock()
+		return nil
+	}
+
+	u, err := parseProxyURL(raw)
+	if err != nil {
+		return err
+	}
+
+	proxyMu.Lock()
+	explicitProxy = u
+	proxyMu.Unlock()
+	return nil
+}
+
+// parseProxyURL validates a proxy URL and its scheme
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https, socks5 or socks5h)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy URL %q has no host", raw)
+	}
+	return u, nil
+}
+
+// proxyForRequest picks the proxy for a request: the explicit -proxy URL if set, otherwise
+// HTTP_PROXY/HTTPS_PROXY falling back to ALL_PROXY, with NO_PROXY honored
+func proxyForRequest(req *http.Request) (*url.URL, error) {
+	proxyMu.RLock()
+	u := explicitProxy
+	proxyMu.RUnlock()
+	if u != nil {
+		return u, nil
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if all := getEnvAny(ProxyEnvVar, strings.ToLower(ProxyEnvVar)); all != "" {
+		if cfg.HTTPProxy == "" {
+			cfg.HTTPProxy = all
+		}
+		if cfg.HTTPSProxy == "" {
+			cfg.HTTPSProxy = all
+		}
+	}
+	return cfg.ProxyFunc()(req.URL)
+}
+
+// getEnvAny returns the first non-empty value among the named environment variables
+func getEnvAny(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// newTransport returns a copy of the default transport that honors the configured proxy
+func newTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxyForRequest
+	return t
+}
+
+// newHTTPClient returns an HTTP client with the given timeout that honors the configured proxy
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: newTransport(),
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestProxy verifies API clients send requests through an explicit -proxy URL or ALL_PROXY
+func TestProxy(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy", "NO_PROXY", "no_proxy"} {
+		t.Setenv(name, "")
+	}
+	t.Cleanup(func() { SetProxy("") })
+
+	var mu sync.Mutex
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hosts = append(hosts, r.URL.Host)
+		mu.Unlock()
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	fetch := func() []string {
+		t.Helper()
+		mu.Lock()
+		hosts = nil
+		mu.Unlock()
+		client := NewClient("")
+		client.restURL = "http://api.github.example"
+		if _, err := client.FetchCommits("o", "r", "", nil); err != nil {
+			t.Fatalf("FetchCommits() error = %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), hosts...)
+	}
+
+	if err := SetProxy(proxy.URL); err != nil {
+		t.Fatalf("SetProxy() error = %v", err)
+	}
+	if got := fetch(); len(got) == 0 || got[0] != "api.github.example" {
+		t.Errorf("explicit proxy saw hosts %v, want api.github.example", got)
+	}
+
+	SetProxy("")
+	t.Setenv("ALL_PROXY", proxy.URL)
+	if got := fetch(); len(got) == 0 || got[0] != "api.github.example" {
+		t.Errorf("ALL_PROXY proxy saw hosts %v, want api.github.example", got)
+	}
+
+	for _, raw := range []string{"socks5://127.0.0.1:9050", "socks5h://127.0.0.1:9050", "https://proxy:3128"} {
+		if err := SetProxy(raw); err != nil {
+			t.Errorf("SetProxy(%q) error = %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"ftp://proxy:21", "socks5://"} {
+		if err := SetProxy(raw); err == nil {
+			t.Errorf("SetProxy(%q) should fail", raw)
+		}
+	}
+}
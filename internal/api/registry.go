@@ -3,30 +3,123 @@ package api
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 const (
-	registryBaseURL    = "https://registry-1.docker.io/v2"
-	authURL            = "https://auth.docker.io/token"
-	registryService    = "registry.docker.io"
+	dockerHubRegistry  = "registry-1.docker.io"
 	manifestAccept     = "application/vnd.docker.distribution.manifest.v2+json"
 	manifestListAccept = "application/vnd.docker.distribution.manifest.list.v2+json"
+	ociManifestAccept  = "application/vnd.oci.image.manifest.v1+json"
+	ociIndexAccept     = "application/vnd.oci.image.index.v1+json"
+)
+
+// Registry retry defaults: a rate-limited (429) or flapping (5xx, network error) request is
+// retried with capped exponential backoff and jitter, or after Retry-After when sent
+const (
+	DefaultRegistryRetries = 3
+	registryRetryBaseDelay = 500 * time.Millisecond
+	registryRetryMaxDelay  = 30 * time.Second
+)
+
+// Blob downloads have no overall timeout, since a large layer can take minutes to stream.
+// Instead the wait for response headers is bounded, and a body that stops arriving for
+// registryBlobIdleTimeout is cut off.
+const (
+	registryBlobHeaderTimeout = 60 * time.Second
+	registryBlobIdleTimeout   = 60 * time.Second
 )
 
+// registryAuth describes a registry's anonymous token endpoint
+type registryAuth struct {
+	Realm   string
+	Service string
+}
+
+// knownRegistryAuth holds token endpoints for registries that are used when a
+// 401 response carries no usable WWW-Authenticate challenge
+var knownRegistryAuth = map[string]registryAuth{
+	dockerHubRegistry: {Realm: "https://auth.docker.io/token", Service: "registry.docker.io"},
+	"ghcr.io":         {Realm: "https://ghcr.io/token", Service: "ghcr.io"},
+}
+
+// ErrRegistryAuth is returned when a registry rejects the configured credentials
+var ErrRegistryAuth = errors.New("authentication failed or no access")
+
+// ErrDigestMismatch is returned when downloaded content doesn't hash to its digest
+var ErrDigestMismatch = errors.New("digest mismatch")
+
 // RegistryClient handles Docker Registry API v2 requests
 type RegistryClient struct {
 	httpClient *http.Client
+	blobClient *http.Client // Streams blobs; see registryBlobIdleTimeout
+
+	blobIdleTimeout time.Duration
+
 	token      string // cached bearer token
-	user       string // cached user from last request
-	repo       string // cached repo from last request
+	tokenScope string // registry/repository the cached token was issued for
+
+	// Optional credentials sent as basic auth during the token exchange (private images)
+	username string
+	password string
+
+	userAgent string
+
+	// Retry policy for rate-limited or failing requests
+	maxRetries     int
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+}
+
+// SetCredentials sets the username and password or access token used to pull private images
+func (c *RegistryClient) SetCredentials(username, password string) {
+	c.username = username
+	c.password = password
+	c.token = "" // Tokens issued anonymously don't carry the new access
+	c.tokenScope = ""
+}
+
+// SetUserAgent sets the User-Agent sent with every request; empty restores the default
+func (c *RegistryClient) SetUserAgent(ua string) {
+	c.userAgent = resolveUserAgent(ua)
+}
+
+// HasCredentials returns true if registry credentials are configured
+func (c *RegistryClient) HasCredentials() bool {
+	return c.username != "" && c.password != ""
+}
+
+// Username returns the configured registry username
+func (c *RegistryClient) Username() string {
+	return c.username
+}
+
+// ImageRef is a parsed image reference: [registry/][namespace/]repo[:tag][@digest]
+type ImageRef struct {
+	Registry  string // Registry host, e.g. "registry-1.docker.io" or "ghcr.io"
+	Namespace string // User, org or owner ("library" for official Docker Hub images)
+	Repo      string // Repository name; may contain slashes for nested paths
+	Tag       string // Empty when the reference has no tag
+	Digest    string // Empty when the reference has no digest
+	Name      string // The reference as written, without tag or digest
 }
 
 // Manifest represents a Docker image manifest
@@ -73,89 +166,233 @@ type TarEntry struct {
 
 // NewRegistryClient creates a new Docker Registry API client
 func NewRegistryClient() *RegistryClient {
+	return NewRegistryClientWithRetry(DefaultRegistryRetries)
+}
+
+// NewRegistryClientWithRetry creates a registry client that retries rate-limited or failing
+// requests up to maxRetries times (0 disables retries)
+func NewRegistryClientWithRetry(maxRetries int) *RegistryClient {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
 	return &RegistryClient{
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		httpClient:      newHTTPClient(60 * time.Second),
+		blobClient:      newBlobHTTPClient(),
+		blobIdleTimeout: registryBlobIdleTimeout,
+		userAgent:       defaultUserAgent(),
+		maxRetries:      maxRetries,
+		retryBaseDelay:  registryRetryBaseDelay,
+		retryMaxDelay:   registryRetryMaxDelay,
 	}
 }
 
-// ParseImageRef parses an image reference into user, repo, and tag
-// Examples:
-//   - "nginx" -> "library", "nginx", "latest"
-//   - "nginx:1.21" -> "library", "nginx", "1.21"
-//   - "moby/buildkit" -> "moby", "buildkit", "latest"
-//   - "moby/buildkit:v0.12" -> "moby", "buildkit", "v0.12"
-func ParseImageRef(ref string) (user, repo, tag string) {
-	tag = "latest"
+// newBlobHTTPClient returns the client blobs stream on: only the wait for response headers is
+// bounded, as a Client.Timeout would also cut off reading a large layer's body
+func newBlobHTTPClient() *http.Client {
+	t := newTransport()
+	t.ResponseHeaderTimeout = registryBlobHeaderTimeout
+	return &http.Client{Transport: t}
+}
+
+// ParseImageRef parses an image reference, defaulting to Docker Hub when no host is present
+// The first path component is treated as a registry host when it contains "." or ":" or is
+// "localhost", matching the Docker CLI. Examples:
+//   - "nginx" -> registry-1.docker.io, "library", "nginx"
+//   - "moby/buildkit:v0.12" -> registry-1.docker.io, "moby", "buildkit", tag "v0.12"
+//   - "ghcr.io/owner/image:tag" -> ghcr.io, "owner", "image", tag "tag"
+//   - "localhost:5000/app@sha256:..." -> localhost:5000, "", "app", digest "sha256:..."
+func ParseImageRef(ref string) (ImageRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ImageRef{}, fmt.Errorf("empty image reference")
+	}
+
+	var parsed ImageRef
+
+	// Split off digest if present
+	if idx := strings.Index(ref, "@"); idx != -1 {
+		parsed.Digest = ref[idx+1:]
+		ref = ref[:idx]
+		if !strings.Contains(parsed.Digest, ":") {
+			return ImageRef{}, fmt.Errorf("invalid digest %q", parsed.Digest)
+		}
+	}
+
+	// Split off tag if present (a colon before the last slash is a registry port)
+	if idx := strings.LastIndex(ref, ":"); idx != -1 && !strings.Contains(ref[idx+1:], "/") {
+		parsed.Tag = ref[idx+1:]
+		ref = ref[:idx]
+		if parsed.Tag == "" {
+			return ImageRef{}, fmt.Errorf("empty tag in image reference")
+		}
+	}
+	parsed.Name = ref
 
-	// Split off tag if present
-	if idx := strings.LastIndex(ref, ":"); idx != -1 {
-		// Make sure it's not a port number (no slashes after colon)
-		afterColon := ref[idx+1:]
-		if !strings.Contains(afterColon, "/") {
-			tag = afterColon
-			ref = ref[:idx]
+	path := ref
+	if idx := strings.Index(ref, "/"); idx != -1 {
+		host := ref[:idx]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			parsed.Registry = host
+			path = ref[idx+1:]
+		}
+	}
+	if parsed.Registry == "" || parsed.Registry == "docker.io" || parsed.Registry == "index.docker.io" {
+		parsed.Registry = dockerHubRegistry
+		if !strings.Contains(path, "/") {
+			path = "library/" + path
 		}
 	}
 
-	// Split user/repo
-	if strings.Contains(ref, "/") {
-		parts := strings.SplitN(ref, "/", 2)
-		user = parts[0]
-		repo = parts[1]
+	if idx := strings.Index(path, "/"); idx != -1 {
+		parsed.Namespace = path[:idx]
+		parsed.Repo = path[idx+1:]
 	} else {
-		user = "library"
-		repo = ref
+		parsed.Repo = path
+	}
+	if parsed.Repo == "" || strings.HasSuffix(parsed.Repo, "/") {
+		return ImageRef{}, fmt.Errorf("missing repository in image reference %q", ref)
+	}
+
+	return parsed, nil
+}
+
+// Path returns the repository path used in registry API URLs (e.g., "library/nginx")
+func (r ImageRef) Path() string {
+	if r.Namespace == "" {
+		return r.Repo
+	}
+	return r.Namespace + "/" + r.Repo
+}
+
+// Reference returns the digest if present, otherwise the tag (default "latest")
+func (r ImageRef) Reference() string {
+	if r.Digest != "" {
+		return r.Digest
 	}
+	if r.Tag != "" {
+		return r.Tag
+	}
+	return "latest"
+}
+
+// HasReference reports whether the reference named an explicit tag or digest
+func (r ImageRef) HasReference() bool {
+	return r.Tag != "" || r.Digest != ""
+}
+
+// WithReference returns the image name joined with a tag or digest
+func (r ImageRef) WithReference(tagOrDigest string) string {
+	if strings.Contains(tagOrDigest, ":") {
+		return r.Name + "@" + tagOrDigest
+	}
+	return r.Name + ":" + tagOrDigest
+}
+
+// scope returns the registry/repository key a pull token is valid for
+func (r ImageRef) scope() string {
+	return r.Registry + "/" + r.Path()
+}
 
-	return user, repo, tag
+// FetchPullToken retrieves an anonymous bearer token for pulling from the image's registry
+// Uses the built-in token endpoint for known registries (Docker Hub, GHCR)
+func (c *RegistryClient) FetchPullToken(ref ImageRef) (string, error) {
+	auth, ok := knownRegistryAuth[ref.Registry]
+	if !ok {
+		return "", fmt.Errorf("no known token endpoint for registry %s", ref.Registry)
+	}
+	return c.fetchToken(auth, ref)
 }
 
-// FetchPullToken retrieves an anonymous bearer token for pulling from Docker Hub
-func (c *RegistryClient) FetchPullToken(user, repo string) (string, error) {
-	url := fmt.Sprintf("%s?service=%s&scope=repository:%s/%s:pull", authURL, registryService, user, repo)
+// fetchToken requests a pull-scoped token from a registry's token endpoint and caches it
+func (c *RegistryClient) fetchToken(auth registryAuth, ref ImageRef) (string, error) {
+	params := url.Values{}
+	if auth.Service != "" {
+		params.Set("service", auth.Service)
+	}
+	params.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Path()))
+
+	req, err := http.NewRequest("GET", auth.Realm+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if c.HasCredentials() {
+		req.SetBasicAuth(c.username, c.password)
+	}
 
-	resp, err := c.httpClient.Get(url)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch token: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if c.HasCredentials() && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+		return "", fmt.Errorf("%w: %s rejected credentials for %s", ErrRegistryAuth, ref.Registry, c.username)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
 	}
 
+	// Registries return the token as "token" (Docker Hub, GHCR) or "access_token" (OAuth2 style)
 	var tokenResp struct {
-		Token string `json:"token"`
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
 		return "", fmt.Errorf("failed to decode token response: %w", err)
 	}
 
-	if tokenResp.Token == "" {
+	token := tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+	if token == "" {
 		return "", fmt.Errorf("token endpoint returned empty token")
 	}
 
-	c.token = tokenResp.Token
-	c.user = user
-	c.repo = repo
+	c.token = token
+	c.tokenScope = ref.scope()
 
-	return tokenResp.Token, nil
+	return token, nil
+}
+
+// parseBearerChallenge extracts the token endpoint from a WWW-Authenticate header
+// e.g. `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:o/i:pull"`
+func parseBearerChallenge(header string) (registryAuth, bool) {
+	scheme, params, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return registryAuth{}, false
+	}
+
+	var auth registryAuth
+	for _, part := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(key) {
+		case "realm":
+			auth.Realm = value
+		case "service":
+			auth.Service = value
+		}
+	}
+	return auth, auth.Realm != ""
 }
 
 // ListTags fetches available tags for an image repository
 func (c *RegistryClient) ListTags(imageRef string) ([]string, error) {
-	user, repo, _ := ParseImageRef(imageRef)
-
-	url := fmt.Sprintf("%s/tags/list", registryURL(user, repo))
+	ref, err := ParseImageRef(imageRef)
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", registryURL(ref)+"/tags/list", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequest(req, ref)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch tags: %w", err)
 	}
@@ -178,25 +415,27 @@ func (c *RegistryClient) ListTags(imageRef string) ([]string, error) {
 	return tagsResp.Tags, nil
 }
 
-// registryURL builds a registry URL for the given user/repo
-func registryURL(user, repo string) string {
-	return fmt.Sprintf("%s/%s/%s", registryBaseURL, user, repo)
+// registryURL builds the v2 API base URL for an image's repository
+func registryURL(ref ImageRef) string {
+	return fmt.Sprintf("https://%s/v2/%s", ref.Registry, ref.Path())
 }
 
 // doRequest performs an HTTP request with auth handling
-func (c *RegistryClient) doRequest(req *http.Request) (*http.Response, error) {
-	// Set accept header for manifests - request both v2 manifest and manifest list
-	// Docker registry uses content negotiation to return appropriate type
+// A 401 triggers the registry's token flow (from its WWW-Authenticate challenge, or the
+// known endpoint for Docker Hub/GHCR) and one retry with the new token.
+func (c *RegistryClient) doRequest(req *http.Request, ref ImageRef) (*http.Response, error) {
+	// Set accept header for manifests - request v2/OCI manifests and manifest lists/indexes
+	// The registry uses content negotiation to return the appropriate type
 	if strings.Contains(req.URL.Path, "/manifests/") {
-		req.Header.Set("Accept", manifestAccept+", "+manifestListAccept)
+		req.Header.Set("Accept", strings.Join([]string{manifestAccept, manifestListAccept, ociManifestAccept, ociIndexAccept}, ", "))
 	}
 
-	// Add auth if we have a token
-	if c.token != "" {
+	// Add auth if we have a token for this repository
+	if c.token != "" && c.tokenScope == ref.scope() {
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return nil, err
 	}
@@ -205,42 +444,139 @@ func (c *RegistryClient) doRequest(req *http.Request) (*http.Response, error) {
 	if resp.StatusCode == http.StatusUnauthorized {
 		resp.Body.Close()
 
-		// Extract user/repo from URL
-		parts := strings.Split(req.URL.Path, "/")
-		if len(parts) >= 4 {
-			user := parts[2]
-			repo := parts[3]
-			if _, err := c.FetchPullToken(user, repo); err != nil {
-				return nil, fmt.Errorf("failed to refresh token: %w", err)
+		auth, ok := parseBearerChallenge(resp.Header.Get("WWW-Authenticate"))
+		if !ok {
+			auth, ok = knownRegistryAuth[ref.Registry]
+		}
+		if !ok {
+			return nil, fmt.Errorf("registry %s requires authentication", ref.Registry)
+		}
+		if _, err := c.fetchToken(auth, ref); err != nil {
+			if errors.Is(err, ErrRegistryAuth) {
+				return nil, err
 			}
+			return nil, fmt.Errorf("failed to refresh token: %w", err)
+		}
+
+		// Retry with new token
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		resp, err = c.doWithRetry(req)
+		if err != nil {
+			return nil, err
+		}
 
-			// Retry with new token
-			req.Header.Set("Authorization", "Bearer "+c.token)
-			return c.httpClient.Do(req)
+		// Still rejected with a fresh token: the credentials lack access to this repository
+		if c.HasCredentials() && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w to %s/%s as %s", ErrRegistryAuth, ref.Registry, ref.Path(), c.username)
 		}
 	}
 
 	return resp, nil
 }
 
+// doBlobRequest is doRequest for a blob, which streams on blobClient. The body is cut off once
+// no data arrives for blobIdleTimeout.
+func (c *RegistryClient) doBlobRequest(req *http.Request, ref ImageRef) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	resp, err := c.doRequest(req.WithContext(ctx), ref)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &idleTimeoutBody{
+		ReadCloser: resp.Body,
+		timer:      time.AfterFunc(c.blobIdleTimeout, cancel),
+		timeout:    c.blobIdleTimeout,
+		cancel:     cancel,
+	}
+	return resp, nil
+}
+
+// idleTimeoutBody cancels its request when no data has been read for timeout
+type idleTimeoutBody struct {
+	io.ReadCloser
+	timer   *time.Timer
+	timeout time.Duration
+	cancel  context.CancelFunc
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.timer.Reset(b.timeout)
+	}
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// doWithRetry sends a bodiless request, retrying 429s, 5xx responses and network errors
+// up to maxRetries times. The last response or error is returned once retries run out.
+func (c *RegistryClient) doWithRetry(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.userAgent)
+	client := c.httpClient
+	if strings.Contains(req.URL.Path, "/blobs/") {
+		client = c.blobClient
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= c.maxRetries || (err == nil && !retryableStatus(resp.StatusCode)) {
+			return resp, err
+		}
+
+		delay := c.backoff(attempt)
+		if err == nil {
+			// A registry asking for a long wait (say, a day) is still capped like the backoff
+			delay = min(retryAfterDelay(resp, delay), c.retryMaxDelay)
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+	}
+}
+
+// retryableStatus reports whether a registry response is worth retrying
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || (status >= 500 && status != http.StatusNotImplemented)
+}
+
+// backoff returns the delay before retry attempt+1: exponential from retryBaseDelay, capped
+// at retryMaxDelay, with jitter across the upper half so parallel fetches don't retry in step
+func (c *RegistryClient) backoff(attempt int) time.Duration {
+	delay := c.retryBaseDelay << attempt
+	if delay <= 0 || delay > c.retryMaxDelay {
+		delay = c.retryMaxDelay
+	}
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(half)
+}
+
 // GetManifest fetches the manifest for an image
 // If digest is empty, fetches by tag. Otherwise fetches by digest.
 func (c *RegistryClient) GetManifest(imageRef string, digest string) (*Manifest, error) {
-	user, repo, tag := ParseImageRef(imageRef)
+	ref, err := ParseImageRef(imageRef)
+	if err != nil {
+		return nil, err
+	}
 
-	ref := tag
+	reference := ref.Reference()
 	if digest != "" {
-		ref = digest
+		reference = digest
 	}
 
-	url := fmt.Sprintf("%s/manifests/%s", registryURL(user, repo), ref)
-
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/manifests/%s", registryURL(ref), reference), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doRequest(req, ref)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
 	}
@@ -358,34 +694,64 @@ func (c *RegistryClient) GetManifest(imageRef string, digest string) (*Manifest,
 
 // FetchBuildSteps retrieves the Dockerfile history from the image config
 func (c *RegistryClient) FetchBuildSteps(imageRef, configDigest string) ([]string, error) {
-	user, repo, _ := ParseImageRef(imageRef)
+	_, steps, err := c.FetchImageConfig(imageRef, configDigest)
+	return steps, err
+}
 
-	url := fmt.Sprintf("%s/blobs/%s", registryURL(user, repo), configDigest)
+// FetchImageConfig retrieves the image config blob and returns its runtime settings
+// (env, entrypoint, cmd, working dir, exposed ports, labels) along with the build steps
+func (c *RegistryClient) FetchImageConfig(imageRef, configDigest string) (*models.ImageConfig, []string, error) {
+	ref, err := ParseImageRef(imageRef)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/blobs/%s", registryURL(ref), configDigest), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doBlobRequest(req, ref)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch config: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch config: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("config request failed with status %d", resp.StatusCode)
+		return nil, nil, fmt.Errorf("config request failed with status %d", resp.StatusCode)
 	}
 
 	var config struct {
+		Config struct {
+			User         string              `json:"User"`
+			Env          []string            `json:"Env"`
+			Entrypoint   []string            `json:"Entrypoint"`
+			Cmd          []string            `json:"Cmd"`
+			WorkingDir   string              `json:"WorkingDir"`
+			ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+			Labels       map[string]string   `json:"Labels"`
+		} `json:"config"`
 		History []struct {
 			CreatedBy  string `json:"created_by"`
 			EmptyLayer bool   `json:"empty_layer,omitempty"`
 		} `json:"history"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
-		return nil, fmt.Errorf("failed to parse config: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	imageConfig := &models.ImageConfig{
+		User:       config.Config.User,
+		Env:        config.Config.Env,
+		Entrypoint: config.Config.Entrypoint,
+		Cmd:        config.Config.Cmd,
+		WorkingDir: config.Config.WorkingDir,
+		Labels:     config.Config.Labels,
+	}
+	for port := range config.Config.ExposedPorts {
+		imageConfig.ExposedPorts = append(imageConfig.ExposedPorts, port)
 	}
+	sort.Strings(imageConfig.ExposedPorts)
 
 	var steps []string
 	for _, entry := range config.History {
@@ -396,7 +762,7 @@ func (c *RegistryClient) FetchBuildSteps(imageRef, configDigest string) ([]strin
 		steps = append(steps, step)
 	}
 
-	return steps, nil
+	return imageConfig, steps, nil
 }
 
 // ExtractV1BuildSteps extracts build steps from v1 manifest history
@@ -438,35 +804,44 @@ func ExtractV1BuildSteps(history []V1HistoryEntry) []string {
 
 // PeekLayerBlob downloads a layer and lists its contents without saving to disk
 func (c *RegistryClient) PeekLayerBlob(imageRef, digest string) ([]TarEntry, error) {
-	user, repo, _ := ParseImageRef(imageRef)
+	entries, _, err := c.ScanLayerBlob(imageRef, digest, nil)
+	return entries, err
+}
 
-	url := fmt.Sprintf("%s/blobs/%s", registryURL(user, repo), digest)
+// ScanLayerBlob lists a layer's contents and scans small text files for secrets in one pass
+// With no rules it behaves like PeekLayerBlob. Findings are tagged with imageRef and digest.
+func (c *RegistryClient) ScanLayerBlob(imageRef, digest string, rules []SecretRule) ([]TarEntry, []models.LayerSecretFinding, error) {
+	ref, err := ParseImageRef(imageRef)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/blobs/%s", registryURL(ref), digest), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doBlobRequest(req, ref)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch layer: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch layer: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("layer request failed with status %d", resp.StatusCode)
+		return nil, nil, fmt.Errorf("layer request failed with status %d", resp.StatusCode)
 	}
 
 	// Decompress gzip
 	gzReader, err := gzip.NewReader(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		return nil, nil, fmt.Errorf("failed to create gzip reader: %w", err)
 	}
 	defer gzReader.Close()
 
 	// Read tar entries
 	tarReader := tar.NewReader(gzReader)
 	var entries []TarEntry
+	var findings []models.LayerSecretFinding
 
 	for {
 		header, err := tarReader.Next()
@@ -474,7 +849,7 @@ func (c *RegistryClient) PeekLayerBlob(imageRef, digest string) ([]TarEntry, err
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to read tar entry: %w", err)
+			return nil, nil, fmt.Errorf("failed to read tar entry: %w", err)
 		}
 
 		entries = append(entries, TarEntry{
@@ -482,23 +857,83 @@ func (c *RegistryClient) PeekLayerBlob(imageRef, digest string) ([]TarEntry, err
 			Size:  header.Size,
 			IsDir: header.Typeflag == tar.TypeDir,
 		})
+
+		// Only small regular files are worth reading; the rest is skipped by tarReader.Next
+		if len(rules) == 0 || header.Typeflag != tar.TypeReg || header.Size == 0 || header.Size > maxSecretScanSize {
+			continue
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		for _, f := range ScanSecrets(header.Name, content, rules) {
+			f.ImageRef = imageRef
+			f.LayerDigest = digest
+			findings = append(findings, f)
+		}
+	}
+
+	return entries, findings, nil
+}
+
+// digestHasher returns a hash for a digest's algorithm ("sha256:..." or "sha512:...")
+func digestHasher(digest string) (hash.Hash, string, error) {
+	algorithm, encoded, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, "", fmt.Errorf("invalid digest %q", digest)
+	}
+	switch algorithm {
+	case "sha256":
+		return sha256.New(), encoded, nil
+	case "sha512":
+		return sha512.New(), encoded, nil
 	}
+	return nil, "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+}
+
+// DownloadProgress reports how much of a layer download has been written to disk
+type DownloadProgress struct {
+	Written int64
+	Total   int64 // From Content-Length, else the manifest size; 0 when unknown
+}
+
+// progressWriter reports bytes written to a progress callback
+type progressWriter struct {
+	progress DownloadProgress
+	report   func(DownloadProgress)
+}
 
-	return entries, nil
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.progress.Written += int64(len(p))
+	w.report(w.progress)
+	return len(p), nil
 }
 
-// DownloadLayerBlob downloads a layer blob to disk
+// DownloadLayerBlob downloads a layer blob to disk, verifying it against its digest
+// The content is hashed while it streams to the file; on a mismatch or failed write the
+// partial file is removed and an error (ErrDigestMismatch for a mismatch) is returned.
 func (c *RegistryClient) DownloadLayerBlob(imageRef, digest string, size int64) (string, error) {
-	user, repo, _ := ParseImageRef(imageRef)
+	return c.DownloadLayerBlobWithProgress(imageRef, digest, size, nil)
+}
 
-	url := fmt.Sprintf("%s/blobs/%s", registryURL(user, repo), digest)
+// DownloadLayerBlobWithProgress is DownloadLayerBlob reporting bytes written as the layer
+// streams to disk. progress is called from the downloading goroutine and may be nil.
+func (c *RegistryClient) DownloadLayerBlobWithProgress(imageRef, digest string, size int64, progress func(DownloadProgress)) (string, error) {
+	ref, err := ParseImageRef(imageRef)
+	if err != nil {
+		return "", err
+	}
+	hasher, want, err := digestHasher(digest)
+	if err != nil {
+		return "", err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/blobs/%s", registryURL(ref), digest), nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.doRequest(req)
+	resp, err := c.doBlobRequest(req, ref)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch layer: %w", err)
 	}
@@ -509,7 +944,12 @@ func (c *RegistryClient) DownloadLayerBlob(imageRef, digest string, size int64)
 	}
 
 	// Create output directory
-	outputDir := filepath.Join("downloads", fmt.Sprintf("%s_%s", user, repo), "latest")
+	// Docker Hub images keep the plain "user_repo" directory; other registries are prefixed by host
+	dirName := strings.ReplaceAll(ref.Path(), "/", "_")
+	if ref.Registry != dockerHubRegistry {
+		dirName = strings.ReplaceAll(ref.Registry, ":", "_") + "_" + dirName
+	}
+	outputDir := filepath.Join("downloads", dirName, "latest")
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
@@ -522,14 +962,30 @@ func (c *RegistryClient) DownloadLayerBlob(imageRef, digest string, size int64)
 	if err != nil {
 		return "", fmt.Errorf("failed to create output file: %w", err)
 	}
-	defer file.Close()
 
-	// Stream to file
-	_, err = io.Copy(file, resp.Body)
+	// Stream to file, hashing (and reporting progress) as we go
+	dst := io.MultiWriter(file, hasher)
+	if progress != nil {
+		total := resp.ContentLength
+		if total <= 0 {
+			total = size
+		}
+		dst = io.MultiWriter(dst, &progressWriter{progress: DownloadProgress{Total: total}, report: progress})
+	}
+	_, err = io.Copy(dst, resp.Body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
+		os.Remove(outputPath)
 		return "", fmt.Errorf("failed to write layer to file: %w", err)
 	}
 
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != want {
+		os.Remove(outputPath)
+		return "", fmt.Errorf("%w: layer %s downloaded with hash %s", ErrDigestMismatch, digest, got)
+	}
+
 	return outputPath, nil
 }
 
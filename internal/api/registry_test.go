@@ -0,0 +1,322 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseImageRef verifies registry host detection, Docker Hub defaults and tag/digest parsing
+func TestParseImageRef(t *testing.T) {
+	digest := "sha256:" + "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		ref       string
+		registry  string
+		namespace string
+		repo      string
+		reference string
+		name      string
+	}{
+		{"nginx", dockerHubRegistry, "library", "nginx", "latest", "nginx"},
+		{"nginx:1.21", dockerHubRegistry, "library", "nginx", "1.21", "nginx"},
+		{"moby/buildkit:v0.12", dockerHubRegistry, "moby", "buildkit", "v0.12", "moby/buildkit"},
+		{"docker.io/library/alpine", dockerHubRegistry, "library", "alpine", "latest", "docker.io/library/alpine"},
+		{"docker.io/alpine:3", dockerHubRegistry, "library", "alpine", "3", "docker.io/alpine"},
+		{"ghcr.io/owner/image:tag", "ghcr.io", "owner", "image", "tag", "ghcr.io/owner/image"},
+		{"ghcr.io/owner/group/image", "ghcr.io", "owner", "group/image", "latest", "ghcr.io/owner/group/image"},
+		{"localhost:5000/app@" + digest, "localhost:5000", "", "app", digest, "localhost:5000/app"},
+		{"quay.io/org/app:v1@" + digest, "quay.io", "org", "app", digest, "quay.io/org/app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := ParseImageRef(tt.ref)
+			if err != nil {
+				t.Fatalf("ParseImageRef(%q) error = %v", tt.ref, err)
+			}
+			if got.Registry != tt.registry || got.Namespace != tt.namespace || got.Repo != tt.repo {
+				t.Errorf("ParseImageRef(%q) = %s %q %q, want %s %q %q",
+					tt.ref, got.Registry, got.Namespace, got.Repo, tt.registry, tt.namespace, tt.repo)
+			}
+			if got.Reference() != tt.reference {
+				t.Errorf("Reference() = %q, want %q", got.Reference(), tt.reference)
+			}
+			if got.Name != tt.name {
+				t.Errorf("Name = %q, want %q", got.Name, tt.name)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "nginx:", "ghcr.io/", "nginx@latest"} {
+		if _, err := ParseImageRef(bad); err == nil {
+			t.Errorf("ParseImageRef(%q) returned nil error", bad)
+		}
+	}
+}
+
+// TestImageRefWithReference verifies tags join with ":" and digests with "@"
+func TestImageRefWithReference(t *testing.T) {
+	ref, err := ParseImageRef("ghcr.io/owner/image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.HasReference() {
+		t.Error("HasReference() = true for untagged reference")
+	}
+	if got := ref.WithReference("v2"); got != "ghcr.io/owner/image:v2" {
+		t.Errorf("WithReference(tag) = %q", got)
+	}
+	if got := ref.WithReference("sha256:abc"); got != "ghcr.io/owner/image@sha256:abc" {
+		t.Errorf("WithReference(digest) = %q", got)
+	}
+}
+
+// TestParseBearerChallenge verifies token endpoint discovery from WWW-Authenticate
+func TestParseBearerChallenge(t *testing.T) {
+	auth, ok := parseBearerChallenge(`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:owner/image:pull"`)
+	if !ok || auth.Realm != "https://ghcr.io/token" || auth.Service != "ghcr.io" {
+		t.Errorf("parseBearerChallenge() = %+v, %v", auth, ok)
+	}
+	if _, ok := parseBearerChallenge(`Basic realm="registry"`); ok {
+		t.Error("parseBearerChallenge() accepted a Basic challenge")
+	}
+}
+
+// TestRegistryCredentialedTokenFlow verifies basic auth in the token exchange and the
+// auth error surfaced when a fresh token is still rejected
+func TestRegistryCredentialedTokenFlow(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "bob" || pass != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"private-token"}`)
+			return
+		}
+
+		// Only owner/app is readable with the issued token
+		if r.Header.Get("Authorization") != "Bearer private-token" || !strings.HasPrefix(r.URL.Path, "/v2/owner/app/") {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"name":"owner/app","tags":["v1","v2"]}`)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	client := NewRegistryClient()
+	client.httpClient = server.Client()
+	client.SetCredentials("bob", "s3cret")
+
+	tags, err := client.ListTags(host + "/owner/app")
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	if strings.Join(tags, ",") != "v1,v2" {
+		t.Errorf("ListTags() = %v, want [v1 v2]", tags)
+	}
+
+	if _, err := client.ListTags(host + "/owner/other"); !errors.Is(err, ErrRegistryAuth) {
+		t.Errorf("ListTags() without access error = %v, want ErrRegistryAuth", err)
+	}
+
+	client.SetCredentials("bob", "wrong")
+	if _, err := client.ListTags(host + "/owner/app"); !errors.Is(err, ErrRegistryAuth) {
+		t.Errorf("ListTags() with bad password error = %v, want ErrRegistryAuth", err)
+	}
+}
+
+// TestRegistryRetriesRateLimits verifies 429s are retried (honoring Retry-After) until the
+// registry answers, and surfaced once the configured retries run out
+func TestRegistryRetriesRateLimits(t *testing.T) {
+	var requests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests % 3 {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests) // No Retry-After: exponential backoff
+		default:
+			fmt.Fprint(w, `{"name":"owner/app","tags":["v1"]}`)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	client := NewRegistryClient()
+	client.httpClient = server.Client()
+	client.retryBaseDelay = time.Millisecond
+
+	tags, err := client.ListTags(host + "/owner/app")
+	if err != nil || strings.Join(tags, ",") != "v1" || requests != 3 {
+		t.Fatalf("ListTags() = %v, %v after %d requests", tags, err, requests)
+	}
+
+	requests = 0
+	client = NewRegistryClientWithRetry(1)
+	client.httpClient = server.Client()
+	client.retryBaseDelay = time.Millisecond
+	if _, err := client.ListTags(host + "/owner/app"); err == nil || !strings.Contains(err.Error(), "status 429") || requests != 2 {
+		t.Errorf("ListTags() with 1 retry = %v after %d requests", err, requests)
+	}
+
+	// A Retry-After longer than retryMaxDelay waits retryMaxDelay instead
+	day := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer day.Close()
+	client = NewRegistryClientWithRetry(1)
+	client.httpClient = day.Client()
+	client.retryMaxDelay = 10 * time.Millisecond
+	start := time.Now()
+	if _, err := client.ListTags(strings.TrimPrefix(day.URL, "https://") + "/owner/app"); err == nil {
+		t.Error("ListTags() against a rate-limited registry succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Retry-After: 86400 waited %v, want it capped at retryMaxDelay", elapsed)
+	}
+}
+
+// TestRegistryBackoff verifies the retry delay grows exponentially, stays capped and is jittered
+func TestRegistryBackoff(t *testing.T) {
+	client := NewRegistryClient()
+	for attempt, want := range []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second} {
+		if got := client.backoff(attempt); got < want/2 || got >= want {
+			t.Errorf("backoff(%d) = %v, want [%v, %v)", attempt, got, want/2, want)
+		}
+	}
+	if got := client.backoff(40); got < registryRetryMaxDelay/2 || got >= registryRetryMaxDelay {
+		t.Errorf("backoff(40) = %v, want capped at %v", got, registryRetryMaxDelay)
+	}
+}
+
+// TestDownloadLayerBlobVerifiesDigest verifies a good blob is kept and a corrupted one is
+// rejected with ErrDigestMismatch and removed
+func TestDownloadLayerBlobVerifiesDigest(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	blob := []byte("layer contents")
+	sum := sha256.Sum256(blob)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	corrupt := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if corrupt {
+			w.Write(blob[:len(blob)-1]) // Truncated body
+			return
+		}
+		w.Write(blob)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	client := NewRegistryClient()
+	client.httpClient, client.blobClient = server.Client(), server.Client()
+
+	path, err := client.DownloadLayerBlob(host+"/owner/app", digest, int64(len(blob)))
+	if err != nil {
+		t.Fatalf("DownloadLayerBlob() error = %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != string(blob) {
+		t.Errorf("downloaded %q", got)
+	}
+
+	corrupt = true
+	if _, err := client.DownloadLayerBlob(host+"/owner/app", digest, int64(len(blob))); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("DownloadLayerBlob() corrupted error = %v, want ErrDigestMismatch", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("corrupted download left %s behind (stat err %v)", path, err)
+	}
+}
+
+// TestDownloadLayerBlobProgress verifies progress reports bytes written against Content-Length
+func TestDownloadLayerBlobProgress(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	blob := []byte(strings.Repeat("layer", 50000))
+	sum := sha256.Sum256(blob)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(blob)))
+		w.Write(blob)
+	}))
+	defer server.Close()
+
+	client := NewRegistryClient()
+	client.httpClient, client.blobClient = server.Client(), server.Client()
+
+	var updates []DownloadProgress
+	_, err := client.DownloadLayerBlobWithProgress(strings.TrimPrefix(server.URL, "https://")+"/owner/app",
+		"sha256:"+hex.EncodeToString(sum[:]), 0, func(p DownloadProgress) { updates = append(updates, p) })
+	if err != nil {
+		t.Fatalf("DownloadLayerBlobWithProgress() error = %v", err)
+	}
+	if len(updates) < 2 {
+		t.Fatalf("got %d progress updates, want several", len(updates))
+	}
+	if last := updates[len(updates)-1]; last.Written != int64(len(blob)) || last.Total != int64(len(blob)) {
+		t.Errorf("final progress = %+v, want %d/%d", last, len(blob), len(blob))
+	}
+}
+
+// TestDownloadLayerBlobStreamsWithoutDeadline verifies a layer that keeps streaming downloads
+// past the idle timeout, while one that stalls mid-body is cut off
+func TestDownloadLayerBlobStreamsWithoutDeadline(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	chunk := []byte(strings.Repeat("layer", 1000))
+	var blob []byte
+	for i := 0; i < 5; i++ {
+		blob = append(blob, chunk...)
+	}
+	sum := sha256.Sum256(blob)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pause := 20 * time.Millisecond
+		if strings.HasPrefix(r.URL.Path, "/v2/owner/stalled/") {
+			pause = 200 * time.Millisecond
+		}
+		for i := 0; i < 5; i++ {
+			w.Write(chunk)
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(pause):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	client := NewRegistryClient()
+	if client.blobClient.Timeout != 0 {
+		t.Errorf("blob client Timeout = %v, want none", client.blobClient.Timeout)
+	}
+	client.httpClient, client.blobClient = server.Client(), server.Client()
+	client.blobIdleTimeout = 60 * time.Millisecond
+
+	// 5 chunks 20ms apart take longer than the idle timeout in total
+	if _, err := client.DownloadLayerBlob(host+"/owner/app", digest, int64(len(blob))); err != nil {
+		t.Fatalf("DownloadLayerBlob() streaming error = %v", err)
+	}
+
+	if _, err := client.DownloadLayerBlob(host+"/owner/stalled", digest, int64(len(blob))); err == nil {
+		t.Error("DownloadLayerBlob() of a stalled body succeeded, want it cut off")
+	}
+}
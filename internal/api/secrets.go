@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+const (
+	maxSecretScanSize     = 1 << 20 // Files larger than this are not scanned
+	binarySniffLen        = 8000    // Bytes checked for NUL when detecting binary files
+	maxFindingsPerFile    = 20      // Stop reporting a file after this many hits
+	maxSecretPreviewLen   = 120     // Longest preview line kept per finding
+	secretPreviewVisible  = 4       // Leading characters of a secret left unredacted
+	secretPreviewRedacted = "********"
+)
+
+// SecretRule is a named pattern that flags likely secrets in file contents
+// When Pattern has a capture group, group 1 is the secret to redact; otherwise the whole match is.
+type SecretRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Marker rules match a header that identifies a secret (e.g., a PEM block) rather than
+	// the secret itself, so the match is shown unredacted
+	Marker bool
+}
+
+// DefaultSecretRules returns the built-in secret rules
+// Callers can append their own rules before passing the slice to ScanLayerBlob.
+func DefaultSecretRules() []SecretRule {
+	return []SecretRule{
+		{Name: "aws-access-key-id", Pattern: regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+		{Name: "aws-secret-access-key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,})\b`)},
+		{Name: "github-fine-grained-token", Pattern: regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{22,})\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\b(xox[abprs]-[A-Za-z0-9-]{10,})\b`)},
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`), Marker: true},
+		{Name: "generic-api-key", Pattern: regexp.MustCompile(`(?i)(?:api[_-]?key|secret[_-]?key|access[_-]?token|auth[_-]?token|client[_-]?secret)["']?\s*[:=]\s*["']?([A-Za-z0-9_\-./+=]{16,})`)},
+	}
+}
+
+// ScanSecrets runs the rules over a file's contents and returns any findings
+// Binary and oversized files are skipped. ImageRef and LayerDigest are left for the caller to fill.
+func ScanSecrets(path string, content []byte, rules []SecretRule) []models.LayerSecretFinding {
+	if len(rules) == 0 || len(content) == 0 || len(content) > maxSecretScanSize || isBinary(content) {
+		return nil
+	}
+
+	var findings []models.LayerSecretFinding
+	for _, line := range strings.Split(string(content), "\n") {
+		preview := "" // Built on the line's first finding, with every secret on it redacted
+		for _, rule := range rules {
+			for range rule.Pattern.FindAllStringSubmatchIndex(line, -1) {
+				if preview == "" {
+					preview = secretPreview(line, rules)
+				}
+				findings = append(findings, models.LayerSecretFinding{
+					FilePath: path,
+					RuleName: rule.Name,
+					Preview:  preview,
+				})
+				if len(findings) >= maxFindingsPerFile {
+					return findings
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// isBinary reports whether content looks binary (a NUL byte near the start, as git does)
+func isBinary(content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffLen {
+		sniff = sniff[:binarySniffLen]
+	}
+	return bytes.IndexByte(sniff, 0) >= 0
+}
+
+// secretPreview returns the line with every secret any rule matches on it redacted, trimmed to a
+// readable length. Redacting only the finding's own match would leave other secrets on the line
+// (e.g. the secret key next to an access key in one-line JSON) in clear text.
+func secretPreview(line string, rules []SecretRule) string {
+	var spans [][2]int
+	for _, rule := range rules {
+		if rule.Marker {
+			continue
+		}
+		for _, match := range rule.Pattern.FindAllStringSubmatchIndex(line, -1) {
+			start, end := match[0], match[1]
+			if len(match) >= 4 && match[2] >= 0 {
+				start, end = match[2], match[3]
+			}
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	// Overlapping matches are redacted as one secret
+	var b strings.Builder
+	pos := 0
+	for i := 0; i < len(spans); i++ {
+		start, end := spans[i][0], spans[i][1]
+		for i+1 < len(spans) && spans[i+1][0] < end {
+			i++
+			if spans[i][1] > end {
+				end = spans[i][1]
+			}
+		}
+		b.WriteString(line[pos:start])
+		b.WriteString(redactSecret(line[start:end]))
+		pos = end
+	}
+	b.WriteString(line[pos:])
+	redacted := strings.TrimSpace(b.String())
+
+	if runes := []rune(redacted); len(runes) > maxSecretPreviewLen {
+		redacted = string(runes[:maxSecretPreviewLen]) + "..."
+	}
+	return redacted
+}
+
+// redactSecret keeps a short prefix of the secret so findings can be told apart
+func redactSecret(secret string) string {
+	if len(secret) <= secretPreviewVisible*2 {
+		return secretPreviewRedacted
+	}
+	return secret[:secretPreviewVisible] + secretPreviewRedacted
+}
@@ -1,40 +1,116 @@
 package api
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 const (
 	vtAPIBaseURL     = "https://www.virustotal.com/api/v3"
 	crtshBaseURL     = "https://crt.sh"
+	stAPIBaseURL     = "https://api.securitytrails.com/v1"
+	censysAPIBaseURL = "https://search.censys.io/api/v2"
 	subdomainTimeout = 60 * time.Second
 	vtBatchSize      = 40 // VT API default limit
+
+	defaultResolveConcurrency = 20
+	defaultCrtshConcurrency   = 4
+	defaultProbeConcurrency   = 10
+	defaultProbeTimeout       = 10 * time.Second
+
+	censysPerPage    = 100 // Censys search page size maximum
+	censysMaxPages   = 10  // Cap pages per run to stay within free-tier query quotas
+	censysMaxRetries = 3   // Attempts per page after a 429
+
+	crtshMaxRetries = 3               // Attempts after a 502/503/504 from an overloaded crt.sh
+	crtshRetryDelay = 5 * time.Second // First backoff, doubled on each retry
+
+	vtDefaultPageDelay = 500 * time.Millisecond // Pause between VT pages when no rate limit headers come back
+	vtMaxPageDelay     = time.Minute            // Cap on a header-derived pause - an exhausted daily quota fails fast instead
 )
 
+// ErrCrtshUnavailable is returned when crt.sh stays overloaded or answers with an HTML
+// error page instead of JSON
+var ErrCrtshUnavailable = errors.New("crt.sh temporarily unavailable")
+
 // SubdomainClient handles subdomain enumeration API requests
 type SubdomainClient struct {
 	httpClient *http.Client
 	vtAPIKey   string
+	stAPIKey   string
 	logger     *log.Logger
+
+	// Censys Search API credentials (HTTP basic auth)
+	censysAPIID     string
+	censysAPISecret string
+
+	userAgent string
+	// crtshBrowserUA sends BrowserUserAgent to crt.sh instead of userAgent
+	crtshBrowserUA bool
+
+	// Base URLs, overridable in tests
+	vtBaseURL     string
+	crtshBaseURL  string
+	censysBaseURL string
+
+	// crtshRetryDelay is the first crt.sh backoff, shortened in tests
+	crtshRetryDelay time.Duration
+
+	// vtPageDelay is the pause between VT pages when the response has no X-RateLimit-* headers
+	vtPageDelay time.Duration
+
+	// includeWildcards keeps "*.x" certificate names as wildcard records instead of dropping them
+	includeWildcards bool
+
+	// lookupPTR adds reverse DNS lookups of the resolved IPs to ResolveSubdomains
+	lookupPTR bool
+
+	// prefixSource resolves ASNs to announced prefixes for ReverseResolveASN (RIPEstat by default)
+	prefixSource PrefixSource
+
+	// DNS lookups, overridable in tests
+	lookupHost func(host string) ([]string, error)
+	lookupAddr func(addr string) ([]string, error)
 }
 
-// NewSubdomainClient creates a new subdomain enumeration client
+// NewSubdomainClient creates a new subdomain enumeration client; a nil logger uses the shared one
 func NewSubdomainClient(vtAPIKey string, logger *log.Logger) *SubdomainClient {
+	if logger == nil {
+		logger = logging.WithPrefix("Subdomains")
+	}
 	return &SubdomainClient{
-		httpClient: &http.Client{
-			Timeout: subdomainTimeout,
-		},
-		vtAPIKey: vtAPIKey,
-		logger:   logger,
+		httpClient:      newHTTPClient(subdomainTimeout),
+		vtAPIKey:        vtAPIKey,
+		logger:          logger,
+		userAgent:       defaultUserAgent(),
+		crtshBrowserUA:  crtshBrowserUserAgentFromEnv(),
+		vtBaseURL:       vtAPIBaseURL,
+		crtshBaseURL:    crtshBaseURL,
+		censysBaseURL:   censysAPIBaseURL,
+		crtshRetryDelay: crtshRetryDelay,
+		vtPageDelay:     vtDefaultPageDelay,
+		lookupHost:      net.LookupHost,
+		lookupAddr:      net.LookupAddr,
 	}
 }
 
@@ -48,61 +124,204 @@ func (c *SubdomainClient) HasVirusTotalAPIKey() bool {
 	return c.vtAPIKey != ""
 }
 
+// SetSecurityTrailsAPIKey updates the SecurityTrails API key
+func (c *SubdomainClient) SetSecurityTrailsAPIKey(apiKey string) {
+	c.stAPIKey = apiKey
+}
+
+// HasSecurityTrailsAPIKey returns true if a SecurityTrails API key is configured
+func (c *SubdomainClient) HasSecurityTrailsAPIKey() bool {
+	return c.stAPIKey != ""
+}
+
+// SetCensysCredentials updates the Censys API id and secret
+func (c *SubdomainClient) SetCensysCredentials(apiID, secret string) {
+	c.censysAPIID = apiID
+	c.censysAPISecret = secret
+}
+
+// HasCensysCredentials returns true if both Censys API id and secret are configured
+func (c *SubdomainClient) HasCensysCredentials() bool {
+	return c.censysAPIID != "" && c.censysAPISecret != ""
+}
+
+// SetUserAgent sets the User-Agent sent with every request, including HTTP probes of
+// discovered hosts; empty restores the default
+func (c *SubdomainClient) SetUserAgent(ua string) {
+	c.userAgent = resolveUserAgent(ua)
+}
+
+// SetCrtshBrowserUserAgent makes crt.sh requests send a browser User-Agent, as they did
+// before identifying User-Agents were the default, for when crt.sh turns away other clients
+func (c *SubdomainClient) SetCrtshBrowserUserAgent(browser bool) {
+	c.crtshBrowserUA = browser
+}
+
+// SetIncludeWildcards controls whether crt.sh wildcard names are kept
+// When enabled, "*.dev.example.com" is stored as "dev.example.com" with IsWildcard set.
+func (c *SubdomainClient) SetIncludeWildcards(include bool) {
+	c.includeWildcards = include
+}
+
+// SetLookupPTR controls whether ResolveSubdomains also looks up PTR records for resolved IPs
+func (c *SubdomainClient) SetLookupPTR(lookup bool) {
+	c.lookupPTR = lookup
+}
+
+// normalizeCertName normalizes a certificate name, honoring the wildcard option
+// Returns the normalized name, whether it came from a wildcard, and ok=false to skip.
+func (c *SubdomainClient) normalizeCertName(raw, domain string) (string, bool, bool) {
+	if name, ok := NormalizeSubdomain(raw, domain); ok {
+		return name, false, true
+	}
+	if !c.includeWildcards {
+		return "", false, false
+	}
+
+	trimmed := strings.ToLower(strings.TrimSpace(raw))
+	if !strings.HasPrefix(trimmed, "*.") {
+		return "", false, false
+	}
+	name, ok := NormalizeSubdomain(strings.TrimPrefix(trimmed, "*."), domain)
+	return name, true, ok
+}
+
+// NormalizeSubdomain canonicalizes a discovered hostname for storage
+// Lowercases, trims whitespace and trailing dots, and rejects wildcards and names
+// not under parentDomain. Returns ok=false when the name should be skipped.
+func NormalizeSubdomain(raw, parentDomain string) (string, bool) {
+	name := strings.TrimRight(strings.ToLower(strings.TrimSpace(raw)), ".")
+	parent := strings.TrimRight(strings.ToLower(strings.TrimSpace(parentDomain)), ".")
+
+	if name == "" || strings.HasPrefix(name, "*") {
+		return "", false
+	}
+
+	if name != parent && !strings.HasSuffix(name, "."+parent) {
+		return "", false
+	}
+
+	return name, true
+}
+
 // =============================================================================
 // VirusTotal API
 // =============================================================================
 
+// SetVirusTotalPageDelay sets the pause between VirusTotal pages used when responses carry no
+// rate limit headers; zero or less restores the 500ms default
+func (c *SubdomainClient) SetVirusTotalPageDelay(delay time.Duration) {
+	if delay <= 0 {
+		delay = vtDefaultPageDelay
+	}
+	c.vtPageDelay = delay
+}
+
+// VTProgress reports how a VirusTotal enumeration is going
+type VTProgress struct {
+	Subdomains     int           // Subdomains fetched so far
+	Pages          int           // Pages fetched in this run
+	PagesPerMinute float64       // Observed page rate, pauses included
+	Delay          time.Duration // Pause before the next page
+	Remaining      int           // Requests left in the quota window (-1 = no X-RateLimit-Remaining header)
+}
+
+// vtRateLimit holds the quota reported by a VirusTotal response's X-RateLimit-* headers
+type vtRateLimit struct {
+	remaining int           // Requests left in the window, -1 when absent
+	reset     time.Duration // Time until the window resets, 0 when absent
+}
+
+// parseVTRateLimit reads X-RateLimit-Remaining and X-RateLimit-Reset. Reset may be a Unix
+// timestamp or a number of seconds.
+func parseVTRateLimit(h http.Header) vtRateLimit {
+	rl := vtRateLimit{remaining: -1}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil && v >= 0 {
+		rl.remaining = v
+	}
+	if v, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil && v > 0 {
+		if v > 1e9 {
+			rl.reset = time.Until(time.Unix(v, 0))
+		} else {
+			rl.reset = time.Duration(v) * time.Second
+		}
+	}
+	return rl
+}
+
+// pageDelay spreads the remaining quota evenly over the rest of the window, waiting for the
+// reset once it is used up. Without both headers the configured fixed delay applies.
+func (c *SubdomainClient) pageDelay(rl vtRateLimit) time.Duration {
+	if rl.remaining < 0 || rl.reset <= 0 {
+		return c.vtPageDelay
+	}
+	delay := rl.reset
+	if rl.remaining > 0 {
+		delay = rl.reset / time.Duration(rl.remaining)
+	}
+	return min(delay, vtMaxPageDelay)
+}
+
 // FetchVirusTotalSubdomains fetches subdomains from VirusTotal API
 // Returns subdomains, cursor for next page, and any error
-func (c *SubdomainClient) FetchVirusTotalSubdomains(domain string, cursor string) ([]models.Subdomain, string, error) {
+func (c *SubdomainClient) FetchVirusTotalSubdomains(ctx context.Context, domain string, cursor string) ([]models.Subdomain, string, error) {
+	subdomains, nextCursor, _, err := c.fetchVirusTotalPage(ctx, domain, cursor)
+	return subdomains, nextCursor, err
+}
+
+// fetchVirusTotalPage fetches one page of subdomains along with the response's rate limit
+func (c *SubdomainClient) fetchVirusTotalPage(ctx context.Context, domain string, cursor string) ([]models.Subdomain, string, vtRateLimit, error) {
+	noLimit := vtRateLimit{remaining: -1}
 	if c.vtAPIKey == "" {
-		return nil, "", fmt.Errorf("VirusTotal API key not configured")
+		return nil, "", noLimit, fmt.Errorf("VirusTotal API key not configured")
 	}
 
 	// Build URL - domain should not be escaped, it's part of the path
-	reqURL := fmt.Sprintf("%s/domains/%s/subdomains?limit=%d", vtAPIBaseURL, domain, vtBatchSize)
+	reqURL := fmt.Sprintf("%s/domains/%s/subdomains?limit=%d", c.vtBaseURL, domain, vtBatchSize)
 	if cursor != "" {
 		reqURL += "&cursor=" + url.QueryEscape(cursor)
 	}
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", noLimit, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("x-apikey", c.vtAPIKey)
 	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("request failed: %w", err)
+		return nil, "", noLimit, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	rl := parseVTRateLimit(resp.Header)
 
 	if resp.StatusCode == 401 {
-		return nil, "", fmt.Errorf("invalid API key")
+		return nil, "", rl, fmt.Errorf("invalid API key")
 	}
 	if resp.StatusCode == 429 {
-		return nil, "", fmt.Errorf("rate limited - please wait and try again")
+		return nil, "", rl, fmt.Errorf("rate limited - please wait and try again")
 	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return nil, "", rl, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read response: %w", err)
+		return nil, "", rl, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Debug: check if body is empty or unexpected
 	if len(body) == 0 {
-		return nil, "", fmt.Errorf("empty response from VirusTotal")
+		return nil, "", rl, fmt.Errorf("empty response from VirusTotal")
 	}
 
 	var vtResp models.VirusTotalSubdomainResponse
 	if err := json.Unmarshal(body, &vtResp); err != nil {
-		return nil, "", fmt.Errorf("failed to parse response (body: %s): %w", string(body[:min(len(body), 200)]), err)
+		return nil, "", rl, fmt.Errorf("failed to parse response (body: %s): %w", string(body[:min(len(body), 200)]), err)
 	}
 
 	// Debug: return error showing raw response if no data
@@ -111,7 +330,7 @@ func (c *SubdomainClient) FetchVirusTotalSubdomains(domain string, cursor string
 		if len(snippet) > 500 {
 			snippet = snippet[:500]
 		}
-		return nil, "", fmt.Errorf("VT returned 0 items. Response: %s", snippet)
+		return nil, "", rl, fmt.Errorf("VT returned 0 items. Response: %s", snippet)
 	}
 
 	// Convert to Subdomain structs
@@ -128,45 +347,68 @@ func (c *SubdomainClient) FetchVirusTotalSubdomains(domain string, cursor string
 
 	// If no subdomains found but we got data, log what we got
 	if len(subdomains) == 0 && len(vtResp.Data) > 0 {
-		return nil, "", fmt.Errorf("parsed %d items but 0 matched domain type", len(vtResp.Data))
+		return nil, "", rl, fmt.Errorf("parsed %d items but 0 matched domain type", len(vtResp.Data))
 	}
 
 	// Get next cursor if available
 	nextCursor := vtResp.Meta.Cursor
 
-	return subdomains, nextCursor, nil
+	return subdomains, nextCursor, rl, nil
 }
 
 // FetchAllVirusTotalSubdomains fetches all subdomains from VirusTotal with pagination
-func (c *SubdomainClient) FetchAllVirusTotalSubdomains(domain string, progress func(count int), cancel <-chan struct{}) ([]models.Subdomain, error) {
+// Cancelling ctx aborts the in-flight request and returns the partial results
+func (c *SubdomainClient) FetchAllVirusTotalSubdomains(ctx context.Context, domain string, progress func(VTProgress)) ([]models.Subdomain, error) {
+	subdomains, _, err := c.FetchAllVirusTotalSubdomainsWithResume(ctx, domain, "", progress)
+	return subdomains, err
+}
+
+// FetchAllVirusTotalSubdomainsWithResume fetches subdomains starting from a stored cursor
+// Pass an empty startCursor to begin from the first page. Returns the subdomains fetched,
+// the cursor to resume from (empty when enumeration completed), and any error.
+// Pages are paced by the X-RateLimit-* headers when VT sends them, otherwise by the fixed
+// page delay; progress is called after each page with the observed rate.
+func (c *SubdomainClient) FetchAllVirusTotalSubdomainsWithResume(ctx context.Context, domain string, startCursor string, progress func(VTProgress)) ([]models.Subdomain, string, error) {
 	var allSubdomains []models.Subdomain
-	cursor := ""
+	cursor := startCursor
+	start := time.Now()
+	pages := 0
 
 	for {
 		// Check for cancellation
-		select {
-		case <-cancel:
-			return allSubdomains, fmt.Errorf("cancelled")
-		default:
+		if ctx.Err() != nil {
+			return allSubdomains, cursor, fmt.Errorf("cancelled")
 		}
 
-		batch, nextCursor, err := c.FetchVirusTotalSubdomains(domain, cursor)
+		batch, nextCursor, rl, err := c.fetchVirusTotalPage(ctx, domain, cursor)
+		if ctx.Err() != nil {
+			// Request was aborted mid-flight
+			return allSubdomains, cursor, fmt.Errorf("cancelled")
+		}
 		if err != nil {
 			if len(allSubdomains) > 0 {
 				// Return partial results on error
-				return allSubdomains, err
+				return allSubdomains, cursor, err
 			}
-			return nil, err
+			return nil, cursor, err
 		}
 
 		allSubdomains = append(allSubdomains, batch...)
+		pages++
+		delay := c.pageDelay(rl)
 
 		if progress != nil {
-			progress(len(allSubdomains))
+			progress(VTProgress{
+				Subdomains:     len(allSubdomains),
+				Pages:          pages,
+				PagesPerMinute: float64(pages) / time.Since(start).Minutes(),
+				Delay:          delay,
+				Remaining:      rl.remaining,
+			})
 		}
 
 		if c.logger != nil {
-			c.logger.Info("VT subdomains fetched", "count", len(allSubdomains), "hasMore", nextCursor != "")
+			c.logger.Info("VT subdomains fetched", "count", len(allSubdomains), "hasMore", nextCursor != "", "remaining", rl.remaining, "delay", delay)
 		}
 
 		if nextCursor == "" {
@@ -174,11 +416,15 @@ func (c *SubdomainClient) FetchAllVirusTotalSubdomains(domain string, progress f
 		}
 		cursor = nextCursor
 
-		// Small delay to be respectful to the API
-		time.Sleep(500 * time.Millisecond)
+		// Pace requests to the quota rather than tripping 429s
+		select {
+		case <-ctx.Done():
+			return allSubdomains, cursor, fmt.Errorf("cancelled")
+		case <-time.After(delay):
+		}
 	}
 
-	return allSubdomains, nil
+	return allSubdomains, "", nil
 }
 
 // =============================================================================
@@ -186,32 +432,12 @@ func (c *SubdomainClient) FetchAllVirusTotalSubdomains(domain string, progress f
 // =============================================================================
 
 // FetchCrtshSubdomains fetches subdomains from crt.sh certificate transparency logs
-func (c *SubdomainClient) FetchCrtshSubdomains(domain string) ([]models.Subdomain, error) {
-	// Build URL - use wildcard query to get all subdomains
-	reqURL := fmt.Sprintf("%s/?q=%%.%s&output=json", crtshBaseURL, url.QueryEscape(domain))
-
-	req, err := http.NewRequest("GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("crt.sh returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
+// Cancelling ctx (or a context.WithTimeout deadline) aborts the request immediately.
+// 502/503/504 responses are retried up to crtshMaxRetries times with exponential backoff.
+func (c *SubdomainClient) FetchCrtshSubdomains(ctx context.Context, domain string) ([]models.Subdomain, error) {
+	body, err := c.fetchCrtsh(ctx, domain)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, err
 	}
 
 	// Handle empty response
@@ -219,6 +445,11 @@ func (c *SubdomainClient) FetchCrtshSubdomains(domain string) ([]models.Subdomai
 		return []models.Subdomain{}, nil
 	}
 
+	// Under load crt.sh serves its Postgres error page with a 200
+	if !looksLikeJSON(body) {
+		return nil, fmt.Errorf("%w: %s", ErrCrtshUnavailable, crtshErrorSummary(body))
+	}
+
 	var crtshResp models.CrtshResponse
 	if err := json.Unmarshal(body, &crtshResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
@@ -235,17 +466,10 @@ func (c *SubdomainClient) FetchCrtshSubdomains(domain string) ([]models.Subdomai
 
 		// name_value can contain multiple names separated by newlines (SANs)
 		names := strings.Split(entry.NameValue, "\n")
-		for _, name := range names {
-			name = strings.TrimSpace(name)
-			name = strings.ToLower(name)
-
-			// Skip empty names and wildcards
-			if name == "" || strings.HasPrefix(name, "*") {
-				continue
-			}
-
-			// Ensure it's a subdomain of the target domain
-			if !strings.HasSuffix(name, "."+domain) && name != domain {
+		for _, raw := range names {
+			// Skip empty names, wildcards (unless enabled), and anything outside the target domain
+			name, wildcard, ok := c.normalizeCertName(raw, domain)
+			if !ok {
 				continue
 			}
 
@@ -257,19 +481,23 @@ func (c *SubdomainClient) FetchCrtshSubdomains(domain string) ([]models.Subdomai
 					Subdomain:   name,
 					Source:      "crtsh",
 					CertExpired: isExpired,
+					IsWildcard:  wildcard,
 				}
 			} else {
 				// Update expired flag if any cert is expired
 				if isExpired {
 					existing.CertExpired = true
 				}
+				// A concrete name outranks wildcard-only evidence
+				if !wildcard {
+					existing.IsWildcard = false
+				}
 			}
 
 			// Track common name - also add it as a separate subdomain if it's different
 			if entry.CommonName != "" && entry.CommonName != name {
-				cn := strings.TrimSpace(strings.ToLower(entry.CommonName))
-				// Skip wildcards
-				if !strings.HasPrefix(cn, "*") && cn != "" {
+				cn, ok := NormalizeSubdomain(entry.CommonName, domain)
+				if ok {
 					// Add CNAME as its own subdomain entry so it can be researched
 					if _, exists := subdomainMap[cn]; !exists {
 						subdomainMap[cn] = &models.Subdomain{
@@ -294,6 +522,585 @@ func (c *SubdomainClient) FetchCrtshSubdomains(domain string) ([]models.Subdomai
 	return subdomains, nil
 }
 
+// fetchCrtsh requests the certificate JSON for a domain, retrying overloaded responses
+func (c *SubdomainClient) fetchCrtsh(ctx context.Context, domain string) ([]byte, error) {
+	// Build URL - use wildcard query to get all subdomains
+	reqURL := fmt.Sprintf("%s/?q=%%.%s&output=json", c.crtshBaseURL, url.QueryEscape(domain))
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/json")
+		if c.crtshBrowserUA {
+			req.Header.Set("User-Agent", BrowserUserAgent)
+		} else {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() == context.Canceled {
+				return nil, fmt.Errorf("cancelled")
+			}
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if isRetryableCrtshStatus(resp.StatusCode) {
+			if attempt >= crtshMaxRetries {
+				return nil, fmt.Errorf("%w: status %d after %d retries", ErrCrtshUnavailable, resp.StatusCode, crtshMaxRetries)
+			}
+			backoff := retryAfterDelay(resp, c.crtshRetryDelay<<attempt)
+			if c.logger != nil {
+				c.logger.Warn("crt.sh overloaded, waiting", "status", resp.StatusCode, "backoff", backoff, "retry", attempt+1, "maxRetries", crtshMaxRetries)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("cancelled")
+			case <-time.After(backoff):
+			}
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			if !looksLikeJSON(body) {
+				return nil, fmt.Errorf("crt.sh returned status %d: %s", resp.StatusCode, crtshErrorSummary(body))
+			}
+			return nil, fmt.Errorf("crt.sh returned status %d: %s", resp.StatusCode, string(body))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		return body, nil
+	}
+}
+
+// isRetryableCrtshStatus returns true for the gateway errors crt.sh sends when overloaded
+func isRetryableCrtshStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// looksLikeJSON returns true if the body starts like a JSON array or object
+func looksLikeJSON(body []byte) bool {
+	trimmed := strings.TrimSpace(string(body))
+	return strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{")
+}
+
+// crtshErrorSummary reduces an HTML error page to its first line of visible text
+func crtshErrorSummary(body []byte) string {
+	text := htmlTagPattern.ReplaceAllString(string(body), "\n")
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(html.UnescapeString(line)); line != "" && !strings.EqualFold(line, "crt.sh") {
+			if len(line) > 200 {
+				line = line[:200] + "..."
+			}
+			return line
+		}
+	}
+	return "non-JSON response"
+}
+
+// htmlTagPattern matches an HTML tag, comment or doctype
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// FetchCrtshSubdomainsMulti fetches several root domains from crt.sh in parallel
+// At most concurrency queries run at once. Results from all domains are merged;
+// failures are reported per domain so one bad domain doesn't abort the rest.
+// Cancelling ctx aborts the queries in flight; domains not started yet are skipped.
+func (c *SubdomainClient) FetchCrtshSubdomainsMulti(ctx context.Context, domains []string, concurrency int) ([]models.Subdomain, map[string]error) {
+	if concurrency <= 0 {
+		concurrency = defaultCrtshConcurrency
+	}
+
+	perDomain := make([][]models.Subdomain, len(domains))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	done, _ := runSubdomainWorkers(len(domains), concurrency, ctx.Done(), func(i int) {
+		subdomains, err := c.FetchCrtshSubdomains(ctx, domains[i])
+		if err != nil {
+			mu.Lock()
+			errs[domains[i]] = err
+			mu.Unlock()
+			return
+		}
+		perDomain[i] = subdomains
+
+		if c.logger != nil {
+			c.logger.Info("crt.sh subdomains fetched", "domain", domains[i], "count", len(subdomains))
+		}
+	})
+
+	var all []models.Subdomain
+	for i, subdomains := range perDomain {
+		if !done[i] {
+			errs[domains[i]] = fmt.Errorf("cancelled")
+			continue
+		}
+		all = append(all, subdomains...)
+	}
+
+	return all, errs
+}
+
+// =============================================================================
+// SecurityTrails API
+// =============================================================================
+
+// FetchSecurityTrailsSubdomains fetches subdomains from the SecurityTrails API
+// SecurityTrails returns bare labels, so each one is joined onto the parent domain
+func (c *SubdomainClient) FetchSecurityTrailsSubdomains(domain string) ([]models.Subdomain, error) {
+	if c.stAPIKey == "" {
+		return nil, fmt.Errorf("SecurityTrails API key not configured")
+	}
+
+	reqURL := fmt.Sprintf("%s/domain/%s/subdomains?children_only=false", stAPIBaseURL, domain)
+
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("APIKEY", c.stAPIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		return nil, fmt.Errorf("invalid API key")
+	}
+	if resp.StatusCode == 429 {
+		return nil, fmt.Errorf("rate limited - please wait and try again")
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("SecurityTrails returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var stResp models.SecurityTrailsSubdomainResponse
+	if err := json.Unmarshal(body, &stResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	subdomains := make([]models.Subdomain, 0, len(stResp.Subdomains))
+	for _, label := range stResp.Subdomains {
+		label = strings.Trim(strings.TrimSpace(label), ".")
+		if label == "" {
+			continue
+		}
+
+		name, ok := NormalizeSubdomain(label+"."+domain, domain)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		subdomains = append(subdomains, models.Subdomain{
+			Domain:    domain,
+			Subdomain: name,
+			Source:    "securitytrails",
+		})
+	}
+
+	if c.logger != nil {
+		c.logger.Info("SecurityTrails subdomains fetched", "count", len(subdomains), "limitReached", stResp.Meta.LimitReached)
+	}
+
+	return subdomains, nil
+}
+
+// =============================================================================
+// Censys API
+// =============================================================================
+
+// FetchCensysSubdomains fetches subdomains from Censys certificate search
+// Names are taken from each certificate's names and parsed SAN DNS names, keeping only
+// those under domain. Follows the result cursor for up to censysMaxPages pages and
+// returns the partial results if a later page fails.
+func (c *SubdomainClient) FetchCensysSubdomains(domain string) ([]models.Subdomain, error) {
+	if !c.HasCensysCredentials() {
+		return nil, fmt.Errorf("Censys API credentials not configured")
+	}
+
+	seen := make(map[string]bool)
+	var subdomains []models.Subdomain
+	cursor := ""
+
+	for page := 0; page < censysMaxPages; page++ {
+		censysResp, err := c.fetchCensysPage(domain, cursor)
+		if err != nil {
+			if len(subdomains) > 0 {
+				return subdomains, err
+			}
+			return nil, err
+		}
+
+		for _, hit := range censysResp.Result.Hits {
+			names := append(hit.Names, hit.Parsed.Extensions.SubjectAltName.DNSNames...)
+			for _, raw := range names {
+				name, wildcard, ok := c.normalizeCertName(raw, domain)
+				if !ok || seen[name] {
+					continue
+				}
+				seen[name] = true
+
+				subdomains = append(subdomains, models.Subdomain{
+					Domain:     domain,
+					Subdomain:  name,
+					Source:     "censys",
+					IsWildcard: wildcard,
+				})
+			}
+		}
+
+		if c.logger != nil {
+			c.logger.Info("Censys subdomains fetched", "count", len(subdomains), "hasMore", censysResp.Result.Links.Next != "")
+		}
+
+		cursor = censysResp.Result.Links.Next
+		if cursor == "" {
+			break
+		}
+	}
+
+	return subdomains, nil
+}
+
+// fetchCensysPage requests one page of certificate search results
+// A 429 is retried up to censysMaxRetries times, waiting for Retry-After when the
+// server sends it and backing off exponentially otherwise.
+func (c *SubdomainClient) fetchCensysPage(domain, cursor string) (*models.CensysCertificateSearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", "names: "+domain)
+	params.Set("per_page", fmt.Sprintf("%d", censysPerPage))
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	reqURL := fmt.Sprintf("%s/certificates/search?%s", c.censysBaseURL, params.Encode())
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("GET", reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.SetBasicAuth(c.censysAPIID, c.censysAPISecret)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+
+		if resp.StatusCode == 429 {
+			resp.Body.Close()
+			if attempt >= censysMaxRetries {
+				return nil, fmt.Errorf("rate limited - please wait and try again")
+			}
+			backoff := retryAfterDelay(resp, time.Duration(10<<attempt)*time.Second)
+			if c.logger != nil {
+				c.logger.Warn("Censys rate limited, waiting", "backoff", backoff, "retry", attempt+1, "maxRetries", censysMaxRetries)
+			}
+			time.Sleep(backoff)
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == 401 || resp.StatusCode == 403 {
+			return nil, fmt.Errorf("invalid API credentials")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Censys returned status %d: %s", resp.StatusCode, string(body))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		var censysResp models.CensysCertificateSearchResponse
+		if err := json.Unmarshal(body, &censysResp); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		return &censysResp, nil
+	}
+}
+
+// retryAfterDelay returns the Retry-After delay in seconds, or fallback when absent or invalid
+func retryAfterDelay(resp *http.Response, fallback time.Duration) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return fallback
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// =============================================================================
+// DNS Resolution
+// =============================================================================
+
+// ResolveSubdomains performs DNS lookups for each subdomain using a bounded worker pool
+// Returns a copy of the input with ResolvedIPs/Resolves populated, plus PTRNames when
+// SetLookupPTR is on. On cancellation the records looked up so far are returned along
+// with a "cancelled" error.
+func (c *SubdomainClient) ResolveSubdomains(subdomains []models.Subdomain, concurrency int, cancel <-chan struct{}) ([]models.Subdomain, error) {
+	if concurrency <= 0 {
+		concurrency = defaultResolveConcurrency
+	}
+
+	results := make([]models.Subdomain, len(subdomains))
+	copy(results, subdomains)
+
+	done, cancelled := runSubdomainWorkers(len(results), concurrency, cancel, func(i int) {
+		ips, err := c.lookupHost(results[i].Subdomain)
+		if err != nil {
+			ips = nil
+		}
+		results[i].ResolvedIPs = strings.Join(ips, ",")
+		results[i].Resolves = len(ips) > 0
+		if c.lookupPTR {
+			results[i].PTRNames = strings.Join(c.reverseLookup(ips), ",")
+		}
+	})
+
+	if c.logger != nil {
+		c.logger.Info("Subdomain resolution finished", "count", len(results), "cancelled", cancelled)
+	}
+
+	if cancelled {
+		return completedSubdomains(results, done), fmt.Errorf("cancelled")
+	}
+
+	return results, nil
+}
+
+// reverseLookup returns the distinct PTR names of ips, without trailing dots
+// IPs with no PTR record (or a failed lookup) are skipped.
+func (c *SubdomainClient) reverseLookup(ips []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, ip := range ips {
+		ptrs, err := c.lookupAddr(ip)
+		if err != nil {
+			continue
+		}
+		for _, name := range ptrs {
+			name = strings.ToLower(strings.TrimSuffix(name, "."))
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// =============================================================================
+// HTTP Probing
+// =============================================================================
+
+// ProbeSubdomains checks each subdomain over http:// and https:// using a bounded worker pool
+// A HEAD request is tried first, falling back to GET when HEAD fails or is rejected.
+// Redirects are followed; the final status, Server header, and final URL are recorded.
+// The certificate served over https:// is recorded too, flagged when it does not cover the host.
+// timeout applies per probe request. progress is called with the number of subdomains probed so far.
+func (c *SubdomainClient) ProbeSubdomains(subdomains []models.Subdomain, timeout time.Duration, concurrency int, progress func(count int), cancel <-chan struct{}) ([]models.Subdomain, error) {
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	if concurrency <= 0 {
+		concurrency = defaultProbeConcurrency
+	}
+
+	// Liveness only - certificate validity is not our concern here, and many
+	// internal hosts serve self-signed or mismatched certificates
+	probeTransport := newTransport()
+	probeTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	probeClient := &http.Client{
+		Timeout:   timeout,
+		Transport: probeTransport,
+	}
+
+	results := make([]models.Subdomain, len(subdomains))
+	copy(results, subdomains)
+
+	var mu sync.Mutex
+	probed := 0
+
+	done, cancelled := runSubdomainWorkers(len(results), concurrency, cancel, func(i int) {
+		host := results[i].Subdomain
+
+		httpsResult := probeURL(probeClient, "https://"+host, c.userAgent)
+		httpResult := probeURL(probeClient, "http://"+host, c.userAgent)
+
+		results[i].HTTPSStatus = httpsResult.status
+		results[i].HTTPStatus = httpResult.status
+
+		// Prefer the HTTPS response for server/final URL when it answered
+		final := httpsResult
+		if final.status == 0 {
+			final = httpResult
+		}
+		results[i].ServerHeader = final.server
+		results[i].FinalURL = final.finalURL
+		if cert := httpsResult.cert; cert != nil {
+			setTLSCert(&results[i], cert)
+		}
+
+		mu.Lock()
+		probed++
+		count := probed
+		mu.Unlock()
+		if progress != nil {
+			progress(count)
+		}
+	})
+
+	if c.logger != nil {
+		c.logger.Info("Subdomain probing finished", "count", len(results), "cancelled", cancelled)
+	}
+
+	if cancelled {
+		return completedSubdomains(results, done), fmt.Errorf("cancelled")
+	}
+
+	return results, nil
+}
+
+// probeResult holds the outcome of probing a single URL
+type probeResult struct {
+	status   int
+	server   string
+	finalURL string
+	cert     *x509.Certificate // Leaf certificate served for the requested host (https only)
+}
+
+// probeURL issues a HEAD request to target, falling back to GET
+// Returns a zero status when the host did not answer at all
+func probeURL(client *http.Client, target, userAgent string) probeResult {
+	for _, method := range []string{"HEAD", "GET"} {
+		req, err := http.NewRequest(method, target, nil)
+		if err != nil {
+			return probeResult{}
+		}
+		req.Header.Set("User-Agent", userAgent)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+
+		// Some servers reject HEAD outright - retry those with GET
+		if method == "HEAD" && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+			continue
+		}
+
+		return probeResult{
+			status:   resp.StatusCode,
+			server:   resp.Header.Get("Server"),
+			finalURL: resp.Request.URL.String(),
+			cert:     firstHopCert(resp),
+		}
+	}
+	return probeResult{}
+}
+
+// firstHopCert returns the leaf certificate of the first response in a redirect chain - the
+// one served for the requested host rather than wherever redirects ended up
+func firstHopCert(resp *http.Response) *x509.Certificate {
+	for resp.Request != nil && resp.Request.Response != nil {
+		resp = resp.Request.Response
+	}
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return resp.TLS.PeerCertificates[0]
+}
+
+// setTLSCert records a probed certificate's details on a subdomain, flagging certificates
+// that do not cover its hostname
+func setTLSCert(s *models.Subdomain, cert *x509.Certificate) {
+	s.TLSSubject = cert.Subject.CommonName
+	s.TLSSANs = strings.Join(cert.DNSNames, ",")
+	s.TLSIssuer = cert.Issuer.CommonName
+	if s.TLSIssuer == "" {
+		s.TLSIssuer = cert.Issuer.String()
+	}
+	s.TLSNotBefore = cert.NotBefore.UTC()
+	s.TLSNotAfter = cert.NotAfter.UTC()
+
+	host := s.Subdomain
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	s.TLSHostMismatch = cert.VerifyHostname(host) != nil
+}
+
+// runSubdomainWorkers calls fn for each index in [0, n) from at most concurrency goroutines
+// Stops handing out work once cancel is closed. Returns which indexes completed and
+// whether the run was cancelled.
+func runSubdomainWorkers(n, concurrency int, cancel <-chan struct{}, fn func(i int)) ([]bool, bool) {
+	if concurrency > n {
+		concurrency = n
+	}
+
+	done := make([]bool, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+				done[i] = true
+			}
+		}()
+	}
+
+	cancelled := false
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case <-cancel:
+			cancelled = true
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return done, cancelled
+}
+
+// completedSubdomains returns the subset of results whose index is marked done
+func completedSubdomains(results []models.Subdomain, done []bool) []models.Subdomain {
+	partial := make([]models.Subdomain, 0, len(results))
+	for i, sd := range results {
+		if done[i] {
+			partial = append(partial, sd)
+		}
+	}
+	return partial
+}
+
 // =============================================================================
 // JSON Import
 // =============================================================================
@@ -348,15 +1155,9 @@ func (c *SubdomainClient) ImportCrtshJSON(data []byte, domain string) ([]models.
 		isExpired := !notAfter.IsZero() && notAfter.Before(now)
 
 		names := strings.Split(entry.NameValue, "\n")
-		for _, name := range names {
-			name = strings.TrimSpace(name)
-			name = strings.ToLower(name)
-
-			if name == "" || strings.HasPrefix(name, "*") {
-				continue
-			}
-
-			if !strings.HasSuffix(name, "."+domain) && name != domain {
+		for _, raw := range names {
+			name, wildcard, ok := c.normalizeCertName(raw, domain)
+			if !ok {
 				continue
 			}
 
@@ -367,11 +1168,15 @@ func (c *SubdomainClient) ImportCrtshJSON(data []byte, domain string) ([]models.
 					Subdomain:   name,
 					Source:      "import",
 					CertExpired: isExpired,
+					IsWildcard:  wildcard,
 				}
 			} else {
 				if isExpired {
 					existing.CertExpired = true
 				}
+				if !wildcard {
+					existing.IsWildcard = false
+				}
 			}
 
 			if entry.CommonName != "" && entry.CommonName != name {
@@ -400,31 +1205,210 @@ func (c *SubdomainClient) ImportPlainTextSubdomains(data []byte, domain string)
 	var subdomains []models.Subdomain
 
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		line = strings.ToLower(line)
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
+		// Skip comments
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
 			continue
 		}
 
-		// Skip if already seen
-		if subdomainMap[line] {
+		// Skip empty lines, wildcards, and names unrelated to the domain
+		name, ok := NormalizeSubdomain(line, domain)
+		if !ok {
 			continue
 		}
-		subdomainMap[line] = true
 
-		// Validate it's related to the domain
-		if !strings.HasSuffix(line, "."+domain) && line != domain {
+		// Skip if already seen
+		if subdomainMap[name] {
 			continue
 		}
+		subdomainMap[name] = true
 
 		subdomains = append(subdomains, models.Subdomain{
 			Domain:    domain,
-			Subdomain: line,
+			Subdomain: name,
 			Source:    "import",
 		})
 	}
 
 	return subdomains, nil
 }
+
+// amassRecord is one line of Amass JSON output (amass enum -json)
+type amassRecord struct {
+	Name      string `json:"name"`
+	Addresses []struct {
+		IP string `json:"ip"`
+	} `json:"addresses"`
+}
+
+// ImportAmassJSON parses Amass JSON lines output, one {"name","addresses"} object per line.
+// Addresses are kept as the resolved IPs; names outside domain are skipped.
+func (c *SubdomainClient) ImportAmassJSON(data []byte, domain string) ([]models.Subdomain, error) {
+	byName := make(map[string]*models.Subdomain)
+	var order []string
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var rec amassRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse Amass JSON line %d: %w", i+1, err)
+		}
+		name, ok := NormalizeSubdomain(rec.Name, domain)
+		if !ok {
+			continue
+		}
+
+		sd, ok := byName[name]
+		if !ok {
+			sd = &models.Subdomain{Domain: domain, Subdomain: name, Source: "amass"}
+			byName[name] = sd
+			order = append(order, name)
+		}
+		for _, addr := range rec.Addresses {
+			addResolvedIP(sd, addr.IP)
+		}
+	}
+
+	return orderedSubdomains(byName, order), nil
+}
+
+// subfinderRecord is one line of Subfinder JSON output (subfinder -oJ)
+type subfinderRecord struct {
+	Host   string `json:"host"`
+	Source string `json:"source"`
+	IP     string `json:"ip"` // Only present when run with -active -ip
+}
+
+// ImportSubfinderJSON parses Subfinder JSON lines output, one {"host","source"} object per line.
+// A host reported by several of Subfinder's sources is imported once; names outside domain are skipped.
+func (c *SubdomainClient) ImportSubfinderJSON(data []byte, domain string) ([]models.Subdomain, error) {
+	byName := make(map[string]*models.Subdomain)
+	var order []string
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var rec subfinderRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			return nil, fmt.Errorf("failed to parse Subfinder JSON line %d: %w", i+1, err)
+		}
+		name, ok := NormalizeSubdomain(rec.Host, domain)
+		if !ok {
+			continue
+		}
+
+		sd, ok := byName[name]
+		if !ok {
+			sd = &models.Subdomain{Domain: domain, Subdomain: name, Source: "subfinder"}
+			byName[name] = sd
+			order = append(order, name)
+		}
+		addResolvedIP(sd, rec.IP)
+	}
+
+	return orderedSubdomains(byName, order), nil
+}
+
+// addResolvedIP appends ip to the subdomain's resolved IPs unless empty or already listed
+func addResolvedIP(sd *models.Subdomain, ip string) {
+	ip = strings.TrimSpace(ip)
+	if ip == "" || slices.Contains(strings.Split(sd.ResolvedIPs, ","), ip) {
+		return
+	}
+	if sd.ResolvedIPs == "" {
+		sd.ResolvedIPs = ip
+	} else {
+		sd.ResolvedIPs += "," + ip
+	}
+	sd.Resolves = true
+}
+
+// orderedSubdomains returns the collected subdomains in first-seen order
+func orderedSubdomains(byName map[string]*models.Subdomain, order []string) []models.Subdomain {
+	subdomains := make([]models.Subdomain, 0, len(order))
+	for _, name := range order {
+		subdomains = append(subdomains, *byName[name])
+	}
+	return subdomains
+}
+
+// Formats ImportSubdomains can detect
+const (
+	ImportFormatCrtsh      = "crtsh"
+	ImportFormatVirusTotal = "virustotal"
+	ImportFormatAmass      = "amass"
+	ImportFormatSubfinder  = "subfinder"
+	ImportFormatPlainText  = "text"
+)
+
+// ImportSubdomains sniffs the format of an export file and parses it with the matching
+// importer: a crt.sh JSON array (objects with name_value), a VirusTotal response (data[] of
+// type "domain") or array of names, Amass or Subfinder JSON lines, or else plain text.
+// Returns the detected format alongside the subdomains.
+func (c *SubdomainClient) ImportSubdomains(data []byte, domain string) (string, []models.Subdomain, error) {
+	// Editors on Windows like to prefix a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	format := DetectImportFormat(data)
+	var subdomains []models.Subdomain
+	var err error
+	switch format {
+	case ImportFormatCrtsh:
+		subdomains, err = c.ImportCrtshJSON(data, domain)
+	case ImportFormatVirusTotal:
+		subdomains, err = c.ImportVirusTotalJSON(data, domain)
+	case ImportFormatAmass:
+		subdomains, err = c.ImportAmassJSON(data, domain)
+	case ImportFormatSubfinder:
+		subdomains, err = c.ImportSubfinderJSON(data, domain)
+	default:
+		subdomains, err = c.ImportPlainTextSubdomains(data, domain)
+	}
+	return format, subdomains, err
+}
+
+// DetectImportFormat guesses which importer an export file needs, defaulting to plain text
+func DetectImportFormat(data []byte) string {
+	trimmed := strings.TrimSpace(string(data))
+
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		var entries []map[string]json.RawMessage
+		if json.Unmarshal([]byte(trimmed), &entries) == nil {
+			if len(entries) > 0 && entries[0]["name_value"] != nil {
+				return ImportFormatCrtsh
+			}
+			return ImportFormatPlainText
+		}
+		var names []string
+		if json.Unmarshal([]byte(trimmed), &names) == nil {
+			return ImportFormatVirusTotal
+		}
+
+	case strings.HasPrefix(trimmed, "{"):
+		var vtResp models.VirusTotalSubdomainResponse
+		if json.Unmarshal([]byte(trimmed), &vtResp) == nil {
+			for _, item := range vtResp.Data {
+				if item.Type == "domain" {
+					return ImportFormatVirusTotal
+				}
+			}
+		}
+		// JSON lines: the first record's keys name the tool
+		first, _, _ := strings.Cut(trimmed, "\n")
+		var record map[string]json.RawMessage
+		if json.Unmarshal([]byte(first), &record) == nil {
+			switch {
+			case record["name"] != nil:
+				return ImportFormatAmass
+			case record["host"] != nil:
+				return ImportFormatSubfinder
+			}
+		}
+	}
+
+	return ImportFormatPlainText
+}
@@ -0,0 +1,513 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// TestNormalizeSubdomain verifies hostname canonicalization and rejection rules
+func TestNormalizeSubdomain(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		parent string
+		want   string
+		wantOK bool
+	}{
+		{"lowercase", "API.Example.COM", "example.com", "api.example.com", true},
+		{"whitespace", "  www.example.com \r", "example.com", "www.example.com", true},
+		{"trailing dot", "x.example.com.", "example.com", "x.example.com", true},
+		{"parent itself", "example.com", "example.com", "example.com", true},
+		{"parent with trailing dot", "a.example.com", "Example.com.", "a.example.com", true},
+		{"wildcard", "*.x", "example.com", "", false},
+		{"wildcard under parent", "*.example.com", "example.com", "", false},
+		{"unrelated domain", "www.other.com", "example.com", "", false},
+		{"suffix without dot", "badexample.com", "example.com", "", false},
+		{"empty", "   ", "example.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeSubdomain(tt.raw, tt.parent)
+			if ok != tt.wantOK {
+				t.Errorf("NormalizeSubdomain(%q, %q) ok = %v, want %v", tt.raw, tt.parent, ok, tt.wantOK)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeSubdomain(%q, %q) = %q, want %q", tt.raw, tt.parent, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestImportCrtshJSONWildcards verifies wildcard handling with the option off and on
+func TestImportCrtshJSONWildcards(t *testing.T) {
+	data := []byte(`[
+		{"common_name": "*.dev.example.com", "name_value": "*.dev.example.com\nwww.example.com", "not_after": "2099-01-01T00:00:00"},
+		{"common_name": "*.example.com", "name_value": "*.example.com\n*.other.com", "not_after": "2099-01-01T00:00:00"}
+	]`)
+
+	byName := func(subdomains []models.Subdomain) map[string]models.Subdomain {
+		m := make(map[string]models.Subdomain)
+		for _, s := range subdomains {
+			m[s.Subdomain] = s
+		}
+		return m
+	}
+
+	t.Run("off", func(t *testing.T) {
+		client := NewSubdomainClient("", nil)
+		subdomains, err := client.ImportCrtshJSON(data, "example.com")
+		if err != nil {
+			t.Fatalf("ImportCrtshJSON() error = %v", err)
+		}
+		got := byName(subdomains)
+		if len(got) != 1 {
+			t.Fatalf("ImportCrtshJSON() returned %d records, want 1: %v", len(got), got)
+		}
+		if sd, ok := got["www.example.com"]; !ok || sd.IsWildcard {
+			t.Errorf("www.example.com = %+v, want non-wildcard record", sd)
+		}
+	})
+
+	t.Run("on", func(t *testing.T) {
+		client := NewSubdomainClient("", nil)
+		client.SetIncludeWildcards(true)
+		subdomains, err := client.ImportCrtshJSON(data, "example.com")
+		if err != nil {
+			t.Fatalf("ImportCrtshJSON() error = %v", err)
+		}
+		got := byName(subdomains)
+		want := map[string]bool{
+			"www.example.com": false,
+			"dev.example.com": true,
+			"example.com":     true,
+		}
+		if len(got) != len(want) {
+			t.Fatalf("ImportCrtshJSON() returned %d records, want %d: %v", len(got), len(want), got)
+		}
+		for name, wantWildcard := range want {
+			sd, ok := got[name]
+			if !ok {
+				t.Errorf("missing record for %s", name)
+				continue
+			}
+			if sd.IsWildcard != wantWildcard {
+				t.Errorf("%s IsWildcard = %v, want %v", name, sd.IsWildcard, wantWildcard)
+			}
+		}
+	})
+}
+
+// TestImportToolJSON verifies the Amass and Subfinder JSON lines importers against fixtures
+func TestImportToolJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		source string
+		want   map[string]string // Subdomain -> resolved IPs
+	}{
+		{"amass", "amass.jsonl", "amass", map[string]string{
+			"www.example.com": "93.184.216.34",
+			"api.example.com": "10.0.0.1,2001:db8::1,10.0.0.2",
+			"dev.example.com": "",
+		}},
+		{"subfinder", "subfinder.jsonl", "subfinder", map[string]string{
+			"www.example.com": "",
+			"vpn.example.com": "198.51.100.7",
+		}},
+	}
+
+	client := NewSubdomainClient("", nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			importer := client.ImportAmassJSON
+			if tt.name == "subfinder" {
+				importer = client.ImportSubfinderJSON
+			}
+			subdomains, err := importer(data, "example.com")
+			if err != nil {
+				t.Fatalf("import error = %v", err)
+			}
+			if len(subdomains) != len(tt.want) {
+				t.Fatalf("imported %d records, want %d: %+v", len(subdomains), len(tt.want), subdomains)
+			}
+			for _, sd := range subdomains {
+				ips, ok := tt.want[sd.Subdomain]
+				if !ok {
+					t.Errorf("unexpected record %s", sd.Subdomain)
+					continue
+				}
+				if sd.Domain != "example.com" || sd.Source != tt.source {
+					t.Errorf("%s domain/source = %s/%s", sd.Subdomain, sd.Domain, sd.Source)
+				}
+				if sd.ResolvedIPs != ips || sd.Resolves != (ips != "") {
+					t.Errorf("%s resolved = %q (%v), want %q", sd.Subdomain, sd.ResolvedIPs, sd.Resolves, ips)
+				}
+			}
+		})
+	}
+
+	if _, err := client.ImportSubfinderJSON([]byte("www.example.com\n"), "example.com"); err == nil {
+		t.Error("ImportSubfinderJSON accepted plain text")
+	}
+}
+
+// TestImportSubdomainsDetectsFormat verifies the import dispatcher picks the right parser
+func TestImportSubdomainsDetectsFormat(t *testing.T) {
+	fixture := func(name string) string {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		format string
+		want   int
+	}{
+		{"crt.sh", `[{"common_name": "www.example.com", "name_value": "www.example.com\napi.example.com", "not_after": "2099-01-01T00:00:00"}]`, ImportFormatCrtsh, 2},
+		{"virustotal response", `{"data": [{"id": "www.example.com", "type": "domain"}], "links": {}}`, ImportFormatVirusTotal, 1},
+		{"virustotal names", `["www.example.com", "api.example.com"]`, ImportFormatVirusTotal, 2},
+		{"amass", fixture("amass.jsonl"), ImportFormatAmass, 3},
+		{"subfinder", fixture("subfinder.jsonl"), ImportFormatSubfinder, 2},
+		{"plain text", "# hosts\nwww.example.com\napi.example.com\n", ImportFormatPlainText, 2},
+		{"byte order mark", "\ufeffwww.example.com\n", ImportFormatPlainText, 1},
+		{"unknown JSON falls back", `{"hostname": "www.example.com"}`, ImportFormatPlainText, 0},
+	}
+
+	client := NewSubdomainClient("", nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, subdomains, err := client.ImportSubdomains([]byte(tt.data), "example.com")
+			if err != nil {
+				t.Fatalf("ImportSubdomains() error = %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+			if len(subdomains) != tt.want {
+				t.Errorf("imported %d records, want %d: %+v", len(subdomains), tt.want, subdomains)
+			}
+			for _, sd := range subdomains {
+				if !strings.HasSuffix(sd.Subdomain, "example.com") || strings.ContainsRune(sd.Subdomain, '\ufeff') {
+					t.Errorf("bad subdomain %q", sd.Subdomain)
+				}
+			}
+		})
+	}
+}
+
+// TestFetchCrtshSubdomainsCancel verifies a cancelled context aborts an in-flight request
+func TestFetchCrtshSubdomainsCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client goes away (or the test finishes)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewSubdomainClient("", nil)
+	client.crtshBaseURL = server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.FetchCrtshSubdomains(ctx, "example.com")
+	elapsed := time.Since(start)
+
+	if err == nil || err.Error() != "cancelled" {
+		t.Errorf("FetchCrtshSubdomains() error = %v, want cancelled", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("FetchCrtshSubdomains() took %v after cancel, want prompt return", elapsed)
+	}
+}
+
+// TestFetchCrtshSubdomainsMulti verifies results from several domains are merged, a failing
+// domain is reported without losing the others, and cancelling skips what hasn't run
+func TestFetchCrtshSubdomainsMulti(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// q=%.<domain> isn't valid query escaping, so read it from the raw query
+		domain := strings.TrimSuffix(strings.TrimPrefix(r.URL.RawQuery, "q=%."), "&output=json")
+		if domain == "bad.com" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `[{"common_name":"www.%s","name_value":"www.%s\napi.%s"}]`, domain, domain, domain)
+	}))
+	defer server.Close()
+
+	client := NewSubdomainClient("", nil)
+	client.crtshBaseURL = server.URL
+
+	subdomains, errs := client.FetchCrtshSubdomainsMulti(context.Background(), []string{"a.com", "bad.com", "b.com"}, 2)
+	if len(subdomains) != 4 {
+		t.Errorf("got %d subdomains, want 4 (two per good domain)", len(subdomains))
+	}
+	for _, sub := range subdomains {
+		if !strings.HasSuffix(sub.Subdomain, "."+sub.Domain) {
+			t.Errorf("subdomain %s filed under %s", sub.Subdomain, sub.Domain)
+		}
+	}
+	if len(errs) != 1 || errs["bad.com"] == nil {
+		t.Errorf("errs = %v, want only bad.com", errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	subdomains, errs = client.FetchCrtshSubdomainsMulti(ctx, []string{"a.com", "b.com"}, 1)
+	if len(subdomains) != 0 || len(errs) != 2 {
+		t.Errorf("cancelled: got %d subdomains and errs %v, want none and both domains failed", len(subdomains), errs)
+	}
+}
+
+// TestFetchCrtshSubdomainsUnavailable verifies 502/503 responses are retried and an HTML
+// error page is reported as crt.sh being unavailable rather than a JSON parse error
+func TestFetchCrtshSubdomainsUnavailable(t *testing.T) {
+	const errorPage = `<!DOCTYPE html><html><head><title>crt.sh</title></head><body>
+<h1>crt.sh</h1><p>Sorry, something went wrong... ERROR: canceling statement due to statement timeout</p></body></html>`
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, errorPage)
+		}
+	}))
+	defer server.Close()
+
+	client := NewSubdomainClient("", nil)
+	client.crtshBaseURL = server.URL
+	client.crtshRetryDelay = time.Millisecond
+
+	_, err := client.FetchCrtshSubdomains(context.Background(), "example.com")
+	if !errors.Is(err, ErrCrtshUnavailable) {
+		t.Fatalf("FetchCrtshSubdomains() error = %v, want ErrCrtshUnavailable", err)
+	}
+	if !strings.Contains(err.Error(), "statement timeout") || strings.Contains(err.Error(), "<") {
+		t.Errorf("error = %q, want the page's message without markup", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 (two retries)", requests)
+	}
+
+	// A crt.sh that stays overloaded gives up after crtshMaxRetries
+	requests = 0
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	if _, err := client.FetchCrtshSubdomains(context.Background(), "example.com"); !errors.Is(err, ErrCrtshUnavailable) || requests != crtshMaxRetries+1 {
+		t.Errorf("persistent 503: error = %v after %d requests", err, requests)
+	}
+}
+
+// TestResolveSubdomainsPTR verifies PTR names are looked up for resolved IPs only when enabled,
+// deduplicated across IPs, and that the resolved IPs group into their networks
+func TestResolveSubdomainsPTR(t *testing.T) {
+	client := NewSubdomainClient("", nil)
+	client.lookupHost = func(host string) ([]string, error) {
+		switch host {
+		case "api.example.com":
+			return []string{"203.0.113.10", "203.0.113.11", "2001:db8:1:2::1"}, nil
+		default:
+			return nil, fmt.Errorf("no such host")
+		}
+	}
+	ptrLookups := 0
+	client.lookupAddr = func(addr string) ([]string, error) {
+		ptrLookups++
+		if strings.HasPrefix(addr, "2001:") {
+			return nil, fmt.Errorf("no PTR")
+		}
+		return []string{"Shared-Host.Provider.net."}, nil
+	}
+	subs := []models.Subdomain{{Subdomain: "api.example.com"}, {Subdomain: "gone.example.com"}}
+
+	resolved, err := client.ResolveSubdomains(subs, 2, nil)
+	if err != nil || ptrLookups != 0 || resolved[0].PTRNames != "" {
+		t.Fatalf("PTR off: %d lookups, %+v, %v", ptrLookups, resolved, err)
+	}
+
+	client.SetLookupPTR(true)
+	resolved, err = client.ResolveSubdomains(subs, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved[0].PTRNames != "shared-host.provider.net" || ptrLookups != 3 {
+		t.Errorf("api PTR = %q after %d lookups", resolved[0].PTRNames, ptrLookups)
+	}
+	if resolved[1].Resolves || resolved[1].PTRNames != "" {
+		t.Errorf("unresolved host = %+v", resolved[1])
+	}
+	if got := strings.Join(resolved[0].Networks(), " "); got != "203.0.113.0/24 2001:db8:1::/48" {
+		t.Errorf("Networks() = %q", got)
+	}
+}
+
+// TestProbeSubdomainsTLS verifies the certificate served over https:// is captured and that a
+// certificate not covering the probed host is flagged
+func TestProbeSubdomainsTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "test")
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "https://"))
+
+	// The test certificate covers example.com and 127.0.0.1, but not localhost
+	subs := []models.Subdomain{{Subdomain: "127.0.0.1:" + port}, {Subdomain: "localhost:" + port}}
+	probed, err := NewSubdomainClient("", nil).ProbeSubdomains(subs, 5*time.Second, 2, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := server.Certificate()
+	for i, wantMismatch := range []bool{false, true} {
+		p := probed[i]
+		if p.HTTPSStatus != http.StatusOK || !p.HasTLSCert() {
+			t.Fatalf("%s: status %d, cert %v", p.Subdomain, p.HTTPSStatus, p.HasTLSCert())
+		}
+		if p.TLSSANs != strings.Join(cert.DNSNames, ",") || p.TLSIssuer != cert.Issuer.String() ||
+			!p.TLSNotAfter.Equal(cert.NotAfter) || !p.TLSNotBefore.Equal(cert.NotBefore) {
+			t.Errorf("%s: cert = %q %q %v-%v", p.Subdomain, p.TLSSANs, p.TLSIssuer, p.TLSNotBefore, p.TLSNotAfter)
+		}
+		if p.TLSHostMismatch != wantMismatch {
+			t.Errorf("%s: TLSHostMismatch = %v, want %v", p.Subdomain, p.TLSHostMismatch, wantMismatch)
+		}
+	}
+}
+
+// TestFetchCensysSubdomains verifies SAN extraction, cursor pagination and 429 retry
+func TestFetchCensysSubdomains(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case requests == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Query().Get("cursor") == "":
+			fmt.Fprint(w, `{"code":200,"result":{"hits":[
+				{"names":["api.example.com","*.dev.example.com","other.org"]}
+			],"links":{"next":"page2"}}}`)
+		default:
+			fmt.Fprint(w, `{"code":200,"result":{"hits":[
+				{"names":["API.example.com."],"parsed":{"extensions":{"subject_alt_name":{"dns_names":["www.example.com"]}}}}
+			],"links":{"next":""}}}`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewSubdomainClient("", nil)
+	client.censysBaseURL = server.URL
+	if _, err := client.FetchCensysSubdomains("example.com"); err == nil {
+		t.Fatal("FetchCensysSubdomains() without credentials returned nil error")
+	}
+
+	client.SetCensysCredentials("id", "secret")
+	subdomains, err := client.FetchCensysSubdomains("example.com")
+	if err != nil {
+		t.Fatalf("FetchCensysSubdomains() error = %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 (429, page 1, page 2)", requests)
+	}
+
+	var got []string
+	for _, sd := range subdomains {
+		if sd.Source != "censys" {
+			t.Errorf("%s Source = %q, want censys", sd.Subdomain, sd.Source)
+		}
+		got = append(got, sd.Subdomain)
+	}
+	want := []string{"api.example.com", "www.example.com"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("subdomains = %v, want %v", got, want)
+	}
+}
+
+// TestFetchAllVirusTotalSubdomainsPacing verifies the page delay follows the X-RateLimit-*
+// headers when present, falls back to the configured delay otherwise, and reaches progress
+func TestFetchAllVirusTotalSubdomainsPacing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("X-RateLimit-Remaining", "20")
+			w.Header().Set("X-RateLimit-Reset", "1")
+			fmt.Fprint(w, `{"data":[{"id":"api.example.com","type":"domain"}],"meta":{"cursor":"page2"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[{"id":"www.example.com","type":"domain"}],"meta":{"cursor":""}}`)
+	}))
+	defer server.Close()
+
+	client := NewSubdomainClient("key", nil)
+	client.vtBaseURL = server.URL
+	client.SetVirusTotalPageDelay(time.Millisecond)
+
+	var updates []VTProgress
+	subdomains, cursor, err := client.FetchAllVirusTotalSubdomainsWithResume(context.Background(), "example.com", "", func(p VTProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil || cursor != "" {
+		t.Fatalf("FetchAllVirusTotalSubdomainsWithResume() = cursor %q, error %v", cursor, err)
+	}
+	if len(subdomains) != 2 || len(updates) != 2 {
+		t.Fatalf("got %d subdomains and %d progress updates, want 2 and 2", len(subdomains), len(updates))
+	}
+
+	// 20 requests left over a 1s window spreads to 50ms per page
+	if p := updates[0]; p.Delay != 50*time.Millisecond || p.Remaining != 20 || p.Pages != 1 || p.Subdomains != 1 {
+		t.Errorf("first page progress = %+v, want 50ms delay with 20 remaining", p)
+	}
+	if p := updates[1]; p.Delay != time.Millisecond || p.Remaining != -1 || p.Pages != 2 || p.PagesPerMinute <= 0 {
+		t.Errorf("second page progress = %+v, want the configured 1ms delay and a positive rate", p)
+	}
+
+	// An exhausted quota waits out the reset, capped so a daily limit fails fast
+	if d := client.pageDelay(vtRateLimit{remaining: 0, reset: 3 * time.Second}); d != 3*time.Second {
+		t.Errorf("pageDelay(exhausted) = %v, want 3s", d)
+	}
+	if d := client.pageDelay(vtRateLimit{remaining: 0, reset: 24 * time.Hour}); d != vtMaxPageDelay {
+		t.Errorf("pageDelay(daily reset) = %v, want %v", d, vtMaxPageDelay)
+	}
+
+	client.SetVirusTotalPageDelay(0)
+	if d := client.pageDelay(vtRateLimit{remaining: -1}); d != vtDefaultPageDelay {
+		t.Errorf("pageDelay(no headers) after reset = %v, want %v", d, vtDefaultPageDelay)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Version is the gitsome-ng version reported in DefaultUserAgent
+const Version = "1.0"
+
+// DefaultUserAgent identifies gitsome-ng to the APIs it queries
+const DefaultUserAgent = "gitsome-ng/" + Version
+
+// UserAgentEnvVar overrides the User-Agent of every client, e.g. to follow an engagement's
+// rules for identifying traffic
+const UserAgentEnvVar = "GITSOME_USER_AGENT"
+
+// BrowserUserAgent is a desktop browser User-Agent for services that throttle unknown clients
+// (see SubdomainClient.SetCrtshBrowserUserAgent)
+const BrowserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+// CrtshBrowserUserAgentEnvVar, when set to a true value ("1", "true"), makes new subdomain
+// clients send BrowserUserAgent to crt.sh
+const CrtshBrowserUserAgentEnvVar = "GITSOME_CRTSH_BROWSER_UA"
+
+// defaultUserAgent returns the User-Agent new clients start with: GITSOME_USER_AGENT when set,
+// otherwise DefaultUserAgent
+func defaultUserAgent() string {
+	if ua := strings.TrimSpace(os.Getenv(UserAgentEnvVar)); ua != "" {
+		return ua
+	}
+	return DefaultUserAgent
+}
+
+// crtshBrowserUserAgentFromEnv reports whether GITSOME_CRTSH_BROWSER_UA asks for the browser
+// User-Agent on crt.sh
+func crtshBrowserUserAgentFromEnv() bool {
+	browser, _ := strconv.ParseBool(os.Getenv(CrtshBrowserUserAgentEnvVar))
+	return browser
+}
+
+// resolveUserAgent returns ua, or the default when ua is empty
+func resolveUserAgent(ua string) string {
+	if ua = strings.TrimSpace(ua); ua != "" {
+		return ua
+	}
+	return defaultUserAgent()
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUserAgent verifies clients identify as gitsome-ng by default, honor GITSOME_USER_AGENT
+// and setters, and that crt.sh can still be sent a browser User-Agent
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	crtsh := func(client *SubdomainClient) string {
+		t.Helper()
+		client.crtshBaseURL = server.URL
+		if _, err := client.FetchCrtshSubdomains(context.Background(), "example.com"); err != nil {
+			t.Fatalf("FetchCrtshSubdomains() error = %v", err)
+		}
+		return got
+	}
+	github := func(client *Client) string {
+		t.Helper()
+		client.restURL = server.URL
+		if _, err := client.FetchCommits("o", "r", "", nil); err != nil {
+			t.Fatalf("FetchCommits() error = %v", err)
+		}
+		return got
+	}
+
+	if ua := crtsh(NewSubdomainClient("", nil)); ua != DefaultUserAgent {
+		t.Errorf("crt.sh User-Agent = %q, want %q", ua, DefaultUserAgent)
+	}
+	browser := NewSubdomainClient("", nil)
+	browser.SetCrtshBrowserUserAgent(true)
+	if ua := crtsh(browser); ua != BrowserUserAgent {
+		t.Errorf("crt.sh User-Agent with browser option = %q, want %q", ua, BrowserUserAgent)
+	}
+
+	t.Setenv(UserAgentEnvVar, "engagement-42 (ops@example.com)")
+	t.Setenv(CrtshBrowserUserAgentEnvVar, "true")
+	if ua := github(NewClient("")); ua != "engagement-42 (ops@example.com)" {
+		t.Errorf("GitHub User-Agent from %s = %q", UserAgentEnvVar, ua)
+	}
+	if ua := crtsh(NewSubdomainClient("", nil)); ua != BrowserUserAgent {
+		t.Errorf("crt.sh User-Agent with %s = %q, want the browser one", CrtshBrowserUserAgentEnvVar, ua)
+	}
+
+	client := NewClient("")
+	client.SetUserAgent("custom/2.0")
+	if ua := github(client); ua != "custom/2.0" {
+		t.Errorf("GitHub User-Agent after SetUserAgent = %q", ua)
+	}
+	client.SetUserAgent("")
+	if ua := github(client); ua != "engagement-42 (ops@example.com)" {
+		t.Errorf("GitHub User-Agent after SetUserAgent(\"\") = %q, want the default", ua)
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 	"golang.org/x/net/publicsuffix"
 )
@@ -27,18 +29,26 @@ const (
 type WaybackClient struct {
 	httpClient *http.Client
 	logger     *log.Logger
+	userAgent  string
 }
 
-// NewWaybackClient creates a new Wayback Machine API client
+// NewWaybackClient creates a new Wayback Machine API client; a nil logger uses the shared one
 func NewWaybackClient(logger *log.Logger) *WaybackClient {
+	if logger == nil {
+		logger = logging.WithPrefix("Wayback")
+	}
 	return &WaybackClient{
-		httpClient: &http.Client{
-			Timeout: cdxTimeout,
-		},
-		logger: logger,
+		httpClient: newHTTPClient(cdxTimeout),
+		logger:     logger,
+		userAgent:  defaultUserAgent(),
 	}
 }
 
+// SetUserAgent sets the User-Agent sent with every request; empty restores the default
+func (c *WaybackClient) SetUserAgent(ua string) {
+	c.userAgent = resolveUserAgent(ua)
+}
+
 // ExtractRootDomain extracts the root domain from a URL or hostname
 // Uses publicsuffix to handle complex TLDs like .co.uk
 // Examples:
@@ -72,10 +82,108 @@ func ExtractRootDomain(input string) (string, error) {
 	return rootDomain, nil
 }
 
+// CDXFilter narrows CDX results by HTTP status code and MIME type
+// Each list is OR-ed; the include and exclude lists are AND-ed together.
+// Status codes may use "x" as a wildcard digit (e.g. "3xx").
+type CDXFilter struct {
+	StatusCodes        []string // Keep only these status codes (e.g. "200")
+	ExcludeStatusCodes []string // Drop these status codes (e.g. "404", "301")
+	MimeTypes          []string // Keep only these MIME types (e.g. "text/html")
+	ExcludeMimeTypes   []string // Drop these MIME types
+
+	// CountCaptures fetches every capture instead of letting the server collapse by urlkey,
+	// then collapses each page client-side with CollapseByURL so captures per URL are counted
+	CountCaptures bool
+}
+
+// IsEmpty reports whether the filter matches every record (CountCaptures does not filter)
+func (f CDXFilter) IsEmpty() bool {
+	return len(f.StatusCodes) == 0 && len(f.ExcludeStatusCodes) == 0 && len(f.MimeTypes) == 0 && len(f.ExcludeMimeTypes) == 0
+}
+
+// ParseCDXFilter builds a filter from comma-separated status codes and MIME types
+// Values prefixed with "!" are excluded, e.g. status "200,3xx" or "!404,!301".
+func ParseCDXFilter(status, mime string) (CDXFilter, error) {
+	var f CDXFilter
+	f.StatusCodes, f.ExcludeStatusCodes = splitFilterList(status)
+	for _, code := range append(append([]string{}, f.StatusCodes...), f.ExcludeStatusCodes...) {
+		if !isStatusPattern(code) {
+			return CDXFilter{}, fmt.Errorf("invalid status code %q (use e.g. 200, 3xx, !404)", code)
+		}
+	}
+	f.MimeTypes, f.ExcludeMimeTypes = splitFilterList(mime)
+	return f, nil
+}
+
+// splitFilterList splits comma-separated values into included and excluded ("!"-prefixed) lists
+func splitFilterList(text string) (include, exclude []string) {
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "!") {
+			if v := strings.TrimSpace(part[1:]); v != "" {
+				exclude = append(exclude, v)
+			}
+		} else if part != "" {
+			include = append(include, part)
+		}
+	}
+	return include, exclude
+}
+
+// isStatusPattern reports whether code is a three-digit status, with "x" allowed as a wildcard digit
+func isStatusPattern(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range strings.ToLower(code) {
+		if (c < '0' || c > '9') && c != 'x' {
+			return false
+		}
+	}
+	return true
+}
+
+// params returns the CDX filter= values, already query-escaped
+// The CDX server matches the regex against the whole field, so "3.." matches any 3xx.
+func (f CDXFilter) params() []string {
+	var params []string
+	add := func(field string, values []string, exclude bool, toRegex func(string) string) {
+		if len(values) == 0 {
+			return
+		}
+		patterns := make([]string, len(values))
+		for i, v := range values {
+			patterns[i] = toRegex(v)
+		}
+		pattern := patterns[0]
+		if len(patterns) > 1 {
+			pattern = "(" + strings.Join(patterns, "|") + ")"
+		}
+		prefix := ""
+		if exclude {
+			prefix = "!"
+		}
+		params = append(params, url.QueryEscape(prefix+field+":"+pattern))
+	}
+
+	statusRegex := func(code string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(code)), "x", ".")
+	}
+	mimeRegex := func(mime string) string {
+		return regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(mime)))
+	}
+
+	add("statuscode", f.StatusCodes, false, statusRegex)
+	add("statuscode", f.ExcludeStatusCodes, true, statusRegex)
+	add("mimetype", f.MimeTypes, false, mimeRegex)
+	add("mimetype", f.ExcludeMimeTypes, true, mimeRegex)
+	return params
+}
+
 // BuildCDXQuery constructs the raw query string for CDX API
 // Returns the query string WITHOUT the leading '?'
 // The asterisk wildcard must NOT be URL-encoded for the CDX API
-func BuildCDXQuery(domain string, resumeKey string) string {
+func BuildCDXQuery(domain string, resumeKey string, filter CDXFilter) string {
 	// Clean domain: lowercase and trim whitespace
 	domain = strings.ToLower(strings.TrimSpace(domain))
 
@@ -84,11 +192,22 @@ func BuildCDXQuery(domain string, resumeKey string) string {
 	// Note: Cannot combine *.domain/* - only one wildcard type allowed
 	// IMPORTANT: The asterisk must remain literal (not encoded as %2A)
 	query := fmt.Sprintf(
-		"url=*.%s&output=json&fl=original,timestamp,statuscode,mimetype&collapse=urlkey&limit=%d&showResumeKey=true",
+		"url=*.%s&output=json&fl=original,timestamp,statuscode,mimetype&limit=%d&showResumeKey=true",
 		domain,
 		cdxBatchSize,
 	)
 
+	// collapse=urlkey keeps one capture per URL server-side; counting captures needs them all
+	if !filter.CountCaptures {
+		query += "&collapse=urlkey"
+	}
+
+	// Filters apply server-side, so a page may hold fewer than cdxBatchSize records
+	// (even none) while the resume key still points at the next page
+	for _, param := range filter.params() {
+		query += "&filter=" + param
+	}
+
 	if resumeKey != "" {
 		query += "&resumeKey=" + url.QueryEscape(resumeKey)
 	}
@@ -136,7 +255,7 @@ func (c *WaybackClient) GetCDXRecordCount(domain string) (int, error) {
 		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "text/plain, */*")
 
 	resp, err := c.httpClient.Do(req)
@@ -179,8 +298,7 @@ func (c *WaybackClient) FetchLatestCDX(targetURL string) (*models.CDXRecord, err
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers emulating a real browser
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Referer", "https://web.archive.org/")
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
@@ -255,9 +373,9 @@ func (c *WaybackClient) FetchLatestCDX(targetURL string) (*models.CDXRecord, err
 
 // FetchCDX fetches CDX records for a domain with pagination support
 // Returns records, resume key for next page, and any error
-func (c *WaybackClient) FetchCDX(domain string, resumeKey string) (*models.CDXResponse, error) {
+func (c *WaybackClient) FetchCDX(domain string, resumeKey string, filter CDXFilter) (*models.CDXResponse, error) {
 	// Build raw URL string with literal asterisk - DO NOT use url.URL as it encodes the asterisk
-	rawURL := "https://web.archive.org/cdx/search/cdx?" + BuildCDXQuery(domain, resumeKey)
+	rawURL := "https://web.archive.org/cdx/search/cdx?" + BuildCDXQuery(domain, resumeKey, filter)
 
 	// Create request with raw URL string
 	req, err := http.NewRequest("GET", rawURL, nil)
@@ -265,8 +383,7 @@ func (c *WaybackClient) FetchCDX(domain string, resumeKey string) (*models.CDXRe
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set headers emulating a real browser
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Referer", "https://web.archive.org/")
 	req.Header.Set("Accept", "application/json, text/plain, */*")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
@@ -304,7 +421,44 @@ func (c *WaybackClient) FetchCDX(domain string, resumeKey string) (*models.CDXRe
 	}
 
 	// Parse JSON response
-	return c.parseCDXResponse(body, domain)
+	cdxResp, err := c.parseCDXResponse(body, domain)
+	if err != nil {
+		return nil, err
+	}
+	if filter.CountCaptures {
+		cdxResp.Records = CollapseByURL(cdxResp.Records)
+	}
+	return cdxResp, nil
+}
+
+// CollapseByURL deduplicates captures by normalized URL, keeping the most recent capture of each
+// Captures on the kept record counts how many captures were collapsed into it. Order follows each
+// URL's first appearance.
+func CollapseByURL(records []models.CDXRecord) []models.CDXRecord {
+	index := make(map[string]int)
+	var collapsed []models.CDXRecord
+	for _, r := range records {
+		captures := r.Captures
+		if captures == 0 {
+			captures = 1
+		}
+
+		key := models.NormalizeCDXURL(r.URL)
+		i, seen := index[key]
+		if !seen {
+			r.Captures = captures
+			index[key] = len(collapsed)
+			collapsed = append(collapsed, r)
+			continue
+		}
+
+		total := collapsed[i].Captures + captures
+		if r.Timestamp > collapsed[i].Timestamp {
+			collapsed[i] = r
+		}
+		collapsed[i].Captures = total
+	}
+	return collapsed
 }
 
 // parseCDXResponse parses the CDX JSON response
@@ -393,15 +547,15 @@ type BatchCallback func(batch []models.CDXRecord, resumeKey string, totalCount,
 // Calls the progress callback with (current count, page number) after each page
 // Returns early if cancelled via the cancel channel
 // On rate limiting (503), returns partial results with nil error
-func (c *WaybackClient) FetchAllCDX(domain string, progress func(count, page int), cancel <-chan struct{}) ([]models.CDXRecord, error) {
-	result := c.FetchAllCDXWithResume(domain, "", progress, nil, cancel)
+func (c *WaybackClient) FetchAllCDX(domain string, filter CDXFilter, progress func(count, page int), cancel <-chan struct{}) ([]models.CDXRecord, error) {
+	result := c.FetchAllCDXWithResume(domain, "", filter, progress, nil, cancel)
 	return result.Records, result.Error
 }
 
 // FetchAllCDXWithResume fetches CDX records starting from a resume key
 // The batchCallback is called after each batch is fetched, allowing immediate processing
 // Returns FetchResult with records, current resume key, and completion status
-func (c *WaybackClient) FetchAllCDXWithResume(domain string, startResumeKey string, progress func(count, page int), batchCallback BatchCallback, cancel <-chan struct{}) FetchResult {
+func (c *WaybackClient) FetchAllCDXWithResume(domain string, startResumeKey string, filter CDXFilter, progress func(count, page int), batchCallback BatchCallback, cancel <-chan struct{}) FetchResult {
 	var allRecords []models.CDXRecord
 	resumeKey := startResumeKey
 	page := 0
@@ -422,7 +576,7 @@ func (c *WaybackClient) FetchAllCDXWithResume(domain string, startResumeKey stri
 		}
 
 		page++
-		resp, err := c.FetchCDX(domain, resumeKey)
+		resp, err := c.FetchCDX(domain, resumeKey, filter)
 		if err != nil {
 			// Check if it's a rate limit (503/429), timeout, or server error
 			errStr := err.Error()
@@ -517,3 +671,74 @@ func (c *WaybackClient) FetchAllCDXWithResume(domain string, startResumeKey stri
 		// This allows configurable delays rather than hardcoded ones
 	}
 }
+
+// EnrichProgress reports where a subdomain enrichment run is
+type EnrichProgress struct {
+	Subdomain string // Subdomain currently being fetched
+	Index     int    // 1-based position of Subdomain in the run
+	Total     int    // Number of subdomains in the run
+	Records   int    // Records stored so far across all subdomains
+}
+
+// EnrichResult summarizes a subdomain enrichment run
+type EnrichResult struct {
+	Indexed int // Subdomains fetched and stored
+	Failed  int // Subdomains whose fetch failed (left unindexed for a later run)
+	Records int // Records stored across all subdomains
+}
+
+// EnrichStore persists the CDX records fetched for one subdomain
+// Returning an error stops the run
+type EnrichStore func(subdomain string, records []models.CDXRecord) error
+
+// EnrichSubdomains fetches CDX records for each subdomain in turn and hands them to store
+// Waits delay between requests to be polite to the Wayback servers. A subdomain whose fetch
+// fails is logged and skipped so one bad host doesn't sink the run; cancellation stops it early.
+func (c *WaybackClient) EnrichSubdomains(subdomains []string, filter CDXFilter, delay time.Duration, progress func(EnrichProgress), store EnrichStore, cancel <-chan struct{}) (EnrichResult, error) {
+	var result EnrichResult
+
+	// wait sleeps for delay, returning false if cancelled first
+	wait := func() bool {
+		select {
+		case <-cancel:
+			return false
+		case <-time.After(delay):
+			return true
+		}
+	}
+
+	for i, subdomain := range subdomains {
+		if i > 0 && !wait() {
+			return result, fmt.Errorf("cancelled")
+		}
+		if progress != nil {
+			progress(EnrichProgress{Subdomain: subdomain, Index: i + 1, Total: len(subdomains), Records: result.Records})
+		}
+
+		// Space out the pages of a single subdomain too
+		pacer := func(batch []models.CDXRecord, resumeKey string, totalCount, page int) bool {
+			return resumeKey == "" || wait()
+		}
+		fetched := c.FetchAllCDXWithResume(subdomain, "", filter, nil, pacer, cancel)
+		if fetched.Error != nil || !fetched.IsComplete {
+			select {
+			case <-cancel:
+				return result, fmt.Errorf("cancelled")
+			default:
+			}
+			if c.logger != nil {
+				c.logger.Warn("CDX enrichment failed, skipping subdomain", "subdomain", subdomain, "error", fetched.Error)
+			}
+			result.Failed++
+			continue
+		}
+
+		if err := store(subdomain, fetched.Records); err != nil {
+			return result, fmt.Errorf("failed to store CDX records for %s: %w", subdomain, err)
+		}
+		result.Indexed++
+		result.Records += len(fetched.Records)
+	}
+
+	return result, nil
+}
@@ -2,8 +2,14 @@ package api
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 // TestBuildCDXQuery verifies the query string is built correctly
@@ -27,7 +33,7 @@ func TestBuildCDXQuery(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.domain, func(t *testing.T) {
-			query := BuildCDXQuery(tt.domain, tt.resumeKey)
+			query := BuildCDXQuery(tt.domain, tt.resumeKey, CDXFilter{})
 
 			// Verify asterisk is NOT encoded (should be *, not %2A)
 			if containsSubstring(query, "%2A") || containsSubstring(query, "%2a") {
@@ -44,6 +50,48 @@ func TestBuildCDXQuery(t *testing.T) {
 	}
 }
 
+// TestBuildCDXQueryFilters verifies status and MIME filters map to CDX filter= params alongside the resume key
+func TestBuildCDXQueryFilters(t *testing.T) {
+	filter, err := ParseCDXFilter("200, 3xx, !404", "text/html,application/rss+xml")
+	if err != nil {
+		t.Fatalf("ParseCDXFilter() error = %v", err)
+	}
+
+	query := BuildCDXQuery("bfl.ai", "com,bfl)/ 20240101", filter)
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("query does not parse: %v", err)
+	}
+
+	want := []string{
+		"statuscode:(200|3..)",
+		"!statuscode:404",
+		`mimetype:(text/html|application/rss\+xml)`,
+	}
+	got := values["filter"]
+	if len(got) != len(want) {
+		t.Fatalf("filters = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("filter %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if values.Get("resumeKey") != "com,bfl)/ 20240101" {
+		t.Errorf("resumeKey = %q, want it kept with filters", values.Get("resumeKey"))
+	}
+	if !containsSubstring(query, "url=*.bfl.ai") {
+		t.Errorf("asterisk encoded with filters applied: %s", query)
+	}
+
+	if q := BuildCDXQuery("bfl.ai", "", CDXFilter{}); containsSubstring(q, "filter=") {
+		t.Errorf("empty filter added params: %s", q)
+	}
+	if _, err := ParseCDXFilter("20", ""); err == nil {
+		t.Error("ParseCDXFilter() accepted a two-digit status code")
+	}
+}
+
 // TestURLConstruction verifies the URL is built without double-encoding
 func TestURLConstruction(t *testing.T) {
 	domain := "bfl.ai"
@@ -53,7 +101,7 @@ func TestURLConstruction(t *testing.T) {
 		Scheme:   "https",
 		Host:     "web.archive.org",
 		Path:     "/cdx/search/cdx",
-		RawQuery: BuildCDXQuery(domain, ""),
+		RawQuery: BuildCDXQuery(domain, "", CDXFilter{}),
 	}
 
 	urlStr := reqURL.String()
@@ -121,7 +169,7 @@ func TestFetchCDXIntegration(t *testing.T) {
 	}
 
 	client := NewWaybackClient(nil)
-	resp, err := client.FetchCDX("bfl.ai", "")
+	resp, err := client.FetchCDX("bfl.ai", "", CDXFilter{})
 
 	if err != nil {
 		t.Fatalf("FetchCDX failed: %v", err)
@@ -142,3 +190,91 @@ func TestFetchCDXIntegration(t *testing.T) {
 		fmt.Printf("  %d: %s (status: %v)\n", i, r.URL, r.StatusCode)
 	}
 }
+
+// TestCollapseByURL verifies captures collapse by normalized URL, keeping the newest capture and counting them
+func TestCollapseByURL(t *testing.T) {
+	status := func(code int) *int { return &code }
+	records := []models.CDXRecord{
+		{URL: "http://bfl.ai/docs/", Timestamp: "20200101000000", StatusCode: status(200)},
+		{URL: "https://bfl.ai/login", Timestamp: "20210101000000", StatusCode: status(200)},
+		{URL: "http://BFL.ai:80/docs", Timestamp: "20230101000000", StatusCode: status(301)},
+		{URL: "http://bfl.ai/docs#top", Timestamp: "20220101000000", StatusCode: status(200)},
+	}
+
+	got := CollapseByURL(records)
+	if len(got) != 2 {
+		t.Fatalf("CollapseByURL() returned %d records, want 2", len(got))
+	}
+	if got[0].Captures != 3 || got[0].Timestamp != "20230101000000" || *got[0].StatusCode != 301 {
+		t.Errorf("docs = %d captures at %s (status %d), want 3 at 20230101000000 (301)", got[0].Captures, got[0].Timestamp, *got[0].StatusCode)
+	}
+	if got[1].Captures != 1 {
+		t.Errorf("login captures = %d, want 1", got[1].Captures)
+	}
+
+	if q := BuildCDXQuery("bfl.ai", "", CDXFilter{CountCaptures: true}); containsSubstring(q, "collapse=") {
+		t.Errorf("counting query still collapses server-side: %s", q)
+	}
+	if q := BuildCDXQuery("bfl.ai", "", CDXFilter{}); !containsSubstring(q, "collapse=urlkey") {
+		t.Errorf("default query lost collapse=urlkey: %s", q)
+	}
+}
+
+// roundTripFunc stubs an http.RoundTripper so CDX requests never leave the process
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestEnrichSubdomains verifies each subdomain is fetched and stored in order, and a failing
+// subdomain is skipped rather than stored
+func TestEnrichSubdomains(t *testing.T) {
+	client := NewWaybackClient(nil)
+	client.httpClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.Contains(r.URL.RawQuery, "broken.bfl.ai") {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("boom")), Header: http.Header{}}, nil
+		}
+		host := strings.TrimPrefix(strings.SplitN(r.URL.RawQuery, "&", 2)[0], "url=*.")
+		body := fmt.Sprintf(`[["original","timestamp","statuscode","mimetype"],["https://%s/","20240101000000","200","text/html"]]`, host)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	})
+
+	var seen []string
+	var stored []string
+	progress := func(p EnrichProgress) {
+		seen = append(seen, fmt.Sprintf("%d/%d %s", p.Index, p.Total, p.Subdomain))
+	}
+	store := func(subdomain string, records []models.CDXRecord) error {
+		if len(records) != 1 || records[0].Domain != subdomain {
+			t.Errorf("store(%s) got records %+v", subdomain, records)
+		}
+		stored = append(stored, subdomain)
+		return nil
+	}
+
+	subdomains := []string{"api.bfl.ai", "broken.bfl.ai", "www.bfl.ai"}
+	result, err := client.EnrichSubdomains(subdomains, CDXFilter{}, time.Millisecond, progress, store, nil)
+	if err != nil {
+		t.Fatalf("EnrichSubdomains() error = %v", err)
+	}
+	if result != (EnrichResult{Indexed: 2, Failed: 1, Records: 2}) {
+		t.Errorf("EnrichSubdomains() result = %+v", result)
+	}
+	if got := strings.Join(stored, ","); got != "api.bfl.ai,www.bfl.ai" {
+		t.Errorf("stored = %s", got)
+	}
+	if got := strings.Join(seen, ","); got != "1/3 api.bfl.ai,2/3 broken.bfl.ai,3/3 www.bfl.ai" {
+		t.Errorf("progress = %s", got)
+	}
+
+	// A closed cancel channel stops the run before the second subdomain
+	cancel := make(chan struct{})
+	stored = nil
+	store = func(subdomain string, records []models.CDXRecord) error {
+		stored = append(stored, subdomain)
+		close(cancel)
+		return nil
+	}
+	if _, err := client.EnrichSubdomains(subdomains, CDXFilter{}, time.Hour, nil, store, cancel); err == nil || len(stored) != 1 {
+		t.Errorf("cancelled run: err = %v, stored = %v", err, stored)
+	}
+}
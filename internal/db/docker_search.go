@@ -0,0 +1,104 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDockerSearchCacheTTL is how long cached Docker Hub searches are kept when no TTL is set
+const DefaultDockerSearchCacheTTL = 7 * 24 * time.Hour
+
+// DockerSearchPage is one cached page of Docker Hub search results
+type DockerSearchPage struct {
+	Response  string // JSON-encoded search response
+	FetchedAt time.Time
+}
+
+// CachedDockerSearch summarizes a cached Docker Hub search query
+type CachedDockerSearch struct {
+	Query     string
+	Pages     int
+	FetchedAt time.Time // When the most recent page was fetched
+}
+
+// normalizeDockerQuery keys the cache so searches differing only in case or spacing share entries
+func normalizeDockerQuery(query string) string {
+	return strings.ToLower(strings.TrimSpace(query))
+}
+
+// SaveDockerSearch caches one page of Docker Hub search results, replacing any older copy
+func (db *DB) SaveDockerSearch(query string, page int, response string) error {
+	_, err := db.conn.Exec(upsertDockerSearch, normalizeDockerQuery(query), page, response)
+	if err != nil {
+		return fmt.Errorf("failed to save Docker Hub search: %w", err)
+	}
+	return nil
+}
+
+// GetDockerSearch returns a cached page of Docker Hub search results, or nil if not cached
+func (db *DB) GetDockerSearch(query string, page int) (*DockerSearchPage, error) {
+	var p DockerSearchPage
+	var fetchedAt string
+	err := db.conn.QueryRow(selectDockerSearch, normalizeDockerQuery(query), page).Scan(&p.Response, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker Hub search: %w", err)
+	}
+	p.FetchedAt, _ = parseTimestamp(fetchedAt)
+	return &p, nil
+}
+
+// GetCachedDockerSearches lists cached Docker Hub search queries, most recently fetched first
+func (db *DB) GetCachedDockerSearches() ([]CachedDockerSearch, error) {
+	rows, err := db.conn.Query(selectCachedDockerSearches)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cached Docker Hub searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []CachedDockerSearch
+	for rows.Next() {
+		var s CachedDockerSearch
+		var fetchedAt string
+		if err := rows.Scan(&s.Query, &s.Pages, &fetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cached Docker Hub search: %w", err)
+		}
+		s.FetchedAt, _ = parseTimestamp(fetchedAt)
+		searches = append(searches, s)
+	}
+	return searches, nil
+}
+
+// EvictDockerSearches removes cached search pages fetched more than ttl ago
+// Returns the number of pages removed.
+func (db *DB) EvictDockerSearches(ttl time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-ttl).UTC().Format("2006-01-02 15:04:05")
+	result, err := db.conn.Exec(deleteStaleDockerSearches, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to evict Docker Hub searches: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetDockerSearchCacheTTL returns how long cached Docker Hub searches are kept
+// Falls back to DefaultDockerSearchCacheTTL when unset or invalid.
+func (db *DB) GetDockerSearchCacheTTL() time.Duration {
+	value, err := db.GetSetting(SettingDockerSearchCacheTTL)
+	if err != nil || value == "" {
+		return DefaultDockerSearchCacheTTL
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return DefaultDockerSearchCacheTTL
+	}
+	return ttl
+}
+
+// SetDockerSearchCacheTTL saves how long cached Docker Hub searches are kept
+func (db *DB) SetDockerSearchCacheTTL(ttl time.Duration) error {
+	return db.SetSetting(SettingDockerSearchCacheTTL, ttl.String())
+}
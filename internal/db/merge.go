@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MergeTableCount reports how many rows of one table a merge copied or left alone
+type MergeTableCount struct {
+	Table   string
+	Merged  int // Rows copied into the destination (new, or replacing a conflict with overwrite)
+	Skipped int // Conflicting rows where the destination's copy was kept
+}
+
+// MergeReport summarizes a MergeFrom run, one entry per merged table
+type MergeReport struct {
+	Tables []MergeTableCount
+}
+
+// mergeTable describes how rows of a table are copied between project databases
+// Columns leave out surrogate ids so rows get fresh ones; conflicts are detected by the
+// table's natural unique key.
+type mergeTable struct {
+	name    string
+	columns string
+}
+
+// mergeTables are merged in order, parents before children
+var mergeTables = []mergeTable{
+	{"tracked_repos", "repo_owner, repo_name, added_at"},
+	{"commits", "sha, message, author_name, author_email, author_date, committer_name, committer_email, committer_date, github_author_login, github_committer_login, html_url, repo_owner, repo_name, signed, signature_verified, signing_key"},
+	{"co_authors", "commit_sha, name, email"},
+	{"commit_files", "commit_sha, path"},
+	{"user_profiles", "login, name, bio, company, location, email, website_url, twitter_username, pronouns, avatar_url, follower_count, following_count, created_at, organizations, social_accounts, fetched_at"},
+	{"user_repositories", "github_login, name, owner_login, description, url, ssh_url, homepage_url, disk_usage, stargazer_count, fork_count, commit_count, is_fork, is_empty, is_in_organization, has_wiki_enabled, visibility, primary_language, license_name, created_at, updated_at, pushed_at, fetched_at"},
+	{"user_gists", "id, github_login, name, description, url, resource_path, is_public, is_fork, stargazer_count, fork_count, revision_count, created_at, updated_at, pushed_at, fetched_at"},
+	{"target_domains", "domain, vt_enumerated, crtsh_enumerated, vt_cursor, added_at"},
+	{"subdomains", "domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch"},
+	{"github_user_ids", "id, login, fetched_at"},
+	{"subdomain_snapshots", "domain, label, subdomains, created_at"},
+}
+
+const (
+	gistFileColumns    = "gist_id, name, encoded_name, extension, language, size, encoding, is_image, is_truncated, text"
+	gistCommentColumns = "id, gist_id, author_login, body_text, created_at, updated_at"
+)
+
+// MergeFrom copies tracked repos, commits, user profiles/repos/gists and subdomains from the
+// project database at otherPath into this one. Rows whose key already exists here are kept
+// as-is unless overwrite is set, in which case the other database's copy replaces them.
+// Gist files and comments travel with their gist. The merge runs in one transaction: on
+// error nothing is changed.
+//
+// The other database is opened (and so migrated to the current schema) before attaching.
+func (db *DB) MergeFrom(otherPath string, overwrite bool) (*MergeReport, error) {
+	if _, err := os.Stat(otherPath); err != nil {
+		return nil, fmt.Errorf("failed to open merge source: %w", err)
+	}
+	if same, err := db.isSameFile(otherPath); err != nil {
+		return nil, err
+	} else if same {
+		return nil, fmt.Errorf("cannot merge a database into itself")
+	}
+
+	// Bring the source up to the current schema so every merged column exists
+	other, err := New(otherPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open merge source: %w", err)
+	}
+	other.Close()
+
+	// ATTACH applies per connection, so pin one for the whole merge
+	ctx := context.Background()
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS merge_src", otherPath); err != nil {
+		return nil, fmt.Errorf("failed to attach merge source: %w", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE merge_src")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	verb := "INSERT OR IGNORE"
+	if overwrite {
+		verb = "INSERT OR REPLACE"
+	}
+
+	report := &MergeReport{}
+	for _, t := range mergeTables {
+		if t.name == "user_gists" {
+			if err := mergeGistChildren(ctx, tx, overwrite); err != nil {
+				return nil, err
+			}
+		}
+
+		var total int
+		if err := tx.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM merge_src.%s", t.name)).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count %s to merge: %w", t.name, err)
+		}
+		res, err := tx.ExecContext(ctx, fmt.Sprintf("%s INTO main.%s (%s) SELECT %s FROM merge_src.%s", verb, t.name, t.columns, t.columns, t.name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge %s: %w", t.name, err)
+		}
+		merged, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count merged %s: %w", t.name, err)
+		}
+		report.Tables = append(report.Tables, MergeTableCount{Table: t.name, Merged: int(merged), Skipped: total - int(merged)})
+	}
+
+	// The search index triggers clear a row's entries before every insert, including the ones
+	// OR IGNORE then drops, so rebuild the index rather than trust it after a bulk merge
+	if _, err := tx.ExecContext(ctx, rebuildSearchIndex); err != nil {
+		return nil, fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, rebuildCommitMessageIndex); err != nil {
+		return nil, fmt.Errorf("failed to rebuild commit message index: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+	for _, t := range report.Tables {
+		db.logger.Info("Merged table", "source", otherPath, "table", t.Table, "merged", t.Merged, "skipped", t.Skipped)
+	}
+	return report, nil
+}
+
+// mergeGistChildren copies gist files and comments ahead of their gists
+// Only gists the merge will write get their children copied: new gists always, existing ones
+// only with overwrite, where the destination's files and comments are replaced wholesale.
+func mergeGistChildren(ctx context.Context, tx *sql.Tx, overwrite bool) error {
+	var stmts []string
+	if overwrite {
+		stmts = []string{
+			"DELETE FROM main.gist_files WHERE gist_id IN (SELECT id FROM merge_src.user_gists)",
+			"DELETE FROM main.gist_comments WHERE gist_id IN (SELECT id FROM merge_src.user_gists)",
+			fmt.Sprintf("INSERT INTO main.gist_files (%s) SELECT %s FROM merge_src.gist_files", gistFileColumns, gistFileColumns),
+			fmt.Sprintf("INSERT OR REPLACE INTO main.gist_comments (%s) SELECT %s FROM merge_src.gist_comments", gistCommentColumns, gistCommentColumns),
+		}
+	} else {
+		stmts = []string{
+			fmt.Sprintf("INSERT INTO main.gist_files (%s) SELECT %s FROM merge_src.gist_files WHERE gist_id NOT IN (SELECT id FROM main.user_gists)", gistFileColumns, gistFileColumns),
+			fmt.Sprintf("INSERT OR IGNORE INTO main.gist_comments (%s) SELECT %s FROM merge_src.gist_comments WHERE gist_id NOT IN (SELECT id FROM main.user_gists)", gistCommentColumns, gistCommentColumns),
+		}
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to merge gist files and comments: %w", err)
+		}
+	}
+	return nil
+}
+
+// isSameFile reports whether path is the file backing this database
+func (db *DB) isSameFile(path string) (bool, error) {
+	var mainPath string
+	if err := db.conn.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&mainPath); err != nil {
+		return false, fmt.Errorf("failed to read database path: %w", err)
+	}
+	if mainPath == "" {
+		return false, nil // In-memory database
+	}
+	a, err := os.Stat(mainPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat database: %w", err)
+	}
+	b, err := os.Stat(filepath.Clean(path))
+	if err != nil {
+		return false, fmt.Errorf("failed to stat merge source: %w", err)
+	}
+	return os.SameFile(a, b), nil
+}
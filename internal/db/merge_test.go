@@ -0,0 +1,139 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// seedMergeProject writes one project's view of overlapping data; tag marks whose copy a
+// conflicting row is
+func seedMergeProject(t *testing.T, path, tag string, shas, logins, gists, subdomains []string) {
+	t.Helper()
+	database, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := database.AddTrackedRepo("owner", "repo-"+tag); err != nil {
+		t.Fatal(err)
+	}
+	var commits []models.CommitRecord
+	for _, sha := range shas {
+		commits = append(commits, models.CommitRecord{SHA: sha, Message: tag, CommitterEmail: "c@example.com", RepoOwner: "owner", RepoName: "repo"})
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	for _, login := range logins {
+		if err := database.SaveUserProfile(models.UserProfile{Login: login, Bio: tag + "bio"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var userGists []models.UserGist
+	for _, id := range gists {
+		userGists = append(userGists, models.UserGist{ID: id, GitHubLogin: "alice", Description: tag, Files: []models.GistFile{{Name: tag + "-" + id + ".txt"}}})
+	}
+	if err := database.SaveUserGists(userGists); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.InsertTargetDomain("bfl.ai"); err != nil {
+		t.Fatal(err)
+	}
+	var subs []models.Subdomain
+	for _, s := range subdomains {
+		subs = append(subs, models.Subdomain{Domain: "bfl.ai", Subdomain: s, Source: tag})
+	}
+	if _, err := database.InsertSubdomains(subs); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestMergeFrom verifies merging overlapping projects keeps the destination's rows by default,
+// takes the source's with overwrite, and moves gist files along with their gist
+func TestMergeFrom(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.db")
+	seedMergeProject(t, src, "src", []string{"a", "b", "c"}, []string{"alice", "bob"}, []string{"g1", "g2"}, []string{"api.bfl.ai", "www.bfl.ai"})
+
+	for _, overwrite := range []bool{false, true} {
+		into := filepath.Join(dir, "into.db")
+		if overwrite {
+			into = filepath.Join(dir, "into-overwrite.db")
+		}
+		seedMergeProject(t, into, "dst", []string{"a"}, []string{"alice"}, []string{"g1"}, []string{"api.bfl.ai"})
+
+		database, err := New(into)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer database.Close()
+
+		report, err := database.MergeFrom(src, overwrite)
+		if err != nil {
+			t.Fatalf("MergeFrom(overwrite=%v) error = %v", overwrite, err)
+		}
+		counts := map[string]MergeTableCount{}
+		for _, c := range report.Tables {
+			counts[c.Table] = c
+		}
+		want := map[string]MergeTableCount{
+			"tracked_repos":  {Table: "tracked_repos", Merged: 1},
+			"commits":        {Table: "commits", Merged: 2, Skipped: 1},
+			"user_profiles":  {Table: "user_profiles", Merged: 1, Skipped: 1},
+			"user_gists":     {Table: "user_gists", Merged: 1, Skipped: 1},
+			"target_domains": {Table: "target_domains", Merged: 0, Skipped: 1},
+			"subdomains":     {Table: "subdomains", Merged: 1, Skipped: 1},
+		}
+		for table, w := range want {
+			if overwrite {
+				w.Merged, w.Skipped = w.Merged+w.Skipped, 0
+			}
+			if counts[table] != w {
+				t.Errorf("overwrite=%v %s: got %+v, want %+v", overwrite, table, counts[table], w)
+			}
+		}
+
+		// Conflicting rows come from the destination, or the source with overwrite
+		winner := "dst"
+		if overwrite {
+			winner = "src"
+		}
+		stats, total, err := database.GetCommitterStats("owner", "repo")
+		if err != nil || total != 3 || len(stats) != 1 {
+			t.Errorf("overwrite=%v: %d commits, %v", overwrite, total, err)
+		}
+		var message string
+		if err := database.conn.QueryRow("SELECT message FROM commits WHERE sha = 'a'").Scan(&message); err != nil || message != winner {
+			t.Errorf("overwrite=%v: commit a message = %q, %v; want %q", overwrite, message, err, winner)
+		}
+		if profile, err := database.GetUserProfile("alice"); err != nil || profile.Bio != winner+"bio" {
+			t.Errorf("overwrite=%v: alice bio = %q, %v", overwrite, profile.Bio, err)
+		}
+		if results, err := database.SearchLocalKeyword(winner + "bio"); err != nil || len(results) == 0 || results[0].Login != "alice" {
+			t.Errorf("overwrite=%v: search index missing merged bio: %v, %v", overwrite, results, err)
+		}
+
+		// Gist files follow whichever copy of the gist won
+		files, err := database.GetGistFiles("g1")
+		if err != nil || len(files) != 1 || files[0].Name != winner+"-g1.txt" {
+			t.Errorf("overwrite=%v: g1 files = %+v, %v", overwrite, files, err)
+		}
+		files, err = database.GetGistFiles("g2")
+		if err != nil || len(files) != 1 || files[0].Name != "src-g2.txt" {
+			t.Errorf("overwrite=%v: g2 files = %+v, %v", overwrite, files, err)
+		}
+	}
+
+	// Merging a project into itself is refused
+	database, err := New(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if _, err := database.MergeFrom(src, false); err == nil {
+		t.Error("MergeFrom(self) succeeded")
+	}
+}
@@ -0,0 +1,418 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// migration upgrades the schema from version-1 to version
+type migration struct {
+	version     int
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// columnDef describes a column added to an existing table
+type columnDef struct {
+	table      string
+	column     string
+	definition string
+}
+
+// migrations are applied in order on New(); the schema version is stored in PRAGMA user_version.
+// Tables themselves are created with CREATE TABLE IF NOT EXISTS before migrations run, so a
+// migration only has to bring tables created by older builds up to date. Append new migrations
+// to the end - never edit or reorder one that has shipped.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "columns added before schema versioning",
+		// Databases from before versioning may already have any subset of these, so each
+		// column is only added when missing
+		apply: addColumns(
+			columnDef{"user_repositories", "commit_count", "INTEGER DEFAULT 0"},
+			columnDef{"user_gists", "revision_count", "INTEGER DEFAULT 0"},
+			columnDef{"user_repositories", "primary_language", "TEXT"},
+			columnDef{"user_repositories", "license_name", "TEXT"},
+			columnDef{"user_gists", "fork_count", "INTEGER DEFAULT 0"},
+			columnDef{"user_profiles", "organizations", "TEXT"},
+			columnDef{"layer_inspections", "contents", "TEXT"},
+			columnDef{"subdomains", "resolved_ips", "TEXT"},
+			columnDef{"subdomains", "resolves", "BOOLEAN DEFAULT FALSE"},
+			columnDef{"subdomains", "http_status", "INTEGER DEFAULT 0"},
+			columnDef{"subdomains", "https_status", "INTEGER DEFAULT 0"},
+			columnDef{"subdomains", "server_header", "TEXT"},
+			columnDef{"subdomains", "final_url", "TEXT"},
+			columnDef{"subdomains", "is_wildcard", "BOOLEAN DEFAULT FALSE"},
+			columnDef{"target_domains", "vt_cursor", "TEXT"},
+			columnDef{"image_manifests", "image_config", "TEXT"},
+			columnDef{"wayback_records", "captures", "INTEGER DEFAULT 0"},
+		),
+	},
+	{
+		version:     2,
+		description: "covering indexes for committer stats",
+		// GetCommitterStats filters on repo then groups by committer; with the login in the
+		// index too, both stats queries are answered from the index without touching rows
+		apply: execAll(
+			"CREATE INDEX IF NOT EXISTS idx_commits_repo_committer ON commits(repo_owner, repo_name, committer_name, committer_email, github_committer_login)",
+			"CREATE INDEX IF NOT EXISTS idx_commits_committer_login ON commits(committer_name, committer_email, github_committer_login)",
+		),
+	},
+	{
+		version:     3,
+		description: "full-text index for local keyword search",
+		apply:       execAll(createSearchIndex, rebuildSearchIndex),
+	},
+	{
+		version:     4,
+		description: "co-authors from commit message trailers",
+		apply: func(tx *sql.Tx) error {
+			if _, err := tx.Exec(createCoAuthorsTable); err != nil {
+				return err
+			}
+			return backfillCoAuthors(tx)
+		},
+	},
+	{
+		version:     5,
+		description: "GitHub logins from noreply commit emails",
+		apply: func(tx *sql.Tx) error {
+			if _, err := tx.Exec(createGitHubUserIDsTable); err != nil {
+				return err
+			}
+			return backfillNoreplyLogins(tx)
+		},
+	},
+	{
+		version:     6,
+		description: "Docker Hub search cache",
+		apply:       execAll(createDockerSearchCacheTable),
+	},
+	{
+		version:     7,
+		description: "every source that reported a subdomain",
+		apply: func(tx *sql.Tx) error {
+			if err := addColumns(columnDef{"subdomains", "sources", "TEXT"})(tx); err != nil {
+				return err
+			}
+			_, err := tx.Exec(backfillSubdomainSources)
+			return err
+		},
+	},
+	{
+		version:     8,
+		description: "reverse DNS names of resolved subdomain IPs",
+		apply:       addColumns(columnDef{"subdomains", "ptr_names", "TEXT"}),
+	},
+	{
+		version:     9,
+		description: "file paths changed by each commit",
+		apply:       execAll(createCommitFilesTable),
+	},
+	{
+		version:     10,
+		description: "commit signature verification",
+		// Older rows keep NULL status, which the stats treat as unknown rather than unsigned.
+		// The covering indexes from version 2 are rebuilt to include the new columns.
+		apply: func(tx *sql.Tx) error {
+			if err := addColumns(
+				columnDef{"commits", "signed", "BOOLEAN"},
+				columnDef{"commits", "signature_verified", "BOOLEAN"},
+				columnDef{"commits", "signing_key", "TEXT"},
+			)(tx); err != nil {
+				return err
+			}
+			return execAll(
+				"DROP INDEX IF EXISTS idx_commits_repo_committer",
+				"DROP INDEX IF EXISTS idx_commits_committer_login",
+				"CREATE INDEX idx_commits_repo_committer ON commits(repo_owner, repo_name, committer_name, committer_email, github_committer_login, signed, signature_verified, signing_key)",
+				"CREATE INDEX idx_commits_committer_login ON commits(committer_name, committer_email, github_committer_login, signed, signature_verified, signing_key)",
+			)(tx)
+		},
+	},
+	{
+		version:     11,
+		description: "TLS certificates served to HTTP probes",
+		apply: addColumns(
+			columnDef{"subdomains", "tls_subject", "TEXT"},
+			columnDef{"subdomains", "tls_sans", "TEXT"},
+			columnDef{"subdomains", "tls_issuer", "TEXT"},
+			columnDef{"subdomains", "tls_not_before", "DATETIME"},
+			columnDef{"subdomains", "tls_not_after", "DATETIME"},
+			columnDef{"subdomains", "tls_host_mismatch", "BOOLEAN DEFAULT FALSE"},
+		),
+	},
+	{
+		version:     12,
+		description: "opt-in full-text index of commit messages",
+		apply:       execAll(createCommitMessageIndex),
+	},
+	{
+		version:     13,
+		description: "saved subdomain snapshots",
+		apply:       execAll(createSubdomainSnapshotsTable),
+	},
+	{
+		version:     14,
+		description: "wayback captures keyed by normalized URL",
+		// Rows whose URLs normalize to the same key are merged before the unique index is built
+		apply: func(tx *sql.Tx) error {
+			if err := addColumns(columnDef{"wayback_records", "url_key", "TEXT"})(tx); err != nil {
+				return err
+			}
+			if err := backfillWaybackURLKeys(tx); err != nil {
+				return err
+			}
+			return execAll("CREATE UNIQUE INDEX IF NOT EXISTS idx_wayback_url_key ON wayback_records(url_key)")(tx)
+		},
+	},
+}
+
+// currentSchemaVersion is the version a fully migrated database reports
+var currentSchemaVersion = migrations[len(migrations)-1].version
+
+// migrate applies every migration newer than the database's user_version
+// Each migration runs in its own transaction together with its version bump, so a failure
+// leaves the database at the last good version.
+func migrate(conn *sql.DB, logger *log.Logger) error {
+	version, err := schemaVersion(conn)
+	if err != nil {
+		return err
+	}
+	if version > currentSchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d)", version, currentSchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		tx, err := conn.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", m.version, err)
+		}
+		if err := m.apply(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.description, err)
+		}
+		// PRAGMA doesn't take bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to set schema version %d: %w", m.version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", m.version, err)
+		}
+		logger.Info("Applied schema migration", "version", m.version, "description", m.description)
+	}
+
+	return nil
+}
+
+// schemaVersion returns the schema version recorded in the database
+func schemaVersion(conn *sql.DB) (int, error) {
+	var version int
+	if err := conn.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// SchemaVersion returns the schema version of the open database
+func (db *DB) SchemaVersion() (int, error) {
+	return schemaVersion(db.conn)
+}
+
+// execAll returns a migration step running each statement in order
+func execAll(stmts ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumns returns a migration step adding each column its table doesn't already have
+func addColumns(columns ...columnDef) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, c := range columns {
+			exists, err := hasColumn(tx, c.table, c.column)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+				return fmt.Errorf("failed to add %s.%s: %w", c.table, c.column, err)
+			}
+		}
+		return nil
+	}
+}
+
+// hasColumn reports whether table has a column with the given name
+func hasColumn(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    bool
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// backfillCoAuthors parses co-authors out of commits stored before they were tracked
+func backfillCoAuthors(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT sha, message FROM commits WHERE message LIKE '%co-authored-by:%'")
+	if err != nil {
+		return fmt.Errorf("failed to read commit messages: %w", err)
+	}
+	type commitCoAuthor struct {
+		sha      string
+		coAuthor models.CoAuthor
+	}
+	var found []commitCoAuthor
+	for rows.Next() {
+		var sha string
+		var message sql.NullString
+		if err := rows.Scan(&sha, &message); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan commit message: %w", err)
+		}
+		for _, ca := range models.ParseCoAuthors(message.String) {
+			found = append(found, commitCoAuthor{sha, ca})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read commit messages: %w", err)
+	}
+
+	for _, f := range found {
+		if _, err := tx.Exec(insertCoAuthor, f.sha, f.coAuthor.Name, f.coAuthor.Email); err != nil {
+			return fmt.Errorf("failed to save co-author for %s: %w", f.sha, err)
+		}
+	}
+	return nil
+}
+
+// backfillNoreplyLogins fills in missing author/committer logins of stored commits whose
+// email is a noreply address naming the login, as ToRecord now does on ingestion
+func backfillNoreplyLogins(tx *sql.Tx) error {
+	for _, role := range []string{"author", "committer"} {
+		rows, err := tx.Query(fmt.Sprintf(
+			"SELECT sha, %[1]s_email FROM commits WHERE COALESCE(github_%[1]s_login, '') = '' AND %[1]s_email LIKE '%%@users.noreply.github.com'", role))
+		if err != nil {
+			return fmt.Errorf("failed to read %s emails: %w", role, err)
+		}
+		logins := make(map[string]string)
+		for rows.Next() {
+			var sha, email string
+			if err := rows.Scan(&sha, &email); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan %s email: %w", role, err)
+			}
+			if login, ok := models.ResolveNoreplyLogin(email); ok {
+				logins[sha] = login
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to read %s emails: %w", role, err)
+		}
+
+		update := fmt.Sprintf("UPDATE commits SET github_%s_login = ? WHERE sha = ?", role)
+		for sha, login := range logins {
+			if _, err := tx.Exec(update, login, sha); err != nil {
+				return fmt.Errorf("failed to set %s login for %s: %w", role, sha, err)
+			}
+		}
+	}
+	return nil
+}
+
+// backfillWaybackURLKeys fills in the normalized URL key of stored captures. Rows sharing a key
+// are merged into the first one: capture counts add up, the newest capture's status and MIME
+// type are kept, and tags survive from whichever row had them.
+func backfillWaybackURLKeys(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, url, timestamp, status_code, mime_type, COALESCE(tags, ''), COALESCE(captures, 0) FROM wayback_records ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("failed to read wayback records: %w", err)
+	}
+	type waybackRow struct {
+		id         int64
+		timestamp  sql.NullString
+		statusCode sql.NullInt64
+		mimeType   sql.NullString
+		tags       string
+		captures   int
+	}
+	kept := make(map[string]*waybackRow)
+	var keys []string
+	var merged []int64
+	for rows.Next() {
+		var r waybackRow
+		var rawURL string
+		if err := rows.Scan(&r.id, &rawURL, &r.timestamp, &r.statusCode, &r.mimeType, &r.tags, &r.captures); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan wayback record: %w", err)
+		}
+		key := models.NormalizeCDXURL(rawURL)
+		first, seen := kept[key]
+		if !seen {
+			kept[key] = &r
+			keys = append(keys, key)
+			continue
+		}
+		first.captures += r.captures
+		if r.timestamp.String > first.timestamp.String {
+			first.timestamp, first.statusCode, first.mimeType = r.timestamp, r.statusCode, r.mimeType
+		}
+		if first.tags == "" {
+			first.tags = r.tags
+		}
+		merged = append(merged, r.id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read wayback records: %w", err)
+	}
+
+	for _, id := range merged {
+		if _, err := tx.Exec("DELETE FROM wayback_records WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to merge wayback record %d: %w", id, err)
+		}
+	}
+	for _, key := range keys {
+		r := kept[key]
+		if _, err := tx.Exec("UPDATE wayback_records SET url_key = ?, timestamp = ?, status_code = ?, mime_type = ?, tags = ?, captures = ? WHERE id = ?",
+			key, r.timestamp, r.statusCode, r.mimeType, r.tags, r.captures, r.id); err != nil {
+			return fmt.Errorf("failed to key wayback record %d: %w", r.id, err)
+		}
+	}
+	return nil
+}
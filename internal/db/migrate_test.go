@@ -0,0 +1,108 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// TestMigrateUpgradesV0Database verifies a database from before schema versioning is brought
+// up to the current version without losing rows, and that reopening it is a no-op
+func TestMigrateUpgradesV0Database(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// Tables as an older build created them: no capture counts, no resolution/probe columns
+	old, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open v0 db: %v", err)
+	}
+	for _, stmt := range []string{
+		`CREATE TABLE wayback_records (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT UNIQUE NOT NULL,
+			domain TEXT NOT NULL,
+			timestamp TEXT,
+			status_code INTEGER,
+			mime_type TEXT,
+			tags TEXT DEFAULT '',
+			fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE subdomains (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			domain TEXT NOT NULL,
+			subdomain TEXT UNIQUE NOT NULL,
+			source TEXT NOT NULL,
+			cnames TEXT,
+			alt_names TEXT,
+			cert_expired BOOLEAN DEFAULT FALSE,
+			cdx_indexed BOOLEAN DEFAULT FALSE,
+			discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE commits (sha TEXT PRIMARY KEY, message TEXT, author_name TEXT, author_email TEXT, author_date TEXT,
+			committer_name TEXT, committer_email TEXT, committer_date TEXT, github_author_login TEXT,
+			github_committer_login TEXT, html_url TEXT, repo_owner TEXT, repo_name TEXT)`,
+		`INSERT INTO wayback_records (url, domain, timestamp) VALUES ('https://bfl.ai/', 'bfl.ai', '20240101000000')`,
+		`INSERT INTO wayback_records (url, domain, timestamp, tags) VALUES ('https://BFL.ai:443', 'bfl.ai', '20240201000000', '#login')`,
+		`INSERT INTO commits (sha, message, committer_name, committer_email, author_date, repo_owner, repo_name)
+			VALUES ('abc', 'Fix it
+
+Co-authored-by: Bob <bob@example.com>', 'Alice', 'alice@example.com', '2024-01-01T00:00:00Z', 'o', 'r')`,
+		`INSERT INTO commits (sha, message, committer_name, committer_email, author_date, repo_owner, repo_name)
+			VALUES ('def', 'Tidy', 'Mona', '1234+monalisa@users.noreply.github.com', '2024-01-02T00:00:00Z', 'o', 'r')`,
+	} {
+		if _, err := old.Exec(stmt); err != nil {
+			t.Fatalf("seed v0 db: %v", err)
+		}
+	}
+	old.Close()
+
+	database, err := New(path)
+	if err != nil {
+		t.Fatalf("New() on v0 db: %v", err)
+	}
+	if version, err := database.SchemaVersion(); err != nil || version != currentSchemaVersion {
+		t.Fatalf("SchemaVersion() = %d, %v; want %d", version, err, currentSchemaVersion)
+	}
+
+	tx, err := database.conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, col := range []columnDef{{table: "wayback_records", column: "captures"}, {table: "subdomains", column: "final_url"}, {table: "subdomains", column: "is_wildcard"}} {
+		if ok, err := hasColumn(tx, col.table, col.column); err != nil || !ok {
+			t.Errorf("%s.%s missing after migration (err %v)", col.table, col.column, err)
+		}
+	}
+	tx.Rollback()
+
+	// Both v0 rows normalize to the same URL, so they merge into the first
+	records, err := database.GetWaybackRecords("bfl.ai")
+	if err != nil || len(records) != 1 {
+		t.Errorf("GetWaybackRecords() = %d records, %v; want the merged v0 rows", len(records), err)
+	} else if records[0].URL != "https://bfl.ai/" || records[0].Timestamp != "20240201000000" || records[0].Tags != "#login" {
+		t.Errorf("merged v0 row = %+v, want the first URL with the newest capture and its tags", records[0])
+	}
+
+	// Co-authors and noreply logins are backfilled for commits stored before they were tracked
+	stats, _, err := database.GetCommitterStats("o", "r")
+	got := make(map[string]models.ContributorStats)
+	for _, s := range stats {
+		got[s.Name] = s
+	}
+	if err != nil || len(stats) != 3 || !got["Bob"].IsCoAuthor() || got["Mona"].GitHubLogin != "monalisa" {
+		t.Errorf("GetCommitterStats() after migration = %+v, %v; want Alice, Mona (monalisa) and co-author Bob", stats, err)
+	}
+	database.Close()
+
+	// Reopening an up-to-date database applies nothing and keeps the version
+	database, err = New(path)
+	if err != nil {
+		t.Fatalf("reopen migrated db: %v", err)
+	}
+	defer database.Close()
+	if version, _ := database.SchemaVersion(); version != currentSchemaVersion {
+		t.Errorf("SchemaVersion() after reopen = %d, want %d", version, currentSchemaVersion)
+	}
+}
@@ -14,7 +14,10 @@ CREATE TABLE IF NOT EXISTS commits (
     github_committer_login TEXT,
     html_url TEXT,
     repo_owner TEXT,
-    repo_name TEXT
+    repo_name TEXT,
+    signed BOOLEAN,
+    signature_verified BOOLEAN,
+    signing_key TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_commits_repo ON commits(repo_owner, repo_name);
@@ -22,21 +25,33 @@ CREATE INDEX IF NOT EXISTS idx_commits_committer ON commits(committer_name, comm
 CREATE INDEX IF NOT EXISTS idx_commits_author ON commits(author_name, author_email);
 `
 
+// A commit stored again without its signing status (e.g. loaded from a JSON file) keeps the
+// status it already had
 const insertCommit = `
 INSERT OR REPLACE INTO commits (
     sha, message, author_name, author_email, author_date,
     committer_name, committer_email, committer_date,
     github_author_login, github_committer_login,
-    html_url, repo_owner, repo_name
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    html_url, repo_owner, repo_name,
+    signed, signature_verified, signing_key
+) VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?13,
+    CASE WHEN ?14 IS NULL THEN (SELECT signed FROM commits WHERE sha = ?1) ELSE ?14 END,
+    CASE WHEN ?14 IS NULL THEN (SELECT signature_verified FROM commits WHERE sha = ?1) ELSE ?15 END,
+    CASE WHEN ?14 IS NULL THEN (SELECT signing_key FROM commits WHERE sha = ?1) ELSE ?16 END)
 `
 
+// Signing columns are only set for commits fetched with their verification status, so
+// signature_known counts the commits the signed share is computed over
 const selectCommitterStats = `
 SELECT 
     committer_name,
     committer_email,
     COALESCE(github_committer_login, '') as github_login,
-    COUNT(*) as commit_count
+    COUNT(*) as commit_count,
+    COUNT(signed) as signature_known,
+    COALESCE(SUM(signed), 0) as signed_commits,
+    COALESCE(SUM(signature_verified), 0) as verified_commits,
+    COALESCE(GROUP_CONCAT(DISTINCT signing_key), '') as signing_keys
 FROM commits
 WHERE repo_owner = ? AND repo_name = ?
 GROUP BY committer_name, committer_email
@@ -59,6 +74,117 @@ const selectTotalCommits = `
 SELECT COUNT(*) FROM commits WHERE repo_owner = ? AND repo_name = ?
 `
 
+// Committers are counted the way selectCommitterStats groups them
+const selectRepositoryDataCounts = `
+SELECT COUNT(*), COUNT(DISTINCT committer_name || char(0) || committer_email)
+FROM commits WHERE repo_owner = ? AND repo_name = ?
+`
+
+// Range variants bound author_date (stored as RFC 3339 UTC text, so string comparison orders by time)
+const selectCommitterStatsInRange = `
+SELECT 
+    committer_name,
+    committer_email,
+    COALESCE(github_committer_login, '') as github_login,
+    COUNT(*) as commit_count,
+    COUNT(signed) as signature_known,
+    COALESCE(SUM(signed), 0) as signed_commits,
+    COALESCE(SUM(signature_verified), 0) as verified_commits,
+    COALESCE(GROUP_CONCAT(DISTINCT signing_key), '') as signing_keys
+FROM commits
+WHERE repo_owner = ? AND repo_name = ? AND author_date >= ? AND author_date <= ?
+GROUP BY committer_name, committer_email
+ORDER BY commit_count DESC
+`
+
+const selectTotalCommitsInRange = `
+SELECT COUNT(*) FROM commits WHERE repo_owner = ? AND repo_name = ? AND author_date >= ? AND author_date <= ?
+`
+
+// Schema for co-authors parsed from Co-authored-by commit trailers
+// Rows go when their commit is deleted; INSERT OR REPLACE on commits doesn't fire delete
+// triggers, so InsertCommits clears a commit's co-authors itself before re-adding them.
+const createCoAuthorsTable = `
+CREATE TABLE IF NOT EXISTS co_authors (
+    commit_sha TEXT NOT NULL,
+    name TEXT,
+    email TEXT NOT NULL,
+    PRIMARY KEY (commit_sha, email)
+);
+
+CREATE TRIGGER IF NOT EXISTS co_authors_commit_delete AFTER DELETE ON commits BEGIN
+    DELETE FROM co_authors WHERE commit_sha = old.sha;
+END;
+`
+
+const insertCoAuthor = `
+INSERT OR IGNORE INTO co_authors (commit_sha, name, email) VALUES (?, ?, ?)
+`
+
+const deleteCommitCoAuthors = `
+DELETE FROM co_authors WHERE commit_sha = ?
+`
+
+// Schema for the file paths each commit changed. Only filled when commits are fetched with
+// their files; rows go when their commit is deleted, like co_authors.
+const createCommitFilesTable = `
+CREATE TABLE IF NOT EXISTS commit_files (
+    commit_sha TEXT NOT NULL,
+    path TEXT NOT NULL,
+    PRIMARY KEY (commit_sha, path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_files_path ON commit_files(path);
+
+CREATE TRIGGER IF NOT EXISTS commit_files_commit_delete AFTER DELETE ON commits BEGIN
+    DELETE FROM commit_files WHERE commit_sha = old.sha;
+END;
+`
+
+const insertCommitFile = `
+INSERT OR IGNORE INTO commit_files (commit_sha, path) VALUES (?, ?)
+`
+
+const deleteCommitFiles = `
+DELETE FROM commit_files WHERE commit_sha = ?
+`
+
+// selectTopFiles ranks a repository's file paths by how many commits changed them
+const selectTopFiles = `
+SELECT f.path, COUNT(DISTINCT f.commit_sha) AS commit_count
+FROM commit_files f
+JOIN commits c ON c.sha = f.commit_sha
+WHERE c.repo_owner = ? AND c.repo_name = ?
+GROUP BY f.path
+ORDER BY commit_count DESC, f.path
+LIMIT ?
+`
+
+// selectFileEditors ranks the committers of one file path by how many of their commits changed it
+const selectFileEditors = `
+SELECT MAX(c.committer_name), c.committer_email, MAX(COALESCE(c.github_committer_login, '')), COUNT(*) AS commit_count
+FROM commit_files f
+JOIN commits c ON c.sha = f.commit_sha
+WHERE c.repo_owner = ? AND c.repo_name = ? AND f.path = ?
+GROUP BY c.committer_email
+ORDER BY commit_count DESC, c.committer_email
+LIMIT ?
+`
+
+// selectCoAuthorStats counts co-authored commits per co-author, bounded by author_date like
+// selectCommitterStatsInRange
+const selectCoAuthorStats = `
+SELECT 
+    ca.name,
+    ca.email,
+    COUNT(*) as commit_count
+FROM co_authors ca
+JOIN commits c ON c.sha = ca.commit_sha
+WHERE c.repo_owner = ? AND c.repo_name = ? AND c.author_date >= ? AND c.author_date <= ?
+GROUP BY ca.name, ca.email
+ORDER BY commit_count DESC
+`
+
 // Schema for committer links (grouping same person's different accounts)
 const createLinksTable = `
 CREATE TABLE IF NOT EXISTS committer_links (
@@ -75,6 +201,17 @@ CREATE INDEX IF NOT EXISTS idx_links_repo ON committer_links(repo_owner, repo_na
 CREATE INDEX IF NOT EXISTS idx_links_group ON committer_links(group_id);
 `
 
+// Schema for optional link group labels (e.g. "John Doe / @jdoe"); unlabeled groups have no row
+const createLinkLabelsTable = `
+CREATE TABLE IF NOT EXISTS link_group_labels (
+    repo_owner TEXT NOT NULL,
+    repo_name TEXT NOT NULL,
+    group_id INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    PRIMARY KEY(repo_owner, repo_name, group_id)
+);
+`
+
 // Schema for tagged committers
 const createTagsTable = `
 CREATE TABLE IF NOT EXISTS committer_tags (
@@ -105,10 +242,54 @@ SELECT COALESCE(MAX(group_id), 0) FROM committer_links
 WHERE repo_owner = ? AND repo_name = ?
 `
 
+// selectLinkGroupMembers lists linked emails with their name, login, commit count and group label, for
+// one repo or (when the first parameter is empty) every repo
+const selectLinkGroupMembers = `
+SELECT l.repo_owner, l.repo_name, l.group_id, l.committer_email,
+    COALESCE((SELECT c.committer_name FROM commits c
+        WHERE c.committer_email = l.committer_email AND c.repo_owner = l.repo_owner AND c.repo_name = l.repo_name
+        ORDER BY c.committer_date DESC LIMIT 1), ''),
+    COALESCE((SELECT c.github_committer_login FROM commits c
+        WHERE c.committer_email = l.committer_email AND COALESCE(c.github_committer_login, '') != ''
+        LIMIT 1), ''),
+    (SELECT COUNT(*) FROM commits c
+        WHERE c.committer_email = l.committer_email AND c.repo_owner = l.repo_owner AND c.repo_name = l.repo_name),
+    COALESCE(g.label, '')
+FROM committer_links l
+LEFT JOIN link_group_labels g
+    ON g.repo_owner = l.repo_owner AND g.repo_name = l.repo_name AND g.group_id = l.group_id
+WHERE ? = '' OR (l.repo_owner = ? AND l.repo_name = ?)
+ORDER BY l.repo_owner, l.repo_name, l.group_id, l.committer_email
+`
+
 const deleteLink = `
 DELETE FROM committer_links WHERE repo_owner = ? AND repo_name = ? AND committer_email = ?
 `
 
+const upsertGroupLabel = `
+INSERT OR REPLACE INTO link_group_labels (repo_owner, repo_name, group_id, label)
+VALUES (?, ?, ?, ?)
+`
+
+const deleteGroupLabel = `
+DELETE FROM link_group_labels WHERE repo_owner = ? AND repo_name = ? AND group_id = ?
+`
+
+const selectGroupLabel = `
+SELECT label FROM link_group_labels WHERE repo_owner = ? AND repo_name = ? AND group_id = ?
+`
+
+const selectGroupLabels = `
+SELECT group_id, label FROM link_group_labels WHERE repo_owner = ? AND repo_name = ?
+`
+
+// deleteOrphanGroupLabels drops labels of groups with no links left, so a reused group ID
+// doesn't inherit an old name
+const deleteOrphanGroupLabels = `
+DELETE FROM link_group_labels WHERE repo_owner = ? AND repo_name = ?
+    AND group_id NOT IN (SELECT group_id FROM committer_links WHERE repo_owner = ? AND repo_name = ?)
+`
+
 // SQL queries for tags
 const insertTag = `
 INSERT OR IGNORE INTO committer_tags (repo_owner, repo_name, committer_email)
@@ -124,6 +305,10 @@ const deleteTag = `
 DELETE FROM committer_tags WHERE repo_owner = ? AND repo_name = ? AND committer_email = ?
 `
 
+const deleteAllTags = `
+DELETE FROM committer_tags WHERE repo_owner = ? AND repo_name = ?
+`
+
 // Schema for highlight domains (email domains to highlight)
 const createDomainsTable = `
 CREATE TABLE IF NOT EXISTS highlight_domains (
@@ -150,8 +335,8 @@ SELECT domain, color_index FROM highlight_domains
 ORDER BY created_at ASC
 `
 
-const selectMaxDomainColorIndex = `
-SELECT COALESCE(MAX(color_index), -1) FROM highlight_domains
+const selectDomainColorIndexes = `
+SELECT color_index FROM highlight_domains
 `
 
 const deleteDomain = `
@@ -190,13 +375,21 @@ SELECT
     committer_name,
     committer_email,
     COALESCE(github_committer_login, '') as github_login,
-    SUM(commit_count) as total_commits
+    SUM(commit_count) as total_commits,
+    SUM(signature_known),
+    COALESCE(SUM(signed_commits), 0),
+    COALESCE(SUM(verified_commits), 0),
+    COALESCE(GROUP_CONCAT(signing_keys), '')
 FROM (
     SELECT 
         committer_name,
         committer_email,
         github_committer_login,
         COUNT(*) as commit_count,
+        COUNT(signed) as signature_known,
+        SUM(signed) as signed_commits,
+        SUM(signature_verified) as verified_commits,
+        GROUP_CONCAT(DISTINCT signing_key) as signing_keys,
         CASE 
             WHEN github_committer_login IS NOT NULL AND github_committer_login != '' 
             THEN github_committer_login 
@@ -448,6 +641,72 @@ const deleteGistComments = `
 DELETE FROM gist_comments WHERE gist_id = ?
 `
 
+// Schema for user public keys (SSH and GPG)
+const createUserPublicKeysTable = `
+CREATE TABLE IF NOT EXISTS user_public_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    github_login TEXT NOT NULL,
+    key_type TEXT NOT NULL,
+    key_id TEXT,
+    fingerprint TEXT,
+    created_at TEXT,
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_public_keys_login ON user_public_keys(github_login);
+`
+
+// SQL queries for user public keys
+const insertUserPublicKey = `
+INSERT INTO user_public_keys (github_login, key_type, key_id, fingerprint, created_at, fetched_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+`
+
+const selectUserPublicKeys = `
+SELECT id, github_login, key_type, key_id, fingerprint, created_at, fetched_at
+FROM user_public_keys
+WHERE github_login = ?
+ORDER BY key_type ASC, created_at ASC
+`
+
+const deleteUserPublicKeys = `
+DELETE FROM user_public_keys WHERE github_login = ?
+`
+
+// Schema for user public events
+const createUserEventsTable = `
+CREATE TABLE IF NOT EXISTS user_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    github_login TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    repo_name TEXT,
+    url TEXT,
+    created_at TEXT,
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(github_login, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_events_login ON user_events(github_login);
+`
+
+// SQL queries for user events
+const insertUserEvent = `
+INSERT INTO user_events (github_login, event_id, event_type, repo_name, url, created_at, fetched_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+`
+
+const selectUserEvents = `
+SELECT id, github_login, event_id, event_type, repo_name, url, created_at, fetched_at
+FROM user_events
+WHERE github_login = ?
+ORDER BY created_at DESC
+`
+
+const deleteUserEvents = `
+DELETE FROM user_events WHERE github_login = ?
+`
+
 // Check if user has fetched data
 const selectUserHasData = `
 SELECT EXISTS(SELECT 1 FROM user_profiles WHERE login = ?)
@@ -494,6 +753,84 @@ ORDER BY timestamp DESC
 LIMIT ?
 `
 
+// Schema for API ETags (conditional request support)
+const createAPIETagsTable = `
+CREATE TABLE IF NOT EXISTS api_etags (
+    endpoint TEXT PRIMARY KEY,
+    etag TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// SQL queries for API ETags
+const upsertAPIETag = `
+INSERT INTO api_etags (endpoint, etag, updated_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(endpoint) DO UPDATE SET
+    etag = excluded.etag,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+const selectAPIETag = `
+SELECT etag FROM api_etags WHERE endpoint = ?
+`
+
+// Schema for GitHub account ID lookups (resolving ID-only noreply commit emails)
+// An empty login marks an ID that didn't resolve, e.g. a deleted account.
+const createGitHubUserIDsTable = `
+CREATE TABLE IF NOT EXISTS github_user_ids (
+    id INTEGER PRIMARY KEY,
+    login TEXT NOT NULL,
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+const upsertGitHubUserID = `
+INSERT INTO github_user_ids (id, login, fetched_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(id) DO UPDATE SET
+    login = excluded.login,
+    fetched_at = CURRENT_TIMESTAMP
+`
+
+const selectGitHubUserID = `
+SELECT login FROM github_user_ids WHERE id = ?
+`
+
+// Schema for cached Docker Hub search result pages (response JSON as returned by the API client)
+const createDockerSearchCacheTable = `
+CREATE TABLE IF NOT EXISTS docker_search_cache (
+    query TEXT NOT NULL,
+    page INTEGER NOT NULL,
+    response TEXT NOT NULL,
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (query, page)
+);
+`
+
+const upsertDockerSearch = `
+INSERT INTO docker_search_cache (query, page, response, fetched_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(query, page) DO UPDATE SET
+    response = excluded.response,
+    fetched_at = CURRENT_TIMESTAMP
+`
+
+const selectDockerSearch = `
+SELECT response, fetched_at FROM docker_search_cache WHERE query = ? AND page = ?
+`
+
+const selectCachedDockerSearches = `
+SELECT query, COUNT(*) as pages, MAX(fetched_at) as fetched_at
+FROM docker_search_cache
+GROUP BY query
+ORDER BY fetched_at DESC
+`
+
+const deleteStaleDockerSearches = `
+DELETE FROM docker_search_cache WHERE fetched_at < ?
+`
+
 // Schema for layer inspections (Docker image layer peek history)
 const createLayerInspectionsTable = `
 CREATE TABLE IF NOT EXISTS layer_inspections (
@@ -553,6 +890,39 @@ const updateLayerDownloaded = `
 UPDATE layer_inspections SET downloaded = TRUE, download_path = ? WHERE image_ref = ? AND layer_digest = ?
 `
 
+// Schema for layer secret findings (likely secrets found in layer file contents)
+// Findings belong to a layer digest; image_ref records which image the scan ran for
+const createLayerSecretFindingsTable = `
+CREATE TABLE IF NOT EXISTS layer_secret_findings (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    image_ref TEXT NOT NULL,
+    layer_digest TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    rule_name TEXT NOT NULL,
+    preview TEXT,
+    found_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_layer_secret_findings_digest ON layer_secret_findings(layer_digest);
+`
+
+// SQL queries for layer secret findings
+const insertLayerSecretFinding = `
+INSERT INTO layer_secret_findings (image_ref, layer_digest, file_path, rule_name, preview, found_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+`
+
+const selectLayerSecretFindingsByDigest = `
+SELECT id, image_ref, layer_digest, file_path, rule_name, preview, found_at
+FROM layer_secret_findings
+WHERE layer_digest = ?
+ORDER BY file_path ASC, id ASC
+`
+
+const deleteLayerSecretFindings = `
+DELETE FROM layer_secret_findings WHERE layer_digest = ?
+`
+
 // Schema for image manifests (stores build steps and other image metadata)
 const createImageManifestsTable = `
 CREATE TABLE IF NOT EXISTS image_manifests (
@@ -563,6 +933,7 @@ CREATE TABLE IF NOT EXISTS image_manifests (
     config_digest TEXT,
     layer_count INTEGER,
     total_size INTEGER,
+    image_config TEXT,
     fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -572,12 +943,12 @@ CREATE INDEX IF NOT EXISTS idx_image_manifests_ref ON image_manifests(image_ref)
 // SQL queries for image manifests
 const insertImageManifest = `
 INSERT OR REPLACE INTO image_manifests (
-    image_ref, platform, build_steps, config_digest, layer_count, total_size, fetched_at
-) VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+    image_ref, platform, build_steps, config_digest, layer_count, total_size, image_config, fetched_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 `
 
 const selectImageManifest = `
-SELECT id, image_ref, platform, build_steps, config_digest, layer_count, total_size, fetched_at
+SELECT id, image_ref, platform, build_steps, config_digest, layer_count, total_size, image_config, fetched_at
 FROM image_manifests
 WHERE image_ref = ?
 `
@@ -586,16 +957,42 @@ const selectImageManifestBuildSteps = `
 SELECT build_steps FROM image_manifests WHERE image_ref = ?
 `
 
+const selectImageManifestConfig = `
+SELECT image_config FROM image_manifests WHERE image_ref = ?
+`
+
+// Schema for cached registry tag listings
+const createImageTagsTable = `
+CREATE TABLE IF NOT EXISTS image_tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    image_ref TEXT NOT NULL UNIQUE,
+    tags TEXT,
+    fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// SQL queries for cached image tags
+const insertImageTags = `
+INSERT OR REPLACE INTO image_tags (image_ref, tags, fetched_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+`
+
+const selectImageTags = `
+SELECT tags, fetched_at FROM image_tags WHERE image_ref = ?
+`
+
 // Schema for wayback CDX records (Wayback Machine archive URLs)
 const createWaybackRecordsTable = `
 CREATE TABLE IF NOT EXISTS wayback_records (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     url TEXT UNIQUE NOT NULL,
+    url_key TEXT,
     domain TEXT NOT NULL,
     timestamp TEXT,
     status_code INTEGER,
     mime_type TEXT,
     tags TEXT DEFAULT '',
+    captures INTEGER DEFAULT 0,
     fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -605,29 +1002,50 @@ CREATE INDEX IF NOT EXISTS idx_wayback_mime ON wayback_records(mime_type);
 `
 
 // SQL queries for wayback records
+// Records with a capture count merge into an existing row for the same URL: counts add up and
+// the newest capture wins. Records without a count (captures = 0) are ignored if the URL exists.
 const insertWaybackRecord = `
-INSERT OR IGNORE INTO wayback_records (url, domain, timestamp, status_code, mime_type, tags)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO wayback_records (url, url_key, domain, timestamp, status_code, mime_type, tags, captures)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(url_key) DO UPDATE SET
+    captures = wayback_records.captures + excluded.captures,
+    status_code = CASE WHEN excluded.timestamp > wayback_records.timestamp THEN excluded.status_code ELSE wayback_records.status_code END,
+    mime_type = CASE WHEN excluded.timestamp > wayback_records.timestamp THEN excluded.mime_type ELSE wayback_records.mime_type END,
+    timestamp = MAX(wayback_records.timestamp, excluded.timestamp)
+WHERE excluded.captures > 0
+`
+
+const resetWaybackCaptures = `
+UPDATE wayback_records SET captures = 0 WHERE domain = ?
 `
 
 const selectWaybackRecords = `
-SELECT id, url, domain, timestamp, status_code, mime_type, tags, fetched_at
+SELECT id, url, domain, timestamp, status_code, mime_type, tags, captures, fetched_at
 FROM wayback_records
 WHERE domain = ?
 ORDER BY timestamp DESC
 `
 
 const selectWaybackRecordsByFilter = `
-SELECT id, url, domain, timestamp, status_code, mime_type, tags, fetched_at
+SELECT id, url, domain, timestamp, status_code, mime_type, tags, captures, fetched_at
 FROM wayback_records
 WHERE domain = ?
 AND (? = '' OR mime_type LIKE ?)
 AND (? = '' OR url LIKE ?)
 AND (? = '' OR tags LIKE ?)
-ORDER BY timestamp DESC
+ORDER BY CASE WHEN ? THEN captures ELSE 0 END DESC, timestamp DESC
 LIMIT ? OFFSET ?
 `
 
+// Search CDX URLs across all domains (LIKE wildcards in the keyword escaped)
+const searchWaybackURLs = `
+SELECT id, url, domain, timestamp, status_code, mime_type, tags, captures, fetched_at
+FROM wayback_records
+WHERE LOWER(url) LIKE ? ESCAPE '\'
+ORDER BY domain, url
+LIMIT ?
+`
+
 const selectWaybackRecordCount = `
 SELECT COUNT(*) FROM wayback_records WHERE domain = ?
 `
@@ -704,6 +1122,7 @@ CREATE TABLE IF NOT EXISTS target_domains (
     domain TEXT UNIQUE NOT NULL,
     vt_enumerated BOOLEAN DEFAULT FALSE,
     crtsh_enumerated BOOLEAN DEFAULT FALSE,
+    vt_cursor TEXT,
     added_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -717,11 +1136,26 @@ CREATE TABLE IF NOT EXISTS subdomains (
     domain TEXT NOT NULL,
     subdomain TEXT UNIQUE NOT NULL,
     source TEXT NOT NULL,
+    sources TEXT,
     cnames TEXT,
     alt_names TEXT,
     cert_expired BOOLEAN DEFAULT FALSE,
+    is_wildcard BOOLEAN DEFAULT FALSE,
     cdx_indexed BOOLEAN DEFAULT FALSE,
+    resolved_ips TEXT,
+    resolves BOOLEAN DEFAULT FALSE,
+    ptr_names TEXT,
+    http_status INTEGER DEFAULT 0,
+    https_status INTEGER DEFAULT 0,
+    server_header TEXT,
+    final_url TEXT,
     discovered_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    tls_subject TEXT,
+    tls_sans TEXT,
+    tls_issuer TEXT,
+    tls_not_before DATETIME,
+    tls_not_after DATETIME,
+    tls_host_mismatch BOOLEAN DEFAULT FALSE,
     FOREIGN KEY(domain) REFERENCES target_domains(domain) ON DELETE CASCADE
 );
 
@@ -730,6 +1164,11 @@ CREATE INDEX IF NOT EXISTS idx_subdomains_cdx ON subdomains(cdx_indexed);
 CREATE INDEX IF NOT EXISTS idx_subdomains_source ON subdomains(source);
 `
 
+// backfillSubdomainSources seeds sources from the single source stored before it existed
+const backfillSubdomainSources = `
+UPDATE subdomains SET sources = source WHERE sources IS NULL OR sources = ''
+`
+
 // SQL queries for target domains
 const insertTargetDomain = `
 INSERT OR IGNORE INTO target_domains (domain) VALUES (?)
@@ -755,42 +1194,78 @@ const updateTargetDomainCrtshEnumerated = `
 UPDATE target_domains SET crtsh_enumerated = TRUE WHERE domain = ?
 `
 
+const selectTargetDomainVTCursor = `
+SELECT COALESCE(vt_cursor, '') FROM target_domains WHERE domain = ?
+`
+
+const updateTargetDomainVTCursor = `
+UPDATE target_domains SET vt_cursor = ? WHERE domain = ?
+`
+
 const deleteTargetDomain = `
 DELETE FROM target_domains WHERE domain = ?
 `
 
 // SQL queries for subdomains
 const insertSubdomain = `
-INSERT OR IGNORE INTO subdomains (domain, subdomain, source, cnames, alt_names, cert_expired)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT OR IGNORE INTO subdomains (domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
+// updateSubdomainMerge folds a re-discovered subdomain into the stored row: the source is
+// appended to sources and new cnames/alt names are appended, each only when not already listed
 const updateSubdomainMerge = `
 UPDATE subdomains SET
-    cnames = CASE WHEN cnames IS NULL OR cnames = '' THEN ? ELSE cnames || ',' || ? END,
-    alt_names = CASE WHEN alt_names IS NULL OR alt_names = '' THEN ? ELSE alt_names || ',' || ? END,
-    cert_expired = CASE WHEN ? THEN TRUE ELSE cert_expired END
+    sources = CASE
+        WHEN instr(',' || COALESCE(NULLIF(sources, ''), source) || ',', ',' || ? || ',') > 0 THEN COALESCE(NULLIF(sources, ''), source)
+        ELSE COALESCE(NULLIF(sources, ''), source) || ',' || ? END,
+    cnames = CASE
+        WHEN ? = '' OR instr(',' || COALESCE(cnames, '') || ',', ',' || ? || ',') > 0 THEN cnames
+        WHEN cnames IS NULL OR cnames = '' THEN ? ELSE cnames || ',' || ? END,
+    alt_names = CASE
+        WHEN ? = '' OR instr(',' || COALESCE(alt_names, '') || ',', ',' || ? || ',') > 0 THEN alt_names
+        WHEN alt_names IS NULL OR alt_names = '' THEN ? ELSE alt_names || ',' || ? END,
+    cert_expired = CASE WHEN ? THEN TRUE ELSE cert_expired END,
+    is_wildcard = CASE WHEN ? THEN is_wildcard ELSE FALSE END
 WHERE subdomain = ?
 `
 
 const selectSubdomains = `
-SELECT id, domain, subdomain, source, cnames, alt_names, cert_expired, cdx_indexed, discovered_at
+SELECT id, domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch
 FROM subdomains
 WHERE domain = ?
 ORDER BY subdomain ASC
 `
 
 const selectSubdomainsFiltered = `
-SELECT id, domain, subdomain, source, cnames, alt_names, cert_expired, cdx_indexed, discovered_at
+SELECT id, domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch
 FROM subdomains
 WHERE domain = ?
 AND (? = '' OR subdomain LIKE ?)
-AND (? = '' OR source = ?)
+AND (? = '' OR instr(',' || sources || ',', ',' || ? || ',') > 0)
 AND (? = -1 OR cdx_indexed = ?)
-ORDER BY subdomain ASC
+AND (? = '' OR datetime(discovered_at) >= datetime(?))
+ORDER BY CASE WHEN ? = '' THEN NULL ELSE datetime(discovered_at) END DESC, subdomain ASC
 LIMIT ? OFFSET ?
 `
 
+// Subdomains of a domain discovered at or after a time, newest first
+const selectRecentSubdomains = `
+SELECT id, domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch
+FROM subdomains
+WHERE domain = ? AND datetime(discovered_at) >= datetime(?)
+ORDER BY datetime(discovered_at) DESC, subdomain ASC
+`
+
+// Search subdomains across all target domains (LIKE wildcards in the keyword escaped)
+const searchSubdomains = `
+SELECT id, domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch
+FROM subdomains
+WHERE LOWER(subdomain) LIKE ? ESCAPE '\'
+ORDER BY domain, subdomain
+LIMIT ?
+`
+
 const selectSubdomainCount = `
 SELECT COUNT(*) FROM subdomains WHERE domain = ?
 `
@@ -799,18 +1274,21 @@ const selectSubdomainCountFiltered = `
 SELECT COUNT(*) FROM subdomains
 WHERE domain = ?
 AND (? = '' OR subdomain LIKE ?)
-AND (? = '' OR source = ?)
+AND (? = '' OR instr(',' || sources || ',', ',' || ? || ',') > 0)
 AND (? = -1 OR cdx_indexed = ?)
+AND (? = '' OR datetime(discovered_at) >= datetime(?))
 `
 
 const selectSubdomainStats = `
 SELECT 
     COUNT(*) as total,
-    SUM(CASE WHEN source = 'virustotal' THEN 1 ELSE 0 END) as vt_count,
-    SUM(CASE WHEN source = 'crtsh' THEN 1 ELSE 0 END) as crtsh_count,
-    SUM(CASE WHEN source = 'import' THEN 1 ELSE 0 END) as import_count,
-    SUM(CASE WHEN cdx_indexed THEN 1 ELSE 0 END) as cdx_count,
-    SUM(CASE WHEN cert_expired THEN 1 ELSE 0 END) as expired_count
+    COALESCE(SUM(CASE WHEN instr(',' || sources || ',', ',virustotal,') > 0 THEN 1 ELSE 0 END), 0) as vt_count,
+    COALESCE(SUM(CASE WHEN instr(',' || sources || ',', ',crtsh,') > 0 THEN 1 ELSE 0 END), 0) as crtsh_count,
+    COALESCE(SUM(CASE WHEN instr(',' || sources || ',', ',securitytrails,') > 0 THEN 1 ELSE 0 END), 0) as securitytrails_count,
+    COALESCE(SUM(CASE WHEN instr(',' || sources || ',', ',censys,') > 0 THEN 1 ELSE 0 END), 0) as censys_count,
+    COALESCE(SUM(CASE WHEN instr(',' || sources || ',', ',import,') > 0 THEN 1 ELSE 0 END), 0) as import_count,
+    COALESCE(SUM(CASE WHEN cdx_indexed THEN 1 ELSE 0 END), 0) as cdx_count,
+    COALESCE(SUM(CASE WHEN cert_expired THEN 1 ELSE 0 END), 0) as expired_count
 FROM subdomains
 WHERE domain = ?
 `
@@ -819,6 +1297,20 @@ const updateSubdomainCDXIndexed = `
 UPDATE subdomains SET cdx_indexed = TRUE WHERE subdomain = ?
 `
 
+const updateSubdomainResolution = `
+UPDATE subdomains SET resolved_ips = ?, resolves = ? WHERE subdomain = ?
+`
+
+const updateSubdomainPTRNames = `
+UPDATE subdomains SET ptr_names = ? WHERE subdomain = ?
+`
+
+const updateSubdomainProbe = `
+UPDATE subdomains SET http_status = ?, https_status = ?, server_header = ?, final_url = ?,
+    tls_subject = ?, tls_sans = ?, tls_issuer = ?, tls_not_before = ?, tls_not_after = ?, tls_host_mismatch = ?
+WHERE subdomain = ?
+`
+
 const deleteSubdomain = `
 DELETE FROM subdomains WHERE id = ?
 `
@@ -827,8 +1319,53 @@ const deleteSubdomainsByDomain = `
 DELETE FROM subdomains WHERE domain = ?
 `
 
+// Schema for saved subdomain sets of a domain, compared against later to spot changes. The
+// set is a JSON array of subdomain names.
+const createSubdomainSnapshotsTable = `
+CREATE TABLE IF NOT EXISTS subdomain_snapshots (
+    domain TEXT NOT NULL,
+    label TEXT NOT NULL,
+    subdomains TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (domain, label)
+);
+`
+
+// Saving under an existing label replaces that snapshot
+const upsertSubdomainSnapshot = `
+INSERT INTO subdomain_snapshots (domain, label, subdomains, created_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT(domain, label) DO UPDATE SET
+    subdomains = excluded.subdomains,
+    created_at = CURRENT_TIMESTAMP
+`
+
+const selectSubdomainSnapshot = `
+SELECT subdomains, created_at FROM subdomain_snapshots WHERE domain = ? AND label = ?
+`
+
+// Snapshots of a domain with their sizes, newest first
+const selectSubdomainSnapshots = `
+SELECT label, json_array_length(subdomains), created_at
+FROM subdomain_snapshots
+WHERE domain = ?
+ORDER BY created_at DESC, label DESC
+`
+
+const deleteSubdomainSnapshot = `
+DELETE FROM subdomain_snapshots WHERE domain = ? AND label = ?
+`
+
+const deleteSubdomainSnapshotsByDomain = `
+DELETE FROM subdomain_snapshots WHERE domain = ?
+`
+
+const selectSubdomainNames = `
+SELECT subdomain FROM subdomains WHERE domain = ? ORDER BY subdomain ASC
+`
+
 const selectAllSubdomainsForDomain = `
-SELECT id, domain, subdomain, source, cnames, alt_names, cert_expired, cdx_indexed, discovered_at
+SELECT id, domain, subdomain, source, sources, cnames, alt_names, cert_expired, is_wildcard, cdx_indexed, resolved_ips, resolves, ptr_names, http_status, https_status, server_header, final_url, discovered_at, tls_subject, tls_sans, tls_issuer, tls_not_before, tls_not_after, tls_host_mismatch
 FROM subdomains
 WHERE domain = ?
 `
@@ -837,7 +1374,12 @@ WHERE domain = ?
 const selectTargetDomainsWithCounts = `
 SELECT 
     t.id, t.domain, t.vt_enumerated, t.crtsh_enumerated, t.added_at,
-    COUNT(s.id) as subdomain_count
+    COUNT(s.id) as subdomain_count,
+    SUM(CASE WHEN instr(',' || s.sources || ',', ',virustotal,') > 0 THEN 1 ELSE 0 END) as vt_count,
+    SUM(CASE WHEN instr(',' || s.sources || ',', ',crtsh,') > 0 THEN 1 ELSE 0 END) as crtsh_count,
+    SUM(CASE WHEN instr(',' || s.sources || ',', ',securitytrails,') > 0 THEN 1 ELSE 0 END) as securitytrails_count,
+    SUM(CASE WHEN instr(',' || s.sources || ',', ',censys,') > 0 THEN 1 ELSE 0 END) as censys_count,
+    SUM(CASE WHEN instr(',' || s.sources || ',', ',import,') > 0 THEN 1 ELSE 0 END) as import_count
 FROM target_domains t
 LEFT JOIN subdomains s ON t.domain = s.domain
 GROUP BY t.id
@@ -870,4 +1412,203 @@ SELECT value FROM app_settings WHERE key = ?
 
 const deleteSetting = `
 DELETE FROM app_settings WHERE key = ?
-`
\ No newline at end of file
+`
+
+const countSettingsWithPrefix = `
+SELECT COUNT(*) FROM app_settings WHERE value LIKE ?
+`
+
+const selectSettingWithPrefix = `
+SELECT value FROM app_settings WHERE value LIKE ? LIMIT 1
+`
+
+// =============================================================================
+// Local Keyword Search (FTS5)
+// =============================================================================
+
+// Full-text index over the text searched by SearchLocalKeyword. Each searchable field of a
+// source row gets its own entry with rowid = source rowid * 8 + field code, so entries can be
+// found and removed by rowid without scanning:
+//
+//	0 bio, 1 company, 2 location  (user_profiles)
+//	3 name, 4 description         (user_repositories)
+//	5 description                 (user_gists)
+//	6 name                        (gist_files)
+//
+// Triggers keep it in sync. The inserts use INSERT OR REPLACE, and REPLACE removes the old row
+// without firing delete triggers, so BEFORE INSERT triggers clear the entries of the row being
+// replaced first.
+const createSearchIndex = `
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(body, tokenize = 'unicode61 remove_diacritics 2');
+
+CREATE TRIGGER IF NOT EXISTS search_profiles_replace BEFORE INSERT ON user_profiles BEGIN
+    DELETE FROM search_index WHERE rowid BETWEEN
+        (SELECT rowid * 8 FROM user_profiles WHERE login = NEW.login) AND
+        (SELECT rowid * 8 + 2 FROM user_profiles WHERE login = NEW.login);
+END;
+CREATE TRIGGER IF NOT EXISTS search_profiles_insert AFTER INSERT ON user_profiles BEGIN
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.rowid * 8, NEW.bio WHERE COALESCE(NEW.bio, '') != ''
+    UNION ALL SELECT NEW.rowid * 8 + 1, NEW.company WHERE COALESCE(NEW.company, '') != ''
+    UNION ALL SELECT NEW.rowid * 8 + 2, NEW.location WHERE COALESCE(NEW.location, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_profiles_update AFTER UPDATE OF bio, company, location ON user_profiles BEGIN
+    DELETE FROM search_index WHERE rowid BETWEEN OLD.rowid * 8 AND OLD.rowid * 8 + 2;
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.rowid * 8, NEW.bio WHERE COALESCE(NEW.bio, '') != ''
+    UNION ALL SELECT NEW.rowid * 8 + 1, NEW.company WHERE COALESCE(NEW.company, '') != ''
+    UNION ALL SELECT NEW.rowid * 8 + 2, NEW.location WHERE COALESCE(NEW.location, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_profiles_delete AFTER DELETE ON user_profiles BEGIN
+    DELETE FROM search_index WHERE rowid BETWEEN OLD.rowid * 8 AND OLD.rowid * 8 + 2;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_repos_replace BEFORE INSERT ON user_repositories BEGIN
+    DELETE FROM search_index WHERE rowid IN (
+        SELECT id * 8 + 3 FROM user_repositories
+        WHERE github_login = NEW.github_login AND owner_login IS NEW.owner_login AND name IS NEW.name
+        UNION ALL
+        SELECT id * 8 + 4 FROM user_repositories
+        WHERE github_login = NEW.github_login AND owner_login IS NEW.owner_login AND name IS NEW.name
+    );
+END;
+CREATE TRIGGER IF NOT EXISTS search_repos_insert AFTER INSERT ON user_repositories BEGIN
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.id * 8 + 3, NEW.name WHERE COALESCE(NEW.name, '') != ''
+    UNION ALL SELECT NEW.id * 8 + 4, NEW.description WHERE COALESCE(NEW.description, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_repos_update AFTER UPDATE OF name, description ON user_repositories BEGIN
+    DELETE FROM search_index WHERE rowid BETWEEN OLD.id * 8 + 3 AND OLD.id * 8 + 4;
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.id * 8 + 3, NEW.name WHERE COALESCE(NEW.name, '') != ''
+    UNION ALL SELECT NEW.id * 8 + 4, NEW.description WHERE COALESCE(NEW.description, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_repos_delete AFTER DELETE ON user_repositories BEGIN
+    DELETE FROM search_index WHERE rowid BETWEEN OLD.id * 8 + 3 AND OLD.id * 8 + 4;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_gists_replace BEFORE INSERT ON user_gists BEGIN
+    DELETE FROM search_index WHERE rowid = (SELECT rowid * 8 + 5 FROM user_gists WHERE id = NEW.id);
+END;
+CREATE TRIGGER IF NOT EXISTS search_gists_insert AFTER INSERT ON user_gists WHEN COALESCE(NEW.description, '') != '' BEGIN
+    INSERT INTO search_index (rowid, body) VALUES (NEW.rowid * 8 + 5, NEW.description);
+END;
+CREATE TRIGGER IF NOT EXISTS search_gists_update AFTER UPDATE OF description ON user_gists BEGIN
+    DELETE FROM search_index WHERE rowid = OLD.rowid * 8 + 5;
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.rowid * 8 + 5, NEW.description WHERE COALESCE(NEW.description, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_gists_delete AFTER DELETE ON user_gists BEGIN
+    DELETE FROM search_index WHERE rowid = OLD.rowid * 8 + 5;
+END;
+
+CREATE TRIGGER IF NOT EXISTS search_gist_files_insert AFTER INSERT ON gist_files WHEN COALESCE(NEW.name, '') != '' BEGIN
+    INSERT INTO search_index (rowid, body) VALUES (NEW.id * 8 + 6, NEW.name);
+END;
+CREATE TRIGGER IF NOT EXISTS search_gist_files_update AFTER UPDATE OF name ON gist_files BEGIN
+    DELETE FROM search_index WHERE rowid = OLD.id * 8 + 6;
+    INSERT INTO search_index (rowid, body)
+    SELECT NEW.id * 8 + 6, NEW.name WHERE COALESCE(NEW.name, '') != '';
+END;
+CREATE TRIGGER IF NOT EXISTS search_gist_files_delete AFTER DELETE ON gist_files BEGIN
+    DELETE FROM search_index WHERE rowid = OLD.id * 8 + 6;
+END;
+`
+
+// Repopulates the search index from the source tables
+const rebuildSearchIndex = `
+DELETE FROM search_index;
+
+INSERT INTO search_index (rowid, body)
+SELECT rowid * 8, bio FROM user_profiles WHERE COALESCE(bio, '') != ''
+UNION ALL SELECT rowid * 8 + 1, company FROM user_profiles WHERE COALESCE(company, '') != ''
+UNION ALL SELECT rowid * 8 + 2, location FROM user_profiles WHERE COALESCE(location, '') != ''
+UNION ALL SELECT id * 8 + 3, name FROM user_repositories WHERE COALESCE(name, '') != ''
+UNION ALL SELECT id * 8 + 4, description FROM user_repositories WHERE COALESCE(description, '') != ''
+UNION ALL SELECT rowid * 8 + 5, description FROM user_gists WHERE COALESCE(description, '') != ''
+UNION ALL SELECT id * 8 + 6, name FROM gist_files WHERE COALESCE(name, '') != '';
+`
+
+// Full-text index of commit messages, keyed by the commits rowid. Indexing is opt-in per
+// project (the commit_message_search setting) as it roughly doubles the space messages take;
+// while it is off the insert trigger adds nothing and the index stays empty. As with
+// search_index, a BEFORE INSERT trigger clears the entry of a commit being replaced.
+const createCommitMessageIndex = `
+CREATE VIRTUAL TABLE IF NOT EXISTS commit_message_index USING fts5(message, tokenize = 'unicode61 remove_diacritics 2');
+
+CREATE TRIGGER IF NOT EXISTS commit_messages_replace BEFORE INSERT ON commits BEGIN
+    DELETE FROM commit_message_index WHERE rowid = (SELECT rowid FROM commits WHERE sha = NEW.sha);
+END;
+CREATE TRIGGER IF NOT EXISTS commit_messages_insert AFTER INSERT ON commits
+WHEN COALESCE(NEW.message, '') != ''
+    AND EXISTS (SELECT 1 FROM app_settings WHERE key = 'commit_message_search' AND value = 'true')
+BEGIN
+    INSERT INTO commit_message_index (rowid, message) VALUES (NEW.rowid, NEW.message);
+END;
+CREATE TRIGGER IF NOT EXISTS commit_messages_delete AFTER DELETE ON commits BEGIN
+    DELETE FROM commit_message_index WHERE rowid = OLD.rowid;
+END;
+`
+
+// Repopulates the commit message index, or empties it when indexing is off
+const rebuildCommitMessageIndex = `
+DELETE FROM commit_message_index;
+
+INSERT INTO commit_message_index (rowid, message)
+SELECT rowid, message FROM commits
+WHERE COALESCE(message, '') != ''
+    AND EXISTS (SELECT 1 FROM app_settings WHERE key = 'commit_message_search' AND value = 'true');
+`
+
+// Commit messages of one repo matching an FTS query, best match first, with a snippet of
+// the matching text
+const searchCommitMessages = `
+SELECT c.sha, COALESCE(c.author_name, ''), COALESCE(c.author_email, ''), COALESCE(c.github_author_login, ''),
+    COALESCE(c.author_date, ''), COALESCE(c.html_url, ''),
+    snippet(commit_message_index, 0, '', '', '...', 16)
+FROM commit_message_index
+JOIN commits c ON c.rowid = commit_message_index.rowid
+WHERE commit_message_index MATCH ? AND c.repo_owner = ? AND c.repo_name = ?
+ORDER BY commit_message_index.rank
+LIMIT ?
+`
+
+// Ranked local keyword search. Hits are decoded back to their source rows; a row matching in
+// several fields (e.g. repo name and description) is reported once, for its best-ranked field.
+const searchLocalKeyword = `
+WITH hits AS (
+    SELECT rowid / 8 AS src, rowid % 8 AS field, rank
+    FROM search_index
+    WHERE search_index MATCH ?
+),
+best AS (
+    SELECT src, field, MIN(rank) AS rank
+    FROM hits
+    GROUP BY src, CASE WHEN field <= 2 THEN 0 WHEN field <= 4 THEN 3 ELSE field END
+)
+SELECT up.login, up.name, up.email,
+    CASE best.field WHEN 0 THEN 'bio' WHEN 1 THEN 'company' ELSE 'location' END,
+    CASE best.field WHEN 0 THEN up.bio WHEN 1 THEN up.company ELSE up.location END,
+    best.rank
+FROM best JOIN user_profiles up ON up.rowid = best.src
+WHERE best.field <= 2
+UNION ALL
+SELECT ur.github_login, up.name, up.email, 'repo',
+    CASE best.field WHEN 3 THEN ur.name ELSE ur.description END,
+    best.rank
+FROM best JOIN user_repositories ur ON ur.id = best.src
+LEFT JOIN user_profiles up ON ur.github_login = up.login
+WHERE best.field IN (3, 4)
+UNION ALL
+SELECT ug.github_login, up.name, up.email, 'gist', ug.description, best.rank
+FROM best JOIN user_gists ug ON ug.rowid = best.src
+LEFT JOIN user_profiles up ON ug.github_login = up.login
+WHERE best.field = 5
+UNION ALL
+SELECT ug.github_login, up.name, up.email, 'gist_file', gf.name, best.rank
+FROM best JOIN gist_files gf ON gf.id = best.src
+JOIN user_gists ug ON gf.gist_id = ug.id
+LEFT JOIN user_profiles up ON ug.github_login = up.login
+WHERE best.field = 6
+ORDER BY 6
+`
@@ -0,0 +1,205 @@
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretEnvVar names the environment variable holding the passphrase for sensitive settings
+const SecretEnvVar = "GITSOME_SECRET"
+
+const (
+	// settingSecretSalt stores the per-database salt for the passphrase-derived key
+	settingSecretSalt = "secret_salt"
+
+	// encryptedPrefix marks a setting value as AES-GCM ciphertext (base64 of nonce + sealed value)
+	encryptedPrefix = "enc:v1:"
+
+	secretKeyIterations = 600000
+	secretKeyLength     = 32 // AES-256
+)
+
+// sensitiveSettings are encrypted at rest when a passphrase is configured
+var sensitiveSettings = map[string]bool{
+	SettingVirusTotalAPIKey:     true,
+	SettingSecurityTrailsAPIKey: true,
+	SettingCensysAPISecret:      true,
+	SettingDockerPassword:       true,
+}
+
+// IsSensitiveSetting reports whether a setting key is encrypted when a passphrase is configured
+func IsSensitiveSetting(key string) bool {
+	return sensitiveSettings[key]
+}
+
+// SetPassphrase derives the key for sensitive settings from passphrase and encrypts any of them
+// still stored in plaintext. An empty passphrase turns encryption off for this connection.
+func (db *DB) SetPassphrase(passphrase string) error {
+	if passphrase == "" {
+		db.secretKey = nil
+		return nil
+	}
+
+	salt, err := db.secretSalt()
+	if err != nil {
+		return err
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, secretKeyIterations, secretKeyLength)
+	if err != nil {
+		return fmt.Errorf("failed to derive settings key: %w", err)
+	}
+
+	// Refuse a passphrase that can't open values encrypted earlier rather than mixing keys
+	previous := db.secretKey
+	db.secretKey = key
+	if err := db.checkPassphrase(); err != nil {
+		db.secretKey = previous
+		return err
+	}
+
+	return db.encryptSensitiveSettings()
+}
+
+// HasPassphrase reports whether sensitive settings are being encrypted
+func (db *DB) HasPassphrase() bool {
+	return db.secretKey != nil
+}
+
+// HasEncryptedSettings reports whether any setting is stored encrypted
+func (db *DB) HasEncryptedSettings() (bool, error) {
+	var count int
+	err := db.conn.QueryRow(countSettingsWithPrefix, encryptedPrefix+"%").Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to count encrypted settings: %w", err)
+	}
+	return count > 0, nil
+}
+
+// HasPlaintextSecrets reports whether any sensitive setting is stored unencrypted
+func (db *DB) HasPlaintextSecrets() (bool, error) {
+	for key := range sensitiveSettings {
+		value, err := db.rawSetting(key)
+		if err != nil {
+			return false, err
+		}
+		if value != "" && !strings.HasPrefix(value, encryptedPrefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadPassphraseFromEnv applies the GITSOME_SECRET passphrase, if set
+func (db *DB) loadPassphraseFromEnv() error {
+	return db.SetPassphrase(os.Getenv(SecretEnvVar))
+}
+
+// encryptSensitiveSettings rewrites plaintext sensitive settings as ciphertext
+func (db *DB) encryptSensitiveSettings() error {
+	for key := range sensitiveSettings {
+		value, err := db.rawSetting(key)
+		if err != nil {
+			return err
+		}
+		if value == "" || strings.HasPrefix(value, encryptedPrefix) {
+			continue
+		}
+		if err := db.SetSetting(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPassphrase verifies the current key decrypts an existing encrypted setting, if any
+func (db *DB) checkPassphrase() error {
+	var value string
+	err := db.conn.QueryRow(selectSettingWithPrefix, encryptedPrefix+"%").Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read encrypted setting: %w", err)
+	}
+	_, err = db.decryptSetting(value)
+	return err
+}
+
+// secretSalt returns the database's key derivation salt, creating it on first use
+func (db *DB) secretSalt() ([]byte, error) {
+	encoded, err := db.rawSetting(settingSecretSalt)
+	if err != nil {
+		return nil, err
+	}
+	if encoded != "" {
+		salt, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode settings salt: %w", err)
+		}
+		return salt, nil
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate settings salt: %w", err)
+	}
+	if _, err := db.conn.Exec(upsertSetting, settingSecretSalt, base64.StdEncoding.EncodeToString(salt)); err != nil {
+		return nil, fmt.Errorf("failed to save settings salt: %w", err)
+	}
+	return salt, nil
+}
+
+// encryptSetting seals a value with the settings key
+func (db *DB) encryptSetting(value string) (string, error) {
+	gcm, err := db.settingsCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSetting opens a value sealed by encryptSetting
+func (db *DB) decryptSetting(value string) (string, error) {
+	if db.secretKey == nil {
+		return "", fmt.Errorf("setting is encrypted: set %s to the passphrase used to store it", SecretEnvVar)
+	}
+	gcm, err := db.settingsCipher()
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("failed to decode encrypted setting")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt setting: wrong passphrase?")
+	}
+	return string(plain), nil
+}
+
+// settingsCipher builds the AES-GCM cipher for the settings key
+func (db *DB) settingsCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(db.secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create settings cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create settings cipher: %w", err)
+	}
+	return gcm, nil
+}
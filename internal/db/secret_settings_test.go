@@ -0,0 +1,84 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSensitiveSettingsEncryption verifies API keys saved before a passphrase existed get
+// encrypted once one is configured, stay readable with it, and are refused without it
+func TestSensitiveSettingsEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.db")
+	t.Setenv(SecretEnvVar, "")
+
+	open := func() *DB {
+		t.Helper()
+		database, err := New(path)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		return database
+	}
+
+	// No passphrase: plaintext, as before
+	database := open()
+	if err := database.SetVirusTotalAPIKey("vt-key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetDockerCredentials("octocat", "hunter2-token"); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := database.rawSetting(SettingVirusTotalAPIKey); raw != "vt-key" {
+		t.Errorf("raw VT key without passphrase = %q", raw)
+	}
+	if plain, err := database.HasPlaintextSecrets(); err != nil || !plain {
+		t.Errorf("HasPlaintextSecrets() = %v, %v; want true", plain, err)
+	}
+	database.Close()
+
+	// Opening with a passphrase encrypts the existing sensitive values only
+	t.Setenv(SecretEnvVar, "correct horse")
+	database = open()
+	if raw, _ := database.rawSetting(SettingVirusTotalAPIKey); !strings.HasPrefix(raw, encryptedPrefix) {
+		t.Errorf("raw VT key after unlock = %q, want ciphertext", raw)
+	}
+	if raw, _ := database.rawSetting(SettingDockerUsername); raw != "octocat" {
+		t.Errorf("non-sensitive docker username = %q, want plaintext", raw)
+	}
+	if key, err := database.GetVirusTotalAPIKey(); err != nil || key != "vt-key" {
+		t.Errorf("GetVirusTotalAPIKey() = %q, %v", key, err)
+	}
+	if _, password, err := database.GetDockerCredentials(); err != nil || password != "hunter2-token" {
+		t.Errorf("docker password = %q, %v", password, err)
+	}
+	database.Close()
+
+	// A wrong passphrase still opens the database, with the settings left locked
+	t.Setenv(SecretEnvVar, "wrong")
+	database = open()
+	if database.HasPassphrase() {
+		t.Error("HasPassphrase() after wrong passphrase = true, want false")
+	}
+	if _, err := database.GetVirusTotalAPIKey(); err == nil {
+		t.Error("GetVirusTotalAPIKey() with wrong passphrase succeeded")
+	}
+	database.Close()
+
+	// Without one, encrypted values can't be read until the passphrase is supplied
+	t.Setenv(SecretEnvVar, "")
+	database = open()
+	defer database.Close()
+	if _, err := database.GetVirusTotalAPIKey(); err == nil {
+		t.Error("GetVirusTotalAPIKey() without passphrase succeeded")
+	}
+	if encrypted, err := database.HasEncryptedSettings(); err != nil || !encrypted {
+		t.Errorf("HasEncryptedSettings() = %v, %v; want true", encrypted, err)
+	}
+	if err := database.SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("SetPassphrase() error = %v", err)
+	}
+	if key, err := database.GetVirusTotalAPIKey(); err != nil || key != "vt-key" {
+		t.Errorf("GetVirusTotalAPIKey() after SetPassphrase = %q, %v", key, err)
+	}
+}
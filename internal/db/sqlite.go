@@ -3,12 +3,18 @@ package db
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 
 	_ "modernc.org/sqlite"
@@ -16,7 +22,9 @@ import (
 
 // DB wraps the SQLite database connection
 type DB struct {
-	conn *sql.DB
+	conn      *sql.DB
+	secretKey []byte      // Key for sensitive settings; nil stores them in plaintext
+	logger    *log.Logger // Child of the shared logger, prefixed "DB"
 }
 
 // New creates a new database connection and initializes the schema
@@ -33,6 +41,8 @@ func New(dbPath string) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	logger := logging.WithPrefix("DB")
+	logger.Debug("Opening database", "path", dbPath)
 
 	// Initialize schema
 	if _, err := conn.Exec(createCommitsTable); err != nil {
@@ -46,6 +56,12 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create links schema: %w", err)
 	}
 
+	// Initialize link group labels table
+	if _, err := conn.Exec(createLinkLabelsTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create link labels schema: %w", err)
+	}
+
 	// Initialize tags table
 	if _, err := conn.Exec(createTagsTable); err != nil {
 		conn.Close()
@@ -94,24 +110,54 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create user profiles schema: %w", err)
 	}
 
+	// Initialize user public keys table
+	if _, err := conn.Exec(createUserPublicKeysTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create user public keys schema: %w", err)
+	}
+
+	// Initialize user events table
+	if _, err := conn.Exec(createUserEventsTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create user events schema: %w", err)
+	}
+
 	// Initialize API logs table
 	if _, err := conn.Exec(createAPILogsTable); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("failed to create API logs schema: %w", err)
 	}
 
+	// Initialize API ETags table
+	if _, err := conn.Exec(createAPIETagsTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create API ETags schema: %w", err)
+	}
+
 	// Initialize layer inspections table
 	if _, err := conn.Exec(createLayerInspectionsTable); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("failed to create layer inspections schema: %w", err)
 	}
 
+	// Initialize layer secret findings table
+	if _, err := conn.Exec(createLayerSecretFindingsTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create layer secret findings schema: %w", err)
+	}
+
 	// Initialize image manifests table (for build steps)
 	if _, err := conn.Exec(createImageManifestsTable); err != nil {
 		conn.Close()
 		return nil, fmt.Errorf("failed to create image manifests schema: %w", err)
 	}
 
+	// Initialize image tags table (cached registry tag listings)
+	if _, err := conn.Exec(createImageTagsTable); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create image tags schema: %w", err)
+	}
+
 	// Initialize wayback records table (Wayback Machine CDX records)
 	if _, err := conn.Exec(createWaybackRecordsTable); err != nil {
 		conn.Close()
@@ -140,22 +186,22 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create settings schema: %w", err)
 	}
 
-	// Run migrations to add new columns to existing tables
-	// These will silently fail if columns already exist
-	migrations := []string{
-		"ALTER TABLE user_repositories ADD COLUMN commit_count INTEGER DEFAULT 0",
-		"ALTER TABLE user_gists ADD COLUMN revision_count INTEGER DEFAULT 0",
-		"ALTER TABLE user_repositories ADD COLUMN primary_language TEXT",
-		"ALTER TABLE user_repositories ADD COLUMN license_name TEXT",
-		"ALTER TABLE user_gists ADD COLUMN fork_count INTEGER DEFAULT 0",
-		"ALTER TABLE user_profiles ADD COLUMN organizations TEXT",
-		"ALTER TABLE layer_inspections ADD COLUMN contents TEXT",
+	// Bring tables created by older builds up to the current schema version
+	if err := migrate(conn, logger); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
-	for _, migration := range migrations {
-		conn.Exec(migration) // Ignore errors - column may already exist
+
+	db := &DB{conn: conn, logger: logger}
+
+	// Encrypt sensitive settings when GITSOME_SECRET is set. A passphrase that can't open them
+	// leaves the settings locked rather than keeping the rest of the database out of reach.
+	if err := db.loadPassphraseFromEnv(); err != nil {
+		db.secretKey = nil
+		logger.Warn("Sensitive settings stay locked", "env", SecretEnvVar, "error", err)
 	}
 
-	return &DB{conn: conn}, nil
+	return db, nil
 }
 
 // Close closes the database connection
@@ -163,6 +209,30 @@ func (db *DB) Close() error {
 	return db.conn.Close()
 }
 
+// Backup writes a transactionally consistent copy of the database to path using VACUUM INTO
+// Safe while the app holds the connection: the copy reflects committed data only, even with a
+// write transaction in flight. Fails if path already exists.
+func (db *DB) Backup(path string) error {
+	if _, err := db.conn.Exec("VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	return nil
+}
+
+// Optimize refreshes query planner statistics and rebuilds the database file to release free pages
+// VACUUM needs exclusive access, so run it while nothing else is using the database; if another
+// connection holds a lock it fails with a busy error and leaves the file untouched.
+func (db *DB) Optimize() error {
+	if _, err := db.conn.Exec("PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+	if _, err := db.conn.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	// VACUUM may renumber implicit rowids, which the search index is keyed on
+	return db.RebuildSearchIndex()
+}
+
 // ListProjectFiles returns a list of .db files in the given directory
 func ListProjectFiles(dir string) ([]string, error) {
 	if dir == "" {
@@ -201,7 +271,39 @@ func (db *DB) InsertCommits(records []models.CommitRecord) error {
 	}
 	defer stmt.Close()
 
+	clearCoAuthors, err := tx.Prepare(deleteCommitCoAuthors)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer clearCoAuthors.Close()
+
+	addCoAuthor, err := tx.Prepare(insertCoAuthor)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer addCoAuthor.Close()
+
+	clearFiles, err := tx.Prepare(deleteCommitFiles)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer clearFiles.Close()
+
+	addFile, err := tx.Prepare(insertCommitFile)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer addFile.Close()
+
 	for _, r := range records {
+		// NULL status means unknown; insertCommit then keeps what was stored before
+		var signed, verified, signingKey any
+		if r.Signature != nil {
+			signed, verified = r.Signature.Signed, r.Signature.Verified
+			if r.Signature.SigningKey != "" {
+				signingKey = r.Signature.SigningKey
+			}
+		}
 		_, err := stmt.Exec(
 			r.SHA,
 			r.Message,
@@ -216,10 +318,36 @@ func (db *DB) InsertCommits(records []models.CommitRecord) error {
 			r.HTMLURL,
 			r.RepoOwner,
 			r.RepoName,
+			signed,
+			verified,
+			signingKey,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert commit %s: %w", r.SHA, err)
 		}
+
+		// Replace rather than merge, so a re-fetched commit's co-authors match its message
+		if _, err := clearCoAuthors.Exec(r.SHA); err != nil {
+			return fmt.Errorf("failed to clear co-authors of %s: %w", r.SHA, err)
+		}
+		for _, ca := range r.CoAuthors {
+			if _, err := addCoAuthor.Exec(r.SHA, ca.Name, ca.Email); err != nil {
+				return fmt.Errorf("failed to insert co-author of %s: %w", r.SHA, err)
+			}
+		}
+
+		// Files are only known when fetched; a commit re-fetched without them keeps its old ones
+		if r.Files == nil {
+			continue
+		}
+		if _, err := clearFiles.Exec(r.SHA); err != nil {
+			return fmt.Errorf("failed to clear files of %s: %w", r.SHA, err)
+		}
+		for _, path := range r.Files {
+			if _, err := addFile.Exec(r.SHA, path); err != nil {
+				return fmt.Errorf("failed to insert file of %s: %w", r.SHA, err)
+			}
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -230,31 +358,138 @@ func (db *DB) InsertCommits(records []models.CommitRecord) error {
 }
 
 // GetCommitterStats returns contributor statistics grouped by committer
+// Co-authors from commit trailers are included as extra rows marked with
+// models.ContributorSourceCoAuthor; the total counts commits, not contributors.
 func (db *DB) GetCommitterStats(repoOwner, repoName string) ([]models.ContributorStats, int, error) {
 	total, err := db.getTotalCommits(repoOwner, repoName)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	rows, err := db.conn.Query(selectCommitterStats, repoOwner, repoName)
+	stats, err := db.queryCommitterStats(total, selectCommitterStats, repoOwner, repoName)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to query committer stats: %w", err)
+		return nil, 0, err
+	}
+	coAuthors, err := db.queryCoAuthorStats(total, repoOwner, repoName, "", commitDateUpperBound)
+	if err != nil {
+		return nil, 0, err
+	}
+	return mergeCoAuthorStats(stats, coAuthors), total, nil
+}
+
+// commitDateUpperBound sorts after every stored author_date, standing in for an open end
+const commitDateUpperBound = "9999-12-31T23:59:59Z"
+
+// GetCommitterStatsInRange returns committer statistics for commits authored between from and
+// to, both inclusive. A zero from or to leaves that end open. Percentages are of the commits in
+// the range, which is also the total returned.
+func (db *DB) GetCommitterStatsInRange(repoOwner, repoName string, from, to time.Time) ([]models.ContributorStats, int, error) {
+	lower, upper := "", commitDateUpperBound
+	if !from.IsZero() {
+		lower = from.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	if !to.IsZero() {
+		upper = to.UTC().Format("2006-01-02T15:04:05Z")
+	}
+
+	var total int
+	err := db.conn.QueryRow(selectTotalCommitsInRange, repoOwner, repoName, lower, upper).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get total commits in range: %w", err)
+	}
+
+	stats, err := db.queryCommitterStats(total, selectCommitterStatsInRange, repoOwner, repoName, lower, upper)
+	if err != nil {
+		return nil, 0, err
+	}
+	coAuthors, err := db.queryCoAuthorStats(total, repoOwner, repoName, lower, upper)
+	if err != nil {
+		return nil, 0, err
+	}
+	return mergeCoAuthorStats(stats, coAuthors), total, nil
+}
+
+// queryCoAuthorStats counts co-authored commits in a repo with author_date between lower and upper
+func (db *DB) queryCoAuthorStats(total int, repoOwner, repoName, lower, upper string) ([]models.ContributorStats, error) {
+	rows, err := db.conn.Query(selectCoAuthorStats, repoOwner, repoName, lower, upper)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query co-author stats: %w", err)
 	}
 	defer rows.Close()
 
 	var stats []models.ContributorStats
 	for rows.Next() {
 		var s models.ContributorStats
-		if err := rows.Scan(&s.Name, &s.Email, &s.GitHubLogin, &s.CommitCount); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		var name sql.NullString
+		if err := rows.Scan(&name, &s.Email, &s.CommitCount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
+		s.Name = name.String
+		s.Source = models.ContributorSourceCoAuthor
 		if total > 0 {
 			s.Percentage = float64(s.CommitCount) / float64(total) * 100
 		}
 		stats = append(stats, s)
 	}
 
-	return stats, total, nil
+	return stats, nil
+}
+
+// mergeCoAuthorStats appends co-author rows to committer stats, keeping commit count order
+// Committers come first on ties.
+func mergeCoAuthorStats(stats, coAuthors []models.ContributorStats) []models.ContributorStats {
+	if len(coAuthors) == 0 {
+		return stats
+	}
+	stats = append(stats, coAuthors...)
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].CommitCount > stats[j].CommitCount
+	})
+	return stats
+}
+
+// queryCommitterStats runs a committer stats query, computing percentages over total
+func (db *DB) queryCommitterStats(total int, query string, args ...any) ([]models.ContributorStats, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query committer stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.ContributorStats
+	for rows.Next() {
+		var s models.ContributorStats
+		var keys string
+		if err := rows.Scan(&s.Name, &s.Email, &s.GitHubLogin, &s.CommitCount,
+			&s.SignatureKnown, &s.SignedCommits, &s.VerifiedCommits, &keys); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		s.SigningKeys = splitSigningKeys(keys)
+		if total > 0 {
+			s.Percentage = float64(s.CommitCount) / float64(total) * 100
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
+// splitSigningKeys splits a GROUP_CONCAT of signing keys into a sorted list, dropping
+// duplicates that come from concatenating per-repo groups
+func splitSigningKeys(concat string) []string {
+	if concat == "" {
+		return nil
+	}
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(concat, ",") {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // GetAuthorStats returns contributor statistics grouped by author
@@ -312,6 +547,48 @@ func (db *DB) GetLatestCommitSHA(repoOwner, repoName string) (string, error) {
 	return sha, nil
 }
 
+// topFileEditors is how many editors GetTopFiles lists per file
+const topFileEditors = 3
+
+// GetTopFiles returns a repository's most frequently changed file paths, each with its top
+// editors. Only commits fetched with their files count.
+func (db *DB) GetTopFiles(repoOwner, repoName string, limit int) ([]models.FileStats, error) {
+	rows, err := db.conn.Query(selectTopFiles, repoOwner, repoName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top files: %w", err)
+	}
+	var files []models.FileStats
+	for rows.Next() {
+		var f models.FileStats
+		if err := rows.Scan(&f.Path, &f.Commits); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan top file: %w", err)
+		}
+		files = append(files, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query top files: %w", err)
+	}
+
+	for i := range files {
+		editors, err := db.conn.Query(selectFileEditors, repoOwner, repoName, files[i].Path, topFileEditors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query file editors: %w", err)
+		}
+		for editors.Next() {
+			var e models.FileEditor
+			if err := editors.Scan(&e.Name, &e.Email, &e.Login, &e.Commits); err != nil {
+				editors.Close()
+				return nil, fmt.Errorf("failed to scan file editor: %w", err)
+			}
+			files[i].Editors = append(files[i].Editors, e)
+		}
+		editors.Close()
+	}
+	return files, nil
+}
+
 // GetNextGroupID returns the next available group ID for linking
 func (db *DB) GetNextGroupID(repoOwner, repoName string) (int, error) {
 	var maxID int
@@ -351,15 +628,93 @@ func (db *DB) GetLinks(repoOwner, repoName string) (map[string]int, error) {
 	return links, nil
 }
 
+// GetLinkGroups returns the link groups of a repository with their labels and each member's
+// name, login and commit count. An empty repoOwner returns the groups of every repository.
+func (db *DB) GetLinkGroups(repoOwner, repoName string) ([]models.LinkGroup, error) {
+	rows, err := db.conn.Query(selectLinkGroupMembers, repoOwner, repoOwner, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query link groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []models.LinkGroup
+	for rows.Next() {
+		var owner, name, label string
+		var groupID int
+		var m models.LinkGroupMember
+		if err := rows.Scan(&owner, &name, &groupID, &m.Email, &m.Name, &m.Login, &m.Commits, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan link group member: %w", err)
+		}
+		m.Repo = owner + "/" + name
+		// Rows arrive ordered by repo then group, so a new group starts when either changes
+		if n := len(groups); n == 0 || groups[n-1].GroupID != groupID || groups[n-1].Repos[0] != m.Repo {
+			groups = append(groups, models.LinkGroup{GroupID: groupID, Label: label, Repos: []string{m.Repo}})
+		}
+		groups[len(groups)-1].Members = append(groups[len(groups)-1].Members, m)
+	}
+	return groups, rows.Err()
+}
+
 // RemoveLink removes a link for a committer
 func (db *DB) RemoveLink(repoOwner, repoName, email string) error {
 	_, err := db.conn.Exec(deleteLink, repoOwner, repoName, email)
 	if err != nil {
 		return fmt.Errorf("failed to remove link: %w", err)
 	}
+	if _, err := db.conn.Exec(deleteOrphanGroupLabels, repoOwner, repoName, repoOwner, repoName); err != nil {
+		return fmt.Errorf("failed to remove group label: %w", err)
+	}
+	return nil
+}
+
+// SaveGroupLabel names a link group; an empty label clears it
+func (db *DB) SaveGroupLabel(repoOwner, repoName string, groupID int, label string) error {
+	label = strings.TrimSpace(label)
+	var err error
+	if label == "" {
+		_, err = db.conn.Exec(deleteGroupLabel, repoOwner, repoName, groupID)
+	} else {
+		_, err = db.conn.Exec(upsertGroupLabel, repoOwner, repoName, groupID, label)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save group label: %w", err)
+	}
 	return nil
 }
 
+// GetGroupLabel returns the label of a link group, or "" if it has none
+func (db *DB) GetGroupLabel(repoOwner, repoName string, groupID int) (string, error) {
+	var label string
+	err := db.conn.QueryRow(selectGroupLabel, repoOwner, repoName, groupID).Scan(&label)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get group label: %w", err)
+	}
+	return label, nil
+}
+
+// GetGroupLabels returns a map of group ID to label for a repository's labeled link groups
+func (db *DB) GetGroupLabels(repoOwner, repoName string) (map[int]string, error) {
+	rows, err := db.conn.Query(selectGroupLabels, repoOwner, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[int]string)
+	for rows.Next() {
+		var groupID int
+		var label string
+		if err := rows.Scan(&groupID, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan group label: %w", err)
+		}
+		labels[groupID] = label
+	}
+	return labels, rows.Err()
+}
+
 // SaveTag tags a committer for future bulk actions
 func (db *DB) SaveTag(repoOwner, repoName, email string) error {
 	_, err := db.conn.Exec(insertTag, repoOwner, repoName, email)
@@ -378,6 +733,15 @@ func (db *DB) RemoveTag(repoOwner, repoName, email string) error {
 	return nil
 }
 
+// ClearTags removes all committer tags for a repository
+func (db *DB) ClearTags(repoOwner, repoName string) error {
+	_, err := db.conn.Exec(deleteAllTags, repoOwner, repoName)
+	if err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	return nil
+}
+
 // GetTags returns a set of tagged committer emails for a repository
 func (db *DB) GetTags(repoOwner, repoName string) (map[string]bool, error) {
 	rows, err := db.conn.Query(selectTags, repoOwner, repoName)
@@ -436,14 +800,32 @@ func (db *DB) GetDomains() (map[string]int, error) {
 	return domains, nil
 }
 
-// GetNextDomainColorIndex returns the next available color index for domains (global)
+// GetNextDomainColorIndex returns the lowest color index not used by any domain (global)
+// Filling gaps left by deleted domains keeps existing domains' colors stable.
 func (db *DB) GetNextDomainColorIndex() (int, error) {
-	var maxIndex int
-	err := db.conn.QueryRow(selectMaxDomainColorIndex).Scan(&maxIndex)
+	rows, err := db.conn.Query(selectDomainColorIndexes)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get max domain color index: %w", err)
+		return 0, fmt.Errorf("failed to get domain color indexes: %w", err)
+	}
+	defer rows.Close()
+
+	used := make(map[int]bool)
+	for rows.Next() {
+		var idx int
+		if err := rows.Scan(&idx); err != nil {
+			return 0, fmt.Errorf("failed to scan domain color index: %w", err)
+		}
+		used[idx] = true
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read domain color indexes: %w", err)
 	}
-	return maxIndex + 1, nil
+
+	next := 0
+	for used[next] {
+		next++
+	}
+	return next, nil
 }
 
 // RemoveDomain removes a highlight domain (global)
@@ -513,9 +895,12 @@ func (db *DB) GetCombinedCommitterStats() ([]models.ContributorStats, int, error
 	var stats []models.ContributorStats
 	for rows.Next() {
 		var s models.ContributorStats
-		if err := rows.Scan(&s.Name, &s.Email, &s.GitHubLogin, &s.CommitCount); err != nil {
+		var keys string
+		if err := rows.Scan(&s.Name, &s.Email, &s.GitHubLogin, &s.CommitCount,
+			&s.SignatureKnown, &s.SignedCommits, &s.VerifiedCommits, &keys); err != nil {
 			return nil, 0, fmt.Errorf("failed to scan combined stats row: %w", err)
 		}
+		s.SigningKeys = splitSigningKeys(keys)
 		if total > 0 {
 			s.Percentage = float64(s.CommitCount) / float64(total) * 100
 		}
@@ -661,7 +1046,16 @@ func (db *DB) DeleteCommitterByEmail(repoOwner, repoName, email string) error {
 	return nil
 }
 
-// DeleteRepositoryData removes all commits, tags, and links for a repository
+// DeleteRepositoryDataPreview returns how many commits and committers DeleteRepositoryData would remove
+func (db *DB) DeleteRepositoryDataPreview(repoOwner, repoName string) (commits, committers int, err error) {
+	err = db.conn.QueryRow(selectRepositoryDataCounts, repoOwner, repoName).Scan(&commits, &committers)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to preview repository delete: %w", err)
+	}
+	return commits, committers, nil
+}
+
+// DeleteRepositoryData removes all commits, tags, links and link labels for a repository
 func (db *DB) DeleteRepositoryData(repoOwner, repoName string) error {
 	// Delete all commits for this repo
 	_, err := db.conn.Exec("DELETE FROM commits WHERE repo_owner = ? AND repo_name = ?", repoOwner, repoName)
@@ -674,6 +1068,7 @@ func (db *DB) DeleteRepositoryData(repoOwner, repoName string) error {
 
 	// Delete all links for this repo
 	db.conn.Exec("DELETE FROM committer_links WHERE repo_owner = ? AND repo_name = ?", repoOwner, repoName)
+	db.conn.Exec("DELETE FROM link_group_labels WHERE repo_owner = ? AND repo_name = ?", repoOwner, repoName)
 
 	return nil
 }
@@ -891,6 +1286,116 @@ func (db *DB) GetGistFiles(gistID string) ([]models.GistFile, error) {
 	return files, nil
 }
 
+// SaveUserPublicKeys replaces the stored public keys for a user
+func (db *DB) SaveUserPublicKeys(githubLogin string, keys []models.UserPublicKey) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(deleteUserPublicKeys, githubLogin); err != nil {
+		return fmt.Errorf("failed to clear user public keys: %w", err)
+	}
+
+	stmt, err := tx.Prepare(insertUserPublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, k := range keys {
+		if _, err := stmt.Exec(githubLogin, k.KeyType, k.KeyID, k.Fingerprint, k.CreatedAt); err != nil {
+			return fmt.Errorf("failed to save public key %s: %w", k.Fingerprint, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetUserPublicKeys returns the stored SSH and GPG keys for a user
+func (db *DB) GetUserPublicKeys(githubLogin string) ([]models.UserPublicKey, error) {
+	rows, err := db.conn.Query(selectUserPublicKeys, githubLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user public keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []models.UserPublicKey
+	for rows.Next() {
+		var k models.UserPublicKey
+		var keyID, fingerprint, createdAt sql.NullString
+		var fetchedAt string
+		if err := rows.Scan(&k.ID, &k.GitHubLogin, &k.KeyType, &keyID, &fingerprint, &createdAt, &fetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user public key: %w", err)
+		}
+		k.KeyID = keyID.String
+		k.Fingerprint = fingerprint.String
+		k.CreatedAt = createdAt.String
+		k.FetchedAt, _ = parseTimestamp(fetchedAt)
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// SaveUserEvents replaces the stored public events for a user
+func (db *DB) SaveUserEvents(githubLogin string, events []models.UserEvent) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(deleteUserEvents, githubLogin); err != nil {
+		return fmt.Errorf("failed to clear user events: %w", err)
+	}
+
+	stmt, err := tx.Prepare(insertUserEvent)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, e := range events {
+		if _, err := stmt.Exec(githubLogin, e.EventID, e.Type, e.RepoName, e.URL, e.CreatedAt); err != nil {
+			return fmt.Errorf("failed to save user event %s: %w", e.EventID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetUserEvents returns the stored public events for a user, newest first
+func (db *DB) GetUserEvents(githubLogin string) ([]models.UserEvent, error) {
+	rows, err := db.conn.Query(selectUserEvents, githubLogin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.UserEvent
+	for rows.Next() {
+		var e models.UserEvent
+		var repoName, url, createdAt sql.NullString
+		var fetchedAt string
+		if err := rows.Scan(&e.ID, &e.GitHubLogin, &e.EventID, &e.Type, &repoName, &url, &createdAt, &fetchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user event: %w", err)
+		}
+		e.RepoName = repoName.String
+		e.URL = url.String
+		e.CreatedAt = createdAt.String
+		e.FetchedAt, _ = parseTimestamp(fetchedAt)
+		events = append(events, e)
+	}
+	return events, nil
+}
+
 // GetGistComments returns all comments for a gist
 func (db *DB) GetGistComments(gistID string) ([]models.GistComment, error) {
 	rows, err := db.conn.Query(selectGistComments, gistID)
@@ -1045,6 +1550,50 @@ func (db *DB) SaveAPILog(method, endpoint string, statusCode int, errorMsg strin
 	return nil
 }
 
+// SaveETag stores the ETag returned for an API endpoint
+func (db *DB) SaveETag(endpoint, etag string) error {
+	_, err := db.conn.Exec(upsertAPIETag, endpoint, etag)
+	if err != nil {
+		return fmt.Errorf("failed to save ETag: %w", err)
+	}
+	return nil
+}
+
+// GetETag returns the stored ETag for an API endpoint, or empty string if none
+func (db *DB) GetETag(endpoint string) (string, error) {
+	var etag string
+	err := db.conn.QueryRow(selectAPIETag, endpoint).Scan(&etag)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get ETag: %w", err)
+	}
+	return etag, nil
+}
+
+// SaveLoginByID caches the login for a GitHub account ID; empty marks an unresolvable ID
+func (db *DB) SaveLoginByID(id int64, login string) error {
+	_, err := db.conn.Exec(upsertGitHubUserID, id, login)
+	if err != nil {
+		return fmt.Errorf("failed to save GitHub user ID: %w", err)
+	}
+	return nil
+}
+
+// GetLoginByID returns the cached login for a GitHub account ID and whether it was cached
+func (db *DB) GetLoginByID(id int64) (string, bool, error) {
+	var login string
+	err := db.conn.QueryRow(selectGitHubUserID, id).Scan(&login)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get GitHub user ID: %w", err)
+	}
+	return login, true, nil
+}
+
 // GetAPILogs returns the most recent API logs
 func (db *DB) GetAPILogs(limit int) ([]map[string]interface{}, error) {
 	rows, err := db.conn.Query(selectAPILogs, limit)
@@ -1289,6 +1838,83 @@ func (db *DB) SearchUsersWithDockerAndDomains() ([]models.ContributorStats, int,
 	return stats, totalCommits, nil
 }
 
+// likePattern builds a case-insensitive LIKE pattern matching keyword anywhere, for use with
+// LOWER(column) LIKE ? ESCAPE '\'. The keyword matches literally - % and _ are LIKE wildcards.
+func likePattern(keyword string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + strings.ToLower(escaper.Replace(keyword)) + "%"
+}
+
+// GlobalCommitterSearchLimit caps how many committers SearchCommittersGlobal returns
+const GlobalCommitterSearchLimit = 500
+
+// SearchCommittersGlobal finds committers in every tracked repo whose name, email or GitHub
+// login contains keyword (case-insensitive), aggregated by email, most commits first. Names
+// note the repos the committer appears in, e.g. "Alice [acme/api +2]". At most
+// GlobalCommitterSearchLimit committers are returned; the total counts their commits.
+func (db *DB) SearchCommittersGlobal(keyword string) ([]models.ContributorStats, int, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []models.ContributorStats{}, 0, nil
+	}
+
+	pattern := likePattern(keyword)
+
+	query := `
+		SELECT
+			MAX(committer_name),
+			committer_email,
+			COALESCE(MAX(github_committer_login), '') as github_login,
+			COUNT(*) as commit_count,
+			GROUP_CONCAT(DISTINCT repo_owner || '/' || repo_name) as repos
+		FROM commits
+		WHERE LOWER(committer_name) LIKE ? ESCAPE '\'
+			OR LOWER(committer_email) LIKE ? ESCAPE '\'
+			OR LOWER(COALESCE(github_committer_login, '')) LIKE ? ESCAPE '\'
+		GROUP BY committer_email
+		ORDER BY commit_count DESC, committer_email
+		LIMIT ?
+	`
+	rows, err := db.conn.Query(query, pattern, pattern, pattern, GlobalCommitterSearchLimit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search committers: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []models.ContributorStats
+	totalCommits := 0
+	for rows.Next() {
+		var s models.ContributorStats
+		var repos string
+		if err := rows.Scan(&s.Name, &s.Email, &s.GitHubLogin, &s.CommitCount, &repos); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan committer: %w", err)
+		}
+		s.Name += " " + repoOriginLabel(strings.Split(repos, ","))
+		totalCommits += s.CommitCount
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to search committers: %w", err)
+	}
+
+	for i := range stats {
+		if totalCommits > 0 {
+			stats[i].Percentage = float64(stats[i].CommitCount) / float64(totalCommits) * 100
+		}
+	}
+
+	return stats, totalCommits, nil
+}
+
+// repoOriginLabel names the first repo (alphabetically) and how many others, e.g. "[acme/api +2]"
+func repoOriginLabel(repos []string) string {
+	sort.Strings(repos)
+	if len(repos) == 1 {
+		return "[" + repos[0] + "]"
+	}
+	return fmt.Sprintf("[%s +%d]", repos[0], len(repos)-1)
+}
+
 // LocalSearchResult represents a match from local keyword search
 type LocalSearchResult struct {
 	Login       string
@@ -1434,6 +2060,60 @@ func (db *DB) UpdateLayerDownloaded(imageRef, layerDigest, downloadPath string)
 	return nil
 }
 
+// SaveLayerSecretFindings replaces the stored secret findings for a layer digest
+// An empty slice records that the layer was scanned clean by clearing old findings.
+func (db *DB) SaveLayerSecretFindings(layerDigest string, findings []models.LayerSecretFinding) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(deleteLayerSecretFindings, layerDigest); err != nil {
+		return fmt.Errorf("failed to clear layer secret findings: %w", err)
+	}
+
+	stmt, err := tx.Prepare(insertLayerSecretFinding)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, f := range findings {
+		if _, err := stmt.Exec(f.ImageRef, layerDigest, f.FilePath, f.RuleName, f.Preview); err != nil {
+			return fmt.Errorf("failed to save layer secret finding %s: %w", f.FilePath, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetLayerSecretFindings returns the stored secret findings for a layer digest
+func (db *DB) GetLayerSecretFindings(layerDigest string) ([]models.LayerSecretFinding, error) {
+	rows, err := db.conn.Query(selectLayerSecretFindingsByDigest, layerDigest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query layer secret findings: %w", err)
+	}
+	defer rows.Close()
+
+	var findings []models.LayerSecretFinding
+	for rows.Next() {
+		var f models.LayerSecretFinding
+		var preview sql.NullString
+		var foundAt string
+		if err := rows.Scan(&f.ID, &f.ImageRef, &f.LayerDigest, &f.FilePath, &f.RuleName, &preview, &foundAt); err != nil {
+			return nil, fmt.Errorf("failed to scan layer secret finding: %w", err)
+		}
+		f.Preview = preview.String
+		f.FoundAt, _ = parseTimestamp(foundAt)
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
 // CachedImageRow represents a row from the distinct cached images query
 type CachedImageRow struct {
 	ImageRef   string
@@ -1536,162 +2216,233 @@ func (db *DB) SearchLayerContents(pattern string) ([]LayerSearchResult, error) {
 	return results, nil
 }
 
-// SearchLocalKeyword searches user profiles, repos, and gists for a keyword
-func (db *DB) SearchLocalKeyword(keyword string) ([]LocalSearchResult, error) {
+// BuildStepSearchResult represents a search hit in the build steps of a cached image manifest
+type BuildStepSearchResult struct {
+	ImageRef  string
+	StepIndex int    // Zero-based position of the step in the image history
+	Step      string // The matching build step
+}
+
+// SearchBuildSteps searches the build steps of all cached image manifests (case-insensitive substring)
+func (db *DB) SearchBuildSteps(keyword string) ([]BuildStepSearchResult, error) {
 	if keyword == "" {
-		return []LocalSearchResult{}, nil
+		return []BuildStepSearchResult{}, nil
 	}
 
-	var results []LocalSearchResult
-	pattern := "%" + keyword + "%"
-
-	// Search user profiles (bio, company, location)
-	profileQuery := `
-		SELECT login, name, email, 
-			CASE 
-				WHEN bio LIKE ? THEN 'bio'
-				WHEN company LIKE ? THEN 'company'
-				WHEN location LIKE ? THEN 'location'
-			END as match_type,
-			CASE 
-				WHEN bio LIKE ? THEN bio
-				WHEN company LIKE ? THEN company
-				WHEN location LIKE ? THEN location
-			END as match_source
-		FROM user_profiles
-		WHERE bio LIKE ? OR company LIKE ? OR location LIKE ?
-	`
-	rows, err := db.conn.Query(profileQuery, pattern, pattern, pattern, pattern, pattern, pattern, pattern, pattern, pattern)
+	// LIKE narrows the rows; the JSON is parsed below to find the matching steps
+	rows, err := db.conn.Query(`
+		SELECT image_ref, build_steps
+		FROM image_manifests
+		WHERE build_steps LIKE ?
+		ORDER BY fetched_at DESC
+	`, "%"+keyword+"%")
 	if err != nil {
-		return nil, fmt.Errorf("failed to search profiles: %w", err)
+		return nil, fmt.Errorf("failed to search build steps: %w", err)
 	}
 	defer rows.Close()
 
+	lowerKeyword := strings.ToLower(keyword)
+	var results []BuildStepSearchResult
 	for rows.Next() {
-		var r LocalSearchResult
-		var matchType, matchSource sql.NullString
-		if err := rows.Scan(&r.Login, &r.Name, &r.Email, &matchType, &matchSource); err != nil {
+		var imageRef string
+		var buildSteps sql.NullString
+		if err := rows.Scan(&imageRef, &buildSteps); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if !buildSteps.Valid {
 			continue
 		}
-		if matchType.Valid {
-			r.MatchType = matchType.String
+
+		var steps []string
+		if err := json.Unmarshal([]byte(buildSteps.String), &steps); err != nil {
+			continue // Skip malformed JSON
 		}
-		if matchSource.Valid {
-			r.MatchSource = matchSource.String
+		for i, step := range steps {
+			if strings.Contains(strings.ToLower(step), lowerKeyword) {
+				results = append(results, BuildStepSearchResult{ImageRef: imageRef, StepIndex: i, Step: step})
+			}
 		}
-		results = append(results, r)
 	}
+	return results, rows.Err()
+}
 
-	// Search user repositories (name, description)
-	// Show what actually matched - repo name or description
-	repoQuery := `
-		SELECT ur.github_login, up.name, up.email,
-			'repo' as match_type,
-			CASE 
-				WHEN ur.name LIKE ? THEN ur.name
-				ELSE ur.description
-			END as match_source
-		FROM user_repositories ur
-		LEFT JOIN user_profiles up ON ur.github_login = up.login
-		WHERE ur.name LIKE ? OR ur.description LIKE ?
-	`
-	rows2, err := db.conn.Query(repoQuery, pattern, pattern, pattern)
+// SearchLocalKeyword searches user profiles, repos, and gists for a keyword
+// Uses the full-text search index; results come back best match first. Each word of the
+// keyword must appear, matching whole words or word prefixes.
+func (db *DB) SearchLocalKeyword(keyword string) ([]LocalSearchResult, error) {
+	query := ftsQuery(keyword)
+	if query == "" {
+		return []LocalSearchResult{}, nil
+	}
+
+	rows, err := db.conn.Query(searchLocalKeyword, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search repos: %w", err)
+		return nil, fmt.Errorf("failed to search local data: %w", err)
 	}
-	defer rows2.Close()
+	defer rows.Close()
 
-	for rows2.Next() {
+	var results []LocalSearchResult
+	for rows.Next() {
 		var r LocalSearchResult
-		var name, email sql.NullString
-		var matchSource sql.NullString
-		if err := rows2.Scan(&r.Login, &name, &email, &r.MatchType, &matchSource); err != nil {
-			continue
-		}
-		if name.Valid {
-			r.Name = name.String
-		}
-		if email.Valid {
-			r.Email = email.String
-		}
-		if matchSource.Valid {
-			r.MatchSource = matchSource.String
-		}
+		var name, email, matchSource sql.NullString
+		var rank float64
+		if err := rows.Scan(&r.Login, &name, &email, &r.MatchType, &matchSource, &rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		r.Name = name.String
+		r.Email = email.String
+		r.MatchSource = matchSource.String
 		results = append(results, r)
 	}
+	return results, rows.Err()
+}
 
-	// Search gists (description)
-	gistQuery := `
-		SELECT ug.github_login, up.name, up.email,
-			'gist' as match_type,
-			ug.description as match_source
-		FROM user_gists ug
-		LEFT JOIN user_profiles up ON ug.github_login = up.login
-		WHERE ug.description LIKE ?
-	`
-	rows3, err := db.conn.Query(gistQuery, pattern)
+// CommitMessageSearchLimit caps how many commits SearchCommitMessages returns
+const CommitMessageSearchLimit = 500
+
+// ErrCommitMessageSearchOff is returned by SearchCommitMessages when the project has not
+// turned on commit message indexing
+var ErrCommitMessageSearchOff = errors.New("commit message search is off for this project")
+
+// CommitMessageResult is a commit whose message matched SearchCommitMessages
+type CommitMessageResult struct {
+	SHA         string
+	AuthorName  string
+	AuthorEmail string
+	AuthorLogin string
+	AuthorDate  time.Time
+	HTMLURL     string
+	Snippet     string // Matching part of the message, on one line
+}
+
+// CommitMessageSearchEnabled reports whether commit messages are full-text indexed
+func (db *DB) CommitMessageSearchEnabled() (bool, error) {
+	value, err := db.GetSetting(SettingCommitMessageSearch)
+	return value == "true", err
+}
+
+// SetCommitMessageSearch turns commit message indexing on or off for the project. Turning it
+// on indexes the messages already stored; turning it off drops the index contents.
+func (db *DB) SetCommitMessageSearch(enabled bool) error {
+	tx, err := db.conn.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to search gists: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows3.Close()
+	defer tx.Rollback()
 
-	for rows3.Next() {
-		var r LocalSearchResult
-		var name, email sql.NullString
-		var matchSource sql.NullString
-		if err := rows3.Scan(&r.Login, &name, &email, &r.MatchType, &matchSource); err != nil {
-			continue
-		}
-		if name.Valid {
-			r.Name = name.String
-		}
-		if email.Valid {
-			r.Email = email.String
-		}
-		if matchSource.Valid {
-			r.MatchSource = matchSource.String
-		}
-		results = append(results, r)
+	if _, err := tx.Exec(upsertSetting, SettingCommitMessageSearch, strconv.FormatBool(enabled)); err != nil {
+		return fmt.Errorf("failed to save setting: %w", err)
 	}
+	if _, err := tx.Exec(rebuildCommitMessageIndex); err != nil {
+		return fmt.Errorf("failed to rebuild commit message index: %w", err)
+	}
+	return tx.Commit()
+}
 
-	// Search gist files (filename)
-	gistFileQuery := `
-		SELECT ug.github_login, up.name, up.email,
-			'gist_file' as match_type,
-			gf.name as match_source
-		FROM gist_files gf
-		JOIN user_gists ug ON gf.gist_id = ug.id
-		LEFT JOIN user_profiles up ON ug.github_login = up.login
-		WHERE gf.name LIKE ?
-	`
-	rows4, err := db.conn.Query(gistFileQuery, pattern)
+// SearchCommitMessages finds commits of a repository whose message contains every word of
+// keyword (as whole words or prefixes), best match first. Returns ErrCommitMessageSearchOff
+// unless indexing was turned on with SetCommitMessageSearch.
+func (db *DB) SearchCommitMessages(owner, repo, keyword string) ([]CommitMessageResult, error) {
+	enabled, err := db.CommitMessageSearchEnabled()
 	if err != nil {
-		return nil, fmt.Errorf("failed to search gist files: %w", err)
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrCommitMessageSearchOff
+	}
+	query := ftsQuery(keyword)
+	if query == "" {
+		return []CommitMessageResult{}, nil
 	}
-	defer rows4.Close()
 
-	for rows4.Next() {
-		var r LocalSearchResult
-		var name, email sql.NullString
-		var matchSource sql.NullString
-		if err := rows4.Scan(&r.Login, &name, &email, &r.MatchType, &matchSource); err != nil {
-			continue
-		}
-		if name.Valid {
-			r.Name = name.String
-		}
-		if email.Valid {
-			r.Email = email.String
-		}
-		if matchSource.Valid {
-			r.MatchSource = matchSource.String
+	rows, err := db.conn.Query(searchCommitMessages, query, owner, repo, CommitMessageSearchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search commit messages: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CommitMessageResult
+	for rows.Next() {
+		var r CommitMessageResult
+		var authorDate string
+		if err := rows.Scan(&r.SHA, &r.AuthorName, &r.AuthorEmail, &r.AuthorLogin, &authorDate, &r.HTMLURL, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan commit message result: %w", err)
 		}
+		r.AuthorDate, _ = parseTimestamp(authorDate)
+		r.Snippet = strings.Join(strings.Fields(r.Snippet), " ")
 		results = append(results, r)
 	}
+	return results, rows.Err()
+}
+
+// GlobalSearchLimit caps how many subdomains and Wayback URLs SearchEverything returns each
+const GlobalSearchLimit = 200
+
+// GlobalSearchResults holds the hits of SearchEverything, one slice per subsystem
+type GlobalSearchResults struct {
+	Committers  []models.ContributorStats // Committers across all repos by name, email or login
+	Profiles    []LocalSearchResult       // GitHub profiles, repos and gists
+	Subdomains  []models.Subdomain        // Subdomains of every target domain
+	BuildSteps  []BuildStepSearchResult   // Build steps of cached Docker image manifests
+	WaybackURLs []models.CDXRecord        // Cached Wayback CDX URLs
+}
+
+// Total returns the number of hits across all categories
+func (r *GlobalSearchResults) Total() int {
+	return len(r.Committers) + len(r.Profiles) + len(r.Subdomains) + len(r.BuildSteps) + len(r.WaybackURLs)
+}
+
+// SearchEverything runs keyword through the committer, profile, subdomain, build step and
+// Wayback URL searches. A failing search fails the whole call rather than hiding a category.
+func (db *DB) SearchEverything(keyword string) (*GlobalSearchResults, error) {
+	keyword = strings.TrimSpace(keyword)
+	results := &GlobalSearchResults{}
+	if keyword == "" {
+		return results, nil
+	}
 
+	var err error
+	if results.Committers, _, err = db.SearchCommittersGlobal(keyword); err != nil {
+		return nil, err
+	}
+	if results.Profiles, err = db.SearchLocalKeyword(keyword); err != nil {
+		return nil, err
+	}
+	if results.Subdomains, err = db.SearchSubdomains(keyword, GlobalSearchLimit); err != nil {
+		return nil, err
+	}
+	if results.BuildSteps, err = db.SearchBuildSteps(keyword); err != nil {
+		return nil, err
+	}
+	if results.WaybackURLs, err = db.SearchWaybackURLs(keyword, GlobalSearchLimit); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
+// ftsQuery turns free text into an FTS5 query matching every word as a prefix
+// Words are quoted so FTS5 operators and punctuation in the keyword are taken literally.
+func ftsQuery(keyword string) string {
+	var terms []string
+	for _, word := range strings.Fields(keyword) {
+		// Skip words with nothing the tokenizer would index (e.g. a lone "-")
+		if !strings.ContainsFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) {
+			continue
+		}
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// RebuildSearchIndex repopulates the local keyword search index from the profile, repo and
+// gist tables. The index is kept in sync automatically; this is for repairing it.
+func (db *DB) RebuildSearchIndex() error {
+	if _, err := db.conn.Exec(rebuildSearchIndex); err != nil {
+		return fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+	return nil
+}
+
 // ImageManifest represents a cached Docker image manifest with build steps
 type ImageManifest struct {
 	ID           int
@@ -1701,11 +2452,12 @@ type ImageManifest struct {
 	ConfigDigest string
 	LayerCount   int
 	TotalSize    int64
+	Config       *models.ImageConfig // Runtime settings from the config blob (nil if unknown)
 	FetchedAt    time.Time
 }
 
-// SaveImageManifest saves an image manifest with build steps to the database
-func (db *DB) SaveImageManifest(imageRef, platform string, buildSteps []string, configDigest string, layerCount int, totalSize int64) error {
+// SaveImageManifest saves an image manifest with build steps and image config to the database
+func (db *DB) SaveImageManifest(imageRef, platform string, buildSteps []string, configDigest string, layerCount int, totalSize int64, config *models.ImageConfig) error {
 	// Serialize build steps to JSON
 	stepsJSON := "[]"
 	if len(buildSteps) > 0 {
@@ -1716,7 +2468,17 @@ func (db *DB) SaveImageManifest(imageRef, platform string, buildSteps []string,
 		stepsJSON = string(bytes)
 	}
 
-	_, err := db.conn.Exec(insertImageManifest, imageRef, platform, stepsJSON, configDigest, layerCount, totalSize)
+	// Serialize image config to JSON (NULL when unknown)
+	var configJSON sql.NullString
+	if config != nil {
+		bytes, err := json.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal image config: %w", err)
+		}
+		configJSON = sql.NullString{String: string(bytes), Valid: true}
+	}
+
+	_, err := db.conn.Exec(insertImageManifest, imageRef, platform, stepsJSON, configDigest, layerCount, totalSize, configJSON)
 	if err != nil {
 		return fmt.Errorf("failed to save image manifest: %w", err)
 	}
@@ -1727,13 +2489,13 @@ func (db *DB) SaveImageManifest(imageRef, platform string, buildSteps []string,
 func (db *DB) GetImageManifest(imageRef string) (*ImageManifest, error) {
 	var im ImageManifest
 	var fetchedAt string
-	var platform, buildStepsJSON, configDigest sql.NullString
+	var platform, buildStepsJSON, configDigest, configJSON sql.NullString
 	var layerCount sql.NullInt64
 	var totalSize sql.NullInt64
 
 	err := db.conn.QueryRow(selectImageManifest, imageRef).Scan(
 		&im.ID, &im.ImageRef, &platform, &buildStepsJSON, &configDigest,
-		&layerCount, &totalSize, &fetchedAt,
+		&layerCount, &totalSize, &configJSON, &fetchedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil // Not found
@@ -1755,6 +2517,14 @@ func (db *DB) GetImageManifest(imageRef string) (*ImageManifest, error) {
 		}
 	}
 
+	// Parse image config from JSON
+	if configJSON.Valid && configJSON.String != "" {
+		var config models.ImageConfig
+		if err := json.Unmarshal([]byte(configJSON.String), &config); err == nil {
+			im.Config = &config
+		}
+	}
+
 	return &im, nil
 }
 
@@ -1781,3 +2551,65 @@ func (db *DB) GetImageBuildSteps(imageRef string) ([]string, error) {
 
 	return steps, nil
 }
+
+// GetImageConfig retrieves just the image config (env, entrypoint, ports, ...) for an image
+func (db *DB) GetImageConfig(imageRef string) (*models.ImageConfig, error) {
+	var configJSON sql.NullString
+
+	err := db.conn.QueryRow(selectImageManifestConfig, imageRef).Scan(&configJSON)
+	if err == sql.ErrNoRows {
+		return nil, nil // Not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image config: %w", err)
+	}
+
+	if !configJSON.Valid || configJSON.String == "" {
+		return nil, nil
+	}
+
+	var config models.ImageConfig
+	if err := json.Unmarshal([]byte(configJSON.String), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse image config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// SaveImageTags caches the tag listing for an image, replacing any earlier listing
+func (db *DB) SaveImageTags(imageRef string, tags []string) error {
+	tagsJSON, err := json.Marshal(tags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal image tags: %w", err)
+	}
+
+	if _, err := db.conn.Exec(insertImageTags, imageRef, string(tagsJSON)); err != nil {
+		return fmt.Errorf("failed to save image tags: %w", err)
+	}
+	return nil
+}
+
+// GetImageTags retrieves the cached tag listing for an image and when it was fetched
+// Returns nil tags if the image has not been cached.
+func (db *DB) GetImageTags(imageRef string) ([]string, time.Time, error) {
+	var tagsJSON sql.NullString
+	var fetchedAt string
+
+	err := db.conn.QueryRow(selectImageTags, imageRef).Scan(&tagsJSON, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil // Not found
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to get image tags: %w", err)
+	}
+
+	var tags []string
+	if tagsJSON.Valid && tagsJSON.String != "" {
+		if err := json.Unmarshal([]byte(tagsJSON.String), &tags); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to parse image tags: %w", err)
+		}
+	}
+
+	fetched, _ := parseTimestamp(fetchedAt)
+	return tags, fetched, nil
+}
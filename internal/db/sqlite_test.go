@@ -0,0 +1,1193 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// seedCommits inserts n synthetic commits spread over a few repos and committers
+func seedCommits(tb testing.TB, database *DB, n int) {
+	tb.Helper()
+	tx, err := database.conn.Begin()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	stmt, err := tx.Prepare(insertCommit)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer stmt.Close()
+	for i := 0; i < n; i++ {
+		committer := i % 500
+		login := ""
+		if committer%3 != 0 {
+			login = fmt.Sprintf("user%d", committer%200)
+		}
+		_, err := stmt.Exec(
+			fmt.Sprintf("%040x", i), "msg", "a", "a@example.com", "2024-01-01",
+			fmt.Sprintf("Committer %d", committer), fmt.Sprintf("c%d@example.com", committer), "2024-01-01",
+			"", login, "", "owner", fmt.Sprintf("repo%d", i%20), nil, nil, nil,
+		)
+		if err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		tb.Fatal(err)
+	}
+}
+
+// queryPlan returns the EXPLAIN QUERY PLAN details for query, one step per line
+func queryPlan(t *testing.T, database *DB, query string, args ...any) string {
+	t.Helper()
+	rows, err := database.conn.Query("EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	defer rows.Close()
+	var steps []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatalf("scan plan: %v", err)
+		}
+		steps = append(steps, detail)
+	}
+	return strings.Join(steps, "\n")
+}
+
+// TestCommitterStatsUseIndexes verifies both committer stats queries are answered from the
+// covering indexes instead of scanning the table or sorting into a temp b-tree for GROUP BY
+func TestCommitterStatsUseIndexes(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "plan.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	seedCommits(t, database, 2000)
+	if _, err := database.conn.Exec("ANALYZE"); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := queryPlan(t, database, selectCommitterStats, "owner", "repo1")
+	if !strings.Contains(plan, "USING COVERING INDEX idx_commits_repo_committer") || strings.Contains(plan, "GROUP BY") {
+		t.Errorf("committer stats plan:\n%s", plan)
+	}
+
+	plan = queryPlan(t, database, selectCombinedCommitterStats)
+	if !strings.Contains(plan, "COVERING INDEX idx_commits_committer_login") {
+		t.Errorf("combined committer stats plan:\n%s", plan)
+	}
+}
+
+// TestCommitterStatsInRange verifies the range filters on author date with inclusive bounds,
+// recomputes percentages over the commits in range, and returns nothing for an empty window
+func TestCommitterStatsInRange(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "range.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	day := func(d int) time.Time { return time.Date(2024, time.January, d, 12, 0, 0, 0, time.UTC) }
+	var commits []models.CommitRecord
+	for i, c := range []struct {
+		committer string
+		day       int
+	}{{"alice", 1}, {"alice", 2}, {"bob", 2}, {"bob", 10}, {"bob", 11}, {"carol", 20}} {
+		commits = append(commits, models.CommitRecord{
+			SHA: fmt.Sprint(i), CommitterName: c.committer, CommitterEmail: c.committer + "@example.com",
+			AuthorDate: day(c.day), RepoOwner: "owner", RepoName: "repo",
+		})
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(stats []models.ContributorStats) string {
+		var parts []string
+		for _, s := range stats {
+			parts = append(parts, fmt.Sprintf("%s=%d/%.0f%%", s.Name, s.CommitCount, s.Percentage))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		wantTotal int
+		want      string
+	}{
+		{"bounded", day(2), day(10), 3, "bob=2/67% alice=1/33%"},
+		{"open start", time.Time{}, day(1), 1, "alice=1/100%"},
+		{"open end", day(11), time.Time{}, 2, "bob=1/50% carol=1/50%"},
+		{"unbounded", time.Time{}, time.Time{}, 6, "bob=3/50% alice=2/33% carol=1/17%"},
+		{"empty", day(12), day(19), 0, ""},
+	}
+	for _, tt := range tests {
+		stats, total, err := database.GetCommitterStatsInRange("owner", "repo", tt.from, tt.to)
+		if err != nil {
+			t.Fatalf("%s: GetCommitterStatsInRange() error = %v", tt.name, err)
+		}
+		if total != tt.wantTotal || describe(stats) != tt.want {
+			t.Errorf("%s: got %d commits [%s], want %d [%s]", tt.name, total, describe(stats), tt.wantTotal, tt.want)
+		}
+	}
+
+	// The unbounded stats are untouched by the range queries
+	if _, total, err := database.GetCommitterStats("owner", "repo"); err != nil || total != 6 {
+		t.Errorf("GetCommitterStats() = %d commits, %v; want 6", total, err)
+	}
+}
+
+// TestCoAuthorStats verifies co-authors are stored with their commit, show up in committer
+// stats as marked rows, and follow the commit when it is re-fetched or deleted
+func TestCoAuthorStats(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "coauthors.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	bob := models.CoAuthor{Name: "Bob", Email: "bob@example.com"}
+	carol := models.CoAuthor{Name: "Carol", Email: "carol@example.com"}
+	commit := func(sha string, day int, coAuthors ...models.CoAuthor) models.CommitRecord {
+		return models.CommitRecord{
+			SHA: sha, CommitterName: "Alice", CommitterEmail: "alice@example.com",
+			AuthorDate: time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC),
+			RepoOwner:  "owner", RepoName: "repo", CoAuthors: coAuthors,
+		}
+	}
+	if err := database.InsertCommits([]models.CommitRecord{
+		commit("a", 1, bob, carol),
+		commit("b", 2, bob),
+		commit("c", 3),
+		commit("d", 4),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(stats []models.ContributorStats) string {
+		var parts []string
+		for _, s := range stats {
+			parts = append(parts, fmt.Sprintf("%s=%d/%.0f%%", s.DisplayName(), s.CommitCount, s.Percentage))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	stats, total, err := database.GetCommitterStats("owner", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Alice=4/100% Bob (co-author)=2/50% Carol (co-author)=1/25%"; total != 4 || describe(stats) != want {
+		t.Errorf("GetCommitterStats() = %d [%s], want 4 [%s]", total, describe(stats), want)
+	}
+
+	// The date range bounds co-authored commits too
+	stats, _, err = database.GetCommitterStatsInRange("owner", "repo", time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC), time.Time{})
+	if want := "Alice=3/100% Bob (co-author)=1/33%"; err != nil || describe(stats) != want {
+		t.Errorf("GetCommitterStatsInRange() = [%s], %v; want [%s]", describe(stats), err, want)
+	}
+
+	// A re-fetched commit's co-authors replace the old ones; a deleted commit takes its own along
+	if err := database.InsertCommits([]models.CommitRecord{commit("a", 1, carol)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec("DELETE FROM commits WHERE sha = 'b'"); err != nil {
+		t.Fatal(err)
+	}
+	stats, _, err = database.GetCommitterStats("owner", "repo")
+	if want := "Alice=3/100% Carol (co-author)=1/33%"; err != nil || describe(stats) != want {
+		t.Errorf("after re-fetch and delete = [%s], %v; want [%s]", describe(stats), err, want)
+	}
+	var rows int
+	if err := database.conn.QueryRow("SELECT COUNT(*) FROM co_authors").Scan(&rows); err != nil || rows != 1 {
+		t.Errorf("co_authors rows = %d, %v; want 1", rows, err)
+	}
+}
+
+// TestBackupDuringPendingWrite verifies a backup taken while another connection has an
+// uncommitted write holds exactly the committed data and passes an integrity check
+func TestBackupDuringPendingWrite(t *testing.T) {
+	dir := t.TempDir()
+	database, err := New(filepath.Join(dir, "live.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	seedCommits(t, database, 100)
+
+	tx, err := database.conn.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM commits WHERE repo_name = 'repo1'"); err != nil {
+		t.Fatal(err)
+	}
+
+	backupPath := filepath.Join(dir, "backup.db")
+	if err := database.Backup(backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := database.Backup(backupPath); err == nil {
+		t.Error("Backup() over an existing file succeeded")
+	}
+
+	backup, err := New(backupPath)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer backup.Close()
+	var integrity string
+	if err := backup.conn.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Errorf("integrity_check = %q, %v", integrity, err)
+	}
+	if _, total, err := backup.GetCommitterStats("owner", "repo1"); err != nil || total != 5 {
+		t.Errorf("backup repo1 commits = %d, %v; want the 5 committed before the pending delete", total, err)
+	}
+}
+
+// TestOptimizeReclaimsDeletedPages verifies Optimize shrinks the file after a bulk delete and
+// leaves the remaining data intact
+func TestOptimizeReclaimsDeletedPages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacuum.db")
+	database, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	seedCommits(t, database, 5000)
+	if _, err := database.conn.Exec("DELETE FROM commits WHERE repo_name != 'repo1'"); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Optimize(); err != nil {
+		t.Fatalf("Optimize() error = %v", err)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf("file size %d -> %d, want it to shrink", before.Size(), after.Size())
+	}
+
+	if _, total, err := database.GetCommitterStats("owner", "repo1"); err != nil || total != 250 {
+		t.Errorf("GetCommitterStats() after Optimize = %d commits, %v; want 250", total, err)
+	}
+}
+
+// TestSearchLocalKeyword verifies the full-text index follows inserts, replacements and deletes,
+// reports each source row once, and ranks closer matches first
+func TestSearchLocalKeyword(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "search.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	search := func(keyword string) []string {
+		t.Helper()
+		results, err := database.SearchLocalKeyword(keyword)
+		if err != nil {
+			t.Fatalf("SearchLocalKeyword(%q) error = %v", keyword, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Login+" "+r.MatchType+": "+r.MatchSource)
+		}
+		return got
+	}
+
+	if err := database.SaveUserProfile(models.UserProfile{Login: "alice", Name: "Alice", Bio: "Platform engineer, Kubernetes operator wrangler and occasional baker", Location: "Berlin"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SaveUserRepositories([]models.UserRepository{
+		{GitHubLogin: "alice", OwnerLogin: "alice", Name: "kube-tools", Description: "Helpers for Kubernetes"},
+		{GitHubLogin: "alice", OwnerLogin: "alice", Name: "dotfiles", Description: "My shell setup"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SaveUserGists([]models.UserGist{
+		{ID: "g1", GitHubLogin: "bob", Description: "cluster notes", Files: []models.GistFile{{Name: "kubeconfig.yaml"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(search("kube"), "\n")
+	want := "alice repo: kube-tools\nbob gist_file: kubeconfig.yaml\nalice bio: Platform engineer, Kubernetes operator wrangler and occasional baker"
+	if got != want {
+		t.Errorf("search kube:\n%s\nwant:\n%s", got, want)
+	}
+	if got := search(`berlin "`); len(got) != 1 || got[0] != "alice location: Berlin" {
+		t.Errorf("search berlin = %v", got)
+	}
+	if got := search("-"); len(got) != 0 {
+		t.Errorf("search with no words = %v", got)
+	}
+
+	// Re-saving replaces the old entries instead of leaving stale ones behind
+	if err := database.SaveUserProfile(models.UserProfile{Login: "alice", Name: "Alice", Bio: "Gardener"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("operator"); len(got) != 0 {
+		t.Errorf("stale bio still matches: %v", got)
+	}
+	if err := database.SaveUserGists([]models.UserGist{{ID: "g1", GitHubLogin: "bob", Description: "cluster notes"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.DeleteUserRepository("alice", "kube-tools"); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("kube"); len(got) != 0 {
+		t.Errorf("deleted rows still match: %v", got)
+	}
+
+	// Rebuilding yields the same index
+	if err := database.RebuildSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if got := search("gardener"); len(got) != 1 {
+		t.Errorf("search after rebuild = %v", got)
+	}
+}
+
+func TestDockerSearchCache(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "docker.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if p, err := database.GetDockerSearch("nginx", 1); err != nil || p != nil {
+		t.Fatalf("uncached page = %v, %v", p, err)
+	}
+	for page, body := range []string{`{"page":1}`, `{"page":2}`} {
+		if err := database.SaveDockerSearch("Nginx ", page+1, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := database.SaveDockerSearch("redis", 1, `{"old":true}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec("UPDATE docker_search_cache SET fetched_at = '2000-01-01 00:00:00' WHERE query = 'redis'"); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := database.GetDockerSearch("nginx", 2)
+	if err != nil || p == nil || p.Response != `{"page":2}` || time.Since(p.FetchedAt) > time.Minute {
+		t.Fatalf("cached page = %+v, %v", p, err)
+	}
+	searches, err := database.GetCachedDockerSearches()
+	if err != nil || len(searches) != 2 || searches[0].Query != "nginx" || searches[0].Pages != 2 {
+		t.Fatalf("cached searches = %+v, %v", searches, err)
+	}
+
+	if ttl := database.GetDockerSearchCacheTTL(); ttl != DefaultDockerSearchCacheTTL {
+		t.Errorf("default TTL = %v", ttl)
+	}
+	if err := database.SetDockerSearchCacheTTL(30 * 24 * time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := database.GetDockerSearchCacheTTL(); ttl != 30*24*time.Hour {
+		t.Errorf("saved TTL = %v", ttl)
+	}
+
+	if n, err := database.EvictDockerSearches(database.GetDockerSearchCacheTTL()); err != nil || n != 1 {
+		t.Errorf("evicted %d pages, %v", n, err)
+	}
+	if p, _ := database.GetDockerSearch("redis", 1); p != nil {
+		t.Error("stale search survived eviction")
+	}
+	if p, _ := database.GetDockerSearch("nginx", 1); p == nil {
+		t.Error("fresh search was evicted")
+	}
+}
+
+func TestDeletePreviews(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "preview.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	var commits []models.CommitRecord
+	for i, email := range []string{"a@x.io", "a@x.io", "b@x.io"} {
+		commits = append(commits, models.CommitRecord{SHA: fmt.Sprint(i), CommitterName: "n", CommitterEmail: email, RepoOwner: "o", RepoName: "r"})
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	if n, c, err := database.DeleteRepositoryDataPreview("o", "r"); err != nil || n != 3 || c != 2 {
+		t.Errorf("repo preview = %d commits, %d committers, %v", n, c, err)
+	}
+
+	if err := database.InsertTargetDomain("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "a.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "b.example.com", Source: "crtsh"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := database.DeleteTargetDomainPreview("example.com"); err != nil || n != 2 {
+		t.Errorf("domain preview = %d, %v", n, err)
+	}
+
+	// Previews change nothing
+	if n, _ := database.GetSubdomainCount("example.com"); n != 2 {
+		t.Errorf("subdomains after preview = %d", n)
+	}
+	if _, total, _ := database.GetCommitterStats("o", "r"); total != 3 {
+		t.Errorf("commits after preview = %d", total)
+	}
+}
+
+func TestSearchCommittersGlobal(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "global.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	var commits []models.CommitRecord
+	add := func(repo, name, email, login string, n int) {
+		for i := 0; i < n; i++ {
+			commits = append(commits, models.CommitRecord{
+				SHA: fmt.Sprintf("%s-%s-%d", repo, email, i), CommitterName: name, CommitterEmail: email,
+				GitHubCommitterLogin: login, RepoOwner: "acme", RepoName: repo,
+			})
+		}
+	}
+	add("api", "Alice", "alice@ACME.com", "alice", 3)
+	add("web", "Alice", "alice@ACME.com", "alice", 1)
+	add("cli", "Alice", "alice@ACME.com", "alice", 1)
+	add("api", "Bob", "bob@other.org", "acme-bot", 2)
+	add("api", "Carol", "carol@other.org", "", 1)
+	add("api", "Percy", "100%@other.org", "", 1)
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, total, err := database.SearchCommittersGlobal("Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 2 || total != 7 {
+		t.Fatalf("search acme = %d committers, %d commits: %+v", len(stats), total, stats)
+	}
+	if stats[0].Name != "Alice [acme/api +2]" || stats[0].CommitCount != 5 || stats[1].Name != "Bob [acme/api]" {
+		t.Errorf("search acme = %+v", stats)
+	}
+
+	// LIKE wildcards in the keyword match literally
+	if stats, _, _ := database.SearchCommittersGlobal("%"); len(stats) != 1 || stats[0].Email != "100%@other.org" {
+		t.Errorf("search %% = %+v", stats)
+	}
+	if stats, _, _ := database.SearchCommittersGlobal("  "); len(stats) != 0 {
+		t.Errorf("blank search = %+v", stats)
+	}
+}
+
+// TestSearchEverything verifies one keyword reaches every subsystem's search, with subdomain
+// and Wayback URL matches spanning all domains
+func TestSearchEverything(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "everything.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := database.InsertCommits([]models.CommitRecord{
+		{SHA: "a1", CommitterName: "Staging Bot", CommitterEmail: "bot@acme.com", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "a2", CommitterName: "Alice", CommitterEmail: "alice@acme.com", RepoOwner: "acme", RepoName: "api"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SaveUserProfile(models.UserProfile{Login: "alice", Bio: "Runs the staging cluster"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.InsertSubdomains([]models.Subdomain{
+		{Domain: "acme.com", Subdomain: "staging.acme.com", Source: "crtsh"},
+		{Domain: "acme.com", Subdomain: "www.acme.com", Source: "crtsh"},
+		{Domain: "other.org", Subdomain: "STAGING-db.other.org", Source: "virustotal"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SaveImageManifest("acme/api:latest", "linux/amd64", []string{"RUN make", "ENV STAGE=staging"}, "sha256:c", 1, 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.InsertWaybackRecords([]models.CDXRecord{
+		{URL: "https://acme.com/staging/login", Domain: "acme.com", Timestamp: "20240101000000"},
+		{URL: "https://acme.com/about", Domain: "acme.com", Timestamp: "20240101000000"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := database.SearchEverything("Staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.Committers) != 1 || results.Committers[0].Email != "bot@acme.com" {
+		t.Errorf("Committers = %+v", results.Committers)
+	}
+	if len(results.Profiles) != 1 || results.Profiles[0].Login != "alice" {
+		t.Errorf("Profiles = %+v", results.Profiles)
+	}
+	if len(results.Subdomains) != 2 || results.Subdomains[0].Subdomain != "staging.acme.com" || results.Subdomains[1].Domain != "other.org" {
+		t.Errorf("Subdomains = %+v", results.Subdomains)
+	}
+	if len(results.BuildSteps) != 1 || results.BuildSteps[0].StepIndex != 1 {
+		t.Errorf("BuildSteps = %+v", results.BuildSteps)
+	}
+	if len(results.WaybackURLs) != 1 || results.WaybackURLs[0].URL != "https://acme.com/staging/login" {
+		t.Errorf("WaybackURLs = %+v", results.WaybackURLs)
+	}
+	if results.Total() != 6 {
+		t.Errorf("Total() = %d, want 6", results.Total())
+	}
+
+	if results, err := database.SearchEverything("  "); err != nil || results.Total() != 0 {
+		t.Errorf("blank search = %+v, %v", results, err)
+	}
+	if subdomains, _ := database.SearchSubdomains("_", GlobalSearchLimit); len(subdomains) != 0 {
+		t.Errorf("search _ matched %+v, want the wildcard taken literally", subdomains)
+	}
+}
+
+// TestWaybackCapturesAcrossPages verifies captures of one normalized URL add up in a single row
+// when its variants arrive on different CDX pages
+func TestWaybackCapturesAcrossPages(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "wayback.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	pages := [][]models.CDXRecord{
+		{{URL: "https://acme.com/login/", Domain: "acme.com", Timestamp: "20240101000000", Captures: 2}},
+		{{URL: "HTTPS://acme.com:443/login", Domain: "acme.com", Timestamp: "20240301000000", Captures: 3}},
+	}
+	for _, page := range pages {
+		if _, err := database.InsertWaybackRecords(page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := database.GetWaybackRecords("acme.com")
+	if err != nil || len(records) != 1 {
+		t.Fatalf("GetWaybackRecords() = %+v, %v; want one row", records, err)
+	}
+	if records[0].Captures != 5 || records[0].Timestamp != "20240301000000" {
+		t.Errorf("merged row = %d captures at %s, want 5 at the newest capture", records[0].Captures, records[0].Timestamp)
+	}
+
+	// Without capture counts a variant of a stored URL is a duplicate
+	if n, err := database.InsertWaybackRecords([]models.CDXRecord{{URL: "https://ACME.com/login", Domain: "acme.com", Timestamp: "20240401000000"}}); err != nil || n != 0 {
+		t.Errorf("uncounted duplicate inserted %d rows, %v", n, err)
+	}
+}
+
+// TestSearchCommitMessages verifies message search is off until turned on, then finds the
+// stored and newly fetched messages of one repo and follows replaced and deleted commits
+func TestSearchCommitMessages(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "messages.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := database.InsertCommits([]models.CommitRecord{
+		{SHA: "a1", Message: "Fix login for JIRA-1234\n\nRemove hardcoded password", AuthorName: "Alice", GitHubAuthorLogin: "alice", CommitterEmail: "alice@acme.com", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "a2", Message: "Bump deps", CommitterEmail: "bot@acme.com", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "b1", Message: "Rotate password", CommitterEmail: "bob@acme.com", RepoOwner: "acme", RepoName: "web"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.SearchCommitMessages("acme", "api", "password"); !errors.Is(err, ErrCommitMessageSearchOff) {
+		t.Fatalf("search before opting in: err = %v", err)
+	}
+
+	// Turning it on indexes the messages already stored
+	if err := database.SetCommitMessageSearch(true); err != nil {
+		t.Fatal(err)
+	}
+	results, err := database.SearchCommitMessages("acme", "api", "passw")
+	if err != nil || len(results) != 1 || results[0].SHA != "a1" || results[0].AuthorLogin != "alice" {
+		t.Fatalf("SearchCommitMessages = %+v, %v", results, err)
+	}
+	if !strings.Contains(results[0].Snippet, "hardcoded password") || strings.Contains(results[0].Snippet, "\n") {
+		t.Errorf("Snippet = %q", results[0].Snippet)
+	}
+
+	// Commits fetched later are indexed, and a re-fetched commit is indexed by its new message
+	if err := database.InsertCommits([]models.CommitRecord{
+		{SHA: "a3", Message: "Add staging password to config", CommitterEmail: "bot@acme.com", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "a1", Message: "Fix login for JIRA-1234", CommitterEmail: "alice@acme.com", RepoOwner: "acme", RepoName: "api"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := database.SearchCommitMessages("acme", "api", "password"); len(results) != 1 || results[0].SHA != "a3" {
+		t.Errorf("after refetch = %+v", results)
+	}
+	if results, _ := database.SearchCommitMessages("acme", "api", "jira-1234"); len(results) != 1 {
+		t.Errorf("search jira-1234 = %+v", results)
+	}
+
+	if err := database.DeleteCommitterByEmail("acme", "api", "bot@acme.com"); err != nil {
+		t.Fatal(err)
+	}
+	if results, _ := database.SearchCommitMessages("acme", "api", "password"); len(results) != 0 {
+		t.Errorf("deleted commit still found: %+v", results)
+	}
+
+	// Turning it off empties the index
+	if err := database.SetCommitMessageSearch(false); err != nil {
+		t.Fatal(err)
+	}
+	var indexed int
+	if err := database.conn.QueryRow("SELECT COUNT(*) FROM commit_message_index").Scan(&indexed); err != nil || indexed != 0 {
+		t.Errorf("index rows after turning off = %d, %v", indexed, err)
+	}
+}
+
+// BenchmarkCommitterStats compares the stats queries with and without the covering indexes
+// over 200k commits: go test -run '^$' -bench CommitterStats ./internal/db
+func BenchmarkCommitterStats(b *testing.B) {
+	database, err := New(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer database.Close()
+	seedCommits(b, database, 200000)
+
+	run := func(b *testing.B) {
+		b.Run("repo", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := database.GetCommitterStats("owner", "repo7"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run("combined", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := database.GetCombinedCommitterStats(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	b.Run("indexed", run)
+
+	if _, err := database.conn.Exec("DROP INDEX idx_commits_repo_committer; DROP INDEX idx_commits_committer_login"); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("unindexed", run)
+}
+
+// TestSubdomainSourcesMerge verifies a subdomain reported by several sources keeps every
+// source, counts under each in the stats and matches each source filter
+func TestSubdomainSourcesMerge(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "sources.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := database.InsertTargetDomain("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	batches := [][]models.Subdomain{
+		{{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh", AltNames: "www.example.com"}},
+		{
+			{Domain: "example.com", Subdomain: "api.example.com", Source: "virustotal", CNAMEs: "edge.cdn.net"},
+			{Domain: "example.com", Subdomain: "dev.example.com", Source: "virustotal"},
+		},
+		// Re-running a source adds nothing new
+		{{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh", AltNames: "www.example.com"}},
+	}
+	for i, batch := range batches {
+		if _, err := database.InsertSubdomains(batch); err != nil {
+			t.Fatalf("batch %d: %v", i, err)
+		}
+	}
+
+	subs, err := database.GetSubdomains("example.com")
+	if err != nil || len(subs) != 2 {
+		t.Fatalf("GetSubdomains = %+v, %v", subs, err)
+	}
+	api := subs[0]
+	if api.Source != "crtsh" || api.Sources != "crtsh,virustotal" || !api.HasSource("virustotal") {
+		t.Errorf("api sources = %q (first %q)", api.Sources, api.Source)
+	}
+	if api.CNAMEs != "edge.cdn.net" || api.AltNames != "www.example.com" {
+		t.Errorf("api names = cnames %q, alt %q", api.CNAMEs, api.AltNames)
+	}
+
+	stats, err := database.GetSubdomainStats("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Total != 2 || stats.CrtshCount != 1 || stats.VTCount != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	_, total, err := database.GetSubdomainsFiltered(models.SubdomainFilter{Domain: "example.com", Source: "virustotal", CDXIndexed: -1, Limit: 10})
+	if err != nil || total != 2 {
+		t.Errorf("virustotal filter = %d, %v", total, err)
+	}
+
+	// Reverse DNS names are stored alongside the resolution
+	if err := database.UpdateSubdomainPTRNames("api.example.com", []string{"a.host.net", "b.host.net"}); err != nil {
+		t.Fatal(err)
+	}
+	if subs, _ := database.GetSubdomains("example.com"); subs[0].PTRNames != "a.host.net,b.host.net" {
+		t.Errorf("PTRNames = %q", subs[0].PTRNames)
+	}
+
+	// Probing stores the live certificate, and a later probe without one clears it
+	probed := subs[0]
+	probed.HTTPSStatus = 200
+	probed.TLSSubject = "www.example.com"
+	probed.TLSSANs = "www.example.com,example.com"
+	probed.TLSIssuer = "R3"
+	probed.TLSNotBefore = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	probed.TLSNotAfter = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	probed.TLSHostMismatch = true
+	if err := database.UpdateSubdomainProbe(probed); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := database.GetSubdomains("example.com")
+	if c := got[0]; c.TLSSubject != "www.example.com" || c.TLSSANs != probed.TLSSANs || c.TLSIssuer != "R3" ||
+		!c.TLSNotBefore.Equal(probed.TLSNotBefore) || !c.TLSNotAfter.Equal(probed.TLSNotAfter) || !c.TLSHostMismatch {
+		t.Errorf("probed cert = %+v", c)
+	}
+	if err := database.UpdateSubdomainProbe(models.Subdomain{Subdomain: "api.example.com", HTTPStatus: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := database.GetSubdomains("example.com"); got[0].HasTLSCert() || got[0].TLSHostMismatch {
+		t.Errorf("cert not cleared: %+v", got[0])
+	}
+}
+
+// TestRecentSubdomains verifies the latest run's finds are listed newest first, the discovery
+// filter pages them, and the per-domain run and export times are forgotten with the domain
+func TestRecentSubdomains(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "recent.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	if err := database.InsertTargetDomain("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "old.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Age the first run so the next one is distinguishable
+	if _, err := database.conn.Exec("UPDATE subdomains SET discovered_at = '2025-01-01 00:00:00'"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.conn.Exec("UPDATE subdomains SET discovered_at = '2025-01-02 00:00:00' WHERE subdomain = 'api.example.com'"); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.SetLastSubdomainExport("example.com", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A run that only re-finds known subdomains is not recorded as a run
+	if err := database.SetSetting(SettingSubdomainLastRunPrefix+"example.com", "2025-01-01T00:00:00Z"); err != nil {
+		t.Fatal(err)
+	}
+	database.InsertSubdomains([]models.Subdomain{{Domain: "example.com", Subdomain: "old.example.com", Source: "virustotal"}})
+	if run, _ := database.GetLastSubdomainRun("example.com"); !run.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("merge-only run recorded: %v", run)
+	}
+
+	if _, err := database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "new.example.com", Source: "virustotal"},
+		{Domain: "example.com", Subdomain: "old.example.com", Source: "virustotal"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	lastRun, err := database.GetLastSubdomainRun("example.com")
+	if err != nil || lastRun.IsZero() || time.Since(lastRun) > time.Minute {
+		t.Fatalf("GetLastSubdomainRun = %v, %v", lastRun, err)
+	}
+
+	recent, err := database.GetRecentSubdomains("example.com", lastRun)
+	if err != nil || len(recent) != 1 || recent[0].Subdomain != "new.example.com" {
+		t.Fatalf("GetRecentSubdomains(last run) = %+v, %v", recent, err)
+	}
+	recent, _ = database.GetRecentSubdomains("example.com", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	if len(recent) != 2 || recent[0].Subdomain != "new.example.com" || recent[1].Subdomain != "api.example.com" {
+		t.Errorf("GetRecentSubdomains not newest first: %+v", recent)
+	}
+
+	page, total, err := database.GetSubdomainsFiltered(models.SubdomainFilter{
+		Domain: "example.com", CDXIndexed: -1, Limit: 1,
+		DiscoveredSince: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	})
+	if err != nil || total != 2 || len(page) != 1 || page[0].Subdomain != "new.example.com" {
+		t.Errorf("DiscoveredSince filter = %+v (total %d), %v", page, total, err)
+	}
+
+	lastExport, err := database.GetLastSubdomainExport("example.com")
+	if err != nil || !lastExport.Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetLastSubdomainExport = %v, %v", lastExport, err)
+	}
+
+	if err := database.DeleteTargetDomain("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	run, _ := database.GetLastSubdomainRun("example.com")
+	export, _ := database.GetLastSubdomainExport("example.com")
+	if !run.IsZero() || !export.IsZero() {
+		t.Errorf("times kept after delete: run %v, export %v", run, export)
+	}
+}
+
+func TestSubdomainSnapshots(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "snapshots.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	database.InsertTargetDomain("example.com")
+	database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "old.example.com", Source: "crtsh"},
+	})
+
+	snap, err := database.SaveSubdomainSnapshot("example.com", "before")
+	if err != nil || snap.Label != "before" || snap.Count != 2 {
+		t.Fatalf("SaveSubdomainSnapshot = %+v, %v", snap, err)
+	}
+	if diff, err := database.DiffSubdomainSnapshot("example.com", "before"); err != nil || diff == nil || !diff.Unchanged() {
+		t.Errorf("diff against an identical snapshot = %+v, %v", diff, err)
+	}
+	if diff, err := database.DiffSubdomainSnapshot("example.com", "missing"); err != nil || diff != nil {
+		t.Errorf("diff against a missing snapshot = %+v, %v", diff, err)
+	}
+
+	database.InsertSubdomains([]models.Subdomain{{Domain: "example.com", Subdomain: "new.example.com", Source: "virustotal"}})
+	database.conn.Exec("DELETE FROM subdomains WHERE subdomain = 'old.example.com'")
+	diff, err := database.DiffSubdomainSnapshot("example.com", "before")
+	if err != nil || diff == nil {
+		t.Fatalf("DiffSubdomainSnapshot = %+v, %v", diff, err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0] != "new.example.com" || len(diff.Removed) != 1 || diff.Removed[0] != "old.example.com" {
+		t.Errorf("diff = added %v, removed %v", diff.Added, diff.Removed)
+	}
+
+	// Re-saving a label replaces it; an empty label is timestamped
+	if snap, _ = database.SaveSubdomainSnapshot("example.com", "before"); snap.Count != 2 {
+		t.Errorf("re-saved snapshot count = %d", snap.Count)
+	}
+	auto, err := database.SaveSubdomainSnapshot("example.com", "")
+	if err != nil || auto.Label == "" {
+		t.Fatalf("auto-labelled snapshot = %+v, %v", auto, err)
+	}
+	snapshots, err := database.GetSubdomainSnapshots("example.com")
+	if err != nil || len(snapshots) != 2 {
+		t.Fatalf("GetSubdomainSnapshots = %+v, %v", snapshots, err)
+	}
+
+	if err := database.DeleteSubdomainSnapshot("example.com", "before"); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots, _ = database.GetSubdomainSnapshots("example.com"); len(snapshots) != 1 || snapshots[0].Label != auto.Label {
+		t.Errorf("snapshots after delete = %+v", snapshots)
+	}
+	if err := database.DeleteTargetDomain("example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots, _ = database.GetSubdomainSnapshots("example.com"); len(snapshots) != 0 {
+		t.Errorf("snapshots survived DeleteTargetDomain: %+v", snapshots)
+	}
+}
+
+// TestGroupLabels verifies labels are saved, cleared, and dropped with the group's last link
+func TestGroupLabels(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "labels.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer database.Close()
+
+	for _, email := range []string{"a@x.io", "b@x.io"} {
+		if err := database.SaveLink("o", "r", 1, email); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if label, err := database.GetGroupLabel("o", "r", 1); err != nil || label != "" {
+		t.Errorf("unlabeled group = %q, %v", label, err)
+	}
+	if err := database.SaveGroupLabel("o", "r", 1, "Alice / @alice"); err != nil {
+		t.Fatal(err)
+	}
+	if labels, err := database.GetGroupLabels("o", "r"); err != nil || labels[1] != "Alice / @alice" {
+		t.Errorf("GetGroupLabels = %v, %v", labels, err)
+	}
+	if groups, err := database.GetLinkGroups("o", "r"); err != nil || len(groups) != 1 || groups[0].Label != "Alice / @alice" {
+		t.Errorf("GetLinkGroups = %+v, %v", groups, err)
+	}
+
+	if err := database.SaveGroupLabel("o", "r", 1, "  "); err != nil {
+		t.Fatal(err)
+	}
+	if label, _ := database.GetGroupLabel("o", "r", 1); label != "" {
+		t.Errorf("cleared label = %q", label)
+	}
+
+	database.SaveGroupLabel("o", "r", 1, "Alice")
+	database.RemoveLink("o", "r", "a@x.io")
+	if label, _ := database.GetGroupLabel("o", "r", 1); label != "Alice" {
+		t.Errorf("label dropped while the group has members: %q", label)
+	}
+	database.RemoveLink("o", "r", "b@x.io")
+	if label, _ := database.GetGroupLabel("o", "r", 1); label != "" {
+		t.Errorf("label kept for an empty group: %q", label)
+	}
+}
+
+// TestDeleteUndo verifies undoable deletes restore every row they removed, including rows
+// cleaned up by triggers and rows in child tables
+func TestDeleteUndo(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "undo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	day := func(d int) time.Time { return time.Date(2024, time.January, d, 12, 0, 0, 0, time.UTC) }
+	if err := database.InsertCommits([]models.CommitRecord{
+		{SHA: "a", CommitterName: "Alice", CommitterEmail: "alice@x.io", AuthorDate: day(1), CommitterDate: day(1), RepoOwner: "o", RepoName: "r",
+			CoAuthors: []models.CoAuthor{{Name: "Bob", Email: "bob@x.io"}}},
+		{SHA: "b", CommitterName: "Alice", CommitterEmail: "alice@x.io", AuthorDate: day(5), CommitterDate: day(5), RepoOwner: "o", RepoName: "r"},
+		{SHA: "c", CommitterName: "Carol", CommitterEmail: "carol@x.io", AuthorDate: day(2), CommitterDate: day(2), RepoOwner: "o", RepoName: "r"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	database.SaveTag("o", "r", "alice@x.io")
+	database.SaveLink("o", "r", 1, "alice@x.io")
+	database.SaveLink("o", "r", 1, "carol@x.io")
+
+	describe := func() string {
+		stats, total, err := database.GetCommitterStatsInRange("o", "r", day(1), day(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parts []string
+		for _, s := range stats {
+			parts = append(parts, fmt.Sprintf("%s=%d", s.DisplayName(), s.CommitCount))
+		}
+		return fmt.Sprintf("%d %s", total, strings.Join(parts, " "))
+	}
+	before := describe()
+
+	deletion, err := database.DeleteCommitterUndoable("o", "r", "alice@x.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deletion.Rows() != 5 || deletion.Description != "committer alice@x.io" {
+		t.Errorf("deletion = %q, %d rows; want 5", deletion.Description, deletion.Rows())
+	}
+	if got := describe(); got != "1 Carol=1" {
+		t.Errorf("after delete = %q", got)
+	}
+
+	if err := database.RestoreDeletion(deletion); err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(); got != before {
+		t.Errorf("after restore = %q, want %q", got, before)
+	}
+	tags, _ := database.GetTags("o", "r")
+	links, _ := database.GetLinks("o", "r")
+	if !tags["alice@x.io"] || links["alice@x.io"] != 1 {
+		t.Errorf("tag/link not restored: %v %v", tags, links)
+	}
+
+	gist := models.UserGist{
+		ID: "g1", GitHubLogin: "alice", Name: "notes",
+		Files:    []models.GistFile{{GistID: "g1", Name: "a.txt", Text: "hi"}},
+		Comments: []models.GistComment{{ID: "c1", GistID: "g1", BodyText: "nice"}},
+	}
+	if err := database.SaveUserGists([]models.UserGist{gist}); err != nil {
+		t.Fatal(err)
+	}
+	deletion, err = database.DeleteUserGistUndoable("alice", "g1")
+	if err != nil || deletion.Rows() != 3 {
+		t.Fatalf("gist delete = %v rows, %v", deletion, err)
+	}
+	if gists, _ := database.GetUserGists("alice"); len(gists) != 0 {
+		t.Fatalf("gist still there after delete: %+v", gists)
+	}
+	if err := database.RestoreDeletion(deletion); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := database.GetGistFiles("g1")
+	if gists, _ := database.GetUserGists("alice"); len(gists) != 1 || len(files) != 1 || files[0].Text != "hi" {
+		t.Errorf("gist restore = %+v, files %+v", gists, files)
+	}
+
+	database.AddTrackedRepo("o", "r")
+	deletion, err = database.DeleteTrackedRepoUndoable("o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repos, _ := database.GetTrackedRepos(); len(repos) != 0 {
+		t.Fatalf("repo still tracked after delete")
+	}
+	if err := database.RestoreDeletion(deletion); err != nil {
+		t.Fatal(err)
+	}
+	if repos, _ := database.GetTrackedRepos(); len(repos) != 1 || describe() != before {
+		t.Errorf("repo restore = %v, stats %q", repos, describe())
+	}
+}
+
+func TestTopFiles(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "files.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	when := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(sha, name, email string, files ...string) models.CommitRecord {
+		return models.CommitRecord{SHA: sha, CommitterName: name, CommitterEmail: email, AuthorDate: when, CommitterDate: when,
+			RepoOwner: "o", RepoName: "r", Files: files}
+	}
+	if err := database.InsertCommits([]models.CommitRecord{
+		commit("a", "Alice", "alice@x.io", "main.go", "go.mod"),
+		commit("b", "Alice", "alice@x.io", "main.go"),
+		commit("c", "Bob", "bob@x.io", "main.go", "README.md"),
+		commit("d", "Bob", "bob@x.io"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func() string {
+		files, err := database.GetTopFiles("o", "r", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parts []string
+		for _, f := range files {
+			var editors []string
+			for _, e := range f.Editors {
+				editors = append(editors, fmt.Sprintf("%s:%d", e.Email, e.Commits))
+			}
+			parts = append(parts, fmt.Sprintf("%s=%d[%s]", f.Path, f.Commits, strings.Join(editors, ",")))
+		}
+		return strings.Join(parts, " ")
+	}
+	want := "main.go=3[alice@x.io:2,bob@x.io:1] README.md=1[bob@x.io:1]"
+	if got := describe(); got != want {
+		t.Errorf("GetTopFiles() = %q, want %q", got, want)
+	}
+
+	// Re-inserting without files (e.g. a fetch without -files) keeps the stored paths
+	if err := database.InsertCommits([]models.CommitRecord{commit("a", "Alice", "alice@x.io")}); err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(); got != want {
+		t.Errorf("after re-insert without files GetTopFiles() = %q, want %q", got, want)
+	}
+
+	// Deleting a committer drops the paths of their commits
+	if err := database.DeleteCommitterByEmail("o", "r", "alice@x.io"); err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(); got != "README.md=1[bob@x.io:1] main.go=1[bob@x.io:1]" {
+		t.Errorf("after delete GetTopFiles() = %q", got)
+	}
+}
+
+// TestCommitterSigningStats verifies the signed share only counts commits with a known status,
+// and that storing a commit again without one keeps the old status
+func TestCommitterSigningStats(t *testing.T) {
+	database, err := New(filepath.Join(t.TempDir(), "signed.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	when := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(sha, repo, email string, sig *models.CommitSignature) models.CommitRecord {
+		return models.CommitRecord{SHA: sha, CommitterName: email[:1], CommitterEmail: email, AuthorDate: when, CommitterDate: when,
+			RepoOwner: "o", RepoName: repo, GitHubCommitterLogin: email[:1], Signature: sig}
+	}
+	if err := database.InsertCommits([]models.CommitRecord{
+		commit("a1", "r", "a@x.io", &models.CommitSignature{Signed: true, Verified: true, SigningKey: "AAAA"}),
+		commit("a2", "r", "a@x.io", &models.CommitSignature{Signed: true, SigningKey: "BBBB"}),
+		commit("a3", "r", "a@x.io", &models.CommitSignature{}),
+		commit("a4", "r", "a@x.io", nil), // cached before signatures were recorded
+		commit("b1", "r", "b@x.io", nil),
+		commit("a5", "other", "a@x.io", &models.CommitSignature{Signed: true, Verified: true, SigningKey: "AAAA"}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(stats []models.ContributorStats) string {
+		var parts []string
+		for _, s := range stats {
+			pct, ok := s.SignedPercent()
+			parts = append(parts, fmt.Sprintf("%s=%d/%d/%d %.0f %v %v", s.Email, s.SignedCommits, s.VerifiedCommits, s.SignatureKnown, pct, ok, s.SigningKeys))
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	stats, _, err := database.GetCommitterStats("o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "a@x.io=2/1/3 67 true [AAAA BBBB]; b@x.io=0/0/0 0 false []"
+	if got := describe(stats); got != want {
+		t.Errorf("GetCommitterStats() = %q, want %q", got, want)
+	}
+
+	combined, _, err := database.GetCombinedCommitterStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "a@x.io=3/2/4 75 true [AAAA BBBB]; b@x.io=0/0/0 0 false []"
+	if got := describe(combined); got != want {
+		t.Errorf("GetCombinedCommitterStats() = %q, want %q", got, want)
+	}
+
+	// Loading a1 again from a JSON file without verification keeps its status
+	if err := database.InsertCommits([]models.CommitRecord{commit("a1", "r", "a@x.io", nil)}); err != nil {
+		t.Fatal(err)
+	}
+	stats, _, err = database.GetCommitterStats("o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(stats); !strings.HasPrefix(got, "a@x.io=2/1/3 ") {
+		t.Errorf("after re-insert without status GetCommitterStats() = %q", got)
+	}
+}
@@ -2,7 +2,11 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/thesavant42/gitsome-ng/internal/models"
 )
@@ -54,7 +58,10 @@ func (db *DB) GetTargetDomainsWithCounts() ([]models.TargetDomain, error) {
 	for rows.Next() {
 		var d models.TargetDomain
 		var addedAt string
-		if err := rows.Scan(&d.ID, &d.Domain, &d.VTEnumerated, &d.CrtshEnumerated, &addedAt, &d.SubdomainCount); err != nil {
+		if err := rows.Scan(
+			&d.ID, &d.Domain, &d.VTEnumerated, &d.CrtshEnumerated, &addedAt, &d.SubdomainCount,
+			&d.VTCount, &d.CrtshCount, &d.SecurityTrailsCount, &d.CensysCount, &d.ImportCount,
+		); err != nil {
 			return nil, fmt.Errorf("failed to scan target domain: %w", err)
 		}
 		d.AddedAt, _ = parseTimestamp(addedAt)
@@ -100,6 +107,39 @@ func (db *DB) MarkCrtshEnumerated(domain string) error {
 	return nil
 }
 
+// GetVTCursor returns the stored VirusTotal pagination cursor for a domain
+// Returns an empty string when there is nothing to resume
+func (db *DB) GetVTCursor(domain string) (string, error) {
+	var cursor string
+	err := db.conn.QueryRow(selectTargetDomainVTCursor, domain).Scan(&cursor)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get VT cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SetVTCursor stores the VirusTotal pagination cursor for a domain
+// Pass an empty cursor to clear it once enumeration completes
+func (db *DB) SetVTCursor(domain, cursor string) error {
+	_, err := db.conn.Exec(updateTargetDomainVTCursor, cursor, domain)
+	if err != nil {
+		return fmt.Errorf("failed to set VT cursor: %w", err)
+	}
+	return nil
+}
+
+// DeleteTargetDomainPreview returns how many subdomains DeleteTargetDomain would remove with the domain
+func (db *DB) DeleteTargetDomainPreview(domain string) (int, error) {
+	var count int
+	if err := db.conn.QueryRow(selectSubdomainCount, domain).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to preview target domain delete: %w", err)
+	}
+	return count, nil
+}
+
 // DeleteTargetDomain removes a target domain and all its subdomains
 func (db *DB) DeleteTargetDomain(domain string) error {
 	// Delete subdomains first (foreign key)
@@ -110,6 +150,15 @@ func (db *DB) DeleteTargetDomain(domain string) error {
 	if _, err := db.conn.Exec(deleteTargetDomain, domain); err != nil {
 		return fmt.Errorf("failed to delete target domain: %w", err)
 	}
+	if _, err := db.conn.Exec(deleteSubdomainSnapshotsByDomain, domain); err != nil {
+		return fmt.Errorf("failed to delete subdomain snapshots: %w", err)
+	}
+	// Forget its run and export times so a re-added domain starts fresh
+	for _, key := range []string{SettingSubdomainLastRunPrefix + domain, SettingSubdomainLastExportPrefix + domain} {
+		if err := db.DeleteSetting(key); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -133,7 +182,9 @@ func scanTargetDomains(rows *sql.Rows) ([]models.TargetDomain, error) {
 // =============================================================================
 
 // InsertSubdomains inserts multiple subdomains into the database
-// Uses INSERT OR IGNORE for deduplication, returns count of new records inserted
+// Uses INSERT OR IGNORE for deduplication; a subdomain already stored gets the new source,
+// CNAMEs and alt names merged in. Returns count of new records inserted. The call counts as a
+// run for every domain that gained subdomains, see GetLastSubdomainRun.
 func (db *DB) InsertSubdomains(subdomains []models.Subdomain) (int, error) {
 	if len(subdomains) == 0 {
 		return 0, nil
@@ -157,22 +208,38 @@ func (db *DB) InsertSubdomains(subdomains []models.Subdomain) (int, error) {
 	}
 	defer updateStmt.Close()
 
+	// discovered_at has second precision, so truncating keeps every new row at or after the start
+	runStart := time.Now().UTC().Truncate(time.Second)
+	grown := make(map[string]bool)
+
 	inserted := 0
 	for _, s := range subdomains {
-		// Try to insert
-		result, err := insertStmt.Exec(s.Domain, s.Subdomain, s.Source, s.CNAMEs, s.AltNames, s.CertExpired)
+		result, err := insertStmt.Exec(s.Domain, s.Subdomain, s.Source, s.Source, s.CNAMEs, s.AltNames, s.CertExpired, s.IsWildcard)
 		if err != nil {
-			// If insert fails (duplicate), try to update/merge
-			_, updateErr := updateStmt.Exec(s.CNAMEs, s.CNAMEs, s.AltNames, s.AltNames, s.CertExpired, s.Subdomain)
-			if updateErr != nil {
-				continue // Skip on both insert and update failure
-			}
 			continue
 		}
 
 		rowsAffected, _ := result.RowsAffected()
 		if rowsAffected > 0 {
 			inserted++
+			grown[s.Domain] = true
+			continue
+		}
+
+		// Already stored: merge this source and its names into the existing row
+		if _, err := updateStmt.Exec(
+			s.Source, s.Source,
+			s.CNAMEs, s.CNAMEs, s.CNAMEs, s.CNAMEs,
+			s.AltNames, s.AltNames, s.AltNames, s.AltNames,
+			s.CertExpired, s.IsWildcard, s.Subdomain,
+		); err != nil {
+			continue // Skip rows that fail to merge
+		}
+	}
+
+	for domain := range grown {
+		if _, err := tx.Exec(upsertSetting, SettingSubdomainLastRunPrefix+domain, runStart.Format(time.RFC3339)); err != nil {
+			return 0, fmt.Errorf("failed to record subdomain run: %w", err)
 		}
 	}
 
@@ -194,7 +261,8 @@ func (db *DB) GetSubdomains(domain string) ([]models.Subdomain, error) {
 	return scanSubdomains(rows)
 }
 
-// GetSubdomainsFiltered returns subdomains with filtering and pagination
+// GetSubdomainsFiltered returns subdomains with filtering and pagination. Pages are sorted by
+// subdomain, or newest first when DiscoveredSince is set.
 func (db *DB) GetSubdomainsFiltered(filter models.SubdomainFilter) ([]models.Subdomain, int, error) {
 	// Build search pattern
 	searchPattern := ""
@@ -202,10 +270,16 @@ func (db *DB) GetSubdomainsFiltered(filter models.SubdomainFilter) ([]models.Sub
 		searchPattern = "%" + filter.SearchText + "%"
 	}
 
+	since := ""
+	if !filter.DiscoveredSince.IsZero() {
+		since = filter.DiscoveredSince.UTC().Format("2006-01-02 15:04:05")
+	}
+
 	// Get total count first
 	var total int
 	err := db.conn.QueryRow(selectSubdomainCountFiltered,
 		filter.Domain, filter.SearchText, searchPattern, filter.Source, filter.Source, filter.CDXIndexed, filter.CDXIndexed,
+		since, since,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count subdomains: %w", err)
@@ -214,7 +288,7 @@ func (db *DB) GetSubdomainsFiltered(filter models.SubdomainFilter) ([]models.Sub
 	// Get paginated records
 	rows, err := db.conn.Query(selectSubdomainsFiltered,
 		filter.Domain, filter.SearchText, searchPattern, filter.Source, filter.Source, filter.CDXIndexed, filter.CDXIndexed,
-		filter.Limit, filter.Offset,
+		since, since, since, filter.Limit, filter.Offset,
 	)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query subdomains: %w", err)
@@ -229,6 +303,34 @@ func (db *DB) GetSubdomainsFiltered(filter models.SubdomainFilter) ([]models.Sub
 	return subdomains, total, nil
 }
 
+// GetRecentSubdomains returns the subdomains of domain discovered at or after since, newest first
+func (db *DB) GetRecentSubdomains(domain string, since time.Time) ([]models.Subdomain, error) {
+	rows, err := db.conn.Query(selectRecentSubdomains, domain, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent subdomains: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSubdomains(rows)
+}
+
+// SearchSubdomains finds subdomains of every target domain containing keyword
+// (case-insensitive), ordered by domain; at most limit are returned
+func (db *DB) SearchSubdomains(keyword string, limit int) ([]models.Subdomain, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []models.Subdomain{}, nil
+	}
+
+	rows, err := db.conn.Query(searchSubdomains, likePattern(keyword), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search subdomains: %w", err)
+	}
+	defer rows.Close()
+
+	return scanSubdomains(rows)
+}
+
 // GetAllSubdomainsForDomain returns all subdomains for a domain (for export)
 func (db *DB) GetAllSubdomainsForDomain(domain string) ([]models.Subdomain, error) {
 	rows, err := db.conn.Query(selectAllSubdomainsForDomain, domain)
@@ -255,7 +357,7 @@ func (db *DB) GetSubdomainStats(domain string) (*models.SubdomainStats, error) {
 	var stats models.SubdomainStats
 
 	err := db.conn.QueryRow(selectSubdomainStats, domain).Scan(
-		&stats.Total, &stats.VTCount, &stats.CrtshCount, &stats.ImportCount,
+		&stats.Total, &stats.VTCount, &stats.CrtshCount, &stats.SecurityTrailsCount, &stats.CensysCount, &stats.ImportCount,
 		&stats.CDXCount, &stats.ExpiredCount,
 	)
 	if err == sql.ErrNoRows {
@@ -277,6 +379,41 @@ func (db *DB) MarkSubdomainCDXIndexed(subdomain string) error {
 	return nil
 }
 
+// UpdateSubdomainResolution stores the DNS lookup result for a subdomain
+// An empty ips slice marks the subdomain as not resolving
+func (db *DB) UpdateSubdomainResolution(subdomain string, ips []string) error {
+	_, err := db.conn.Exec(updateSubdomainResolution, strings.Join(ips, ","), len(ips) > 0, subdomain)
+	if err != nil {
+		return fmt.Errorf("failed to update subdomain resolution: %w", err)
+	}
+	return nil
+}
+
+// UpdateSubdomainPTRNames stores the reverse DNS names of a subdomain's resolved IPs
+func (db *DB) UpdateSubdomainPTRNames(subdomain string, names []string) error {
+	_, err := db.conn.Exec(updateSubdomainPTRNames, strings.Join(names, ","), subdomain)
+	if err != nil {
+		return fmt.Errorf("failed to update subdomain PTR names: %w", err)
+	}
+	return nil
+}
+
+// UpdateSubdomainProbe stores the HTTP/HTTPS probe result for a subdomain, including the
+// certificate served over https:// (cleared when none was captured)
+func (db *DB) UpdateSubdomainProbe(s models.Subdomain) error {
+	var notBefore, notAfter any
+	if s.HasTLSCert() {
+		notBefore = s.TLSNotBefore.UTC().Format("2006-01-02T15:04:05Z")
+		notAfter = s.TLSNotAfter.UTC().Format("2006-01-02T15:04:05Z")
+	}
+	_, err := db.conn.Exec(updateSubdomainProbe, s.HTTPStatus, s.HTTPSStatus, s.ServerHeader, s.FinalURL,
+		s.TLSSubject, s.TLSSANs, s.TLSIssuer, notBefore, notAfter, s.TLSHostMismatch, s.Subdomain)
+	if err != nil {
+		return fmt.Errorf("failed to update subdomain probe: %w", err)
+	}
+	return nil
+}
+
 // DeleteSubdomain removes a single subdomain by ID
 func (db *DB) DeleteSubdomain(id int64) error {
 	_, err := db.conn.Exec(deleteSubdomain, id)
@@ -301,18 +438,43 @@ func scanSubdomains(rows *sql.Rows) ([]models.Subdomain, error) {
 	for rows.Next() {
 		var s models.Subdomain
 		var discoveredAt string
-		var cnames, altNames sql.NullString
+		var sources, cnames, altNames, resolvedIPs, ptrNames, serverHeader, finalURL sql.NullString
+		var tlsSubject, tlsSANs, tlsIssuer, tlsNotBefore, tlsNotAfter sql.NullString
+		var resolves, isWildcard, tlsHostMismatch sql.NullBool
+		var httpStatus, httpsStatus sql.NullInt64
 
 		if err := rows.Scan(
-			&s.ID, &s.Domain, &s.Subdomain, &s.Source, &cnames, &altNames,
-			&s.CertExpired, &s.CDXIndexed, &discoveredAt,
+			&s.ID, &s.Domain, &s.Subdomain, &s.Source, &sources, &cnames, &altNames,
+			&s.CertExpired, &isWildcard, &s.CDXIndexed, &resolvedIPs, &resolves, &ptrNames,
+			&httpStatus, &httpsStatus, &serverHeader, &finalURL, &discoveredAt,
+			&tlsSubject, &tlsSANs, &tlsIssuer, &tlsNotBefore, &tlsNotAfter, &tlsHostMismatch,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan subdomain: %w", err)
 		}
 
+		s.Sources = sources.String
+		if s.Sources == "" {
+			s.Sources = s.Source
+		}
 		s.CNAMEs = cnames.String
 		s.AltNames = altNames.String
+		s.IsWildcard = isWildcard.Bool
+		s.ResolvedIPs = resolvedIPs.String
+		s.Resolves = resolves.Bool
+		s.PTRNames = ptrNames.String
+		s.HTTPStatus = int(httpStatus.Int64)
+		s.HTTPSStatus = int(httpsStatus.Int64)
+		s.ServerHeader = serverHeader.String
+		s.FinalURL = finalURL.String
 		s.DiscoveredAt, _ = parseTimestamp(discoveredAt)
+		s.TLSSubject = tlsSubject.String
+		s.TLSSANs = tlsSANs.String
+		s.TLSIssuer = tlsIssuer.String
+		if tlsNotAfter.Valid {
+			s.TLSNotBefore, _ = parseTimestamp(tlsNotBefore.String)
+			s.TLSNotAfter, _ = parseTimestamp(tlsNotAfter.String)
+		}
+		s.TLSHostMismatch = tlsHostMismatch.Bool
 
 		subdomains = append(subdomains, s)
 	}
@@ -320,17 +482,155 @@ func scanSubdomains(rows *sql.Rows) ([]models.Subdomain, error) {
 	return subdomains, nil
 }
 
+// =============================================================================
+// Subdomain Snapshot Operations
+// =============================================================================
+
+// SaveSubdomainSnapshot records the domain's current subdomains under label, replacing an
+// older snapshot with the same label. An empty label is replaced by the current UTC time,
+// e.g. "2025-01-02T15:04:05Z". Returns the snapshot saved.
+func (db *DB) SaveSubdomainSnapshot(domain, label string) (*models.SubdomainSnapshot, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	names, err := db.subdomainNames(domain)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode subdomain snapshot: %w", err)
+	}
+	if _, err := db.conn.Exec(upsertSubdomainSnapshot, domain, label, string(data)); err != nil {
+		return nil, fmt.Errorf("failed to save subdomain snapshot: %w", err)
+	}
+	return &models.SubdomainSnapshot{Domain: domain, Label: label, Count: len(names), CreatedAt: time.Now().UTC().Truncate(time.Second)}, nil
+}
+
+// GetSubdomainSnapshots lists the snapshots saved for a domain, newest first
+func (db *DB) GetSubdomainSnapshots(domain string) ([]models.SubdomainSnapshot, error) {
+	rows, err := db.conn.Query(selectSubdomainSnapshots, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subdomain snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.SubdomainSnapshot
+	for rows.Next() {
+		s := models.SubdomainSnapshot{Domain: domain}
+		var createdAt string
+		if err := rows.Scan(&s.Label, &s.Count, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan subdomain snapshot: %w", err)
+		}
+		s.CreatedAt, _ = parseTimestamp(createdAt)
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// DiffSubdomainSnapshot compares the domain's current subdomains with the snapshot saved
+// under label. Returns nil if there is no such snapshot.
+func (db *DB) DiffSubdomainSnapshot(domain, label string) (*models.SubdomainDiff, error) {
+	var data, createdAt string
+	err := db.conn.QueryRow(selectSubdomainSnapshot, domain, label).Scan(&data, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subdomain snapshot: %w", err)
+	}
+	var saved []string
+	if err := json.Unmarshal([]byte(data), &saved); err != nil {
+		return nil, fmt.Errorf("failed to decode subdomain snapshot %s: %w", label, err)
+	}
+	current, err := db.subdomainNames(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.SubdomainDiff{Snapshot: models.SubdomainSnapshot{Domain: domain, Label: label, Count: len(saved)}}
+	diff.Snapshot.CreatedAt, _ = parseTimestamp(createdAt)
+
+	inSnapshot := make(map[string]bool, len(saved))
+	for _, name := range saved {
+		inSnapshot[name] = true
+	}
+	inCurrent := make(map[string]bool, len(current))
+	for _, name := range current {
+		inCurrent[name] = true
+		if !inSnapshot[name] {
+			diff.Added = append(diff.Added, name)
+		}
+	}
+	for _, name := range saved {
+		if !inCurrent[name] {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Removed)
+	return diff, nil
+}
+
+// DeleteSubdomainSnapshot removes the snapshot saved for a domain under label
+func (db *DB) DeleteSubdomainSnapshot(domain, label string) error {
+	if _, err := db.conn.Exec(deleteSubdomainSnapshot, domain, label); err != nil {
+		return fmt.Errorf("failed to delete subdomain snapshot: %w", err)
+	}
+	return nil
+}
+
+// subdomainNames returns the names of a domain's subdomains, sorted
+func (db *DB) subdomainNames(domain string) ([]string, error) {
+	rows, err := db.conn.Query(selectSubdomainNames, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subdomains: %w", err)
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan subdomain: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 // =============================================================================
 // Application Settings Operations
 // =============================================================================
 
 // Setting keys
 const (
-	SettingVirusTotalAPIKey = "virustotal_api_key"
+	SettingVirusTotalAPIKey     = "virustotal_api_key"
+	SettingSecurityTrailsAPIKey = "securitytrails_api_key"
+	SettingCensysAPIID          = "censys_api_id"
+	SettingCensysAPISecret      = "censys_api_secret"
+	SettingDockerUsername       = "docker_username"
+	SettingDockerPassword       = "docker_password"
+	SettingDockerSearchCacheTTL = "docker_search_cache_ttl"
+	SettingUIPrefs              = "ui_prefs"              // JSON-encoded TUI state restored when the project is reopened
+	SettingCommitMessageSearch  = "commit_message_search" // "true" when commit messages are full-text indexed
+
+	// Per-domain keys, suffixed with the target domain
+	SettingSubdomainLastRunPrefix    = "subdomain_last_run:"    // Start of the latest run that found new subdomains
+	SettingSubdomainLastExportPrefix = "subdomain_last_export:" // When the domain's subdomains were last exported
 )
 
 // SetSetting saves a setting to the database
+// Sensitive settings are encrypted when a passphrase is configured
 func (db *DB) SetSetting(key, value string) error {
+	if sensitiveSettings[key] && db.secretKey != nil && value != "" {
+		encrypted, err := db.encryptSetting(value)
+		if err != nil {
+			return err
+		}
+		value = encrypted
+	}
 	_, err := db.conn.Exec(upsertSetting, key, value)
 	if err != nil {
 		return fmt.Errorf("failed to save setting: %w", err)
@@ -338,8 +638,24 @@ func (db *DB) SetSetting(key, value string) error {
 	return nil
 }
 
-// GetSetting retrieves a setting from the database
+// GetSetting retrieves a setting from the database, decrypting it if it was stored encrypted
 func (db *DB) GetSetting(key string) (string, error) {
+	value, err := db.rawSetting(key)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(value, encryptedPrefix) {
+		plain, err := db.decryptSetting(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to get setting %s: %w", key, err)
+		}
+		return plain, nil
+	}
+	return value, nil
+}
+
+// rawSetting retrieves a setting exactly as stored
+func (db *DB) rawSetting(key string) (string, error) {
 	var value string
 	err := db.conn.QueryRow(selectSetting, key).Scan(&value)
 	if err == sql.ErrNoRows {
@@ -360,6 +676,35 @@ func (db *DB) DeleteSetting(key string) error {
 	return nil
 }
 
+// GetLastSubdomainRun returns when the latest run that found new subdomains of domain started,
+// or the zero time if none has. GetRecentSubdomains from this time lists that run's finds.
+func (db *DB) GetLastSubdomainRun(domain string) (time.Time, error) {
+	return db.timeSetting(SettingSubdomainLastRunPrefix + domain)
+}
+
+// GetLastSubdomainExport returns when domain's subdomains were last exported, or the zero time
+func (db *DB) GetLastSubdomainExport(domain string) (time.Time, error) {
+	return db.timeSetting(SettingSubdomainLastExportPrefix + domain)
+}
+
+// SetLastSubdomainExport records that domain's subdomains were exported at t
+func (db *DB) SetLastSubdomainExport(domain string, t time.Time) error {
+	return db.SetSetting(SettingSubdomainLastExportPrefix+domain, t.UTC().Format(time.RFC3339))
+}
+
+// timeSetting reads an RFC 3339 timestamp setting, returning the zero time when unset
+func (db *DB) timeSetting(key string) (time.Time, error) {
+	value, err := db.GetSetting(key)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse setting %s: %w", key, err)
+	}
+	return t, nil
+}
+
 // GetVirusTotalAPIKey retrieves the VirusTotal API key from settings
 func (db *DB) GetVirusTotalAPIKey() (string, error) {
 	return db.GetSetting(SettingVirusTotalAPIKey)
@@ -370,3 +715,54 @@ func (db *DB) SetVirusTotalAPIKey(apiKey string) error {
 	return db.SetSetting(SettingVirusTotalAPIKey, apiKey)
 }
 
+// GetSecurityTrailsAPIKey retrieves the SecurityTrails API key from settings
+func (db *DB) GetSecurityTrailsAPIKey() (string, error) {
+	return db.GetSetting(SettingSecurityTrailsAPIKey)
+}
+
+// SetSecurityTrailsAPIKey saves the SecurityTrails API key to settings
+func (db *DB) SetSecurityTrailsAPIKey(apiKey string) error {
+	return db.SetSetting(SettingSecurityTrailsAPIKey, apiKey)
+}
+
+// GetCensysCredentials retrieves the Censys API id and secret from settings
+func (db *DB) GetCensysCredentials() (string, string, error) {
+	apiID, err := db.GetSetting(SettingCensysAPIID)
+	if err != nil {
+		return "", "", err
+	}
+	secret, err := db.GetSetting(SettingCensysAPISecret)
+	if err != nil {
+		return "", "", err
+	}
+	return apiID, secret, nil
+}
+
+// SetCensysCredentials saves the Censys API id and secret to settings
+func (db *DB) SetCensysCredentials(apiID, secret string) error {
+	if err := db.SetSetting(SettingCensysAPIID, apiID); err != nil {
+		return err
+	}
+	return db.SetSetting(SettingCensysAPISecret, secret)
+}
+
+// GetDockerCredentials retrieves the registry username and password/token from settings
+func (db *DB) GetDockerCredentials() (string, string, error) {
+	username, err := db.GetSetting(SettingDockerUsername)
+	if err != nil {
+		return "", "", err
+	}
+	password, err := db.GetSetting(SettingDockerPassword)
+	if err != nil {
+		return "", "", err
+	}
+	return username, password, nil
+}
+
+// SetDockerCredentials saves the registry username and password/token to settings
+func (db *DB) SetDockerCredentials(username, password string) error {
+	if err := db.SetSetting(SettingDockerUsername, username); err != nil {
+		return err
+	}
+	return db.SetSetting(SettingDockerPassword, password)
+}
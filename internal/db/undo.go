@@ -0,0 +1,188 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Deletion holds the rows removed by one of the Delete*Undoable calls so RestoreDeletion can
+// put them back. Rows are captured before the delete, from every table it touches.
+type Deletion struct {
+	Description string // what was deleted, e.g. "committer alice@example.com"
+	tables      []deletedRows
+}
+
+// Rows returns how many rows the deletion removed across all tables
+func (d *Deletion) Rows() int {
+	n := 0
+	for _, t := range d.tables {
+		n += len(t.rows)
+	}
+	return n
+}
+
+// deletedRows is a snapshot of the rows one table lost
+type deletedRows struct {
+	table   string
+	columns []string
+	rows    [][]any
+}
+
+// rowSelector picks the rows of a table that a delete is about to remove
+type rowSelector struct {
+	table string
+	where string
+	args  []any
+}
+
+// captureDeletion snapshots every row matched by the selectors, in order. RestoreDeletion
+// re-inserts tables in the same order, so parents should come before their children.
+func (db *DB) captureDeletion(description string, selectors ...rowSelector) (*Deletion, error) {
+	d := &Deletion{Description: description}
+	for _, sel := range selectors {
+		rows, err := db.conn.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s", sel.table, sel.where), sel.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to snapshot %s: %w", sel.table, err)
+		}
+		snapshot, err := scanDeletedRows(sel.table, rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		d.tables = append(d.tables, snapshot)
+	}
+	return d, nil
+}
+
+// scanDeletedRows reads every row of a SELECT * into a table snapshot
+func scanDeletedRows(table string, rows *sql.Rows) (deletedRows, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return deletedRows{}, fmt.Errorf("failed to read %s columns: %w", table, err)
+	}
+	snapshot := deletedRows{table: table, columns: columns}
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return deletedRows{}, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		snapshot.rows = append(snapshot.rows, values)
+	}
+	return snapshot, rows.Err()
+}
+
+// RestoreDeletion re-inserts the rows of a deletion in one transaction. Rows that were
+// re-created since (e.g. by a fresh fetch) are kept as they are.
+func (db *DB) RestoreDeletion(d *Deletion) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, t := range d.tables {
+		if len(t.rows) == 0 {
+			continue
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", ")
+		stmt, err := tx.Prepare(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+			t.table, strings.Join(t.columns, ", "), placeholders))
+		if err != nil {
+			return fmt.Errorf("failed to prepare %s restore: %w", t.table, err)
+		}
+		for _, row := range t.rows {
+			if _, err := stmt.Exec(row...); err != nil {
+				stmt.Close()
+				return fmt.Errorf("failed to restore %s row: %w", t.table, err)
+			}
+		}
+		stmt.Close()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return nil
+}
+
+// DeleteCommitterUndoable deletes a committer like DeleteCommitterByEmail and returns what
+// was removed: their commits, those commits' co-authors and files, and the committer's tag and link
+func (db *DB) DeleteCommitterUndoable(repoOwner, repoName, email string) (*Deletion, error) {
+	args := []any{repoOwner, repoName, email}
+	d, err := db.captureDeletion("committer "+email,
+		rowSelector{"commits", "repo_owner = ? AND repo_name = ? AND committer_email = ?", args},
+		rowSelector{"co_authors", "commit_sha IN (SELECT sha FROM commits WHERE repo_owner = ? AND repo_name = ? AND committer_email = ?)", args},
+		rowSelector{"commit_files", "commit_sha IN (SELECT sha FROM commits WHERE repo_owner = ? AND repo_name = ? AND committer_email = ?)", args},
+		rowSelector{"committer_tags", "repo_owner = ? AND repo_name = ? AND committer_email = ?", args},
+		rowSelector{"committer_links", "repo_owner = ? AND repo_name = ? AND committer_email = ?", args},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteCommitterByEmail(repoOwner, repoName, email); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteTrackedRepoUndoable removes a tracked repository and all its data (as
+// DeleteRepositoryData and RemoveTrackedRepo do) and returns what was removed
+func (db *DB) DeleteTrackedRepoUndoable(repoOwner, repoName string) (*Deletion, error) {
+	args := []any{repoOwner, repoName}
+	d, err := db.captureDeletion(fmt.Sprintf("repository %s/%s", repoOwner, repoName),
+		rowSelector{"tracked_repos", "repo_owner = ? AND repo_name = ?", args},
+		rowSelector{"commits", "repo_owner = ? AND repo_name = ?", args},
+		rowSelector{"co_authors", "commit_sha IN (SELECT sha FROM commits WHERE repo_owner = ? AND repo_name = ?)", args},
+		rowSelector{"commit_files", "commit_sha IN (SELECT sha FROM commits WHERE repo_owner = ? AND repo_name = ?)", args},
+		rowSelector{"committer_tags", "repo_owner = ? AND repo_name = ?", args},
+		rowSelector{"committer_links", "repo_owner = ? AND repo_name = ?", args},
+		rowSelector{"link_group_labels", "repo_owner = ? AND repo_name = ?", args},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteRepositoryData(repoOwner, repoName); err != nil {
+		return nil, err
+	}
+	if err := db.RemoveTrackedRepo(repoOwner, repoName); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteUserRepositoryUndoable deletes a user's repository like DeleteUserRepository and
+// returns what was removed
+func (db *DB) DeleteUserRepositoryUndoable(githubLogin, repoName string) (*Deletion, error) {
+	d, err := db.captureDeletion("repository "+repoName,
+		rowSelector{"user_repositories", "github_login = ? AND name = ?", []any{githubLogin, repoName}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteUserRepository(githubLogin, repoName); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteUserGistUndoable deletes a gist with its files and comments like DeleteUserGist and
+// returns what was removed
+func (db *DB) DeleteUserGistUndoable(githubLogin, gistID string) (*Deletion, error) {
+	d, err := db.captureDeletion("gist "+gistID,
+		rowSelector{"user_gists", "github_login = ? AND id = ?", []any{githubLogin, gistID}},
+		rowSelector{"gist_files", "gist_id = ?", []any{gistID}},
+		rowSelector{"gist_comments", "gist_id = ?", []any{gistID}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.DeleteUserGist(githubLogin, gistID); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -3,14 +3,17 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 // InsertWaybackRecords inserts multiple CDX records into the database
-// Uses INSERT OR IGNORE to skip duplicates (based on unique URL constraint)
-// Returns the number of records actually inserted
+// Duplicates (URLs with the same normalized key, see models.NormalizeCDXURL) are skipped unless
+// the record carries a capture count, in which case the count is added to the existing row and
+// the newest capture kept.
+// Returns the number of records inserted or merged
 func (db *DB) InsertWaybackRecords(records []models.CDXRecord) (int, error) {
 	if len(records) == 0 {
 		return 0, nil
@@ -40,7 +43,7 @@ func (db *DB) InsertWaybackRecords(records []models.CDXRecord) (int, error) {
 			mimeType = *r.MimeType
 		}
 
-		result, err := stmt.Exec(r.URL, r.Domain, r.Timestamp, statusCode, mimeType, r.Tags)
+		result, err := stmt.Exec(r.URL, models.NormalizeCDXURL(r.URL), r.Domain, r.Timestamp, statusCode, mimeType, r.Tags, r.Captures)
 		if err != nil {
 			// Skip errors for individual records (e.g., duplicates)
 			continue
@@ -59,6 +62,14 @@ func (db *DB) InsertWaybackRecords(records []models.CDXRecord) (int, error) {
 	return inserted, nil
 }
 
+// ResetWaybackCaptures clears the capture counts for a domain before a fresh counting fetch
+func (db *DB) ResetWaybackCaptures(domain string) error {
+	if _, err := db.conn.Exec(resetWaybackCaptures, domain); err != nil {
+		return fmt.Errorf("failed to reset wayback captures: %w", err)
+	}
+	return nil
+}
+
 // GetWaybackRecords retrieves all wayback records for a domain
 func (db *DB) GetWaybackRecords(domain string) ([]models.CDXRecord, error) {
 	rows, err := db.conn.Query(selectWaybackRecords, domain)
@@ -98,7 +109,7 @@ func (db *DB) GetWaybackRecordsFiltered(filter models.WaybackFilter) ([]models.C
 	// Get paginated records
 	rows, err := db.conn.Query(selectWaybackRecordsByFilter,
 		filter.Domain, filter.MimeType, mimePattern, filter.SearchText, searchPattern, filter.Tags, tagPattern,
-		filter.Limit, filter.Offset,
+		filter.SortByCaptures, filter.Limit, filter.Offset,
 	)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query wayback records: %w", err)
@@ -113,6 +124,23 @@ func (db *DB) GetWaybackRecordsFiltered(filter models.WaybackFilter) ([]models.C
 	return records, total, nil
 }
 
+// SearchWaybackURLs finds cached CDX records of every domain whose URL contains keyword
+// (case-insensitive), ordered by domain; at most limit are returned
+func (db *DB) SearchWaybackURLs(keyword string, limit int) ([]models.CDXRecord, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []models.CDXRecord{}, nil
+	}
+
+	rows, err := db.conn.Query(searchWaybackURLs, likePattern(keyword), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search wayback URLs: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWaybackRecords(rows)
+}
+
 // GetWaybackRecordCount returns the total number of records for a domain
 func (db *DB) GetWaybackRecordCount(domain string) (int, error) {
 	var count int
@@ -178,9 +206,10 @@ func scanWaybackRecords(rows *sql.Rows) ([]models.CDXRecord, error) {
 		var fetchedAt string
 		var statusCode sql.NullInt64
 		var mimeType, timestamp sql.NullString
+		var captures sql.NullInt64
 
 		if err := rows.Scan(
-			&r.ID, &r.URL, &r.Domain, &timestamp, &statusCode, &mimeType, &r.Tags, &fetchedAt,
+			&r.ID, &r.URL, &r.Domain, &timestamp, &statusCode, &mimeType, &r.Tags, &captures, &fetchedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan wayback record: %w", err)
 		}
@@ -196,6 +225,7 @@ func scanWaybackRecords(rows *sql.Rows) ([]models.CDXRecord, error) {
 			mt := mimeType.String
 			r.MimeType = &mt
 		}
+		r.Captures = int(captures.Int64)
 		r.FetchedAt, _ = parseTimestamp(fetchedAt)
 
 		records = append(records, r)
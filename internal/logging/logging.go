@@ -0,0 +1,126 @@
+// Package logging configures the logger shared by the commands, API clients and database
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// LevelEnvVar names the environment variable holding the default log level
+const LevelEnvVar = "GITSOME_LOG_LEVEL"
+
+// DefaultFile is where logs go while a TUI owns the terminal and no -log-file was given
+const DefaultFile = "gitsome.log"
+
+// defaultLevel applies when neither -log-level nor GITSOME_LOG_LEVEL is set
+const defaultLevel = log.WarnLevel
+
+var shared = log.NewWithOptions(os.Stderr, log.Options{
+	Level:           defaultLevel,
+	ReportTimestamp: true,
+	TimeFormat:      time.RFC3339,
+})
+
+// Options configures the shared logger
+type Options struct {
+	Level       string // debug|info|warn|error; empty falls back to GITSOME_LOG_LEVEL, then warn
+	File        string // Also write logs to this file
+	Interactive bool   // A TUI owns the terminal: write to File (or DefaultFile) only, never stderr
+}
+
+// AddFlags registers -log-level and -log-file on fs, filling the returned options when parsed
+func AddFlags(fs *flag.FlagSet) *Options {
+	opts := &Options{}
+	fs.StringVar(&opts.Level, "log-level", "", "Log level: debug|info|warn|error (default $"+LevelEnvVar+" or warn)")
+	fs.StringVar(&opts.File, "log-file", "", "Also write logs to this file")
+	return opts
+}
+
+// Logger returns the shared logger
+func Logger() *log.Logger {
+	return shared
+}
+
+// WithPrefix returns a child of the shared logger that prefixes its entries, e.g. "API".
+// Children copy the shared settings, so create them after Setup.
+func WithPrefix(prefix string) *log.Logger {
+	return shared.WithPrefix(prefix)
+}
+
+// Setup applies opts to the shared logger. The returned closer releases the log file.
+func Setup(opts Options) (io.Closer, error) {
+	raw := strings.TrimSpace(opts.Level)
+	if raw == "" {
+		raw = strings.TrimSpace(os.Getenv(LevelEnvVar))
+	}
+	level := defaultLevel
+	if raw != "" {
+		parsed, err := log.ParseLevel(strings.ToLower(raw))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q (want debug|info|warn|error)", raw)
+		}
+		level = parsed
+	}
+
+	path := opts.File
+	if path == "" && opts.Interactive {
+		path = DefaultFile
+	}
+
+	var writers []io.Writer
+	if !opts.Interactive {
+		writers = append(writers, os.Stderr)
+	}
+	file := &lazyFile{path: path}
+	if path != "" {
+		writers = append(writers, file)
+	}
+
+	shared.SetOutput(io.MultiWriter(writers...))
+	shared.SetLevel(level)
+	return file, nil
+}
+
+// lazyFile opens its file on the first write, so runs that log nothing leave no empty file
+// behind. Write errors are swallowed - logging must never break the app.
+type lazyFile struct {
+	path string
+	once sync.Once
+	mu   sync.Mutex
+	f    *os.File
+}
+
+// Write appends p to the file, opening it first if needed
+func (l *lazyFile) Write(p []byte) (int, error) {
+	l.once.Do(func() {
+		f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err == nil {
+			l.f = f
+		}
+	})
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		l.f.Write(p)
+	}
+	return len(p), nil
+}
+
+// Close closes the file if it was opened
+func (l *lazyFile) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	err := l.f.Close()
+	l.f = nil
+	return err
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+)
+
+// TestSetup verifies level resolution (flag, then GITSOME_LOG_LEVEL) and that interactive runs
+// log only to a file that is created on the first entry
+func TestSetup(t *testing.T) {
+	defer func() {
+		shared.SetOutput(os.Stderr)
+		shared.SetLevel(defaultLevel)
+	}()
+
+	t.Setenv(LevelEnvVar, "")
+	if _, err := Setup(Options{Level: "loud"}); err == nil {
+		t.Error("Setup(loud) should fail")
+	}
+
+	t.Setenv(LevelEnvVar, "DEBUG")
+	closer, err := Setup(Options{})
+	if err != nil || shared.GetLevel() != log.DebugLevel {
+		t.Fatalf("env level = %v, %v", shared.GetLevel(), err)
+	}
+	closer.Close()
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	closer, err = Setup(Options{Level: "info", File: path, Interactive: true})
+	if err != nil || shared.GetLevel() != log.InfoLevel {
+		t.Fatalf("flag level = %v, %v", shared.GetLevel(), err)
+	}
+	defer closer.Close()
+
+	WithPrefix("API").Debug("hidden")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("log file created before anything was logged: %v", err)
+	}
+	WithPrefix("API").Info("fetched", "page", 2)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "API") || !strings.Contains(got, "fetched page=2") || strings.Contains(got, "hidden") {
+		t.Errorf("log file = %q", got)
+	}
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // GitUser represents the git identity (name, email, date) from commit.author/commit.committer
 type GitUser struct {
@@ -19,10 +24,18 @@ type GitHubUser struct {
 
 // CommitDetails contains the git commit metadata
 type CommitDetails struct {
-	Author       GitUser `json:"author"`
-	Committer    GitUser `json:"committer"`
-	Message      string  `json:"message"`
-	CommentCount int     `json:"comment_count"`
+	Author       GitUser             `json:"author"`
+	Committer    GitUser             `json:"committer"`
+	Message      string              `json:"message"`
+	CommentCount int                 `json:"comment_count"`
+	Verification *CommitVerification `json:"verification"` // Absent in older cached JSON
+}
+
+// CommitVerification is GitHub's signature check of a commit
+type CommitVerification struct {
+	Verified  bool   `json:"verified"`
+	Reason    string `json:"reason"`    // e.g. "valid", "unsigned", "unknown_key"
+	Signature string `json:"signature"` // Armored PGP, SSH or S/MIME signature; empty when unsigned
 }
 
 // Commit represents a GitHub API commit response
@@ -34,6 +47,15 @@ type Commit struct {
 	Committer *GitHubUser    `json:"committer"` // GitHub user, can be null
 	HTMLURL   string         `json:"html_url"`
 	Parents   []CommitParent `json:"parents"`
+	Files     []CommitFile   `json:"files,omitempty"` // Only in single-commit responses
+}
+
+// CommitFile is a file changed by a commit
+type CommitFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"` // added, modified, removed, renamed, ...
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
 }
 
 // CommitParent represents a parent commit reference
@@ -57,6 +79,44 @@ type CommitRecord struct {
 	HTMLURL              string
 	RepoOwner            string
 	RepoName             string
+	CoAuthors            []CoAuthor       // From Co-authored-by trailers in Message
+	Files                []string         // Changed file paths; nil when files weren't fetched
+	Signature            *CommitSignature // nil when the verification status wasn't fetched
+}
+
+// CommitSignature is the stored signing status of a commit
+type CommitSignature struct {
+	Signed     bool
+	Verified   bool   // GitHub verified the signature against the committer's account
+	SigningKey string // GPG long key ID or SSH "SHA256:" fingerprint, when it can be read
+}
+
+// CoAuthor is a contributor credited by a "Co-authored-by: Name <email>" commit trailer
+type CoAuthor struct {
+	Name  string
+	Email string
+}
+
+// coAuthorTrailer matches one Co-authored-by trailer line; the key is case-insensitive
+var coAuthorTrailer = regexp.MustCompile(`(?im)^[ \t]*co-authored-by:[ \t]*(.*?)[ \t]*<([^<>\s]+@[^<>\s]+)>[ \t]*\r?$`)
+
+// ParseCoAuthors extracts Co-authored-by trailers from a commit message, once per email
+// Every line is checked, not just the final trailer block, since squash merges often list the
+// trailers of each squashed commit mid-message. Lines without an email in angle brackets are
+// skipped.
+func ParseCoAuthors(message string) []CoAuthor {
+	var coAuthors []CoAuthor
+	seen := make(map[string]bool)
+	for _, match := range coAuthorTrailer.FindAllStringSubmatch(message, -1) {
+		email := strings.TrimSpace(match[2])
+		key := strings.ToLower(email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		coAuthors = append(coAuthors, CoAuthor{Name: strings.TrimSpace(match[1]), Email: email})
+	}
+	return coAuthors
 }
 
 // ToRecord converts a Commit to a CommitRecord for database storage
@@ -73,6 +133,21 @@ func (c *Commit) ToRecord(repoOwner, repoName string) CommitRecord {
 		HTMLURL:       c.HTMLURL,
 		RepoOwner:     repoOwner,
 		RepoName:      repoName,
+		CoAuthors:     ParseCoAuthors(c.Commit.Message),
+	}
+	if c.Files != nil {
+		record.Files = make([]string, 0, len(c.Files))
+		for _, f := range c.Files {
+			record.Files = append(record.Files, f.Filename)
+		}
+	}
+
+	if v := c.Commit.Verification; v != nil {
+		record.Signature = &CommitSignature{
+			Signed:     v.Signature != "",
+			Verified:   v.Verified,
+			SigningKey: SigningKeyID(v.Signature),
+		}
 	}
 
 	if c.Author != nil {
@@ -82,9 +157,78 @@ func (c *Commit) ToRecord(repoOwner, repoName string) CommitRecord {
 		record.GitHubCommitterLogin = c.Committer.Login
 	}
 
+	// GitHub leaves the account null when it can't match the email, but noreply addresses
+	// still name the login
+	if record.GitHubAuthorLogin == "" {
+		record.GitHubAuthorLogin, _ = ResolveNoreplyLogin(record.AuthorEmail)
+	}
+	if record.GitHubCommitterLogin == "" {
+		record.GitHubCommitterLogin, _ = ResolveNoreplyLogin(record.CommitterEmail)
+	}
+
 	return record
 }
 
+// noreplyDomain hosts the private commit emails GitHub assigns to accounts
+const noreplyDomain = "@users.noreply.github.com"
+
+// noreplyLogin matches a GitHub login, including app bots like "dependabot[bot]"
+var noreplyLogin = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]{0,38}(?:\[bot\])?$`)
+
+// noreplyLocalPart returns the part of a GitHub noreply email before the domain
+func noreplyLocalPart(email string) (string, bool) {
+	email = strings.TrimSpace(email)
+	if len(email) <= len(noreplyDomain) || !strings.EqualFold(email[len(email)-len(noreplyDomain):], noreplyDomain) {
+		return "", false
+	}
+	return email[:len(email)-len(noreplyDomain)], true
+}
+
+// IsNoreplyEmail returns true if the email is a GitHub noreply address that can't receive mail
+func IsNoreplyEmail(email string) bool {
+	_, ok := noreplyLocalPart(email)
+	return ok
+}
+
+// ResolveNoreplyLogin extracts the GitHub login from a noreply commit email, either
+// "12345678+login@users.noreply.github.com" or the older "login@users.noreply.github.com".
+// A bare numeric local part is an account ID, not a login: see NoreplyUserID.
+func ResolveNoreplyLogin(email string) (login string, ok bool) {
+	local, ok := noreplyLocalPart(email)
+	if !ok {
+		return "", false
+	}
+	if id, name, found := strings.Cut(local, "+"); found {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return "", false
+		}
+		local = name
+	} else if _, err := strconv.ParseInt(local, 10, 64); err == nil {
+		return "", false
+	}
+	if !noreplyLogin.MatchString(local) {
+		return "", false
+	}
+	return local, true
+}
+
+// NoreplyUserID returns the account ID from a noreply email whose local part is only the
+// numeric ID ("12345678@users.noreply.github.com"), which needs an API lookup to name the login
+func NoreplyUserID(email string) (int64, bool) {
+	local, ok := noreplyLocalPart(email)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(local, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// ContributorSourceCoAuthor marks contributor stats counted from Co-authored-by trailers
+const ContributorSourceCoAuthor = "co-author"
+
 // ContributorStats holds statistics for a contributor
 type ContributorStats struct {
 	Name        string
@@ -92,6 +236,35 @@ type ContributorStats struct {
 	GitHubLogin string
 	CommitCount int
 	Percentage  float64
+	Source      string // Empty for committers, ContributorSourceCoAuthor for co-authors
+
+	// Signing stats only count commits fetched with their verification status
+	SignatureKnown  int      // commits whose signing status is known
+	SignedCommits   int      // of those, commits carrying a signature
+	VerifiedCommits int      // of those, signatures GitHub verified
+	SigningKeys     []string // distinct keys the committer signed with
+}
+
+// SignedPercent returns the share of commits with known status that are signed, and false
+// when no commit's status is known (e.g. commits cached before signatures were recorded)
+func (s ContributorStats) SignedPercent() (float64, bool) {
+	if s.SignatureKnown == 0 {
+		return 0, false
+	}
+	return float64(s.SignedCommits) / float64(s.SignatureKnown) * 100, true
+}
+
+// IsCoAuthor reports whether the stats come from Co-authored-by trailers rather than commits
+func (s ContributorStats) IsCoAuthor() bool {
+	return s.Source == ContributorSourceCoAuthor
+}
+
+// DisplayName returns the name with a marker for co-authors
+func (s ContributorStats) DisplayName() string {
+	if s.IsCoAuthor() {
+		return s.Name + " (co-author)"
+	}
+	return s.Name
 }
 
 // RepoInfo holds information about a tracked repository
@@ -201,11 +374,76 @@ type UserProfile struct {
 	FetchedAt       time.Time
 }
 
+// UserPublicKey represents a public SSH or GPG key registered to a GitHub user
+type UserPublicKey struct {
+	ID          int64
+	GitHubLogin string
+	KeyType     string // SSH algorithm (e.g., "ssh-ed25519") or "gpg"
+	KeyID       string // GraphQL node ID for SSH keys, long key ID for GPG keys
+	Fingerprint string // SSH fingerprint; GPG keys use the key ID (the API has no fingerprint)
+	CreatedAt   string
+	FetchedAt   time.Time
+}
+
+// UserEvent represents a public activity event (push, PR, issue, ...) by a GitHub user
+type UserEvent struct {
+	ID          int64
+	GitHubLogin string
+	EventID     string
+	Type        string // e.g., "PushEvent", "PullRequestEvent"
+	RepoName    string // owner/repo
+	URL         string // related PR/issue URL, or the repo URL
+	CreatedAt   string
+	FetchedAt   time.Time
+}
+
 // UserData contains all fetched data for a user
 type UserData struct {
 	Login        string
 	Profile      UserProfile
 	Repositories []UserRepository
 	Gists        []UserGist
+	PublicKeys   []UserPublicKey
+	Events       []UserEvent
+	Truncated    bool // repos or gists stopped at the page cap
+}
+
+// LinkGroupMember is one linked committer identity
+type LinkGroupMember struct {
+	Email   string
+	Name    string // most recent committer name used with the email
+	Login   string // GitHub login, when known
+	Repo    string // owner/repo the link was made in
+	Commits int    // commits by the email in Repo
+}
+
+// LinkGroup is a cluster of committer identities linked as the same person
+type LinkGroup struct {
+	GroupID int      // group ID within the repo (the first repo's when merged across repos)
+	Label   string   // optional name, e.g. "John Doe / @jdoe"; empty for unlabeled groups
+	Repos   []string // owner/repo names the links were made in
+	Members []LinkGroupMember
 }
 
+// LinkGroupName returns the label of a link group, or "#<id>" when it has none
+func LinkGroupName(groupID int, label string) string {
+	if label != "" {
+		return label
+	}
+	return "#" + strconv.Itoa(groupID)
+}
+
+// FileStats is how often a file path changed in a repository, and who changed it most
+type FileStats struct {
+	Path    string
+	Commits int
+	Editors []FileEditor // most frequent committers first
+}
+
+// FileEditor is a committer who changed a file, with how many of their commits touched it
+type FileEditor struct {
+	Name    string
+	Email   string
+	Login   string
+	Commits int
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseCoAuthors covers trailer formats seen in real GitHub commit messages
+func TestParseCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []CoAuthor
+	}{
+		{
+			name:    "single trailer",
+			message: "Add feature\n\nCo-authored-by: Jane Doe <jane@example.com>",
+			want:    []CoAuthor{{"Jane Doe", "jane@example.com"}},
+		},
+		{
+			name:    "multiple trailers with noreply emails",
+			message: "Pair on parser\n\nCo-authored-by: octocat <583231+octocat@users.noreply.github.com>\nCo-authored-by: Mona <mona@users.noreply.github.com>\n",
+			want:    []CoAuthor{{"octocat", "583231+octocat@users.noreply.github.com"}, {"Mona", "mona@users.noreply.github.com"}},
+		},
+		{
+			name:    "key case and spacing vary",
+			message: "Fix\n\nco-authored-by:Jane <jane@example.com>\nCo-Authored-By:   Bob Smith   <bob@example.com>  \r\n",
+			want:    []CoAuthor{{"Jane", "jane@example.com"}, {"Bob Smith", "bob@example.com"}},
+		},
+		{
+			name:    "squash merge repeats trailers mid-message",
+			message: "Feature (#12)\n\n* first\n\nCo-authored-by: Jane <jane@example.com>\n\n* second\n\nCo-authored-by: Jane <JANE@example.com>\nCo-authored-by: Bob <bob@example.com>",
+			want:    []CoAuthor{{"Jane", "jane@example.com"}, {"Bob", "bob@example.com"}},
+		},
+		{
+			name:    "indented trailer",
+			message: "Merge\n\n    Co-authored-by: Jane <jane@example.com>",
+			want:    []CoAuthor{{"Jane", "jane@example.com"}},
+		},
+		{
+			name:    "malformed trailers are skipped",
+			message: "Co-authored-by: Jane\nCo-authored-by: Bob <not-an-email>\nCo-authored-by: <>\nSee Co-authored-by: Eve <eve@example.com> in the docs",
+		},
+		{
+			name:    "no trailers",
+			message: "Plain commit message",
+		},
+	}
+	for _, tt := range tests {
+		if got := ParseCoAuthors(tt.message); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseCoAuthors() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestResolveNoreplyLogin covers both noreply email forms and addresses that don't name a login
+func TestResolveNoreplyLogin(t *testing.T) {
+	tests := []struct {
+		email string
+		login string
+		ok    bool
+	}{
+		{"583231+octocat@users.noreply.github.com", "octocat", true},
+		{"octocat@users.noreply.github.com", "octocat", true},
+		{"49699333+dependabot[bot]@users.noreply.github.com", "dependabot[bot]", true},
+		{"1234+Mona-Lisa@Users.NoReply.GitHub.com", "Mona-Lisa", true},
+		{"583231@users.noreply.github.com", "", false}, // ID only - needs an API lookup
+		{"abc+octocat@users.noreply.github.com", "", false},
+		{"-octocat@users.noreply.github.com", "", false},
+		{"noreply@github.com", "", false},
+		{"octocat@example.com", "", false},
+		{"@users.noreply.github.com", "", false},
+	}
+	for _, tt := range tests {
+		login, ok := ResolveNoreplyLogin(tt.email)
+		if login != tt.login || ok != tt.ok {
+			t.Errorf("ResolveNoreplyLogin(%q) = %q, %v; want %q, %v", tt.email, login, ok, tt.login, tt.ok)
+		}
+	}
+
+	if id, ok := NoreplyUserID("583231@users.noreply.github.com"); !ok || id != 583231 {
+		t.Errorf("NoreplyUserID(ID only) = %d, %v", id, ok)
+	}
+	if _, ok := NoreplyUserID("583231+octocat@users.noreply.github.com"); ok {
+		t.Error("NoreplyUserID(ID+login) should leave it to ResolveNoreplyLogin")
+	}
+
+	// ToRecord fills in logins GitHub left null, and keeps the ones it set
+	c := Commit{Commit: CommitDetails{
+		Author:    GitUser{Email: "583231+octocat@users.noreply.github.com"},
+		Committer: GitUser{Email: "mona@users.noreply.github.com"},
+	}, Committer: &GitHubUser{Login: "monalisa"}}
+	if r := c.ToRecord("o", "r"); r.GitHubAuthorLogin != "octocat" || r.GitHubCommitterLogin != "monalisa" {
+		t.Errorf("ToRecord() logins = %q, %q", r.GitHubAuthorLogin, r.GitHubCommitterLogin)
+	}
+}
+
+// TestSigningKeyID reads the key from signatures made by gpg and ssh-keygen
+func TestSigningKeyID(t *testing.T) {
+	const pgp = `-----BEGIN PGP SIGNATURE-----
+
+iHUEABYIAB0WIQT/LCW62u7ZD+oP9Qgbvo4T3Y3ydwUCatGlqwAKCRAbvo4T3Y3y
+d9EZAP0Q1CgWj3jf4A6lD1mqLFO9HDlOekkUqE1WmxJoCccu7wD/b9X8It6xD19k
+42VgZbZMQauapvtao9sIirqqZ2lsZgU=
+=ULbH
+-----END PGP SIGNATURE-----`
+	const ssh = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgXjxNOT+ZFBowrzGrUX5x9csmvP
+ijQkJrQMhw2sl2/3MAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5
+AAAAQA0voA194L80Ix4vI+/0rbK0ZFbH8B02LfP6IepCrCiXWdABK81L48oJohhiKdwfvL
+1CvGZtDUjzde4gdOsw1Q8=
+-----END SSH SIGNATURE-----`
+
+	tests := []struct {
+		name      string
+		signature string
+		want      string
+	}{
+		{"pgp", pgp, "1BBE8E13DD8DF277"},
+		{"ssh", ssh, "SHA256:P8Hfm7dXQAriE5yBkpbIRJGVGaml4/AQD9a1pzCufRg"},
+		{"unsigned", "", ""},
+		{"truncated", "-----BEGIN PGP SIGNATURE-----\n\niHUEABYIAB0W\n-----END PGP SIGNATURE-----", ""},
+		{"smime", "-----BEGIN SIGNED MESSAGE-----\nMIIG\n-----END SIGNED MESSAGE-----", ""},
+	}
+	for _, tt := range tests {
+		if got := SigningKeyID(tt.signature); got != tt.want {
+			t.Errorf("SigningKeyID(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// ToRecord only sets a signature status when the response carried one
+	c := Commit{SHA: "a", Commit: CommitDetails{Verification: &CommitVerification{Verified: true, Reason: "valid", Signature: pgp}}}
+	want := &CommitSignature{Signed: true, Verified: true, SigningKey: "1BBE8E13DD8DF277"}
+	if got := c.ToRecord("o", "r").Signature; !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRecord().Signature = %+v, want %+v", got, want)
+	}
+	c.Commit.Verification = nil
+	if got := c.ToRecord("o", "r").Signature; got != nil {
+		t.Errorf("ToRecord() without verification = %+v, want nil", got)
+	}
+}
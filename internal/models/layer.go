@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// LayerSecretFinding represents a likely secret found inside a Docker image layer file
+type LayerSecretFinding struct {
+	ID          int64
+	ImageRef    string // Image the layer was inspected for
+	LayerDigest string
+	FilePath    string // Path of the file inside the layer
+	RuleName    string // Secret rule that matched (e.g., "aws-access-key-id")
+	Preview     string // Matching line with the secret redacted
+	FoundAt     time.Time
+}
+
+// ImageConfig holds the runtime settings from a Docker image config blob
+type ImageConfig struct {
+	User         string            `json:"user,omitempty"`
+	Env          []string          `json:"env,omitempty"`
+	Entrypoint   []string          `json:"entrypoint,omitempty"`
+	Cmd          []string          `json:"cmd,omitempty"`
+	WorkingDir   string            `json:"workingDir,omitempty"`
+	ExposedPorts []string          `json:"exposedPorts,omitempty"` // e.g. "80/tcp", sorted
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// IsEmpty returns true if the config sets nothing worth displaying
+func (c *ImageConfig) IsEmpty() bool {
+	return c == nil || (c.User == "" && len(c.Env) == 0 && len(c.Entrypoint) == 0 && len(c.Cmd) == 0 &&
+		c.WorkingDir == "" && len(c.ExposedPorts) == 0 && len(c.Labels) == 0)
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// SigningKeyID identifies the key that made an armored commit signature: the long key ID
+// (16 uppercase hex digits, as GitHub lists GPG keys) for PGP signatures, or the
+// "SHA256:..." fingerprint for SSH signatures. It returns "" for unsigned commits, S/MIME
+// signatures and anything it can't parse.
+func SigningKeyID(signature string) string {
+	switch {
+	case strings.Contains(signature, "-----BEGIN PGP SIGNATURE-----"):
+		data, ok := dearmor(signature)
+		if !ok {
+			return ""
+		}
+		return pgpIssuerKeyID(data)
+	case strings.Contains(signature, "-----BEGIN SSH SIGNATURE-----"):
+		data, ok := dearmor(signature)
+		if !ok {
+			return ""
+		}
+		return sshSignatureFingerprint(data)
+	}
+	return ""
+}
+
+// dearmor decodes the base64 body of an ASCII-armored block, skipping armor headers and the
+// PGP checksum line
+func dearmor(armored string) ([]byte, bool) {
+	var body strings.Builder
+	inBody := false
+	for _, line := range strings.Split(armored, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "-----BEGIN "):
+			inBody = true
+		case strings.HasPrefix(line, "-----END "):
+			inBody = false
+		case !inBody, line == "", strings.Contains(line, ": "), strings.HasPrefix(line, "="):
+			// Outside the block, armor headers ("Version: ...") and the checksum
+		default:
+			body.WriteString(line)
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(body.String())
+	return data, err == nil && len(data) > 0
+}
+
+// pgpIssuerKeyID reads the issuer of the first signature packet of a binary OpenPGP message
+func pgpIssuerKeyID(data []byte) string {
+	body, ok := pgpSignaturePacket(data)
+	if !ok || len(body) == 0 {
+		return ""
+	}
+
+	switch body[0] {
+	case 3:
+		// version, hashed length (5), type, creation time (4), key ID (8)
+		if len(body) < 15 {
+			return ""
+		}
+		return fmt.Sprintf("%X", body[7:15])
+	case 4:
+		// version, type, public key algorithm, hash algorithm, then hashed and unhashed
+		// subpacket areas; the issuer may be in either
+		pos := 4
+		for area := 0; area < 2; area++ {
+			if pos+2 > len(body) {
+				return ""
+			}
+			n := int(binary.BigEndian.Uint16(body[pos:]))
+			pos += 2
+			if pos+n > len(body) {
+				return ""
+			}
+			if id := pgpSubpacketIssuer(body[pos : pos+n]); id != "" {
+				return id
+			}
+			pos += n
+		}
+	}
+	return ""
+}
+
+// pgpSignaturePacket returns the body of the first packet if it is a signature packet (tag 2)
+func pgpSignaturePacket(data []byte) ([]byte, bool) {
+	if len(data) < 2 || data[0]&0x80 == 0 {
+		return nil, false
+	}
+
+	var tag, length, pos int
+	if data[0]&0x40 != 0 {
+		// New format: tag in the low six bits, then a one, two or five octet length
+		tag = int(data[0] & 0x3F)
+		switch first := int(data[1]); {
+		case first < 192:
+			length, pos = first, 2
+		case first < 224:
+			if len(data) < 3 {
+				return nil, false
+			}
+			length, pos = (first-192)<<8+int(data[2])+192, 3
+		case first == 255:
+			if len(data) < 6 {
+				return nil, false
+			}
+			length, pos = int(binary.BigEndian.Uint32(data[2:])), 6
+		default:
+			return nil, false // partial body lengths aren't used for signatures
+		}
+	} else {
+		// Old format: tag in bits 5-2, length type in bits 1-0
+		tag = int(data[0]>>2) & 0x0F
+		switch data[0] & 0x03 {
+		case 0:
+			length, pos = int(data[1]), 2
+		case 1:
+			if len(data) < 3 {
+				return nil, false
+			}
+			length, pos = int(binary.BigEndian.Uint16(data[1:])), 3
+		case 2:
+			if len(data) < 5 {
+				return nil, false
+			}
+			length, pos = int(binary.BigEndian.Uint32(data[1:])), 5
+		default:
+			length, pos = len(data)-1, 1
+		}
+	}
+
+	if tag != 2 || length < 0 || pos+length > len(data) {
+		return nil, false
+	}
+	return data[pos : pos+length], true
+}
+
+// pgpSubpacketIssuer finds an issuer (type 16) or issuer fingerprint (type 33) subpacket and
+// returns the long key ID
+func pgpSubpacketIssuer(area []byte) string {
+	for pos := 0; pos < len(area); {
+		var n int
+		switch first := int(area[pos]); {
+		case first < 192:
+			n, pos = first, pos+1
+		case first < 255:
+			if pos+1 >= len(area) {
+				return ""
+			}
+			n, pos = (first-192)<<8+int(area[pos+1])+192, pos+2
+		default:
+			if pos+5 > len(area) {
+				return ""
+			}
+			n, pos = int(binary.BigEndian.Uint32(area[pos+1:])), pos+5
+		}
+		if n == 0 || pos+n > len(area) {
+			return ""
+		}
+
+		sub := area[pos : pos+n]
+		switch sub[0] & 0x7F {
+		case 16:
+			if len(sub) == 9 {
+				return fmt.Sprintf("%X", sub[1:9])
+			}
+		case 33:
+			// Key version, then a v4 fingerprint whose last 8 octets are the key ID
+			if len(sub) == 22 && sub[1] == 4 {
+				return fmt.Sprintf("%X", sub[14:22])
+			}
+		}
+		pos += n
+	}
+	return ""
+}
+
+// sshSignatureFingerprint returns the SHA256 fingerprint of the public key in an SSHSIG blob,
+// in the format ssh-keygen -l prints
+func sshSignatureFingerprint(data []byte) string {
+	const magic = "SSHSIG"
+	if len(data) < len(magic)+8 || string(data[:len(magic)]) != magic {
+		return ""
+	}
+	pos := len(magic) + 4 // skip the version
+	n := int(binary.BigEndian.Uint32(data[pos:]))
+	pos += 4
+	if n <= 0 || pos+n > len(data) {
+		return ""
+	}
+	sum := sha256.Sum256(data[pos : pos+n])
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
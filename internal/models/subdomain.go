@@ -1,18 +1,78 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
 
 // Subdomain represents a discovered subdomain record
 type Subdomain struct {
 	ID           int64
 	Domain       string    // Parent/root domain
 	Subdomain    string    // Full hostname (e.g., "api.example.com")
-	Source       string    // "virustotal", "crtsh", "import"
+	Source       string    // "virustotal", "crtsh", "securitytrails", "censys", "import", "amass", "subfinder", "rdns"
+	Sources      string    // Comma-separated sources that reported it, first discoverer first
 	CNAMEs       string    // Comma-separated CNAMEs
 	AltNames     string    // Comma-separated alt names from certificate
 	CertExpired  bool      // Certificate is expired
+	IsWildcard   bool      // Only seen as a "*." certificate name
 	CDXIndexed   bool      // Has been processed via Wayback CDX
+	ResolvedIPs  string    // Comma-separated IPs from the last DNS lookup
+	Resolves     bool      // Last DNS lookup returned at least one address
+	PTRNames     string    // Comma-separated reverse DNS names of ResolvedIPs
+	HTTPStatus   int       // Final status code over http:// (0 = no response)
+	HTTPSStatus  int       // Final status code over https:// (0 = no response)
+	ServerHeader string    // Server header from the final probe response
+	FinalURL     string    // URL reached after following redirects
 	DiscoveredAt time.Time // When the subdomain was discovered
+
+	// Certificate served live over https://, captured while probing (empty when not probed)
+	TLSSubject      string    // Subject common name
+	TLSSANs         string    // Comma-separated DNS subject alternative names
+	TLSIssuer       string    // Issuer common name
+	TLSNotBefore    time.Time // Start of the validity period
+	TLSNotAfter     time.Time // End of the validity period
+	TLSHostMismatch bool      // Certificate does not cover the subdomain's hostname
+}
+
+// HasTLSCert reports whether probing captured a certificate for the subdomain
+func (s Subdomain) HasTLSCert() bool {
+	return !s.TLSNotAfter.IsZero()
+}
+
+// HasSource reports whether source is one of the sources that reported the subdomain
+func (s Subdomain) HasSource(source string) bool {
+	for _, src := range strings.Split(s.Sources, ",") {
+		if src == source {
+			return true
+		}
+	}
+	return s.Source == source
+}
+
+// Networks returns the distinct networks of the resolved IPs - the /24 for IPv4 and the
+// /48 for IPv6 - so hosts sharing infrastructure can be grouped without an ASN lookup
+func (s Subdomain) Networks() []string {
+	var networks []string
+	seen := make(map[string]bool)
+	for _, raw := range strings.Split(s.ResolvedIPs, ",") {
+		ip := net.ParseIP(strings.TrimSpace(raw))
+		if ip == nil {
+			continue
+		}
+		bits := 48
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 24
+		}
+		network := (&net.IPNet{IP: ip.Mask(net.CIDRMask(bits, len(ip)*8)), Mask: net.CIDRMask(bits, len(ip)*8)}).String()
+		if !seen[network] {
+			seen[network] = true
+			networks = append(networks, network)
+		}
+	}
+	return networks
 }
 
 // TargetDomain represents a domain being tracked for subdomain enumeration
@@ -23,26 +83,110 @@ type TargetDomain struct {
 	CrtshEnumerated bool      // Has been enumerated via crt.sh
 	AddedAt         time.Time // When the domain was added
 	SubdomainCount  int       // Count of discovered subdomains (populated by join query)
+
+	// Per-source counts (populated by join query)
+	VTCount             int
+	CrtshCount          int
+	SecurityTrailsCount int
+	CensysCount         int
+	ImportCount         int
+}
+
+// EnumeratedSummary lists the sources the domain has been fully enumerated with,
+// e.g. "[done: VT, crt.sh]", or "" when none
+func (d TargetDomain) EnumeratedSummary() string {
+	var done []string
+	if d.VTEnumerated {
+		done = append(done, "VT")
+	}
+	if d.CrtshEnumerated {
+		done = append(done, "crt.sh")
+	}
+	if len(done) == 0 {
+		return ""
+	}
+	return "[done: " + strings.Join(done, ", ") + "]"
+}
+
+// CountSummary formats the subdomain count with its per-source breakdown
+// e.g., "123 (VT:80 crt:40 imp:3)"; sources with no records are omitted
+func (d TargetDomain) CountSummary() string {
+	var parts []string
+	if d.VTCount > 0 {
+		parts = append(parts, fmt.Sprintf("VT:%d", d.VTCount))
+	}
+	if d.CrtshCount > 0 {
+		parts = append(parts, fmt.Sprintf("crt:%d", d.CrtshCount))
+	}
+	if d.SecurityTrailsCount > 0 {
+		parts = append(parts, fmt.Sprintf("st:%d", d.SecurityTrailsCount))
+	}
+	if d.CensysCount > 0 {
+		parts = append(parts, fmt.Sprintf("cen:%d", d.CensysCount))
+	}
+	if d.ImportCount > 0 {
+		parts = append(parts, fmt.Sprintf("imp:%d", d.ImportCount))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d", d.SubdomainCount)
+	}
+	return fmt.Sprintf("%d (%s)", d.SubdomainCount, strings.Join(parts, " "))
+}
+
+// SubdomainSnapshot describes a saved set of a domain's subdomains
+type SubdomainSnapshot struct {
+	Domain    string
+	Label     string
+	Count     int // Subdomains in the set
+	CreatedAt time.Time
+}
+
+// SubdomainDiff compares a domain's current subdomains with a snapshot
+type SubdomainDiff struct {
+	Snapshot SubdomainSnapshot
+	Added    []string // Stored now but not in the snapshot
+	Removed  []string // In the snapshot but no longer stored
+}
+
+// Unchanged reports whether the subdomains match the snapshot exactly
+func (d *SubdomainDiff) Unchanged() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
 }
 
 // SubdomainStats holds statistics for a domain's subdomains
 type SubdomainStats struct {
-	Total        int
-	VTCount      int
-	CrtshCount   int
-	ImportCount  int
-	CDXCount     int
-	ExpiredCount int
+	Total               int
+	VTCount             int
+	CrtshCount          int
+	SecurityTrailsCount int
+	CensysCount         int
+	ImportCount         int
+	CDXCount            int
+	ExpiredCount        int
+}
+
+// Summary formats the totals like TargetDomain.CountSummary, e.g. "123 (VT:80 crt:40 imp:3)"
+func (s SubdomainStats) Summary() string {
+	return TargetDomain{
+		SubdomainCount:      s.Total,
+		VTCount:             s.VTCount,
+		CrtshCount:          s.CrtshCount,
+		SecurityTrailsCount: s.SecurityTrailsCount,
+		CensysCount:         s.CensysCount,
+		ImportCount:         s.ImportCount,
+	}.CountSummary()
 }
 
 // SubdomainFilter holds filter criteria for querying subdomains
 type SubdomainFilter struct {
 	Domain     string
 	SearchText string // Filter by subdomain substring
-	Source     string // Filter by source ("virustotal", "crtsh", "import", or "" for all)
+	Source     string // Filter by source ("virustotal", "crtsh", "securitytrails", "censys", "import", "rdns", or "" for all)
 	CDXIndexed int    // -1 = all, 0 = not indexed, 1 = indexed
 	Limit      int
 	Offset     int
+
+	DiscoveredSince time.Time // Only subdomains discovered at or after this time (zero = all)
 }
 
 // VirusTotalSubdomainResponse represents the VT API response for subdomains
@@ -60,6 +204,42 @@ type VirusTotalSubdomainResponse struct {
 	} `json:"meta"`
 }
 
+// SecurityTrailsSubdomainResponse represents the SecurityTrails API response for subdomains
+// Subdomains holds bare labels (e.g., "api"), not fully-qualified names
+type SecurityTrailsSubdomainResponse struct {
+	Endpoint       string   `json:"endpoint"`
+	Subdomains     []string `json:"subdomains"`
+	SubdomainCount int      `json:"subdomain_count"`
+	Meta           struct {
+		LimitReached bool `json:"limit_reached"`
+	} `json:"meta"`
+}
+
+// CensysCertificateSearchResponse represents a Censys Search v2 certificates search page
+// Links.Next is the cursor for the following page (empty on the last page)
+type CensysCertificateSearchResponse struct {
+	Code   int    `json:"code"`
+	Status string `json:"status"`
+	Result struct {
+		Total int `json:"total"`
+		Hits  []struct {
+			FingerprintSHA256 string   `json:"fingerprint_sha256"`
+			Names             []string `json:"names"`
+			Parsed            struct {
+				Extensions struct {
+					SubjectAltName struct {
+						DNSNames []string `json:"dns_names"`
+					} `json:"subject_alt_name"`
+				} `json:"extensions"`
+			} `json:"parsed"`
+		} `json:"hits"`
+		Links struct {
+			Next string `json:"next"`
+			Prev string `json:"prev"`
+		} `json:"links"`
+	} `json:"result"`
+}
+
 // CrtshEntry represents a single entry from crt.sh JSON response
 type CrtshEntry struct {
 	IssuerCAID        int    `json:"issuer_ca_id"`
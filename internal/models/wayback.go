@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"net/url"
+	"strings"
+	"time"
+)
 
 // CDXRecord represents a Wayback Machine CDX record
 type CDXRecord struct {
@@ -11,6 +15,7 @@ type CDXRecord struct {
 	StatusCode *int    // nullable - some records don't have status
 	MimeType   *string // nullable - some records don't have mime type
 	Tags       string
+	Captures   int // Captures of this URL seen when counting captures; 0 when unknown
 	FetchedAt  time.Time
 }
 
@@ -27,8 +32,10 @@ type WaybackFilter struct {
 	MimeType   string // Filter by MIME type (e.g., "text/html", "image/")
 	SearchText string // Filter by URL substring
 	Tags       string // Filter by tags (e.g., "#important" or "review")
-	Limit      int
-	Offset     int
+	// SortByCaptures orders the most heavily archived URLs first
+	SortByCaptures bool
+	Limit          int
+	Offset         int
 }
 
 // WaybackDomainStats represents statistics for a cached domain
@@ -36,3 +43,24 @@ type WaybackDomainStats struct {
 	Domain      string
 	RecordCount int
 }
+
+// NormalizeCDXURL returns the key captures of the same URL are collapsed and stored under
+// Scheme and host are lowercased, default ports and fragments dropped, and a trailing slash ignored.
+func NormalizeCDXURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(raw)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	key := scheme + "://" + host + strings.TrimSuffix(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key
+}
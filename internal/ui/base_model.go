@@ -4,6 +4,9 @@ package ui
 // Embed these helpers in models to reduce boilerplate for Init, Update, and View patterns.
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -161,6 +164,75 @@ func HandleNavigationKeys(key string, cursor, maxItems int) int {
 	return cursor
 }
 
+// maxRowJumpDigits bounds the typed row number so it can't overflow
+const maxRowJumpDigits = 7
+
+// RowJump is a ':' prompt that moves a table cursor to a typed row number.
+// Embed it in a table model and pass keys through HandleKey before other key handling.
+//
+// Example:
+//
+//	case tea.KeyMsg:
+//	    if m.jump.HandleKey(msg.String(), &m.table) {
+//	        return m, nil
+//	    }
+type RowJump struct {
+	Active bool
+	Digits string
+}
+
+// HandleKey opens the prompt on ':' and, while it is open, consumes digits,
+// backspace, enter (jump) and esc (cancel). Returns true when the key was consumed.
+func (j *RowJump) HandleKey(key string, t *table.Model) bool {
+	if !j.Active {
+		if key != ":" || len(t.Rows()) == 0 {
+			return false
+		}
+		j.Active = true
+		j.Digits = ""
+		return true
+	}
+
+	switch key {
+	case "esc":
+		j.Active = false
+	case "enter":
+		if row, err := strconv.Atoi(j.Digits); err == nil {
+			JumpToRow(t, row)
+		}
+		j.Active = false
+	case "backspace":
+		if len(j.Digits) > 0 {
+			j.Digits = j.Digits[:len(j.Digits)-1]
+		}
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(j.Digits) < maxRowJumpDigits {
+			j.Digits += key
+		}
+	}
+	return true
+}
+
+// Prompt returns the help line shown while the prompt is open
+func (j RowJump) Prompt(totalRows int) string {
+	return fmt.Sprintf("Go to row: %s_ (1-%d) | enter: jump | esc: cancel", j.Digits, totalRows)
+}
+
+// JumpToRow moves the table cursor to a 1-based row number, clamped to the table's rows.
+func JumpToRow(t *table.Model, row int) {
+	total := len(t.Rows())
+	if total == 0 {
+		return
+	}
+	if row < 1 {
+		row = 1
+	}
+	if row > total {
+		row = total
+	}
+	t.SetCursor(row - 1)
+}
+
 // =============================================================================
 // Selection State Helpers
 // =============================================================================
@@ -130,6 +130,7 @@ func WaybackColumns() []ColumnSpec {
 		{Title: "Timestamp", FixedWidth: 20},
 		{Title: "Status", FixedWidth: 10},
 		{Title: "MIME Type", FixedWidth: 30},
+		{Title: "Captures", FixedWidth: 9},
 	}
 }
 
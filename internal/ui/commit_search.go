@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+)
+
+// commitSearchAuthorWidth is the width of the author column in the result lines
+const commitSearchAuthorWidth = 16
+
+// commitSearchModel searches the commit messages of one repository, listing the SHA, author
+// and matching snippet of each hit. Enter opens the commit on GitHub.
+type commitSearchModel struct {
+	database  *db.DB
+	owner     string
+	repo      string
+	layout    Layout
+	textInput textinput.Model
+	inputMode bool
+	enabled   bool   // Commit message indexing is on for the project
+	keyword   string // Keyword of the results shown
+	results   []db.CommitMessageResult
+	cursor    int
+	statusMsg string
+	quitting  bool
+}
+
+func newCommitSearchModel(database *db.DB, owner, repo string) commitSearchModel {
+	ti := textinput.New()
+	ti.Placeholder = "Keyword (ticket, hostname, password...)"
+	ti.Focus()
+	ti.CharLimit = 200
+
+	m := commitSearchModel{
+		database:  database,
+		owner:     owner,
+		repo:      repo,
+		layout:    DefaultLayout(),
+		textInput: ti,
+		inputMode: true,
+	}
+	enabled, err := database.CommitMessageSearchEnabled()
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Settings error: %v", err)
+	}
+	m.enabled = enabled
+	return m
+}
+
+func (m commitSearchModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m commitSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.layout = NewLayout(msg.Width, msg.Height)
+		m.textInput.Width = m.layout.InnerWidth - 12
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+e" {
+			m.toggleIndexing()
+			return m, nil
+		}
+		if m.inputMode {
+			return m.handleInputKeys(msg)
+		}
+		return m.handleResultKeys(msg)
+	}
+	return m, nil
+}
+
+// toggleIndexing turns commit message indexing on (indexing the stored messages) or off
+func (m *commitSearchModel) toggleIndexing() {
+	if err := m.database.SetCommitMessageSearch(!m.enabled); err != nil {
+		m.statusMsg = fmt.Sprintf("Indexing error: %v", err)
+		return
+	}
+	m.enabled = !m.enabled
+	m.keyword, m.results, m.cursor = "", nil, 0
+	if m.enabled {
+		m.statusMsg = "Commit messages indexed - new commits are indexed as they are fetched"
+	} else {
+		m.statusMsg = "Commit message index dropped"
+	}
+}
+
+func (m commitSearchModel) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		if m.keyword == "" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		// Back to the previous results
+		m.inputMode = false
+		m.textInput.SetValue(m.keyword)
+		m.textInput.Blur()
+		return m, nil
+	case "enter":
+		keyword := strings.TrimSpace(m.textInput.Value())
+		if keyword == "" {
+			return m, nil
+		}
+		m.search(keyword)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// search runs keyword against the repository's commit messages and shows the hits
+func (m *commitSearchModel) search(keyword string) {
+	results, err := m.database.SearchCommitMessages(m.owner, m.repo, keyword)
+	if errors.Is(err, db.ErrCommitMessageSearchOff) {
+		m.statusMsg = "Commit message search is off for this project - press Ctrl+E to index messages"
+		return
+	}
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Search error: %v", err)
+		return
+	}
+	m.keyword = keyword
+	m.results = results
+	m.cursor = 0
+	m.inputMode = false
+	m.textInput.Blur()
+	m.statusMsg = fmt.Sprintf("Found %d commits for '%s'", len(results), keyword)
+	if len(results) == db.CommitMessageSearchLimit {
+		m.statusMsg += fmt.Sprintf(" (capped at %d - refine the keyword to see more)", db.CommitMessageSearchLimit)
+	}
+}
+
+func (m commitSearchModel) handleResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "/":
+		m.inputMode = true
+		m.textInput.SetValue("")
+		m.textInput.Focus()
+		return m, textinput.Blink
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.results)-1 {
+			m.cursor++
+		}
+	case "pgup":
+		m.cursor = max(m.cursor-m.visibleLines(), 0)
+	case "pgdown":
+		m.cursor = max(min(m.cursor+m.visibleLines(), len(m.results)-1), 0)
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.results)-1, 0)
+	case "enter":
+		if m.cursor < len(m.results) {
+			m.statusMsg = openOrCopyURL("commit", m.commitURL(m.results[m.cursor]))
+		}
+	case "y":
+		if m.cursor < len(m.results) {
+			m.statusMsg = copyURL("commit URL", m.commitURL(m.results[m.cursor]))
+		}
+	}
+	return m, nil
+}
+
+// commitURL is the commit's GitHub page, built from the repo when none was stored
+func (m commitSearchModel) commitURL(r db.CommitMessageResult) string {
+	if r.HTMLURL != "" {
+		return r.HTMLURL
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/commit/%s", m.owner, m.repo, r.SHA)
+}
+
+// visibleLines is how many result lines fit below the header and search box
+func (m commitSearchModel) visibleLines() int {
+	return max(m.layout.ViewportHeight-6-8, 5)
+}
+
+func (m commitSearchModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(ViewHeaderWithSubtitle("Commit Message Search", fmt.Sprintf("%s/%s", m.owner, m.repo), m.layout.InnerWidth))
+	b.WriteString("Search: ")
+	if m.inputMode {
+		b.WriteString(m.textInput.View())
+	} else {
+		b.WriteString(m.keyword)
+	}
+	b.WriteString("\n")
+	if m.statusMsg != "" {
+		b.WriteString(RenderNormal(m.statusMsg))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case !m.enabled:
+		b.WriteString(RenderDim("Commit messages are not indexed for this project. Press Ctrl+E to index them (grows the database)."))
+	case m.keyword == "":
+		b.WriteString(RenderDim("Type a keyword and press Enter."))
+	case len(m.results) == 0:
+		b.WriteString(RenderNormal("No matching commits. Press / to search again."))
+	default:
+		b.WriteString(m.renderResults())
+	}
+
+	indexHelp := "Ctrl+E: index messages"
+	if m.enabled {
+		indexHelp = "Ctrl+E: drop index"
+	}
+	helpText := "↑/↓: navigate | Enter: open commit | y: copy URL | /: new search | " + indexHelp + " | Esc: back"
+	if m.inputMode {
+		helpText = "Enter: search | " + indexHelp + " | Esc: back"
+	}
+	return TwoBoxView(b.String(), helpText, m.layout)
+}
+
+// renderResults lists one line per commit, scrolled to keep the cursor visible
+func (m commitSearchModel) renderResults() string {
+	lines := make([]string, len(m.results))
+	for i, r := range m.results {
+		author := r.AuthorLogin
+		if author == "" {
+			author = r.AuthorName
+		}
+		sha := r.SHA
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		author = padRight(truncateToWidth(author, commitSearchAuthorWidth), commitSearchAuthorWidth)
+		label := truncateToWidth(fmt.Sprintf("%s  %s  %s  %s", sha, r.AuthorDate.Format("2006-01-02"), author, r.Snippet), m.layout.InnerWidth-4)
+		if i == m.cursor {
+			lines[i] = RenderSelectedWidth("> "+label, m.layout.InnerWidth)
+		} else {
+			lines[i] = RenderNormal("  " + label)
+		}
+	}
+
+	height := m.visibleLines()
+	start := tableScrollStart(m.cursor, height, len(lines))
+	end := min(start+height, len(lines))
+	return strings.Join(lines[start:end], "\n")
+}
+
+// RunCommitMessageSearch searches the commit messages of a repository. Messages are only
+// searchable once indexing is turned on for the project, which the view offers to do.
+func RunCommitMessageSearch(database *db.DB, owner, repo string) error {
+	if database == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	_, err := tea.NewProgram(newCommitSearchModel(database, owner, repo), tea.WithAltScreen()).Run()
+	return err
+}
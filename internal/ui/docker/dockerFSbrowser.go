@@ -230,7 +230,7 @@ func (m fsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.statusMsg = fmt.Sprintf("Download failed: %v", msg.err)
 		} else {
-			m.statusMsg = fmt.Sprintf("Downloaded to: %s", msg.path)
+			m.statusMsg = fmt.Sprintf("Downloaded and verified to: %s", msg.path)
 		}
 		return m, nil
 
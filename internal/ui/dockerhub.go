@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
@@ -23,6 +26,13 @@ type DockerHubSearchModel struct {
 	textInput         textinput.Model
 	spinner           spinner.Model
 	results           *api.DockerHubSearchResponse
+	pages             map[int]*api.DockerHubSearchResponse // pages fetched for the current query
+	pageCachedAt      map[int]time.Time                    // when cached pages were fetched (absent = live)
+	offline           bool                                 // prefer cached pages of any age (browsing cached searches)
+	cachedListMode    bool                                 // showing the cached searches list
+	cachedSearches    []db.CachedDockerSearch
+	cachedTable       table.Model
+	cacheTTL          time.Duration
 	filteredResults   []api.DockerHubSearchResult // results after applying type filter
 	query             string
 	page              int
@@ -49,10 +59,19 @@ const (
 
 // DockerHubSearchMsg is sent when search results are ready
 type DockerHubSearchMsg struct {
-	Results *api.DockerHubSearchResponse
-	Err     error
+	Page     int
+	Results  *api.DockerHubSearchResponse
+	CachedAt time.Time // when the results were fetched if served from the cache, zero if live
+	Err      error
 }
 
+// dockerSearchFreshFor is how long a cached search page is used before Docker Hub is queried again
+// Older pages are kept until the cache TTL evicts them and still serve offline browsing.
+const dockerSearchFreshFor = 24 * time.Hour
+
+// dockerSearchTTLPresets are the cache TTLs cycled with "t" in the cached searches list
+var dockerSearchTTLPresets = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour, 90 * 24 * time.Hour}
+
 // NewDockerHubSearchModel creates a new Docker Hub search TUI
 func NewDockerHubSearchModel(logger *log.Logger, database *db.DB) DockerHubSearchModel {
 	// Create text input for search
@@ -90,6 +109,10 @@ func NewDockerHubSearchModel(logger *log.Logger, database *db.DB) DockerHubSearc
 		textInput:    ti,
 		spinner:      spinnerModel,
 		page:         1,
+		pages:        make(map[int]*api.DockerHubSearchResponse),
+		pageCachedAt: make(map[int]time.Time),
+		cachedTable:  newCachedSearchesTable(layout),
+		cacheTTL:     db.DefaultDockerSearchCacheTTL,
 		inputMode:    true,
 		cachedImages: make(map[string]int),
 		filterType:   FilterAll,
@@ -117,12 +140,14 @@ func (m DockerHubSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.results != nil {
 			m.updateTable()
 		}
+		m.cachedTable.SetHeight(m.layout.TableHeight)
+		m.cachedTable.SetColumns(CalculateColumns(CachedDockerSearchColumns(), m.layout.TableWidth))
 
 		// Trigger pending search after layout is initialized with correct dimensions
 		if m.pendingSearch && !m.layoutInitialized {
 			m.layoutInitialized = true
 			m.pendingSearch = false // Clear flag so it doesn't re-trigger
-			return m, m.doSearch()
+			return m, m.doSearch(m.page, false)
 		}
 		m.layoutInitialized = true
 		return m, nil
@@ -135,25 +160,36 @@ func (m DockerHubSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DockerHubSearchMsg:
 		m.searching = false
 		if msg.Err != nil {
+			// Stay on the current page so n/p can retry
 			m.err = msg.Err
 			return m, nil
 		}
-		m.results = msg.Results
-		m.updateTable()
+		m.err = nil
+		m.pages[msg.Page] = msg.Results
+		if msg.CachedAt.IsZero() {
+			delete(m.pageCachedAt, msg.Page)
+		} else {
+			m.pageCachedAt[msg.Page] = msg.CachedAt
+		}
+		m.showPage(msg.Page)
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.cachedListMode {
+			return m.handleCachedListKeys(msg)
+		}
+
 		// Handle input mode
 		if m.inputMode {
 			switch msg.String() {
 			case "enter":
 				if m.textInput.Value() != "" {
-					m.query = m.textInput.Value()
-					m.page = 1
-					m.inputMode = false
-					m.searching = true
-					return m, m.doSearch()
+					m.offline = false
+					return m, m.startSearch(m.textInput.Value())
 				}
+			case "tab":
+				m.showCachedSearches()
+				return m, nil
 			case "esc":
 				m.quitting = true
 				m.returnToMain = true
@@ -180,20 +216,27 @@ func (m DockerHubSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.textInput.Focus()
 			return m, textinput.Blink
 
-		case "n", "right":
-			// Next page
-			if m.results != nil && m.page*25 < m.results.Total {
-				m.page++
+		case "r":
+			// Re-fetch the current page from Docker Hub, bypassing the cache
+			if !m.searching && m.query != "" {
 				m.searching = true
-				return m, m.doSearch()
+				return m, m.doSearch(m.page, true)
+			}
+
+		case "c":
+			m.showCachedSearches()
+			return m, nil
+
+		case "n", "right":
+			// Next page - disabled on the last page
+			if !m.searching && m.results != nil && m.results.HasNextPage() {
+				return m, m.goToPage(m.page + 1)
 			}
 
 		case "p", "left":
 			// Previous page
-			if m.page > 1 {
-				m.page--
-				m.searching = true
-				return m, m.doSearch()
+			if !m.searching && m.page > 1 {
+				return m, m.goToPage(m.page - 1)
 			}
 
 		case "up", "k":
@@ -277,15 +320,24 @@ func (m DockerHubSearchModel) View() string {
 			Divider().
 			Spacing(2).
 			Text(" Search: " + m.textInput.View()).
-			Help("Enter: search | Esc: back to main").
+			Help("Enter: search | Tab: cached searches | Esc: back to main").
 			Build()
 	}
 
+	if m.cachedListMode {
+		return m.renderCachedList()
+	}
+
 	// Table mode - build query info
 	queryInfo := fmt.Sprintf(" Query: %s", m.query)
 	if m.results != nil {
-		totalPages := (m.results.Total + 24) / 25 // 25 results per page, round up
-		queryInfo += fmt.Sprintf("  |  Page %d of %d  |  Total: %d results", m.page, totalPages, m.results.Total)
+		queryInfo += fmt.Sprintf("  |  Page %d of %d  |  Total: %d results", m.page, m.results.TotalPages(), m.results.Total)
+		if !m.results.HasNextPage() {
+			queryInfo += " (last page)"
+		}
+		if cachedAt, ok := m.pageCachedAt[m.page]; ok {
+			queryInfo += fmt.Sprintf("  |  cached %s ago", formatCacheAge(time.Since(cachedAt)))
+		}
 	}
 
 	// Add filter status
@@ -322,15 +374,194 @@ func (m DockerHubSearchModel) View() string {
 		builder.Table(m.table)
 	}
 
-	return builder.Help("Enter: inspect | /: search | n: next | p: prev | f: filter (1-4) | Esc: back").
+	// Only offer the page keys that go somewhere
+	help := "Enter: inspect | /: search"
+	if m.results != nil && m.results.HasNextPage() {
+		help += " | n: next"
+	}
+	if m.page > 1 {
+		help += " | p: prev"
+	}
+	return builder.Help(help + " | r: refresh | c: cached | f: filter (1-4) | Esc: back").
 		Build()
 }
 
-// doSearch performs the search asynchronously
-func (m DockerHubSearchModel) doSearch() tea.Cmd {
+// goToPage shows a page of the current query, fetching it unless it was already loaded
+func (m *DockerHubSearchModel) goToPage(page int) tea.Cmd {
+	if _, ok := m.pages[page]; ok {
+		m.err = nil
+		m.showPage(page)
+		return nil
+	}
+	m.searching = true
+	return m.doSearch(page, false)
+}
+
+// startSearch begins a new query from its first page
+func (m *DockerHubSearchModel) startSearch(query string) tea.Cmd {
+	m.query = query
+	m.page = 1
+	m.pages = make(map[int]*api.DockerHubSearchResponse)
+	m.pageCachedAt = make(map[int]time.Time)
+	m.results = nil
+	m.err = nil
+	m.inputMode = false
+	m.searching = true
+	return m.doSearch(1, false)
+}
+
+// showPage makes a fetched page current and rebuilds the table from its first row
+func (m *DockerHubSearchModel) showPage(page int) {
+	m.page = page
+	m.results = m.pages[page]
+	m.updateTable()
+	m.table.SetCursor(0)
+}
+
+// doSearch fetches a page of results for the current query asynchronously
+// A cached page younger than dockerSearchFreshFor (or of any age when browsing offline) is used
+// unless refresh is set; when Docker Hub can't be reached, a cached page of any age is used instead.
+func (m DockerHubSearchModel) doSearch(page int, refresh bool) tea.Cmd {
+	query, database, offline, client := m.query, m.database, m.offline, m.client
 	return func() tea.Msg {
-		results, err := m.client.Search(m.query, m.page)
-		return DockerHubSearchMsg{Results: results, Err: err}
+		var cached *db.DockerSearchPage
+		var cachedResults *api.DockerHubSearchResponse
+		if database != nil {
+			cached, _ = database.GetDockerSearch(query, page)
+			if cached != nil && json.Unmarshal([]byte(cached.Response), &cachedResults) != nil {
+				cached, cachedResults = nil, nil // Unreadable entry - treat as a miss
+			}
+		}
+		if cached != nil && !refresh && (offline || time.Since(cached.FetchedAt) < dockerSearchFreshFor) {
+			return DockerHubSearchMsg{Page: page, Results: cachedResults, CachedAt: cached.FetchedAt}
+		}
+
+		results, err := client.Search(query, page)
+		if err != nil {
+			if cached != nil {
+				return DockerHubSearchMsg{Page: page, Results: cachedResults, CachedAt: cached.FetchedAt}
+			}
+			return DockerHubSearchMsg{Page: page, Err: err}
+		}
+		if database != nil {
+			if data, err := json.Marshal(results); err == nil {
+				_ = database.SaveDockerSearch(query, page, string(data)) // Cache is best-effort
+			}
+		}
+		return DockerHubSearchMsg{Page: page, Results: results}
+	}
+}
+
+// CachedDockerSearchColumns returns column specs for the cached Docker Hub searches table.
+func CachedDockerSearchColumns() []ColumnSpec {
+	return []ColumnSpec{
+		{Title: "Query", FlexRatio: 100, MinWidth: 20},
+		{Title: "Pages", FixedWidth: 8},
+		{Title: "Fetched", FixedWidth: 14},
+	}
+}
+
+// newCachedSearchesTable creates the table listing cached Docker Hub searches
+func newCachedSearchesTable(layout Layout) table.Model {
+	t := table.New(
+		table.WithColumns(CalculateColumns(CachedDockerSearchColumns(), layout.TableWidth)),
+		table.WithRows([]table.Row{}),
+		table.WithFocused(true),
+		table.WithHeight(layout.TableHeight),
+	)
+	ApplyTableStyles(&t)
+	return t
+}
+
+// showCachedSearches switches to the cached searches list, reloading it from the database
+func (m *DockerHubSearchModel) showCachedSearches() {
+	m.cachedListMode = true
+	m.cachedSearches = nil
+	if m.database == nil {
+		m.cachedTable.SetRows(nil)
+		return
+	}
+	m.cacheTTL = m.database.GetDockerSearchCacheTTL()
+	m.cachedSearches, _ = m.database.GetCachedDockerSearches()
+
+	rows := make([]table.Row, len(m.cachedSearches))
+	for i, c := range m.cachedSearches {
+		rows[i] = table.Row{c.Query, fmt.Sprintf("%d", c.Pages), formatCacheAge(time.Since(c.FetchedAt)) + " ago"}
+	}
+	m.cachedTable.SetRows(rows)
+	m.cachedTable.SetCursor(0)
+}
+
+// handleCachedListKeys handles keys in the cached searches list
+func (m DockerHubSearchModel) handleCachedListKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.cachedListMode = false
+		if m.results == nil && !m.searching {
+			m.inputMode = true
+			m.textInput.Focus()
+		}
+		return m, nil
+
+	case "up", "k":
+		m.cachedTable.MoveUp(1)
+	case "down", "j":
+		m.cachedTable.MoveDown(1)
+
+	case "enter":
+		cursor := m.cachedTable.Cursor()
+		if cursor >= 0 && cursor < len(m.cachedSearches) {
+			m.cachedListMode = false
+			m.offline = true // Browse what was cached; "r" still refreshes a page
+			m.textInput.SetValue(m.cachedSearches[cursor].Query)
+			return m, m.startSearch(m.cachedSearches[cursor].Query)
+		}
+
+	case "t":
+		// Cycle the cache TTL and evict anything now past it
+		if m.database == nil {
+			return m, nil
+		}
+		next := dockerSearchTTLPresets[0]
+		for i, preset := range dockerSearchTTLPresets {
+			if preset == m.cacheTTL {
+				next = dockerSearchTTLPresets[(i+1)%len(dockerSearchTTLPresets)]
+			}
+		}
+		if err := m.database.SetDockerSearchCacheTTL(next); err == nil {
+			m.database.EvictDockerSearches(next)
+		}
+		m.showCachedSearches()
+	}
+	return m, nil
+}
+
+// renderCachedList renders the cached searches list
+func (m DockerHubSearchModel) renderCachedList() string {
+	builder := NewPageView(m.layout).
+		Title("Docker Hub Search - Cached Searches").
+		Divider().
+		Spacing(2).
+		QueryInfo(fmt.Sprintf(" %d cached searches  |  Kept for %s", len(m.cachedSearches), formatCacheAge(m.cacheTTL)))
+	if len(m.cachedSearches) == 0 {
+		builder.DimText(" No cached searches yet - results are cached as you search")
+	} else {
+		builder.Table(m.cachedTable)
+	}
+	return builder.Help("Enter: open offline | t: change TTL | Esc: back").Build()
+}
+
+// formatCacheAge renders a cache age or TTL compactly, e.g. "45s", "3h", "7d"
+func formatCacheAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
 	}
 }
 
@@ -462,10 +693,18 @@ func RunDockerHubSearch(logger *log.Logger, database *db.DB, initialQuery string
 	// Keep track of the last search state to restore after inspector
 	var lastQuery string
 	var lastResults *api.DockerHubSearchResponse
+	var lastPages map[int]*api.DockerHubSearchResponse
+	var lastPageCachedAt map[int]time.Time
+	var lastOffline bool
 	var lastPage int
 	// Track if this is the first iteration (for initial query)
 	firstIteration := true
 
+	// Drop cached searches older than the configured TTL
+	if database != nil {
+		database.EvictDockerSearches(database.GetDockerSearchCacheTTL())
+	}
+
 	for {
 		model := NewDockerHubSearchModel(logger, database)
 
@@ -481,6 +720,9 @@ func RunDockerHubSearch(logger *log.Logger, database *db.DB, initialQuery string
 			// Restore previous search state if we have it (after inspector returns)
 			model.query = lastQuery
 			model.results = lastResults
+			model.pages = lastPages
+			model.pageCachedAt = lastPageCachedAt
+			model.offline = lastOffline
 			model.page = lastPage
 			model.inputMode = false // Start in table mode with results
 			model.updateTable()     // Rebuild table rows from restored results
@@ -509,10 +751,13 @@ func RunDockerHubSearch(logger *log.Logger, database *db.DB, initialQuery string
 			// Save current search state before launching inspector
 			lastQuery = m.query
 			lastResults = m.results
+			lastPages = m.pages
+			lastPageCachedAt = m.pageCachedAt
+			lastOffline = m.offline
 			lastPage = m.page
 
 			// Prompt for tag
-			tag, err := PromptForTag(m.SelectedImage())
+			tag, err := PromptForTag(m.SelectedImage(), database)
 			if err != nil {
 				// User cancelled, go back to search with preserved state
 				continue
@@ -535,17 +780,18 @@ func RunDockerHubSearch(logger *log.Logger, database *db.DB, initialQuery string
 
 // PromptForDockerHubPath prompts the user to enter a Docker Hub repository path
 // Uses the generic RunInput for consistent UI
-func PromptForDockerHubPath() (string, error) {
+func PromptForDockerHubPath(database *db.DB) (string, error) {
 	value, cancelled, err := RunInput(InputConfig{
 		Title:       "Browse DockerHub Repository",
-		Subtitle:    "Examples: nginx, library/alpine, myuser/myapp",
+		Subtitle:    "Examples: nginx, library/alpine, myuser/myapp, ghcr.io/owner/image:tag\n" + registryAuthStatus(newRegistryClient(database)),
 		Placeholder: "user/container (e.g., nginx, library/alpine, myuser/myapp)",
 		HelpText:    "Enter: confirm | Esc: cancel",
 		Validator: func(s string) error {
 			if strings.TrimSpace(s) == "" {
 				return fmt.Errorf("path cannot be empty")
 			}
-			return nil
+			_, err := api.ParseImageRef(s)
+			return err
 		},
 	})
 	if err != nil {
@@ -561,7 +807,7 @@ func PromptForDockerHubPath() (string, error) {
 // If the repository doesn't exist or has no tags, displays a 404 error
 func RunBrowseDockerHubRepo(logger *log.Logger, database *db.DB) error {
 	// Prompt for the Docker Hub path
-	path, err := PromptForDockerHubPath()
+	path, err := PromptForDockerHubPath(database)
 	if err != nil {
 		if strings.Contains(err.Error(), "cancelled") {
 			return nil // User cancelled, return to menu
@@ -570,15 +816,19 @@ func RunBrowseDockerHubRepo(logger *log.Logger, database *db.DB) error {
 	}
 
 	// Normalize path - if no slash, treat as official image (library/name)
-	imageName := path
-	if !strings.Contains(path, "/") {
-		imageName = "library/" + path
+	imageName := strings.TrimSpace(path)
+	if !strings.Contains(imageName, "/") {
+		imageName = "library/" + imageName
 	}
 
 	// Try to fetch tags to validate the repository exists
-	client := api.NewRegistryClient()
+	client := newRegistryClient(database)
 	tags, err := client.ListTags(imageName)
 
+	if errors.Is(err, api.ErrRegistryAuth) {
+		PrintError(err.Error())
+		return nil
+	}
 	if err != nil || len(tags) == 0 {
 		// Repository not found or has no tags - show 404 error
 		show404Error(imageName)
@@ -586,7 +836,7 @@ func RunBrowseDockerHubRepo(logger *log.Logger, database *db.DB) error {
 	}
 
 	// Repository exists - proceed with tag selection and inspection
-	tag, err := PromptForTag(imageName)
+	tag, err := PromptForTag(imageName, database)
 	if err != nil {
 		if strings.Contains(err.Error(), "cancelled") || strings.Contains(err.Error(), "no tag selected") {
 			return nil
@@ -594,7 +844,11 @@ func RunBrowseDockerHubRepo(logger *log.Logger, database *db.DB) error {
 		return err
 	}
 
-	imageRef := imageName + ":" + tag
+	ref, err := api.ParseImageRef(imageName)
+	if err != nil {
+		return err
+	}
+	imageRef := ref.WithReference(tag)
 
 	// Run the layer inspector
 	if err := RunLayerInspectorWithDB(imageRef, database); err != nil {
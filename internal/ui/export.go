@@ -1,10 +1,11 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -41,11 +42,44 @@ func ExportTabToMarkdown(stats []models.ContributorStats, repoOwner, repoName st
 	sb.WriteString(fmt.Sprintf("**Total Commits:** %d\n", totalCommits))
 	sb.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
 
-	// Table header
-	sb.WriteString("| Rank | Name | GitHub Login | Email | Commits | % |\n")
-	sb.WriteString("|------|------|--------------|-------|---------|---|\n")
+	// Table
+	sb.WriteString(contributorMarkdownTable(stats, AllColumns()))
+
+	// Write to file
+	err := os.WriteFile(filename, []byte(sb.String()), 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write markdown file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// contributorMarkdownTable renders stats as a GitHub-flavored markdown table, ranked in the
+// given order, with the Rank and Commits columns plus the visible optional columns
+func contributorMarkdownTable(stats []models.ContributorStats, visible ColumnSet) string {
+	// Pipes would end a cell early
+	cell := strings.NewReplacer("|", "\\|").Replace
+
+	headers := []string{"Rank"}
+	for _, col := range []struct{ key, title string }{
+		{ColumnName, "Name"}, {ColumnLogin, "GitHub Login"}, {ColumnEmail, "Email"},
+	} {
+		if visible[col.key] {
+			headers = append(headers, col.title)
+		}
+	}
+	headers = append(headers, "Commits")
+	if visible[ColumnPercent] {
+		headers = append(headers, "%")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	for _, h := range headers {
+		sb.WriteString("|" + strings.Repeat("-", len(h)+2))
+	}
+	sb.WriteString("|\n")
 
-	// Table rows
 	for i, s := range stats {
 		login := s.GitHubLogin
 		if login == "" {
@@ -54,47 +88,193 @@ func ExportTabToMarkdown(stats []models.ContributorStats, repoOwner, repoName st
 			// Make GitHub login a clickable link
 			login = fmt.Sprintf("[%s](https://github.com/%s)", login, login)
 		}
-		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %d | %.1f%% |\n",
-			i+1, s.Name, login, s.Email, s.CommitCount, s.Percentage))
+		cells := []string{fmt.Sprintf("%d", i+1)}
+		if visible[ColumnName] {
+			cells = append(cells, cell(s.DisplayName()))
+		}
+		if visible[ColumnLogin] {
+			cells = append(cells, login)
+		}
+		if visible[ColumnEmail] {
+			cells = append(cells, cell(s.Email))
+		}
+		cells = append(cells, fmt.Sprintf("%d", s.CommitCount))
+		if visible[ColumnPercent] {
+			cells = append(cells, fmt.Sprintf("%.1f%%", s.Percentage))
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
 	}
+	return sb.String()
+}
 
-	// Write to file
-	err := os.WriteFile(filename, []byte(sb.String()), 0644)
+// tabExportJSON is the document written by ExportTabToJSON
+type tabExportJSON struct {
+	RepoOwner    string                 `json:"repo_owner"`
+	RepoName     string                 `json:"repo_name"`
+	TotalCommits int                    `json:"total_commits"`
+	Committers   int                    `json:"total_committers"`
+	GeneratedAt  string                 `json:"generated_at"`
+	Contributors []contributorExportRow `json:"contributors"`
+}
+
+// contributorExportRow is one committer row in a JSON tab export
+type contributorExportRow struct {
+	Rank        int     `json:"rank"`
+	Name        string  `json:"name"`
+	GitHubLogin string  `json:"github_login,omitempty"`
+	Email       string  `json:"email"`
+	Commits     int     `json:"commits"`
+	Percentage  float64 `json:"percentage"`
+	Tagged      bool    `json:"tagged"`
+	LinkGroup   int     `json:"link_group,omitempty"` // 0 = not linked
+	LinkLabel   string  `json:"link_label,omitempty"`
+	Source      string  `json:"source,omitempty"` // "co-author" for Co-authored-by trailers
+}
+
+// ExportTabToJSON exports the current stats (with tag, link and link label state) to a JSON file
+func ExportTabToJSON(stats []models.ContributorStats, tags map[string]bool, links map[string]int, linkLabels map[int]string, repoOwner, repoName string, totalCommits int) (string, error) {
+	timestamp := time.Now().Format("2006-01-02")
+	safeOwner := strings.ReplaceAll(repoOwner, "/", "-")
+	safeName := strings.ReplaceAll(repoName, "/", "-")
+	filename := fmt.Sprintf("%s-%s-%s.json", safeOwner, safeName, timestamp)
+
+	doc := tabExportJSON{
+		RepoOwner:    repoOwner,
+		RepoName:     repoName,
+		TotalCommits: totalCommits,
+		Committers:   len(stats),
+		GeneratedAt:  time.Now().Format(time.RFC3339),
+		Contributors: make([]contributorExportRow, 0, len(stats)),
+	}
+	for i, s := range stats {
+		doc.Contributors = append(doc.Contributors, contributorExportRow{
+			Rank:        i + 1,
+			Name:        s.Name,
+			GitHubLogin: s.GitHubLogin,
+			Email:       s.Email,
+			Commits:     s.CommitCount,
+			Percentage:  s.Percentage,
+			Source:      s.Source,
+			Tagged:      tags[s.Email],
+			LinkGroup:   links[s.Email],
+			LinkLabel:   linkLabels[links[s.Email]],
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to write markdown file: %w", err)
+		return "", fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write JSON file: %w", err)
 	}
 
 	return filename, nil
 }
 
-// ExportDatabaseBackup copies the current database to a backup file
-func ExportDatabaseBackup(currentDBPath string) (string, error) {
+// ExportDatabaseBackup writes a consistent backup of the open database into the working
+// directory, named after the current database file
+func ExportDatabaseBackup(database *db.DB, currentDBPath string) (string, error) {
+	if database == nil {
+		return "", fmt.Errorf("no database connection")
+	}
+
 	// Generate backup filename with timestamp
 	timestamp := time.Now().Format("2006-01-02-150405")
 	baseName := strings.TrimSuffix(filepath.Base(currentDBPath), filepath.Ext(currentDBPath))
 	backupFilename := fmt.Sprintf("%s-backup-%s.db", baseName, timestamp)
 
-	// Open source file
-	src, err := os.Open(currentDBPath)
+	if err := database.Backup(backupFilename); err != nil {
+		return "", err
+	}
+
+	return backupFilename, nil
+}
+
+// ExportDomainsJSON exports highlight domains (domain -> color_index) to a JSON file
+func ExportDomainsJSON(database *db.DB) (string, error) {
+	if database == nil {
+		return "", fmt.Errorf("no database connection")
+	}
+
+	domains, err := database.GetDomains()
 	if err != nil {
-		return "", fmt.Errorf("failed to open database: %w", err)
+		return "", fmt.Errorf("failed to get domains: %w", err)
 	}
-	defer src.Close()
 
-	// Create destination file
-	dst, err := os.Create(backupFilename)
+	data, err := json.MarshalIndent(domains, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("failed to create backup file: %w", err)
+		return "", fmt.Errorf("failed to encode domains: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filename := fmt.Sprintf("highlight-domains-%s.json", timestamp)
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write domains file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// ImportDomainsJSON loads highlight domains from a JSON file written by ExportDomainsJSON
+// Domains that already exist keep their current color; imported colors that would collide
+// with an existing domain get the lowest unused index instead. Returns the number imported.
+func ImportDomainsJSON(database *db.DB, path string) (int, error) {
+	if database == nil {
+		return 0, fmt.Errorf("no database connection")
 	}
-	defer dst.Close()
 
-	// Copy contents
-	_, err = io.Copy(dst, src)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to copy database: %w", err)
+		return 0, fmt.Errorf("failed to read domains file: %w", err)
 	}
 
-	return backupFilename, nil
+	var incoming map[string]int
+	if err := json.Unmarshal(data, &incoming); err != nil {
+		return 0, fmt.Errorf("failed to parse domains file: %w", err)
+	}
+
+	existing, err := database.GetDomains()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get domains: %w", err)
+	}
+	usedColors := make(map[int]bool, len(existing))
+	for _, idx := range existing {
+		usedColors[idx] = true
+	}
+
+	// Sorted for deterministic color assignment on collisions
+	names := make([]string, 0, len(incoming))
+	for domain := range incoming {
+		names = append(names, domain)
+	}
+	sort.Strings(names)
+
+	imported := 0
+	for _, name := range names {
+		domain := strings.TrimSpace(name)
+		if domain == "" {
+			continue
+		}
+		if _, ok := existing[domain]; ok {
+			continue
+		}
+
+		colorIndex := incoming[name]
+		if colorIndex < 0 || usedColors[colorIndex] {
+			colorIndex = lowestUnusedColorIndex(existing)
+		}
+
+		if err := database.SaveDomain(domain, colorIndex); err != nil {
+			return imported, err
+		}
+		existing[domain] = colorIndex
+		usedColors[colorIndex] = true
+		imported++
+	}
+
+	return imported, nil
 }
 
 // ExportProjectReport exports a comprehensive project report to markdown
@@ -287,4 +467,3 @@ func ExportProjectReport(database *db.DB, dbPath string) (string, error) {
 
 	return filename, nil
 }
-
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// Contact export formats accepted by ExportTaggedContacts
+const (
+	ContactsFormatVCard = "vcard"
+	ContactsFormatCSV   = "csv"
+)
+
+// contact is one tagged user merged from their commits and stored profile
+type contact struct {
+	Name     string
+	Email    string
+	Login    string
+	Company  string
+	Location string
+	Twitter  string
+}
+
+// githubURL returns the contact's GitHub profile URL
+func (c contact) githubURL() string {
+	return "https://github.com/" + c.Login
+}
+
+// ExportTaggedContacts writes the repo's tagged users as a vCard or CSV contacts file and
+// returns the filename and number of contacts written. Service accounts and users with no
+// deliverable email (none, or only GitHub noreply addresses) are skipped; when nothing is
+// left no file is written.
+func ExportTaggedContacts(database *db.DB, owner, repo, format string) (string, int, error) {
+	if database == nil {
+		return "", 0, fmt.Errorf("no database connection")
+	}
+	var ext string
+	switch format {
+	case ContactsFormatVCard:
+		ext = "vcf"
+	case ContactsFormatCSV:
+		ext = "csv"
+	default:
+		return "", 0, fmt.Errorf("unsupported contacts format %q", format)
+	}
+
+	contacts, err := collectTaggedContacts(database, owner, repo)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(contacts) == 0 {
+		return "", 0, nil
+	}
+
+	timestamp := time.Now().Format("2006-01-02")
+	safeOwner := strings.ReplaceAll(owner, "/", "-")
+	safeName := strings.ReplaceAll(repo, "/", "-")
+	filename := fmt.Sprintf("%s-%s-contacts-%s.%s", safeOwner, safeName, timestamp, ext)
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create contacts file: %w", err)
+	}
+	defer f.Close()
+
+	if format == ContactsFormatVCard {
+		var sb strings.Builder
+		for _, c := range contacts {
+			writeVCard(&sb, c)
+		}
+		if _, err := f.WriteString(sb.String()); err != nil {
+			return "", 0, fmt.Errorf("failed to write contacts file: %w", err)
+		}
+		return filename, len(contacts), nil
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"name", "email", "github_url", "company", "location", "twitter"})
+	for _, c := range contacts {
+		w.Write([]string{c.Name, c.Email, c.githubURL(), c.Company, c.Location, c.Twitter})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", 0, fmt.Errorf("failed to write contacts file: %w", err)
+	}
+	return filename, len(contacts), nil
+}
+
+// collectTaggedContacts merges the tagged users' commit identities with their stored
+// profiles, one contact per login, sorted by login
+func collectTaggedContacts(database *db.DB, owner, repo string) ([]contact, error) {
+	users, err := database.GetTaggedUsersWithLogins(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].GitHubLogin != users[j].GitHubLogin {
+			return users[i].GitHubLogin < users[j].GitHubLogin
+		}
+		return users[i].Email < users[j].Email
+	})
+
+	var contacts []contact
+	byLogin := make(map[string]int)
+	for _, u := range users {
+		if isServiceAccount(u.GitHubLogin, u.Email) || strings.HasSuffix(u.GitHubLogin, "[bot]") {
+			continue
+		}
+		idx, seen := byLogin[u.GitHubLogin]
+		if !seen {
+			profile, err := database.GetUserProfile(u.GitHubLogin)
+			if err != nil {
+				return nil, err
+			}
+			c := contact{
+				Name:     profile.Name,
+				Email:    profile.Email,
+				Login:    u.GitHubLogin,
+				Company:  strings.TrimPrefix(profile.Company, "@"),
+				Location: profile.Location,
+				Twitter:  profile.TwitterUsername,
+			}
+			if c.Name == "" {
+				c.Name = u.Name
+			}
+			contacts = append(contacts, c)
+			idx = len(contacts) - 1
+			byLogin[u.GitHubLogin] = idx
+		}
+		// The profile's public email wins; otherwise use the first commit email that can receive mail
+		if contacts[idx].Email == "" && u.Email != "" && !models.IsNoreplyEmail(u.Email) {
+			contacts[idx].Email = u.Email
+		}
+	}
+
+	kept := contacts[:0]
+	for _, c := range contacts {
+		if c.Email == "" {
+			continue
+		}
+		if c.Name == "" {
+			c.Name = c.Login
+		}
+		kept = append(kept, c)
+	}
+	return kept, nil
+}
+
+// writeVCard appends one vCard 3.0 entry for the contact
+func writeVCard(sb *strings.Builder, c contact) {
+	line := func(s string) {
+		sb.WriteString(s)
+		sb.WriteString("\r\n")
+	}
+	line("BEGIN:VCARD")
+	line("VERSION:3.0")
+	line("FN:" + vcardEscape(c.Name))
+	line("N:" + vcardEscape(c.Name) + ";;;;")
+	line("EMAIL;TYPE=INTERNET:" + vcardEscape(c.Email))
+	line("URL:" + c.githubURL())
+	line("X-SOCIALPROFILE;TYPE=github:" + c.githubURL())
+	if c.Company != "" {
+		line("ORG:" + vcardEscape(c.Company))
+	}
+	if c.Location != "" {
+		line("ADR:;;;" + vcardEscape(c.Location) + ";;;")
+	}
+	if c.Twitter != "" {
+		line("X-SOCIALPROFILE;TYPE=twitter:https://twitter.com/" + vcardEscape(c.Twitter))
+	}
+	line("END:VCARD")
+}
+
+// vcardEscape escapes text for a vCard property value
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,187 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/api"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+)
+
+// projectReportHTMLTemplate renders a self-contained report (inline CSS, no external assets)
+// html/template escapes all names, emails, and URLs.
+var projectReportHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Project Report: {{.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1100px; color: #222; }
+h1 { border-bottom: 2px solid #c00; padding-bottom: .3em; }
+h2 { margin-top: 2em; }
+.meta { color: #666; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border: 1px solid #ddd; padding: 6px 10px; text-align: left; }
+th { background: #f4f4f4; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+tr.domain td { background: #fff6cc; font-weight: 600; }
+a { color: #0366d6; text-decoration: none; }
+a:hover { text-decoration: underline; }
+.legend { display: inline-block; padding: 2px 8px; background: #fff6cc; border: 1px solid #ddd; }
+</style>
+</head>
+<body>
+<h1>Project Report: {{.Name}}</h1>
+<p class="meta">Generated {{.Generated}} &middot; {{len .Repos}} repositories &middot; {{.TotalCommits}} commits</p>
+{{if .Domains}}<p>Highlighted domains: <span class="legend">{{range $i, $d := .Domains}}{{if $i}}, {{end}}{{$d}}{{end}}</span></p>{{end}}
+{{range .Repos}}
+<h2><a href="{{.URL}}">{{.Owner}}/{{.Name}}</a></h2>
+<p class="meta">{{len .Rows}} committers &middot; {{.TotalCommits}} commits</p>
+{{if .Rows}}<table>
+<thead><tr><th>Rank</th><th>Name</th><th>GitHub Login</th><th>Email</th><th>Commits</th><th>%</th></tr></thead>
+<tbody>
+{{range .Rows}}<tr{{if .Highlight}} class="domain"{{end}}><td class="num">{{.Rank}}</td><td>{{.Name}}</td><td>{{if .Login}}<a href="{{.ProfileURL}}">{{.Login}}</a>{{else}}-{{end}}</td><td>{{.Email}}</td><td class="num">{{.Commits}}</td><td class="num">{{printf "%.1f" .Percentage}}%</td></tr>
+{{end}}</tbody>
+</table>{{else}}<p><em>No cached commits</em></p>{{end}}
+{{else}}<p><em>No repositories tracked</em></p>
+{{end}}
+</body>
+</html>
+`))
+
+// htmlReportRow is one committer row in the HTML project report
+type htmlReportRow struct {
+	Rank       int
+	Name       string
+	Login      string
+	ProfileURL string
+	Email      string
+	Commits    int
+	Percentage float64
+	Highlight  bool // email domain is a highlight domain
+}
+
+// htmlReportRepo is one repository section in the HTML project report
+type htmlReportRepo struct {
+	Owner        string
+	Name         string
+	URL          string
+	TotalCommits int
+	Rows         []htmlReportRow
+}
+
+// ExportProjectReportHTML exports the project report as a self-contained HTML file
+// Repo and profile links point at the GitHub Enterprise server apiBaseURL names (empty = github.com).
+func ExportProjectReportHTML(database *db.DB, dbPath, apiBaseURL string) (string, error) {
+	if database == nil {
+		return "", fmt.Errorf("no database connection")
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filename := fmt.Sprintf("project-report-%s.html", timestamp)
+
+	repos, err := database.GetTrackedRepos()
+	if err != nil {
+		return "", fmt.Errorf("failed to get tracked repos: %w", err)
+	}
+
+	domains, err := database.GetDomains()
+	if err != nil {
+		return "", fmt.Errorf("failed to get domains: %w", err)
+	}
+	domainNames := make([]string, 0, len(domains))
+	for d := range domains {
+		domainNames = append(domainNames, d)
+	}
+	sort.Strings(domainNames)
+	webBase := api.WebBaseURL(apiBaseURL)
+
+	data := struct {
+		Name         string
+		Generated    string
+		TotalCommits int
+		Domains      []string
+		Repos        []htmlReportRepo
+	}{
+		Name:      strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath)),
+		Generated: time.Now().Format("2006-01-02 15:04:05"),
+		Domains:   domainNames,
+	}
+
+	for _, repo := range repos {
+		stats, total, err := database.GetCommitterStats(repo.Owner, repo.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to get stats for %s/%s: %w", repo.Owner, repo.Name, err)
+		}
+
+		section := htmlReportRepo{
+			Owner:        repo.Owner,
+			Name:         repo.Name,
+			URL:          fmt.Sprintf("%s/%s/%s", webBase, repo.Owner, repo.Name),
+			TotalCommits: total,
+		}
+		for i, s := range stats {
+			row := htmlReportRow{
+				Rank:       i + 1,
+				Name:       s.DisplayName(),
+				Login:      s.GitHubLogin,
+				Email:      s.Email,
+				Commits:    s.CommitCount,
+				Percentage: s.Percentage,
+			}
+			if s.GitHubLogin != "" {
+				row.ProfileURL = webBase + "/" + s.GitHubLogin
+			}
+			_, row.Highlight = domains[extractDomain(s.Email)]
+			section.Rows = append(section.Rows, row)
+		}
+
+		data.TotalCommits += total
+		data.Repos = append(data.Repos, section)
+	}
+
+	var buf bytes.Buffer
+	if err := projectReportHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if err := writeFileAtomic(filename, buf.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// writeFileAtomic writes data to a temp file beside path and renames it into place, so a crash
+// mid-write never leaves a truncated file at path
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// Link group export formats accepted by ExportLinkGroups
+const (
+	LinkGroupsFormatJSON     = "json"
+	LinkGroupsFormatMarkdown = "md"
+)
+
+// linkGroupsExportJSON is the document written by ExportLinkGroups in JSON format
+type linkGroupsExportJSON struct {
+	Scope       string            `json:"scope"` // owner/repo, or "all repositories"
+	GeneratedAt string            `json:"generated_at"`
+	Groups      []linkGroupExport `json:"groups"`
+}
+
+// linkGroupExport is one identity cluster in a JSON link group export
+type linkGroupExport struct {
+	Group   int                     `json:"group"`
+	Label   string                  `json:"label,omitempty"`
+	Repos   []string                `json:"repos"`
+	Members []linkGroupMemberExport `json:"members"`
+}
+
+// linkGroupMemberExport is one linked identity in a JSON link group export
+type linkGroupMemberExport struct {
+	Email   string `json:"email"`
+	Name    string `json:"name,omitempty"`
+	Login   string `json:"github_login,omitempty"`
+	Repo    string `json:"repo"`
+	Commits int    `json:"commits"`
+}
+
+// ExportLinkGroups writes the committer link groups of owner/repo as JSON or markdown and
+// returns the filename and number of groups written. With an empty owner every repository is
+// exported, and groups from different repos that share an email are merged into one identity
+// cluster. Nothing is written when there are no groups.
+func ExportLinkGroups(database *db.DB, owner, repo, format string) (string, int, error) {
+	if database == nil {
+		return "", 0, fmt.Errorf("no database connection")
+	}
+	if format != LinkGroupsFormatJSON && format != LinkGroupsFormatMarkdown {
+		return "", 0, fmt.Errorf("unsupported link group format %q", format)
+	}
+
+	groups, err := database.GetLinkGroups(owner, repo)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(groups) == 0 {
+		return "", 0, nil
+	}
+
+	scope := owner + "/" + repo
+	base := fmt.Sprintf("%s-%s", strings.ReplaceAll(owner, "/", "-"), strings.ReplaceAll(repo, "/", "-"))
+	if owner == "" {
+		groups = mergeLinkGroups(groups)
+		scope = "all repositories"
+		base = "all-repos"
+	}
+	filename := fmt.Sprintf("%s-link-groups-%s.%s", base, time.Now().Format("2006-01-02"), format)
+
+	var data []byte
+	if format == LinkGroupsFormatJSON {
+		doc := linkGroupsExportJSON{
+			Scope:       scope,
+			GeneratedAt: time.Now().Format(time.RFC3339),
+			Groups:      make([]linkGroupExport, 0, len(groups)),
+		}
+		for _, g := range groups {
+			group := linkGroupExport{Group: g.GroupID, Label: g.Label, Repos: g.Repos}
+			for _, m := range g.Members {
+				group.Members = append(group.Members, linkGroupMemberExport(m))
+			}
+			doc.Groups = append(doc.Groups, group)
+		}
+		if data, err = json.MarshalIndent(doc, "", "  "); err != nil {
+			return "", 0, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		data = []byte(linkGroupsMarkdown(groups, scope))
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", 0, fmt.Errorf("failed to write link groups file: %w", err)
+	}
+	return filename, len(groups), nil
+}
+
+// linkGroupsMarkdown renders one section per identity cluster
+func linkGroupsMarkdown(groups []models.LinkGroup, scope string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Linked Identities for %s\n\n", scope))
+	sb.WriteString(fmt.Sprintf("**Groups:** %d\n", len(groups)))
+	sb.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+
+	for i, g := range groups {
+		title := fmt.Sprintf("Group %d", i+1)
+		if g.Label != "" {
+			title += ": " + g.Label
+		}
+		sb.WriteString(fmt.Sprintf("## %s (%s)\n\n", title, strings.Join(g.Repos, ", ")))
+		sb.WriteString("| Name | GitHub Login | Email | Repo | Commits |\n")
+		sb.WriteString("|------|--------------|-------|------|---------|\n")
+		for _, m := range g.Members {
+			login := "-"
+			if m.Login != "" {
+				login = fmt.Sprintf("[%s](https://github.com/%s)", m.Login, m.Login)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d |\n", m.Name, login, m.Email, m.Repo, m.Commits))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// mergeLinkGroups joins per-repo groups that share an email (case-insensitively) into
+// cross-repo clusters, keeping the order in which each cluster first appears and the first
+// label found
+func mergeLinkGroups(groups []models.LinkGroup) []models.LinkGroup {
+	parent := make([]int, len(groups))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	byEmail := make(map[string]int)
+	for i, g := range groups {
+		for _, m := range g.Members {
+			key := strings.ToLower(m.Email)
+			if j, ok := byEmail[key]; ok {
+				a, b := find(i), find(j)
+				if a > b {
+					a, b = b, a
+				}
+				parent[b] = a
+			} else {
+				byEmail[key] = i
+			}
+		}
+	}
+
+	var merged []models.LinkGroup
+	index := make(map[int]int) // root -> position in merged
+	for i, g := range groups {
+		root := find(i)
+		pos, ok := index[root]
+		if !ok {
+			pos = len(merged)
+			index[root] = pos
+			merged = append(merged, models.LinkGroup{GroupID: g.GroupID})
+		}
+		if merged[pos].Label == "" {
+			merged[pos].Label = g.Label
+		}
+		merged[pos].Members = append(merged[pos].Members, g.Members...)
+		for _, r := range g.Repos {
+			if !containsString(merged[pos].Repos, r) {
+				merged[pos].Repos = append(merged[pos].Repos, r)
+			}
+		}
+	}
+	for i := range merged {
+		sort.Strings(merged[i].Repos)
+	}
+	return merged
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
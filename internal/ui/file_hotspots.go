@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// fileHotspotLimit is how many of the most changed files the hotspot browser lists
+const fileHotspotLimit = 200
+
+// FileHotspotColumns returns column specs for the file hotspots page.
+func FileHotspotColumns() []ColumnSpec {
+	return []ColumnSpec{
+		{Title: "Path", FlexRatio: 60, MinWidth: 20},
+		{Title: "Commits", FixedWidth: 9},
+		{Title: "Top Editors", FlexRatio: 40, MinWidth: 16},
+	}
+}
+
+// RunFileHotspots shows the most frequently changed files of a repository with the committers
+// who changed them most. Only commits fetched with -files have file paths.
+func RunFileHotspots(database *db.DB, owner, repo string) error {
+	if database == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	files, err := database.GetTopFiles(owner, repo, fileHotspotLimit)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Printf("No file paths stored for %s/%s.\n", owner, repo)
+		fmt.Println("Fetch the repository with -files to record the files each commit changed.")
+		return nil
+	}
+
+	_, err = NewTabbedTable(fmt.Sprintf("File Hotspots: %s/%s", owner, repo)).
+		WithSubtitle(fmt.Sprintf("%d most changed files", len(files))).
+		WithHelpText("↑/↓: navigate | :: go to row | Enter: view | Esc: back").
+		AddReadOnlyPage("Files", FileHotspotColumns(), fileHotspotRows(files)).
+		Run()
+	if err != nil {
+		return fmt.Errorf("file hotspots table error: %w", err)
+	}
+	return nil
+}
+
+// fileHotspotRows builds one row per file, listing editors as "login (n)"
+func fileHotspotRows(files []models.FileStats) []table.Row {
+	rows := make([]table.Row, len(files))
+	for i, f := range files {
+		editors := make([]string, len(f.Editors))
+		for j, e := range f.Editors {
+			name := e.Login
+			if name == "" {
+				name = e.Name
+			}
+			if name == "" {
+				name = e.Email
+			}
+			editors[j] = fmt.Sprintf("%s (%d)", name, e.Commits)
+		}
+		rows[i] = table.Row{f.Path, fmt.Sprintf("%d", f.Commits), strings.Join(editors, ", ")}
+	}
+	return rows
+}
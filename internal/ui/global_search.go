@@ -0,0 +1,306 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+)
+
+// globalSearchKind says what Enter does with a global search hit
+type globalSearchKind int
+
+const (
+	globalSearchProfile   globalSearchKind = iota // Open the GitHub profile
+	globalSearchEmail                             // Copy the committer email (no known login)
+	globalSearchSubdomain                         // Open the subdomain browser for the domain
+	globalSearchImage                             // Open the layer inspector for the image
+	globalSearchWayback                           // Open the Wayback cache browser for the domain
+)
+
+// globalSearchHit is one row of the global search results
+type globalSearchHit struct {
+	category string // Section the hit is listed under
+	label    string
+	kind     globalSearchKind
+	target   string // Login, email, domain or image ref the hit drills into
+	filter   string // Subdomain or URL to pre-filter the drilled-in view with
+}
+
+// globalSearchHits flattens search results into rows, grouped by category in display order
+func globalSearchHits(results *db.GlobalSearchResults) []globalSearchHit {
+	var hits []globalSearchHit
+	for _, s := range results.Committers {
+		hit := globalSearchHit{category: "Committers", label: fmt.Sprintf("%s <%s> - %d commits", s.Name, s.Email, s.CommitCount), kind: globalSearchEmail, target: s.Email}
+		if s.GitHubLogin != "" {
+			hit.kind, hit.target = globalSearchProfile, s.GitHubLogin
+		}
+		hits = append(hits, hit)
+	}
+	for _, r := range results.Profiles {
+		hits = append(hits, globalSearchHit{category: "GitHub Profiles", label: fmt.Sprintf("%s %s: %s", r.Login, r.MatchType, r.MatchSource), kind: globalSearchProfile, target: r.Login})
+	}
+	for _, s := range results.Subdomains {
+		hits = append(hits, globalSearchHit{category: "Subdomains", label: fmt.Sprintf("%s (%s)", s.Subdomain, s.Sources), kind: globalSearchSubdomain, target: s.Domain, filter: s.Subdomain})
+	}
+	for _, r := range results.BuildSteps {
+		hits = append(hits, globalSearchHit{category: "Docker Build Steps", label: fmt.Sprintf("%s #%d: %s", r.ImageRef, r.StepIndex+1, r.Step), kind: globalSearchImage, target: r.ImageRef})
+	}
+	for _, r := range results.WaybackURLs {
+		hits = append(hits, globalSearchHit{category: "Wayback URLs", label: fmt.Sprintf("%s %s", r.Timestamp, r.URL), kind: globalSearchWayback, target: r.Domain, filter: r.URL})
+	}
+	return hits
+}
+
+// globalSearchModel searches committers, profiles, subdomains, build steps and Wayback URLs
+// at once, listing hits in category sections. Enter opens profiles in place; other hits quit
+// with drill set so RunGlobalSearch can open the subsystem's own view.
+type globalSearchModel struct {
+	database  *db.DB
+	layout    Layout
+	textInput textinput.Model
+	inputMode bool
+	keyword   string // Keyword of the results shown
+	hits      []globalSearchHit
+	cursor    int
+	statusMsg string
+	drill     *globalSearchHit
+	quitting  bool
+}
+
+func newGlobalSearchModel(database *db.DB) globalSearchModel {
+	ti := textinput.New()
+	ti.Placeholder = "Keyword (name, email, subdomain, build step, URL...)"
+	ti.Focus()
+	ti.CharLimit = 200
+
+	return globalSearchModel{
+		database:  database,
+		layout:    DefaultLayout(),
+		textInput: ti,
+		inputMode: true,
+	}
+}
+
+func (m globalSearchModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, tea.WindowSize())
+}
+
+func (m globalSearchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.layout = NewLayout(msg.Width, msg.Height)
+		m.textInput.Width = m.layout.InnerWidth - 12
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.inputMode {
+			return m.handleInputKeys(msg)
+		}
+		return m.handleResultKeys(msg)
+	}
+	return m, nil
+}
+
+func (m globalSearchModel) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		if m.keyword == "" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		// Back to the previous results
+		m.inputMode = false
+		m.textInput.SetValue(m.keyword)
+		m.textInput.Blur()
+		return m, nil
+	case "enter":
+		keyword := strings.TrimSpace(m.textInput.Value())
+		if keyword == "" {
+			return m, nil
+		}
+		m.search(keyword)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// search runs keyword through every subsystem and shows the hits
+func (m *globalSearchModel) search(keyword string) {
+	results, err := m.database.SearchEverything(keyword)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Search error: %v", err)
+		return
+	}
+	m.keyword = keyword
+	m.hits = globalSearchHits(results)
+	m.cursor = 0
+	m.inputMode = false
+	m.textInput.Blur()
+	m.statusMsg = fmt.Sprintf("Found %d results for '%s'", len(m.hits), keyword)
+	if len(results.Subdomains) == db.GlobalSearchLimit || len(results.WaybackURLs) == db.GlobalSearchLimit {
+		m.statusMsg += fmt.Sprintf(" (subdomains and URLs capped at %d - refine the keyword to see more)", db.GlobalSearchLimit)
+	}
+}
+
+func (m globalSearchModel) handleResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		m.quitting = true
+		return m, tea.Quit
+	case "/":
+		m.inputMode = true
+		m.textInput.SetValue("")
+		m.textInput.Focus()
+		return m, textinput.Blink
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.hits)-1 {
+			m.cursor++
+		}
+	case "pgup":
+		m.cursor = max(m.cursor-m.visibleLines(), 0)
+	case "pgdown":
+		m.cursor = max(min(m.cursor+m.visibleLines(), len(m.hits)-1), 0)
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.hits)-1, 0)
+	case "enter":
+		if m.cursor >= len(m.hits) {
+			return m, nil
+		}
+		hit := m.hits[m.cursor]
+		switch hit.kind {
+		case globalSearchProfile:
+			m.statusMsg = openOrCopyURL("profile", "https://github.com/"+hit.target)
+		case globalSearchEmail:
+			m.statusMsg = copyURL("email", hit.target)
+		default:
+			m.drill = &hit
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+// visibleLines is how many result lines fit below the header and search box
+func (m globalSearchModel) visibleLines() int {
+	return max(m.layout.ViewportHeight-6-8, 5)
+}
+
+func (m globalSearchModel) View() string {
+	if m.quitting || m.drill != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(ViewHeaderWithSubtitle("Global Search", "Committers, GitHub profiles, subdomains, Docker build steps and Wayback URLs", m.layout.InnerWidth))
+	b.WriteString("Search: ")
+	if m.inputMode {
+		b.WriteString(m.textInput.View())
+	} else {
+		b.WriteString(m.keyword)
+	}
+	b.WriteString("\n")
+	if m.statusMsg != "" {
+		b.WriteString(RenderNormal(m.statusMsg))
+	}
+	b.WriteString("\n\n")
+
+	switch {
+	case m.keyword == "":
+		b.WriteString(RenderDim("Type a keyword and press Enter."))
+	case len(m.hits) == 0:
+		b.WriteString(RenderNormal("No results. Press / to search again."))
+	default:
+		b.WriteString(m.renderHits())
+	}
+
+	helpText := "↑/↓: navigate | Enter: open | /: new search | Esc: back"
+	if m.inputMode {
+		helpText = "Enter: search | Esc: back"
+	}
+	return TwoBoxView(b.String(), helpText, m.layout)
+}
+
+// renderHits lists the hits under a heading per category, scrolled to keep the cursor visible
+func (m globalSearchModel) renderHits() string {
+	var lines []string
+	cursorLine := 0
+	for i, hit := range m.hits {
+		if i == 0 || hit.category != m.hits[i-1].category {
+			count := 0
+			for _, h := range m.hits[i:] {
+				if h.category != hit.category {
+					break
+				}
+				count++
+			}
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, RenderAccent(fmt.Sprintf("%s (%d)", hit.category, count)))
+		}
+		label := truncateToWidth(hit.label, m.layout.InnerWidth-4)
+		if i == m.cursor {
+			cursorLine = len(lines)
+			lines = append(lines, RenderSelectedWidth("> "+label, m.layout.InnerWidth))
+		} else {
+			lines = append(lines, RenderNormal("  "+label))
+		}
+	}
+
+	height := m.visibleLines()
+	start := tableScrollStart(cursorLine, height, len(lines))
+	end := min(start+height, len(lines))
+	return strings.Join(lines[start:end], "\n")
+}
+
+// RunGlobalSearch searches every subsystem for a keyword. Enter on a subdomain, build step or
+// Wayback URL opens that subsystem's view, returning to the results when it closes.
+func RunGlobalSearch(logger *log.Logger, database *db.DB) error {
+	if database == nil {
+		return fmt.Errorf("database not available")
+	}
+
+	m := newGlobalSearchModel(database)
+	for {
+		finalModel, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+		if err != nil {
+			return err
+		}
+		fm, ok := finalModel.(globalSearchModel)
+		if !ok || fm.drill == nil {
+			return nil
+		}
+
+		hit := *fm.drill
+		switch hit.kind {
+		case globalSearchSubdomain:
+			err = RunSubdomainBrowserAt(logger, database, hit.target, hit.filter)
+		case globalSearchImage:
+			err = RunLayerInspectorWithDB(hit.target, database)
+		case globalSearchWayback:
+			err = RunWaybackCacheBrowserAt(logger, database, hit.target, hit.filter)
+		}
+		if err != nil {
+			fm.statusMsg = fmt.Sprintf("Open failed: %v", err)
+		}
+
+		fm.drill = nil
+		m = fm
+	}
+}
@@ -5,8 +5,11 @@ package ui
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	bubbleSpinner "github.com/charmbracelet/bubbles/spinner"
@@ -14,6 +17,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/thesavant42/gitsome-ng/internal/api"
 	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
 // =============================================================================
@@ -55,6 +59,8 @@ func buildFSTree(entries []api.TarEntry) *fsNode {
 			if i == len(parts)-1 {
 				current.isDir = e.IsDir
 				current.size = e.Size
+			} else {
+				current.isDir = true // Parent of another entry, even if the tar has no entry for it
 			}
 		}
 	}
@@ -89,10 +95,40 @@ func (n *fsNode) getSortedChildren() []*fsNode {
 	return children
 }
 
+// largestFiles returns up to n files under root, largest first (ties ordered by path)
+func largestFiles(root *fsNode, n int) []*fsNode {
+	var files []*fsNode
+	var walk func(node *fsNode)
+	walk = func(node *fsNode) {
+		for _, child := range node.children {
+			if child.isDir {
+				walk(child)
+			} else {
+				files = append(files, child)
+			}
+		}
+	}
+	walk(root)
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].size != files[j].size {
+			return files[i].size > files[j].size
+		}
+		return files[i].getPath() < files[j].getPath()
+	})
+	if len(files) > n {
+		files = files[:n]
+	}
+	return files
+}
+
 // =============================================================================
 // Filesystem Browser Model (using bubbles/table)
 // =============================================================================
 
+// largestFilesLimit is how many files the "largest files" view lists
+const largestFilesLimit = 100
+
 // fsTableRow represents a row in the filesystem table
 type fsTableRow struct {
 	node   *fsNode
@@ -109,15 +145,25 @@ type fsBrowserModel struct {
 	imageRef    string
 	layerDigest string
 	layerSize   int64
+	client      *api.RegistryClient // Used for layer downloads (carries any registry credentials)
+	exportJSON  bool                // Export format toggle: JSON manifest instead of text tree
+	largestView bool                // Flat list of the largest files instead of the directory tree
 	statusMsg   string
-	quitting    bool
-	layout      Layout
+
+	// Layer download in flight ('d'); progress arrives on downloadCh
+	downloading   bool
+	downloadCh    chan api.DownloadProgress
+	download      api.DownloadProgress
+	downloadStart time.Time
+	quitting      bool
+	layout        Layout
 }
 
-func newFSBrowserModel(entries []api.TarEntry, layerInfo, imageRef, layerDigest string, layerSize int64) fsBrowserModel {
+func newFSBrowserModel(entries []api.TarEntry, layerInfo, imageRef, layerDigest string, layerSize int64, client *api.RegistryClient) fsBrowserModel {
 	root := buildFSTree(entries)
 
 	m := fsBrowserModel{
+		client:      client,
 		root:        root,
 		currentNode: root,
 		layerInfo:   layerInfo,
@@ -166,7 +212,7 @@ func (m *fsBrowserModel) initTable() {
 		table.WithColumns(columns),
 		table.WithRows(tableRows),
 		table.WithFocused(true),
-		table.WithHeight(m.layout.TableHeight),
+		table.WithHeight(m.tableHeight()),
 	)
 
 	// Apply styles matching the main TUI pattern
@@ -178,6 +224,8 @@ func (m *fsBrowserModel) buildTableRows() []table.Row {
 	for i, row := range m.rows {
 		if row.isBack {
 			tableRows[i] = table.Row{"..", ""}
+		} else if m.largestView {
+			tableRows[i] = table.Row{row.node.getPath(), api.HumanReadableSize(row.node.size)}
 		} else {
 			name := row.node.name
 			var info string
@@ -202,6 +250,14 @@ func (m *fsBrowserModel) updateTableRows() {
 	// Build rows data
 	m.rows = []fsTableRow{}
 
+	if m.largestView {
+		for _, file := range largestFiles(m.root, largestFilesLimit) {
+			m.rows = append(m.rows, fsTableRow{node: file})
+		}
+		m.table.SetRows(m.buildTableRows())
+		return
+	}
+
 	// Always add ".." - at root it goes back to layer selector
 	m.rows = append(m.rows, fsTableRow{isBack: true})
 
@@ -230,7 +286,18 @@ func (m *fsBrowserModel) rebuildTable() {
 		{Title: "Size", Width: sizeW},
 	}
 	m.table.SetColumns(columns)
-	m.table.SetHeight(m.layout.TableHeight)
+	m.table.SetHeight(m.tableHeight())
+}
+
+// tableHeight returns the table rows that fit the browser's border with the status line below
+// The border holds ViewportHeight-5 lines: title, divider, blank, path, count, blank, the table
+// (header + divider + rows) and the status line.
+func (m fsBrowserModel) tableHeight() int {
+	h := m.layout.ViewportHeight - 5 - 9
+	if h < MinTableHeight {
+		return MinTableHeight
+	}
+	return h
 }
 
 func (m fsBrowserModel) Init() tea.Cmd {
@@ -243,13 +310,23 @@ type downloadMsg struct {
 	err  error
 }
 
+// downloadProgressMsg is sent as a layer download streams to disk
+type downloadProgressMsg struct {
+	progress api.DownloadProgress
+}
+
 func (m fsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case downloadProgressMsg:
+		m.download = msg.progress
+		return m, waitForDownloadProgress(m.downloadCh)
+
 	case downloadMsg:
+		m.downloading = false
 		if msg.err != nil {
 			m.statusMsg = fmt.Sprintf("Download failed: %v", msg.err)
 		} else {
-			m.statusMsg = fmt.Sprintf("Downloaded to: %s", msg.path)
+			m.statusMsg = fmt.Sprintf("Downloaded and verified to: %s", msg.path)
 		}
 		return m, nil
 
@@ -262,6 +339,10 @@ func (m fsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Clear status message on any key press
 		m.statusMsg = ""
 
+		if m.largestView {
+			return m.handleLargestViewKey(msg)
+		}
+
 		switch msg.String() {
 		case "q", "esc":
 			// Go back - if at root, return to layer selector; otherwise go up
@@ -276,8 +357,26 @@ func (m fsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "d":
 			// Download the layer
-			m.statusMsg = "Downloading layer..."
-			return m, m.downloadLayer()
+			return m.startDownload()
+		case "e":
+			// Export the whole layer tree, not just the current directory
+			path, err := exportLayerTree(m.root, m.imageRef, m.layerDigest, m.exportJSON)
+			if err != nil {
+				m.statusMsg = fmt.Sprintf("Export failed: %v", err)
+			} else {
+				m.statusMsg = fmt.Sprintf("Exported file tree to: %s", path)
+			}
+			return m, nil
+		case "f":
+			m.exportJSON = !m.exportJSON
+			m.statusMsg = fmt.Sprintf("Export format: %s", m.exportFormat())
+			return m, nil
+		case "l":
+			// Switch to the flat largest-files view
+			m.largestView = true
+			m.updateTableRows()
+			m.table.SetCursor(0)
+			return m, nil
 		case "enter":
 			cursor := m.table.Cursor()
 			if cursor < 0 || cursor >= len(m.rows) {
@@ -324,14 +423,95 @@ func (m fsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m fsBrowserModel) downloadLayer() tea.Cmd {
+// handleLargestViewKey handles keys in the largest-files view
+// Enter jumps to the selected file's directory in the tree view.
+func (m fsBrowserModel) handleLargestViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "t", "l", "q", "esc", "backspace", "h":
+		// Back to the tree where we left it
+		m.largestView = false
+		m.updateTableRows()
+		m.table.SetCursor(0)
+		return m, nil
+	case "enter":
+		cursor := m.table.Cursor()
+		if cursor < 0 || cursor >= len(m.rows) {
+			return m, nil
+		}
+		file := m.rows[cursor].node
+		m.largestView = false
+		m.currentNode = file.parent
+		m.updateTableRows()
+		for i, row := range m.rows {
+			if row.node == file {
+				m.table.SetCursor(i)
+				break
+			}
+		}
+		return m, nil
+	case "d":
+		return m.startDownload()
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// startDownload begins streaming the layer to disk, unless a download is already running
+func (m fsBrowserModel) startDownload() (tea.Model, tea.Cmd) {
+	if m.downloading {
+		return m, nil
+	}
+	m.downloading = true
+	m.download = api.DownloadProgress{Total: m.layerSize}
+	m.downloadStart = time.Now()
+	m.downloadCh = make(chan api.DownloadProgress, 1)
+	return m, tea.Batch(m.downloadLayer(m.downloadCh), waitForDownloadProgress(m.downloadCh))
+}
+
+func (m fsBrowserModel) downloadLayer(ch chan api.DownloadProgress) tea.Cmd {
 	return func() tea.Msg {
-		client := api.NewRegistryClient()
-		path, err := client.DownloadLayerBlob(m.imageRef, m.layerDigest, m.layerSize)
+		defer close(ch)
+		// Keep only the latest update so a slow UI never stalls the download
+		progress := func(p api.DownloadProgress) {
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- p:
+			default:
+			}
+		}
+		path, err := m.client.DownloadLayerBlobWithProgress(m.imageRef, m.layerDigest, m.layerSize, progress)
 		return downloadMsg{path: path, err: err}
 	}
 }
 
+// waitForDownloadProgress waits for the next download progress update
+func waitForDownloadProgress(ch chan api.DownloadProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return downloadProgressMsg{progress: p}
+	}
+}
+
+// downloadStatus describes a running download, e.g. "Downloading layer... 45.2 MB / 120.0 MB (37%) at 5.1 MB/s"
+func (m fsBrowserModel) downloadStatus() string {
+	status := "Downloading layer... " + api.HumanReadableSize(m.download.Written)
+	if m.download.Total > 0 {
+		status += fmt.Sprintf(" / %s (%d%%)", api.HumanReadableSize(m.download.Total), m.download.Written*100/m.download.Total)
+	}
+	if elapsed := time.Since(m.downloadStart).Seconds(); elapsed >= 1 {
+		status += fmt.Sprintf(" at %s/s", api.HumanReadableSize(int64(float64(m.download.Written)/elapsed)))
+	}
+	return status
+}
+
 func (m fsBrowserModel) View() string {
 	if m.quitting {
 		return ""
@@ -356,21 +536,29 @@ func (m fsBrowserModel) View() string {
 	contentBuilder.WriteString(strings.Repeat("─", m.layout.InnerWidth))
 	contentBuilder.WriteString("\n\n")
 
-	// Current path
-	path := m.currentNode.getPath()
-	if m.currentNode == m.root {
-		path = "/"
+	// Current path (or the largest-files heading)
+	if m.largestView {
+		contentBuilder.WriteString(NormalStyle.Render(fmt.Sprintf("Largest files (top %d by size)", largestFilesLimit)))
+		contentBuilder.WriteString("\n")
+		contentBuilder.WriteString(StatsStyle.Render(fmt.Sprintf("%d files", len(m.rows))))
+	} else {
+		path := m.currentNode.getPath()
+		if m.currentNode == m.root {
+			path = "/"
+		}
+		contentBuilder.WriteString(NormalStyle.Render(path))
+		contentBuilder.WriteString("\n")
+		contentBuilder.WriteString(StatsStyle.Render(fmt.Sprintf("%d items", len(m.rows)-1))) // -1 for ".."
 	}
-	contentBuilder.WriteString(NormalStyle.Render(path))
-	contentBuilder.WriteString("\n")
-	contentBuilder.WriteString(StatsStyle.Render(fmt.Sprintf("%d items", len(m.rows)-1))) // -1 for ".."
 	contentBuilder.WriteString("\n\n")
 
 	// Table view with full-width selection
 	contentBuilder.WriteString(RenderTableWithSelection(m.table, m.layout))
 
-	// Show status message if present
-	if m.statusMsg != "" {
+	// Show download progress or the status message if present
+	if m.downloading {
+		contentBuilder.WriteString("\n" + StatusMsgStyle.Render(m.downloadStatus()))
+	} else if m.statusMsg != "" {
 		contentBuilder.WriteString("\n" + StatusMsgStyle.Render(m.statusMsg))
 	}
 
@@ -390,7 +578,10 @@ func (m fsBrowserModel) View() string {
 	b.WriteString("\n")
 
 	// Help footer below border - use proper centering and width calculation
-	helpText := "enter: open | backspace: up | d: download | esc: back"
+	helpText := fmt.Sprintf("enter: open | backspace: up | l: largest files | d: download | e: export (%s) | f: format | esc: back", m.exportFormat())
+	if m.largestView {
+		helpText = "enter: go to file | t/esc: tree view | d: download"
+	}
 	textWidth := len(helpText)
 	padding := (m.layout.InnerWidth - textWidth) / 2
 	var footerContent strings.Builder
@@ -404,7 +595,7 @@ func (m fsBrowserModel) View() string {
 		footerContent.WriteString(strings.Repeat(" ", remaining))
 	}
 	// Apply white border to footer
-	footerBordered := NewBorderStyleWithColor(colorWhite).
+	footerBordered := NewBorderStyleWithColor(palette.Text).
 		Width(m.layout.InnerWidth).
 		Height(1).
 		Render(footerContent.String())
@@ -413,9 +604,92 @@ func (m fsBrowserModel) View() string {
 	return b.String()
 }
 
+// exportFormat returns the name of the selected export format
+func (m fsBrowserModel) exportFormat() string {
+	if m.exportJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// layerTreeEntry is one path in an exported layer manifest
+type layerTreeEntry struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"`
+	IsDir bool   `json:"is_dir"`
+}
+
+// layerTreeManifest is the JSON form of an exported layer file tree
+type layerTreeManifest struct {
+	Image      string           `json:"image"`
+	Layer      string           `json:"layer"`
+	ExportedAt string           `json:"exported_at"`
+	Entries    []layerTreeEntry `json:"entries"`
+}
+
+// exportLayerTree writes the layer's full file tree to a timestamped file in the working directory
+// asJSON selects a flattened JSON manifest; otherwise an indented text tree is written.
+func exportLayerTree(root *fsNode, imageRef, layerDigest string, asJSON bool) (string, error) {
+	now := time.Now()
+	digest := strings.TrimPrefix(layerDigest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	base := fmt.Sprintf("layer-%s-%s", digest, now.Format("20060102-150405"))
+
+	var data []byte
+	var filename string
+	if asJSON {
+		manifest := layerTreeManifest{
+			Image:      imageRef,
+			Layer:      layerDigest,
+			ExportedAt: now.Format(time.RFC3339),
+			Entries:    []layerTreeEntry{},
+		}
+		var walk func(n *fsNode)
+		walk = func(n *fsNode) {
+			for _, child := range n.getSortedChildren() {
+				manifest.Entries = append(manifest.Entries, layerTreeEntry{Path: child.getPath(), Size: child.size, IsDir: child.isDir})
+				walk(child)
+			}
+		}
+		walk(root)
+
+		encoded, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode layer tree: %w", err)
+		}
+		data = encoded
+		filename = base + ".json"
+	} else {
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("# %s\n# %s\n# Exported: %s\n\n/\n", imageRef, layerDigest, now.Format("2006-01-02 15:04:05")))
+		var walk func(n *fsNode, depth int)
+		walk = func(n *fsNode, depth int) {
+			for _, child := range n.getSortedChildren() {
+				indent := strings.Repeat("  ", depth)
+				if child.isDir {
+					sb.WriteString(fmt.Sprintf("%s%s/\n", indent, child.name))
+				} else {
+					sb.WriteString(fmt.Sprintf("%s%s (%s)\n", indent, child.name, api.HumanReadableSize(child.size)))
+				}
+				walk(child, depth+1)
+			}
+		}
+		walk(root, 1)
+		data = []byte(sb.String())
+		filename = base + ".txt"
+	}
+
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write layer tree: %w", err)
+	}
+	return filename, nil
+}
+
 // runFSBrowser launches the filesystem browser for layer contents
-func runFSBrowser(entries []api.TarEntry, layerInfo, imageRef, layerDigest string, layerSize int64) error {
-	m := newFSBrowserModel(entries, layerInfo, imageRef, layerDigest, layerSize)
+func runFSBrowser(entries []api.TarEntry, layerInfo, imageRef, layerDigest string, layerSize int64, client *api.RegistryClient) error {
+	m := newFSBrowserModel(entries, layerInfo, imageRef, layerDigest, layerSize, client)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
@@ -428,7 +702,7 @@ func runFSBrowser(entries []api.TarEntry, layerInfo, imageRef, layerDigest strin
 // runLayerSelectorTabbedTUI runs the layer selector using the new TabbedTableView.
 // This replaces both runLayerSelectorTUI (fresh fetch) and showCachedLayersForImage (cached).
 // Returns: selection string ("ALL", "0", "1,2,3", etc.) or empty if cancelled.
-func runLayerSelectorTabbedTUI(imageRef string, layers []api.Layer, buildSteps []string) (string, error) {
+func runLayerSelectorTabbedTUI(imageRef string, layers []api.Layer, buildSteps []string, imageConfig *models.ImageConfig) (string, error) {
 	// Build layer rows: "ALL" first, then individual layers
 	layerRows := make([]table.Row, len(layers)+1)
 	layerRows[0] = table.Row{"ALL", "(fetch all layers)", ""}
@@ -459,8 +733,13 @@ func runLayerSelectorTabbedTUI(imageRef string, layers []api.Layer, buildSteps [
 		builder.AddReadOnlyPage("Build Steps", BuildStepsColumns(), buildStepsRows)
 	}
 
+	// Add Config page (read-only) with env, entrypoint, ports and labels
+	if !imageConfig.IsEmpty() {
+		builder.AddReadOnlyPage("Config", ImageConfigColumns(), buildImageConfigRows(imageConfig))
+	}
+
 	// Set help text
-	if len(buildSteps) > 0 {
+	if len(buildSteps) > 0 || !imageConfig.IsEmpty() {
 		builder.WithHelpText("↑/↓: navigate | Tab/←/→: switch page | Enter: select/view | Esc: back")
 	} else {
 		builder.WithHelpText("↑/↓: navigate | Enter: select | Esc: back")
@@ -498,6 +777,78 @@ func buildBuildStepsRows(buildSteps []string) []table.Row {
 	return rows
 }
 
+// buildImageConfigRows creates table rows from an image config
+// Env vars, exposed ports and labels get one row each so long values can be opened in the detail view.
+func buildImageConfigRows(config *models.ImageConfig) []table.Row {
+	var rows []table.Row
+	if config.User != "" {
+		rows = append(rows, table.Row{"User", config.User})
+	}
+	if config.WorkingDir != "" {
+		rows = append(rows, table.Row{"WorkingDir", config.WorkingDir})
+	}
+	if len(config.Entrypoint) > 0 {
+		entrypoint, _ := json.Marshal(config.Entrypoint)
+		rows = append(rows, table.Row{"Entrypoint", string(entrypoint)})
+	}
+	if len(config.Cmd) > 0 {
+		cmd, _ := json.Marshal(config.Cmd)
+		rows = append(rows, table.Row{"Cmd", string(cmd)})
+	}
+	for _, port := range config.ExposedPorts {
+		rows = append(rows, table.Row{"ExposedPort", port})
+	}
+	for _, env := range config.Env {
+		rows = append(rows, table.Row{"Env", env})
+	}
+
+	labels := make([]string, 0, len(config.Labels))
+	for key := range config.Labels {
+		labels = append(labels, key)
+	}
+	sort.Strings(labels)
+	for _, key := range labels {
+		rows = append(rows, table.Row{"Label", key + "=" + config.Labels[key]})
+	}
+
+	if len(rows) == 0 {
+		return []table.Row{{"", "No image config available"}}
+	}
+	return rows
+}
+
+// Environment variables holding registry credentials for private images
+const (
+	dockerUsernameEnv = "DOCKER_USERNAME"
+	dockerPasswordEnv = "DOCKER_PASSWORD"
+)
+
+// tagCacheTTL is how long a cached tag listing is used before the registry is queried again
+const tagCacheTTL = time.Hour
+
+// newRegistryClient creates a registry client with credentials for private images
+// DOCKER_USERNAME/DOCKER_PASSWORD take precedence over the credentials saved in settings.
+func newRegistryClient(database *db.DB) *api.RegistryClient {
+	client := api.NewRegistryClient()
+
+	username, password := os.Getenv(dockerUsernameEnv), os.Getenv(dockerPasswordEnv)
+	if (username == "" || password == "") && database != nil {
+		username, password, _ = database.GetDockerCredentials()
+	}
+	if username != "" && password != "" {
+		client.SetCredentials(username, password)
+	}
+	return client
+}
+
+// registryAuthStatus describes which credentials a registry client pulls with
+func registryAuthStatus(client *api.RegistryClient) string {
+	if client.HasCredentials() {
+		return fmt.Sprintf("Registry auth: signed in as %s", client.Username())
+	}
+	return "Registry auth: anonymous (public images only)"
+}
+
 // RunLayerInspector runs the interactive layer inspector for a Docker image (no DB)
 func RunLayerInspector(imageRef string) error {
 	return runLayerInspectorInternal(imageRef, nil)
@@ -510,7 +861,7 @@ func RunLayerInspectorWithDB(imageRef string, database *db.DB) error {
 
 // runLayerInspectorInternal is the internal implementation
 func runLayerInspectorInternal(imageRef string, database *db.DB) error {
-	client := api.NewRegistryClient()
+	client := newRegistryClient(database)
 
 	// 1. Fetch manifest with spinner
 	var manifest *api.Manifest
@@ -551,39 +902,40 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 		}
 	}
 
-	// 3. Fetch and display build steps
+	// 3. Fetch and display build steps and image config (env, entrypoint, ports, ...)
 	var steps []string
+	var imageConfig *models.ImageConfig
 	var configDigest string
 
 	if manifest.Config.Digest != "" {
 		configDigest = manifest.Config.Digest
-		// v2 manifest - fetch config blob for build steps
+		// v2 manifest - fetch config blob for build steps and runtime settings
 		var stepsErr error
-		err := RunWithSpinner("Fetching build steps...", func() {
-			steps, stepsErr = client.FetchBuildSteps(imageRef, manifest.Config.Digest)
+		err := RunWithSpinner("Fetching image config...", func() {
+			imageConfig, steps, stepsErr = client.FetchImageConfig(imageRef, manifest.Config.Digest)
 		})
 
 		if err != nil {
 			return fmt.Errorf("spinner error: %w", err)
 		}
 		if stepsErr != nil {
-			// Log but don't fail - build steps are optional
-			fmt.Printf("  (Could not fetch build steps: %v)\n", stepsErr)
+			// Log but don't fail - build steps and config are optional
+			fmt.Printf("  (Could not fetch image config: %v)\n", stepsErr)
 		}
 	} else if len(manifest.V1History) > 0 {
 		// v1 manifest - extract build steps from v1Compatibility history
 		steps = api.ExtractV1BuildSteps(manifest.V1History)
 	}
 
-	// 4. Save manifest info (including build steps) to database
-	if database != nil && len(steps) > 0 {
+	// 4. Save manifest info (including build steps and config) to database
+	if database != nil && (len(steps) > 0 || !imageConfig.IsEmpty()) {
 		// Calculate total size
 		var totalSize int64
 		for _, layer := range manifest.Layers {
 			totalSize += layer.Size
 		}
 		// Save to database (platform is empty if not multi-arch)
-		if err := database.SaveImageManifest(imageRef, "", steps, configDigest, len(manifest.Layers), totalSize); err != nil {
+		if err := database.SaveImageManifest(imageRef, "", steps, configDigest, len(manifest.Layers), totalSize, imageConfig); err != nil {
 			fmt.Printf("  (Could not save manifest: %v)\n", err)
 		}
 	}
@@ -595,7 +947,7 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 	}
 
 	// 6. Run the layer selector TUI with proper styling (using new TabbedTableView)
-	selectionInput, err := runLayerSelectorTabbedTUI(imageRef, manifest.Layers, steps)
+	selectionInput, err := runLayerSelectorTabbedTUI(imageRef, manifest.Layers, steps, imageConfig)
 	if err != nil {
 		return err
 	}
@@ -677,6 +1029,7 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 		layer := manifest.Layers[idx]
 
 		var entries []api.TarEntry
+		var findings []models.LayerSecretFinding
 		var fromCache bool
 
 		// Check if we've already fetched this layer's contents (by digest only - same layer can appear in multiple images)
@@ -688,6 +1041,7 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 					entries = nil
 				} else {
 					fromCache = true
+					findings, _ = database.GetLayerSecretFindings(layer.Digest)
 				}
 			}
 		}
@@ -697,7 +1051,7 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 			var peekErr error
 
 			err := RunWithSpinner(fmt.Sprintf("Fetching layer %d from registry...", idx), func() {
-				entries, peekErr = client.PeekLayerBlob(imageRef, layer.Digest)
+				entries, findings, peekErr = client.ScanLayerBlob(imageRef, layer.Digest, api.DefaultSecretRules())
 			})
 
 			if err != nil {
@@ -716,6 +1070,9 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 				if err := database.SaveLayerInspection(imageRef, layer.Digest, idx, layer.Size, len(entries), string(contentsJSON)); err != nil {
 					fmt.Printf("(Could not save to database: %v)\n", err)
 				}
+				if err := database.SaveLayerSecretFindings(layer.Digest, findings); err != nil {
+					fmt.Printf("(Could not save secret findings: %v)\n", err)
+				}
 			}
 		}
 
@@ -727,12 +1084,12 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 		sizeStr := api.HumanReadableSize(layer.Size)
 		layerInfo := fmt.Sprintf("Layer %d/%d (%s) [%s] %s", currentIdx+1, len(indicesToPeek), sourceLabel, layer.Digest[:12], sizeStr)
 
-		if err := runFSBrowser(entries, layerInfo, imageRef, layer.Digest, layer.Size); err != nil {
+		if err := runFSBrowser(entries, layerInfo, imageRef, layer.Digest, layer.Size, client); err != nil {
 			return fmt.Errorf("browser error: %w", err)
 		}
 
 		// Prompt for next action
-		action, err := runLayerActionSelectorTUI(currentIdx+1, len(indicesToPeek), sourceLabel)
+		action, err := runLayerActionSelectorTUI(currentIdx+1, len(indicesToPeek), sourceLabel, len(findings))
 		if err != nil || action == "" {
 			// Treat error or cancellation as "done browsing"
 			return nil
@@ -743,13 +1100,18 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 			currentIdx++
 		case "prev":
 			currentIdx--
+		case "secrets":
+			if err := runSecretFindingsTUI(fmt.Sprintf("Secrets: %s layer %d", imageRef, idx), findings, map[string]int{layer.Digest: idx}); err != nil {
+				return err
+			}
+			// Stay on same layer
 		case "refresh":
 			// Clear cache and re-fetch
 			if database != nil {
 				// Force re-fetch by fetching fresh
 				var peekErr error
 				err := RunWithSpinner(fmt.Sprintf("Re-fetching layer %d from registry...", idx), func() {
-					entries, peekErr = client.PeekLayerBlob(imageRef, layer.Digest)
+					entries, findings, peekErr = client.ScanLayerBlob(imageRef, layer.Digest, api.DefaultSecretRules())
 				})
 
 				if err != nil {
@@ -759,6 +1121,7 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 				} else {
 					contentsJSON, _ := json.Marshal(entries)
 					database.SaveLayerInspection(imageRef, layer.Digest, idx, layer.Size, len(entries), string(contentsJSON))
+					database.SaveLayerSecretFindings(layer.Digest, findings)
 				}
 			}
 			// Stay on same layer to show refreshed content
@@ -774,12 +1137,20 @@ func runLayerInspectorInternal(imageRef string, database *db.DB) error {
 
 // imageRefInputModel is the TUI model for entering an image reference
 // PromptForImageRef prompts the user for an image reference using generic InputModel
-func PromptForImageRef() (string, error) {
+// The subtitle shows whether private-image credentials are in use.
+func PromptForImageRef(database *db.DB) (string, error) {
 	value, cancelled, err := RunInput(InputConfig{
 		Title:       "Enter Image Reference",
-		Subtitle:    "Format: user/repo:tag (e.g., moby/buildkit:latest)",
+		Subtitle:    "Format: [registry/]user/repo[:tag] (e.g., moby/buildkit:latest, ghcr.io/owner/image:tag)\n" + registryAuthStatus(newRegistryClient(database)),
 		Placeholder: "moby/buildkit:latest",
 		HelpText:    "Enter: confirm | Esc: cancel",
+		Validator: func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return nil // Empty falls back to the default image
+			}
+			_, err := api.ParseImageRef(s)
+			return err
+		},
 	})
 	if err != nil {
 		return "", fmt.Errorf("input error: %w", err)
@@ -823,7 +1194,8 @@ func runPlatformSelectorTUI(platforms []api.Platform) (int, error) {
 // =============================================================================
 
 // runLayerActionSelectorTUI runs the action selector TUI and returns the selected action
-func runLayerActionSelectorTUI(currentLayer, totalLayers int, sourceLabel string) (string, error) {
+// A "View secret findings" action is offered when the layer has findings.
+func runLayerActionSelectorTUI(currentLayer, totalLayers int, sourceLabel string, findingCount int) (string, error) {
 	actions := []string{"next", "prev", "refresh", "done"}
 	actionLabels := []string{
 		"Next layer",
@@ -831,6 +1203,10 @@ func runLayerActionSelectorTUI(currentLayer, totalLayers int, sourceLabel string
 		"Re-fetch from registry",
 		"Done browsing",
 	}
+	if findingCount > 0 {
+		actions = append([]string{"secrets"}, actions...)
+		actionLabels = append([]string{fmt.Sprintf("View secret findings (%d)", findingCount)}, actionLabels...)
+	}
 
 	idx, err := RunSelector(SelectorConfig{
 		Title:    fmt.Sprintf("Layer %d/%d (%s) - What next?", currentLayer, totalLayers, sourceLabel),
@@ -852,20 +1228,29 @@ func runLayerActionSelectorTUI(currentLayer, totalLayers int, sourceLabel string
 // =============================================================================
 
 // runTagSelectorTUI runs the tag selector TUI and returns the selected tag
-func runTagSelectorTUI(imageName string, tags []string) (string, error) {
+func runTagSelectorTUI(imageName string, tags []string, fetchedAt time.Time) (string, bool, error) {
+	title := fmt.Sprintf("Select tag for %s", imageName)
+	if !fetchedAt.IsZero() {
+		title = fmt.Sprintf("%s (cached %s ago)", title, time.Since(fetchedAt).Round(time.Second))
+	}
+
 	idx, err := RunSelector(SelectorConfig{
-		Title:    fmt.Sprintf("Select tag for %s", imageName),
-		Subtitle: fmt.Sprintf("%d tags available", len(tags)),
-		HelpText: "↑/↓: navigate | Enter: select | Esc: back",
-		Items:    tags,
+		Title:      title,
+		Subtitle:   fmt.Sprintf("%d tags available", len(tags)),
+		HelpText:   "↑/↓: navigate | Enter: select | r: refresh tags | Esc: back",
+		Items:      tags,
+		RefreshKey: "r",
 	})
 	if err != nil {
-		return "", err
+		return "", false, err
+	}
+	if idx == SelectorRefresh {
+		return "", true, nil
 	}
 	if idx < 0 || idx >= len(tags) {
-		return "", nil
+		return "", false, nil
 	}
-	return tags[idx], nil
+	return tags[idx], false, nil
 }
 
 // =============================================================================
@@ -895,62 +1280,99 @@ func runTagInputTUI(imageName string, fetchErr error) (string, error) {
 }
 
 // PromptForTag prompts the user to select a tag from available tags
-func PromptForTag(imageName string) (string, error) {
-	client := api.NewRegistryClient()
+// A fully-qualified reference that already names a tag or digest (e.g. ghcr.io/owner/image:tag)
+// is returned as-is without prompting.
+func PromptForTag(imageName string, database *db.DB) (string, error) {
+	ref, err := api.ParseImageRef(imageName)
+	if err != nil {
+		return "", err
+	}
+	if ref.HasReference() {
+		return ref.Reference(), nil
+	}
 
-	// Fetch available tags with a spinner
-	var tags []string
-	var fetchErr error
+	client := newRegistryClient(database)
+	refresh := false
 
-	err := RunWithSpinner(fmt.Sprintf("Fetching tags for %s...", imageName), func() {
-		tags, fetchErr = client.ListTags(imageName)
-	})
+	for {
+		tags, fetchedAt, fetchErr := fetchImageTags(client, database, imageName, refresh)
+		if fetchErr != nil {
+			// If we can't fetch tags, fall back to manual input
+			tag, err := runTagInputTUI(imageName, fetchErr)
+			if err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(tag) == "" {
+				tag = "latest"
+			}
+			return tag, nil
+		}
 
-	if err != nil {
-		return "", fmt.Errorf("spinner error: %w", err)
-	}
-	if fetchErr != nil {
-		// If we can't fetch tags, fall back to manual input
-		tag, err := runTagInputTUI(imageName, fetchErr)
-		if err != nil {
-			return "", err
+		if len(tags) == 0 {
+			return "", fmt.Errorf("no tags found for %s", imageName)
 		}
-		if strings.TrimSpace(tag) == "" {
-			tag = "latest"
+
+		// If only one tag, use it directly
+		if len(tags) == 1 {
+			return tags[0], nil
 		}
-		return tag, nil
-	}
 
-	if len(tags) == 0 {
-		return "", fmt.Errorf("no tags found for %s", imageName)
-	}
+		// Build options for tag selection (limit to 50 most recent)
+		maxTags := 50
+		if len(tags) > maxTags {
+			tags = tags[len(tags)-maxTags:] // Take last N (usually most recent)
+		}
 
-	// If only one tag, use it directly
-	if len(tags) == 1 {
-		return tags[0], nil
-	}
+		// Reverse so newest are first
+		for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+			tags[i], tags[j] = tags[j], tags[i]
+		}
+
+		// Run the tag selector TUI
+		selected, refreshRequested, err := runTagSelectorTUI(imageName, tags, fetchedAt)
+		if err != nil {
+			return "", fmt.Errorf("tag selector error: %w", err)
+		}
+		if refreshRequested {
+			refresh = true
+			continue
+		}
+		if selected == "" {
+			return "", fmt.Errorf("no tag selected")
+		}
 
-	// Build options for tag selection (limit to 50 most recent)
-	maxTags := 50
-	if len(tags) > maxTags {
-		tags = tags[len(tags)-maxTags:] // Take last N (usually most recent)
+		return selected, nil
 	}
+}
 
-	// Reverse so newest are first
-	for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
-		tags[i], tags[j] = tags[j], tags[i]
+// fetchImageTags returns the tag list for an image, served from the database cache when it is
+// younger than tagCacheTTL unless refresh is set. fetchedAt is zero when the tags came from the registry.
+func fetchImageTags(client *api.RegistryClient, database *db.DB, imageName string, refresh bool) ([]string, time.Time, error) {
+	if database != nil && !refresh {
+		cached, fetchedAt, err := database.GetImageTags(imageName)
+		if err == nil && len(cached) > 0 && time.Since(fetchedAt) < tagCacheTTL {
+			return cached, fetchedAt, nil
+		}
 	}
 
-	// Run the tag selector TUI
-	selected, err := runTagSelectorTUI(imageName, tags)
+	// Fetch available tags with a spinner
+	var tags []string
+	var fetchErr error
+
+	err := RunWithSpinner(fmt.Sprintf("Fetching tags for %s...", imageName), func() {
+		tags, fetchErr = client.ListTags(imageName)
+	})
 	if err != nil {
-		return "", fmt.Errorf("tag selector error: %w", err)
+		return nil, time.Time{}, fmt.Errorf("spinner error: %w", err)
 	}
-	if selected == "" {
-		return "", fmt.Errorf("no tag selected")
+	if fetchErr != nil {
+		return nil, time.Time{}, fetchErr
 	}
 
-	return selected, nil
+	if database != nil && len(tags) > 0 {
+		_ = database.SaveImageTags(imageName, tags) // Cache is best-effort
+	}
+	return tags, time.Time{}, nil
 }
 
 // CachedImagesColumns returns column specs for cached images table.
@@ -1023,6 +1445,59 @@ func CachedLayerDetailColumns() []ColumnSpec {
 	}
 }
 
+// LayerSecretColumns returns column specs for the secret findings table.
+func LayerSecretColumns() []ColumnSpec {
+	return []ColumnSpec{
+		{Title: "Layer", FixedWidth: 8},
+		{Title: "File", FlexRatio: 40, MinWidth: 16},
+		{Title: "Rule", FixedWidth: 26},
+		{Title: "Preview", FlexRatio: 60, MinWidth: 16},
+	}
+}
+
+// layerSecretRows builds table rows for secret findings
+// layerIndex maps a layer digest to its index in the image being browsed.
+func layerSecretRows(findings []models.LayerSecretFinding, layerIndex map[string]int) []table.Row {
+	rows := make([]table.Row, len(findings))
+	for i, f := range findings {
+		layer := "?"
+		if idx, ok := layerIndex[f.LayerDigest]; ok {
+			layer = fmt.Sprintf("%d", idx)
+		}
+		rows[i] = table.Row{layer, f.FilePath, f.RuleName, f.Preview}
+	}
+	return rows
+}
+
+// collectLayerSecretFindings loads stored secret findings for each layer, topmost layer first
+func collectLayerSecretFindings(database *db.DB, layers []db.LayerInspection) ([]models.LayerSecretFinding, map[string]int) {
+	var findings []models.LayerSecretFinding
+	layerIndex := make(map[string]int, len(layers))
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		layerIndex[layer.LayerDigest] = layer.LayerIndex
+		layerFindings, err := database.GetLayerSecretFindings(layer.LayerDigest)
+		if err != nil {
+			continue
+		}
+		findings = append(findings, layerFindings...)
+	}
+	return findings, layerIndex
+}
+
+// runSecretFindingsTUI shows secret findings in a read-only table (Enter shows the full row)
+func runSecretFindingsTUI(title string, findings []models.LayerSecretFinding, layerIndex map[string]int) error {
+	_, err := NewTabbedTable(title).
+		WithSubtitle(fmt.Sprintf("%d possible secrets", len(findings))).
+		WithHelpText("↑/↓: navigate | Enter: view | Esc: back").
+		AddReadOnlyPage("Secrets", LayerSecretColumns(), layerSecretRows(findings, layerIndex)).
+		Run()
+	if err != nil {
+		return fmt.Errorf("secret findings table error: %w", err)
+	}
+	return nil
+}
+
 // DELETED: layerTableModel - replaced by TabbedTableView throughout
 
 func showCachedLayersForImage(database *db.DB, imageRef string) error {
@@ -1040,6 +1515,11 @@ func showCachedLayersForImage(database *db.DB, imageRef string) error {
 	buildSteps, _ := database.GetImageBuildSteps(imageRef)
 	// Ignore error - build steps are optional
 
+	imageConfig, _ := database.GetImageConfig(imageRef)
+	// Ignore error - image config is optional
+
+	findings, layerIndex := collectLayerSecretFindings(database, layers)
+
 	for {
 		// Build layer rows (no header - table columns provide that)
 		layerRows := make([]table.Row, len(layers))
@@ -1061,6 +1541,19 @@ func showCachedLayersForImage(database *db.DB, imageRef string) error {
 		if len(buildSteps) > 0 {
 			buildStepsRows := buildBuildStepsRows(buildSteps)
 			builder.AddReadOnlyPage("Build Steps", BuildStepsColumns(), buildStepsRows)
+		}
+
+		// Add Config page if the image config was saved
+		if !imageConfig.IsEmpty() {
+			builder.AddReadOnlyPage("Config", ImageConfigColumns(), buildImageConfigRows(imageConfig))
+		}
+
+		// Add Secrets page if any layer has findings
+		if len(findings) > 0 {
+			builder.AddReadOnlyPage(fmt.Sprintf("Secrets (%d)", len(findings)), LayerSecretColumns(), layerSecretRows(findings, layerIndex))
+		}
+
+		if len(buildSteps) > 0 || !imageConfig.IsEmpty() || len(findings) > 0 {
 			builder.WithHelpText("↑/↓: navigate | Tab/←/→: switch page | Enter: browse/view | Esc: back")
 		} else {
 			builder.WithHelpText("↑/↓: navigate | Enter: browse | Esc: back")
@@ -1091,7 +1584,7 @@ func showCachedLayersForImage(database *db.DB, imageRef string) error {
 		// Launch filesystem browser
 		sizeStr := api.HumanReadableSize(layer.LayerSize)
 		layerInfo := fmt.Sprintf("Layer %d [%s] %s", layer.LayerIndex, layer.LayerDigest[:12], sizeStr)
-		if err := runFSBrowser(entries, layerInfo, imageRef, layer.LayerDigest, layer.LayerSize); err != nil {
+		if err := runFSBrowser(entries, layerInfo, imageRef, layer.LayerDigest, layer.LayerSize, newRegistryClient(database)); err != nil {
 			return err
 		}
 		// After browsing, loop back to layer selection
@@ -1123,12 +1616,41 @@ func (i searchResultItem) FilterValue() string {
 	return i.result.FilePath
 }
 
-// searchModel is the Bubble Tea model for searching cached layers
+// buildStepResultItem implements list.Item for build step search results
+type buildStepResultItem struct {
+	result  db.BuildStepSearchResult
+	keyword string
+}
+
+func (i buildStepResultItem) Title() string {
+	return highlightMatch(i.result.Step, i.keyword)
+}
+
+func (i buildStepResultItem) Description() string {
+	return fmt.Sprintf("%s · Step %d", i.result.ImageRef, i.result.StepIndex+1)
+}
+
+func (i buildStepResultItem) FilterValue() string {
+	return i.result.Step
+}
+
+// highlightMatch renders every case-insensitive occurrence of keyword in text with the accent style
+func highlightMatch(text, keyword string) string {
+	if keyword == "" {
+		return text
+	}
+	re := regexp.MustCompile("(?i)" + regexp.QuoteMeta(keyword))
+	return re.ReplaceAllStringFunc(text, AccentStyle.Render)
+}
+
+// searchModel is the Bubble Tea model for searching the layer cache
+// The search function decides what is searched (layer file paths or build steps).
 type searchModel struct {
+	title     string
+	search    func(keyword string) ([]list.Item, error)
 	textInput string
-	results   []db.LayerSearchResult
+	results   []list.Item
 	list      list.Model
-	database  *db.DB
 	searching bool
 	quitting  bool
 	layout    Layout
@@ -1136,20 +1658,51 @@ type searchModel struct {
 	statusMsg string
 }
 
+// newSearchModel creates a search over cached layer file paths
 func newSearchModel(database *db.DB) searchModel {
+	return newCacheSearchModel("Search Cached Layers", func(keyword string) ([]list.Item, error) {
+		results, err := database.SearchLayerContents(keyword)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]list.Item, len(results))
+		for i, result := range results {
+			items[i] = searchResultItem{result: result}
+		}
+		return items, nil
+	})
+}
+
+// newBuildStepSearchModel creates a search over the build steps of cached image manifests
+func newBuildStepSearchModel(database *db.DB) searchModel {
+	return newCacheSearchModel("Search Build Steps", func(keyword string) ([]list.Item, error) {
+		results, err := database.SearchBuildSteps(keyword)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]list.Item, len(results))
+		for i, result := range results {
+			items[i] = buildStepResultItem{result: result, keyword: keyword}
+		}
+		return items, nil
+	})
+}
+
+func newCacheSearchModel(title string, search func(keyword string) ([]list.Item, error)) searchModel {
 	layout := DefaultLayout()
 
 	// Create empty list with default delegate styles
 	delegate := list.NewDefaultDelegate()
 
 	l := list.New([]list.Item{}, delegate, layout.InnerWidth-4, layout.TableHeight)
-	l.Title = "Search Cached Layers"
+	l.Title = title
 	l.SetShowStatusBar(true)
 	l.SetShowHelp(true)
 	l.SetFilteringEnabled(false) // We do our own search
 
 	return searchModel{
-		database:  database,
+		title:     title,
+		search:    search,
 		list:      l,
 		inputMode: true, // Start in input mode
 		layout:    layout,
@@ -1182,13 +1735,13 @@ func (m searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.textInput != "" {
 					// Perform search
 					m.searching = true
-					results, err := m.database.SearchLayerContents(m.textInput)
+					results, err := m.search(m.textInput)
 					m.searching = false
 					if err != nil {
 						m.statusMsg = fmt.Sprintf("Search error: %v", err)
 					} else {
 						m.results = results
-						m.updateListItems()
+						m.list.SetItems(results)
 						m.statusMsg = fmt.Sprintf("Found %d results for '%s'", len(results), m.textInput)
 					}
 					m.inputMode = false
@@ -1227,14 +1780,6 @@ func (m searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-func (m *searchModel) updateListItems() {
-	items := make([]list.Item, len(m.results))
-	for i, result := range m.results {
-		items[i] = searchResultItem{result: result}
-	}
-	m.list.SetItems(items)
-}
-
 func (m searchModel) View() string {
 	if m.quitting {
 		return ""
@@ -1243,7 +1788,7 @@ func (m searchModel) View() string {
 	var contentBuilder strings.Builder
 
 	// Title
-	contentBuilder.WriteString(TitleStyle.Render("Search Cached Layers"))
+	contentBuilder.WriteString(TitleStyle.Render(m.title))
 	contentBuilder.WriteString("\n")
 	// White divider after title
 	contentBuilder.WriteString(strings.Repeat("─", m.layout.InnerWidth))
@@ -1318,7 +1863,7 @@ func (m searchModel) View() string {
 	}
 
 	// Apply white border to footer
-	footerBordered := NewBorderStyleWithColor(colorWhite).
+	footerBordered := NewBorderStyleWithColor(palette.Text).
 		Width(m.layout.InnerWidth).
 		Height(1).
 		Render(footerContent.String())
@@ -1335,6 +1880,14 @@ func RunSearchCachedLayers(database *db.DB) error {
 	return err
 }
 
+// RunSearchBuildSteps runs the search interface for build steps of cached image manifests
+func RunSearchBuildSteps(database *db.DB) error {
+	m := newBuildStepSearchModel(database)
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
 // =============================================================================
 // Batch Fetch TUI Model - Styled batch layer download with spinner
 // =============================================================================
@@ -1438,8 +1991,8 @@ func (m batchFetchModel) startBatchFetch() tea.Cmd {
 		for i, layer := range m.layers {
 			idx := m.indices[i]
 
-			// Fetch layer
-			entries, err := m.client.PeekLayerBlob(m.imageRef, layer.Digest)
+			// Fetch layer and scan its small text files for secrets
+			entries, findings, err := m.client.ScanLayerBlob(m.imageRef, layer.Digest, api.DefaultSecretRules())
 			if err != nil {
 				errors = append(errors, fmt.Errorf("layer %d (%s): %w", idx, layer.Digest[:12], err))
 				continue
@@ -1450,6 +2003,9 @@ func (m batchFetchModel) startBatchFetch() tea.Cmd {
 			if err := m.database.SaveLayerInspection(m.imageRef, layer.Digest, idx, layer.Size, len(entries), string(contentsJSON)); err != nil {
 				errors = append(errors, fmt.Errorf("layer %d (save): %w", idx, err))
 			}
+			if err := m.database.SaveLayerSecretFindings(layer.Digest, findings); err != nil {
+				errors = append(errors, fmt.Errorf("layer %d (save secrets): %w", idx, err))
+			}
 		}
 
 		return batchFetchCompleteMsg{errors: errors}
@@ -1577,6 +2133,8 @@ func showBatchLayersForImage(database *db.DB, imageRef string, indices []int, ma
 		return nil
 	}
 
+	findings, layerIndex := collectLayerSecretFindings(database, layers)
+
 	// Use tabbed table for layer selection
 	for {
 		// Build layer rows (no header - table columns provide that)
@@ -1594,7 +2152,12 @@ func showBatchLayersForImage(database *db.DB, imageRef string, indices []int, ma
 		// Create tabbed table
 		builder := NewTabbedTable(fmt.Sprintf("Batch Layers: %s", imageRef))
 		builder.AddPage("Layers", CachedLayerDetailColumns(), layerRows)
-		builder.WithHelpText("↑/↓: navigate | Enter: browse | q/Esc: back")
+		if len(findings) > 0 {
+			builder.AddReadOnlyPage(fmt.Sprintf("Secrets (%d)", len(findings)), LayerSecretColumns(), layerSecretRows(findings, layerIndex))
+			builder.WithHelpText("↑/↓: navigate | Tab/←/→: switch page | Enter: browse/view | q/Esc: back")
+		} else {
+			builder.WithHelpText("↑/↓: navigate | Enter: browse | q/Esc: back")
+		}
 
 		result, err := builder.Run()
 		if err != nil {
@@ -1627,7 +2190,7 @@ func showBatchLayersForImage(database *db.DB, imageRef string, indices []int, ma
 		// Launch filesystem browser
 		sizeStr := api.HumanReadableSize(layer.LayerSize)
 		layerInfo := fmt.Sprintf("Layer %d [%s] %s", layer.LayerIndex, layer.LayerDigest[:12], sizeStr)
-		if err := runFSBrowser(entries, layerInfo, imageRef, layer.LayerDigest, layer.LayerSize); err != nil {
+		if err := runFSBrowser(entries, layerInfo, imageRef, layer.LayerDigest, layer.LayerSize, newRegistryClient(database)); err != nil {
 			return err
 		}
 		// After browsing, loop back to layer selection (no prompt)
@@ -100,6 +100,28 @@ func PromptForGitHubToken() (string, error) {
 	return token, nil
 }
 
+// PromptForPassphrase prompts for the passphrase protecting the project's stored API keys
+// Returns an empty string if the user skips it
+func PromptForPassphrase() (string, error) {
+	var passphrase string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Settings Passphrase").
+				Description("This project's API keys are encrypted. Leave empty to continue without them.\nSet GITSOME_SECRET to skip this prompt.").
+				EchoMode(huh.EchoModePassword).
+				Value(&passphrase),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return "", nil // Continue locked on cancel
+	}
+
+	return passphrase, nil
+}
+
 // ConfirmFetch asks user to confirm fetching commits
 func ConfirmFetch(owner, repo string) (bool, error) {
 	var confirm bool
@@ -215,4 +237,3 @@ func PromptForFilename(defaultName string) (string, error) {
 
 	return filename, nil
 }
-
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/thesavant42/gitsome-ng/internal/logging"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 )
 
@@ -132,11 +133,18 @@ func PrintSuccess(message string) {
 	fmt.Println(ReportSuccessStyle.Render(message))
 }
 
-// PrintError prints an error message
+// PrintError prints an error message and records it in the log
 func PrintError(message string) {
+	logging.Logger().Error(message)
 	fmt.Println(ReportErrorStyle.Render("Error: " + message))
 }
 
+// PrintWarning prints a warning message and records it in the log
+func PrintWarning(message string) {
+	logging.Logger().Warn(message)
+	fmt.Println(ReportWarningStyle.Render("Warning: " + message))
+}
+
 // PrintSummary prints a brief summary after the tables
 func PrintSummary(committerCount, authorCount, totalCommits int) {
 	summary := fmt.Sprintf(
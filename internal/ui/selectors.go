@@ -22,8 +22,13 @@ type SelectorConfig struct {
 	HelpText string   // Help text for footer (e.g., "↑/↓: navigate | Enter: select")
 	Items    []string // Display labels for each option
 	Values   []string // Optional: actual values (if different from display labels)
+	// RefreshKey, when set, closes the selector with SelectorRefresh so the caller can reload items
+	RefreshKey string
 }
 
+// SelectorRefresh is returned by Selected when the user pressed the configured RefreshKey.
+const SelectorRefresh = -2
+
 // SelectorModel is a generic single-column table selector.
 // Replaces platformSelectorModel, tagSelectorModel, layerActionSelectorModel, etc.
 type SelectorModel struct {
@@ -32,6 +37,7 @@ type SelectorModel struct {
 	layout   Layout
 	selected int // Index of selected item, -1 if cancelled
 	quitting bool
+	jump     RowJump
 }
 
 // NewSelectorModel creates a generic selector with the given configuration.
@@ -55,7 +61,7 @@ func NewSelectorModel(cfg SelectorConfig) SelectorModel {
 	// Default help text if not provided
 	helpText := cfg.HelpText
 	if helpText == "" {
-		helpText = "↑/↓: navigate | Enter: select | Esc: cancel"
+		helpText = "↑/↓: navigate | :: go to row | Enter: select | Esc: cancel"
 	}
 	cfg.HelpText = helpText
 
@@ -79,6 +85,9 @@ func (m SelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.jump.HandleKey(msg.String(), &m.table) {
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "esc":
 			m.selected = -1
@@ -89,6 +98,11 @@ func (m SelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 		}
+		if m.config.RefreshKey != "" && msg.String() == m.config.RefreshKey {
+			m.selected = SelectorRefresh
+			m.quitting = true
+			return m, tea.Quit
+		}
 	}
 
 	// Let table handle navigation
@@ -122,11 +136,16 @@ func (m SelectorModel) View() string {
 	// Table with full-width selection highlighting
 	content.WriteString(RenderTableWithSelection(m.table, m.layout))
 
+	helpText := m.config.HelpText
+	if m.jump.Active {
+		helpText = m.jump.Prompt(len(m.table.Rows()))
+	}
+
 	// Use TwoBoxView for consistent two-box layout
-	return TwoBoxView(content.String(), m.config.HelpText, m.layout)
+	return TwoBoxView(content.String(), helpText, m.layout)
 }
 
-// Selected returns the index of the selected item, or -1 if cancelled.
+// Selected returns the index of the selected item, -1 if cancelled, or SelectorRefresh.
 func (m SelectorModel) Selected() int {
 	return m.selected
 }
@@ -149,7 +168,7 @@ func (m SelectorModel) SelectedValue() string {
 // =============================================================================
 
 // RunSelector runs a selector TUI and returns the selected index.
-// Returns -1 if the user cancelled.
+// Returns -1 if the user cancelled, or SelectorRefresh if they pressed the RefreshKey.
 func RunSelector(cfg SelectorConfig) (int, error) {
 	model := NewSelectorModel(cfg)
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -6,11 +6,13 @@ package ui
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
 )
 
@@ -65,8 +67,9 @@ const (
 
 // Color string exports for progress bar and other components
 // These are used as: string(ColorText) -> "#FFFFFF" style notation
-// Using ANSI 256-color indices as hex approximations for Charm components
-const (
+// Using ANSI 256-color indices as hex approximations for Charm components.
+// SetTheme updates them to the selected palette.
+var (
 	ColorText    = "15"  // bright white (ANSI 256 color 15)
 	ColorTextDim = "241" // gray (ANSI 256 color 241)
 )
@@ -86,17 +89,154 @@ var LinkGroupColors = []int{
 	colorRedLink,
 }
 
+// =============================================================================
+// Themes
+// =============================================================================
+
+// colorDefault selects the terminal's own foreground or background color
+const colorDefault = -1
+
+// Theme is a named palette of 256-color codes used by the render functions
+type Theme struct {
+	Name         string
+	Text         int   // Normal text and footer borders
+	Dim          int   // Dimmed text and empty progress
+	Accent       int   // Hints, accents and dividers
+	Progress     int   // Progress text
+	Border       int   // Main box borders, spinner and progress fill
+	Error        int   // Errors and arrows
+	Success      int   // Success messages
+	Selection    int   // Background of selected rows, tabs and status messages
+	SelectedText int   // Text on the selection background
+	Pending      int   // Background of rows pending a link
+	PendingText  int   // Text on the pending background
+	Domain       int   // Rows whose email matches a highlight domain
+	LinkGroups   []int // Link group row colors, cycled by group number
+
+	Light *lightColors // Text colors for light backgrounds (nil = the palette reads on both)
+}
+
+// lightColors replace the text colors of a dark-optimized palette on a light background
+type lightColors struct {
+	Text   int
+	Dim    int
+	Accent int
+}
+
+// Themes are the palettes selectable with -theme or GITSOME_THEME
+var Themes = []Theme{
+	{
+		Name: "default", Text: colorWhite, Dim: colorGray, Accent: colorYellow, Progress: colorYellowDim,
+		Border: colorRed, Error: colorRed, Success: colorGreen, Selection: colorDarkRed, SelectedText: colorWhite,
+		Pending: colorYellowDim, PendingText: colorBlack, Domain: colorYellow, LinkGroups: LinkGroupColors,
+		Light: &lightColors{Text: 235, Dim: 243, Accent: 130},
+	},
+	{
+		// No hues, and the terminal's own text color so it reads on light backgrounds too
+		Name: "mono", Text: colorDefault, Dim: 244, Accent: colorDefault, Progress: 246,
+		Border: 244, Error: colorDefault, Success: colorDefault, Selection: 240, SelectedText: 231,
+		Pending: 250, PendingText: 232, Domain: 246, LinkGroups: []int{250, 242, 253, 238, 247, 236, 244, 252},
+	},
+	{
+		// Solarized accents, readable on light and dark backgrounds
+		Name: "solarized", Text: 244, Dim: 240, Accent: 136, Progress: 166,
+		Border: 33, Error: 160, Success: 64, Selection: 235, SelectedText: 230,
+		Pending: 136, PendingText: 234, Domain: 166, LinkGroups: []int{37, 136, 125, 166, 61, 64, 33, 160},
+	},
+}
+
+var (
+	theme           = Themes[0] // Theme picked with SetTheme
+	lightBackground bool        // Terminal background is light (see SetBackground)
+
+	// palette is the theme the render functions use, adapted to the background
+	palette = Themes[0]
+)
+
+// ThemeNames lists the selectable theme names
+func ThemeNames() []string {
+	names := make([]string, len(Themes))
+	for i, t := range Themes {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// SetTheme selects the named palette for all rendering; an empty name keeps the default.
+// Call it at startup, before any view is created.
+func SetTheme(name string) error {
+	if name == "" {
+		name = Themes[0].Name
+	}
+	for _, t := range Themes {
+		if strings.EqualFold(t.Name, name) {
+			theme = t
+			applyPalette()
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemeNames(), ", "))
+}
+
+// Background modes accepted by SetBackground
+const (
+	BackgroundAuto  = "auto"
+	BackgroundDark  = "dark"
+	BackgroundLight = "light"
+)
+
+// SetBackground adapts the text colors to the terminal background: "light", "dark", or
+// "auto" (or empty) to ask the terminal. Terminals that don't answer are treated as dark,
+// keeping the dark-optimized colors. Call it at startup, before the TUI takes the terminal.
+func SetBackground(mode string) error {
+	switch strings.ToLower(mode) {
+	case "", BackgroundAuto:
+		lightBackground = !lipgloss.HasDarkBackground()
+	case BackgroundDark:
+		lightBackground = false
+	case BackgroundLight:
+		lightBackground = true
+	default:
+		return fmt.Errorf("unknown background %q (use %s, %s or %s)", mode, BackgroundAuto, BackgroundDark, BackgroundLight)
+	}
+	applyPalette()
+	return nil
+}
+
+// applyPalette derives the rendering palette from the theme and background
+func applyPalette() {
+	palette = theme
+	if lightBackground && theme.Light != nil {
+		palette.Text, palette.Dim, palette.Accent = theme.Light.Text, theme.Light.Dim, theme.Light.Accent
+	}
+	ColorText, ColorTextDim = charmColor(palette.Text), charmColor(palette.Dim)
+}
+
+// charmColor formats a color code for Charm components ("" = terminal default)
+func charmColor(color int) string {
+	if color == colorDefault {
+		return ""
+	}
+	return strconv.Itoa(color)
+}
+
 // =============================================================================
 // Styling Functions
 // =============================================================================
 
 // fg returns foreground color escape sequence
 func fg(color int) string {
+	if color == colorDefault {
+		return "\033[39m"
+	}
 	return fmt.Sprintf("\033[38;5;%dm", color)
 }
 
 // bg returns ANSI background color escape sequence
 func bg(color int) string {
+	if color == colorDefault {
+		return "\033[49m"
+	}
 	return fmt.Sprintf("\033[48;5;%dm", color)
 }
 
@@ -131,88 +271,88 @@ func padRight(s string, width int) string {
 
 // RenderTitle renders text as a title (bold white)
 func RenderTitle(text string) string {
-	return style(text, colorWhite, true)
+	return style(text, palette.Text, true)
 }
 
 // RenderNormal renders text in normal style (white)
 func RenderNormal(text string) string {
-	return style(text, colorWhite, false)
+	return style(text, palette.Text, false)
 }
 
 // RenderSelected renders text with selection highlight (white on dark red, bold)
 func RenderSelected(text string) string {
-	return styleWithBg(text, colorWhite, colorDarkRed, true)
+	return styleWithBg(text, palette.SelectedText, palette.Selection, true)
 }
 
 // RenderSelectedWidth renders text with selection highlight at specific width
 func RenderSelectedWidth(text string, width int) string {
 	padded := padRight(text, width)
-	return styleWithBg(padded, colorWhite, colorDarkRed, true)
+	return styleWithBg(padded, palette.SelectedText, palette.Selection, true)
 }
 
 // RenderHint renders hint/help text (yellow)
 func RenderHint(text string) string {
-	return style(text, colorYellow, false)
+	return style(text, palette.Accent, false)
 }
 
 // RenderAccent renders accented text (yellow bold)
 func RenderAccent(text string) string {
-	return style(text, colorYellow, true)
+	return style(text, palette.Accent, true)
 }
 
 // RenderProgress renders progress text (dim yellow)
 func RenderProgress(text string) string {
-	return style(text, colorYellowDim, false)
+	return style(text, palette.Progress, false)
 }
 
 // RenderDim renders dimmed text (gray)
 func RenderDim(text string) string {
-	return style(text, colorGray, false)
+	return style(text, palette.Dim, false)
 }
 
 // RenderError renders error text (red bold)
 func RenderError(text string) string {
-	return style(text, colorRed, true)
+	return style(text, palette.Error, true)
 }
 
 // RenderSuccess renders success text (green bold)
 func RenderSuccess(text string) string {
-	return style(text, colorGreen, true)
+	return style(text, palette.Success, true)
 }
 
 // RenderStats renders stats text (white bold)
 func RenderStats(text string) string {
-	return style(text, colorWhite, true)
+	return style(text, palette.Text, true)
 }
 
 // RenderDivider renders divider text (yellow)
 func RenderDivider(text string) string {
-	return style(text, colorYellow, false)
+	return style(text, palette.Accent, false)
 }
 
 // RenderDividerSelected renders selected divider (yellow on dark red, bold)
 func RenderDividerSelected(text string) string {
-	return styleWithBg(text, colorYellow, colorDarkRed, true)
+	return styleWithBg(text, palette.Accent, palette.Selection, true)
 }
 
 // RenderStatusMsg renders status message (white on dark red)
 func RenderStatusMsg(text string) string {
-	return styleWithBg(" "+text+" ", colorWhite, colorDarkRed, false)
+	return styleWithBg(" "+text+" ", palette.SelectedText, palette.Selection, false)
 }
 
 // RenderTabActive renders active tab (white on dark red, bold)
 func RenderTabActive(text string) string {
-	return styleWithBg("  "+text+"  ", colorWhite, colorDarkRed, true)
+	return styleWithBg("  "+text+"  ", palette.SelectedText, palette.Selection, true)
 }
 
 // RenderTabInactive renders inactive tab (white)
 func RenderTabInactive(text string) string {
-	return "  " + style(text, colorWhite, false) + "  "
+	return "  " + style(text, palette.Text, false) + "  "
 }
 
 // RenderArrow renders arrow (red bold)
 func RenderArrow(text string) string {
-	return style(text, colorRed, true)
+	return style(text, palette.Error, true)
 }
 
 // =============================================================================
@@ -222,13 +362,13 @@ func RenderArrow(text string) string {
 // RenderPendingRow renders a row with pending selection styling
 func RenderPendingRow(row string, width int) string {
 	padded := padRight(row, width)
-	return styleWithBg(padded, colorBlack, colorYellowDim, false)
+	return styleWithBg(padded, palette.PendingText, palette.Pending, false)
 }
 
 // RenderLinkedRow renders a row with link group coloring (full width)
 func RenderLinkedRow(row string, groupID int, width int) string {
-	colorIdx := (groupID - 1) % len(LinkGroupColors)
-	color := LinkGroupColors[colorIdx]
+	colorIdx := (groupID - 1) % len(palette.LinkGroups)
+	color := palette.LinkGroups[colorIdx]
 	padded := padRight(row, width)
 	return style(padded, color, false)
 }
@@ -236,13 +376,13 @@ func RenderLinkedRow(row string, groupID int, width int) string {
 // RenderDomainRow renders a row with domain highlight coloring (yellow, full width)
 func RenderDomainRow(row string, width int) string {
 	padded := padRight(row, width)
-	return style(padded, colorYellow, false)
+	return style(padded, palette.Domain, false)
 }
 
 // RenderNormalRow renders a row with normal text coloring (white, full width)
 func RenderNormalRow(row string, width int) string {
 	padded := padRight(row, width)
-	return style(padded, colorWhite, false)
+	return style(padded, palette.Text, false)
 }
 
 // RenderSelectedRow renders a row with selection highlighting (full width)
@@ -253,7 +393,7 @@ func RenderSelectedRow(row string, width int) string {
 // RenderNormalRowWithWidth renders a row with normal text at specific width
 func RenderNormalRowWithWidth(row string, width int) string {
 	padded := padRight(row, width)
-	return style(padded, colorWhite, false)
+	return style(padded, palette.Text, false)
 }
 
 // =============================================================================
@@ -275,12 +415,12 @@ var BorderChars = struct {
 
 // RenderBorder renders content inside a colored border
 func RenderBorder(content string, width, height int) string {
-	return renderBorderWithColor(content, width, height, colorRed)
+	return renderBorderWithColor(content, width, height, palette.Border)
 }
 
 // RenderBorderWhite renders content inside a white border
 func RenderBorderWhite(content string, width, height int) string {
-	return renderBorderWithColor(content, width, height, colorWhite)
+	return renderBorderWithColor(content, width, height, palette.Text)
 }
 
 // renderBorderWithColor renders content inside a border with specified color
@@ -496,20 +636,48 @@ const (
 	ColWidthCommits = 8
 	ColWidthPercent = 7
 	ColSeparators   = 12
+
+	// Optional Signed column (see TUIModel.showSigned)
+	ColWidthSigned    = 6
+	ColWidthSignedMax = 24
+)
+
+// Optional committer table columns, which can be hidden (Tag, Rank and Commits always show)
+const (
+	ColumnName    = "name"
+	ColumnLogin   = "login"
+	ColumnEmail   = "email"
+	ColumnPercent = "percent"
 )
 
+// OptionalColumns lists the optional columns in table order
+var OptionalColumns = []string{ColumnName, ColumnLogin, ColumnEmail, ColumnPercent}
+
+// ColumnSet holds the optional columns that are shown
+type ColumnSet map[string]bool
+
+// AllColumns shows every optional column
+func AllColumns() ColumnSet {
+	set := make(ColumnSet, len(OptionalColumns))
+	for _, col := range OptionalColumns {
+		set[col] = true
+	}
+	return set
+}
+
 type ColumnWidths struct {
 	Tag     int
 	Rank    int
-	Name    int
+	Name    int // 0 when the column is hidden, as for the optional columns below
 	Login   int
 	Email   int
 	Commits int
 	Percent int
+	Signed  int // 0 when the Signed column is hidden
 }
 
 func (w ColumnWidths) Total() int {
-	return w.Tag + w.Rank + w.Name + w.Login + w.Email + w.Commits + w.Percent
+	return w.Tag + w.Rank + w.Name + w.Login + w.Email + w.Commits + w.Percent + w.Signed
 }
 
 func DefaultColumnWidths() ColumnWidths {
@@ -524,16 +692,28 @@ func DefaultColumnWidths() ColumnWidths {
 	}
 }
 
-func BuildTableColumns(widths ColumnWidths) []table.Column {
-	return []table.Column{
+func BuildTableColumns(widths ColumnWidths, visible ColumnSet) []table.Column {
+	columns := []table.Column{
 		{Title: "Tag", Width: widths.Tag},
 		{Title: "Rank", Width: widths.Rank},
-		{Title: "Name", Width: widths.Name},
-		{Title: "GitHub Login", Width: widths.Login},
-		{Title: "Email", Width: widths.Email},
-		{Title: "Commits", Width: widths.Commits},
-		{Title: "%", Width: widths.Percent},
 	}
+	if visible[ColumnName] {
+		columns = append(columns, table.Column{Title: "Name", Width: widths.Name})
+	}
+	if visible[ColumnLogin] {
+		columns = append(columns, table.Column{Title: "GitHub Login", Width: widths.Login})
+	}
+	if visible[ColumnEmail] {
+		columns = append(columns, table.Column{Title: "Email", Width: widths.Email})
+	}
+	columns = append(columns, table.Column{Title: "Commits", Width: widths.Commits})
+	if visible[ColumnPercent] {
+		columns = append(columns, table.Column{Title: "%", Width: widths.Percent})
+	}
+	if widths.Signed > 0 {
+		columns = append(columns, table.Column{Title: "Signed", Width: widths.Signed})
+	}
+	return columns
 }
 
 // =============================================================================
@@ -582,7 +762,7 @@ func GetMainTableStyles() table.Styles {
 // Note: Charm's spinner.Model.Style field requires a specific Style type
 // We cannot assign our custom types to it, so spinner styling is left as default
 // This variable exists only to prevent compilation errors in code that references it
-var SpinnerStyle = styleRenderer{render: func(s string) string { return style(s, colorRed, false) }}
+var SpinnerStyle = styleRenderer{render: func(s string) string { return style(s, palette.Border, false) }}
 
 // NewAppSpinner creates a spinner with basic styling
 func NewAppSpinner() spinner.Model {
@@ -742,6 +922,7 @@ var ReportHighlightStyle = styleRenderer{render: func(s string) string { return
 var ReportSuccessStyle = styleRenderer{render: func(s string) string { return style(s, colorGreen, true) }}
 var ReportErrorStyle = styleRenderer{render: func(s string) string { return style(s, colorRed, true) }}
 var ReportProgressStyle = styleRenderer{render: func(s string) string { return style(s, colorYellow, false) }}
+var ReportWarningStyle = styleRenderer{render: func(s string) string { return style(s, colorYellow, true) }}
 var ReportSummaryStyle = styleRenderer{render: func(s string) string { return fg(colorCyan) + codeItalic + s + codeReset }}
 
 // Row status styles
@@ -750,8 +931,8 @@ var LinkedRowStyle = styleRenderer{render: func(s string) string { return style(
 var DomainRowStyle = styleRenderer{render: func(s string) string { return style(s, colorDomain, false) }}
 
 // ProgressFilledStyle for progress bars
-var ProgressFilledStyle = styleRenderer{render: func(s string) string { return style(s, colorRed, false) }}
-var ProgressEmptyStyle = styleRenderer{render: func(s string) string { return style(s, colorGray, false) }}
+var ProgressFilledStyle = styleRenderer{render: func(s string) string { return style(s, palette.Border, false) }}
+var ProgressEmptyStyle = styleRenderer{render: func(s string) string { return style(s, palette.Dim, false) }}
 
 // =============================================================================
 // Style Renderer Types (compatibility layer)
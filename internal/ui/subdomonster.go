@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -42,25 +43,50 @@ type SubdomonsterModel struct {
 
 	// Filters
 	filterText   string
+	filterBefore string // filterText when the search prompt opened, restored on Esc
 	filterSource string
 	filterCDX    int // -1 = all, 0 = not indexed, 1 = indexed
+	filterRecent subdomonsterRecent
+
+	// When the domain was last exported; rows discovered since are marked new (zero = never)
+	lastExport time.Time
 
 	// Fetch state
 	fetching       bool
 	fetchProgress  int
-	fetchSource    string // "virustotal" or "crtsh"
-	cancelFetch    chan struct{}
+	fetchSource    string   // "virustotal", "crtsh" or "wayback"
+	fetchDomains   []string // Domains a crt.sh refresh of all tracked domains covers (nil = m.domain)
+	fetchCtx       context.Context
+	cancelFetch    context.CancelFunc
 	fetchCancelled bool
 	fetchStartTime time.Time
+	pendingReenum  string // source awaiting a second key press to re-enumerate
+
+	// VirusTotal pagination progress
+	vtCh       chan api.VTProgress
+	vtProgress api.VTProgress
+
+	// Wayback CDX enrichment state
+	waybackClient  *api.WaybackClient
+	enrichCh       chan api.EnrichProgress
+	enrichProgress api.EnrichProgress
 
 	// Domain browser state
-	cachedDomains []models.TargetDomain
-	domainCursor  int
+	cachedDomains       []models.TargetDomain
+	domainCursor        int
+	pendingDeleteDomain string // domain awaiting delete confirmation ("" = none)
+	pendingDeleteCount  int    // subdomains that delete would remove
 
 	// Pagination
 	page     int
 	pageSize int
 
+	// Snapshot comparison state
+	snapshots      []models.SubdomainSnapshot // Saved snapshots of the domain, newest first
+	snapshotCursor int                        // Snapshot the diff view compares against
+	snapshotDiff   *models.SubdomainDiff
+	diffScroll     int
+
 	// Settings
 	vtAPIKey        string
 	settingsCursor  int
@@ -84,8 +110,21 @@ const (
 	subdomonsterViewTable                                // Table view of subdomains
 	subdomonsterViewFilter                               // Filter input overlay
 	subdomonsterViewSettings                             // API key settings
+	subdomonsterViewDiff                                 // Changes since a saved snapshot
 )
 
+// subdomonsterRecent limits the table to recently discovered subdomains, newest first
+type subdomonsterRecent int
+
+const (
+	subdomonsterRecentOff     subdomonsterRecent = iota // All subdomains
+	subdomonsterRecentLastRun                           // Found by the latest enumeration run
+	subdomonsterRecentLastDay                           // Discovered in the last subdomonsterRecentWindow
+)
+
+// subdomonsterRecentWindow is how far back the "last N hours" recent filter looks
+const subdomonsterRecentWindow = 24 * time.Hour
+
 type subdomonsterInputMode int
 
 const (
@@ -95,16 +134,37 @@ const (
 )
 
 // Messages
+
+// subdomonsterFetchProgressMsg reports VirusTotal pagination progress after each page
 type subdomonsterFetchProgressMsg struct {
-	count int
+	progress api.VTProgress
 }
 
 type subdomonsterFetchCompleteMsg struct {
 	subdomains []models.Subdomain
 	source     string
+	cursor     string // VirusTotal cursor to resume from (empty when complete)
 	err        error
 }
 
+// subdomonsterCrtshBatchCompleteMsg carries a crt.sh refresh of several domains
+type subdomonsterCrtshBatchCompleteMsg struct {
+	domains    []string
+	subdomains []models.Subdomain
+	errs       map[string]error // Per-domain failures; the other domains completed
+}
+
+// subdomonsterEnrichProgressMsg reports which subdomain the CDX enrichment is on
+type subdomonsterEnrichProgressMsg struct {
+	progress api.EnrichProgress
+}
+
+type subdomonsterEnrichCompleteMsg struct {
+	result api.EnrichResult
+	total  int // Unindexed subdomains the run started with
+	err    error
+}
+
 type subdomonsterDomainsLoadedMsg struct {
 	domains []models.TargetDomain
 	err     error
@@ -113,6 +173,8 @@ type subdomonsterDomainsLoadedMsg struct {
 type subdomonsterSubdomainsLoadedMsg struct {
 	subdomains []models.Subdomain
 	total      int
+	filterText string    // search text the page was loaded for, to drop stale live-filter results
+	lastExport time.Time // when the domain was last exported
 	err        error
 }
 
@@ -147,25 +209,31 @@ func NewSubdomonsterModel(logger *log.Logger, database *db.DB) SubdomonsterModel
 	prog.EmptyColor = "241"
 
 	return SubdomonsterModel{
-		client:    api.NewSubdomainClient("", nil),
-		logger:    logger,
-		database:  database,
-		layout:    layout,
-		table:     t,
-		textInput: ti,
-		spinner:   spinnerModel,
-		progress:  prog,
-		viewMode:  subdomonsterViewInput,
-		inputMode: subdomonsterInputDomain,
-		filterCDX: -1, // Show all by default
-		page:      1,
-		pageSize:  100,
+		client:        api.NewSubdomainClient("", logger),
+		waybackClient: api.NewWaybackClient(logger),
+		logger:        logger,
+		database:      database,
+		layout:        layout,
+		table:         t,
+		textInput:     ti,
+		spinner:       spinnerModel,
+		progress:      prog,
+		viewMode:      subdomonsterViewInput,
+		inputMode:     subdomonsterInputDomain,
+		filterCDX:     -1, // Show all by default
+		page:          1,
+		pageSize:      100,
 	}
 }
 
 // Init implements tea.Model
 func (m SubdomonsterModel) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink, m.spinner.Tick, m.loadCachedDomains(), m.loadAPIKey())
+	cmds := []tea.Cmd{textinput.Blink, m.spinner.Tick, m.loadCachedDomains(), m.loadAPIKey()}
+	if m.domain != "" {
+		// Opened on a domain, e.g. from global search
+		cmds = append(cmds, m.loadSubdomainsFromDB())
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model
@@ -211,18 +279,32 @@ func (m SubdomonsterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case subdomonsterFetchProgressMsg:
-		m.fetchProgress = msg.count
-		return m, nil
+		m.vtProgress = msg.progress
+		m.fetchProgress = msg.progress.Subdomains
+		return m, waitForVTProgress(m.vtCh)
 
 	case subdomonsterFetchCompleteMsg:
 		m.fetching = false
+		if m.cancelFetch != nil {
+			m.cancelFetch() // Release the fetch context
+		}
 		if msg.err != nil {
+			// Keep partial results and remember where VT stopped so the next run resumes there
+			if m.database != nil && len(msg.subdomains) > 0 {
+				m.database.InsertSubdomains(msg.subdomains)
+			}
+			if m.database != nil && msg.source == "virustotal" && msg.cursor != "" {
+				m.database.SetVTCursor(m.domain, msg.cursor)
+			}
 			if msg.err.Error() == "cancelled" {
 				m.statusMsg = fmt.Sprintf("Fetch cancelled. Got %d subdomains.", len(msg.subdomains))
 			} else {
 				m.err = msg.err
 				m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
 			}
+			if msg.source == "virustotal" && msg.cursor != "" {
+				m.statusMsg += " Press v to resume, V to restart."
+			}
 		} else {
 			// Insert subdomains into database
 			if m.database != nil && len(msg.subdomains) > 0 {
@@ -233,20 +315,92 @@ func (m SubdomonsterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.loadSubdomainsFromDB()
 				}
 				m.statusMsg = fmt.Sprintf("Found %d subdomains (%d new) via %s", len(msg.subdomains), inserted, msg.source)
+			} else if len(msg.subdomains) == 0 {
+				m.statusMsg = fmt.Sprintf("No subdomains found via %s", msg.source)
+			}
 
-				// Mark domain as enumerated
+			// Mark domain as enumerated - a completed run with no results still counts
+			if m.database != nil {
 				switch msg.source {
 				case "virustotal":
 					m.database.MarkVTEnumerated(m.domain)
+					m.database.SetVTCursor(m.domain, "")
 				case "crtsh":
 					m.database.MarkCrtshEnumerated(m.domain)
 				}
-			} else if len(msg.subdomains) == 0 {
-				m.statusMsg = fmt.Sprintf("No subdomains found via %s", msg.source)
+				if stats, err := m.database.GetSubdomainStats(m.domain); err == nil {
+					m.statusMsg += fmt.Sprintf(". %s now has %s subdomains", m.domain, stats.Summary())
+				}
 			}
 		}
 		return m, m.loadSubdomainsFromDB()
 
+	case subdomonsterCrtshBatchCompleteMsg:
+		m.fetching = false
+		m.fetchDomains = nil
+		if m.cancelFetch != nil {
+			m.cancelFetch()
+		}
+		if m.viewMode == subdomonsterViewFetching {
+			m.viewMode = subdomonsterViewDomains
+		}
+		inserted := 0
+		if m.database != nil && len(msg.subdomains) > 0 {
+			n, err := m.database.InsertSubdomains(msg.subdomains)
+			if err != nil {
+				m.err = err
+				m.statusMsg = fmt.Sprintf("DB error inserting subdomains: %v", err)
+				return m, m.loadCachedDomains()
+			}
+			inserted = n
+		}
+		var failed []string
+		for _, domain := range msg.domains {
+			if _, ok := msg.errs[domain]; ok {
+				failed = append(failed, domain)
+				continue
+			}
+			if m.database != nil {
+				m.database.MarkCrtshEnumerated(domain)
+			}
+		}
+		m.statusMsg = fmt.Sprintf("crt.sh refresh: %d subdomains (%d new) across %d/%d domains",
+			len(msg.subdomains), inserted, len(msg.domains)-len(failed), len(msg.domains))
+		if len(failed) > 0 {
+			m.statusMsg += fmt.Sprintf(". Failed: %s", strings.Join(failed, ", "))
+		}
+		if m.fetchCancelled {
+			m.statusMsg = "Refresh cancelled. " + m.statusMsg
+		}
+		return m, m.loadCachedDomains()
+
+	case subdomonsterEnrichProgressMsg:
+		m.enrichProgress = msg.progress
+		m.fetchProgress = msg.progress.Records
+		return m, waitForEnrichProgress(m.enrichCh)
+
+	case subdomonsterEnrichCompleteMsg:
+		m.fetching = false
+		if m.cancelFetch != nil {
+			m.cancelFetch()
+		}
+		summary := fmt.Sprintf("Wayback CDX: indexed %d/%d subdomains (%d records)", msg.result.Indexed, msg.total, msg.result.Records)
+		if msg.result.Failed > 0 {
+			summary += fmt.Sprintf(", %d failed", msg.result.Failed)
+		}
+		switch {
+		case msg.err != nil && msg.err.Error() == "cancelled":
+			m.statusMsg = "Enrichment cancelled. " + summary
+		case msg.err != nil:
+			m.err = msg.err
+			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
+		case msg.total == 0:
+			m.statusMsg = "All subdomains are already CDX indexed"
+		default:
+			m.statusMsg = summary
+		}
+		return m, m.loadSubdomainsFromDB()
+
 	case subdomonsterDomainsLoadedMsg:
 		if msg.err != nil {
 			if m.viewMode != subdomonsterViewInput {
@@ -263,11 +417,23 @@ func (m SubdomonsterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, nil
 		}
+		if msg.filterText != m.filterText {
+			return m, nil // superseded by a later keystroke
+		}
 		m.subdomains = msg.subdomains
 		m.totalSubdomains = msg.total
-		m.sortSubdomainsTree()
+		m.lastExport = msg.lastExport
+		if m.filterRecent == subdomonsterRecentOff {
+			m.sortSubdomainsTree()
+		} else {
+			// Keep the newest-first order the recent filter loads in
+			m.sortedSubdomains = m.subdomains
+		}
 		m.updateTable()
-		m.viewMode = subdomonsterViewTable
+		// Live filtering reloads while typing; stay in the filter prompt
+		if m.viewMode != subdomonsterViewFilter {
+			m.viewMode = subdomonsterViewTable
+		}
 		return m, nil
 
 	case subdomonsterAPIKeyLoadedMsg:
@@ -285,6 +451,11 @@ func (m SubdomonsterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m SubdomonsterModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A re-run confirmation only holds for the very next key
+	if m.pendingReenum != "" && !m.isEnumerationKey(msg.String()) {
+		m.pendingReenum = ""
+	}
+
 	switch m.viewMode {
 	case subdomonsterViewInput:
 		return m.handleInputKeys(msg)
@@ -298,11 +469,24 @@ func (m SubdomonsterModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleFilterKeys(msg)
 	case subdomonsterViewSettings:
 		return m.handleSettingsKeys(msg)
+	case subdomonsterViewDiff:
+		return m.handleDiffKeys(msg)
 	default:
 		return m, nil
 	}
 }
 
+// isEnumerationKey reports whether key starts an enumeration in the current view
+func (m SubdomonsterModel) isEnumerationKey(key string) bool {
+	switch m.viewMode {
+	case subdomonsterViewInput:
+		return key == "alt+v" || key == "alt+c"
+	case subdomonsterViewTable:
+		return key == "v" || key == "V" || key == "c"
+	}
+	return false
+}
+
 func (m SubdomonsterModel) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "esc", "q":
@@ -339,61 +523,22 @@ func (m SubdomonsterModel) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		m.settingsInput = m.vtAPIKey
 		return m, nil
 
-	case "v":
-		// Enumerate via VirusTotal directly from input view
+	case "alt+v", "alt+c":
+		// Enumerate directly from the input view (alt, so v and c can still be typed)
 		if m.textInput.Value() == "" {
 			m.statusMsg = "Enter a domain first"
 			return m, nil
 		}
-		if !m.client.HasVirusTotalAPIKey() {
-			// Prompt for API key
-			m.viewMode = subdomonsterViewSettings
-			m.settingsEditing = true
-			m.settingsInput = ""
-			m.statusMsg = "Enter your VirusTotal API key:"
-			return m, nil
-		}
 		// Set domain and ensure it exists in database
-		domain := strings.ToLower(strings.TrimSpace(m.textInput.Value()))
-		m.domain = domain
+		m.domain = strings.ToLower(strings.TrimSpace(m.textInput.Value()))
 		m.err = nil
 		if m.database != nil {
-			m.database.InsertTargetDomain(domain)
+			m.database.InsertTargetDomain(m.domain)
 		}
-		// Start VirusTotal fetch
-		m.viewMode = subdomonsterViewFetching
-		m.fetching = true
-		m.fetchProgress = 0
-		m.fetchSource = "virustotal"
-		m.fetchCancelled = false
-		m.cancelFetch = make(chan struct{})
-		m.fetchStartTime = time.Now()
-		m.statusMsg = "Fetching subdomains from VirusTotal..."
-		return m, tea.Batch(m.progress.SetPercent(0.0), m.doVirusTotalFetch())
-
-	case "c":
-		// Enumerate via crt.sh directly from input view
-		if m.textInput.Value() == "" {
-			m.statusMsg = "Enter a domain first"
-			return m, nil
+		if msg.String() == "alt+c" {
+			return m.startEnumeration("crtsh", msg.String(), false)
 		}
-		// Set domain and ensure it exists in database
-		domain := strings.ToLower(strings.TrimSpace(m.textInput.Value()))
-		m.domain = domain
-		m.err = nil
-		if m.database != nil {
-			m.database.InsertTargetDomain(domain)
-		}
-		// Start crt.sh fetch
-		m.viewMode = subdomonsterViewFetching
-		m.fetching = true
-		m.fetchProgress = 0
-		m.fetchSource = "crtsh"
-		m.fetchCancelled = false
-		m.cancelFetch = make(chan struct{})
-		m.fetchStartTime = time.Now()
-		m.statusMsg = "Fetching subdomains from crt.sh..."
-		return m, tea.Batch(m.progress.SetPercent(0.0), m.doCrtshFetch())
+		return m.startEnumeration("virustotal", msg.String(), true)
 
 	default:
 		var cmd tea.Cmd
@@ -402,7 +547,88 @@ func (m SubdomonsterModel) handleInputKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	}
 }
 
+// startEnumeration fetches m.domain's subdomains from source ("virustotal" or "crtsh").
+// A source the domain was already enumerated with needs key pressed twice, so an accidental
+// re-run (and its API quota) is skipped; resume continues an interrupted VirusTotal run.
+func (m SubdomonsterModel) startEnumeration(source, key string, resume bool) (tea.Model, tea.Cmd) {
+	if source == "virustotal" && !m.client.HasVirusTotalAPIKey() {
+		// Prompt for API key
+		m.viewMode = subdomonsterViewSettings
+		m.settingsEditing = true
+		m.settingsInput = ""
+		m.statusMsg = "Enter your VirusTotal API key:"
+		return m, nil
+	}
+
+	if m.pendingReenum != source && m.alreadyEnumerated(source) {
+		m.pendingReenum = source
+		m.statusMsg = fmt.Sprintf("%s was already enumerated via %s. Press %s again to re-run.", m.domain, sourceLabel(source), key)
+		return m, nil
+	}
+	m.pendingReenum = ""
+
+	m.viewMode = subdomonsterViewFetching
+	m.fetching = true
+	m.fetchProgress = 0
+	m.fetchSource = source
+	m.fetchCancelled = false
+	m.fetchCtx, m.cancelFetch = context.WithCancel(context.Background())
+	m.fetchStartTime = time.Now()
+	m.statusMsg = fmt.Sprintf("Fetching subdomains from %s...", sourceLabel(source))
+	if source == "crtsh" {
+		return m, tea.Batch(m.progress.SetPercent(0.0), m.doCrtshFetch())
+	}
+	m.vtProgress = api.VTProgress{}
+	m.vtCh = make(chan api.VTProgress)
+	return m, tea.Batch(m.progress.SetPercent(0.0), m.doVirusTotalFetch(resume, m.vtCh), waitForVTProgress(m.vtCh))
+}
+
+// alreadyEnumerated reports whether m.domain has completed an enumeration via source
+func (m SubdomonsterModel) alreadyEnumerated(source string) bool {
+	if m.database == nil {
+		return false
+	}
+	target, err := m.database.GetTargetDomain(m.domain)
+	if err != nil || target == nil {
+		return false
+	}
+	switch source {
+	case "virustotal":
+		return target.VTEnumerated
+	case "crtsh":
+		return target.CrtshEnumerated
+	}
+	return false
+}
+
+// sourceLabel returns the display name of an enumeration source
+func sourceLabel(source string) string {
+	switch source {
+	case "virustotal":
+		return "VirusTotal"
+	case "crtsh":
+		return "crt.sh"
+	}
+	return source
+}
+
 func (m SubdomonsterModel) handleDomainsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Delete confirmation - only y deletes, any other key cancels
+	if m.pendingDeleteDomain != "" {
+		domain := m.pendingDeleteDomain
+		m.pendingDeleteDomain = ""
+		if msg.String() != "y" && msg.String() != "Y" {
+			m.statusMsg = "Delete cancelled"
+			return m, nil
+		}
+		if err := m.database.DeleteTargetDomain(domain); err != nil {
+			m.statusMsg = fmt.Sprintf("Delete error: %v", err)
+			return m, nil
+		}
+		m.statusMsg = fmt.Sprintf("Deleted domain: %s (%s subdomains)", domain, formatCount(m.pendingDeleteCount))
+		return m, m.loadCachedDomains()
+	}
+
 	switch msg.String() {
 	case "esc":
 		m.viewMode = subdomonsterViewInput
@@ -429,16 +655,38 @@ func (m SubdomonsterModel) handleDomainsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd
 		}
 
 	case "d", "D":
-		// Delete selected domain
+		// Ask before deleting the selected domain, showing how many subdomains go with it
 		if len(m.cachedDomains) > 0 && m.domainCursor < len(m.cachedDomains) && m.database != nil {
 			domain := m.cachedDomains[m.domainCursor].Domain
-			if err := m.database.DeleteTargetDomain(domain); err != nil {
+			count, err := m.database.DeleteTargetDomainPreview(domain)
+			if err != nil {
 				m.statusMsg = fmt.Sprintf("Delete error: %v", err)
-			} else {
-				m.statusMsg = fmt.Sprintf("Deleted domain: %s", domain)
-				return m, m.loadCachedDomains()
+				return m, nil
 			}
+			m.pendingDeleteDomain = domain
+			m.pendingDeleteCount = count
+			m.statusMsg = ""
+		}
+
+	case "C":
+		// Refresh every tracked domain from crt.sh, several queries at a time
+		if len(m.cachedDomains) == 0 {
+			return m, nil
+		}
+		domains := make([]string, len(m.cachedDomains))
+		for i, d := range m.cachedDomains {
+			domains[i] = d.Domain
 		}
+		m.viewMode = subdomonsterViewFetching
+		m.fetching = true
+		m.fetchProgress = 0
+		m.fetchSource = "crtsh"
+		m.fetchDomains = domains
+		m.fetchCancelled = false
+		m.fetchCtx, m.cancelFetch = context.WithCancel(context.Background())
+		m.fetchStartTime = time.Now()
+		m.statusMsg = fmt.Sprintf("Refreshing %d domains from crt.sh...", len(domains))
+		return m, m.doCrtshFetchAll(domains)
 
 	case "a", "A":
 		// Add new domain - go to input view
@@ -455,10 +703,15 @@ func (m SubdomonsterModel) handleFetchingKeys(msg tea.KeyMsg) (tea.Model, tea.Cm
 	case "esc", "q":
 		// Cancel fetch
 		if !m.fetchCancelled && m.cancelFetch != nil {
-			close(m.cancelFetch)
+			m.cancelFetch()
 			m.fetchCancelled = true
 		}
 		m.fetching = false
+		if m.fetchDomains != nil {
+			// The batch result still arrives and stores the domains that completed
+			m.viewMode = subdomonsterViewDomains
+			return m, m.loadCachedDomains()
+		}
 		return m, m.loadSubdomainsFromDB()
 	}
 	return m, nil
@@ -480,37 +733,28 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		m.table.MoveDown(1)
 		return m, nil
 
-	case "v":
-		// Enumerate via VirusTotal
-		if !m.client.HasVirusTotalAPIKey() {
-			// Prompt for API key
-			m.viewMode = subdomonsterViewSettings
-			m.settingsEditing = true
-			m.settingsInput = ""
-			m.statusMsg = "Enter your VirusTotal API key:"
+	case "enter", "y":
+		// Open the selected subdomain in the browser, at the URL probing ended on if known
+		// (y copies the URL instead)
+		cursor := m.table.Cursor()
+		if cursor < 0 || cursor >= len(m.sortedSubdomains) {
 			return m, nil
 		}
-		m.viewMode = subdomonsterViewFetching
-		m.fetching = true
-		m.fetchProgress = 0
-		m.fetchSource = "virustotal"
-		m.fetchCancelled = false
-		m.cancelFetch = make(chan struct{})
-		m.fetchStartTime = time.Now()
-		m.statusMsg = "Fetching subdomains from VirusTotal..."
-		return m, tea.Batch(m.progress.SetPercent(0.0), m.doVirusTotalFetch())
+		target := subdomainURL(m.sortedSubdomains[cursor])
+		if msg.String() == "y" {
+			m.statusMsg = copyURL(target, target)
+		} else {
+			m.statusMsg = openOrCopyURL(target, target)
+		}
+		return m, nil
+
+	case "v", "V":
+		// Enumerate via VirusTotal (v resumes an interrupted run, V restarts)
+		return m.startEnumeration("virustotal", msg.String(), msg.String() == "v")
 
 	case "c":
 		// Enumerate via crt.sh
-		m.viewMode = subdomonsterViewFetching
-		m.fetching = true
-		m.fetchProgress = 0
-		m.fetchSource = "crtsh"
-		m.fetchCancelled = false
-		m.cancelFetch = make(chan struct{})
-		m.fetchStartTime = time.Now()
-		m.statusMsg = "Fetching subdomains from crt.sh..."
-		return m, tea.Batch(m.progress.SetPercent(0.0), m.doCrtshFetch())
+		return m.startEnumeration("crtsh", msg.String(), false)
 
 	case "i":
 		// Import JSON file
@@ -518,24 +762,27 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m, nil
 
 	case "W":
-		// Forward to Wayback (mark as CDX indexed - roadmap placeholder)
-		if len(m.sortedSubdomains) > 0 {
-			cursor := m.table.Cursor()
-			if cursor >= 0 && cursor < len(m.sortedSubdomains) {
-				subdomain := m.sortedSubdomains[cursor].Subdomain
-				if m.database != nil {
-					m.database.MarkSubdomainCDXIndexed(subdomain)
-					m.statusMsg = fmt.Sprintf("Marked %s for Wayback processing (roadmap feature)", subdomain)
-					return m, m.loadSubdomainsFromDB()
-				}
-			}
+		// Enrich every subdomain not yet CDX indexed with its Wayback records
+		if m.database == nil {
+			return m, nil
 		}
-		return m, nil
+		m.viewMode = subdomonsterViewFetching
+		m.fetching = true
+		m.fetchProgress = 0
+		m.fetchSource = "wayback"
+		m.fetchCancelled = false
+		m.fetchCtx, m.cancelFetch = context.WithCancel(context.Background())
+		m.fetchStartTime = time.Now()
+		m.enrichProgress = api.EnrichProgress{}
+		m.enrichCh = make(chan api.EnrichProgress)
+		m.statusMsg = "Fetching Wayback CDX records for unindexed subdomains..."
+		return m, tea.Batch(m.doCDXEnrich(m.enrichCh), waitForEnrichProgress(m.enrichCh))
 
 	case "/":
 		// Enter filter mode
 		m.viewMode = subdomonsterViewFilter
 		m.inputMode = subdomonsterInputFilter
+		m.filterBefore = m.filterText
 		m.textInput.SetValue(m.filterText)
 		m.textInput.Placeholder = "Filter by subdomain..."
 		m.textInput.Focus()
@@ -549,8 +796,14 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		case "virustotal":
 			m.filterSource = "crtsh"
 		case "crtsh":
+			m.filterSource = "securitytrails"
+		case "securitytrails":
+			m.filterSource = "censys"
+		case "censys":
 			m.filterSource = "import"
 		case "import":
+			m.filterSource = "rdns"
+		case "rdns":
 			m.filterSource = ""
 		}
 		m.page = 1
@@ -581,11 +834,60 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		}
 		return m, m.loadSubdomainsFromDB()
 
+	case "S":
+		// Snapshot the current subdomains for later comparison
+		if m.database == nil {
+			return m, nil
+		}
+		snapshot, err := m.database.SaveSubdomainSnapshot(m.domain, "")
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Snapshot error: %v", err)
+		} else {
+			m.statusMsg = fmt.Sprintf("Saved snapshot %s (%d subdomains) - press D to compare against it", snapshot.Label, snapshot.Count)
+		}
+		return m, nil
+
+	case "D":
+		// Compare the current subdomains with the newest snapshot
+		if m.database == nil {
+			return m, nil
+		}
+		snapshots, err := m.database.GetSubdomainSnapshots(m.domain)
+		if err != nil {
+			m.statusMsg = fmt.Sprintf("Snapshot error: %v", err)
+			return m, nil
+		}
+		if len(snapshots) == 0 {
+			m.statusMsg = "No snapshots of this domain yet - press S to save one"
+			return m, nil
+		}
+		m.snapshots = snapshots
+		m.showSnapshotDiff(0)
+		m.viewMode = subdomonsterViewDiff
+		return m, nil
+
+	case "N":
+		// Cycle recently discovered filter
+		switch m.filterRecent {
+		case subdomonsterRecentOff:
+			m.filterRecent = subdomonsterRecentLastRun
+			m.statusMsg = "Filter: discovered in the last run, newest first"
+		case subdomonsterRecentLastRun:
+			m.filterRecent = subdomonsterRecentLastDay
+			m.statusMsg = fmt.Sprintf("Filter: discovered in the last %s, newest first", formatRecentWindow(subdomonsterRecentWindow))
+		default:
+			m.filterRecent = subdomonsterRecentOff
+			m.statusMsg = "Filter: showing all discovery times"
+		}
+		m.page = 1
+		return m, m.loadSubdomainsFromDB()
+
 	case "r":
 		// Clear filters and reload
 		m.filterText = ""
 		m.filterSource = ""
 		m.filterCDX = -1
+		m.filterRecent = subdomonsterRecentOff
 		m.page = 1
 		m.statusMsg = "Filters cleared"
 		return m, m.loadSubdomainsFromDB()
@@ -594,10 +896,20 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		// Export to markdown
 		if len(m.sortedSubdomains) > 0 {
 			filename := fmt.Sprintf("subdomains-%s-%s.md", m.domain, time.Now().Format("20060102-150405"))
+			exportedAt := time.Now()
 			if err := m.exportToMarkdown(filename); err != nil {
 				m.statusMsg = fmt.Sprintf("Export error: %v", err)
 			} else {
 				m.statusMsg = fmt.Sprintf("Exported to %s", filename)
+				// Later finds are marked new against this export
+				if m.database != nil {
+					if err := m.database.SetLastSubdomainExport(m.domain, exportedAt); err != nil {
+						m.statusMsg += fmt.Sprintf(" (export time not saved: %v)", err)
+					} else {
+						m.lastExport = exportedAt.Truncate(time.Second)
+						m.updateTable()
+					}
+				}
 			}
 		}
 		return m, nil
@@ -647,21 +959,31 @@ func (m SubdomonsterModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 func (m SubdomonsterModel) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
-		m.filterText = m.textInput.Value()
-		m.page = 1
 		m.viewMode = subdomonsterViewTable
 		m.textInput.Placeholder = "Enter domain (e.g., example.com)"
-		return m, m.loadSubdomainsFromDB()
+		return m, nil
 
 	case "esc":
+		// Restore the filter that was active before the prompt opened
 		m.viewMode = subdomonsterViewTable
 		m.textInput.Placeholder = "Enter domain (e.g., example.com)"
-		return m, nil
+		if m.filterText == m.filterBefore {
+			return m, nil
+		}
+		m.filterText = m.filterBefore
+		m.page = 1
+		return m, m.loadSubdomainsFromDB()
 
 	default:
+		// Filter live as the text changes
 		var cmd tea.Cmd
 		m.textInput, cmd = m.textInput.Update(msg)
-		return m, cmd
+		if m.textInput.Value() == m.filterText {
+			return m, cmd
+		}
+		m.filterText = m.textInput.Value()
+		m.page = 1
+		return m, tea.Batch(cmd, m.loadSubdomainsFromDB())
 	}
 }
 
@@ -752,6 +1074,8 @@ func (m SubdomonsterModel) View() string {
 		viewContent = m.renderFilterView()
 	case subdomonsterViewSettings:
 		viewContent = m.renderSettingsView()
+	case subdomonsterViewDiff:
+		viewContent = m.renderDiffView()
 	}
 
 	builder.CustomContent(viewContent)
@@ -787,7 +1111,7 @@ func (m SubdomonsterModel) renderInputView() string {
 		}
 		for i := 0; i < maxShow; i++ {
 			d := m.cachedDomains[i]
-			line := fmt.Sprintf("   %s (%d subdomains)", d.Domain, d.SubdomainCount)
+			line := fmt.Sprintf("   %s: %s subdomains", d.Domain, d.CountSummary())
 			b.WriteString(NormalStyle.Render(line))
 			b.WriteString("\n")
 		}
@@ -821,7 +1145,10 @@ func (m SubdomonsterModel) renderDomainsView() string {
 	normalStyle := NormalStyle.Width(m.layout.InnerWidth)
 
 	for i, d := range m.cachedDomains {
-		line := fmt.Sprintf("%s (%d subdomains)", d.Domain, d.SubdomainCount)
+		line := fmt.Sprintf("%s: %s subdomains", d.Domain, d.CountSummary())
+		if done := d.EnumeratedSummary(); done != "" {
+			line += " " + done
+		}
 		if i == m.domainCursor {
 			b.WriteString(selectedStyle.Render("> " + line))
 		} else {
@@ -830,6 +1157,15 @@ func (m SubdomonsterModel) renderDomainsView() string {
 		b.WriteString("\n")
 	}
 
+	if m.pendingDeleteDomain != "" {
+		b.WriteString("\n")
+		b.WriteString(AccentStyle.Render(fmt.Sprintf(" Delete %s? This will delete %s subdomains and cannot be undone. (y/n)",
+			m.pendingDeleteDomain, formatCount(m.pendingDeleteCount))))
+	} else if m.statusMsg != "" {
+		b.WriteString("\n")
+		b.WriteString(NormalStyle.Render(" " + m.statusMsg))
+	}
+
 	return b.String()
 }
 
@@ -837,11 +1173,34 @@ func (m SubdomonsterModel) renderFetchingView() string {
 	var b strings.Builder
 	b.WriteString(m.spinner.View())
 	b.WriteString(" ")
-	b.WriteString(AccentStyle.Render(fmt.Sprintf("Fetching subdomains for %s via %s...", m.domain, m.fetchSource)))
-	b.WriteString("\n\n")
+	if m.fetchSource == "wayback" {
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Enriching %s subdomains via Wayback CDX...", m.domain)))
+		b.WriteString("\n\n")
 
-	b.WriteString(NormalStyle.Render(fmt.Sprintf(" Subdomains found: %d", m.fetchProgress)))
-	b.WriteString("\n")
+		if p := m.enrichProgress; p.Total > 0 {
+			b.WriteString(NormalStyle.Render(fmt.Sprintf(" Subdomain %d/%d: %s", p.Index, p.Total, p.Subdomain)))
+			b.WriteString("\n")
+		}
+		b.WriteString(NormalStyle.Render(fmt.Sprintf(" Records stored: %d", m.fetchProgress)))
+		b.WriteString("\n")
+	} else if len(m.fetchDomains) > 0 {
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Fetching subdomains for %d domains via %s...", len(m.fetchDomains), m.fetchSource)))
+		b.WriteString("\n\n")
+	} else {
+		b.WriteString(AccentStyle.Render(fmt.Sprintf("Fetching subdomains for %s via %s...", m.domain, m.fetchSource)))
+		b.WriteString("\n\n")
+
+		b.WriteString(NormalStyle.Render(fmt.Sprintf(" Subdomains found: %d", m.fetchProgress)))
+		b.WriteString("\n")
+		if p := m.vtProgress; m.fetchSource == "virustotal" && p.Pages > 0 {
+			rate := fmt.Sprintf(" Pages: %d (%.1f/min), next in %s", p.Pages, p.PagesPerMinute, p.Delay.Round(time.Millisecond))
+			if p.Remaining >= 0 {
+				rate += fmt.Sprintf(", %d requests left in quota", p.Remaining)
+			}
+			b.WriteString(NormalStyle.Render(rate))
+			b.WriteString("\n")
+		}
+	}
 
 	// Elapsed time
 	if !m.fetchStartTime.IsZero() {
@@ -864,7 +1223,7 @@ func (m SubdomonsterModel) renderFetchingView() string {
 func (m SubdomonsterModel) renderTableView() string {
 	// Build query info
 	queryInfo := fmt.Sprintf(" Domain: %s", m.domain)
-	if m.filterText != "" || m.filterSource != "" || m.filterCDX != -1 {
+	if m.filterText != "" || m.filterSource != "" || m.filterCDX != -1 || m.filterRecent != subdomonsterRecentOff {
 		queryInfo += "  |  Filters:"
 		if m.filterText != "" {
 			queryInfo += fmt.Sprintf(" '%s'", m.filterText)
@@ -878,6 +1237,15 @@ func (m SubdomonsterModel) renderTableView() string {
 		case 1:
 			queryInfo += " CDX=yes"
 		}
+		switch m.filterRecent {
+		case subdomonsterRecentLastRun:
+			queryInfo += " new=last run"
+		case subdomonsterRecentLastDay:
+			queryInfo += " new=" + formatRecentWindow(subdomonsterRecentWindow)
+		}
+	}
+	if !m.lastExport.IsZero() {
+		queryInfo += fmt.Sprintf("  |  + new since export %s", m.lastExport.Local().Format("2006-01-02 15:04"))
 	}
 	maxPage := (m.totalSubdomains + m.pageSize - 1) / m.pageSize
 	if maxPage < 1 {
@@ -895,6 +1263,102 @@ func (m SubdomonsterModel) renderTableView() string {
 	return builder.BuildContent()
 }
 
+// showSnapshotDiff compares the current subdomains with snapshots[i]
+func (m *SubdomonsterModel) showSnapshotDiff(i int) {
+	m.snapshotCursor = i
+	m.diffScroll = 0
+	m.snapshotDiff = nil
+	diff, err := m.database.DiffSubdomainSnapshot(m.domain, m.snapshots[i].Label)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Snapshot error: %v", err)
+		return
+	}
+	m.snapshotDiff = diff
+	m.statusMsg = ""
+}
+
+func (m SubdomonsterModel) handleDiffKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.viewMode = subdomonsterViewTable
+		m.snapshotDiff = nil
+		return m, nil
+	case "up", "k":
+		if m.diffScroll > 0 {
+			m.diffScroll--
+		}
+	case "down", "j":
+		if m.diffScroll < len(m.diffLines())-1 {
+			m.diffScroll++
+		}
+	case "left", "h":
+		if m.snapshotCursor < len(m.snapshots)-1 {
+			m.showSnapshotDiff(m.snapshotCursor + 1)
+		}
+	case "right", "l":
+		if m.snapshotCursor > 0 {
+			m.showSnapshotDiff(m.snapshotCursor - 1)
+		}
+	case "x":
+		label := m.snapshots[m.snapshotCursor].Label
+		if err := m.database.DeleteSubdomainSnapshot(m.domain, label); err != nil {
+			m.statusMsg = fmt.Sprintf("Snapshot error: %v", err)
+			return m, nil
+		}
+		m.snapshots = append(m.snapshots[:m.snapshotCursor:m.snapshotCursor], m.snapshots[m.snapshotCursor+1:]...)
+		if len(m.snapshots) == 0 {
+			m.viewMode = subdomonsterViewTable
+			m.snapshotDiff = nil
+			m.statusMsg = fmt.Sprintf("Deleted snapshot %s", label)
+			return m, nil
+		}
+		m.showSnapshotDiff(min(m.snapshotCursor, len(m.snapshots)-1))
+		m.statusMsg = fmt.Sprintf("Deleted snapshot %s", label)
+	}
+	return m, nil
+}
+
+// diffLines renders the snapshot diff as one line per added ("+") or removed ("-") subdomain
+func (m SubdomonsterModel) diffLines() []string {
+	if m.snapshotDiff == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(m.snapshotDiff.Added)+len(m.snapshotDiff.Removed))
+	for _, name := range m.snapshotDiff.Added {
+		lines = append(lines, RenderAccent(" + "+name))
+	}
+	for _, name := range m.snapshotDiff.Removed {
+		lines = append(lines, RenderDim(" - "+name))
+	}
+	return lines
+}
+
+func (m SubdomonsterModel) renderDiffView() string {
+	var b strings.Builder
+	if len(m.snapshots) > 0 {
+		snap := m.snapshots[m.snapshotCursor]
+		b.WriteString(NormalStyle.Render(fmt.Sprintf(" Domain: %s  |  Snapshot %d/%d: %s (%d subdomains, saved %s)",
+			m.domain, m.snapshotCursor+1, len(m.snapshots), snap.Label, snap.Count, snap.CreatedAt.Local().Format("2006-01-02 15:04"))))
+		b.WriteString("\n")
+	}
+	if d := m.snapshotDiff; d != nil {
+		b.WriteString(NormalStyle.Render(fmt.Sprintf(" %d added, %d removed since the snapshot", len(d.Added), len(d.Removed))))
+		b.WriteString("\n\n")
+		if d.Unchanged() {
+			b.WriteString(HintStyle.Render(" No changes."))
+		} else {
+			lines := m.diffLines()
+			end := min(m.diffScroll+max(m.layout.TableHeight, 5), len(lines))
+			b.WriteString(strings.Join(lines[m.diffScroll:end], "\n"))
+		}
+	}
+	if m.statusMsg != "" {
+		b.WriteString("\n\n")
+		b.WriteString(NormalStyle.Render(" " + m.statusMsg))
+	}
+	return b.String()
+}
+
 func (m SubdomonsterModel) renderFilterView() string {
 	var b strings.Builder
 	b.WriteString(m.renderTableView())
@@ -948,15 +1412,20 @@ func (m SubdomonsterModel) renderSettingsView() string {
 func (m SubdomonsterModel) getHelpText() string {
 	switch m.viewMode {
 	case subdomonsterViewInput:
-		return "Enter: search | v: VirusTotal | c: crt.sh | Tab: browse cached | Ctrl-S: settings | Esc: back"
+		return "Enter: open | Alt-V: VirusTotal | Alt-C: crt.sh | Tab: browse cached | Ctrl-S: settings | Esc: back"
 	case subdomonsterViewDomains:
-		return "Enter: select | a: add domain | d: delete domain | j/k: navigate | Esc: back"
+		if m.pendingDeleteDomain != "" {
+			return "y: delete | any other key: cancel"
+		}
+		return "Enter: select | a: add domain | d: delete domain | C: refresh all via crt.sh | j/k: navigate | Esc: back"
 	case subdomonsterViewFetching:
 		return "Esc: cancel fetch"
 	case subdomonsterViewTable:
-		return "v: VirusTotal | c: crt.sh | /: search | f: filter source | x: toggle CDX | e: export | Esc: back"
+		return "Enter: open | y: copy URL | v: VirusTotal | V: VT restart | c: crt.sh | /: search | f: filter source | x: toggle CDX | N: recent | n/p: page | W: Wayback CDX | S: snapshot | D: diff | e: export | Esc: back"
 	case subdomonsterViewFilter:
-		return "Enter: apply filter | Esc: cancel"
+		return "Type to filter | Enter: done | Esc: cancel"
+	case subdomonsterViewDiff:
+		return "↑/↓: scroll | ←/→: older/newer snapshot | x: delete snapshot | Esc: back"
 	case subdomonsterViewSettings:
 		if m.settingsEditing {
 			return "Enter: save | Esc: cancel"
@@ -1006,23 +1475,58 @@ func (m SubdomonsterModel) loadSubdomainsFromDB() tea.Cmd {
 			Offset:     (m.page - 1) * m.pageSize,
 		}
 
+		switch m.filterRecent {
+		case subdomonsterRecentLastRun:
+			lastRun, err := m.database.GetLastSubdomainRun(m.domain)
+			if err != nil {
+				return subdomonsterSubdomainsLoadedMsg{filterText: m.filterText, err: err}
+			}
+			if lastRun.IsZero() {
+				// No run recorded yet, so nothing counts as recent
+				lastRun = time.Now()
+			}
+			filter.DiscoveredSince = lastRun
+		case subdomonsterRecentLastDay:
+			filter.DiscoveredSince = time.Now().Add(-subdomonsterRecentWindow)
+		}
+
+		lastExport, err := m.database.GetLastSubdomainExport(m.domain)
+		if err != nil {
+			return subdomonsterSubdomainsLoadedMsg{filterText: m.filterText, err: err}
+		}
+
 		subdomains, total, err := m.database.GetSubdomainsFiltered(filter)
-		return subdomonsterSubdomainsLoadedMsg{subdomains: subdomains, total: total, err: err}
+		return subdomonsterSubdomainsLoadedMsg{subdomains: subdomains, total: total, filterText: m.filterText, lastExport: lastExport, err: err}
 	}
 }
 
-func (m SubdomonsterModel) doVirusTotalFetch() tea.Cmd {
+// doVirusTotalFetch runs a VirusTotal enumeration, sending per-page progress on ch, which is
+// closed when the run ends
+func (m SubdomonsterModel) doVirusTotalFetch(resume bool, ch chan api.VTProgress) tea.Cmd {
 	return func() tea.Msg {
-		subdomains, err := m.client.FetchAllVirusTotalSubdomains(
+		defer close(ch)
+
+		// Pick up where an interrupted enumeration left off
+		startCursor := ""
+		if resume && m.database != nil {
+			startCursor, _ = m.database.GetVTCursor(m.domain)
+		}
+
+		subdomains, cursor, err := m.client.FetchAllVirusTotalSubdomainsWithResume(
+			m.fetchCtx,
 			m.domain,
-			func(count int) {
-				// Progress callback - not directly usable in Bubble Tea
+			startCursor,
+			func(p api.VTProgress) {
+				select {
+				case ch <- p:
+				case <-m.fetchCtx.Done():
+				}
 			},
-			m.cancelFetch,
 		)
 		return subdomonsterFetchCompleteMsg{
 			subdomains: subdomains,
 			source:     "virustotal",
+			cursor:     cursor,
 			err:        err,
 		}
 	}
@@ -1030,7 +1534,7 @@ func (m SubdomonsterModel) doVirusTotalFetch() tea.Cmd {
 
 func (m SubdomonsterModel) doCrtshFetch() tea.Cmd {
 	return func() tea.Msg {
-		subdomains, err := m.client.FetchCrtshSubdomains(m.domain)
+		subdomains, err := m.client.FetchCrtshSubdomains(m.fetchCtx, m.domain)
 		return subdomonsterFetchCompleteMsg{
 			subdomains: subdomains,
 			source:     "crtsh",
@@ -1039,6 +1543,76 @@ func (m SubdomonsterModel) doCrtshFetch() tea.Cmd {
 	}
 }
 
+// doCrtshFetchAll refreshes several domains from crt.sh in parallel
+func (m SubdomonsterModel) doCrtshFetchAll(domains []string) tea.Cmd {
+	return func() tea.Msg {
+		subdomains, errs := m.client.FetchCrtshSubdomainsMulti(m.fetchCtx, domains, 0)
+		return subdomonsterCrtshBatchCompleteMsg{domains: domains, subdomains: subdomains, errs: errs}
+	}
+}
+
+// doCDXEnrich fetches Wayback CDX records for every subdomain of the domain that isn't
+// CDX indexed yet, storing each subdomain's records and marking it indexed as it completes.
+// Progress goes out on ch, which is closed when the run ends.
+func (m SubdomonsterModel) doCDXEnrich(ch chan api.EnrichProgress) tea.Cmd {
+	return func() tea.Msg {
+		defer close(ch)
+
+		unindexed, _, err := m.database.GetSubdomainsFiltered(models.SubdomainFilter{
+			Domain:     m.domain,
+			CDXIndexed: 0,
+			Limit:      -1,
+		})
+		if err != nil {
+			return subdomonsterEnrichCompleteMsg{err: err}
+		}
+		subdomains := make([]string, len(unindexed))
+		for i, s := range unindexed {
+			subdomains[i] = s.Subdomain
+		}
+
+		progress := func(p api.EnrichProgress) {
+			select {
+			case ch <- p:
+			case <-m.fetchCtx.Done():
+			}
+		}
+		store := func(subdomain string, records []models.CDXRecord) error {
+			if len(records) > 0 {
+				if _, err := m.database.InsertWaybackRecords(records); err != nil {
+					return err
+				}
+			}
+			return m.database.MarkSubdomainCDXIndexed(subdomain)
+		}
+
+		result, err := m.waybackClient.EnrichSubdomains(subdomains, api.CDXFilter{}, getRequestDelay(defaultDelay), progress, store, m.fetchCtx.Done())
+		return subdomonsterEnrichCompleteMsg{result: result, total: len(subdomains), err: err}
+	}
+}
+
+// waitForVTProgress delivers the next VirusTotal page progress update, if any
+func waitForVTProgress(ch chan api.VTProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return subdomonsterFetchProgressMsg{progress: p}
+	}
+}
+
+// waitForEnrichProgress delivers the next enrichment progress update, if any
+func waitForEnrichProgress(ch chan api.EnrichProgress) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return subdomonsterEnrichProgressMsg{progress: p}
+	}
+}
+
 // =============================================================================
 // Tree Sort
 // =============================================================================
@@ -1112,6 +1686,11 @@ func (m *SubdomonsterModel) updateTable() {
 
 	rows := make([]table.Row, len(m.sortedSubdomains))
 	for i, s := range m.sortedSubdomains {
+		name := s.Subdomain
+		if m.isNewSinceExport(s) {
+			name = "+ " + name
+		}
+
 		cdxStatus := "[ ]"
 		if s.CDXIndexed {
 			cdxStatus = "[x]"
@@ -1123,8 +1702,8 @@ func (m *SubdomonsterModel) updateTable() {
 		}
 
 		rows[i] = table.Row{
-			truncate(s.Subdomain, subdomainW),
-			truncate(s.Source, sourceW),
+			truncate(name, subdomainW),
+			truncate(s.Sources, sourceW),
 			cdxStatus,
 			expiredStatus,
 		}
@@ -1135,12 +1714,22 @@ func (m *SubdomonsterModel) updateTable() {
 	m.table.SetCursor(oldCursor)
 }
 
+// isNewSinceExport reports whether s was discovered after the domain was last exported
+func (m SubdomonsterModel) isNewSinceExport(s models.Subdomain) bool {
+	return !m.lastExport.IsZero() && s.DiscoveredAt.After(m.lastExport)
+}
+
+// formatRecentWindow renders a recent-filter window as hours, e.g. "24h"
+func formatRecentWindow(d time.Duration) string {
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
+
 const (
-	subdomonsterSourceWidth  = 12
+	subdomonsterSourceWidth  = 18
 	subdomonsterCDXWidth     = 5
 	subdomonsterExpiredWidth = 9
 	subdomonsterMinSubWidth  = 40
-	subdomonsterMinTotal     = 70
+	subdomonsterMinTotal     = 76
 )
 
 func calculateSubdomonsterColumns(totalW int) []table.Column {
@@ -1164,7 +1753,7 @@ func calculateSubdomonsterColumns(totalW int) []table.Column {
 
 	return []table.Column{
 		{Title: "Subdomain", Width: subdomainW},
-		{Title: "Source", Width: subdomonsterSourceWidth},
+		{Title: "Sources", Width: subdomonsterSourceWidth},
 		{Title: "CDX", Width: subdomonsterCDXWidth},
 		{Title: "Expired  ", Width: subdomonsterExpiredWidth},
 	}
@@ -1191,6 +1780,8 @@ func (m SubdomonsterModel) exportToMarkdown(filename string) error {
 		b.WriteString(fmt.Sprintf("- Total: %d\n", stats.Total))
 		b.WriteString(fmt.Sprintf("- VirusTotal: %d\n", stats.VTCount))
 		b.WriteString(fmt.Sprintf("- crt.sh: %d\n", stats.CrtshCount))
+		b.WriteString(fmt.Sprintf("- SecurityTrails: %d\n", stats.SecurityTrailsCount))
+		b.WriteString(fmt.Sprintf("- Censys: %d\n", stats.CensysCount))
 		b.WriteString(fmt.Sprintf("- Import: %d\n", stats.ImportCount))
 		b.WriteString(fmt.Sprintf("- CDX Indexed: %d\n", stats.CDXCount))
 		b.WriteString(fmt.Sprintf("- Expired Certs: %d\n", stats.ExpiredCount))
@@ -1198,8 +1789,11 @@ func (m SubdomonsterModel) exportToMarkdown(filename string) error {
 	}
 
 	b.WriteString("## Subdomains\n\n")
-	b.WriteString("| Subdomain | Source | CDX | Expired |\n")
-	b.WriteString("|-----------|--------|-----|--------|\n")
+	if !m.lastExport.IsZero() {
+		b.WriteString(fmt.Sprintf("Subdomains marked + are new since the last export (%s).\n\n", m.lastExport.Local().Format("2006-01-02 15:04:05")))
+	}
+	b.WriteString("| Subdomain | Sources | CDX | Expired |\n")
+	b.WriteString("|-----------|---------|-----|--------|\n")
 
 	// Get all subdomains for export
 	var allSubdomains []models.Subdomain
@@ -1220,14 +1814,25 @@ func (m SubdomonsterModel) exportToMarkdown(filename string) error {
 		}
 		// Escape pipes in values
 		subdomain := strings.ReplaceAll(s.Subdomain, "|", "\\|")
+		if m.isNewSinceExport(s) {
+			subdomain = "+ " + subdomain
+		}
 
 		b.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
-			subdomain, s.Source, cdx, expired))
+			subdomain, s.Sources, cdx, expired))
 	}
 
 	return os.WriteFile(filename, []byte(b.String()), 0644)
 }
 
+// subdomainURL returns the URL to open for a subdomain: where probing ended up, or https
+func subdomainURL(s models.Subdomain) string {
+	if s.FinalURL != "" {
+		return s.FinalURL
+	}
+	return "https://" + s.Subdomain
+}
+
 // =============================================================================
 // Public API
 // =============================================================================
@@ -1259,6 +1864,22 @@ func RunSubdomonster(logger *log.Logger, database *db.DB) error {
 	return nil
 }
 
+// RunSubdomainBrowser opens the stored target domains, drilling into each one's subdomains
+// with live search, source and CDX filters and paging
+func RunSubdomainBrowser(database *db.DB) error {
+	return RunSubdomonsterCache(log.Default(), database)
+}
+
+// RunSubdomainBrowserAt opens the subdomain table of domain with the search filter set to filter
+func RunSubdomainBrowserAt(logger *log.Logger, database *db.DB, domain, filter string) error {
+	model := NewSubdomonsterModel(logger, database)
+	model.viewMode = subdomonsterViewDomains
+	model.domain = domain
+	model.filterText = filter
+	_, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
+
 // RunSubdomonsterCache starts the Subdomonster TUI directly in cached domains browser mode
 func RunSubdomonsterCache(logger *log.Logger, database *db.DB) error {
 	model := NewSubdomonsterModel(logger, database)
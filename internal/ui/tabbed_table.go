@@ -65,6 +65,8 @@ type TabbedTableModel struct {
 	quitting      bool
 	viewMode      string // "table" or "detail"
 	detailContent string // Full content when viewing detail
+	detailOffset  int    // First visible line of the wrapped detail content
+	jump          RowJump
 }
 
 // NewTabbedTableModel creates a new tabbed table viewer.
@@ -84,9 +86,9 @@ func NewTabbedTableModel(cfg TabbedTableConfig) TabbedTableModel {
 	// Default help text
 	if cfg.HelpText == "" {
 		if len(cfg.Pages) > 1 {
-			cfg.HelpText = "↑/↓: navigate | Tab/←/→: switch page | Enter: select | Esc: back"
+			cfg.HelpText = "↑/↓: navigate | :: go to row | Tab/←/→: switch page | Enter: select | Esc: back"
 		} else {
-			cfg.HelpText = "↑/↓: navigate | Enter: select | Esc: back"
+			cfg.HelpText = "↑/↓: navigate | :: go to row | Enter: select | Esc: back"
 		}
 	}
 
@@ -192,6 +194,11 @@ func (m TabbedTableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m TabbedTableModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
+	// Row jump prompt takes all keys while open
+	if m.viewMode == "table" && m.jump.HandleKey(key, &m.tables[m.currentPage]) {
+		return m, nil
+	}
+
 	// Global keys (work on all pages)
 	switch key {
 	case "esc":
@@ -199,6 +206,7 @@ func (m TabbedTableModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.viewMode == "detail" {
 			m.viewMode = "table"
 			m.detailContent = ""
+			m.detailOffset = 0
 			return m, nil
 		}
 		// Otherwise, quit
@@ -221,6 +229,12 @@ func (m TabbedTableModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Detail view scrolls long content (e.g. env values) instead of moving the table cursor
+	if m.viewMode == "detail" {
+		m.scrollDetail(key)
+		return m, nil
+	}
+
 	// Page-specific keys
 	currentPage := m.config.Pages[m.currentPage]
 
@@ -239,6 +253,7 @@ func (m TabbedTableModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				row := currentPage.Rows[cursor]
 				// Join all columns with space separator
 				m.detailContent = strings.Join(row, " ")
+				m.detailOffset = 0
 				m.viewMode = "detail"
 			}
 		}
@@ -344,6 +359,9 @@ func (m TabbedTableModel) View() string {
 	if helpText == "" {
 		helpText = m.config.HelpText
 	}
+	if m.jump.Active {
+		helpText = m.jump.Prompt(len(m.tables[m.currentPage].Rows()))
+	}
 
 	// Use TwoBoxView for consistent layout
 	return TwoBoxView(content.String(), helpText, m.layout)
@@ -359,15 +377,71 @@ func (m TabbedTableModel) renderDetailView() string {
 	content.WriteString(strings.Repeat("─", m.layout.InnerWidth))
 	content.WriteString("\n\n")
 
-	// Wrap content to fit width (simple line-by-line wrapping)
-	wrapped := wrapTextToLines(m.detailContent, m.layout.InnerWidth)
-	content.WriteString(NormalStyle.Render(wrapped))
+	// Show the window of wrapped lines that fits below the header
+	lines := m.detailLines()
+	height := m.detailHeight()
+	end := m.detailOffset + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+	content.WriteString(NormalStyle.Render(strings.Join(lines[m.detailOffset:end], "\n")))
 
 	// Use TwoBoxView for consistent layout
 	helpText := "Esc: back to table"
+	if len(lines) > height {
+		helpText = fmt.Sprintf("↑/↓/PgUp/PgDn: scroll (%d-%d of %d lines) | Esc: back to table", m.detailOffset+1, end, len(lines))
+	}
 	return TwoBoxView(content.String(), helpText, m.layout)
 }
 
+// detailHeaderLines is the title, divider and blank line above the detail content
+const detailHeaderLines = 3
+
+// detailLines returns the detail content wrapped to the view width
+func (m TabbedTableModel) detailLines() []string {
+	return strings.Split(wrapTextToLines(m.detailContent, m.layout.InnerWidth), "\n")
+}
+
+// detailHeight returns how many detail lines fit in the main box
+func (m TabbedTableModel) detailHeight() int {
+	height := m.layout.MainContentHeight() - detailHeaderLines
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// scrollDetail moves the detail view window for a navigation key, clamped to the content
+func (m *TabbedTableModel) scrollDetail(key string) {
+	height := m.detailHeight()
+	maxOffset := len(m.detailLines()) - height
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+
+	switch key {
+	case "up", "k":
+		m.detailOffset--
+	case "down", "j":
+		m.detailOffset++
+	case "pgup", "ctrl+u":
+		m.detailOffset -= height / 2
+	case "pgdown", "ctrl+d", " ":
+		m.detailOffset += height / 2
+	case "home", "g":
+		m.detailOffset = 0
+	case "end", "G":
+		m.detailOffset = maxOffset
+	}
+
+	if m.detailOffset > maxOffset {
+		m.detailOffset = maxOffset
+	}
+	if m.detailOffset < 0 {
+		m.detailOffset = 0
+	}
+}
+
 // wrapTextToLines wraps text to fit within width, returning formatted string with newlines
 func wrapTextToLines(text string, width int) string {
 	if width <= 0 || len(text) <= width {
@@ -523,6 +597,14 @@ func BuildStepsColumns() []ColumnSpec {
 	}
 }
 
+// ImageConfigColumns returns column specs for the image config page.
+func ImageConfigColumns() []ColumnSpec {
+	return []ColumnSpec{
+		{Title: "Setting", FixedWidth: 14},
+		{Title: "Value", FlexRatio: 100},
+	}
+}
+
 // CachedLayerColumns returns column specs for cached layer display.
 func CachedLayerColumns() []ColumnSpec {
 	return []ColumnSpec{
@@ -1,19 +1,29 @@
 package ui
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/thesavant42/gitsome-ng/internal/api"
 	"github.com/thesavant42/gitsome-ng/internal/db"
 	"github.com/thesavant42/gitsome-ng/internal/models"
 
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
+	"github.com/muesli/termenv"
 )
 
 // Message types for async operations
@@ -43,6 +53,7 @@ type userQueryProgressMsg struct {
 
 type userQueryCompleteMsg struct {
 	login string
+	batch int // queryBatch the query was dispatched in
 	data  *models.UserData
 	err   error
 }
@@ -59,30 +70,43 @@ var menuOptions = []string{
 	"  [A]dd Repository to Metadatabase",
 	"  [Q]uery Tagged GitHub DPUsers",
 	"  Keyword [s]earch",
-	"",
+	"  [F]etch All Tracked Repos",
 	"",
 	"---  Docker Hub",
 	"  [D]ocker Hub Search",
 	"  Browse DockerHub [R]epository",
 	"  [B]rowse Cached Docker Layers",
 	"  [S]earch Cached Layers",
-	"",
+	"  Search Build Ste[p]s",
 	"",
 	"---  Wayback CDX Records",
 	"  Search [W]ayback Machine",
 	"  Browse [w]ayback Cache",
 	"",
 	"",
-	"---  Subdomain Enumeration",
-	"  S[u]bDomonster - Subdomain Discovery",
+	"---  Subdomains",
+	"  S[u]bDomonster - Add Domain & Enumerate",
 	"  Browse Cached S[U]bdomains",
 	"",
 	"",
 	"---  System",
 	"  [C]onfigure Highlight Domains",
 	"  [E]xport Tab to Markdown",
+	"  Export Tab to [J]SON",
 	"  [e]xport Database Backup",
 	"  e[X]port Project Report",
+	"  Export Project Report as H[T]ML",
+	"  Export [H]ighlight Domains (JSON)",
+	"  [I]mport Highlight Domains (JSON)",
+	"  Export Tagged Contacts as v[c]ard",
+	"  Export Tagged Contacts as CS[v]",
+	"  Export [L]ink Groups (JSON)",
+	"  Export Link Groups as [M]arkdown",
+	"  Browse File [h]otspots",
+	"",
+	"---  Search",
+	"  [G]lobal Search (committers, subdomains, build steps, Wayback URLs)",
+	"  Search Commit [m]essages",
 }
 
 // isMenuHeader returns true if the menu item is a section header or spacer
@@ -102,12 +126,16 @@ func getNextSelectableMenuItem(current int, direction int) int {
 	return current // Stay at current if no valid item found
 }
 
+// Committer table sort keys, cycled with 'o' (each key in both directions)
+var committerSortKeys = []string{"commits", "name", "login", "email"}
+
 // Search query options
 var searchOptions = []string{
 	"Users with Docker profiles",
 	"Users in highlight domains",
 	"Users with Docker AND in highlight domains",
 	"Local keyword search (bio, repos, gists)",
+	"Committer search across all repos (name, email, login)",
 }
 
 // gistFileEntry represents a flattened view of a gist file with parent gist info
@@ -136,6 +164,7 @@ type TUIModel struct {
 	table        table.Model
 	stats        []models.ContributorStats
 	links        map[string]int  // email -> group_id
+	linkLabels   map[int]string  // group_id -> label, for labeled groups only
 	tags         map[string]bool // email -> tagged
 	pendingLinks []int           // row indices pending to be linked
 	repoOwner    string
@@ -148,8 +177,21 @@ type TUIModel struct {
 	helpVisible  bool
 
 	// Layout state
-	layout       Layout
-	columnWidths ColumnWidths
+	layout        Layout
+	columnWidths  ColumnWidths
+	showSigned    bool            // show the Signed column (% of commits signed, signing keys)
+	hiddenColumns map[string]bool // optional columns hidden with the column picker (see OptionalColumns)
+
+	// Committer sort state (default: commits descending)
+	sortKeyIndex int  // index into committerSortKeys
+	sortAsc      bool // ascending when true
+
+	// Committer filter state ('/' to type, esc to clear)
+	filterInputActive bool                      // whether filter text input is active
+	filterText        string                    // substring matched against name, login, email
+	unfilteredStats   []models.ContributorStats // full stats while a filter is applied (nil = no filter)
+
+	rowJump RowJump // ':' prompt to jump to a row number
 
 	// View state flags:
 	// - menuVisible: controls Update() key handling (true = process menu keys, false = process table keys)
@@ -181,10 +223,19 @@ type TUIModel struct {
 
 	// API fetch state
 	token           string           // GitHub API token
+	apiBaseURL      string           // GitHub Enterprise base URL (empty = github.com)
+	fetchFiles      bool             // also fetch each commit's changed files (one request per commit)
+	maxUserPages    int              // repo/gist page cap for user queries (0 = client default)
 	fetchPromptRepo *models.RepoInfo // repo pending fetch confirmation
 	fetchingRepo    *models.RepoInfo // repo currently being fetched
 	fetchProgress   string           // progress message during fetch
 
+	// Fetch-all state - tracked repos are refreshed one after another
+	fetchAllQueue  []models.RepoInfo // repos to refresh (nil when not running)
+	fetchAllIndex  int               // index of the repo being fetched
+	fetchAllNew    int               // new commits fetched so far
+	fetchAllFailed int               // repos whose fetch failed
+
 	// Project switch state
 	switchProject bool // true when user wants to switch to a different project
 
@@ -194,22 +245,30 @@ type TUIModel struct {
 	launchBrowseDockerRepo   bool   // true when user wants to browse a specific Docker Hub repo
 	launchCachedLayers       bool   // true when user wants to browse cached layers
 	launchSearchCachedLayers bool   // true when user wants to search cached layers
+	launchSearchBuildSteps   bool   // true when user wants to search cached build steps
 	launchWayback            bool   // true when user wants to launch Wayback Machine browser
+	launchGlobalSearch       bool   // true when user wants to search every subsystem at once
 	launchWaybackCache       bool   // true when user wants to browse Wayback cache
 	launchSubdomonster       bool   // true when user wants to launch Subdomonster
 	launchSubdomonsterCache  bool   // true when user wants to browse cached subdomains
+	launchFileHotspots       bool   // true when user wants to browse the current repo's most changed files
+	launchCommitSearch       bool   // true when user wants to search the current repo's commit messages
 
 	// Export state
 	dbPath        string // path to current database for backup export
 	exportMessage string // message to show after export (success or error)
 
 	// User query state
-	queryingUsers      bool     // true when querying tagged users
-	queryProgress      string   // progress message during query
-	queryTotal         int      // total users to query
-	queryCompleted     int      // users completed
-	queryFailed        int      // users that failed
-	queryLoginsToFetch []string // logins remaining to fetch
+	queryingUsers      bool            // true when querying tagged users
+	queryProgress      string          // progress message during query
+	queryTotal         int             // total users to query
+	queryCompleted     int             // users completed
+	queryFailed        int             // users that failed
+	queryLoginsToFetch []string        // logins remaining to fetch
+	queryInFlight      int             // queries dispatched but not yet completed
+	queryBatch         int             // incremented per batch; results from older batches are saved but not counted
+	failedLogins       map[string]bool // logins that failed in the last query run (ctrl+r retries)
+	queryTruncated     int             // users whose repos/gists hit the page cap
 
 	// Progress bar state
 	progressBar     progress.Model // animated progress bar component
@@ -217,6 +276,10 @@ type TUIModel struct {
 	progressPercent float64        // current progress percentage (0.0 to 1.0)
 	progressLabel   string         // descriptive label for current operation
 
+	// Activity spinner, animated while a fetch or user query runs (see busy)
+	spinner  spinner.Model
+	spinning bool // a tick loop is running; it ends on the first tick after the work finishes
+
 	// User detail view state
 	userDetailVisible   bool                    // showing user detail view
 	selectedUserLogin   string                  // which user we're viewing
@@ -227,7 +290,9 @@ type TUIModel struct {
 	userRepos           []models.UserRepository // repos for selected user
 	userGists           []models.UserGist       // gists for selected user
 	userGistFiles       []gistFileEntry         // flattened gist files for display
-	userDetailTab       int                     // 0 = profile, 1 = repos, 2 = gists
+	userKeys            []models.UserPublicKey  // SSH and GPG keys for selected user
+	userEvents          []models.UserEvent      // recent public events for selected user
+	userDetailTab       int                     // 0 = profile, 1 = repos, 2 = gists, 3 = keys, 4 = events
 	userDetailCursor    int                     // cursor position in detail view
 	userReposTable      table.Model             // bubbles table for repos tab
 	userGistsTable      table.Model             // bubbles table for gists tab
@@ -242,12 +307,14 @@ type TUIModel struct {
 	searchPickerCursor      int    // cursor in search picker
 	localSearchInputVisible bool   // whether local search keyword input is shown
 	localSearchKeyword      string // keyword being typed for local search
+	localSearchCommitters   bool   // keyword input searches committers across repos instead of profiles
 
 	// Delete confirmation state
 	deleteConfirmVisible bool
 	deleteConfirmForm    *huh.Form
 	deleteTargetIndex    int    // row index to delete
 	deleteTargetType     string // "committer", "repo", "gist"
+	undo                 *undoState
 
 	// Edit form state
 	editFormVisible bool
@@ -256,6 +323,40 @@ type TUIModel struct {
 	editTargetType  string // "committer", "profile"
 	editLoginValue  string // temp storage for form values
 	editNameValue   string
+
+	// Highlight domain import form state
+	importDomainsFormVisible bool
+	importDomainsForm        *huh.Form
+	importDomainsPath        string
+
+	// Commit date range state - zero dates leave that end open
+	dateRangeFormVisible bool
+	dateRangeForm        *huh.Form
+	dateRangeFromInput   string
+	dateRangeToInput     string
+	dateFrom             time.Time
+	dateTo               time.Time
+
+	// Link group label form state
+	linkLabelFormVisible bool
+	linkLabelForm        *huh.Form
+	linkLabelInput       string
+	linkLabelGroup       int
+
+	// Column picker form state
+	columnsFormVisible bool
+	columnsForm        *huh.Form
+	columnsInput       []string
+}
+
+// undoState remembers the last confirmed delete so ctrl+z can restore it. Only one level is
+// kept, and any other change to the data clears it.
+type undoState struct {
+	deletion  *db.Deletion
+	kind      string          // the deleteTargetType that was deleted
+	repo      models.RepoInfo // repo a committer or tracked repo was deleted from
+	repoIndex int             // position of a deleted tracked repo in the tab list
+	login     string          // user whose repo or gist was deleted
 }
 
 // isServiceAccount returns true if the user is a service account that cannot be scanned
@@ -271,9 +372,13 @@ func isServiceAccount(login, email string) bool {
 }
 
 // calculateColumnWidths computes column widths based on actual data content
-// and constrains them to fit within the available table width
-func calculateColumnWidths(stats []models.ContributorStats, tableWidth int) ColumnWidths {
+// and constrains them to fit within the available table width. Hidden optional
+// columns get zero width, leaving their space to the visible ones.
+func calculateColumnWidths(stats []models.ContributorStats, tableWidth int, showSigned bool, visible ColumnSet) ColumnWidths {
 	widths := DefaultColumnWidths()
+	if showSigned {
+		widths.Signed = ColWidthSigned
+	}
 
 	// Scan all rows to find max width needed for each column
 	for i, s := range stats {
@@ -284,8 +389,8 @@ func calculateColumnWidths(stats []models.ContributorStats, tableWidth int) Colu
 		}
 
 		// Name column
-		if len(s.Name) > widths.Name {
-			widths.Name = len(s.Name)
+		if len(s.DisplayName()) > widths.Name {
+			widths.Name = len(s.DisplayName())
 		}
 
 		// GitHub Login column
@@ -313,6 +418,13 @@ func calculateColumnWidths(stats []models.ContributorStats, tableWidth int) Colu
 		if len(pctStr) > widths.Percent {
 			widths.Percent = len(pctStr)
 		}
+
+		// Signed column, capped so long SSH fingerprints don't crowd out the names
+		if showSigned {
+			if n := len(signedCell(s)); n > widths.Signed {
+				widths.Signed = min(n, ColWidthSignedMax)
+			}
+		}
 	}
 
 	// Ensure header titles fit
@@ -338,9 +450,26 @@ func calculateColumnWidths(stats []models.ContributorStats, tableWidth int) Colu
 		widths.Percent = len("%")
 	}
 
+	// Drop hidden columns, along with their separators
+	separators := ColSeparators
+	for col, width := range map[string]*int{
+		ColumnName:    &widths.Name,
+		ColumnLogin:   &widths.Login,
+		ColumnEmail:   &widths.Email,
+		ColumnPercent: &widths.Percent,
+	} {
+		if !visible[col] {
+			*width = 0
+			separators -= 2
+		}
+	}
+
 	// Calculate total width and constrain flexible columns if needed
 	totalWidth := widths.Tag + widths.Rank + widths.Name + widths.Login +
-		widths.Email + widths.Commits + widths.Percent + ColSeparators
+		widths.Email + widths.Commits + widths.Percent + separators
+	if showSigned {
+		totalWidth += widths.Signed + 2 // plus its separator
+	}
 
 	if totalWidth > tableWidth {
 		// Need to shrink flexible columns (Name, Login, Email)
@@ -358,13 +487,13 @@ func calculateColumnWidths(stats []models.ContributorStats, tableWidth int) Colu
 			widths.Email -= int(float64(overflow) * emailShare)
 
 			// Ensure minimums
-			if widths.Name < ColWidthName {
+			if visible[ColumnName] && widths.Name < ColWidthName {
 				widths.Name = ColWidthName
 			}
-			if widths.Login < ColWidthLogin {
+			if visible[ColumnLogin] && widths.Login < ColWidthLogin {
 				widths.Login = ColWidthLogin
 			}
-			if widths.Email < ColWidthEmail {
+			if visible[ColumnEmail] && widths.Email < ColWidthEmail {
 				widths.Email = ColWidthEmail
 			}
 		}
@@ -387,8 +516,8 @@ func NewTUIModel(
 ) TUIModel {
 	// Calculate column widths based on actual data content, constrained to fit viewport
 	layout := DefaultLayout()
-	widths := calculateColumnWidths(stats, layout.TableWidth)
-	columns := BuildTableColumns(widths)
+	widths := calculateColumnWidths(stats, layout.TableWidth, false, AllColumns())
+	columns := BuildTableColumns(widths, AllColumns())
 
 	// Build processed logins cache - check which users have fetched data
 	processedLogins := make(map[string]bool)
@@ -402,6 +531,13 @@ func NewTUIModel(
 		}
 	}
 
+	linkLabels := make(map[int]string)
+	if database != nil {
+		if labels, err := database.GetGroupLabels(repoOwner, repoName); err == nil {
+			linkLabels = labels
+		}
+	}
+
 	// Build rows
 	rows := make([]table.Row, len(stats))
 	for i, s := range stats {
@@ -424,7 +560,7 @@ func NewTUIModel(
 		rows[i] = table.Row{
 			tagMark,
 			fmt.Sprintf("%d", i+1),
-			s.Name,
+			linkedDisplayName(s, links, linkLabels),
 			login,
 			s.Email,
 			fmt.Sprintf("%d", s.CommitCount),
@@ -437,7 +573,7 @@ func NewTUIModel(
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(TableHeight),
+		table.WithHeight(layout.TableHeight),
 	)
 
 	// Apply styles using centralized function from styles.go
@@ -461,6 +597,7 @@ func NewTUIModel(
 		table:            t,
 		stats:            stats,
 		links:            links,
+		linkLabels:       linkLabels,
 		tags:             tags,
 		highlightDomains: domains,
 		domainList:       domainList,
@@ -474,6 +611,7 @@ func NewTUIModel(
 		layout:           DefaultLayout(),
 		columnWidths:     widths,
 		progressBar:      prog,
+		spinner:          NewAppSpinner(),
 		showProgress:     false,
 		progressPercent:  0.0,
 		progressLabel:    "",
@@ -589,7 +727,7 @@ func (m *TUIModel) initUserReposTable() {
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(m.layout.TableHeight),
+		table.WithHeight(m.userDetailTableHeight()),
 	)
 
 	ApplyTableStyles(&t)
@@ -750,7 +888,7 @@ func (m *TUIModel) initUserGistsTable() {
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(m.layout.TableHeight),
+		table.WithHeight(m.userDetailTableHeight()),
 	)
 
 	ApplyTableStyles(&t)
@@ -789,6 +927,15 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.progressBar = progressModel.(progress.Model)
 		return m, cmd
 
+	// Keep the activity spinner turning until the work is done
+	case spinner.TickMsg:
+		if !m.busy() {
+			m.spinning = false
+			return m, nil
+		}
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
 	// Handle async fetch messages
 	case fetchProgressMsg:
 		m.fetchProgress = fmt.Sprintf("Fetching commits... %d fetched (page %d)", msg.fetched, msg.page)
@@ -803,6 +950,10 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.progressBar.SetPercent(m.progressPercent)
 
 	case fetchCompleteMsg:
+		if m.fetchAllQueue != nil {
+			return m.handleFetchAllComplete(msg)
+		}
+
 		m.fetchingRepo = nil
 		m.fetchProgress = ""
 
@@ -815,36 +966,26 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.repoViewVisible = true
 		m.menuVisible = false
 
-		// Log API call to database
-		if m.database != nil {
-			endpoint := fmt.Sprintf("/repos/%s/%s/commits", msg.owner, msg.name)
-			errorMsg := ""
-			statusCode := 200
-			if msg.err != nil {
-				errorMsg = msg.err.Error()
-				statusCode = 0
-			}
-			m.database.SaveAPILog("GET", endpoint, statusCode, errorMsg, 0, "", "")
-		}
+		m.recordFetch(msg)
 
 		if msg.err != nil {
 			// Show error message to user
+			if errors.Is(msg.err, api.ErrUnauthorized) {
+				m.exportMessage = "GitHub token invalid or expired - update GITHUB_TOKEN"
+				return m, nil
+			}
+			var rateErr *api.RateLimitError
+			if errors.As(msg.err, &rateErr) {
+				m.exportMessage = fmt.Sprintf("Rate limited by GitHub - try again at %s (in %s)",
+					rateErr.Reset.Format("15:04:05"), time.Until(rateErr.Reset).Round(time.Second))
+				return m, nil
+			}
 			m.exportMessage = fmt.Sprintf("Fetch failed: %v", msg.err)
 			return m, nil
 		}
 		if len(msg.commits) == 0 {
 			m.exportMessage = fmt.Sprintf("No commits found for %s/%s", msg.owner, msg.name)
 		}
-		// Store commits in database
-		if m.database != nil && len(msg.commits) > 0 {
-			records := make([]models.CommitRecord, len(msg.commits))
-			for i, c := range msg.commits {
-				records[i] = c.ToRecord(msg.owner, msg.name)
-			}
-			m.database.InsertCommits(records)
-			// Ensure repo is tracked in database
-			m.database.AddTrackedRepo(msg.owner, msg.name)
-		}
 		// Switch to the newly fetched repo
 		for i, repo := range m.repos {
 			if repo.Owner == msg.owner && repo.Name == msg.name {
@@ -865,6 +1006,15 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.progressBar.SetPercent(m.progressPercent)
 
 	case userQueryCompleteMsg:
+		// A query still in flight when the batch was cancelled - keep its data, skip progress
+		if msg.batch != m.queryBatch || !m.queryingUsers {
+			if msg.err == nil && msg.data != nil && m.database != nil {
+				m.saveQueriedUser(msg.login, msg.data)
+				m.updateRows()
+			}
+			return m, nil
+		}
+
 		m.queryCompleted++
 
 		// Update progress bar
@@ -873,6 +1023,12 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.queryProgress = fmt.Sprintf("Completed %d/%d users", m.queryCompleted, m.queryTotal)
 
 		// Log API call to database
+		if msg.err != nil {
+			m.queryFailed++
+			m.failedLogins[msg.login] = true
+		} else {
+			delete(m.failedLogins, msg.login)
+		}
 		if m.database != nil {
 			endpoint := "https://api.github.com/graphql"
 			errorMsg := ""
@@ -880,29 +1036,27 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.err != nil {
 				errorMsg = msg.err.Error()
 				statusCode = 0
-				m.queryFailed++
 			}
 			m.database.SaveAPILog("POST", endpoint, statusCode, errorMsg, 0, "", msg.login)
 		}
 
+		if msg.err == nil && msg.data != nil && msg.data.Truncated {
+			m.queryTruncated++
+		}
+
 		if msg.err == nil && msg.data != nil && m.database != nil {
-			// Save user data to database
-			m.database.SaveUserProfile(msg.data.Profile)
-			m.database.SaveUserRepositories(msg.data.Repositories)
-			m.database.SaveUserGists(msg.data.Gists)
-			// Mark this login as processed so it shows [!] instead of [x]
-			if m.processedLogins == nil {
-				m.processedLogins = make(map[string]bool)
-			}
-			m.processedLogins[msg.login] = true
+			m.saveQueriedUser(msg.login, msg.data)
 		}
-		// Fetch next user if any
+		m.queryInFlight--
+
+		// Refill the worker slot freed by this query
 		if len(m.queryLoginsToFetch) > 0 {
-			login := m.queryLoginsToFetch[0]
-			m.queryLoginsToFetch = m.queryLoginsToFetch[1:]
-			// Update progress bar and start next query
 			cmd := m.progressBar.SetPercent(m.progressPercent)
-			return m, tea.Batch(cmd, m.startUserQuery(login, m.queryCompleted+1, m.queryTotal))
+			return m, tea.Batch(cmd, m.dispatchNextUserQuery())
+		}
+		// Wait for the other in-flight queries
+		if m.queryInFlight > 0 {
+			return m, m.progressBar.SetPercent(m.progressPercent)
 		}
 		// All done - update rows to reflect new [!] indicators
 		m.queryingUsers = false
@@ -915,6 +1069,12 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		m.updateRows()
 		m.exportMessage = fmt.Sprintf("Queried %d users (%d succeeded, %d failed)", m.queryTotal, m.queryTotal-m.queryFailed, m.queryFailed)
+		if m.queryTruncated > 0 {
+			m.exportMessage += fmt.Sprintf(" - %d partial (page cap reached)", m.queryTruncated)
+		}
+		if m.queryFailed > 0 {
+			m.exportMessage += " - ctrl+r to retry failed"
+		}
 		return m, nil
 
 	}
@@ -952,6 +1112,7 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch m.editForm.State {
 		case huh.StateCompleted:
 			m.editFormVisible = false
+			m.undo = nil
 			m.executeEdit()
 			return m, nil
 		case huh.StateAborted:
@@ -961,7 +1122,88 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Handle highlight domain import form (needs all msg types, not just KeyMsg)
+	if m.importDomainsFormVisible && m.importDomainsForm != nil {
+		form, cmd := m.importDomainsForm.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.importDomainsForm = f
+		}
+
+		switch m.importDomainsForm.State {
+		case huh.StateCompleted:
+			m.importDomainsFormVisible = false
+			m.undo = nil
+			m.importDomains(strings.TrimSpace(m.importDomainsForm.GetString("path")))
+			return m, nil
+		case huh.StateAborted:
+			m.importDomainsFormVisible = false
+			return m, nil
+		}
+		return m, cmd
+	}
+
+	// Handle commit date range form (needs all msg types, not just KeyMsg)
+	if m.dateRangeFormVisible && m.dateRangeForm != nil {
+		form, cmd := m.dateRangeForm.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.dateRangeForm = f
+		}
+
+		switch m.dateRangeForm.State {
+		case huh.StateCompleted:
+			m.dateRangeFormVisible = false
+			m.applyDateRange(m.dateRangeFromInput, m.dateRangeToInput)
+			return m, nil
+		case huh.StateAborted:
+			m.dateRangeFormVisible = false
+			return m, nil
+		}
+		return m, cmd
+	}
+
+	// Handle link group label form (needs all msg types, not just KeyMsg)
+	if m.linkLabelFormVisible && m.linkLabelForm != nil {
+		form, cmd := m.linkLabelForm.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.linkLabelForm = f
+		}
+
+		switch m.linkLabelForm.State {
+		case huh.StateCompleted:
+			m.linkLabelFormVisible = false
+			m.undo = nil
+			m.applyLinkLabel(m.linkLabelGroup, m.linkLabelInput)
+			return m, nil
+		case huh.StateAborted:
+			m.linkLabelFormVisible = false
+			return m, nil
+		}
+		return m, cmd
+	}
+
+	// Handle column picker form (needs all msg types, not just KeyMsg)
+	if m.columnsFormVisible && m.columnsForm != nil {
+		form, cmd := m.columnsForm.Update(msg)
+		if f, ok := form.(*huh.Form); ok {
+			m.columnsForm = f
+		}
+
+		switch m.columnsForm.State {
+		case huh.StateCompleted:
+			m.columnsFormVisible = false
+			m.applyVisibleColumns(m.columnsInput)
+			return m, nil
+		case huh.StateAborted:
+			m.columnsFormVisible = false
+			return m, nil
+		}
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		// Clear export message on any key press
 		if m.exportMessage != "" {
@@ -982,7 +1224,7 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.progressLabel = "Fetching commits..."
 				// Trigger animation and start fetch
 				cmd := m.progressBar.SetPercent(m.progressPercent)
-				return m, tea.Batch(cmd, m.startFetch(repo.Owner, repo.Name))
+				return m, tea.Batch(cmd, m.startSpinner(), m.startFetch(repo.Owner, repo.Name))
 			case "n", "N", "esc":
 				m.fetchPromptRepo = nil
 				m.repoViewVisible = true
@@ -1027,11 +1269,27 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleLocalSearchInput(msg)
 		}
 
+		// Handle committer filter input
+		if m.filterInputActive {
+			return m.handleFilterInput(msg)
+		}
+
+		// Cancel a user query batch - in-flight queries finish, no new ones start
+		if m.queryingUsers && (msg.String() == "esc" || msg.String() == "ctrl+x") {
+			m.cancelUserQueries()
+			return m, nil
+		}
+
 		// Block input while fetching
 		if m.fetchingRepo != nil || m.queryingUsers {
 			return m, nil
 		}
 
+		// Row jump prompt (':' then a row number)
+		if m.rowJump.HandleKey(msg.String(), &m.table) {
+			return m, nil
+		}
+
 		// Main table mode
 		switch msg.String() {
 		case "ctrl+c":
@@ -1042,6 +1300,48 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.helpVisible = !m.helpVisible
 			return m, nil
 
+		case "/":
+			m.filterInputActive = true
+			return m, nil
+
+		case "y":
+			// Copy selected email to clipboard
+			cursor := m.table.Cursor()
+			if cursor >= 0 && cursor < len(m.stats) {
+				if err := copyToClipboard(m.stats[cursor].Email); err != nil {
+					m.exportMessage = fmt.Sprintf("Copy failed: %v", err)
+				} else {
+					m.exportMessage = "Copied email to clipboard"
+				}
+			}
+			return m, nil
+
+		case "Y":
+			// Copy selected row as TSV (rank, name, login, email, commits, percentage)
+			cursor := m.table.Cursor()
+			rows := m.table.Rows()
+			if cursor >= 0 && cursor < len(rows) {
+				if err := copyToClipboard(strings.Join(rows[cursor][1:], "\t")); err != nil {
+					m.exportMessage = fmt.Sprintf("Copy failed: %v", err)
+				} else {
+					m.exportMessage = "Copied row to clipboard"
+				}
+			}
+			return m, nil
+
+		case "ctrl+y":
+			// Copy the shown rows (filter, sort and columns as displayed) as a markdown table
+			if len(m.stats) == 0 {
+				m.exportMessage = "No rows to copy"
+				return m, nil
+			}
+			if err := copyToClipboard(contributorMarkdownTable(m.stats, m.visibleColumns())); err != nil {
+				m.exportMessage = fmt.Sprintf("Copy failed: %v", err)
+			} else {
+				m.exportMessage = fmt.Sprintf("Copied %d rows as markdown", len(m.stats))
+			}
+			return m, nil
+
 		case "m", "M":
 			m.menuVisible = true
 			m.menuCursor = 2 // First selectable item (View Repositories)
@@ -1147,6 +1447,13 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				// Clear pending and stay on table
 				m.pendingLinks = nil
+				m.undo = nil
+				m.updateRows()
+				return m, nil
+			}
+			// Active filter - clear it before leaving the table
+			if m.unfilteredStats != nil {
+				m.clearFilter()
 				return m, nil
 			}
 			// No pending links - go back to menu (home)
@@ -1155,6 +1462,34 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.menuVisible = true
 			return m, nil
 
+		case "g":
+			// Set/clear the commit date range for repository stats
+			if m.showCombined || m.searchActive {
+				m.exportMessage = "Date range not available here - switch to a specific repository"
+				return m, nil
+			}
+			return m, m.showDateRangeForm()
+
+		case "o":
+			// Cycle sort: commits↓ commits↑ name↑ name↓ login↑ login↓ email↑ email↓
+			m.cycleSort()
+			m.pendingLinks = nil // Row indices change with the order
+			m.rebuildTable()
+			return m, nil
+
+		case "c":
+			// Pick which optional columns to show
+			return m, m.showColumnsForm()
+
+		case "v":
+			// Toggle the Signed column (off by default to keep the standard layout)
+			m.showSigned = !m.showSigned
+			m.rebuildTable()
+			if m.showSigned {
+				m.exportMessage = "Signed column: % of commits signed, * = some signatures unverified"
+			}
+			return m, nil
+
 		case "t", "T":
 			// Toggle tag / clear processed status
 			// Tags ALL rows with same GitHub login
@@ -1208,19 +1543,96 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				// Update rows to reflect tag change
+				m.undo = nil
 				m.updateRows()
 			}
 			return m, nil
 
+		case "ctrl+t":
+			// Bulk tag all displayed rows (same login grouping as T)
+			if m.showCombined || m.searchActive {
+				m.exportMessage = "Bulk tagging not available here - switch to a specific repository"
+				return m, nil
+			}
+			count := m.tagAllVisible()
+			m.undo = nil
+			m.updateRows()
+			m.exportMessage = fmt.Sprintf("Tagged %d committers", count)
+			return m, nil
+
+		case "ctrl+x":
+			// Clear all tags for the current repository
+			if m.showCombined || m.searchActive {
+				m.exportMessage = "Clearing tags not available here - switch to a specific repository"
+				return m, nil
+			}
+			count := len(m.tags)
+			if m.database != nil {
+				if err := m.database.ClearTags(m.repoOwner, m.repoName); err != nil {
+					m.exportMessage = fmt.Sprintf("Failed to clear tags: %v", err)
+					return m, nil
+				}
+			}
+			m.tags = make(map[string]bool)
+			m.undo = nil
+			m.updateRows()
+			m.exportMessage = fmt.Sprintf("Cleared %d tags", count)
+			return m, nil
+
 		case "u":
 			// Unlink current row
 			cursor := m.table.Cursor()
 			if cursor >= 0 && cursor < len(m.stats) {
 				email := m.stats[cursor].Email
+				groupID := m.links[email]
 				delete(m.links, email)
 				if m.database != nil {
 					m.database.RemoveLink(m.repoOwner, m.repoName, email)
 				}
+				// The label goes with the group's last member
+				if !linkGroupHasMembers(m.links, groupID) {
+					delete(m.linkLabels, groupID)
+				}
+				m.undo = nil
+				m.updateRows()
+			}
+			return m, nil
+
+		case "N":
+			// Name the link group of the current row
+			if m.showCombined || m.searchActive {
+				m.exportMessage = "Naming link groups not available here - switch to a specific repository"
+				return m, nil
+			}
+			cursor := m.table.Cursor()
+			if cursor < 0 || cursor >= len(m.stats) {
+				return m, nil
+			}
+			groupID, ok := m.links[m.stats[cursor].Email]
+			if !ok {
+				m.exportMessage = "Row is not linked - select rows with L and press Esc to link them first"
+				return m, nil
+			}
+			return m, m.showLinkLabelForm(groupID)
+
+		case "ctrl+z":
+			// Undo the last delete
+			m.undoLastDelete()
+			return m, nil
+
+		case "ctrl+r":
+			// Retry logins that failed in the last query run
+			if len(m.failedLogins) == 0 {
+				m.exportMessage = "No failed user queries to retry"
+			} else if m.token == "" {
+				m.exportMessage = "GitHub token required for user queries"
+			} else {
+				logins := make([]string, 0, len(m.failedLogins))
+				for login := range m.failedLogins {
+					logins = append(logins, login)
+				}
+				sort.Strings(logins)
+				return m, m.startUserQueries(logins)
 			}
 			return m, nil
 
@@ -1254,21 +1666,7 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if len(logins) == 0 {
 						m.exportMessage = "All tagged users have already been processed"
 					} else {
-						// Start querying users
-						m.queryingUsers = true
-						m.queryTotal = len(logins)
-						m.queryCompleted = 0
-						m.queryFailed = 0
-						// Show progress bar from the start
-						m.showProgress = true
-						m.progressPercent = 0.0
-						m.progressLabel = fmt.Sprintf("Querying users... 0/%d", len(logins))
-						if len(logins) > 1 {
-							m.queryLoginsToFetch = logins[1:]
-						}
-						// Trigger animation and start query
-						cmd := m.progressBar.SetPercent(m.progressPercent)
-						return m, tea.Batch(cmd, m.startUserQuery(logins[0], 1, m.queryTotal))
+						return m, m.startUserQueries(logins)
 					}
 				}
 			}
@@ -1300,12 +1698,21 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.deleteTargetIndex = m.currentRepoIndex
 				m.deleteTargetType = "tracked_repo"
 
+				// Spell out the scope so a mass delete is never a surprise
+				description := "This will remove the repository and all its commits from the database."
+				if m.database != nil {
+					if commits, committers, err := m.database.DeleteRepositoryDataPreview(repo.Owner, repo.Name); err == nil {
+						description = fmt.Sprintf("This will delete %s commits from %s committers, plus the repository's tags and links.",
+							formatCount(commits), formatCount(committers))
+					}
+				}
+
 				m.deleteConfirmForm = huh.NewForm(
 					huh.NewGroup(
 						huh.NewConfirm().
 							Key("confirm").
 							Title(fmt.Sprintf("Delete repository '%s/%s'?", repo.Owner, repo.Name)).
-							Description("This will remove the repository and all its commits from the database.").
+							Description(description).
 							Affirmative("Yes, delete").
 							Negative("Cancel"),
 					),
@@ -1338,6 +1745,10 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cursor := m.table.Cursor()
 			if cursor >= 0 && cursor < len(m.stats) {
 				s := m.stats[cursor]
+				if s.IsCoAuthor() {
+					m.exportMessage = "Co-authors come from commit messages - delete the commits' committer instead"
+					return m, nil
+				}
 				m.deleteTargetIndex = cursor
 				m.deleteTargetType = "committer"
 
@@ -1362,6 +1773,10 @@ func (m TUIModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cursor := m.table.Cursor()
 			if cursor >= 0 && cursor < len(m.stats) {
 				s := m.stats[cursor]
+				if s.IsCoAuthor() {
+					m.exportMessage = "Co-authors come from commit messages and can't be edited"
+					return m, nil
+				}
 				m.editTargetIndex = cursor
 				m.editTargetType = "committer"
 				m.editLoginValue = s.GitHubLogin
@@ -1459,24 +1874,18 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if len(logins) == 0 {
 					m.exportMessage = "All tagged users have already been processed"
 				} else {
-					m.queryingUsers = true
-					m.queryTotal = len(logins)
-					m.queryCompleted = 0
-					m.queryFailed = 0
-					m.showProgress = true
-					m.progressPercent = 0.0
-					m.progressLabel = fmt.Sprintf("Querying users... 0/%d", len(logins))
-					if len(logins) > 1 {
-						m.queryLoginsToFetch = logins[1:]
-					}
-					cmd := m.progressBar.SetPercent(m.progressPercent)
-					return m, tea.Batch(cmd, m.startUserQuery(logins[0], 1, m.queryTotal))
+					return m, m.startUserQueries(logins)
 				}
 			}
 		} else if m.token == "" {
 			m.exportMessage = "GitHub token required for user queries"
 		}
 		return m, nil
+	case "F":
+		m.menuCursor = 6
+		m.menuVisible = false
+		m.repoViewVisible = true // status messages show in the repo view
+		return m, m.startFetchAll()
 	case "s": // lowercase - Keyword Search
 		m.menuCursor = 5
 		m.menuVisible = false
@@ -1503,6 +1912,11 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		m.launchSearchCachedLayers = true
 		return m, tea.Quit
+	case "p": // lowercase - Search Build Steps
+		m.menuCursor = 13
+		m.quitting = true
+		m.launchSearchBuildSteps = true
+		return m, tea.Quit
 	case "W":
 		m.menuCursor = 16
 		m.quitting = true
@@ -1513,7 +1927,7 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.quitting = true
 		m.launchWaybackCache = true
 		return m, tea.Quit
-	case "u": // SubDomonster - Subdomain Discovery
+	case "u": // SubDomonster - Add Domain & Enumerate
 		m.menuCursor = 21
 		m.quitting = true
 		m.launchSubdomonster = true
@@ -1537,7 +1951,7 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.menuCursor = 23
 		m.menuVisible = false
 		if m.dbPath != "" {
-			filename, err := ExportDatabaseBackup(m.dbPath)
+			filename, err := ExportDatabaseBackup(m.database, m.dbPath)
 			if err != nil {
 				m.exportMessage = fmt.Sprintf("Backup failed: %v", err)
 			} else {
@@ -1561,6 +1975,55 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.exportMessage = "Database not available"
 		}
 		return m, nil
+	case "J":
+		m.menuCursor = 28
+		m.menuVisible = false
+		m.exportTabJSON()
+		return m, nil
+	case "T":
+		m.menuCursor = 31
+		m.menuVisible = false
+		m.exportProjectReportHTML()
+		return m, nil
+	case "H":
+		m.menuCursor = 32
+		m.menuVisible = false
+		m.exportDomains()
+		return m, nil
+	case "I":
+		m.menuCursor = 33
+		return m, m.showImportDomainsForm()
+	case "c":
+		m.menuCursor = 34
+		m.menuVisible = false
+		m.exportTaggedContacts(ContactsFormatVCard)
+		return m, nil
+	case "v":
+		m.menuCursor = 35
+		m.menuVisible = false
+		m.exportTaggedContacts(ContactsFormatCSV)
+		return m, nil
+	case "L":
+		m.menuCursor = 36
+		m.menuVisible = false
+		m.exportLinkGroups(LinkGroupsFormatJSON)
+		return m, nil
+	case "M":
+		m.menuCursor = 37
+		m.menuVisible = false
+		m.exportLinkGroups(LinkGroupsFormatMarkdown)
+		return m, nil
+	case "h": // lowercase - Browse File Hotspots
+		m.menuCursor = 38
+		return m, m.openFileHotspots()
+	case "G":
+		m.menuCursor = 41
+		m.quitting = true
+		m.launchGlobalSearch = true
+		return m, tea.Quit
+	case "m": // lowercase - Search Commit Messages
+		m.menuCursor = 42
+		return m, m.openCommitSearch()
 
 	case "enter":
 		// Handle menu selection based on actual menuOptions indices
@@ -1600,21 +2063,7 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					if len(logins) == 0 {
 						m.exportMessage = "All tagged users have already been processed"
 					} else {
-						// Start querying users
-						m.queryingUsers = true
-						m.queryTotal = len(logins)
-						m.queryCompleted = 0
-						m.queryFailed = 0
-						// Show progress bar from the start
-						m.showProgress = true
-						m.progressPercent = 0.0
-						m.progressLabel = fmt.Sprintf("Querying users... 0/%d", len(logins))
-						if len(logins) > 1 {
-							m.queryLoginsToFetch = logins[1:]
-						}
-						// Trigger animation and start query
-						cmd := m.progressBar.SetPercent(m.progressPercent)
-						return m, tea.Batch(cmd, m.startUserQuery(logins[0], 1, m.queryTotal))
+						return m, m.startUserQueries(logins)
 					}
 				}
 			} else if m.token == "" {
@@ -1624,6 +2073,10 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.menuVisible = false
 			m.searchPickerVisible = true
 			m.searchPickerCursor = 0
+		case 6: // [F]etch All Tracked Repos
+			m.menuVisible = false
+			m.repoViewVisible = true // status messages show in the repo view
+			return m, m.startFetchAll()
 		case 9: // [D]ocker Hub Search
 			m.quitting = true
 			m.launchDockerSearch = true
@@ -1640,6 +2093,10 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			m.launchSearchCachedLayers = true
 			return m, tea.Quit
+		case 13: // Search Build Ste[p]s
+			m.quitting = true
+			m.launchSearchBuildSteps = true
+			return m, tea.Quit
 		case 16: // Search [W]ayback Machine
 			m.quitting = true
 			m.launchWayback = true
@@ -1648,7 +2105,7 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			m.launchWaybackCache = true
 			return m, tea.Quit
-		case 21: // S[u]bDomonster - Subdomain Discovery
+		case 21: // S[u]bDomonster - Add Domain & Enumerate
 			m.quitting = true
 			m.launchSubdomonster = true
 			return m, tea.Quit
@@ -1670,10 +2127,13 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.exportMessage = fmt.Sprintf("Exported to %s", filename)
 			}
-		case 28: // [e]xport Database Backup
+		case 28: // Export Tab to [J]SON
+			m.menuVisible = false
+			m.exportTabJSON()
+		case 29: // [e]xport Database Backup
 			m.menuVisible = false
 			if m.dbPath != "" {
-				filename, err := ExportDatabaseBackup(m.dbPath)
+				filename, err := ExportDatabaseBackup(m.database, m.dbPath)
 				if err != nil {
 					m.exportMessage = fmt.Sprintf("Backup failed: %v", err)
 				} else {
@@ -1682,7 +2142,7 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.exportMessage = "Database path not available"
 			}
-		case 29: // e[X]port Project Report
+		case 30: // e[X]port Project Report
 			m.menuVisible = false
 			if m.database != nil {
 				filename, err := ExportProjectReport(m.database, m.dbPath)
@@ -1694,6 +2154,34 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else {
 				m.exportMessage = "Database not available"
 			}
+		case 31: // Export Project Report as H[T]ML
+			m.menuVisible = false
+			m.exportProjectReportHTML()
+		case 32: // Export [H]ighlight Domains
+			m.menuVisible = false
+			m.exportDomains()
+		case 33: // [I]mport Highlight Domains
+			return m, m.showImportDomainsForm()
+		case 34: // Export Tagged Contacts as v[c]ard
+			m.menuVisible = false
+			m.exportTaggedContacts(ContactsFormatVCard)
+		case 35: // Export Tagged Contacts as CS[v]
+			m.menuVisible = false
+			m.exportTaggedContacts(ContactsFormatCSV)
+		case 36: // Export [L]ink Groups (JSON)
+			m.menuVisible = false
+			m.exportLinkGroups(LinkGroupsFormatJSON)
+		case 37: // Export Link Groups as [M]arkdown
+			m.menuVisible = false
+			m.exportLinkGroups(LinkGroupsFormatMarkdown)
+		case 38: // Browse File [h]otspots
+			return m, m.openFileHotspots()
+		case 41: // [G]lobal Search
+			m.quitting = true
+			m.launchGlobalSearch = true
+			return m, tea.Quit
+		case 42: // Search Commit [m]essages
+			return m, m.openCommitSearch()
 		}
 		return m, nil
 	}
@@ -1701,34 +2189,484 @@ func (m TUIModel) handleMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleLocalSearchInput handles key events for local keyword search input
-func (m TUIModel) handleLocalSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
-		m.localSearchInputVisible = false
-		m.localSearchKeyword = ""
-		return m, nil
-
-	case "enter":
-		if m.localSearchKeyword != "" {
-			m.localSearchInputVisible = false
-			m.switchToLocalSearch(m.localSearchKeyword)
-		}
-		return m, nil
-
-	case "backspace":
-		if len(m.localSearchKeyword) > 0 {
-			m.localSearchKeyword = m.localSearchKeyword[:len(m.localSearchKeyword)-1]
-		}
-		return m, nil
+// exportTabJSON writes the current tab's committers to JSON and reports the result
+func (m *TUIModel) exportTabJSON() {
+	filename, err := ExportTabToJSON(m.stats, m.tags, m.links, m.linkLabels, m.repoOwner, m.repoName, m.totalCommits)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	m.exportMessage = fmt.Sprintf("Exported to %s", filename)
+}
 
-	default:
-		// Add character to keyword (only printable chars)
-		key := msg.String()
-		if len(key) == 1 {
-			m.localSearchKeyword += key
-		}
-		return m, nil
+// exportProjectReportHTML writes the HTML project report and reports the result
+func (m *TUIModel) exportProjectReportHTML() {
+	if m.database == nil {
+		m.exportMessage = "Database not available"
+		return
+	}
+	filename, err := ExportProjectReportHTML(m.database, m.dbPath, m.apiBaseURL)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Project report failed: %v", err)
+		return
+	}
+	m.exportMessage = fmt.Sprintf("Project report exported to %s", filename)
+}
+
+// exportDomains writes the highlight domains to JSON and reports the result
+func (m *TUIModel) exportDomains() {
+	filename, err := ExportDomainsJSON(m.database)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Domain export failed: %v", err)
+		return
+	}
+	m.exportMessage = fmt.Sprintf("Exported %d domains to %s", len(m.highlightDomains), filename)
+}
+
+// exportTaggedContacts writes the current repo's tagged users as contacts and reports the result
+func (m *TUIModel) exportTaggedContacts(format string) {
+	filename, count, err := ExportTaggedContacts(m.database, m.repoOwner, m.repoName, format)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Contacts export failed: %v", err)
+		return
+	}
+	if count == 0 {
+		m.exportMessage = "No tagged users with emails to export"
+		return
+	}
+	m.exportMessage = fmt.Sprintf("Exported %d contacts to %s", count, filename)
+}
+
+// openFileHotspots leaves the TUI to browse the current repo's most changed files
+func (m *TUIModel) openFileHotspots() tea.Cmd {
+	if m.showCombined {
+		m.menuVisible = false
+		m.exportMessage = "File hotspots are per repository - switch to a repository tab first"
+		return nil
+	}
+	m.quitting = true
+	m.launchFileHotspots = true
+	return tea.Quit
+}
+
+// openCommitSearch leaves the TUI to search the current repo's commit messages
+func (m *TUIModel) openCommitSearch() tea.Cmd {
+	if m.showCombined {
+		m.menuVisible = false
+		m.exportMessage = "Commit message search is per repository - switch to a repository tab first"
+		return nil
+	}
+	m.quitting = true
+	m.launchCommitSearch = true
+	return tea.Quit
+}
+
+// exportLinkGroups writes the linked committer identities and reports the result. The
+// combined view exports every repo, merging groups that share an email across repos.
+func (m *TUIModel) exportLinkGroups(format string) {
+	owner, repo := m.repoOwner, m.repoName
+	if m.showCombined {
+		owner, repo = "", ""
+	}
+	filename, count, err := ExportLinkGroups(m.database, owner, repo, format)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Link group export failed: %v", err)
+		return
+	}
+	if count == 0 {
+		m.exportMessage = "No link groups to export"
+		return
+	}
+	m.exportMessage = fmt.Sprintf("Exported %d link groups to %s", count, filename)
+}
+
+// signedCell formats the Signed column: the share of commits signed ("*" when some signatures
+// weren't verified) and the signing key, with "+N" for further keys. Committers whose signing
+// status was never fetched, and co-authors, show "-".
+func signedCell(s models.ContributorStats) string {
+	pct, ok := s.SignedPercent()
+	if !ok || s.IsCoAuthor() {
+		return "-"
+	}
+	cell := fmt.Sprintf("%.0f%%", pct)
+	if s.VerifiedCommits < s.SignedCommits {
+		cell += "*"
+	}
+	if len(s.SigningKeys) > 0 {
+		cell += " " + s.SigningKeys[0]
+		if len(s.SigningKeys) > 1 {
+			cell += fmt.Sprintf(" +%d", len(s.SigningKeys)-1)
+		}
+	}
+	return cell
+}
+
+// linkedDisplayName returns the committer's display name, followed by the link group's
+// label (or "#<id>" for unlabeled groups) when the committer is linked
+func linkedDisplayName(s models.ContributorStats, links map[string]int, labels map[int]string) string {
+	groupID, ok := links[s.Email]
+	if !ok {
+		return s.DisplayName()
+	}
+	return fmt.Sprintf("%s [%s]", s.DisplayName(), models.LinkGroupName(groupID, labels[groupID]))
+}
+
+// linkGroupHasMembers reports whether any email is still linked to groupID
+func linkGroupHasMembers(links map[string]int, groupID int) bool {
+	for _, id := range links {
+		if id == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// showLinkLabelForm opens the label prompt for a link group, prefilled with its current label
+func (m *TUIModel) showLinkLabelForm(groupID int) tea.Cmd {
+	m.linkLabelGroup = groupID
+	m.linkLabelInput = m.linkLabels[groupID]
+	m.linkLabelForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("label").
+				Title(fmt.Sprintf("Label for link group #%d", groupID)).
+				Description("e.g. \"John Doe / @jdoe\", empty to clear").
+				Value(&m.linkLabelInput),
+		),
+	).WithTheme(NewAppTheme())
+	m.linkLabelFormVisible = true
+	return m.linkLabelForm.Init()
+}
+
+// columnTitles are the column picker labels of the optional columns
+var columnTitles = map[string]string{
+	ColumnName:    "Name",
+	ColumnLogin:   "GitHub Login",
+	ColumnEmail:   "Email",
+	ColumnPercent: "Percent",
+}
+
+// showColumnsForm opens the picker of optional committer table columns, checked when shown
+func (m *TUIModel) showColumnsForm() tea.Cmd {
+	visible := m.visibleColumns()
+	options := make([]huh.Option[string], 0, len(OptionalColumns))
+	for _, col := range OptionalColumns {
+		options = append(options, huh.NewOption(columnTitles[col], col).Selected(visible[col]))
+	}
+	m.columnsInput = nil
+	m.columnsForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Key("columns").
+				Title("Columns").
+				Description("Space toggles, Enter applies. Tag, Rank and Commits are always shown").
+				Options(options...).
+				Value(&m.columnsInput),
+		),
+	).WithTheme(NewAppTheme())
+	m.columnsFormVisible = true
+	return m.columnsForm.Init()
+}
+
+// applyVisibleColumns shows the picked optional columns, hides the rest and saves the choice
+// for the project
+func (m *TUIModel) applyVisibleColumns(picked []string) {
+	for _, col := range OptionalColumns {
+		m.setColumnHidden(col, !slices.Contains(picked, col))
+	}
+	m.rebuildTable()
+	if err := saveUIPrefs(m.database, m.currentUIPrefs()); err != nil {
+		m.exportMessage = fmt.Sprintf("Failed to save columns: %v", err)
+		return
+	}
+	if len(m.hiddenColumns) == 0 {
+		m.exportMessage = "Showing all columns"
+	} else {
+		m.exportMessage = fmt.Sprintf("Hiding %d of %d optional columns (c to change)", len(m.hiddenColumns), len(OptionalColumns))
+	}
+}
+
+// applyLinkLabel saves a link group's label and refreshes the rows that show it
+func (m *TUIModel) applyLinkLabel(groupID int, label string) {
+	label = strings.TrimSpace(label)
+	if m.database != nil {
+		if err := m.database.SaveGroupLabel(m.repoOwner, m.repoName, groupID, label); err != nil {
+			m.exportMessage = fmt.Sprintf("Failed to save label: %v", err)
+			return
+		}
+	}
+	if label == "" {
+		delete(m.linkLabels, groupID)
+		m.exportMessage = fmt.Sprintf("Cleared label of link group #%d", groupID)
+	} else {
+		m.linkLabels[groupID] = label
+		m.exportMessage = fmt.Sprintf("Named link group #%d %q", groupID, label)
+	}
+	m.updateRows()
+}
+
+// showImportDomainsForm opens the file path prompt for importing highlight domains
+func (m *TUIModel) showImportDomainsForm() tea.Cmd {
+	m.importDomainsPath = ""
+	m.importDomainsForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("path").
+				Title("Import Highlight Domains").
+				Description("Path to a JSON file from Export Highlight Domains").
+				Value(&m.importDomainsPath),
+		),
+	).WithTheme(NewAppTheme())
+	m.importDomainsFormVisible = true
+	return m.importDomainsForm.Init()
+}
+
+// dateRangeLayout is the date format accepted by the commit date range form
+const dateRangeLayout = "2006-01-02"
+
+// validateRangeDate accepts an empty value (open end) or a YYYY-MM-DD date
+func validateRangeDate(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	if _, err := time.Parse(dateRangeLayout, s); err != nil {
+		return fmt.Errorf("use YYYY-MM-DD, or leave empty")
+	}
+	return nil
+}
+
+// showDateRangeForm opens the from/to prompt for the commit date range, prefilled with the current range
+func (m *TUIModel) showDateRangeForm() tea.Cmd {
+	m.dateRangeFromInput, m.dateRangeToInput = "", ""
+	if !m.dateFrom.IsZero() {
+		m.dateRangeFromInput = m.dateFrom.Format(dateRangeLayout)
+	}
+	if !m.dateTo.IsZero() {
+		m.dateRangeToInput = m.dateTo.Format(dateRangeLayout)
+	}
+	m.dateRangeForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Key("from").
+				Title("From (author date)").
+				Description("YYYY-MM-DD, empty for no start. Clear both to show all commits").
+				Validate(validateRangeDate).
+				Value(&m.dateRangeFromInput),
+			huh.NewInput().
+				Key("to").
+				Title("To (inclusive)").
+				Description("YYYY-MM-DD, empty for no end").
+				Validate(validateRangeDate).
+				Value(&m.dateRangeToInput),
+		),
+	).WithTheme(NewAppTheme())
+	m.dateRangeFormVisible = true
+	return m.dateRangeForm.Init()
+}
+
+// applyDateRange sets the commit date range from the form values and reloads the current repo
+// Both dates are whole days in UTC; empty values leave that end open.
+func (m *TUIModel) applyDateRange(fromInput, toInput string) {
+	var from, to time.Time
+	if s := strings.TrimSpace(fromInput); s != "" {
+		from, _ = time.Parse(dateRangeLayout, s)
+	}
+	if s := strings.TrimSpace(toInput); s != "" {
+		to, _ = time.Parse(dateRangeLayout, s)
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		m.exportMessage = "Date range not changed - end date is before start date"
+		return
+	}
+
+	m.dateFrom, m.dateTo = from, to
+	if m.currentRepoIndex >= 0 && m.currentRepoIndex < len(m.repos) && !m.showCombined && !m.searchActive {
+		m.switchToRepo(m.currentRepoIndex)
+	}
+	if m.hasDateRange() {
+		m.exportMessage = fmt.Sprintf("%d commits in range", m.totalCommits)
+	} else {
+		m.exportMessage = "Date range cleared"
+	}
+}
+
+// hasDateRange reports whether repository stats are limited to a commit date range
+func (m TUIModel) hasDateRange() bool {
+	return !m.dateFrom.IsZero() || !m.dateTo.IsZero()
+}
+
+// dateRangeEnd returns the inclusive upper bound for the range's end date (its last second)
+func (m TUIModel) dateRangeEnd() time.Time {
+	if m.dateTo.IsZero() {
+		return time.Time{}
+	}
+	return m.dateTo.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// dateRangeLabel returns the footer indicator for the commit date range, e.g. "range: 2024-01-01..2024-03-31"
+func (m TUIModel) dateRangeLabel() string {
+	from, to := "…", "…"
+	if !m.dateFrom.IsZero() {
+		from = m.dateFrom.Format(dateRangeLayout)
+	}
+	if !m.dateTo.IsZero() {
+		to = m.dateTo.Format(dateRangeLayout)
+	}
+	return fmt.Sprintf("range: %s..%s", from, to)
+}
+
+// importDomains loads highlight domains from path and refreshes the in-memory list
+func (m *TUIModel) importDomains(path string) {
+	if path == "" {
+		return
+	}
+	imported, err := ImportDomainsJSON(m.database, path)
+	if err != nil {
+		m.exportMessage = fmt.Sprintf("Domain import failed: %v", err)
+		return
+	}
+
+	if domains, err := m.database.GetDomains(); err == nil {
+		m.highlightDomains = domains
+		m.domainList = make([]string, 0, len(domains))
+		for domain := range domains {
+			m.domainList = append(m.domainList, domain)
+		}
+	}
+	m.exportMessage = fmt.Sprintf("Imported %d domains from %s", imported, filepath.Base(path))
+}
+
+// handleFilterInput handles key events for the committer filter input
+// Rows narrow live as the filter text changes.
+func (m TUIModel) handleFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.filterInputActive = false
+		m.clearFilter()
+		return m, nil
+
+	case "enter":
+		// Keep the filter and return to table navigation
+		m.filterInputActive = false
+		if m.filterText == "" {
+			m.clearFilter()
+		}
+		return m, nil
+
+	case "backspace":
+		if len(m.filterText) > 0 {
+			m.filterText = m.filterText[:len(m.filterText)-1]
+			m.applyFilter()
+		}
+		return m, nil
+
+	default:
+		// Add character to filter (only printable chars)
+		key := msg.String()
+		if len(key) == 1 {
+			m.filterText += key
+			m.applyFilter()
+		}
+		return m, nil
+	}
+}
+
+// applyFilter narrows m.stats to committers whose name, login, or email contains filterText
+// The full list is kept in unfilteredStats; tags and links are keyed by email so they survive.
+func (m *TUIModel) applyFilter() {
+	if m.unfilteredStats == nil {
+		m.unfilteredStats = m.stats
+	} else {
+		m.syncFilteredStats()
+	}
+
+	needle := strings.ToLower(m.filterText)
+	filtered := make([]models.ContributorStats, 0, len(m.unfilteredStats))
+	for _, s := range m.unfilteredStats {
+		if needle == "" ||
+			strings.Contains(strings.ToLower(s.Name), needle) ||
+			strings.Contains(strings.ToLower(s.GitHubLogin), needle) ||
+			strings.Contains(strings.ToLower(s.Email), needle) {
+			filtered = append(filtered, s)
+		}
+	}
+
+	m.stats = filtered
+	m.pendingLinks = nil // Row indices change with the filter
+	m.rebuildTable()
+}
+
+// clearFilter restores all rows, keeping any edits made while filtered
+func (m *TUIModel) clearFilter() {
+	m.filterText = ""
+	if m.unfilteredStats == nil {
+		return
+	}
+	m.syncFilteredStats()
+	m.stats = m.unfilteredStats
+	m.unfilteredStats = nil
+	m.pendingLinks = nil
+	m.rebuildTable()
+}
+
+// resetFilter drops the filter without restoring rows (used when m.stats is reloaded)
+func (m *TUIModel) resetFilter() {
+	m.filterInputActive = false
+	m.filterText = ""
+	m.unfilteredStats = nil
+}
+
+// syncFilteredStats copies edits to visible rows back into unfilteredStats
+func (m *TUIModel) syncFilteredStats() {
+	byEmail := make(map[string]models.ContributorStats, len(m.stats))
+	for _, s := range m.stats {
+		byEmail[s.Email] = s
+	}
+	for i, s := range m.unfilteredStats {
+		if edited, ok := byEmail[s.Email]; ok {
+			m.unfilteredStats[i] = edited
+		}
+	}
+}
+
+// filterLabel returns the footer indicator for the current filter, e.g. "filter: bob (3 matches)"
+func (m TUIModel) filterLabel() string {
+	return fmt.Sprintf("filter: %s (%d matches)", m.filterText, len(m.stats))
+}
+
+// handleLocalSearchInput handles key events for local keyword search input
+func (m TUIModel) handleLocalSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.localSearchInputVisible = false
+		m.localSearchKeyword = ""
+		return m, nil
+
+	case "enter":
+		if m.localSearchKeyword != "" {
+			m.localSearchInputVisible = false
+			if m.localSearchCommitters {
+				m.switchToCommitterSearch(m.localSearchKeyword)
+			} else {
+				m.switchToLocalSearch(m.localSearchKeyword)
+			}
+		}
+		return m, nil
+
+	case "backspace":
+		if len(m.localSearchKeyword) > 0 {
+			m.localSearchKeyword = m.localSearchKeyword[:len(m.localSearchKeyword)-1]
+		}
+		return m, nil
+
+	default:
+		// Add character to keyword (only printable chars)
+		key := msg.String()
+		if len(key) == 1 {
+			m.localSearchKeyword += key
+		}
+		return m, nil
 	}
 }
 
@@ -1773,6 +2711,12 @@ func (m TUIModel) handleSearchPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case 3: // Local keyword search
 			m.searchPickerVisible = false
 			m.localSearchInputVisible = true
+			m.localSearchCommitters = false
+			m.localSearchKeyword = ""
+		case 4: // Committer search across all repos
+			m.searchPickerVisible = false
+			m.localSearchInputVisible = true
+			m.localSearchCommitters = true
 			m.localSearchKeyword = ""
 		}
 		return m, nil
@@ -1815,6 +2759,7 @@ func (m TUIModel) handleDomainConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.database != nil {
 				m.database.RemoveDomain(domain)
 			}
+			m.undo = nil
 			// Adjust cursor if needed
 			if m.domainCursor >= len(m.domainList) && m.domainCursor > 0 {
 				m.domainCursor--
@@ -1826,6 +2771,19 @@ func (m TUIModel) handleDomainConfig(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// lowestUnusedColorIndex returns the smallest color index not assigned to any domain
+func lowestUnusedColorIndex(domains map[string]int) int {
+	used := make(map[int]bool, len(domains))
+	for _, idx := range domains {
+		used[idx] = true
+	}
+	next := 0
+	for used[next] {
+		next++
+	}
+	return next
+}
+
 // handleDomainInput handles text input for adding new domains
 func (m TUIModel) handleDomainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -1837,21 +2795,14 @@ func (m TUIModel) handleDomainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		// Add the domain if input is not empty
 		domain := strings.TrimSpace(m.domainInput)
-		if domain != "" && m.highlightDomains[domain] == 0 {
-			// Get next color index
-			colorIndex := 0
+		if _, exists := m.highlightDomains[domain]; domain != "" && !exists {
+			// Lowest unused color index, so existing domains never change color
+			colorIndex := lowestUnusedColorIndex(m.highlightDomains)
 			if m.database != nil {
 				nextIdx, err := m.database.GetNextDomainColorIndex()
 				if err == nil {
 					colorIndex = nextIdx
 				}
-			} else {
-				// Find max color index in current map
-				for _, idx := range m.highlightDomains {
-					if idx >= colorIndex {
-						colorIndex = idx + 1
-					}
-				}
 			}
 
 			m.highlightDomains[domain] = colorIndex
@@ -1859,6 +2810,7 @@ func (m TUIModel) handleDomainInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.database != nil {
 				m.database.SaveDomain(domain, colorIndex)
 			}
+			m.undo = nil
 		}
 		m.domainInputActive = false
 		m.domainInput = ""
@@ -1908,6 +2860,7 @@ func (m TUIModel) handleAddRepoInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					}
 					// Add to tracked repos
 					m.database.AddTrackedRepo(owner, name)
+					m.undo = nil
 					// Add to local list
 					newRepo := models.RepoInfo{Owner: owner, Name: name}
 					m.repos = append(m.repos, newRepo)
@@ -1966,9 +2919,17 @@ func (m *TUIModel) switchToRepo(index int) {
 	repo := m.repos[index]
 	m.repoOwner = repo.Owner
 	m.repoName = repo.Name
+	m.resetFilter()
 
-	// Load stats
-	stats, total, err := m.database.GetCommitterStats(repo.Owner, repo.Name)
+	// Load stats, limited to the commit date range if one is set
+	var stats []models.ContributorStats
+	var total int
+	var err error
+	if m.hasDateRange() {
+		stats, total, err = m.database.GetCommitterStatsInRange(repo.Owner, repo.Name, m.dateFrom, m.dateRangeEnd())
+	} else {
+		stats, total, err = m.database.GetCommitterStats(repo.Owner, repo.Name)
+	}
 	if err != nil {
 		stats = []models.ContributorStats{}
 		total = 0
@@ -1983,6 +2944,12 @@ func (m *TUIModel) switchToRepo(index int) {
 	}
 	m.links = links
 
+	labels, err := m.database.GetGroupLabels(repo.Owner, repo.Name)
+	if err != nil {
+		labels = make(map[int]string)
+	}
+	m.linkLabels = labels
+
 	// Load tags
 	tags, err := m.database.GetTags(repo.Owner, repo.Name)
 	if err != nil {
@@ -2016,6 +2983,7 @@ func (m *TUIModel) switchToCombined() {
 
 	m.repoOwner = "Combined"
 	m.repoName = "All Repos"
+	m.resetFilter()
 
 	// Load combined stats
 	stats, total, err := m.database.GetCombinedCommitterStats()
@@ -2028,6 +2996,7 @@ func (m *TUIModel) switchToCombined() {
 
 	// Clear repo-specific data for combined view
 	m.links = make(map[string]int)
+	m.linkLabels = make(map[int]string)
 	m.tags = make(map[string]bool)
 	m.pendingLinks = nil
 
@@ -2075,6 +3044,30 @@ func (m *TUIModel) switchToSearch(queryType string) {
 		total = 0
 	}
 
+	m.showSearchResults(stats, total)
+}
+
+// switchToCommitterSearch finds committers across all repos by name, email or login
+func (m *TUIModel) switchToCommitterSearch(keyword string) {
+	if m.database == nil || keyword == "" {
+		return
+	}
+
+	stats, total, err := m.database.SearchCommittersGlobal(keyword)
+	if err != nil {
+		stats = []models.ContributorStats{}
+		total = 0
+	}
+	m.searchQuery = "Committers: " + keyword
+	if len(stats) == db.GlobalCommitterSearchLimit {
+		m.exportMessage = fmt.Sprintf("Showing the top %d matches - refine the keyword to see more", db.GlobalCommitterSearchLimit)
+	}
+	m.showSearchResults(stats, total)
+}
+
+// showSearchResults displays search results in the Search tab
+func (m *TUIModel) showSearchResults(stats []models.ContributorStats, total int) {
+	m.resetFilter()
 	m.stats = stats
 	m.totalCommits = total
 	m.searchActive = true
@@ -2084,6 +3077,7 @@ func (m *TUIModel) switchToSearch(queryType string) {
 
 	// Clear repo-specific data for search view
 	m.links = make(map[string]int)
+	m.linkLabels = make(map[int]string)
 	m.tags = make(map[string]bool)
 	m.pendingLinks = nil
 
@@ -2163,6 +3157,7 @@ func (m *TUIModel) switchToLocalSearch(keyword string) {
 		stats = append(stats, s)
 	}
 
+	m.resetFilter()
 	m.stats = stats
 	m.totalCommits = len(results)
 	m.searchActive = true
@@ -2173,6 +3168,7 @@ func (m *TUIModel) switchToLocalSearch(keyword string) {
 
 	// Clear repo-specific data for search view
 	m.links = make(map[string]int)
+	m.linkLabels = make(map[int]string)
 	m.tags = make(map[string]bool)
 	m.pendingLinks = nil
 
@@ -2191,14 +3187,261 @@ func (m *TUIModel) switchToLocalSearch(keyword string) {
 	m.rebuildTable()
 }
 
+// tagAllVisible tags every displayed committer that can be scanned and isn't already processed
+// Like T, tagging one email tags all rows sharing its GitHub login (including rows hidden by a filter).
+// Returns the number of newly tagged emails.
+func (m *TUIModel) tagAllVisible() int {
+	all := m.stats
+	if m.unfilteredStats != nil {
+		all = m.unfilteredStats
+	}
+
+	logins := make(map[string]bool)
+	for _, s := range m.stats {
+		if isServiceAccount(s.GitHubLogin, s.Email) || m.processedLogins[s.GitHubLogin] {
+			continue
+		}
+		logins[s.GitHubLogin] = true
+	}
+
+	count := 0
+	for _, s := range all {
+		if !logins[s.GitHubLogin] || m.tags[s.Email] {
+			continue
+		}
+		m.tags[s.Email] = true
+		if m.database != nil {
+			m.database.SaveTag(m.repoOwner, m.repoName, s.Email)
+		}
+		count++
+	}
+	return count
+}
+
+// cycleSort advances the committer sort to the next key/direction
+// Commits start descending and text keys ascending; the second press reverses.
+func (m *TUIModel) cycleSort() {
+	defaultAsc := committerSortKeys[m.sortKeyIndex] != "commits"
+	if m.sortAsc == defaultAsc {
+		m.sortAsc = !m.sortAsc
+		return
+	}
+	m.sortKeyIndex = (m.sortKeyIndex + 1) % len(committerSortKeys)
+	m.sortAsc = committerSortKeys[m.sortKeyIndex] != "commits"
+}
+
+// sortLabel returns the footer indicator for the current sort, e.g. "sort: commits ↓"
+func (m TUIModel) sortLabel() string {
+	arrow := "↓"
+	if m.sortAsc {
+		arrow = "↑"
+	}
+	return fmt.Sprintf("sort: %s %s", committerSortKeys[m.sortKeyIndex], arrow)
+}
+
+// uiPrefs is the TUI state saved per project (in the project database's settings) on exit
+// and restored on the next launch
+type uiPrefs struct {
+	Repo        string `json:"repo,omitempty"` // "owner/name" of the last repo tab, empty for Combined
+	SortKey     string `json:"sort_key"`
+	SortAsc     bool   `json:"sort_asc"`
+	Filter      string `json:"filter,omitempty"`
+	HelpVisible bool   `json:"help_visible"`
+	ShowSigned  bool   `json:"show_signed,omitempty"`
+	// Optional columns the user hid; stored as hidden so columns added later default to shown
+	HiddenColumns []string `json:"hidden_columns,omitempty"`
+}
+
+// loadUIPrefs reads the saved TUI state, returning false if none was saved or it can't be read
+func loadUIPrefs(database *db.DB) (uiPrefs, bool) {
+	var prefs uiPrefs
+	if database == nil {
+		return prefs, false
+	}
+	value, err := database.GetSetting(db.SettingUIPrefs)
+	if err != nil || value == "" {
+		return prefs, false
+	}
+	if err := json.Unmarshal([]byte(value), &prefs); err != nil {
+		return prefs, false
+	}
+	return prefs, true
+}
+
+// saveUIPrefs stores the TUI state for the next launch
+func saveUIPrefs(database *db.DB, prefs uiPrefs) error {
+	if database == nil {
+		return nil
+	}
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to encode UI preferences: %w", err)
+	}
+	return database.SetSetting(db.SettingUIPrefs, string(data))
+}
+
+// currentUIPrefs captures the state worth restoring; the Search tab is saved as Combined
+func (m TUIModel) currentUIPrefs() uiPrefs {
+	prefs := uiPrefs{
+		SortKey:     committerSortKeys[m.sortKeyIndex],
+		SortAsc:     m.sortAsc,
+		HelpVisible: m.helpVisible,
+		ShowSigned:  m.showSigned,
+	}
+	for _, col := range OptionalColumns {
+		if m.hiddenColumns[col] {
+			prefs.HiddenColumns = append(prefs.HiddenColumns, col)
+		}
+	}
+	if m.currentRepoIndex >= 0 && m.currentRepoIndex < len(m.repos) && !m.showCombined && !m.searchActive {
+		repo := m.repos[m.currentRepoIndex]
+		prefs.Repo = repo.Owner + "/" + repo.Name
+	}
+	if m.unfilteredStats != nil {
+		prefs.Filter = m.filterText
+	}
+	return prefs
+}
+
+// applyUIPrefs restores saved state; a repo no longer tracked or an unknown sort key falls
+// back to the Combined tab and the default sort
+func (m *TUIModel) applyUIPrefs(prefs uiPrefs) {
+	m.helpVisible = prefs.HelpVisible
+	m.showSigned = prefs.ShowSigned
+	m.hiddenColumns = nil
+	for _, col := range prefs.HiddenColumns {
+		m.setColumnHidden(col, true)
+	}
+
+	m.sortKeyIndex, m.sortAsc = 0, false
+	for i, key := range committerSortKeys {
+		if key == prefs.SortKey {
+			m.sortKeyIndex, m.sortAsc = i, prefs.SortAsc
+			break
+		}
+	}
+
+	m.currentRepoIndex, m.showCombined = -1, true
+	for i, repo := range m.repos {
+		if strings.EqualFold(repo.Owner+"/"+repo.Name, prefs.Repo) {
+			m.currentRepoIndex, m.showCombined = i, false
+			break
+		}
+	}
+	if m.showCombined {
+		m.switchToCombined()
+	} else {
+		m.switchToRepo(m.currentRepoIndex)
+	}
+
+	if prefs.Filter != "" {
+		m.filterText = prefs.Filter
+		m.applyFilter()
+	}
+}
+
+// sortStats orders m.stats by the current sort key so rank matches display order
+func (m *TUIModel) sortStats() {
+	key := committerSortKeys[m.sortKeyIndex]
+	asc := m.sortAsc
+	sort.SliceStable(m.stats, func(i, j int) bool {
+		a, b := m.stats[i], m.stats[j]
+		var less, greater bool
+		switch key {
+		case "name":
+			x, y := strings.ToLower(a.Name), strings.ToLower(b.Name)
+			less, greater = x < y, x > y
+		case "login":
+			x, y := strings.ToLower(a.GitHubLogin), strings.ToLower(b.GitHubLogin)
+			less, greater = x < y, x > y
+		case "email":
+			x, y := strings.ToLower(a.Email), strings.ToLower(b.Email)
+			less, greater = x < y, x > y
+		default:
+			less, greater = a.CommitCount < b.CommitCount, a.CommitCount > b.CommitCount
+		}
+		if asc {
+			return less
+		}
+		return greater
+	})
+}
+
+// visibleColumns returns the optional columns currently shown
+func (m TUIModel) visibleColumns() ColumnSet {
+	visible := AllColumns()
+	for col := range m.hiddenColumns {
+		delete(visible, col)
+	}
+	return visible
+}
+
+// setColumnHidden hides or shows an optional column, ignoring unknown names
+func (m *TUIModel) setColumnHidden(col string, hidden bool) {
+	if !slices.Contains(OptionalColumns, col) {
+		return
+	}
+	if m.hiddenColumns == nil {
+		m.hiddenColumns = make(map[string]bool)
+	}
+	if hidden {
+		m.hiddenColumns[col] = true
+	} else {
+		delete(m.hiddenColumns, col)
+	}
+}
+
+// committerRowColumns maps each cell of a full committer row to its optional column ("" = always shown)
+var committerRowColumns = []string{"", "", ColumnName, ColumnLogin, ColumnEmail, "", ColumnPercent}
+
+// visibleCells drops the cells of hidden optional columns from a full committer row
+func visibleCells(row table.Row, visible ColumnSet) table.Row {
+	cells := make(table.Row, 0, len(row))
+	for i, cell := range row {
+		if i < len(committerRowColumns) && committerRowColumns[i] != "" && !visible[committerRowColumns[i]] {
+			continue
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
+
+// mainTableHeight returns the main table height that fills the repo view's main box
+// The rendered table takes one line more than its height (header + divider + rows).
+func (m TUIModel) mainTableHeight() int {
+	// Main box chrome: page indicator + blank line, table overflow line, divider + stats row
+	h := m.layout.MainContentHeight() - 5
+	if len(m.repos) == 0 {
+		h += 2 // no page indicator
+	}
+	if h < MinTableHeight {
+		return MinTableHeight
+	}
+	return h
+}
+
+// userDetailTableHeight returns the repos/gists table height that fits the user detail border
+func (m TUIModel) userDetailTableHeight() int {
+	// Border content is ViewportHeight - 4; tab line + blank line + table overflow line
+	h := m.layout.ViewportHeight - 4 - 3
+	if h < MinTableHeight {
+		return MinTableHeight
+	}
+	return h
+}
+
 // rebuildTable recreates the table with current stats
 func (m *TUIModel) rebuildTable() {
 	// Save current cursor position before rebuilding
 	oldCursor := m.table.Cursor()
 
+	// Apply the selected sort before numbering rows
+	m.sortStats()
+
 	// Calculate column widths based on actual data content, constrained to fit viewport
-	widths := calculateColumnWidths(m.stats, m.layout.TableWidth)
-	columns := BuildTableColumns(widths)
+	visible := m.visibleColumns()
+	widths := calculateColumnWidths(m.stats, m.layout.TableWidth, m.showSigned, visible)
+	columns := BuildTableColumns(widths, visible)
 
 	// Rebuild processed logins cache
 	m.processedLogins = make(map[string]bool)
@@ -2230,14 +3473,17 @@ func (m *TUIModel) rebuildTable() {
 			login = "-"
 		}
 
-		rows[i] = table.Row{
+		rows[i] = visibleCells(table.Row{
 			tagMark,
 			fmt.Sprintf("%d", i+1),
-			s.Name,
+			linkedDisplayName(s, m.links, m.linkLabels),
 			login,
 			s.Email,
 			fmt.Sprintf("%d", s.CommitCount),
 			fmt.Sprintf("%.1f%%", s.Percentage),
+		}, visible)
+		if m.showSigned {
+			rows[i] = append(rows[i], signedCell(s))
 		}
 	}
 
@@ -2245,7 +3491,7 @@ func (m *TUIModel) rebuildTable() {
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(m.layout.TableHeight),
+		table.WithHeight(m.mainTableHeight()),
 	)
 
 	// Apply styles using centralized function from styles.go
@@ -2264,6 +3510,11 @@ func (m *TUIModel) rebuildTable() {
 	m.table = t
 }
 
+// newGitHubClient creates an API client for the configured token and base URL
+func (m *TUIModel) newGitHubClient() *api.Client {
+	return api.NewClientWithBaseURL(m.token, m.apiBaseURL)
+}
+
 // startFetch returns a tea.Cmd that fetches commits from the GitHub API
 func (m *TUIModel) startFetch(owner, name string) tea.Cmd {
 	return func() tea.Msg {
@@ -2271,49 +3522,474 @@ func (m *TUIModel) startFetch(owner, name string) tea.Cmd {
 			return fetchCompleteMsg{owner: owner, name: name, err: fmt.Errorf("no GitHub token")}
 		}
 
-		var client *api.Client
-		if m.dbPath != "" {
-			client = api.NewClientWithLogging(m.token, m.dbPath)
-		} else {
-			client = api.NewClient(m.token)
+		client := m.newGitHubClient()
+		client.SetFetchFiles(m.fetchFiles)
+
+		// Get latest SHA for incremental fetch
+		var latestSHA string
+		if m.database != nil {
+			latestSHA, _ = m.database.GetLatestCommitSHA(owner, name)
+			client.SetETagStore(m.database)
+		}
+
+		commits, err := client.FetchCommits(owner, name, latestSHA, nil)
+		if err != nil {
+			return fetchCompleteMsg{owner: owner, name: name, err: err}
+		}
+
+		// Best effort: commits keep an empty login for IDs that can't be looked up now
+		if m.database != nil {
+			client.SetLoginCache(m.database)
+		}
+		client.ResolveNoreplyUserIDs(commits)
+
+		return fetchCompleteMsg{owner: owner, name: name, commits: commits}
+	}
+}
+
+// recordFetch logs a commit fetch and stores any fetched commits
+func (m *TUIModel) recordFetch(msg fetchCompleteMsg) {
+	if m.database == nil {
+		return
+	}
+
+	// Log API call to database
+	endpoint := fmt.Sprintf("/repos/%s/%s/commits", msg.owner, msg.name)
+	errorMsg := ""
+	statusCode := 200
+	if msg.err != nil {
+		errorMsg = msg.err.Error()
+		statusCode = 0
+	}
+	m.database.SaveAPILog("GET", endpoint, statusCode, errorMsg, 0, "", "")
+
+	// Store commits in database
+	if msg.err == nil && len(msg.commits) > 0 {
+		records := make([]models.CommitRecord, len(msg.commits))
+		for i, c := range msg.commits {
+			records[i] = c.ToRecord(msg.owner, msg.name)
+		}
+		m.database.InsertCommits(records)
+		// Ensure repo is tracked in database
+		m.database.AddTrackedRepo(msg.owner, msg.name)
+		m.undo = nil
+	}
+}
+
+// startFetchAll refreshes every tracked repo in turn using incremental fetches
+func (m *TUIModel) startFetchAll() tea.Cmd {
+	if m.token == "" {
+		m.exportMessage = "GitHub token required to fetch repositories"
+		return nil
+	}
+	if len(m.repos) == 0 {
+		m.exportMessage = "No tracked repositories to fetch"
+		return nil
+	}
+
+	m.fetchAllQueue = append([]models.RepoInfo(nil), m.repos...)
+	m.fetchAllIndex = 0
+	m.fetchAllNew = 0
+	m.fetchAllFailed = 0
+	return m.fetchNextQueuedRepo()
+}
+
+// fetchNextQueuedRepo starts the fetch for the current fetch-all repo
+func (m *TUIModel) fetchNextQueuedRepo() tea.Cmd {
+	repo := m.fetchAllQueue[m.fetchAllIndex]
+	m.fetchingRepo = &repo
+
+	label := fmt.Sprintf("Repo %d/%d: %s/%s", m.fetchAllIndex+1, len(m.fetchAllQueue), repo.Owner, repo.Name)
+	m.fetchProgress = label
+	m.showProgress = true
+	m.progressLabel = label
+	m.progressPercent = float64(m.fetchAllIndex) / float64(len(m.fetchAllQueue))
+
+	return tea.Batch(m.progressBar.SetPercent(m.progressPercent), m.startSpinner(), m.startFetch(repo.Owner, repo.Name))
+}
+
+// handleFetchAllComplete records one fetch-all result and chains to the next repo
+func (m TUIModel) handleFetchAllComplete(msg fetchCompleteMsg) (tea.Model, tea.Cmd) {
+	m.recordFetch(msg)
+
+	if msg.err != nil {
+		m.fetchAllFailed++
+		// A bad token or a rate limit would fail every remaining repo the same way
+		remaining := len(m.fetchAllQueue) - m.fetchAllIndex - 1
+		var rateErr *api.RateLimitError
+		if errors.Is(msg.err, api.ErrUnauthorized) {
+			m.fetchAllFailed += remaining
+			m.finishFetchAll()
+			m.exportMessage += " - GitHub token invalid or expired"
+			return m, nil
+		}
+		if errors.As(msg.err, &rateErr) {
+			m.fetchAllFailed += remaining
+			m.finishFetchAll()
+			m.exportMessage += fmt.Sprintf(" - rate limited until %s", rateErr.Reset.Format("15:04:05"))
+			return m, nil
+		}
+	} else {
+		m.fetchAllNew += len(msg.commits)
+	}
+
+	m.fetchAllIndex++
+	if m.fetchAllIndex < len(m.fetchAllQueue) {
+		return m, m.fetchNextQueuedRepo()
+	}
+
+	m.finishFetchAll()
+	return m, nil
+}
+
+// finishFetchAll ends a fetch-all run, reloads the current tab and reports a summary
+func (m *TUIModel) finishFetchAll() {
+	total := len(m.fetchAllQueue)
+	m.fetchAllQueue = nil
+	m.fetchingRepo = nil
+	m.fetchProgress = ""
+	m.showProgress = false
+	m.progressPercent = 0.0
+	m.progressLabel = ""
+
+	m.repoViewVisible = true
+	m.menuVisible = false
+
+	// Reload the visible tab so new commits show up
+	if !m.searchActive {
+		if m.showCombined {
+			m.switchToCombined()
+		} else {
+			m.switchToRepo(m.currentRepoIndex)
+		}
+	}
+
+	m.exportMessage = fmt.Sprintf("Fetched %s new commits across %d repos", formatCount(m.fetchAllNew), total)
+	if m.fetchAllFailed > 0 {
+		m.exportMessage += fmt.Sprintf(", %d failed", m.fetchAllFailed)
+	}
+}
+
+// busy reports whether a background fetch or user query is running
+func (m TUIModel) busy() bool {
+	return m.fetchingRepo != nil || m.queryingUsers
+}
+
+// startSpinner starts the activity spinner's tick loop unless one is already running
+func (m *TUIModel) startSpinner() tea.Cmd {
+	if m.spinning {
+		return nil
+	}
+	m.spinning = true
+	return m.spinner.Tick
+}
+
+// spinnerView renders the activity spinner, or nothing when no work is running
+func (m TUIModel) spinnerView() string {
+	if !m.busy() || !m.spinning {
+		return ""
+	}
+	return m.spinner.View() + " "
+}
+
+// formatCount formats n with thousands separators (e.g. 1,204)
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// userQueryConcurrency is the number of GitHub user queries run at once
+const userQueryConcurrency = 3
+
+// startUserQueries begins querying logins with up to userQueryConcurrency requests in flight
+// Results arrive as userQueryCompleteMsg and are saved in Update, so database writes stay serialized.
+func (m *TUIModel) startUserQueries(logins []string) tea.Cmd {
+	m.queryingUsers = true
+	m.queryTotal = len(logins)
+	m.queryCompleted = 0
+	m.queryFailed = 0
+	m.queryInFlight = 0
+	m.queryLoginsToFetch = logins
+	m.queryBatch++
+	m.failedLogins = make(map[string]bool)
+	m.queryTruncated = 0
+
+	// Show progress bar from the start
+	m.showProgress = true
+	m.progressPercent = 0.0
+	m.progressLabel = fmt.Sprintf("Querying users... 0/%d", len(logins))
+
+	cmds := []tea.Cmd{m.progressBar.SetPercent(m.progressPercent), m.startSpinner()}
+	for i := 0; i < userQueryConcurrency && len(m.queryLoginsToFetch) > 0; i++ {
+		cmds = append(cmds, m.dispatchNextUserQuery())
+	}
+	return tea.Batch(cmds...)
+}
+
+// saveQueriedUser stores fetched user data and marks the login processed
+func (m *TUIModel) saveQueriedUser(login string, data *models.UserData) {
+	m.database.SaveUserProfile(data.Profile)
+	m.database.SaveUserRepositories(data.Repositories)
+	m.database.SaveUserGists(data.Gists)
+	m.database.SaveUserPublicKeys(login, data.PublicKeys)
+	m.database.SaveUserEvents(login, data.Events)
+	// Mark this login as processed so it shows [!] instead of [x]
+	if m.processedLogins == nil {
+		m.processedLogins = make(map[string]bool)
+	}
+	m.processedLogins[login] = true
+}
+
+// cancelUserQueries stops dispatching queued logins and returns to the table
+// Data already fetched is kept; in-flight results are still saved when they arrive.
+func (m *TUIModel) cancelUserQueries() {
+	m.queryLoginsToFetch = nil
+	m.queryingUsers = false
+	m.queryProgress = ""
+	m.showProgress = false
+	m.progressPercent = 0.0
+	m.progressLabel = ""
+	m.updateRows()
+	m.exportMessage = fmt.Sprintf("Cancelled after %d/%d users", m.queryCompleted, m.queryTotal)
+}
+
+// dispatchNextUserQuery pops the next queued login and returns its query command
+func (m *TUIModel) dispatchNextUserQuery() tea.Cmd {
+	login := m.queryLoginsToFetch[0]
+	m.queryLoginsToFetch = m.queryLoginsToFetch[1:]
+	m.queryInFlight++
+	return m.startUserQuery(login, m.queryCompleted+m.queryInFlight, m.queryTotal)
+}
+
+// startUserQuery returns a tea.Cmd that fetches user repos and gists
+func (m *TUIModel) startUserQuery(login string, _, _ int) tea.Cmd {
+	batch := m.queryBatch
+	return func() tea.Msg {
+		if m.token == "" {
+			return userQueryCompleteMsg{login: login, batch: batch, err: fmt.Errorf("no GitHub token")}
+		}
+
+		client := m.newGitHubClient()
+		client.SetMaxUserPages(m.maxUserPages)
+
+		userData, err := client.FetchUserReposAndGists(login)
+		if err != nil {
+			return userQueryCompleteMsg{login: login, batch: batch, err: err}
+		}
+
+		return userQueryCompleteMsg{login: login, batch: batch, data: userData}
+	}
+}
+
+// Screen rows used for mouse hit-testing (row 0 is the top border of the main box)
+const (
+	repoViewTabsRow       = 1 // page indicator (only shown with tracked repos)
+	repoViewFirstDataRow  = 3 // first table row without the page indicator (header + divider above)
+	userDetailTabsRow     = 2 // after the top margin and border
+	userDetailFirstRow    = 6 // tabs, blank line, header, divider
+	userDetailLabelPrefix = "User: "
+)
+
+// handleMouse handles wheel scrolling and clicks in the repo table and user detail view
+func (m TUIModel) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.fetchPromptRepo != nil || m.fetchingRepo != nil || m.queryingUsers || m.filterInputActive || m.rowJump.Active {
+		return m, nil
+	}
+	if m.userDetailVisible {
+		return m.handleUserDetailMouse(msg)
+	}
+	if !m.repoViewVisible || m.menuVisible || m.addRepoVisible || m.domainConfigVisible ||
+		m.searchPickerVisible || m.localSearchInputVisible {
+		return m, nil
+	}
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.table.MoveUp(1)
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.table.MoveDown(1)
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		firstRow := repoViewFirstDataRow
+		if len(m.repos) > 0 {
+			if msg.Y == repoViewTabsRow {
+				m.clickPageTab(msg.X)
+				return m, nil
+			}
+			firstRow += 2 // page indicator + blank line
+		}
+		row := msg.Y - firstRow
+		if row < 0 || row >= m.table.Height() {
+			return m, nil
+		}
+		idx := tableScrollStart(m.table.Cursor(), m.table.Height(), len(m.stats)) + row
+		if idx < len(m.stats) {
+			m.table.SetCursor(idx)
+		}
+	}
+	return m, nil
+}
+
+// clickPageTab switches to the repo tab under column x of the page indicator
+func (m *TUIModel) clickPageTab(x int) {
+	if m.searchActive || len(m.pendingLinks) > 0 {
+		return
+	}
+	parts, labelIdx, activeIdx, _ := m.visiblePageTabs()
+	// Tabs start after the left border, arrow and space
+	hit := tabAt(x, 3, parts)
+	if hit < 0 || labelIdx[hit] == activeIdx {
+		return
+	}
+	if label := labelIdx[hit]; label == 0 {
+		m.showCombined = true
+		m.currentRepoIndex = -1
+		m.switchToCombined()
+	} else if label <= len(m.repos) {
+		m.showCombined = false
+		m.currentRepoIndex = label - 1
+		m.switchToRepo(m.currentRepoIndex)
+	}
+}
+
+// handleUserDetailMouse handles wheel scrolling, tab clicks and row clicks in the user detail view
+func (m TUIModel) handleUserDetailMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		return m.handleUserDetailView(tea.KeyMsg{Type: tea.KeyUp})
+	case msg.Button == tea.MouseButtonWheelDown:
+		return m.handleUserDetailView(tea.KeyMsg{Type: tea.KeyDown})
+	case msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress:
+		return m, nil
+	}
+
+	if msg.Y == userDetailTabsRow {
+		var tabs []string
+		for i, label := range m.userDetailTabLabels() {
+			if i == m.userDetailTab {
+				tabs = append(tabs, TabActiveStyle.Render(label))
+			} else {
+				tabs = append(tabs, TabInactiveStyle.Render(label))
+			}
 		}
-
-		// Get latest SHA for incremental fetch
-		var latestSHA string
-		if m.database != nil {
-			latestSHA, _ = m.database.GetLatestCommitSHA(owner, name)
+		if tab := tabAt(msg.X, 1+StringWidth(userDetailLabelPrefix), tabs); tab >= 0 && tab != m.userDetailTab {
+			m.selectUserDetailTab(tab)
 		}
+		return m, nil
+	}
 
-		commits, err := client.FetchCommits(owner, name, latestSHA, nil)
-		if err != nil {
-			return fetchCompleteMsg{owner: owner, name: name, err: err}
+	row := msg.Y - userDetailFirstRow
+	if row < 0 {
+		return m, nil
+	}
+	switch m.userDetailTab {
+	case 0:
+		// Clicking a profile row selects and opens it, like Enter
+		if row < len(m.userProfileRows) {
+			m.userDetailCursor = row
+			return m.handleUserDetailView(tea.KeyMsg{Type: tea.KeyEnter})
+		}
+	case 1:
+		t := m.userReposTable
+		if row < t.Height() {
+			if idx := tableScrollStart(t.Cursor(), t.Height(), len(t.Rows())) + row; idx < len(t.Rows()) {
+				m.userReposTable.SetCursor(idx)
+			}
+		}
+	case 2:
+		t := m.userGistsTable
+		if row < t.Height() {
+			if idx := tableScrollStart(t.Cursor(), t.Height(), len(t.Rows())) + row; idx < len(t.Rows()) {
+				m.userGistsTable.SetCursor(idx)
+			}
+		}
+	case 3:
+		if row < len(m.userKeys) {
+			m.userDetailCursor = row
+		}
+	case 4:
+		start, end := m.userEventsWindow()
+		if start+row < end {
+			m.userDetailCursor = start + row
 		}
-
-		return fetchCompleteMsg{owner: owner, name: name, commits: commits}
 	}
+	return m, nil
 }
 
-// startUserQuery returns a tea.Cmd that fetches user repos and gists
-func (m *TUIModel) startUserQuery(login string, _, _ int) tea.Cmd {
-	return func() tea.Msg {
-		if m.token == "" {
-			return userQueryCompleteMsg{login: login, err: fmt.Errorf("no GitHub token")}
+// tabAt returns the index of the rendered tab under column x, or -1
+// Tabs are laid out from column start, separated by single spaces.
+func tabAt(x, start int, tabs []string) int {
+	for i, tab := range tabs {
+		w := StringWidth(tab)
+		if x >= start && x < start+w {
+			return i
 		}
+		start += w + 1
+	}
+	return -1
+}
 
-		var client *api.Client
-		if m.dbPath != "" {
-			client = api.NewClientWithLogging(m.token, m.dbPath)
-		} else {
-			client = api.NewClient(m.token)
-		}
+// userDetailTabCount is the number of tabs in the user detail view
+const userDetailTabCount = 5
 
-		userData, err := client.FetchUserReposAndGists(login)
-		if err != nil {
-			return userQueryCompleteMsg{login: login, err: err}
+// selectUserDetailTab switches the user detail view to a tab and resets its cursor
+func (m *TUIModel) selectUserDetailTab(tab int) {
+	m.userDetailTab = tab
+	m.userDetailCursor = 0
+	// Reset table cursors when switching tabs
+	switch m.userDetailTab {
+	case 1:
+		if len(m.userRepos) > 0 {
+			m.userReposTable.SetCursor(0)
 		}
+	case 2:
+		m.userGistsTable.SetCursor(0)
+	}
+}
 
-		return userQueryCompleteMsg{login: login, data: userData}
+// userDetailURL returns the URL of the selected user detail row and what it points to:
+// a profile link, repo, gist file (anchored to the file) or event. Empty when there is none.
+func (m TUIModel) userDetailURL() (string, string) {
+	switch m.userDetailTab {
+	case 0:
+		if m.userDetailCursor < len(m.userProfileRows) {
+			if row := m.userProfileRows[m.userDetailCursor]; row.IsClickable {
+				return "link", row.URL
+			}
+		}
+	case 1:
+		cursor := m.userReposTable.Cursor()
+		if cursor >= 0 && cursor < len(m.userRepos) {
+			return "repository", m.userRepos[cursor].URL
+		}
+	case 2:
+		// Map table cursor to actual file index (skipping dividers)
+		fileIdx := m.getGistFileIndexFromTableCursor(m.userGistsTable.Cursor())
+		if fileIdx >= 0 && fileIdx < len(m.userGistFiles) {
+			file := m.userGistFiles[fileIdx]
+			if file.IsDivider || file.GistURL == "" {
+				return "", ""
+			}
+			fileURL := file.GistURL
+			if file.FileName != "" {
+				anchor := strings.ReplaceAll(file.FileName, ".", "-")
+				anchor = strings.ReplaceAll(anchor, "_", "-")
+				anchor = strings.ToLower(anchor)
+				fileURL = fileURL + "#file-" + anchor
+			}
+			return "gist file", fileURL
+		}
+	case 4:
+		if m.userDetailCursor < len(m.userEvents) {
+			return "event", m.userEvents[m.userDetailCursor].URL
+		}
 	}
+	return "", ""
 }
 
 // handleUserDetailView handles key events in user detail view
@@ -2325,42 +4001,27 @@ func (m TUIModel) handleUserDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.userDetailVisible = false
 		return m, nil
 
+	case "ctrl+z":
+		// Undo the last repo or gist delete
+		m.undoLastDelete()
+		return m, nil
+
 	case "tab", "right", "l":
-		// Cycle forward: Profile(0) -> Repos(1) -> Gists(2) -> Profile(0)
-		m.userDetailTab = (m.userDetailTab + 1) % 3
-		m.userDetailCursor = 0
-		// Reset table cursors when switching tabs
-		switch m.userDetailTab {
-		case 1:
-			if len(m.userRepos) > 0 {
-				m.userReposTable.SetCursor(0)
-			}
-		case 2:
-			m.userGistsTable.SetCursor(0)
-		}
+		// Cycle forward: Profile(0) -> Repos(1) -> Gists(2) -> Keys(3) -> Events(4) -> Profile(0)
+		m.selectUserDetailTab((m.userDetailTab + 1) % userDetailTabCount)
 		return m, nil
 
 	case "left", "h":
-		// Cycle backward: Profile(0) <- Repos(1) <- Gists(2)
-		m.userDetailTab = (m.userDetailTab + 2) % 3
-		m.userDetailCursor = 0
-		// Reset table cursors when switching tabs
-		switch m.userDetailTab {
-		case 1:
-			if len(m.userRepos) > 0 {
-				m.userReposTable.SetCursor(0)
-			}
-		case 2:
-			m.userGistsTable.SetCursor(0)
-		}
+		// Cycle backward: Profile(0) <- Repos(1) <- Gists(2) <- Keys(3) <- Events(4)
+		m.selectUserDetailTab((m.userDetailTab + userDetailTabCount - 1) % userDetailTabCount)
 		return m, nil
 
 	case "up", "k":
-		// Tab 0 = Profile (manual), Tab 1 = Repos (table), Tab 2 = Gists (table)
+		// Tab 0 = Profile (manual), Tab 1 = Repos (table), Tab 2 = Gists (table), Tab 3/4 = Keys/Events (manual)
 		switch m.userDetailTab {
-		case 0:
+		case 0, 3, 4:
 			if m.userDetailCursor > 0 {
-				// Navigate profile rows manually
+				// Navigate profile, key and event rows manually
 				m.userDetailCursor--
 			}
 		case 1:
@@ -2381,6 +4042,14 @@ func (m TUIModel) handleUserDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.userDetailCursor < len(m.userProfileRows)-1 {
 				m.userDetailCursor++
 			}
+		case 3:
+			if m.userDetailCursor < len(m.userKeys)-1 {
+				m.userDetailCursor++
+			}
+		case 4:
+			if m.userDetailCursor < len(m.userEvents)-1 {
+				m.userDetailCursor++
+			}
 		case 1:
 			// Let table handle navigation
 			m.userReposTable, cmd = m.userReposTable.Update(msg)
@@ -2393,52 +4062,26 @@ func (m TUIModel) handleUserDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "enter":
-		// Tab 0: Profile - open selected row's URL
+		// Tab 0: Profile - Docker Hub row redirects to search instead of the browser
 		if m.userDetailTab == 0 && m.userDetailCursor < len(m.userProfileRows) {
 			row := m.userProfileRows[m.userDetailCursor]
-
-			// Check if Docker Hub row - redirect to search instead of browser
 			if row.Label == "Docker Hub:" && row.DisplayValue != "" {
 				m.quitting = true
 				m.launchDockerSearch = true
 				m.launchDockerSearchQuery = row.DisplayValue
 				return m, tea.Quit
 			}
-
-			// Other clickable rows still open in browser
-			if row.IsClickable && row.URL != "" {
-				openURL(row.URL)
-			}
-			return m, nil
 		}
-		// Tab 1: Repos - open selected repo in browser
-		if m.userDetailTab == 1 && len(m.userRepos) > 0 {
-			cursor := m.userReposTable.Cursor()
-			if cursor >= 0 && cursor < len(m.userRepos) {
-				repo := m.userRepos[cursor]
-				if repo.URL != "" {
-					openURL(repo.URL)
-				}
-			}
+		// Everything else opens in the browser (or is copied when there is none)
+		if what, targetURL := m.userDetailURL(); targetURL != "" {
+			m.exportMessage = openOrCopyURL(what, targetURL)
 		}
-		// Tab 2: Gists - open selected gist file in browser (need to map table cursor back to file index)
-		if m.userDetailTab == 2 {
-			cursor := m.userGistsTable.Cursor()
-			// Map table cursor to actual file index (skipping dividers)
-			fileIdx := m.getGistFileIndexFromTableCursor(cursor)
-			if fileIdx >= 0 && fileIdx < len(m.userGistFiles) {
-				file := m.userGistFiles[fileIdx]
-				if !file.IsDivider && file.GistURL != "" {
-					fileURL := file.GistURL
-					if file.FileName != "" {
-						anchor := strings.ReplaceAll(file.FileName, ".", "-")
-						anchor = strings.ReplaceAll(anchor, "_", "-")
-						anchor = strings.ToLower(anchor)
-						fileURL = fileURL + "#file-" + anchor
-					}
-					openURL(fileURL)
-				}
-			}
+		return m, nil
+
+	case "y":
+		// Copy the selected row's URL instead of opening it
+		if what, targetURL := m.userDetailURL(); targetURL != "" {
+			m.exportMessage = copyURL(what, targetURL)
 		}
 		return m, nil
 
@@ -2446,7 +4089,14 @@ func (m TUIModel) handleUserDetailView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Open user's GitHub profile page
 		if m.selectedUserLogin != "" {
 			profileURL := fmt.Sprintf("https://github.com/%s", m.selectedUserLogin)
-			openURL(profileURL)
+			m.exportMessage = openOrCopyURL("profile", profileURL)
+		}
+		return m, nil
+
+	case "P":
+		// Copy user's GitHub profile URL
+		if m.selectedUserLogin != "" {
+			m.exportMessage = copyURL("profile URL", fmt.Sprintf("https://github.com/%s", m.selectedUserLogin))
 		}
 		return m, nil
 
@@ -2739,6 +4389,18 @@ func (m *TUIModel) showUserDetail(login, name, email string) {
 		gists = []models.UserGist{}
 	}
 
+	// Load public keys
+	keys, err := m.database.GetUserPublicKeys(login)
+	if err != nil {
+		keys = []models.UserPublicKey{}
+	}
+
+	// Load public events
+	events, err := m.database.GetUserEvents(login)
+	if err != nil {
+		events = []models.UserEvent{}
+	}
+
 	// Build flattened list of gist files with dividers
 	var gistFiles []gistFileEntry
 	for _, gist := range gists {
@@ -2788,6 +4450,8 @@ func (m *TUIModel) showUserDetail(login, name, email string) {
 	m.userRepos = repos
 	m.userGists = gists
 	m.userGistFiles = gistFiles
+	m.userKeys = keys
+	m.userEvents = events
 	m.userDetailTab = 0
 	m.userDetailCursor = 0
 	m.userDetailVisible = true
@@ -2823,13 +4487,25 @@ func (m *TUIModel) executeDelete() {
 		if m.deleteTargetIndex >= 0 && m.deleteTargetIndex < len(m.stats) {
 			s := m.stats[m.deleteTargetIndex]
 
-			// Remove from database
+			// Remove from database, keeping the rows for undo
+			m.undo = nil
 			if m.database != nil {
-				m.database.DeleteCommitterByEmail(m.repoOwner, m.repoName, s.Email)
+				deletion, err := m.database.DeleteCommitterUndoable(m.repoOwner, m.repoName, s.Email)
+				if err != nil {
+					m.exportMessage = fmt.Sprintf("Delete failed: %v", err)
+					return
+				}
+				m.undo = &undoState{deletion: deletion, kind: "committer", repo: models.RepoInfo{Owner: m.repoOwner, Name: m.repoName}}
 			}
 
 			// Remove from in-memory stats
 			m.stats = append(m.stats[:m.deleteTargetIndex], m.stats[m.deleteTargetIndex+1:]...)
+			for i, u := range m.unfilteredStats {
+				if u.Email == s.Email {
+					m.unfilteredStats = append(m.unfilteredStats[:i], m.unfilteredStats[i+1:]...)
+					break
+				}
+			}
 
 			// Clean up related data
 			delete(m.tags, s.Email)
@@ -2837,19 +4513,22 @@ func (m *TUIModel) executeDelete() {
 
 			// Update table
 			m.updateRows()
-			m.exportMessage = fmt.Sprintf("Deleted committer: %s", s.Email)
+			m.exportMessage = fmt.Sprintf("Deleted committer: %s%s", s.Email, m.undoHint())
 		}
 
 	case "tracked_repo":
 		if m.deleteTargetIndex >= 0 && m.deleteTargetIndex < len(m.repos) {
 			repo := m.repos[m.deleteTargetIndex]
 
-			// Remove from database
+			// Remove all commits and the tracked repo from the database, keeping the rows for undo
+			m.undo = nil
 			if m.database != nil {
-				// Delete all commits for this repository
-				m.database.DeleteRepositoryData(repo.Owner, repo.Name)
-				// Remove from tracked repos
-				m.database.RemoveTrackedRepo(repo.Owner, repo.Name)
+				deletion, err := m.database.DeleteTrackedRepoUndoable(repo.Owner, repo.Name)
+				if err != nil {
+					m.exportMessage = fmt.Sprintf("Delete failed: %v", err)
+					return
+				}
+				m.undo = &undoState{deletion: deletion, kind: "tracked_repo", repo: repo, repoIndex: m.deleteTargetIndex}
 			}
 
 			// Remove from in-memory repos list
@@ -2871,16 +4550,22 @@ func (m *TUIModel) executeDelete() {
 				m.switchToRepo(m.deleteTargetIndex)
 			}
 
-			m.exportMessage = fmt.Sprintf("Deleted repository: %s/%s", repo.Owner, repo.Name)
+			m.exportMessage = fmt.Sprintf("Deleted repository: %s/%s%s", repo.Owner, repo.Name, m.undoHint())
 		}
 
 	case "repo":
 		if m.deleteTargetIndex >= 0 && m.deleteTargetIndex < len(m.userRepos) {
 			repo := m.userRepos[m.deleteTargetIndex]
 
-			// Remove from database
+			// Remove from database, keeping the row for undo
+			m.undo = nil
 			if m.database != nil {
-				m.database.DeleteUserRepository(m.selectedUserLogin, repo.Name)
+				deletion, err := m.database.DeleteUserRepositoryUndoable(m.selectedUserLogin, repo.Name)
+				if err != nil {
+					m.exportMessage = fmt.Sprintf("Delete failed: %v", err)
+					return
+				}
+				m.undo = &undoState{deletion: deletion, kind: "repo", login: m.selectedUserLogin}
 			}
 
 			// Remove from in-memory list
@@ -2890,16 +4575,22 @@ func (m *TUIModel) executeDelete() {
 			if m.userDetailCursor >= len(m.userRepos) && m.userDetailCursor > 0 {
 				m.userDetailCursor--
 			}
-			m.exportMessage = fmt.Sprintf("Deleted repository: %s", repo.Name)
+			m.exportMessage = fmt.Sprintf("Deleted repository: %s%s", repo.Name, m.undoHint())
 		}
 
 	case "gist":
 		if m.deleteTargetIndex >= 0 && m.deleteTargetIndex < len(m.userGistFiles) {
 			gf := m.userGistFiles[m.deleteTargetIndex]
 
-			// Remove from database
+			// Remove from database, keeping the gist, files and comments for undo
+			m.undo = nil
 			if m.database != nil {
-				m.database.DeleteUserGist(m.selectedUserLogin, gf.GistID)
+				deletion, err := m.database.DeleteUserGistUndoable(m.selectedUserLogin, gf.GistID)
+				if err != nil {
+					m.exportMessage = fmt.Sprintf("Delete failed: %v", err)
+					return
+				}
+				m.undo = &undoState{deletion: deletion, kind: "gist", login: m.selectedUserLogin}
 			}
 
 			// Rebuild gist files list (remove all files from this gist)
@@ -2924,9 +4615,74 @@ func (m *TUIModel) executeDelete() {
 			if m.userDetailCursor >= len(m.userGistFiles) && m.userDetailCursor > 0 {
 				m.userDetailCursor--
 			}
-			m.exportMessage = fmt.Sprintf("Deleted gist: %s", gf.GistID)
+			m.exportMessage = fmt.Sprintf("Deleted gist: %s%s", gf.GistID, m.undoHint())
+		}
+	}
+}
+
+// undoHint returns the message suffix telling the user the last delete can be undone
+func (m TUIModel) undoHint() string {
+	if m.undo == nil {
+		return ""
+	}
+	return " (ctrl+z to undo)"
+}
+
+// undoLastDelete restores the rows removed by the last confirmed delete and refreshes the view
+// they belong to, if it's still showing
+func (m *TUIModel) undoLastDelete() {
+	if m.undo == nil || m.database == nil {
+		m.exportMessage = "Nothing to undo"
+		return
+	}
+	u := m.undo
+	m.undo = nil
+	if err := m.database.RestoreDeletion(u.deletion); err != nil {
+		m.exportMessage = fmt.Sprintf("Undo failed: %v", err)
+		return
+	}
+
+	switch u.kind {
+	case "committer":
+		if !m.showCombined && !m.searchActive && m.repoOwner == u.repo.Owner && m.repoName == u.repo.Name {
+			m.reloadCurrentRepo()
 		}
+	case "tracked_repo":
+		idx := min(u.repoIndex, len(m.repos))
+		m.repos = append(m.repos[:idx], append([]models.RepoInfo{u.repo}, m.repos[idx:]...)...)
+		m.showCombined = false
+		m.searchActive = false
+		m.currentRepoIndex = idx
+		m.switchToRepo(idx)
+	case "repo", "gist":
+		if m.userDetailVisible && m.selectedUserLogin == u.login {
+			tab := m.userDetailTab
+			m.showUserDetail(u.login, m.selectedUserName, m.selectedUserEmail)
+			m.selectUserDetailTab(tab)
+		}
+	}
+	m.exportMessage = fmt.Sprintf("Restored %s", u.deletion.Description)
+}
+
+// reloadCurrentRepo reloads the stats, tags and links of the repository being shown
+func (m *TUIModel) reloadCurrentRepo() {
+	if m.currentRepoIndex >= 0 && m.currentRepoIndex < len(m.repos) {
+		m.switchToRepo(m.currentRepoIndex)
+		return
 	}
+	// Single-repo session (no tabs)
+	if stats, total, err := m.database.GetCommitterStats(m.repoOwner, m.repoName); err == nil {
+		m.stats = stats
+		m.totalCommits = total
+	}
+	if links, err := m.database.GetLinks(m.repoOwner, m.repoName); err == nil {
+		m.links = links
+	}
+	if tags, err := m.database.GetTags(m.repoOwner, m.repoName); err == nil {
+		m.tags = tags
+	}
+	m.pendingLinks = nil
+	m.rebuildTable()
 }
 
 // executeEdit applies the edit form values
@@ -2985,6 +4741,7 @@ func (m *TUIModel) executeEdit() {
 
 // updateRows refreshes the table rows with current tag state
 func (m *TUIModel) updateRows() {
+	visible := m.visibleColumns()
 	rows := make([]table.Row, len(m.stats))
 	for i, s := range m.stats {
 		tagMark := "[ ]"
@@ -3003,14 +4760,17 @@ func (m *TUIModel) updateRows() {
 			login = "-"
 		}
 
-		rows[i] = table.Row{
+		rows[i] = visibleCells(table.Row{
 			tagMark,
 			fmt.Sprintf("%d", i+1),
-			s.Name,
+			linkedDisplayName(s, m.links, m.linkLabels),
 			login,
 			s.Email,
 			fmt.Sprintf("%d", s.CommitCount),
 			fmt.Sprintf("%.1f%%", s.Percentage),
+		}, visible)
+		if m.showSigned {
+			rows[i] = append(rows[i], signedCell(s))
 		}
 	}
 	m.table.SetRows(rows)
@@ -3032,6 +4792,23 @@ func (m TUIModel) View() string {
 		return m.renderFormOverlay(m.editForm.View(), "Edit Row")
 	}
 
+	// Show highlight domain import form if visible
+	if m.importDomainsFormVisible && m.importDomainsForm != nil {
+		return m.renderFormOverlay(m.importDomainsForm.View(), "Import Domains")
+	}
+
+	if m.dateRangeFormVisible && m.dateRangeForm != nil {
+		return m.renderFormOverlay(m.dateRangeForm.View(), "Commit Date Range")
+	}
+
+	if m.linkLabelFormVisible && m.linkLabelForm != nil {
+		return m.renderFormOverlay(m.linkLabelForm.View(), "Name Link Group")
+	}
+
+	if m.columnsFormVisible && m.columnsForm != nil {
+		return m.renderFormOverlay(m.columnsForm.View(), "Table Columns")
+	}
+
 	// Show fetch prompt if pending
 	if m.fetchPromptRepo != nil {
 		return m.renderFetchPrompt()
@@ -3108,6 +4885,9 @@ func (m TUIModel) renderRepoView() string {
 	// Stats row INSIDE the border (white text, plain)
 	currentRow := m.table.Cursor() + 1
 	totalRows := len(m.stats)
+	if totalRows == 0 {
+		currentRow = 0 // e.g. a date range with no commits
+	}
 	statsText := fmt.Sprintf("Row %d/%d Total Commits/Committers: %d/%d", currentRow, totalRows, m.totalCommits, len(m.stats))
 	if m.exportMessage != "" {
 		statsText += " | " + m.exportMessage
@@ -3130,10 +4910,23 @@ func (m TUIModel) renderRepoView() string {
 	b.WriteString("\n")
 
 	// Second box: Help text footer (white border, yellow text)
-	helpText := "(T)ag | (U)sers Query | (E)xport Tab | (A)dd/(R)em Repo | ?: help"
+	helpText := "(T)ag | (U)sers Query | (E)xport Tab | (A)dd/(R)em Repo | ?: help | " + m.sortLabel()
+	if m.rowJump.Active {
+		helpText = m.rowJump.Prompt(len(m.table.Rows()))
+	} else if m.filterInputActive {
+		helpText = fmt.Sprintf("/%s_ | %s | enter: keep | esc: clear", m.filterText, m.filterLabel())
+	} else if m.unfilteredStats != nil {
+		helpText += " | " + m.filterLabel()
+	}
+	if m.hasDateRange() && !m.showCombined && !m.searchActive {
+		// Lead with the range so a narrow footer never hides that stats are partial
+		helpText = m.dateRangeLabel() + " | " + helpText
+	}
 	if len(m.pendingLinks) > 0 {
 		helpText = fmt.Sprintf("[SELECTING: %d rows] %s", len(m.pendingLinks), helpText)
 	}
+	// The spinner leads the footer while a fetch or user query runs
+	helpText = m.spinnerView() + helpText
 	b.WriteString(RenderCenteredFooter(helpText, m.layout.InnerWidth))
 
 	// Only show detailed help outside border when help is visible
@@ -3147,19 +4940,32 @@ func (m TUIModel) renderRepoView() string {
 			"  L              Select/deselect row for linking (yellow = pending)",
 			"  Esc            Commit selected rows as a link group",
 			"  u              Unlink current row from its group",
+			"  N              Name the current row's link group",
 			"  U              Query tagged users (fetches GitHub data)",
+			"  Ctrl+R         Retry users that failed in the last query",
+			"  o              Cycle sort (commits/name/login/email, asc/desc)",
+			"  g              Set/clear commit date range (author date)",
+			"  /              Filter rows by name/login/email (esc clears)",
+			"  y / Y          Copy email / whole row (TSV) to clipboard",
+			"  Ctrl+Y         Copy shown rows as a markdown table",
+			"  v              Show/hide Signed column (% signed, signing keys)",
+			"  c              Show/hide Name, Login, Email and % columns",
 		}
 
 		rightCol := []string{
 			"",
 			"  T              Toggle tag [ ]/[x], or clear [!] for re-scan",
+			"  Ctrl+T/Ctrl+X  Tag all shown rows / clear all repo tags",
 			"  A              Add repository (quick add, skips menu)",
 			"  R              Remove current repository (with confirmation)",
+			"  Ctrl+Z         Undo the last delete",
 			"  S              Search (Docker profiles, highlight domains)",
 			"  Ctrl+D         Docker Hub search",
 			"  X              Export project report (all repos summary)",
 			"  M              Open menu (all options)",
+			"  :              Jump to row number",
 			"  ?              Toggle this help",
+			"  Mouse          Wheel scrolls, click selects a row or tab",
 		}
 
 		// Calculate left column width (find max length)
@@ -3173,13 +4979,21 @@ func (m TUIModel) renderRepoView() string {
 
 		// Build two-column help text with leading space for border padding
 		var helpBuilder strings.Builder
-		for i := 0; i < len(leftCol); i++ {
+		rowCount := max(len(leftCol), len(rightCol))
+		for i := 0; i < rowCount; i++ {
+			left, right := "", ""
+			if i < len(leftCol) {
+				left = leftCol[i]
+			}
+			if i < len(rightCol) {
+				right = rightCol[i]
+			}
 			// Add leading space to respect border padding
 			helpBuilder.WriteString(" ")
 			// Pad left column to fixed width
-			paddedLeft := leftCol[i] + strings.Repeat(" ", leftColWidth-len(leftCol[i]))
+			paddedLeft := left + strings.Repeat(" ", leftColWidth-len(left))
 			helpBuilder.WriteString(paddedLeft)
-			helpBuilder.WriteString(rightCol[i])
+			helpBuilder.WriteString(right)
 			helpBuilder.WriteString("\n")
 		}
 
@@ -3211,24 +5025,15 @@ func (m TUIModel) renderRepoView() string {
 	return b.String()
 }
 
-// renderPageIndicator renders the page indicator for multi-repo navigation
-func (m TUIModel) renderPageIndicator() string {
-	if len(m.repos) == 0 {
-		return ""
-	}
-
-	// Use predefined tab styles instead of creating new ones
-	activeStyle := TabActiveStyle
-	inactiveStyle := TabInactiveStyle
-
-	// Fixed width: InnerWidth = ViewportWidth - 2 (content inside border)
-	// Layout: < (1) + space (1) + [tabs] + [padding] + > (1) = InnerWidth
-	// So tabs + padding = InnerWidth - 3
+// visiblePageTabs renders the repo tabs that fit the page indicator
+// Returns the rendered tabs, the label index of each (0 = Combined, then repos, then search),
+// the active label index, and the total number of labels.
+func (m TUIModel) visiblePageTabs() (parts []string, labelIdx []int, activeIdx, labelCount int) {
+	// Tabs + padding = InnerWidth - 3 (see renderPageIndicator)
 	availableForTabs := m.layout.InnerWidth - 3
 
 	// Build all tab labels - Combined is always first
 	var labels []string
-	activeIdx := 0
 
 	// Combined tab is always first
 	labels = append(labels, "Combined")
@@ -3258,12 +5063,12 @@ func (m TUIModel) renderPageIndicator() string {
 	}
 
 	// Render tabs starting from active, expand both directions
-	var parts []string
 	usedWidth := 0
 
 	// Add active tab
-	activeRendered := activeStyle.Render(activeLabel)
+	activeRendered := TabActiveStyle.Render(activeLabel)
 	parts = append(parts, activeRendered)
+	labelIdx = append(labelIdx, activeIdx)
 	// Use StringWidth() to get visible width (excludes ANSI codes)
 	usedWidth = StringWidth(activeRendered)
 
@@ -3272,10 +5077,11 @@ func (m TUIModel) renderPageIndicator() string {
 	for left >= 0 || right < len(labels) {
 		// Try left
 		if left >= 0 {
-			rendered := inactiveStyle.Render(labels[left])
+			rendered := TabInactiveStyle.Render(labels[left])
 			w := StringWidth(rendered) + 1 // +1 for space
 			if usedWidth+w <= availableForTabs {
 				parts = append([]string{rendered}, parts...)
+				labelIdx = append([]int{left}, labelIdx...)
 				usedWidth += w
 				left--
 			} else {
@@ -3284,10 +5090,11 @@ func (m TUIModel) renderPageIndicator() string {
 		}
 		// Try right
 		if right < len(labels) {
-			rendered := inactiveStyle.Render(labels[right])
+			rendered := TabInactiveStyle.Render(labels[right])
 			w := StringWidth(rendered) + 1 // +1 for space
 			if usedWidth+w <= availableForTabs {
 				parts = append(parts, rendered)
+				labelIdx = append(labelIdx, right)
 				usedWidth += w
 				right++
 			} else {
@@ -3296,6 +5103,22 @@ func (m TUIModel) renderPageIndicator() string {
 		}
 	}
 
+	return parts, labelIdx, activeIdx, len(labels)
+}
+
+// renderPageIndicator renders the page indicator for multi-repo navigation
+func (m TUIModel) renderPageIndicator() string {
+	if len(m.repos) == 0 {
+		return ""
+	}
+
+	// Fixed width: InnerWidth = ViewportWidth - 2 (content inside border)
+	// Layout: < (1) + space (1) + [tabs] + [padding] + > (1) = InnerWidth
+	// So tabs + padding = InnerWidth - 3
+	availableForTabs := m.layout.InnerWidth - 3
+
+	parts, _, activeIdx, labelCount := m.visiblePageTabs()
+
 	// Join tabs with spaces
 	tabsStr := strings.Join(parts, " ")
 	tabsWidth := StringWidth(tabsStr) // Use StringWidth() to get visible width
@@ -3336,7 +5159,7 @@ func (m TUIModel) renderPageIndicator() string {
 		arrowWidth += 1 // Space instead of arrow
 	}
 	arrowWidth += 1 // Space after left arrow
-	if activeIdx < len(labels)-1 {
+	if activeIdx < labelCount-1 {
 		arrowWidth += len(stripEscapeCodes(ArrowStyle.Render(">"))) // Right arrow width
 	} else {
 		arrowWidth += 1 // Space instead of arrow
@@ -3352,7 +5175,7 @@ func (m TUIModel) renderPageIndicator() string {
 
 	// Right arrow
 	rightArrow := ArrowStyle.Render(">")
-	if activeIdx < len(labels)-1 {
+	if activeIdx < labelCount-1 {
 		b.WriteString(rightArrow)
 	} else {
 		b.WriteString(" ")
@@ -3422,7 +5245,7 @@ func (m TUIModel) renderAddRepo() string {
 	}
 
 	// Apply white border to footer
-	footerBordered := NewBorderStyleWithColor(colorWhite).
+	footerBordered := NewBorderStyleWithColor(palette.Text).
 		Width(m.layout.InnerWidth).
 		Height(1).
 		Render(footerContent.String())
@@ -3497,7 +5320,7 @@ func (m TUIModel) renderQueryProgress() string {
 	b.WriteString(TitleStyle.Render("Querying Tagged Users"))
 	b.WriteString("\n\n")
 
-	b.WriteString(" " + ProgressStyle.Render(m.queryProgress))
+	b.WriteString(" " + m.spinnerView() + ProgressStyle.Render(m.queryProgress))
 	b.WriteString("\n\n")
 
 	// Render animated progress bar
@@ -3511,6 +5334,8 @@ func (m TUIModel) renderQueryProgress() string {
 	if m.queryFailed > 0 {
 		b.WriteString(fmt.Sprintf(" (Failed: %d)", m.queryFailed))
 	}
+	b.WriteString("\n\n")
+	b.WriteString(" " + HintStyle.Render("esc: cancel (fetched users are kept)"))
 	b.WriteString("\n")
 
 	// Calculate available height for border (Bug #17 fix - was missing height)
@@ -3528,39 +5353,56 @@ func (m TUIModel) renderQueryProgress() string {
 	return borderStyle.Render(b.String())
 }
 
-// renderUserDetail renders the user detail view with repos and gists
-func (m TUIModel) renderUserDetail() string {
-	var b strings.Builder
-
-	// User label and profile tab (tab 0) - all on same line
-	b.WriteString(NormalStyle.Bold(true).Render("User: "))
-	userInfo := m.selectedUserLogin
-	if m.userDetailTab == 0 {
-		b.WriteString(TabActiveStyle.Render(userInfo))
-	} else {
-		b.WriteString(TabInactiveStyle.Render(userInfo))
-	}
-	b.WriteString(" ")
-
-	// Repos tab (tab 1)
-	if m.userDetailTab == 1 {
-		b.WriteString(TabActiveStyle.Render(fmt.Sprintf("Repos (%d)", len(m.userRepos))))
-	} else {
-		b.WriteString(TabInactiveStyle.Render(fmt.Sprintf("Repos (%d)", len(m.userRepos))))
-	}
-	b.WriteString(" ")
-
-	// Gists tab (tab 2) - count dividers as gists (files are grouped under dividers)
+// userDetailTabLabels returns the user detail tab labels in tab order
+func (m TUIModel) userDetailTabLabels() []string {
+	// Count dividers as gists (files are grouped under dividers)
 	gistCount := 0
 	for _, gf := range m.userGistFiles {
 		if gf.IsDivider {
 			gistCount++
 		}
 	}
-	if m.userDetailTab == 2 {
-		b.WriteString(TabActiveStyle.Render(fmt.Sprintf("Gists (%d)", gistCount)))
-	} else {
-		b.WriteString(TabInactiveStyle.Render(fmt.Sprintf("Gists (%d)", gistCount)))
+	return []string{
+		m.selectedUserLogin,
+		fmt.Sprintf("Repos (%d)", len(m.userRepos)),
+		fmt.Sprintf("Gists (%d)", gistCount),
+		fmt.Sprintf("Keys (%d)", len(m.userKeys)),
+		fmt.Sprintf("Events (%d)", len(m.userEvents)),
+	}
+}
+
+// userEventsWindow returns the visible slice of the events list around the cursor
+// Long histories are windowed so they stay inside the border.
+func (m TUIModel) userEventsWindow() (start, end int) {
+	visible := m.layout.ViewportHeight - 10
+	if visible < 5 {
+		visible = 5
+	}
+	if m.userDetailCursor >= visible {
+		start = m.userDetailCursor - visible + 1
+	}
+	end = start + visible
+	if end > len(m.userEvents) {
+		end = len(m.userEvents)
+	}
+	return start, end
+}
+
+// renderUserDetail renders the user detail view with repos and gists
+func (m TUIModel) renderUserDetail() string {
+	var b strings.Builder
+
+	// User label and tabs - all on same line
+	b.WriteString(NormalStyle.Bold(true).Render("User: "))
+	for i, label := range m.userDetailTabLabels() {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		if i == m.userDetailTab {
+			b.WriteString(TabActiveStyle.Render(label))
+		} else {
+			b.WriteString(TabInactiveStyle.Render(label))
+		}
 	}
 	b.WriteString("\n\n")
 
@@ -3613,6 +5455,49 @@ func (m TUIModel) renderUserDetail() string {
 		} else {
 			b.WriteString(RenderTableWithSelection(m.userGistsTable, m.layout))
 		}
+	case 3:
+		// Keys tab - render as list with selector
+		b.WriteString(NormalStyle.Render(fmt.Sprintf("%-14s %-52s %s", "Type", "Fingerprint", "Created")))
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("─", m.layout.InnerWidth))
+		b.WriteString("\n")
+		if len(m.userKeys) == 0 {
+			b.WriteString(HintStyle.Render("No public SSH/GPG keys found."))
+		}
+		for i, key := range m.userKeys {
+			created := key.CreatedAt
+			if len(created) > 10 {
+				created = created[:10]
+			}
+			line := fmt.Sprintf("%-14s %-52s %s", key.KeyType, key.Fingerprint, created)
+			if i == m.userDetailCursor {
+				b.WriteString(SelectedStyle.Width(m.layout.InnerWidth).Render(line))
+			} else {
+				b.WriteString(NormalStyle.Render(line))
+			}
+			b.WriteString("\n")
+		}
+	case 4:
+		// Events tab - render as list with selector
+		b.WriteString(NormalStyle.Render(fmt.Sprintf("%-28s %-40s %s", "Type", "Repository", "Time")))
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("─", m.layout.InnerWidth))
+		b.WriteString("\n")
+		if len(m.userEvents) == 0 {
+			b.WriteString(HintStyle.Render("No public events found."))
+		}
+		start, end := m.userEventsWindow()
+		for i := start; i < end; i++ {
+			event := m.userEvents[i]
+			created := strings.TrimSuffix(strings.Replace(event.CreatedAt, "T", " ", 1), "Z")
+			line := fmt.Sprintf("%-28s %-40s %s", event.Type, event.RepoName, created)
+			if i == m.userDetailCursor {
+				b.WriteString(SelectedStyle.Width(m.layout.InnerWidth).Render(line))
+			} else {
+				b.WriteString(NormalStyle.Render(line))
+			}
+			b.WriteString("\n")
+		}
 	}
 
 	// Calculate available height for border (Bug #14 fix - was missing height)
@@ -3632,7 +5517,11 @@ func (m TUIModel) renderUserDetail() string {
 	result.WriteString("\n") // Top margin to avoid terminal edge
 	result.WriteString(borderedContent)
 	result.WriteString("\n")
-	result.WriteString(" " + HintStyle.Render("left/right: switch tabs | up/down: navigate | Enter: open in browser | p: profile | Esc: back"))
+	hint := "left/right: switch tabs | up/down: navigate | Enter: open in browser | y: copy URL | p/P: open/copy profile | Esc: back"
+	if m.exportMessage != "" {
+		hint += " | " + m.exportMessage
+	}
+	result.WriteString(" " + HintStyle.Render(hint))
 
 	return result.String()
 }
@@ -3648,7 +5537,7 @@ func (m TUIModel) renderFetchProgress() string {
 	b.WriteString(AccentStyle.Render(fmt.Sprintf("%s/%s", m.fetchingRepo.Owner, m.fetchingRepo.Name)))
 	b.WriteString("\n\n")
 
-	b.WriteString(ProgressStyle.Render(m.fetchProgress))
+	b.WriteString(m.spinnerView() + ProgressStyle.Render(m.fetchProgress))
 	b.WriteString("\n\n")
 
 	// Render animated progress bar
@@ -3748,7 +5637,7 @@ func (m TUIModel) renderMenu() string {
 	}
 
 	// Apply white border to footer content (1 row high)
-	footerBordered := NewBorderStyleWithColor(colorWhite).
+	footerBordered := NewBorderStyleWithColor(palette.Text).
 		Width(m.layout.InnerWidth).
 		Height(1).
 		Render(footerContent.String())
@@ -3842,7 +5731,7 @@ func (m TUIModel) renderDomainConfig() string {
 	}
 
 	// Apply white border to footer
-	footerBordered := NewBorderStyleWithColor(colorWhite).
+	footerBordered := NewBorderStyleWithColor(palette.Text).
 		Width(m.layout.InnerWidth).
 		Height(1).
 		Render(footerContent.String())
@@ -3899,13 +5788,17 @@ func (m TUIModel) renderSearchPicker() string {
 func (m TUIModel) renderLocalSearchInput() string {
 	var b strings.Builder
 
-	b.WriteString(TitleStyle.Render("Local Keyword Search"))
+	title, prompt := "Local Keyword Search", "Search bio, repos, gists for keyword:"
+	if m.localSearchCommitters {
+		title, prompt = "Committer Search", "Search committer names, emails and logins in all repos for:"
+	}
+	b.WriteString(TitleStyle.Render(title))
 	b.WriteString("\n")
 	// White divider after title
 	b.WriteString(strings.Repeat("─", m.layout.InnerWidth))
 	b.WriteString("\n\n")
 
-	b.WriteString(NormalStyle.Render("Search bio, repos, gists for keyword:"))
+	b.WriteString(NormalStyle.Render(prompt))
 	b.WriteString("\n\n")
 
 	// Show input with cursor
@@ -3943,15 +5836,16 @@ func extractDomain(email string) string {
 // Uses regex to find email pattern, which is more robust than fixed-width parsing
 func extractEmailFromRow(line string) string {
 	// Look for email pattern: something@something.something
-	// The email is in column 5 of the table
+	// The email is in column 5 of the table, so the last match wins over anything that looks
+	// like an email in the name column (such as a link group label)
+	email := ""
 	for _, part := range strings.Fields(line) {
 		if strings.Contains(part, "@") && strings.Contains(part, ".") {
 			// Clean up any trailing/leading non-email characters
-			email := strings.TrimSpace(part)
-			return email
+			email = strings.TrimSpace(part)
 		}
 	}
-	return ""
+	return email
 }
 
 // renderTableWithLinks renders the table with colored rows for linked groups
@@ -3980,22 +5874,12 @@ func (m TUIModel) renderTableWithLinks() string {
 
 	// Calculate visible cursor index based on table scrolling
 	// Use m.table.Height() to get actual table height (matches RenderTableWithSelection pattern)
-	height := m.table.Height()
-	totalRows := len(m.stats)
-	start := 0
-	if cursor >= height {
-		start = cursor - height + 1
-	}
-	if start > totalRows-height {
-		start = totalRows - height
-	}
-	if start < 0 {
-		start = 0
-	}
-	visibleCursorIndex := cursor - start
+	visibleCursorIndex := cursor - tableScrollStart(cursor, m.table.Height(), len(m.stats))
 
 	// Track data row index (rows after header)
 	dataRowIndex := 0
+	scrollStart := tableScrollStart(cursor, m.table.Height(), len(m.stats))
+	emailShown := m.visibleColumns()[ColumnEmail]
 
 	for i, line := range lines {
 		// Keep header row (line 0) as-is, but add our divider after it
@@ -4019,8 +5903,15 @@ func (m TUIModel) renderTableWithLinks() string {
 			continue
 		}
 
-		// Extract email from the row content to identify the row
+		// Extract email from the row content to identify the row, or look it up by position
+		// when the Email column is hidden
 		email := extractEmailFromRow(line)
+		if !emailShown {
+			email = ""
+			if idx := scrollStart + dataRowIndex; idx < len(m.stats) {
+				email = m.stats[idx].Email
+			}
+		}
 		if email == "" {
 			// No email found, probably not a data row
 			result = append(result, line)
@@ -4058,6 +5949,16 @@ func (m TUIModel) renderTableWithLinks() string {
 	return strings.Join(result, "\n")
 }
 
+// programOptions returns the Bubble Tea options for the main TUI
+// Mouse reporting is optional because some terminals mishandle it.
+func programOptions(mouse bool) []tea.ProgramOption {
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return opts
+}
+
 // RunInteractiveTable starts the interactive table TUI for a single repo
 func RunInteractiveTable(
 	stats []models.ContributorStats,
@@ -4067,6 +5968,10 @@ func RunInteractiveTable(
 	totalCommits int,
 	cached bool,
 	token string,
+	apiBaseURL string,
+	mouse bool,
+	fetchFiles bool,
+	maxUserPages int,
 ) error {
 	// Load existing links and tags
 	links, err := database.GetLinks(repoOwner, repoName)
@@ -4087,7 +5992,10 @@ func RunInteractiveTable(
 
 	model := NewTUIModel(stats, links, tags, domains, repoOwner, repoName, database, tableType, totalCommits, cached)
 	model.token = token
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	model.apiBaseURL = apiBaseURL
+	model.fetchFiles = fetchFiles
+	model.maxUserPages = maxUserPages
+	p := tea.NewProgram(model, programOptions(mouse)...)
 
 	_, err = p.Run()
 	return err
@@ -4099,7 +6007,11 @@ func RunMultiRepoTUI(
 	database *db.DB,
 	tableType string,
 	token string,
+	apiBaseURL string,
 	dbPath string,
+	mouse bool,
+	fetchFiles bool,
+	maxUserPages int,
 ) (TUIResult, error) {
 	if len(repos) == 0 {
 		return TUIResult{}, fmt.Errorf("no repositories to display")
@@ -4139,13 +6051,21 @@ func RunMultiRepoTUI(
 	model.currentRepoIndex = -1 // No individual repo selected
 	model.showCombined = true
 	model.token = token
+	model.apiBaseURL = apiBaseURL
+	model.fetchFiles = fetchFiles
+	model.maxUserPages = maxUserPages
 	model.dbPath = dbPath
 	model.switchToCombined()      // Load combined stats
 	model.repoViewVisible = false // Start at menu (home), not repo view
 	model.menuVisible = true      // Enable menu input handling at startup
 	model.menuCursor = 2          // First selectable item (View Repositories)
 
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	// Pick up where this project was left: tab, sort, filter and help
+	if prefs, ok := loadUIPrefs(database); ok {
+		model.applyUIPrefs(prefs)
+	}
+
+	p := tea.NewProgram(model, programOptions(mouse)...)
 
 	finalModel, err := p.Run()
 	if err != nil {
@@ -4154,16 +6074,23 @@ func RunMultiRepoTUI(
 
 	// Check what action user wants
 	if m, ok := finalModel.(TUIModel); ok {
+		_ = saveUIPrefs(database, m.currentUIPrefs()) // Best-effort; defaults are fine next time
 		return TUIResult{
 			SwitchProject:            m.switchProject,
 			LaunchDockerSearch:       m.launchDockerSearch,
 			LaunchBrowseDockerRepo:   m.launchBrowseDockerRepo,
 			LaunchCachedLayers:       m.launchCachedLayers,
 			LaunchSearchCachedLayers: m.launchSearchCachedLayers,
+			LaunchSearchBuildSteps:   m.launchSearchBuildSteps,
 			LaunchWayback:            m.launchWayback,
 			LaunchWaybackCache:       m.launchWaybackCache,
 			LaunchSubdomonster:       m.launchSubdomonster,
 			LaunchSubdomonsterCache:  m.launchSubdomonsterCache,
+			LaunchFileHotspots:       m.launchFileHotspots,
+			LaunchGlobalSearch:       m.launchGlobalSearch,
+			LaunchCommitSearch:       m.launchCommitSearch,
+			RepoOwner:                m.repoOwner,
+			RepoName:                 m.repoName,
 			DockerSearchQuery:        m.launchDockerSearchQuery,
 		}, nil
 	}
@@ -4177,10 +6104,16 @@ type TUIResult struct {
 	LaunchBrowseDockerRepo   bool
 	LaunchCachedLayers       bool
 	LaunchSearchCachedLayers bool
+	LaunchSearchBuildSteps   bool
 	LaunchWayback            bool
 	LaunchWaybackCache       bool
 	LaunchSubdomonster       bool
 	LaunchSubdomonsterCache  bool
+	LaunchFileHotspots       bool
+	LaunchGlobalSearch       bool
+	LaunchCommitSearch       bool
+	RepoOwner                string // repository shown when the TUI exited
+	RepoName                 string
 	DockerSearchQuery        string // pre-filled query for Docker Hub search
 }
 
@@ -4217,7 +6150,69 @@ func openURL(targetURL string) error {
 	case "darwin":
 		cmd = exec.Command("open", targetURL)
 	default: // linux, freebsd, etc.
+		// Without a display (e.g. over SSH) xdg-open has no browser to hand the URL to
+		if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			return fmt.Errorf("no display available")
+		}
 		cmd = exec.Command("xdg-open", targetURL)
 	}
 	return cmd.Start()
 }
+
+// openOrCopyURL opens targetURL in the browser, copying it to the clipboard instead when no
+// browser can be launched. what names the URL in the returned status message.
+func openOrCopyURL(what, targetURL string) string {
+	if err := openURL(targetURL); err == nil {
+		return "Opened " + what
+	}
+	if err := copyToClipboard(targetURL); err != nil {
+		return fmt.Sprintf("Browser unavailable and copy failed: %v", err)
+	}
+	return fmt.Sprintf("Browser unavailable — %s copied", what)
+}
+
+// copyURL copies targetURL to the clipboard and returns a status message naming it as what
+func copyURL(what, targetURL string) string {
+	if err := copyToClipboard(targetURL); err != nil {
+		return fmt.Sprintf("Copy failed: %v", err)
+	}
+	return fmt.Sprintf("Copied %s to clipboard", what)
+}
+
+// copyToClipboard writes text to the system clipboard (cross-platform)
+func copyToClipboard(text string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("clip.exe")
+	case "darwin":
+		cmd = exec.Command("pbcopy")
+	default: // linux, freebsd, etc.
+		// The clipboard tools need the display they belong to, which an SSH session lacks
+		x11 := os.Getenv("DISPLAY") != ""
+		wayland := os.Getenv("WAYLAND_DISPLAY") != ""
+		switch {
+		case x11 && commandExists("xclip"):
+			cmd = exec.Command("xclip", "-selection", "clipboard")
+		case x11 && commandExists("xsel"):
+			cmd = exec.Command("xsel", "--clipboard", "--input")
+		case wayland && commandExists("wl-copy"):
+			cmd = exec.Command("wl-copy")
+		default:
+			// No usable local clipboard, e.g. over SSH - ask the terminal to copy via OSC 52
+			termenv.Copy(text)
+			return nil
+		}
+	}
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return nil
+}
+
+// commandExists reports whether name is on PATH
+func commandExists(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,1780 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+	"github.com/thesavant42/gitsome-ng/internal/api"
+	"github.com/thesavant42/gitsome-ng/internal/db"
+	"github.com/thesavant42/gitsome-ng/internal/models"
+)
+
+// addDomain types a domain into the domain input and presses enter
+func addDomain(t *testing.T, m TUIModel, domain string) TUIModel {
+	t.Helper()
+	m.domainInputActive = true
+	m.domainInput = domain
+	updated, _ := m.handleDomainInput(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(TUIModel)
+}
+
+// deleteDomain removes a domain via the domain config delete key
+func deleteDomain(t *testing.T, m TUIModel, domain string) TUIModel {
+	t.Helper()
+	for i, d := range m.domainList {
+		if d == domain {
+			m.domainCursor = i
+			break
+		}
+	}
+	updated, _ := m.handleDomainConfig(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	return updated.(TUIModel)
+}
+
+// TestDomainColorsStable verifies existing domains keep their colors across add/delete/re-add
+func TestDomainColorsStable(t *testing.T) {
+	m := TUIModel{highlightDomains: make(map[string]int)}
+
+	m = addDomain(t, m, "a.com")
+	m = addDomain(t, m, "b.com")
+	m = addDomain(t, m, "c.com")
+
+	want := map[string]int{"a.com": 0, "b.com": 1, "c.com": 2}
+	for domain, idx := range want {
+		if got := m.highlightDomains[domain]; got != idx {
+			t.Fatalf("initial color for %s = %d, want %d", domain, got, idx)
+		}
+	}
+
+	// Delete the middle domain and add a new one - it should fill the gap
+	m = deleteDomain(t, m, "b.com")
+	m = addDomain(t, m, "d.com")
+
+	if got := m.highlightDomains["d.com"]; got != 1 {
+		t.Errorf("new domain color = %d, want 1 (lowest unused)", got)
+	}
+	if m.highlightDomains["a.com"] != 0 || m.highlightDomains["c.com"] != 2 {
+		t.Errorf("existing colors changed: %v", m.highlightDomains)
+	}
+
+	// Re-adding the deleted domain takes the next free index without disturbing others
+	m = addDomain(t, m, "b.com")
+	if got := m.highlightDomains["b.com"]; got != 3 {
+		t.Errorf("re-added domain color = %d, want 3", got)
+	}
+
+	// Adding an existing domain again must not reassign its color
+	m = addDomain(t, m, "a.com")
+	if got := m.highlightDomains["a.com"]; got != 0 {
+		t.Errorf("duplicate add changed a.com color to %d, want 0", got)
+	}
+
+	seen := make(map[int]string)
+	for domain, idx := range m.highlightDomains {
+		if other, ok := seen[idx]; ok {
+			t.Errorf("color %d shared by %s and %s", idx, domain, other)
+		}
+		seen[idx] = domain
+	}
+}
+
+// TestMainTableFillsViewport verifies the table grows with the terminal without pushing the stats row out
+func TestMainTableFillsViewport(t *testing.T) {
+	stats := make([]models.ContributorStats, 200)
+	for i := range stats {
+		stats[i] = models.ContributorStats{Name: fmt.Sprint("user", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+
+	prevHeight := 0
+	for _, height := range []int{24, 40, 60} {
+		m := TUIModel{stats: stats, layout: NewLayout(110, height), repos: []models.RepoInfo{{Owner: "o", Name: "r"}}}
+		m.rebuildTable()
+
+		if m.table.Height() <= prevHeight {
+			t.Errorf("height %d: table height %d did not grow (previous %d)", height, m.table.Height(), prevHeight)
+		}
+		prevHeight = m.table.Height()
+
+		view := m.renderRepoView()
+		if lines := strings.Count(view, "\n") + 1; lines > height {
+			t.Errorf("height %d: view renders %d lines", height, lines)
+		}
+		if !strings.Contains(view, "Row 1/200") {
+			t.Errorf("height %d: stats row clipped from main box", height)
+		}
+	}
+}
+
+// click sends a left-button press at the given screen cell
+func click(m TUIModel, x, y int) TUIModel {
+	updated, _ := m.handleMouse(tea.MouseMsg{X: x, Y: y, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	return updated.(TUIModel)
+}
+
+// TestMouseSelectsRowsAndTabs verifies clicks map to table rows and tabs, and the wheel moves the cursor
+func TestMouseSelectsRowsAndTabs(t *testing.T) {
+	m := TUIModel{
+		layout:           NewLayout(110, 24),
+		repoViewVisible:  true,
+		repos:            []models.RepoInfo{{Owner: "a", Name: "b"}, {Owner: "c", Name: "d"}},
+		showCombined:     true,
+		currentRepoIndex: -1,
+		stats:            []models.ContributorStats{{Name: "one", Email: "one@example.com"}, {Name: "two", Email: "two@example.com"}},
+	}
+	m.rebuildTable()
+
+	// Border, page indicator, blank line, header, divider - second data row is screen row 6
+	m = click(m, 10, 6)
+	if got := m.table.Cursor(); got != 1 {
+		t.Errorf("click on second row: cursor = %d, want 1", got)
+	}
+
+	updated, _ := m.handleMouse(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(TUIModel)
+	if got := m.table.Cursor(); got != 0 {
+		t.Errorf("wheel up: cursor = %d, want 0", got)
+	}
+
+	// "│    Combined     a/b     c/d" - the repo tab "a/b" spans columns 16-22
+	parts, _, _, _ := m.visiblePageTabs()
+	if got := tabAt(18, 3, parts); got != 1 {
+		t.Errorf("tabAt(18) = %d, want 1 (a/b)", got)
+	}
+
+	// User detail: "│User:   octo     Repos (0)  ..." - Repos tab starts at column 16
+	m = TUIModel{layout: NewLayout(110, 24), userDetailVisible: true, selectedUserLogin: "octo",
+		userKeys: []models.UserPublicKey{{KeyType: "ssh-ed25519"}, {KeyType: "gpg"}}}
+	m = click(m, 18, userDetailTabsRow)
+	if m.userDetailTab != 1 {
+		t.Errorf("click on Repos tab: userDetailTab = %d, want 1", m.userDetailTab)
+	}
+	m.userDetailTab = 3
+	m = click(m, 5, userDetailFirstRow+1)
+	if m.userDetailCursor != 1 {
+		t.Errorf("click on second key: cursor = %d, want 1", m.userDetailCursor)
+	}
+}
+
+// TestFetchAllChainsRepos verifies fetch-all walks every tracked repo and summarises the run
+func TestFetchAllChainsRepos(t *testing.T) {
+	m := TUIModel{token: "token", showCombined: true,
+		repos: []models.RepoInfo{{Owner: "a", Name: "one"}, {Owner: "b", Name: "two"}}}
+
+	if cmd := m.startFetchAll(); cmd == nil {
+		t.Fatal("startFetchAll() returned no command")
+	}
+	if m.progressLabel != "Repo 1/2: a/one" {
+		t.Errorf("progressLabel = %q, want %q", m.progressLabel, "Repo 1/2: a/one")
+	}
+
+	updated, cmd := m.handleFetchAllComplete(fetchCompleteMsg{owner: "a", name: "one", commits: make([]models.Commit, 1204)})
+	m = updated.(TUIModel)
+	if cmd == nil || m.fetchingRepo == nil || m.fetchingRepo.Name != "two" {
+		t.Fatalf("expected fetch of second repo to start, fetchingRepo = %+v", m.fetchingRepo)
+	}
+
+	updated, _ = m.handleFetchAllComplete(fetchCompleteMsg{owner: "b", name: "two", err: fmt.Errorf("boom")})
+	m = updated.(TUIModel)
+	if m.fetchingRepo != nil || m.fetchAllQueue != nil {
+		t.Error("fetch-all state not cleared after last repo")
+	}
+	if want := "Fetched 1,204 new commits across 2 repos, 1 failed"; m.exportMessage != want {
+		t.Errorf("exportMessage = %q, want %q", m.exportMessage, want)
+	}
+}
+
+// TestDetailViewScrolls verifies long read-only rows scroll in the detail view and stay within bounds
+func TestDetailViewScrolls(t *testing.T) {
+	m := NewTabbedTableModel(TabbedTableConfig{Pages: []TabbedTablePage{{
+		Name:     "Config",
+		Columns:  ImageConfigColumns(),
+		Rows:     []table.Row{{"Env", "VALUE=" + strings.Repeat("x", 4000)}},
+		ReadOnly: true,
+	}}})
+	m.layout = NewLayout(80, 24)
+
+	press := func(m TabbedTableModel, key tea.KeyMsg) TabbedTableModel {
+		updated, _ := m.handleKeyMsg(key)
+		return updated.(TabbedTableModel)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.viewMode != "detail" {
+		t.Fatalf("enter on read-only row: viewMode = %q, want detail", m.viewMode)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+	if m.detailOffset != 0 {
+		t.Errorf("up at top: offset = %d, want 0", m.detailOffset)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.detailOffset != 1 {
+		t.Errorf("down: offset = %d, want 1", m.detailOffset)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnd})
+	maxOffset := len(m.detailLines()) - m.detailHeight()
+	if maxOffset <= 0 || m.detailOffset != maxOffset {
+		t.Errorf("end: offset = %d, want %d", m.detailOffset, maxOffset)
+	}
+	m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+	if m.detailOffset != maxOffset {
+		t.Errorf("down at bottom: offset = %d, want %d", m.detailOffset, maxOffset)
+	}
+	if view := m.renderDetailView(); strings.Count(view, "\n")+1 > 24 {
+		t.Errorf("detail view renders %d lines", strings.Count(view, "\n")+1)
+	}
+
+	m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.viewMode != "table" || m.detailOffset != 0 {
+		t.Errorf("esc: viewMode = %q offset = %d, want table/0", m.viewMode, m.detailOffset)
+	}
+}
+
+// TestExportLayerTree verifies both export formats list every path with its size
+func TestExportLayerTree(t *testing.T) {
+	t.Chdir(t.TempDir())
+	root := buildFSTree([]api.TarEntry{
+		{Name: "etc/", IsDir: true},
+		{Name: "etc/passwd", Size: 1024},
+		{Name: "usr/bin/app", Size: 2048},
+	})
+
+	path, err := exportLayerTree(root, "library/alpine:latest", "sha256:0123456789abcdef", false)
+	if err != nil {
+		t.Fatalf("text export: %v", err)
+	}
+	text, _ := os.ReadFile(path)
+	if !strings.HasSuffix(path, ".txt") || !strings.Contains(string(text), "    app (2.0 KB)") {
+		t.Errorf("text export %s missing nested file:\n%s", path, text)
+	}
+
+	path, err = exportLayerTree(root, "library/alpine:latest", "sha256:0123456789abcdef", true)
+	if err != nil {
+		t.Fatalf("json export: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	var manifest layerTreeManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("json export %s: %v", path, err)
+	}
+	want := []string{"/etc", "/etc/passwd", "/usr", "/usr/bin", "/usr/bin/app"}
+	if len(manifest.Entries) != len(want) {
+		t.Fatalf("json export has %d entries, want %d", len(manifest.Entries), len(want))
+	}
+	for i, e := range manifest.Entries {
+		if e.Path != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, e.Path, want[i])
+		}
+	}
+	if manifest.Entries[4].Size != 2048 {
+		t.Errorf("app size = %d, want 2048", manifest.Entries[4].Size)
+	}
+}
+
+// TestHighlightMatch verifies every case-insensitive match is styled and the text is otherwise unchanged
+func TestHighlightMatch(t *testing.T) {
+	got := highlightMatch("RUN curl https://x | sh && Curl -V", "curl")
+	if n := strings.Count(got, AccentStyle.Render("curl")) + strings.Count(got, AccentStyle.Render("Curl")); n != 2 {
+		t.Errorf("highlighted %d matches, want 2: %q", n, got)
+	}
+	if got := highlightMatch("a.b", "."); got != "a"+AccentStyle.Render(".")+"b" {
+		t.Errorf("keyword treated as regexp: %q", got)
+	}
+}
+
+// TestLargestFilesView verifies the flat view lists files largest first and enter jumps back into the tree
+func TestLargestFilesView(t *testing.T) {
+	entries := []api.TarEntry{
+		{Name: "etc/", IsDir: true},
+		{Name: "etc/passwd", Size: 10},
+		{Name: "opt/data/app.db", Size: 5000},
+		{Name: "usr/bin/app", Size: 300},
+	}
+	m := newFSBrowserModel(entries, "layer", "library/alpine", "sha256:abc", 0, nil)
+
+	press := func(m fsBrowserModel, key string) fsBrowserModel {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(fsBrowserModel)
+	}
+
+	m = press(m, "l")
+	var paths []string
+	for _, row := range m.rows {
+		paths = append(paths, row.node.getPath())
+	}
+	if want := "/opt/data/app.db,/usr/bin/app,/etc/passwd"; strings.Join(paths, ",") != want {
+		t.Fatalf("largest files = %s, want %s", strings.Join(paths, ","), want)
+	}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(fsBrowserModel)
+	if m.largestView || m.currentNode.getPath() != "/opt/data" {
+		t.Fatalf("enter: largestView=%v dir=%s, want tree at /opt/data", m.largestView, m.currentNode.getPath())
+	}
+	if row := m.rows[m.table.Cursor()]; row.node == nil || row.node.name != "app.db" {
+		t.Errorf("enter: cursor not on app.db")
+	}
+}
+
+// TestWaybackExport verifies the export prompt writes every format and reports the path in the table view
+func TestWaybackExport(t *testing.T) {
+	t.Chdir(t.TempDir())
+	database, err := db.New("wayback.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	updated, _ := NewWaybackModel(nil, database).Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m := updated.(WaybackModel)
+	m.domain = "bfl.ai"
+	m.viewMode = waybackViewTable
+
+	press := func(m WaybackModel, key string) WaybackModel {
+		updated, _ := m.handleTableKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(WaybackModel)
+	}
+
+	m = press(press(m, "e"), "1")
+	if !strings.HasPrefix(m.statusMsg, "Nothing to export") {
+		t.Errorf("empty export: status = %q", m.statusMsg)
+	}
+
+	code := 200
+	mime := "text/html"
+	records := make([]models.CDXRecord, 150) // more than one page
+	for i := range records {
+		records[i] = models.CDXRecord{URL: fmt.Sprintf("https://bfl.ai/p%d", i), Domain: "bfl.ai", Timestamp: "20240101000000", StatusCode: &code, MimeType: &mime}
+	}
+	if _, err := database.InsertWaybackRecords(records); err != nil {
+		t.Fatalf("InsertWaybackRecords: %v", err)
+	}
+
+	for key, format := range waybackExportFormats {
+		m = press(press(m, "e"), key)
+		path := m.statusMsg[strings.LastIndex(m.statusMsg, " ")+1:]
+		data, err := os.ReadFile(path)
+		if err != nil || !strings.HasSuffix(path, "."+format) {
+			t.Fatalf("format %s: status %q, read error %v", format, m.statusMsg, err)
+		}
+		if format == "txt" && strings.Count(string(data), "\n") != len(records) {
+			t.Errorf("URL list has %d lines, want %d", strings.Count(string(data), "\n"), len(records))
+		}
+		if format == "csv" && !strings.Contains(string(data), "https://bfl.ai/p149,20240101000000,200,text/html,0") {
+			t.Errorf("CSV missing record row:\n%.200s", data)
+		}
+	}
+
+	if view := m.View(); !strings.Contains(view, "Exported 150 records") {
+		t.Errorf("table view does not show the export path:\n%s", view)
+	} else if lines := strings.Count(view, "\n") + 1; lines > 30 {
+		t.Errorf("table view with status renders %d lines", lines)
+	}
+}
+
+// TestDateRangeFilter verifies the commit date range narrows repo stats, shows in the footer,
+// renders an empty window cleanly, and clears back to the full history
+func TestDateRangeFilter(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/range.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	var commits []models.CommitRecord
+	for i, day := range []int{1, 2, 2, 5} {
+		commits = append(commits, models.CommitRecord{
+			SHA: fmt.Sprint(i), CommitterName: fmt.Sprint("user", i%2), CommitterEmail: fmt.Sprintf("user%d@example.com", i%2),
+			AuthorDate: time.Date(2024, time.January, day, 23, 30, 0, 0, time.UTC), RepoOwner: "o", RepoName: "r",
+		})
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	m := TUIModel{database: database, layout: NewLayout(110, 24), repos: []models.RepoInfo{{Owner: "o", Name: "r"}}}
+	m.switchToRepo(0)
+
+	// The end date includes its whole day
+	m.applyDateRange("2024-01-02", "2024-01-02")
+	if m.totalCommits != 2 || len(m.stats) != 2 || m.stats[0].Percentage != 50 {
+		t.Errorf("range 01-02: %d commits, stats %+v", m.totalCommits, m.stats)
+	}
+	if view := m.renderRepoView(); !strings.Contains(view, "range: 2024-01-02..2024-01-02") {
+		t.Errorf("footer missing active range:\n%s", view)
+	}
+
+	m.applyDateRange("2024-01-03", "2024-01-04")
+	view := m.renderRepoView()
+	if m.totalCommits != 0 || !strings.Contains(view, "Row 0/0 Total Commits/Committers: 0/0") {
+		t.Errorf("empty range view:\n%s", view)
+	}
+	if lines := strings.Count(view, "\n") + 1; lines > 24 {
+		t.Errorf("empty range view renders %d lines", lines)
+	}
+
+	m.applyDateRange("2024-01-05", "2024-01-01")
+	if m.dateFrom.Day() != 3 || !strings.HasPrefix(m.exportMessage, "Date range not changed") {
+		t.Errorf("reversed range applied: from %v, message %q", m.dateFrom, m.exportMessage)
+	}
+
+	m.applyDateRange("", "")
+	if m.hasDateRange() || m.totalCommits != 4 || strings.Contains(m.renderRepoView(), "range:") {
+		t.Errorf("cleared range: %d commits, range %v..%v", m.totalCommits, m.dateFrom, m.dateTo)
+	}
+}
+
+// TestDockerHubSearchPagination verifies n/p page through results, revisit loaded pages without
+// refetching, stop at the last page, and keep the current page when a fetch fails
+func TestDockerHubSearchPagination(t *testing.T) {
+	updated, _ := NewDockerHubSearchModel(nil, nil).Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m := updated.(DockerHubSearchModel)
+	m.query = "bfl"
+	m.inputMode = false
+
+	page := func(n, count int) DockerHubSearchMsg {
+		results := make([]api.DockerHubSearchResult, count)
+		for i := range results {
+			results[i] = api.DockerHubSearchResult{Name: fmt.Sprintf("bfl/image-%d-%d", n, i)}
+		}
+		return DockerHubSearchMsg{Page: n, Results: &api.DockerHubSearchResponse{Total: 60, Page: n, Results: results}}
+	}
+	press := func(m DockerHubSearchModel, key string) (DockerHubSearchModel, tea.Cmd) {
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(DockerHubSearchModel), cmd
+	}
+	deliver := func(m DockerHubSearchModel, msg DockerHubSearchMsg) DockerHubSearchModel {
+		updated, _ := m.Update(msg)
+		return updated.(DockerHubSearchModel)
+	}
+
+	m = deliver(m, page(1, 25))
+	if view := m.View(); !strings.Contains(view, "Page 1 of 3") || !strings.Contains(view, "n: next") || strings.Contains(view, "p: prev") {
+		t.Errorf("page 1 view:\n%s", view)
+	}
+
+	for _, next := range []DockerHubSearchMsg{page(2, 25), page(3, 10)} {
+		var cmd tea.Cmd
+		m, cmd = press(m, "n")
+		if cmd == nil || !m.searching {
+			t.Fatalf("n on page %d did not fetch the next page", m.page)
+		}
+		m = deliver(m, next)
+	}
+	if m.page != 3 || len(m.table.Rows()) != 10 {
+		t.Errorf("after paging: page %d with %d rows", m.page, len(m.table.Rows()))
+	}
+
+	// The last page disables next
+	view := m.View()
+	if !strings.Contains(view, "Page 3 of 3  |  Total: 60 results (last page)") || strings.Contains(view, "n: next") {
+		t.Errorf("last page view:\n%s", view)
+	}
+	if m, cmd := press(m, "n"); cmd != nil || m.page != 3 {
+		t.Errorf("n on the last page moved to page %d", m.page)
+	}
+
+	// Going back uses the pages already loaded
+	m, cmd := press(m, "p")
+	if cmd != nil || m.page != 2 || m.table.Rows()[0][0] != "bfl/image-2-0" {
+		t.Errorf("p: page %d, fetched %v", m.page, cmd != nil)
+	}
+
+	// A failed fetch leaves the current page in place
+	m = deliver(m, DockerHubSearchMsg{Page: 3, Err: fmt.Errorf("boom")})
+	if m.page != 2 || m.err == nil {
+		t.Errorf("failed fetch: page %d, err %v", m.page, m.err)
+	}
+	if m, _ = press(m, "n"); m.page != 3 || m.err != nil {
+		t.Errorf("n after error: page %d, err %v", m.page, m.err)
+	}
+}
+
+func TestDockerHubSearchCache(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/docker.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	body, _ := json.Marshal(&api.DockerHubSearchResponse{Total: 1, Page: 1, Results: []api.DockerHubSearchResult{{Name: "library/nginx"}}})
+	if err := database.SaveDockerSearch("nginx", 1, string(body)); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, _ := NewDockerHubSearchModel(nil, database).Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m := updated.(DockerHubSearchModel)
+
+	// A fresh cached page is served without hitting Docker Hub
+	m.query = "NGINX"
+	msg, ok := m.doSearch(1, false)().(DockerHubSearchMsg)
+	if !ok || msg.Err != nil || msg.CachedAt.IsZero() || len(msg.Results.Results) != 1 {
+		t.Fatalf("cached search = %+v", msg)
+	}
+	m.inputMode = false
+	updated, _ = m.Update(msg)
+	m = updated.(DockerHubSearchModel)
+	if view := m.View(); !strings.Contains(view, "cached") || !strings.Contains(view, "library/nginx") {
+		t.Errorf("cached page view:\n%s", view)
+	}
+
+	// The cached searches list opens the query offline
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(DockerHubSearchModel)
+	if !m.cachedListMode || len(m.cachedTable.Rows()) != 1 || m.cachedTable.Rows()[0][0] != "nginx" {
+		t.Fatalf("cached list rows = %v", m.cachedTable.Rows())
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(DockerHubSearchModel)
+	if cmd == nil || m.cachedListMode || !m.offline || m.query != "nginx" {
+		t.Errorf("opening cached search: list %v, offline %v, query %q", m.cachedListMode, m.offline, m.query)
+	}
+
+	// "t" cycles the TTL from the default
+	m.showCachedSearches()
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(DockerHubSearchModel)
+	if ttl := database.GetDockerSearchCacheTTL(); ttl != 30*24*time.Hour || m.cacheTTL != ttl {
+		t.Errorf("TTL after t = %v (model %v)", ttl, m.cacheTTL)
+	}
+}
+
+func TestRowJump(t *testing.T) {
+	rows := make([]table.Row, 40)
+	for i := range rows {
+		rows[i] = table.Row{fmt.Sprint("row", i+1)}
+	}
+	keys := func(s string) []tea.KeyMsg {
+		var msgs []tea.KeyMsg
+		for _, r := range s {
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return append(msgs, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	// Main table: typed rows are clamped to the table
+	m := TUIModel{layout: NewLayout(110, 24), repoViewVisible: true,
+		table: InitTable([]table.Column{{Title: "Name", Width: 20}}, rows, NewLayout(110, 24))}
+	for _, tc := range []struct {
+		typed string
+		want  int
+	}{{"17", 16}, {"999", 39}, {"0", 0}} {
+		for _, k := range keys(":" + tc.typed) {
+			updated, _ := m.Update(k)
+			m = updated.(TUIModel)
+		}
+		if m.rowJump.Active || m.table.Cursor() != tc.want {
+			t.Errorf(":%s jumped to %d (active %v), want %d", tc.typed, m.table.Cursor(), m.rowJump.Active, tc.want)
+		}
+	}
+
+	// Esc cancels without moving
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune(":")}, {Type: tea.KeyRunes, Runes: []rune("5")}} {
+		updated, _ := m.Update(k)
+		m = updated.(TUIModel)
+	}
+	if view := m.renderRepoView(); !strings.Contains(view, "Go to row: 5_ (1-40)") {
+		t.Errorf("jump prompt missing from footer:\n%s", view)
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(TUIModel); m.rowJump.Active || m.table.Cursor() != 0 {
+		t.Errorf("esc: active %v, cursor %d", m.rowJump.Active, m.table.Cursor())
+	}
+
+	// Reusable table screens
+	items := make([]string, len(rows))
+	for i := range items {
+		items[i] = fmt.Sprint("tag", i+1)
+	}
+	var sel tea.Model = NewSelectorModel(SelectorConfig{Title: "Tags", Items: items})
+	for _, k := range keys(":12") {
+		sel, _ = sel.Update(k)
+	}
+	if s := sel.(SelectorModel); s.table.Cursor() != 11 || s.Selected() != -1 {
+		t.Errorf("selector: cursor %d, selected %d", s.table.Cursor(), s.Selected())
+	}
+
+	var tabbed tea.Model = NewTabbedTableModel(TabbedTableConfig{Title: "Layers", Pages: []TabbedTablePage{{
+		Name: "Layers", Columns: []ColumnSpec{{Title: "Layer", FlexRatio: 100}}, Rows: rows,
+	}}})
+	for _, k := range keys(":30") {
+		tabbed, _ = tabbed.Update(k)
+	}
+	if tt := tabbed.(TabbedTableModel); tt.tables[0].Cursor() != 29 || tt.quitting {
+		t.Errorf("tabbed table: cursor %d, quitting %v", tt.tables[0].Cursor(), tt.quitting)
+	}
+}
+
+func TestUIPrefsRoundTrip(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/prefs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	var commits []models.CommitRecord
+	for i, name := range []string{"alice", "bob", "carol"} {
+		commits = append(commits, models.CommitRecord{
+			SHA: fmt.Sprint(i), CommitterName: name, CommitterEmail: name + "@example.com",
+			AuthorDate: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), RepoOwner: "o", RepoName: "r2",
+		})
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatalf("InsertCommits: %v", err)
+	}
+
+	repos := []models.RepoInfo{{Owner: "o", Name: "r1"}, {Owner: "o", Name: "r2"}}
+	newModel := func() TUIModel {
+		m := TUIModel{database: database, layout: NewLayout(110, 24), repos: repos, currentRepoIndex: -1, showCombined: true}
+		m.switchToCombined()
+		return m
+	}
+
+	if _, ok := loadUIPrefs(database); ok {
+		t.Fatal("prefs loaded before any were saved")
+	}
+
+	m := newModel()
+	m.currentRepoIndex, m.showCombined = 1, false
+	m.switchToRepo(1)
+	m.sortKeyIndex, m.sortAsc = 1, false // name ↓
+	m.filterText = "r"
+	m.applyFilter()
+	m.helpVisible = true
+	if err := saveUIPrefs(database, m.currentUIPrefs()); err != nil {
+		t.Fatalf("saveUIPrefs: %v", err)
+	}
+
+	prefs, ok := loadUIPrefs(database)
+	if !ok {
+		t.Fatal("saved prefs not loaded")
+	}
+	restored := newModel()
+	restored.applyUIPrefs(prefs)
+	if restored.currentRepoIndex != 1 || restored.showCombined || restored.repoName != "r2" || !restored.helpVisible {
+		t.Errorf("restored tab %d (combined %v, %s), help %v", restored.currentRepoIndex, restored.showCombined, restored.repoName, restored.helpVisible)
+	}
+	if restored.sortLabel() != "sort: name ↓" || restored.filterText != "r" || len(restored.stats) != 1 || len(restored.unfilteredStats) != 3 {
+		t.Errorf("restored %s, filter %q, stats %+v", restored.sortLabel(), restored.filterText, restored.stats)
+	}
+
+	// A repo that is no longer tracked falls back to Combined
+	stale := newModel()
+	stale.repos = repos[:1]
+	stale.applyUIPrefs(uiPrefs{Repo: "o/r2", SortKey: "bogus", SortAsc: true})
+	if !stale.showCombined || stale.currentRepoIndex != -1 || stale.sortLabel() != "sort: commits ↓" {
+		t.Errorf("stale prefs: combined %v, index %d, %s", stale.showCombined, stale.currentRepoIndex, stale.sortLabel())
+	}
+}
+
+func TestCommitterTableColumns(t *testing.T) {
+	stats := []models.ContributorStats{
+		{Name: "Alice Example", Email: "alice.example.person@example.com", GitHubLogin: "alice-example", CommitCount: 3, Percentage: 75},
+		{Name: "Bob", Email: "bob@example.com", CommitCount: 1, Percentage: 25},
+	}
+
+	// A narrow table shrinks Name/Login/Email; hiding Email hands its width to the others
+	all := calculateColumnWidths(stats, 60, false, AllColumns())
+	noEmail := AllColumns()
+	delete(noEmail, ColumnEmail)
+	delete(noEmail, ColumnPercent)
+	hidden := calculateColumnWidths(stats, 60, false, noEmail)
+	if hidden.Email != 0 || hidden.Percent != 0 || hidden.Name <= all.Name {
+		t.Errorf("widths with Email and %% hidden = %+v, all shown = %+v", hidden, all)
+	}
+	columns := BuildTableColumns(hidden, noEmail)
+	var titles []string
+	for _, c := range columns {
+		titles = append(titles, c.Title)
+	}
+	if got := strings.Join(titles, ","); got != "Tag,Rank,Name,GitHub Login,Commits" {
+		t.Errorf("columns = %s", got)
+	}
+
+	database, err := db.New(t.TempDir() + "/cols.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	m := TUIModel{database: database, layout: NewLayout(110, 24), stats: stats, links: map[string]int{}, tags: map[string]bool{}}
+	m.rebuildTable()
+	if n := len(m.table.Rows()[0]); n != 7 {
+		t.Fatalf("default row has %d cells, want 7", n)
+	}
+
+	if cmd := m.showColumnsForm(); cmd == nil || !m.columnsFormVisible {
+		t.Fatal("column picker not shown")
+	}
+	m.columnsFormVisible = false
+	m.applyVisibleColumns([]string{ColumnName, ColumnPercent, "bogus"})
+	row := m.table.Rows()[0]
+	if len(row) != 5 || row[2] != "Alice Example" || row[4] != "75.0%" {
+		t.Errorf("row with Login and Email hidden = %v", row)
+	}
+	if !strings.Contains(m.renderTableWithLinks(), "Alice Example") {
+		t.Error("table without the Email column did not render")
+	}
+
+	// The choice is saved for the project and restored on the next launch
+	prefs, ok := loadUIPrefs(database)
+	if !ok || strings.Join(prefs.HiddenColumns, ",") != "login,email" {
+		t.Fatalf("saved hidden columns = %v (%v)", prefs.HiddenColumns, ok)
+	}
+	restored := TUIModel{database: database, layout: NewLayout(110, 24), currentRepoIndex: -1, showCombined: true}
+	restored.applyUIPrefs(uiPrefs{HiddenColumns: []string{"email", "rank"}})
+	if v := restored.visibleColumns(); v[ColumnEmail] || !v[ColumnName] || len(restored.hiddenColumns) != 1 {
+		t.Errorf("restored visible columns = %v", v)
+	}
+}
+
+func TestSubdomonsterDeleteConfirmation(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/subs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertTargetDomain("example.com")
+	database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "a.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "b.example.com", Source: "crtsh"},
+	})
+
+	m := NewSubdomonsterModel(nil, database)
+	m.viewMode = subdomonsterViewDomains
+	m.cachedDomains, _ = database.GetTargetDomainsWithCounts()
+	press := func(m SubdomonsterModel, key string) (SubdomonsterModel, tea.Cmd) {
+		updated, cmd := m.handleDomainsKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(SubdomonsterModel), cmd
+	}
+
+	// d asks first, showing the scope; anything but y cancels
+	m, _ = press(m, "d")
+	if view := m.renderDomainsView(); !strings.Contains(view, "This will delete 2 subdomains") {
+		t.Errorf("delete prompt missing scope:\n%s", view)
+	}
+	m, _ = press(m, "n")
+	if n, _ := database.GetSubdomainCount("example.com"); m.pendingDeleteDomain != "" || n != 2 {
+		t.Errorf("cancel: pending %q, %d subdomains left", m.pendingDeleteDomain, n)
+	}
+
+	m, _ = press(m, "d")
+	m, cmd := press(m, "y")
+	if n, _ := database.GetSubdomainCount("example.com"); cmd == nil || n != 0 || !strings.Contains(m.statusMsg, "2 subdomains") {
+		t.Errorf("confirm: %d subdomains left, status %q", n, m.statusMsg)
+	}
+}
+
+// TestSubdomainBrowserLiveFilter verifies the search filter reloads while typing, ignores
+// results for superseded text, and that Esc restores the previous filter
+func TestSubdomainBrowserLiveFilter(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/subs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertTargetDomain("example.com")
+	database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "beta.example.com", Source: "virustotal"},
+		{Domain: "example.com", Subdomain: "blog.example.com", Source: "crtsh"},
+	})
+
+	m := NewSubdomonsterModel(nil, database)
+	m.domain = "example.com"
+	load := func(m SubdomonsterModel) SubdomonsterModel {
+		updated, _ := m.Update(m.loadSubdomainsFromDB()())
+		return updated.(SubdomonsterModel)
+	}
+	m = load(m)
+	if m.totalSubdomains != 3 || m.viewMode != subdomonsterViewTable {
+		t.Fatalf("initial load = %d subdomains, view %v", m.totalSubdomains, m.viewMode)
+	}
+
+	updated, _ := m.handleTableKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = updated.(SubdomonsterModel)
+	stale := m.loadSubdomainsFromDB()()
+	updated, cmd := m.handleFilterKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m = updated.(SubdomonsterModel)
+	if cmd == nil || m.filterText != "b" {
+		t.Fatalf("typing did not trigger a reload: filter %q", m.filterText)
+	}
+	m = load(m)
+	if m.totalSubdomains != 2 || m.viewMode != subdomonsterViewFilter {
+		t.Errorf("live filter = %d subdomains, view %v", m.totalSubdomains, m.viewMode)
+	}
+
+	// A page loaded for the old text arriving late is dropped
+	updated, _ = m.Update(stale)
+	if m = updated.(SubdomonsterModel); m.totalSubdomains != 2 {
+		t.Errorf("stale result applied: %d subdomains", m.totalSubdomains)
+	}
+
+	updated, cmd = m.handleFilterKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(SubdomonsterModel)
+	if cmd == nil || m.filterText != "" || m.viewMode != subdomonsterViewTable {
+		t.Errorf("esc: filter %q, view %v", m.filterText, m.viewMode)
+	}
+	if m = load(m); m.totalSubdomains != 3 {
+		t.Errorf("esc did not restore the full list: %d", m.totalSubdomains)
+	}
+
+	if got := subdomainURL(models.Subdomain{Subdomain: "api.example.com"}); got != "https://api.example.com" {
+		t.Errorf("subdomainURL = %q", got)
+	}
+	if got := subdomainURL(models.Subdomain{Subdomain: "api.example.com", FinalURL: "http://api.example.com/login"}); got != "http://api.example.com/login" {
+		t.Errorf("subdomainURL with final URL = %q", got)
+	}
+}
+
+// TestSubdomainBrowserRecent verifies N narrows the table to the latest run's finds and that
+// subdomains discovered after the last export are marked new
+func TestSubdomainBrowserRecent(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/subs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertTargetDomain("example.com")
+	database.InsertSubdomains([]models.Subdomain{
+		{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"},
+		{Domain: "example.com", Subdomain: "dev.example.com", Source: "crtsh"},
+	})
+
+	m := NewSubdomonsterModel(nil, database)
+	m.domain = "example.com"
+	load := func(m SubdomonsterModel) SubdomonsterModel {
+		updated, _ := m.Update(m.loadSubdomainsFromDB()())
+		return updated.(SubdomonsterModel)
+	}
+
+	// Never exported: nothing is marked
+	m = load(m)
+	if strings.HasPrefix(m.table.Rows()[0][0], "+ ") {
+		t.Errorf("marked new without an export: %v", m.table.Rows())
+	}
+	database.SetLastSubdomainExport("example.com", time.Now().Add(-time.Hour))
+	m = load(m)
+	if m.lastExport.IsZero() || !strings.HasPrefix(m.table.Rows()[0][0], "+ ") {
+		t.Errorf("new since export not marked: %v", m.table.Rows())
+	}
+
+	updated, cmd := m.handleTableKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m = updated.(SubdomonsterModel)
+	if cmd == nil || m.filterRecent != subdomonsterRecentLastRun {
+		t.Fatalf("N: filter %v", m.filterRecent)
+	}
+	if m = load(m); m.totalSubdomains != 2 {
+		t.Errorf("last run filter = %d subdomains, want 2", m.totalSubdomains)
+	}
+
+	// A later run that found other subdomains leaves these out
+	database.SetSetting(db.SettingSubdomainLastRunPrefix+"example.com", time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	if m = load(m); m.totalSubdomains != 0 {
+		t.Errorf("last run filter after a newer run = %d subdomains", m.totalSubdomains)
+	}
+
+	updated, _ = m.handleTableKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m = load(updated.(SubdomonsterModel)); m.filterRecent != subdomonsterRecentOff || m.totalSubdomains != 2 {
+		t.Errorf("r did not clear the recent filter: %v, %d", m.filterRecent, m.totalSubdomains)
+	}
+}
+
+// TestSubdomonsterEnumerationTracking verifies completed runs mark the domain enumerated and
+// report its stats, and that re-running an enumerated source needs a second key press
+func TestSubdomonsterEnumerationTracking(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/subs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertTargetDomain("example.com")
+
+	m := NewSubdomonsterModel(nil, database)
+	m.domain = "example.com"
+	m.viewMode = subdomonsterViewTable
+
+	// A crt.sh run that found nothing still counts as done
+	updated, _ := m.Update(subdomonsterFetchCompleteMsg{source: "crtsh"})
+	m = updated.(SubdomonsterModel)
+	if target, _ := database.GetTargetDomain("example.com"); !target.CrtshEnumerated {
+		t.Error("empty crt.sh run not marked enumerated")
+	}
+
+	updated, _ = m.Update(subdomonsterFetchCompleteMsg{source: "crtsh", subdomains: []models.Subdomain{
+		{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"},
+	}})
+	m = updated.(SubdomonsterModel)
+	if !strings.Contains(m.statusMsg, "example.com now has 1 (crt:1) subdomains") {
+		t.Errorf("completion status = %q", m.statusMsg)
+	}
+
+	press := func(m SubdomonsterModel, key string) (SubdomonsterModel, tea.Cmd) {
+		updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(SubdomonsterModel), cmd
+	}
+	m, cmd := press(m, "c")
+	if cmd != nil || m.viewMode != subdomonsterViewTable || !strings.Contains(m.statusMsg, "Press c again") {
+		t.Fatalf("first c: view %v, status %q", m.viewMode, m.statusMsg)
+	}
+
+	// Any other key drops the pending re-run
+	m, _ = press(m, "j")
+	if m, _ = press(m, "c"); m.viewMode != subdomonsterViewTable {
+		t.Fatal("c after another key started a fetch")
+	}
+
+	m, cmd = press(m, "c")
+	if cmd == nil || m.viewMode != subdomonsterViewFetching || m.fetchSource != "crtsh" {
+		t.Errorf("second c: view %v, source %q", m.viewMode, m.fetchSource)
+	}
+	m.cancelFetch()
+}
+
+func TestLayerDownloadProgress(t *testing.T) {
+	m := newFSBrowserModel([]api.TarEntry{{Name: "etc/passwd", Size: 10}}, "Layer 1", "owner/app", "sha256:abc", 10<<10, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(fsBrowserModel)
+	m.downloading = true
+	m.downloadStart = time.Now()
+
+	updated, cmd := m.Update(downloadProgressMsg{progress: api.DownloadProgress{Written: 5 << 10, Total: 10 << 10}})
+	m = updated.(fsBrowserModel)
+	if cmd == nil {
+		t.Error("progress update did not wait for the next one")
+	}
+	if view := m.View(); !strings.Contains(view, "Downloading layer... 5.0 KB / 10.0 KB (50%)") {
+		t.Errorf("progress missing from status line:\n%s", view)
+	}
+
+	// A second d while downloading doesn't start another download
+	if _, cmd := m.startDownload(); cmd != nil {
+		t.Error("d started a second download")
+	}
+
+	updated, _ = m.Update(downloadMsg{err: fmt.Errorf("boom")})
+	if m = updated.(fsBrowserModel); m.downloading || !strings.Contains(m.View(), "Download failed: boom") {
+		t.Errorf("failed download: downloading %v", m.downloading)
+	}
+}
+
+func TestCommitterSearchPicker(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/search.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertCommits([]models.CommitRecord{
+		{SHA: "1", CommitterName: "Alice", CommitterEmail: "alice@acme.com", RepoOwner: "o", RepoName: "r1"},
+		{SHA: "2", CommitterName: "Alice", CommitterEmail: "alice@acme.com", RepoOwner: "o", RepoName: "r2"},
+		{SHA: "3", CommitterName: "Bob", CommitterEmail: "bob@example.com", RepoOwner: "o", RepoName: "r1"},
+	})
+
+	m := TUIModel{database: database, layout: NewLayout(110, 24), repoViewVisible: true, searchPickerVisible: true}
+	m.searchPickerCursor = len(searchOptions) - 1
+	updated, _ := m.handleSearchPicker(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(TUIModel)
+	if !m.localSearchInputVisible || !m.localSearchCommitters || !strings.Contains(m.renderLocalSearchInput(), "Committer Search") {
+		t.Fatalf("committer search option did not open its input")
+	}
+
+	for _, r := range "ACME" {
+		updated, _ = m.handleLocalSearchInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(TUIModel)
+	}
+	updated, _ = m.handleLocalSearchInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(TUIModel)
+	if !m.searchActive || m.searchQuery != "Committers: ACME" || len(m.stats) != 1 || m.stats[0].Name != "Alice [o/r1 +1]" || m.totalCommits != 2 {
+		t.Errorf("committer search: query %q, stats %+v", m.searchQuery, m.stats)
+	}
+}
+
+// TestExportTaggedContacts verifies tagged users are merged with their profiles and that
+// service accounts and users without a deliverable email are skipped
+func TestExportTaggedContacts(t *testing.T) {
+	t.Chdir(t.TempDir())
+	database, err := db.New("contacts.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	if _, n, err := ExportTaggedContacts(database, "acme", "api", ContactsFormatCSV); err != nil || n != 0 {
+		t.Fatalf("empty export = %d contacts, %v", n, err)
+	}
+
+	commits := []models.CommitRecord{
+		{SHA: "1", CommitterName: "Alice", CommitterEmail: "1+alice@users.noreply.github.com", GitHubCommitterLogin: "alice"},
+		{SHA: "2", CommitterName: "Alice W", CommitterEmail: "alice@acme.io", GitHubCommitterLogin: "alice"},
+		{SHA: "3", CommitterName: "Bob", CommitterEmail: "bob@users.noreply.github.com", GitHubCommitterLogin: "bob"},
+		{SHA: "4", CommitterName: "GitHub", CommitterEmail: "noreply@github.com", GitHubCommitterLogin: "web-flow"},
+		{SHA: "5", CommitterName: "Carol", CommitterEmail: "carol@other.org", GitHubCommitterLogin: "carol"},
+	}
+	for i := range commits {
+		commits[i].RepoOwner, commits[i].RepoName = "acme", "api"
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range commits {
+		if err := database.SaveTag("acme", "api", c.CommitterEmail); err != nil {
+			t.Fatal(err)
+		}
+	}
+	profile := models.UserProfile{Login: "carol", Name: "Carol, Esq.", Company: "@acme", Location: "Oslo", TwitterUsername: "carol_tw"}
+	if err := database.SaveUserProfile(profile); err != nil {
+		t.Fatal(err)
+	}
+
+	path, n, err := ExportTaggedContacts(database, "acme", "api", ContactsFormatCSV)
+	if err != nil || n != 2 {
+		t.Fatalf("csv export = %d contacts, %v", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "name,email,github_url,company,location,twitter\n" +
+		"Alice,alice@acme.io,https://github.com/alice,,,\n" +
+		"\"Carol, Esq.\",carol@other.org,https://github.com/carol,acme,Oslo,carol_tw\n"
+	if string(data) != want {
+		t.Errorf("csv export:\n%s\nwant:\n%s", data, want)
+	}
+
+	path, n, err = ExportTaggedContacts(database, "acme", "api", ContactsFormatVCard)
+	if err != nil || n != 2 || !strings.HasSuffix(path, ".vcf") {
+		t.Fatalf("vcard export = %q, %d contacts, %v", path, n, err)
+	}
+	data, _ = os.ReadFile(path)
+	for _, line := range []string{"FN:Carol\\, Esq.\r\n", "EMAIL;TYPE=INTERNET:alice@acme.io\r\n", "ORG:acme\r\n", "ADR:;;;Oslo;;;\r\n"} {
+		if !strings.Contains(string(data), line) {
+			t.Errorf("vcard missing %q:\n%s", line, data)
+		}
+	}
+	if strings.Count(string(data), "BEGIN:VCARD") != 2 {
+		t.Errorf("vcard has %d entries, want 2", strings.Count(string(data), "BEGIN:VCARD"))
+	}
+
+	if _, _, err := ExportTaggedContacts(database, "acme", "api", "mbox"); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
+
+// TestExportProjectReportHTML verifies the HTML report links repos and profiles on the
+// configured GitHub Enterprise server and is renamed into place without leaving temp files
+func TestExportProjectReportHTML(t *testing.T) {
+	t.Chdir(t.TempDir())
+	database, err := db.New("report.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	if err := database.AddTrackedRepo("acme", "api"); err != nil {
+		t.Fatal(err)
+	}
+	commits := []models.CommitRecord{{SHA: "1", CommitterName: "Alice", CommitterEmail: "alice@acme.io", GitHubCommitterLogin: "alice", RepoOwner: "acme", RepoName: "api"}}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct{ apiBase, web string }{
+		{"", "https://github.com"},
+		{"https://ghe.example.com/api/v3/", "https://ghe.example.com"},
+	} {
+		path, err := ExportProjectReportHTML(database, "report.db", tc.apiBase)
+		if err != nil {
+			t.Fatalf("ExportProjectReportHTML(%q): %v", tc.apiBase, err)
+		}
+		data, _ := os.ReadFile(path)
+		for _, link := range []string{`href="` + tc.web + `/acme/api"`, `href="` + tc.web + `/alice"`} {
+			if !strings.Contains(string(data), link) {
+				t.Errorf("report for %q missing %s", tc.apiBase, link)
+			}
+		}
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0644 {
+			t.Errorf("report file mode = %v, %v; want 0644", info, err)
+		}
+		if leftovers, _ := filepath.Glob("*.tmp"); len(leftovers) > 0 {
+			t.Errorf("temp files left behind: %v", leftovers)
+		}
+		os.Remove(path)
+	}
+}
+
+// TestExportLinkGroups verifies per-repo link group exports and the cross-repo merge of groups
+// that share an email
+func TestExportLinkGroups(t *testing.T) {
+	t.Chdir(t.TempDir())
+	database, err := db.New("links.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	if _, n, err := ExportLinkGroups(database, "acme", "api", LinkGroupsFormatJSON); err != nil || n != 0 {
+		t.Fatalf("empty export = %d groups, %v", n, err)
+	}
+
+	commits := []models.CommitRecord{
+		{SHA: "1", CommitterName: "Alice", CommitterEmail: "alice@acme.io", GitHubCommitterLogin: "alice", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "2", CommitterName: "Alice", CommitterEmail: "alice@acme.io", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "3", CommitterName: "al", CommitterEmail: "al@home.net", RepoOwner: "acme", RepoName: "api"},
+		{SHA: "4", CommitterName: "Alice H", CommitterEmail: "al@home.net", RepoOwner: "acme", RepoName: "web"},
+		{SHA: "5", CommitterName: "A. H.", CommitterEmail: "ah@old.org", RepoOwner: "acme", RepoName: "web"},
+		{SHA: "6", CommitterName: "Bob", CommitterEmail: "bob@acme.io", RepoOwner: "acme", RepoName: "web"},
+		{SHA: "7", CommitterName: "Bobby", CommitterEmail: "bob@home.net", RepoOwner: "acme", RepoName: "web"},
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	links := []struct {
+		repo  string
+		group int
+		email string
+	}{
+		{"api", 1, "alice@acme.io"}, {"api", 1, "al@home.net"},
+		{"web", 1, "al@home.net"}, {"web", 1, "ah@old.org"},
+		{"web", 2, "bob@acme.io"}, {"web", 2, "bob@home.net"},
+	}
+	for _, l := range links {
+		if err := database.SaveLink("acme", l.repo, l.group, l.email); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path, n, err := ExportLinkGroups(database, "acme", "api", LinkGroupsFormatJSON)
+	if err != nil || n != 1 || !strings.HasPrefix(path, "acme-api-link-groups-") {
+		t.Fatalf("repo export = %q, %d groups, %v", path, n, err)
+	}
+	var doc linkGroupsExportJSON
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Groups) != 1 || len(doc.Groups[0].Members) != 2 {
+		t.Fatalf("repo export groups = %+v", doc.Groups)
+	}
+	if m := doc.Groups[0].Members[1]; m.Email != "alice@acme.io" || m.Login != "alice" || m.Commits != 2 {
+		t.Errorf("member = %+v", m)
+	}
+
+	path, n, err = ExportLinkGroups(database, "", "", LinkGroupsFormatMarkdown)
+	if err != nil || n != 2 || !strings.HasPrefix(path, "all-repos-link-groups-") || !strings.HasSuffix(path, ".md") {
+		t.Fatalf("combined export = %q, %d groups, %v", path, n, err)
+	}
+	data, _ = os.ReadFile(path)
+	for _, want := range []string{"## Group 1 (acme/api, acme/web)", "| A. H. | - | ah@old.org | acme/web | 1 |", "[alice](https://github.com/alice)", "## Group 2 (acme/web)"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("markdown missing %q:\n%s", want, data)
+		}
+	}
+
+	if _, _, err := ExportLinkGroups(database, "acme", "api", "xml"); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
+
+// TestLinkGroupLabels verifies N names the selected row's link group, the label shows next to
+// linked rows (the numeric id when unlabeled) and lands in the link group export
+func TestLinkGroupLabels(t *testing.T) {
+	t.Chdir(t.TempDir())
+	database, err := db.New("labels.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	commits := []models.CommitRecord{
+		{SHA: "1", CommitterName: "John", CommitterEmail: "john@acme.io", RepoOwner: "o", RepoName: "r"},
+		{SHA: "2", CommitterName: "John", CommitterEmail: "john@acme.io", RepoOwner: "o", RepoName: "r"},
+		{SHA: "3", CommitterName: "jd", CommitterEmail: "jd@home.net", RepoOwner: "o", RepoName: "r"},
+		{SHA: "4", CommitterName: "Other", CommitterEmail: "other@acme.io", RepoOwner: "o", RepoName: "r"},
+	}
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"john@acme.io", "jd@home.net"} {
+		if err := database.SaveLink("o", "r", 1, email); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := TUIModel{database: database, layout: NewLayout(110, 24), repos: []models.RepoInfo{{Owner: "o", Name: "r"}}, repoViewVisible: true}
+	m.switchToRepo(0)
+	row := make(map[string]int) // email -> row index
+	for i, s := range m.stats {
+		row[s.Email] = i
+	}
+	if name := m.table.Rows()[row["john@acme.io"]][2]; name != "John [#1]" {
+		t.Errorf("unlabeled linked row name = %q", name)
+	}
+	if name := m.table.Rows()[row["other@acme.io"]][2]; name != "Other" {
+		t.Errorf("unlinked row name = %q", name)
+	}
+
+	m.table.SetCursor(row["other@acme.io"])
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m = updated.(TUIModel)
+	if m.linkLabelFormVisible || !strings.HasPrefix(m.exportMessage, "Row is not linked") {
+		t.Fatalf("N on unlinked row: form %v, message %q", m.linkLabelFormVisible, m.exportMessage)
+	}
+
+	m.table.SetCursor(row["jd@home.net"])
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m = updated.(TUIModel)
+	if !m.linkLabelFormVisible || m.linkLabelGroup != 1 {
+		t.Fatalf("N on linked row: form %v, group %d", m.linkLabelFormVisible, m.linkLabelGroup)
+	}
+	m.linkLabelFormVisible = false
+	m.applyLinkLabel(m.linkLabelGroup, " John Doe / @jdoe ")
+	if name := m.table.Rows()[row["jd@home.net"]][2]; name != "jd [John Doe / @jdoe]" {
+		t.Errorf("labeled row name = %q", name)
+	}
+	if label, err := database.GetGroupLabel("o", "r", 1); err != nil || label != "John Doe / @jdoe" {
+		t.Errorf("stored label = %q, %v", label, err)
+	}
+
+	// Labels look like emails sometimes; row coloring must still find the real email
+	if email := extractEmailFromRow("  [ ]  1  John [jdoe@corp.io]  -  john@acme.io  2  50.0%"); email != "john@acme.io" {
+		t.Errorf("extractEmailFromRow = %q", email)
+	}
+
+	path, _, err := ExportLinkGroups(database, "o", "r", LinkGroupsFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "## Group 1: John Doe / @jdoe (o/r)") {
+		t.Errorf("markdown export missing label:\n%s", data)
+	}
+
+	// Reloading picks the label up from the database
+	m.switchToRepo(0)
+	if name := m.table.Rows()[row["john@acme.io"]][2]; name != "John [John Doe / @jdoe]" {
+		t.Errorf("reloaded row name = %q", name)
+	}
+}
+
+// TestUndoLastDelete verifies ctrl+z restores a deleted committer or tracked repo, and that
+// another change in between clears the undo
+func TestUndoLastDelete(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/undo.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+
+	var commits []models.CommitRecord
+	for i, email := range []string{"a@x.io", "a@x.io", "b@x.io"} {
+		commits = append(commits, models.CommitRecord{SHA: fmt.Sprint(i), CommitterName: email[:1], CommitterEmail: email, GitHubCommitterLogin: email[:1], RepoOwner: "o", RepoName: "r"})
+	}
+	commits = append(commits, models.CommitRecord{SHA: "x", CommitterName: "z", CommitterEmail: "z@x.io", RepoOwner: "o", RepoName: "other"})
+	if err := database.InsertCommits(commits); err != nil {
+		t.Fatal(err)
+	}
+	database.AddTrackedRepo("o", "r")
+	database.AddTrackedRepo("o", "other")
+
+	m := TUIModel{database: database, layout: NewLayout(110, 24), repoViewVisible: true,
+		repos: []models.RepoInfo{{Owner: "o", Name: "r"}, {Owner: "o", Name: "other"}}}
+	m.switchToRepo(0)
+	ctrlZ := func() {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+		m = updated.(TUIModel)
+	}
+
+	m.deleteTargetType, m.deleteTargetIndex = "committer", 0
+	m.executeDelete()
+	if len(m.stats) != 1 || !strings.HasSuffix(m.exportMessage, "(ctrl+z to undo)") {
+		t.Fatalf("after delete: %d rows, message %q", len(m.stats), m.exportMessage)
+	}
+	ctrlZ()
+	if len(m.stats) != 2 || m.totalCommits != 3 || m.exportMessage != "Restored committer a@x.io" {
+		t.Fatalf("after undo: %d rows, %d commits, message %q", len(m.stats), m.totalCommits, m.exportMessage)
+	}
+	ctrlZ()
+	if m.exportMessage != "Nothing to undo" {
+		t.Errorf("second undo: %q", m.exportMessage)
+	}
+
+	// Tagging after a delete clears the undo
+	m.deleteTargetType, m.deleteTargetIndex = "committer", 1
+	m.executeDelete()
+	m.table.SetCursor(0)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(TUIModel)
+	ctrlZ()
+	if m.exportMessage != "Nothing to undo" || len(m.stats) != 1 {
+		t.Errorf("undo after tagging: %q, %d rows", m.exportMessage, len(m.stats))
+	}
+
+	// A deleted tracked repo comes back in its tab position
+	m.deleteTargetType, m.deleteTargetIndex = "tracked_repo", 0
+	m.executeDelete()
+	if len(m.repos) != 1 || m.repoName != "other" {
+		t.Fatalf("after repo delete: repos %v, showing %s", m.repos, m.repoName)
+	}
+	ctrlZ()
+	if len(m.repos) != 2 || m.repos[0].Name != "r" || m.repoName != "r" || m.totalCommits != 2 {
+		t.Errorf("after repo undo: repos %v, showing %s with %d commits", m.repos, m.repoName, m.totalCommits)
+	}
+}
+
+// TestFileHotspots verifies hotspot rows and that the menu entry needs a repository tab
+func TestFileHotspots(t *testing.T) {
+	rows := fileHotspotRows([]models.FileStats{{Path: "main.go", Commits: 3, Editors: []models.FileEditor{
+		{Name: "Alice", Email: "alice@x.io", Login: "alice", Commits: 2},
+		{Name: "Bob", Email: "bob@x.io", Commits: 1},
+		{Email: "carol@x.io", Commits: 1},
+	}}})
+	want := table.Row{"main.go", "3", "alice (2), Bob (1), carol@x.io (1)"}
+	if len(rows) != 1 || strings.Join(rows[0], "|") != strings.Join(want, "|") {
+		t.Errorf("fileHotspotRows() = %v, want %v", rows, want)
+	}
+
+	m := TUIModel{menuVisible: true, showCombined: true, repoOwner: "o", repoName: "r"}
+	if cmd := m.openFileHotspots(); cmd != nil || m.launchFileHotspots {
+		t.Error("openFileHotspots() launched from the combined view")
+	}
+
+	m = TUIModel{menuVisible: true, repoOwner: "o", repoName: "r"}
+	updated, cmd := m.handleMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	m = updated.(TUIModel)
+	if cmd == nil || !m.launchFileHotspots || !m.quitting {
+		t.Errorf("h key: launchFileHotspots = %v, quitting = %v", m.launchFileHotspots, m.quitting)
+	}
+}
+
+// TestSignedColumnToggle verifies the Signed column is off by default and v toggles it
+func TestSignedColumnToggle(t *testing.T) {
+	stats := []models.ContributorStats{
+		{Name: "Alice", Email: "alice@x.io", CommitCount: 4, SignatureKnown: 3, SignedCommits: 2, VerifiedCommits: 1, SigningKeys: []string{"AAAA", "BBBB"}},
+		{Name: "Bob", Email: "bob@x.io", CommitCount: 1},
+		{Name: "Carol", Email: "carol@x.io", CommitCount: 1, SignatureKnown: 1, Source: models.ContributorSourceCoAuthor},
+	}
+	if got := []string{signedCell(stats[0]), signedCell(stats[1]), signedCell(stats[2])}; strings.Join(got, "|") != "67%* AAAA +1|-|-" {
+		t.Errorf("signedCell() = %q", got)
+	}
+
+	m := NewTUIModel(stats, map[string]int{}, map[string]bool{}, map[string]int{}, "o", "r", nil, "Committers", 6, false)
+	m.repoViewVisible = true
+	if cols := m.table.Columns(); len(cols) != 7 {
+		t.Fatalf("default columns = %d, want 7", len(cols))
+	}
+
+	press := func() {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+		m = updated.(TUIModel)
+	}
+	press()
+	cols := m.table.Columns()
+	if len(cols) != 8 || cols[7].Title != "Signed" || m.table.Rows()[0][7] != "67%* AAAA +1" {
+		t.Fatalf("after v: %d columns, first row %v", len(cols), m.table.Rows()[0])
+	}
+	if !m.currentUIPrefs().ShowSigned {
+		t.Error("ShowSigned not saved in UI prefs")
+	}
+	press()
+	if len(m.table.Columns()) != 7 || len(m.table.Rows()[0]) != 7 {
+		t.Error("second v did not hide the Signed column")
+	}
+}
+
+// TestOpenURLFallsBackToCopy verifies URLs are copied when no browser can be launched and that
+// y copies the selected user detail row's URL
+func TestOpenURLFallsBackToCopy(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("display detection is Linux-only")
+	}
+	// An X session without a browser launcher, and a fake xclip that records what it was given
+	dir := t.TempDir()
+	clip := filepath.Join(dir, "clip.txt")
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+	script := "#!/bin/sh\n" + cat + " > " + clip + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	copied := func() string {
+		t.Helper()
+		data, err := os.ReadFile(clip)
+		if err != nil {
+			t.Fatalf("clipboard not written: %v", err)
+		}
+		os.Remove(clip)
+		return string(data)
+	}
+
+	m := TUIModel{userDetailVisible: true, userDetailTab: 4,
+		userEvents: []models.UserEvent{{Type: "PushEvent", URL: "https://github.com/octo/repo"}}}
+	updated, _ := m.handleUserDetailView(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(TUIModel)
+	if m.exportMessage != "Browser unavailable — event copied" || copied() != "https://github.com/octo/repo" {
+		t.Errorf("enter without a browser: message %q", m.exportMessage)
+	}
+
+	updated, _ = m.handleUserDetailView(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = updated.(TUIModel)
+	if m.exportMessage != "Copied event to clipboard" || copied() != "https://github.com/octo/repo" {
+		t.Errorf("y: message %q", m.exportMessage)
+	}
+
+	if got := waybackArchiveURL(models.CDXRecord{Timestamp: "20240101000000", URL: "http://example.com/a"}); got != "https://web.archive.org/web/20240101000000/http://example.com/a" {
+		t.Errorf("waybackArchiveURL() = %q", got)
+	}
+}
+
+// TestCopyWithoutDisplay verifies a session without a display (e.g. over SSH) copies through the
+// terminal via OSC 52 even when xclip is installed
+func TestCopyWithoutDisplay(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("display detection is Linux-only")
+	}
+	dir := t.TempDir()
+	clip := filepath.Join(dir, "clip.txt")
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte("#!/bin/sh\necho ran > "+clip+"\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	var terminal bytes.Buffer
+	defer termenv.SetDefaultOutput(termenv.DefaultOutput())
+	termenv.SetDefaultOutput(termenv.NewOutput(&terminal))
+
+	if msg := openOrCopyURL("event", "https://github.com/octo/repo"); msg != "Browser unavailable — event copied" {
+		t.Errorf("openOrCopyURL() = %q", msg)
+	}
+	if want := "]52;c;" + base64.StdEncoding.EncodeToString([]byte("https://github.com/octo/repo")); !strings.Contains(terminal.String(), want) {
+		t.Errorf("terminal output %q, want an OSC 52 copy", terminal.String())
+	}
+	if _, err := os.Stat(clip); err == nil {
+		t.Error("xclip ran without a display")
+	}
+}
+
+// TestGlobalSearch verifies hits are grouped by category, that Enter on a subdomain quits to
+// drill into its domain, and that G launches the search from the menu
+func TestGlobalSearch(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/global.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertCommits([]models.CommitRecord{
+		{SHA: "a1", CommitterName: "Staging Bot", CommitterEmail: "bot@acme.com", RepoOwner: "acme", RepoName: "api"},
+	})
+	database.InsertSubdomains([]models.Subdomain{
+		{Domain: "acme.com", Subdomain: "staging.acme.com", Source: "crtsh"},
+	})
+	database.InsertWaybackRecords([]models.CDXRecord{
+		{URL: "https://acme.com/staging", Domain: "acme.com", Timestamp: "20240101000000"},
+	})
+
+	m := newGlobalSearchModel(database)
+	m.textInput.SetValue("staging")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(globalSearchModel)
+	var got []string
+	for _, hit := range m.hits {
+		got = append(got, hit.category)
+	}
+	if m.inputMode || strings.Join(got, ",") != "Committers,Subdomains,Wayback URLs" {
+		t.Fatalf("hits = %v, input mode %v", got, m.inputMode)
+	}
+	if m.hits[0].kind != globalSearchEmail || m.hits[0].target != "bot@acme.com" {
+		t.Errorf("committer without a login = %+v, want its email copied", m.hits[0])
+	}
+	if view := m.View(); !strings.Contains(view, "Subdomains (1)") || !strings.Contains(view, "staging.acme.com") {
+		t.Errorf("view is missing the subdomain section:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(globalSearchModel)
+	if cmd == nil || m.drill == nil || m.drill.kind != globalSearchSubdomain || m.drill.target != "acme.com" || m.drill.filter != "staging.acme.com" {
+		t.Errorf("enter on subdomain: drill = %+v", m.drill)
+	}
+
+	tm := TUIModel{menuVisible: true}
+	updated, cmd = tm.handleMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	if tm = updated.(TUIModel); cmd == nil || !tm.launchGlobalSearch || menuOptions[tm.menuCursor] != "  [G]lobal Search (committers, subdomains, build steps, Wayback URLs)" {
+		t.Errorf("G key: launchGlobalSearch = %v, cursor on %q", tm.launchGlobalSearch, menuOptions[tm.menuCursor])
+	}
+}
+
+// TestCommitMessageSearch verifies the view offers to index messages, then lists matching
+// commits and is reached from the menu only on a repository tab
+func TestCommitMessageSearch(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/messages.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertCommits([]models.CommitRecord{
+		{SHA: "0123456789abcdef", Message: "Drop leaked AWS key", AuthorName: "Alice", CommitterEmail: "alice@acme.com", RepoOwner: "acme", RepoName: "api"},
+	})
+
+	m := newCommitSearchModel(database, "acme", "api")
+	m.textInput.SetValue("aws")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(commitSearchModel); !m.inputMode || !strings.Contains(m.statusMsg, "Ctrl+E") {
+		t.Fatalf("search while off: input mode %v, status %q", m.inputMode, m.statusMsg)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(commitSearchModel)
+	if !m.enabled || len(m.results) != 1 || m.inputMode {
+		t.Fatalf("search after indexing: enabled %v, results %+v", m.enabled, m.results)
+	}
+	if view := m.View(); !strings.Contains(view, "0123456") || !strings.Contains(view, "Alice") {
+		t.Errorf("view is missing the commit:\n%s", view)
+	}
+	if got := m.commitURL(m.results[0]); got != "https://github.com/acme/api/commit/0123456789abcdef" {
+		t.Errorf("commitURL = %q", got)
+	}
+
+	tm := TUIModel{menuVisible: true, showCombined: true}
+	updated, cmd := tm.handleMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if tm = updated.(TUIModel); cmd != nil || tm.launchCommitSearch {
+		t.Error("m key launched commit search from the combined view")
+	}
+	tm = TUIModel{menuVisible: true}
+	updated, cmd = tm.handleMenu(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if tm = updated.(TUIModel); cmd == nil || !tm.launchCommitSearch || menuOptions[tm.menuCursor] != "  Search Commit [m]essages" {
+		t.Errorf("m key: launchCommitSearch = %v, cursor on %q", tm.launchCommitSearch, menuOptions[tm.menuCursor])
+	}
+}
+
+func TestSubdomainBrowserSnapshotDiff(t *testing.T) {
+	database, err := db.New(t.TempDir() + "/subs.db")
+	if err != nil {
+		t.Fatalf("db.New: %v", err)
+	}
+	defer database.Close()
+	database.InsertTargetDomain("example.com")
+	database.InsertSubdomains([]models.Subdomain{{Domain: "example.com", Subdomain: "api.example.com", Source: "crtsh"}})
+
+	m := NewSubdomonsterModel(nil, database)
+	m.domain = "example.com"
+	m.viewMode = subdomonsterViewTable
+	key := func(m SubdomonsterModel, k string) SubdomonsterModel {
+		updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return updated.(SubdomonsterModel)
+	}
+
+	if m = key(m, "D"); m.viewMode != subdomonsterViewTable || !strings.Contains(m.statusMsg, "No snapshots") {
+		t.Fatalf("D without snapshots: view %v, status %q", m.viewMode, m.statusMsg)
+	}
+	if m = key(m, "S"); !strings.Contains(m.statusMsg, "Saved snapshot") {
+		t.Fatalf("S: status %q", m.statusMsg)
+	}
+	database.InsertSubdomains([]models.Subdomain{{Domain: "example.com", Subdomain: "dev.example.com", Source: "crtsh"}})
+
+	m = key(m, "D")
+	if m.viewMode != subdomonsterViewDiff || m.snapshotDiff == nil || len(m.snapshotDiff.Added) != 1 {
+		t.Fatalf("D: view %v, diff %+v", m.viewMode, m.snapshotDiff)
+	}
+	if view := m.renderDiffView(); !strings.Contains(view, "+ dev.example.com") || !strings.Contains(view, "1 added, 0 removed") {
+		t.Errorf("diff view missing the added subdomain:\n%s", view)
+	}
+
+	// Deleting the only snapshot returns to the table
+	if m = key(m, "x"); m.viewMode != subdomonsterViewTable || len(m.snapshots) != 0 {
+		t.Errorf("x: view %v, snapshots %d", m.viewMode, len(m.snapshots))
+	}
+	if snapshots, _ := database.GetSubdomainSnapshots("example.com"); len(snapshots) != 0 {
+		t.Errorf("snapshot not deleted: %+v", snapshots)
+	}
+}
+
+func TestThemes(t *testing.T) {
+	t.Cleanup(func() { SetTheme("default") })
+
+	// The default palette renders exactly the original escape codes
+	if err := SetTheme(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderSelected("x"); got != "\033[38;5;15m\033[48;5;88m\033[1mx\033[0m" {
+		t.Errorf("default RenderSelected = %q", got)
+	}
+	if got := RenderLinkedRow("x", 2, 1); got != "\033[38;5;226mx\033[0m" {
+		t.Errorf("default RenderLinkedRow = %q", got)
+	}
+	if ColorText != "15" || ColorTextDim != "241" {
+		t.Errorf("default Charm colors = %s/%s", ColorText, ColorTextDim)
+	}
+
+	if err := SetTheme("Mono"); err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderNormal("x"); got != "\033[39mx\033[0m" {
+		t.Errorf("mono RenderNormal = %q, want the terminal's text color", got)
+	}
+	if ColorText != "" {
+		t.Errorf("mono ColorText = %q", ColorText)
+	}
+
+	// Every palette keeps link groups apart
+	for _, name := range ThemeNames() {
+		if err := SetTheme(name); err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		for group := 1; group <= len(palette.LinkGroups); group++ {
+			seen[RenderLinkedRow("x", group, 1)] = true
+		}
+		if len(seen) != len(palette.LinkGroups) {
+			t.Errorf("%s link group colors repeat: %v", name, palette.LinkGroups)
+		}
+	}
+	if got := RenderLinkedRow("x", 1, 1); got != "\033[38;5;37mx\033[0m" {
+		t.Errorf("solarized RenderLinkedRow = %q", got)
+	}
+
+	if err := SetTheme("neon"); err == nil || !strings.Contains(err.Error(), "default, mono, solarized") {
+		t.Errorf("unknown theme error = %v", err)
+	}
+}
+
+func TestLightBackground(t *testing.T) {
+	t.Cleanup(func() {
+		SetTheme("default")
+		SetBackground(BackgroundDark)
+	})
+
+	if err := SetBackground(BackgroundLight); err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderNormal("x"); got != "\033[38;5;235mx\033[0m" {
+		t.Errorf("light RenderNormal = %q", got)
+	}
+	if got := HintStyle.Render("x"); got != "\033[38;5;130mx\033[0m" {
+		t.Errorf("light HintStyle = %q", got)
+	}
+	if ColorText != "235" || ColorTextDim != "243" {
+		t.Errorf("light Charm colors = %s/%s", ColorText, ColorTextDim)
+	}
+
+	// A theme picked afterwards keeps the background; mono already reads on both
+	SetTheme("mono")
+	if got := DimStyle.Render("x"); got != "\033[38;5;244mx\033[0m" {
+		t.Errorf("mono light DimStyle = %q", got)
+	}
+	SetTheme("default")
+	if err := SetBackground("Dark"); err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderNormal("x"); got != "\033[38;5;15mx\033[0m" {
+		t.Errorf("dark RenderNormal = %q", got)
+	}
+
+	if err := SetBackground(""); err != nil {
+		t.Errorf("auto background: %v", err)
+	}
+	if err := SetBackground("sepia"); err == nil {
+		t.Error("unknown background accepted")
+	}
+}
+
+// TestCopyMarkdownTable verifies Ctrl+Y copies the shown rows, in display order and with the
+// visible columns, as a markdown table
+func TestCopyMarkdownTable(t *testing.T) {
+	stats := []models.ContributorStats{
+		{Name: "Alice", Email: "alice@x.io", GitHubLogin: "alice", CommitCount: 3, Percentage: 75},
+		{Name: "Bob | Ops", Email: "bob@x.io", CommitCount: 1, Percentage: 25},
+	}
+	want := "| Rank | Name | GitHub Login | Email | Commits | % |\n" +
+		"|------|------|--------------|-------|---------|---|\n" +
+		"| 1 | Alice | [alice](https://github.com/alice) | alice@x.io | 3 | 75.0% |\n" +
+		"| 2 | Bob \\| Ops | - | bob@x.io | 1 | 25.0% |\n"
+	if got := contributorMarkdownTable(stats, AllColumns()); got != want {
+		t.Errorf("contributorMarkdownTable() =\n%s\nwant\n%s", got, want)
+	}
+
+	if runtime.GOOS != "linux" {
+		t.Skip("fake clipboard is Linux-only")
+	}
+	dir := t.TempDir()
+	clip := filepath.Join(dir, "clip.txt")
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not available")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "xclip"), []byte("#!/bin/sh\n"+cat+" > "+clip+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("DISPLAY", ":0")
+
+	m := NewTUIModel(stats, map[string]int{}, map[string]bool{}, map[string]int{}, "o", "r", nil, "Committers", 4, false)
+	m.repoViewVisible = true
+	m.sortAsc = true // commits ↑
+	m.setColumnHidden(ColumnEmail, true)
+	m.rebuildTable()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	m = updated.(TUIModel)
+	if m.exportMessage != "Copied 2 rows as markdown" {
+		t.Fatalf("exportMessage = %q", m.exportMessage)
+	}
+	data, _ := os.ReadFile(clip)
+	lines := strings.Split(string(data), "\n")
+	if lines[0] != "| Rank | Name | GitHub Login | Commits | % |" || !strings.HasPrefix(lines[2], "| 1 | Bob \\| Ops | - | 1 |") {
+		t.Errorf("copied markdown =\n%s", data)
+	}
+}
+
+// TestActivitySpinner verifies the spinner turns in the footer while a fetch runs and stops once
+// it finishes
+func TestActivitySpinner(t *testing.T) {
+	m := NewTUIModel(nil, map[string]int{}, map[string]bool{}, map[string]int{}, "o", "r", nil, "Committers", 0, false)
+	if m.spinnerView() != "" {
+		t.Fatal("spinner shown while idle")
+	}
+
+	m.fetchingRepo = &models.RepoInfo{Owner: "o", Name: "r"}
+	m.fetchProgress = "Fetching commits..."
+	tick := m.startSpinner()
+	if tick == nil || m.startSpinner() != nil {
+		t.Fatal("startSpinner should start exactly one tick loop")
+	}
+	updated, next := m.Update(tick())
+	m = updated.(TUIModel)
+	if next == nil || m.spinnerView() == "" || !strings.Contains(m.renderFetchProgress(), m.spinner.View()) {
+		t.Fatalf("spinner not turning during fetch (next %v, view %q)", next, m.spinnerView())
+	}
+	if !strings.Contains(m.renderRepoView(), m.spinnerView()+"(T)ag") {
+		t.Error("spinner missing from the main footer during fetch")
+	}
+
+	// The first tick after the fetch ends stops the loop and clears the spinner
+	m.fetchingRepo = nil
+	updated, next = m.Update(next())
+	m = updated.(TUIModel)
+	if next != nil || m.spinning || m.spinnerView() != "" {
+		t.Errorf("spinner still running after the fetch: next %v, spinning %v", next, m.spinning)
+	}
+	if strings.Contains(m.renderRepoView(), m.spinner.View()) {
+		t.Error("spinner left in the main footer after the fetch")
+	}
+}
@@ -34,28 +34,7 @@ func RenderTableWithSelection(t table.Model, layout Layout) string {
 	cursor := t.Cursor()
 
 	// Calculate visible cursor index based on table scrolling
-	// Table height is the number of visible data rows (doesn't include header)
-	height := t.Height()
-	totalRows := len(t.Rows())
-
-	// Calculate scroll offset to match bubbles table internal viewport logic
-	// When totalRows <= height, no scrolling occurs (start = 0)
-	// When totalRows > height and cursor moves past visible area, viewport scrolls
-	start := 0
-	if totalRows > height {
-		// Scrolling is possible
-		if cursor >= height {
-			start = cursor - height + 1
-		}
-		// Clamp start to valid range: cannot scroll past the point where
-		// the last row is at the bottom of the viewport
-		maxStart := totalRows - height
-		if start > maxStart {
-			start = maxStart
-		}
-	}
-	// start is always >= 0 at this point since we only modify it when totalRows > height
-
+	start := tableScrollStart(cursor, t.Height(), len(t.Rows()))
 	visibleCursorIndex := cursor - start
 
 	for i, line := range lines {
@@ -93,6 +72,28 @@ func RenderTableWithSelection(t table.Model, layout Layout) string {
 	return strings.Join(result, "\n")
 }
 
+// tableScrollStart returns the index of the first visible row of a scrolled table.
+// Height is the number of visible data rows (doesn't include header).
+func tableScrollStart(cursor, height, totalRows int) int {
+	// Calculate scroll offset to match bubbles table internal viewport logic
+	// When totalRows <= height, no scrolling occurs (start = 0)
+	// When totalRows > height and cursor moves past visible area, viewport scrolls
+	start := 0
+	if totalRows > height {
+		// Scrolling is possible
+		if cursor >= height {
+			start = cursor - height + 1
+		}
+		// Clamp start to valid range: cannot scroll past the point where
+		// the last row is at the bottom of the viewport
+		maxStart := totalRows - height
+		if start > maxStart {
+			start = maxStart
+		}
+	}
+	return start
+}
+
 // =============================================================================
 // View Header - Title + Divider Pattern
 // =============================================================================
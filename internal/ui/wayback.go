@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -75,11 +78,28 @@ type WaybackModel struct {
 
 	// Rate limiting settings - delay in milliseconds between requests
 	requestDelay    int    // Delay in ms between requests (0-2000)
-	settingsCursor  int    // Cursor position in settings view
-	settingsEditing bool   // True when editing the delay value
-	settingsInput   string // Text input for delay value
+	settingsCursor  int    // Cursor position in settings view (see waybackSetting* constants)
+	settingsEditing bool   // True when editing the selected setting
+	settingsInput   string // Text input for the setting being edited
+
+	// CDX fetch filters (applied server-side to new fetches)
+	cdxFilter        api.CDXFilter
+	statusFilterText string // Status filter as entered, e.g. "200,3xx" or "!404,!301"
+	mimeFilterText   string // MIME filter as entered, e.g. "text/html,application/json"
+	sortByCaptures   bool   // Order the table by capture count (most archived first)
+
+	exportChoosing bool // True while the export format prompt is showing
 }
 
+// Settings view rows
+const (
+	waybackSettingDelay = iota
+	waybackSettingStatus
+	waybackSettingMime
+	waybackSettingCaptures
+	waybackSettingCount
+)
+
 // Default delay values (in milliseconds)
 const (
 	defaultDelay = 1000 // 1 second delay (balanced)
@@ -195,7 +215,7 @@ func NewWaybackModel(logger *log.Logger, database *db.DB) WaybackModel {
 	prog.EmptyColor = "241" // Gray for unfilled portion
 
 	return WaybackModel{
-		client:       api.NewWaybackClient(nil), // Pass nil to silence logger during TUI
+		client:       api.NewWaybackClient(logger),
 		logger:       logger,
 		database:     database,
 		layout:       layout,
@@ -213,7 +233,12 @@ func NewWaybackModel(logger *log.Logger, database *db.DB) WaybackModel {
 
 // Init implements tea.Model
 func (m WaybackModel) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink, m.spinner.Tick, m.loadCachedDomains())
+	cmds := []tea.Cmd{textinput.Blink, m.spinner.Tick, m.loadCachedDomains()}
+	if m.domain != "" {
+		// Opened on a domain, e.g. from global search
+		cmds = append(cmds, m.loadRecordsFromDB())
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model
@@ -277,7 +302,9 @@ func (m WaybackModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.fetchPage = msg.page
 
 		// Update status - keep it minimal since main display shows details
-		if msg.inserted > 0 {
+		if msg.inserted > 0 && m.cdxFilter.CountCaptures {
+			m.statusMsg = fmt.Sprintf("Batch complete: %d URLs added or updated", msg.inserted)
+		} else if msg.inserted > 0 {
 			m.statusMsg = fmt.Sprintf("Batch complete: +%d new records", msg.inserted)
 		} else {
 			m.statusMsg = "Batch complete: no new records (duplicates skipped)"
@@ -453,17 +480,28 @@ func (m WaybackModel) handleDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		// Open live URL
 		if m.detailRecord != nil {
-			openURL(m.detailRecord.URL)
-			m.statusMsg = "Opened live URL"
+			m.statusMsg = openOrCopyURL("live URL", m.detailRecord.URL)
 		}
 		return m, nil
 
 	case "a":
 		// Open archived URL
 		if m.detailRecord != nil {
-			archiveURL := fmt.Sprintf("https://web.archive.org/web/%s/%s", m.detailRecord.Timestamp, m.detailRecord.URL)
-			openURL(archiveURL)
-			m.statusMsg = "Opened archived URL"
+			m.statusMsg = openOrCopyURL("archived URL", waybackArchiveURL(*m.detailRecord))
+		}
+		return m, nil
+
+	case "y":
+		// Copy live URL
+		if m.detailRecord != nil {
+			m.statusMsg = copyURL("live URL", m.detailRecord.URL)
+		}
+		return m, nil
+
+	case "Y":
+		// Copy archived URL
+		if m.detailRecord != nil {
+			m.statusMsg = copyURL("archived URL", waybackArchiveURL(*m.detailRecord))
 		}
 		return m, nil
 	}
@@ -567,6 +605,10 @@ func (m WaybackModel) handleFetchingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m WaybackModel) handleSettingsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.settingsEditing && m.settingsCursor != waybackSettingDelay {
+		return m.handleFilterSettingKeys(msg)
+	}
+
 	if m.settingsEditing {
 		// Editing mode - handle text input
 		switch msg.String() {
@@ -616,6 +658,43 @@ func (m WaybackModel) handleSettingsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.viewMode = waybackViewInput
 		return m, textinput.Blink
 
+	case "tab":
+		m.settingsCursor = (m.settingsCursor + 1) % waybackSettingCount
+		return m, nil
+
+	case "shift+tab":
+		m.settingsCursor = (m.settingsCursor + waybackSettingCount - 1) % waybackSettingCount
+		return m, nil
+	}
+
+	if m.settingsCursor == waybackSettingCaptures {
+		switch msg.String() {
+		case "enter", "e", " ":
+			m.cdxFilter.CountCaptures = !m.cdxFilter.CountCaptures
+			if m.cdxFilter.CountCaptures {
+				m.statusMsg = "Next fetch downloads every capture and counts captures per URL"
+			} else {
+				m.statusMsg = "Next fetch lets the server collapse captures by URL"
+			}
+		}
+		return m, nil
+	}
+
+	if m.settingsCursor != waybackSettingDelay {
+		switch msg.String() {
+		case "enter", "e":
+			m.settingsEditing = true
+			m.settingsInput = m.statusFilterText
+			if m.settingsCursor == waybackSettingMime {
+				m.settingsInput = m.mimeFilterText
+			}
+		case "x", "backspace":
+			m.setFilterSetting("")
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
 	case "enter", "e":
 		// Start editing
 		m.settingsEditing = true
@@ -657,7 +736,62 @@ func (m WaybackModel) handleSettingsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleFilterSettingKeys handles text entry for the status and MIME filter settings
+func (m WaybackModel) handleFilterSettingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.settingsEditing = false
+		return m, nil
+
+	case "enter":
+		m.setFilterSetting(m.settingsInput)
+		return m, nil
+
+	case "backspace":
+		if len(m.settingsInput) > 0 {
+			m.settingsInput = m.settingsInput[:len(m.settingsInput)-1]
+		}
+		return m, nil
+
+	default:
+		if len(msg.String()) == 1 && len(m.settingsInput) < 100 {
+			m.settingsInput += msg.String()
+		}
+		return m, nil
+	}
+}
+
+// setFilterSetting parses and stores the filter under the settings cursor
+// Invalid input keeps the editor open and reports the problem in the status line.
+func (m *WaybackModel) setFilterSetting(text string) {
+	statusText, mimeText := m.statusFilterText, m.mimeFilterText
+	if m.settingsCursor == waybackSettingStatus {
+		statusText = strings.TrimSpace(text)
+	} else {
+		mimeText = strings.TrimSpace(text)
+	}
+
+	filter, err := api.ParseCDXFilter(statusText, mimeText)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	m.cdxFilter = filter
+	m.statusFilterText, m.mimeFilterText = statusText, mimeText
+
+	m.settingsEditing = false
+	if m.cdxFilter.IsEmpty() {
+		m.statusMsg = "CDX filters cleared"
+	} else {
+		m.statusMsg = "CDX filters apply to the next fetch"
+	}
+}
+
 func (m WaybackModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.exportChoosing {
+		return m.handleExportKeys(msg)
+	}
+
 	switch msg.String() {
 	case "esc":
 		// Go back to input
@@ -674,27 +808,32 @@ func (m WaybackModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.table.MoveDown(1)
 		return m, nil
 
-	case "enter":
-		// Open live URL in browser
+	case "enter", "y":
+		// Open live URL in browser (y copies it instead)
 		if len(m.filteredRecords) > 0 {
 			cursor := m.table.Cursor()
 			if cursor >= 0 && cursor < len(m.filteredRecords) {
 				url := m.filteredRecords[cursor].URL
-				openURL(url)
-				m.statusMsg = "Opened live URL"
+				if msg.String() == "y" {
+					m.statusMsg = copyURL("live URL", url)
+				} else {
+					m.statusMsg = openOrCopyURL("live URL", url)
+				}
 			}
 		}
 		return m, nil
 
-	case "a":
-		// Open archived URL in browser
+	case "a", "Y":
+		// Open archived URL in browser (Y copies it instead)
 		if len(m.filteredRecords) > 0 {
 			cursor := m.table.Cursor()
 			if cursor >= 0 && cursor < len(m.filteredRecords) {
-				record := m.filteredRecords[cursor]
-				archiveURL := fmt.Sprintf("https://web.archive.org/web/%s/%s", record.Timestamp, record.URL)
-				openURL(archiveURL)
-				m.statusMsg = "Opened archived URL"
+				archiveURL := waybackArchiveURL(m.filteredRecords[cursor])
+				if msg.String() == "Y" {
+					m.statusMsg = copyURL("archived URL", archiveURL)
+				} else {
+					m.statusMsg = openOrCopyURL("archived URL", archiveURL)
+				}
 			}
 		}
 		return m, nil
@@ -830,17 +969,22 @@ func (m WaybackModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "e":
-		// Export to markdown
-		if len(m.filteredRecords) > 0 {
-			filename := fmt.Sprintf("wayback-%s-%s.md", m.domain, time.Now().Format("20060102-150405"))
-			if err := exportWaybackToMarkdown(m.filteredRecords, m.domain, filename); err != nil {
-				m.statusMsg = fmt.Sprintf("Export error: %v", err)
-			} else {
-				m.statusMsg = fmt.Sprintf("Exported to %s", filename)
-			}
-		}
+		// Export every record matching the current filters (not just this page)
+		m.exportChoosing = true
+		m.statusMsg = "Export as: [1] URL list  [2] CSV  [3] JSON  [4] Markdown  (Esc: cancel)"
 		return m, nil
 
+	case "s":
+		// Toggle ordering by capture count (heavily archived URLs first)
+		m.sortByCaptures = !m.sortByCaptures
+		m.page = 1
+		if m.sortByCaptures {
+			m.statusMsg = "Sorted by captures (count captures in ^S settings before fetching)"
+		} else {
+			m.statusMsg = "Sorted by timestamp"
+		}
+		return m, m.loadRecordsFromDB()
+
 	case "n", "right":
 		// Next page
 		maxPage := (m.totalRecords + m.pageSize - 1) / m.pageSize
@@ -859,6 +1003,52 @@ func (m WaybackModel) handleTableKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// waybackExportFormats maps the export prompt keys to file formats
+var waybackExportFormats = map[string]string{"1": "txt", "2": "csv", "3": "json", "4": "md"}
+
+// handleExportKeys handles the export format prompt in the table view
+func (m WaybackModel) handleExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	format, ok := waybackExportFormats[msg.String()]
+	if !ok {
+		if msg.String() == "esc" || msg.String() == "q" {
+			m.exportChoosing = false
+			m.statusMsg = "Export cancelled"
+		}
+		return m, nil
+	}
+	m.exportChoosing = false
+
+	if m.database == nil {
+		m.statusMsg = "Export error: database not available"
+		return m, nil
+	}
+
+	records, _, err := m.database.GetWaybackRecordsFiltered(models.WaybackFilter{
+		Domain:         m.domain,
+		MimeType:       m.filterMimeType,
+		SearchText:     m.filterText,
+		Tags:           m.filterTag,
+		SortByCaptures: m.sortByCaptures,
+		Limit:          -1, // No limit: export every matching record
+	})
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Export error: %v", err)
+		return m, nil
+	}
+	if len(records) == 0 {
+		m.statusMsg = "Nothing to export: no records match the current filters"
+		return m, nil
+	}
+
+	filename := fmt.Sprintf("wayback-%s-%s.%s", m.domain, time.Now().Format("20060102-150405"), format)
+	if err := exportWaybackRecords(records, m.domain, filename, format); err != nil {
+		m.statusMsg = fmt.Sprintf("Export error: %v", err)
+	} else {
+		m.statusMsg = fmt.Sprintf("Exported %d records to %s", len(records), filename)
+	}
+	return m, nil
+}
+
 func (m WaybackModel) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
@@ -1102,9 +1292,11 @@ func (m WaybackModel) renderTableView() string {
 	totalRows := len(m.filteredRecords)
 	queryInfo += fmt.Sprintf("  |  Page %d/%d  |  Total: %d  |  Row %d/%d", m.page, maxPage, m.totalRecords, currentRow, totalRows)
 
-	// Use PageViewBuilder for consistent rendering
+	// Use PageViewBuilder for consistent rendering. The status (e.g. an
+	// export path) goes above the table so a full table can't clip it.
 	return NewPageView(m.layout).
 		QueryInfo(queryInfo).
+		Status(m.statusMsg).
 		Table(m.table).
 		BuildContent()
 }
@@ -1202,11 +1394,58 @@ func (m WaybackModel) renderSettingsView() string {
 	b.WriteString(DimStyle.Render("   2000 ms = 2 second delay (slowest, safest)"))
 	b.WriteString("\n\n")
 
-	b.WriteString(HintStyle.Render(" ←/→: ±100ms | ↑/↓: ±50ms | e: type value | Esc: back"))
+	// Fetch filters (comma-separated; "!" excludes)
+	b.WriteString(NormalStyle.Render(" Fetch Filters:"))
+	b.WriteString(DimStyle.Render("  (comma-separated, ! to exclude; applies to new fetches)"))
+	b.WriteString("\n")
+	b.WriteString(m.renderFilterSetting(waybackSettingStatus, "Status", m.statusFilterText, "e.g. 200,3xx or !404,!301"))
+	b.WriteString("\n")
+	b.WriteString(m.renderFilterSetting(waybackSettingMime, "MIME  ", m.mimeFilterText, "e.g. text/html,application/json"))
+	b.WriteString("\n")
+
+	// Capture counting (collapse client-side instead of collapse=urlkey)
+	marker := "   "
+	if m.settingsCursor == waybackSettingCaptures {
+		marker = " > "
+	}
+	captures := "off (server collapses by URL, no counts)"
+	if m.cdxFilter.CountCaptures {
+		captures = "on (fetch every capture, keep newest per URL)"
+	}
+	b.WriteString(NormalStyle.Render(marker + "Count captures: "))
+	b.WriteString(AccentStyle.Render(captures))
+	b.WriteString("\n\n")
+
+	switch m.settingsCursor {
+	case waybackSettingDelay:
+		b.WriteString(HintStyle.Render(" ←/→: ±100ms | ↑/↓: ±50ms | e: type value | Tab: filters | Esc: back"))
+	case waybackSettingCaptures:
+		b.WriteString(HintStyle.Render(" Enter: toggle | Tab: next setting | Esc: back"))
+	default:
+		b.WriteString(HintStyle.Render(" e: edit filter | x: clear | Tab: next setting | Esc: back"))
+	}
 
 	return b.String()
 }
 
+// renderFilterSetting renders one filter row of the settings view, marking the selected row
+func (m WaybackModel) renderFilterSetting(setting int, label, value, example string) string {
+	marker := "   "
+	if m.settingsCursor == setting {
+		marker = " > "
+	}
+	line := NormalStyle.Render(fmt.Sprintf("%s%s: ", marker, label))
+	switch {
+	case m.settingsEditing && m.settingsCursor == setting:
+		line += AccentStyle.Render(m.settingsInput + "_")
+	case value != "":
+		line += AccentStyle.Render(value)
+	default:
+		line += DimStyle.Render("(none) " + example)
+	}
+	return line
+}
+
 // getHelpTextPlain returns the help text without styling (for two-box layout)
 func (m WaybackModel) getHelpTextPlain() string {
 	switch m.viewMode {
@@ -1215,18 +1454,27 @@ func (m WaybackModel) getHelpTextPlain() string {
 	case waybackViewFetching:
 		return "Esc: cancel fetch"
 	case waybackViewTable:
-		return "Enter: open | a: archive | v: view | /: filter | t: tag | Esc: back"
+		if m.exportChoosing {
+			return "1: URL list | 2: CSV | 3: JSON | 4: Markdown | Esc: cancel"
+		}
+		return "Enter: open | a: archive | y/Y: copy URL/archive | v: view | /: filter | t: tag | s: sort | e: export | Esc: back"
 	case waybackViewFilter:
 		return "Enter: apply filter | Esc: cancel"
 	case waybackViewDomains:
 		return "Enter: select | up/down: navigate | Esc: back"
 	case waybackViewDetail:
-		return "Enter: open live | a: archive | j/k: scroll | Esc: close"
+		return "Enter: open live | a: archive | y/Y: copy live/archive | j/k: scroll | Esc: close"
 	case waybackViewSettings:
 		if m.settingsEditing {
 			return "Enter: save | Esc: cancel"
 		}
-		return "←/→: ±10 | ↑/↓: ±1 | e: edit value | Esc: back"
+		switch m.settingsCursor {
+		case waybackSettingCaptures:
+			return "Enter: toggle | Tab: next setting | Esc: back"
+		case waybackSettingStatus, waybackSettingMime:
+			return "e: edit filter | x: clear | Tab: next setting | Esc: back"
+		}
+		return "←/→: ±10 | ↑/↓: ±1 | e: edit value | Tab: filters | Esc: back"
 	default:
 		return ""
 	}
@@ -1301,6 +1549,13 @@ func (m WaybackModel) renderDetailView() string {
 	}
 	b.WriteString("\n\n")
 
+	// Capture count (only known after a fetch with capture counting on)
+	if r.Captures > 0 {
+		b.WriteString(DimStyle.Render(" Captures: "))
+		b.WriteString(NormalStyle.Render(fmt.Sprintf("%d", r.Captures)))
+		b.WriteString("\n\n")
+	}
+
 	// Status Code
 	b.WriteString(DimStyle.Render(" Status: "))
 	if r.StatusCode != nil {
@@ -1407,16 +1662,27 @@ func (m WaybackModel) doFetchWithResume(resumeKey string) tea.Cmd {
 		default:
 		}
 
+		// A fresh counting fetch starts the capture counts over so refreshes don't double them
+		if resumeKey == "" && m.cdxFilter.CountCaptures && m.database != nil {
+			if err := m.database.ResetWaybackCaptures(m.domain); err != nil {
+				return waybackBatchMsg{err: err, hasMore: false}
+			}
+		}
+
 		// Fetch ONE batch only
-		resp, err := m.client.FetchCDX(m.domain, resumeKey)
+		resp, err := m.client.FetchCDX(m.domain, resumeKey, m.cdxFilter)
 		if err != nil {
 			return waybackBatchMsg{err: err, hasMore: false}
 		}
 
 		// Insert this batch into DB
+		// Fetch state is saved even for empty batches: with filters applied a page can
+		// match nothing while the resume key still advances
 		inserted := 0
-		if m.database != nil && len(resp.Records) > 0 {
-			inserted, _ = m.database.InsertWaybackRecords(resp.Records)
+		if m.database != nil {
+			if len(resp.Records) > 0 {
+				inserted, _ = m.database.InsertWaybackRecords(resp.Records)
+			}
 			// Save fetch state after this batch
 			_ = m.database.SaveWaybackFetchState(
 				m.domain,
@@ -1476,6 +1742,8 @@ func (m WaybackModel) loadRecordsFromDB() tea.Cmd {
 			Tags:       m.filterTag,
 			Limit:      m.pageSize,
 			Offset:     (m.page - 1) * m.pageSize,
+
+			SortByCaptures: m.sortByCaptures,
 		}
 
 		records, total, err := m.database.GetWaybackRecordsFiltered(filter)
@@ -1520,6 +1788,7 @@ func (m *WaybackModel) updateTable() {
 	tsW := columns[1].Width
 	statusW := columns[2].Width
 	mimeW := columns[3].Width
+	capturesW := columns[4].Width
 
 	truncate := func(s string, w int) string {
 		if len(s) <= w {
@@ -1549,11 +1818,17 @@ func (m *WaybackModel) updateTable() {
 			mime = *r.MimeType
 		}
 
+		captures := "-"
+		if r.Captures > 0 {
+			captures = fmt.Sprintf("%d", r.Captures)
+		}
+
 		rows[i] = table.Row{
 			truncate(r.URL, urlW),
 			truncate(ts, tsW),
 			truncate(status, statusW),
 			truncate(mime, mimeW),
+			truncate(captures, capturesW),
 		}
 	}
 
@@ -1613,6 +1888,77 @@ func exportWaybackToMarkdown(records []models.CDXRecord, domain, filename string
 	return writeStringToFile(filename, b.String())
 }
 
+// waybackExportRecord is the JSON form of an exported CDX record
+type waybackExportRecord struct {
+	URL        string `json:"url"`
+	Timestamp  string `json:"timestamp"`
+	StatusCode *int   `json:"status_code"`
+	MimeType   string `json:"mime_type,omitempty"`
+	Captures   int    `json:"captures,omitempty"`
+	Tags       string `json:"tags,omitempty"`
+	ArchiveURL string `json:"archive_url"`
+}
+
+// exportWaybackRecords writes records to filename in the given format
+// txt is one URL per line for feeding other tools; csv and json include timestamp, status and MIME type.
+func exportWaybackRecords(records []models.CDXRecord, domain, filename, format string) error {
+	switch format {
+	case "txt":
+		var b strings.Builder
+		for _, r := range records {
+			b.WriteString(r.URL)
+			b.WriteString("\n")
+		}
+		return writeStringToFile(filename, b.String())
+
+	case "csv":
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		_ = w.Write([]string{"url", "timestamp", "status_code", "mime_type", "captures"})
+		for _, r := range records {
+			status := ""
+			if r.StatusCode != nil {
+				status = strconv.Itoa(*r.StatusCode)
+			}
+			mime := ""
+			if r.MimeType != nil {
+				mime = *r.MimeType
+			}
+			_ = w.Write([]string{r.URL, r.Timestamp, status, mime, strconv.Itoa(r.Captures)})
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return fmt.Errorf("failed to encode CSV: %w", err)
+		}
+		return writeStringToFile(filename, b.String())
+
+	case "json":
+		out := make([]waybackExportRecord, len(records))
+		for i, r := range records {
+			out[i] = waybackExportRecord{
+				URL:        r.URL,
+				Timestamp:  r.Timestamp,
+				StatusCode: r.StatusCode,
+				Captures:   r.Captures,
+				Tags:       r.Tags,
+				ArchiveURL: fmt.Sprintf("https://web.archive.org/web/%s/%s", r.Timestamp, r.URL),
+			}
+			if r.MimeType != nil {
+				out[i].MimeType = *r.MimeType
+			}
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return writeStringToFile(filename, string(data))
+
+	case "md":
+		return exportWaybackToMarkdown(records, domain, filename)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
 func writeStringToFile(filename, content string) error {
 	return os.WriteFile(filename, []byte(content), 0644)
 }
@@ -1662,3 +2008,18 @@ func RunWaybackCacheBrowser(logger *log.Logger, database *db.DB) error {
 
 	return nil
 }
+
+// RunWaybackCacheBrowserAt opens the cached CDX records of domain with the URL filter set to filter
+func RunWaybackCacheBrowserAt(logger *log.Logger, database *db.DB, domain, filter string) error {
+	model := NewWaybackModel(logger, database)
+	model.viewMode = waybackViewDomains
+	model.domain = domain
+	model.filterText = filter
+	_, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	return err
+}
+
+// waybackArchiveURL returns the Wayback Machine URL of a record's capture
+func waybackArchiveURL(record models.CDXRecord) string {
+	return fmt.Sprintf("https://web.archive.org/web/%s/%s", record.Timestamp, record.URL)
+}